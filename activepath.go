@@ -0,0 +1,56 @@
+package templ
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ActivePathOption changes how IsActivePath compares paths.
+type ActivePathOption func(o *activePathOptions)
+
+type activePathOptions struct {
+	prefix bool
+}
+
+// MatchPrefix makes the paths below the path active too, e.g. /settings/profile
+// is active for /settings.
+func MatchPrefix() ActivePathOption {
+	return func(o *activePathOptions) {
+		o.prefix = true
+	}
+}
+
+// IsActivePath returns true if the path is the path of the URL of the request,
+// e.g. to mark the link to the current page in a navigation menu. Trailing
+// slashes, and the query string of the path, are ignored. The URL of the
+// request is added to the context by Handler, see WithRequestURL. If the
+// context doesn't contain the URL, no path is active.
+func IsActivePath(ctx context.Context, path string, opts ...ActivePathOption) bool {
+	var o activePathOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	u := GetRequestURL(ctx)
+	if u == nil {
+		return false
+	}
+	p, err := url.Parse(path)
+	if err != nil {
+		return false
+	}
+	current, target := trimTrailingSlash(u.Path), trimTrailingSlash(p.Path)
+	if current == target {
+		return true
+	}
+	return o.prefix && strings.HasPrefix(current, strings.TrimSuffix(target, "/")+"/")
+}
+
+// trimTrailingSlash removes the trailing slash of the path, except for the
+// root path.
+func trimTrailingSlash(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
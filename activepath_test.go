@@ -0,0 +1,95 @@
+package templ_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestIsActivePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		path     string
+		opts     []templ.ActivePathOption
+		expected bool
+	}{
+		{
+			name:     "the path of the request is active",
+			current:  "/settings",
+			path:     "/settings",
+			expected: true,
+		},
+		{
+			name:     "trailing slashes and query strings are ignored",
+			current:  "/settings/?tab=profile",
+			path:     "/settings?tab=billing",
+			expected: true,
+		},
+		{
+			name:     "other paths aren't active",
+			current:  "/settings",
+			path:     "/profile",
+			expected: false,
+		},
+		{
+			name:     "paths below the path aren't active by default",
+			current:  "/settings/profile",
+			path:     "/settings",
+			expected: false,
+		},
+		{
+			name:     "paths below the path are active with MatchPrefix",
+			current:  "/settings/profile",
+			path:     "/settings",
+			opts:     []templ.ActivePathOption{templ.MatchPrefix()},
+			expected: true,
+		},
+		{
+			name:     "MatchPrefix matches whole segments",
+			current:  "/settings-old",
+			path:     "/settings",
+			opts:     []templ.ActivePathOption{templ.MatchPrefix()},
+			expected: false,
+		},
+		{
+			name:     "the root path is active",
+			current:  "/",
+			path:     "/",
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := templ.WithRequestURL(context.Background(), u)
+			if actual := templ.IsActivePath(ctx, tt.path, tt.opts...); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+	t.Run("no path is active if the context doesn't contain the request URL", func(t *testing.T) {
+		if templ.IsActivePath(context.Background(), "/") {
+			t.Error("expected false, got true")
+		}
+	})
+}
+
+func TestRequestURLMiddleware(t *testing.T) {
+	var active bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		active = templ.IsActivePath(r.Context(), "/settings")
+	})
+	templ.NewRequestURLMiddleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/settings", nil))
+	if !active {
+		t.Error("expected the path of the request to be active")
+	}
+}
@@ -0,0 +1,118 @@
+// Package alpine provides typed builders for Alpine.js attributes.
+//
+// Attribute values are HTML escaped when they're rendered. Go values that are
+// passed to Alpine expressions are JSON encoded, so that they can't change the
+// meaning of the expression.
+package alpine
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// Expression is a JavaScript expression, e.g. "open = !open".
+//
+// Expressions aren't sanitized, so they must not be built from user input. Use
+// Call, or JSON, to pass Go values to expressions.
+type Expression string
+
+// JSON returns the JSON encoding of the value as an expression. JSON is a valid
+// JavaScript literal, and <, > and & are escaped.
+func JSON(v any) (Expression, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return Expression(data), nil
+}
+
+// Call returns an expression that calls the function with the JSON encoded
+// arguments, e.g. Call("select", id) returns select(123).
+func Call(function Expression, args ...any) (Expression, error) {
+	encoded := make([]string, len(args))
+	for i, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return "", err
+		}
+		encoded[i] = string(data)
+	}
+	return Expression(string(function) + "(" + strings.Join(encoded, ",") + ")"), nil
+}
+
+// Attributes merges the attributes into a single set of attributes, so that they
+// can be spread onto an element.
+func Attributes(attrs ...templ.Attributes) templ.Attributes {
+	return templ.MergeAttributes(attrs...)
+}
+
+// Data declares a component with the JSON encoded value as its data.
+func Data(v any) (templ.Attributes, error) {
+	data, err := JSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return templ.Attributes{"x-data": string(data)}, nil
+}
+
+// DataExpression declares a component with the expression as its data, e.g. "dropdown()".
+func DataExpression(expr Expression) templ.Attributes {
+	return templ.Attributes{"x-data": string(expr)}
+}
+
+// Init runs the expression when the component is initialised.
+func Init(expr Expression) templ.Attributes {
+	return templ.Attributes{"x-init": string(expr)}
+}
+
+// On runs the expression when the event is dispatched. The event can include
+// modifiers, e.g. "click.outside", or "keyup.enter".
+func On(event string, expr Expression) templ.Attributes {
+	return templ.Attributes{"x-on:" + attributeNameSuffix(event): string(expr)}
+}
+
+// Bind sets the attribute to the result of the expression.
+func Bind(attr string, expr Expression) templ.Attributes {
+	return templ.Attributes{"x-bind:" + attributeNameSuffix(attr): string(expr)}
+}
+
+// Show shows the element when the expression is true.
+func Show(expr Expression) templ.Attributes {
+	return templ.Attributes{"x-show": string(expr)}
+}
+
+// Text sets the text content of the element to the result of the expression.
+func Text(expr Expression) templ.Attributes {
+	return templ.Attributes{"x-text": string(expr)}
+}
+
+// Model binds the value of the input element to the data property.
+func Model(property string) templ.Attributes {
+	return templ.Attributes{"x-model": property}
+}
+
+// Ref names the element, so that it can be accessed with $refs.
+func Ref(name string) templ.Attributes {
+	return templ.Attributes{"x-ref": name}
+}
+
+// Cloak hides the element until Alpine has initialised it.
+func Cloak() templ.Attributes {
+	return templ.Attributes{"x-cloak": true}
+}
+
+// attributeNameSuffix removes the characters that can't be used in the name of
+// the attribute, so that the name can't add other attributes to the element.
+func attributeNameSuffix(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '-' || r == '_' || r == '.' || r == ':':
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,38 @@
+package alpine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAttributes(t *testing.T) {
+	data, err := Data(map[string]any{"open": false, "label": "</script>"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	call, err := Call("select", `"); alert(1); ("`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attrs := Attributes(
+		data,
+		On("click.outside", "open = false"),
+		On(`keyup" onclick="alert(1)`, call),
+		Show("open"),
+	)
+	w := new(strings.Builder)
+	if err = templ.RenderAttributes(context.Background(), w, attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ` x-data="{&#34;label&#34;:&#34;\u003c/script\u003e&#34;,&#34;open&#34;:false}"` +
+		` x-on:click.outside="open = false"` +
+		` x-on:keyuponclickalert1="select(&#34;\&#34;); alert(1); (\&#34;&#34;)"` +
+		` x-show="open"`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
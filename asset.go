@@ -0,0 +1,182 @@
+package templ
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+)
+
+// AssetManifest maps the names of static assets, e.g. "app.css", to the names
+// of their fingerprinted files, e.g. "app.3f9ab2c1.css", so that assets can be
+// cached indefinitely by browsers and CDNs.
+type AssetManifest struct {
+	// BasePath is the URL path that the files are served from, e.g. "/static/".
+	BasePath string
+	// Files maps asset names to fingerprinted file names, relative to BasePath.
+	Files map[string]string
+}
+
+// URL returns the URL of the named asset. If the asset isn't in the manifest,
+// the URL of the unfingerprinted file is returned.
+func (m AssetManifest) URL(name string) SafeURL {
+	if file, ok := m.Files[name]; ok {
+		name = file
+	}
+	if m.BasePath == "" {
+		return SafeURL(name)
+	}
+	return SafeURL(strings.TrimSuffix(m.BasePath, "/") + "/" + strings.TrimPrefix(name, "/"))
+}
+
+var assetManifest atomic.Pointer[AssetManifest]
+
+// SetAssetManifest sets the manifest used by Asset. It's usually called once,
+// when the application starts.
+func SetAssetManifest(m AssetManifest) {
+	assetManifest.Store(&m)
+}
+
+// Asset returns the fingerprinted URL of the named asset, using the manifest set
+// by SetAssetManifest, e.g. templ.Asset("app.css") returns "/static/app.3f9ab2c1.css".
+func Asset(name string) SafeURL {
+	m := assetManifest.Load()
+	if m == nil {
+		return SafeURL(name)
+	}
+	return m.URL(name)
+}
+
+// LoadAssetManifest reads a manifest file from fsys, e.g. an embed.FS. See
+// ParseAssetManifest for the supported formats.
+func LoadAssetManifest(fsys fs.FS, name, basePath string) (m AssetManifest, err error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return m, fmt.Errorf("templ: failed to read asset manifest: %w", err)
+	}
+	return ParseAssetManifest(data, basePath)
+}
+
+// ParseAssetManifest parses a JSON manifest produced by `templ assets`, a Vite
+// manifest (build.manifest), or an esbuild metafile. The file names in the
+// manifest are relative to basePath.
+//
+// The manifest is read token by token, instead of with json.Unmarshal, since
+// TinyGo's support for reflection is limited.
+func ParseAssetManifest(data []byte, basePath string) (m AssetManifest, err error) {
+	m = AssetManifest{
+		BasePath: basePath,
+		Files:    make(map[string]string),
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return m, fmt.Errorf("templ: failed to parse asset manifest: expected an object")
+	}
+	// esbuild metafiles map output files to their entry points.
+	var entryPoints map[string]string
+	// Names of the entries that aren't files or chunks.
+	var invalid []string
+	err = readMembers(dec, func(name string) error {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'):
+		case json.Delim('['):
+			invalid = append(invalid, name)
+			return skip(dec, t)
+		default:
+			// templ assets manifests map names to files.
+			if file, ok := t.(string); ok {
+				m.Files[name] = file
+			} else {
+				invalid = append(invalid, name)
+			}
+			return nil
+		}
+		if name == "outputs" && entryPoints == nil {
+			entryPoints = make(map[string]string)
+		}
+		// Vite manifests map names to chunks.
+		var file string
+		err = readMembers(dec, func(key string) error {
+			t, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if name == "outputs" && t == json.Delim('{') {
+				return readMembers(dec, func(field string) error {
+					t, err := dec.Token()
+					if err != nil {
+						return err
+					}
+					if s, ok := t.(string); ok && field == "entryPoint" && s != "" {
+						entryPoints[s] = key
+					}
+					return skip(dec, t)
+				})
+			}
+			if s, ok := t.(string); ok && key == "file" {
+				file = s
+			}
+			return skip(dec, t)
+		})
+		if file == "" {
+			invalid = append(invalid, name)
+		} else {
+			m.Files[name] = file
+		}
+		return err
+	})
+	if err != nil {
+		return m, fmt.Errorf("templ: failed to parse asset manifest: %w", err)
+	}
+	if entryPoints != nil {
+		m.Files = entryPoints
+		return m, nil
+	}
+	if len(invalid) > 0 {
+		return m, fmt.Errorf("templ: failed to parse asset manifest entry %q", invalid[0])
+	}
+	return m, nil
+}
+
+// readMembers reads the members of a JSON object, after its opening brace, and
+// its closing brace. f is called with the key of each member, and must read its
+// value.
+func readMembers(dec *json.Decoder, f func(key string) error) error {
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := t.(string)
+		if err = f(key); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// skip reads the rest of the JSON value that starts with the token.
+func skip(dec *json.Decoder, t json.Token) (err error) {
+	var depth int
+	for {
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+		if t, err = dec.Token(); err != nil {
+			return err
+		}
+	}
+}
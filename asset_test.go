@@ -0,0 +1,81 @@
+package templ_test
+
+import (
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAssetManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:     "templ assets manifest",
+			input:    `{"app.css": "app.3f9ab2c1.css", "js/app.js": "js/app.0d1e2f3a.js"}`,
+			expected: map[string]string{"app.css": "app.3f9ab2c1.css", "js/app.js": "js/app.0d1e2f3a.js"},
+		},
+		{
+			name:     "vite manifest",
+			input:    `{"src/main.ts": {"file": "assets/main.4889e940.js", "src": "src/main.ts", "isEntry": true, "css": ["assets/main.b82dbe22.css"], "dynamicImports": [{"nested": ["x"]}]}}`,
+			expected: map[string]string{"src/main.ts": "assets/main.4889e940.js"},
+		},
+		{
+			name:     "esbuild metafile",
+			input:    `{"inputs": {"src/app.css": {"bytes": 10}}, "outputs": {"dist/app-3F9AB2C1.css": {"entryPoint": "src/app.css"}, "dist/chunk-ABC.js": {}}}`,
+			expected: map[string]string{"src/app.css": "dist/app-3F9AB2C1.css"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := templ.ParseAssetManifest([]byte(tt.input), "/static/")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual.Files); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("invalid entries return an error", func(t *testing.T) {
+		if _, err := templ.ParseAssetManifest([]byte(`{"app.css": 123}`), ""); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("invalid JSON returns an error", func(t *testing.T) {
+		for _, input := range []string{``, `[]`, `{"app.css": "app.3f9ab2c1.css"`, `{"src/main.ts": {"file": }}`} {
+			if _, err := templ.ParseAssetManifest([]byte(input), ""); err == nil {
+				t.Errorf("%s: expected an error", input)
+			}
+		}
+	})
+}
+
+func TestAsset(t *testing.T) {
+	t.Run("names are returned unchanged if there is no manifest", func(t *testing.T) {
+		if actual := templ.Asset("app.css"); actual != "app.css" {
+			t.Errorf("expected %q, got %q", "app.css", actual)
+		}
+	})
+	templ.SetAssetManifest(templ.AssetManifest{
+		BasePath: "/static/",
+		Files:    map[string]string{"app.css": "app.3f9ab2c1.css"},
+	})
+	defer templ.SetAssetManifest(templ.AssetManifest{})
+	tests := []struct {
+		name     string
+		expected templ.SafeURL
+	}{
+		{name: "app.css", expected: "/static/app.3f9ab2c1.css"},
+		{name: "favicon.ico", expected: "/static/favicon.ico"},
+	}
+	for _, tt := range tests {
+		if actual := templ.Asset(tt.name); actual != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package templ
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// Behavior attaches a script template to an event of an element without an
+// inline event handler attribute.
+type Behavior struct {
+	// Event name, e.g. click.
+	Event string
+	// Script to call when the event is dispatched.
+	Script ComponentScript
+}
+
+// On returns a behavior that calls the script when the event is dispatched.
+//
+// The script is called with the element as this, and the event after the
+// parameters of the script.
+func On(event string, script ComponentScript) Behavior {
+	return Behavior{Event: event, Script: script}
+}
+
+// behaviorRuntime is the script that attaches behaviors to the elements with
+// data-templ-on attributes, including elements that are added to the document
+// later. The scripts are looked up in the registry when the event is dispatched.
+const behaviorRuntime = `(function(){` +
+	`if(window.templ_behaviors)return;` +
+	`var r=window.templ_behaviors={};` +
+	`function bind(el){` +
+	`if(el.templ_bound)return;el.templ_bound=true;` +
+	`el.getAttribute("data-templ-on").split(/\s+/).forEach(function(h){` +
+	`var i=h.indexOf(":");if(i<1)return;var n=h.slice(i+1);` +
+	`el.addEventListener(h.slice(0,i),function(e){` +
+	`var f=r[n];if(!f)return;var a=el.getAttribute("data-templ-args-"+n);` +
+	`f.apply(el,(a?JSON.parse(a):[]).concat([e]));` +
+	`});});}` +
+	`function scan(n){` +
+	`if(n.matches&&n.matches("[data-templ-on]"))bind(n);` +
+	`if(n.querySelectorAll)n.querySelectorAll("[data-templ-on]").forEach(bind);}` +
+	`new MutationObserver(function(ms){ms.forEach(function(m){m.addedNodes.forEach(scan);});})` +
+	`.observe(document.documentElement,{childList:true,subtree:true});` +
+	`scan(document);` +
+	`})();`
+
+// RenderBehaviors renders a <script> element that registers the scripts as
+// behaviors. The behavior runtime is rendered with the first behaviors of the
+// request, and each script is only registered once.
+func RenderBehaviors(ctx context.Context, w io.Writer, scripts ...ComponentScript) (err error) {
+	if len(scripts) == 0 {
+		return nil
+	}
+	_, v := getContext(ctx)
+	sb := new(strings.Builder)
+	if !v.hasScriptBeenRendered("templ_behaviors") {
+		sb.WriteString(behaviorRuntime)
+		v.addScript("templ_behaviors")
+	}
+	for _, s := range scripts {
+		if v.hasScriptBeenRendered("templ_behavior_" + s.Name) {
+			continue
+		}
+		sb.WriteString(s.Function)
+		sb.WriteString(`templ_behaviors["` + s.Name + `"]=` + s.Name + `;`)
+		v.addScript("templ_behavior_" + s.Name)
+	}
+	if sb.Len() == 0 {
+		return nil
+	}
+	return writeStrings(w, `<script type="text/javascript">`, sb.String(), `</script>`)
+}
+
+// Behaviors is a component that registers the scripts as behaviors. See RenderBehaviors.
+func Behaviors(scripts ...ComponentScript) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return RenderBehaviors(ctx, w, scripts...)
+	})
+}
+
+// BehaviorAttributes returns the data-templ-on attribute that attaches the
+// behaviors to an element, e.g. data-templ-on="click:__templ_greet_1234", and the
+// data attributes that contain the parameters of the scripts.
+//
+// The scripts must also be registered with RenderBehaviors.
+func BehaviorAttributes(behaviors ...Behavior) Attributes {
+	attrs := Attributes{}
+	handlers := make([]string, len(behaviors))
+	for i, b := range behaviors {
+		handlers[i] = b.Event + ":" + b.Script.Name
+		args, err := marshalJSON(b.Script.Params)
+		if err != nil || len(b.Script.Params) == 0 {
+			continue
+		}
+		attrs[scriptDataArgsAttributeName(b.Script.Name)] = string(args)
+	}
+	if len(handlers) > 0 {
+		attrs["data-templ-on"] = strings.Join(handlers, " ")
+	}
+	return attrs
+}
@@ -0,0 +1,53 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderBehaviors(t *testing.T) {
+	greet := templ.ComponentScript{
+		Name:     "__templ_greet_1234",
+		Function: "function __templ_greet_1234(name){alert(name);}",
+		Params:   []any{"Alice"},
+	}
+	ctx := templ.InitializeContext(context.Background())
+
+	w := new(strings.Builder)
+	if err := templ.RenderBehaviors(ctx, w, greet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(w.String(), `<script type="text/javascript">(function(){`) {
+		t.Errorf("expected the behavior runtime to be rendered, got %q", w.String())
+	}
+	if !strings.HasSuffix(w.String(), greet.Function+`templ_behaviors["__templ_greet_1234"]=__templ_greet_1234;</script>`) {
+		t.Errorf("expected the script to be registered, got %q", w.String())
+	}
+
+	w.Reset()
+	if err := templ.Behaviors(greet).Render(ctx, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.Len() != 0 {
+		t.Errorf("expected behaviors to be rendered once, got %q", w.String())
+	}
+}
+
+func TestBehaviorAttributes(t *testing.T) {
+	greet := templ.ComponentScript{Name: "greet", Params: []any{"Alice", 1}}
+	highlight := templ.ComponentScript{Name: "highlight"}
+
+	w := new(strings.Builder)
+	attrs := templ.BehaviorAttributes(templ.On("click", greet), templ.On("mouseover", highlight))
+	if err := templ.RenderAttributes(context.Background(), w, attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ` data-templ-args-greet="[&#34;Alice&#34;,1]" data-templ-on="click:greet mouseover:highlight"`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -1,6 +1,23 @@
 package testhtml
 
+import "github.com/a-h/templ"
+
 type Person struct {
 	Name  string
 	Email string
 }
+
+type Row struct {
+	ID     int
+	Name   string
+	Email  string
+	Active bool
+}
+
+type Item struct {
+	ID       string
+	Name     string
+	Selected bool
+	Hidden   bool
+	Attrs    templ.Attributes
+}
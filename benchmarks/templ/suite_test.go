@@ -0,0 +1,52 @@
+package testhtml
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func benchmarkRender(b *testing.B, c templ.Component) {
+	b.ReportAllocs()
+	w := new(strings.Builder)
+	for i := 0; i < b.N; i++ {
+		if err := c.Render(context.Background(), w); err != nil {
+			b.Fatalf("failed to render: %v", err)
+		}
+		w.Reset()
+	}
+}
+
+func BenchmarkLargeTable(b *testing.B) {
+	rows := make([]Row, 1000)
+	for i := range rows {
+		rows[i] = Row{
+			ID:     i,
+			Name:   "Name " + strconv.Itoa(i),
+			Email:  "user" + strconv.Itoa(i) + "@example.com",
+			Active: i%2 == 0,
+		}
+	}
+	benchmarkRender(b, LargeTable(rows))
+}
+
+func BenchmarkDeepNesting(b *testing.B) {
+	benchmarkRender(b, DeepNesting(100))
+}
+
+func BenchmarkAttributeHeavy(b *testing.B) {
+	items := make([]Item, 200)
+	for i := range items {
+		items[i] = Item{
+			ID:       "item-" + strconv.Itoa(i),
+			Name:     `Item "` + strconv.Itoa(i) + `"`,
+			Selected: i%10 == 0,
+			Hidden:   i%7 == 0,
+			Attrs:    templ.Attributes{"data-index": strconv.Itoa(i), "draggable": true},
+		}
+	}
+	benchmarkRender(b, AttributeHeavy(items))
+}
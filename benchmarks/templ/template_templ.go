@@ -17,6 +17,7 @@ func Render(p Person) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testhtml.Render`, &templ_7745c5c3_SourceLines_1ae21a6a)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -110,3 +111,5 @@ func Render(p Person) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_1ae21a6a = templ.SourceLines{FileName: `benchmarks/templ/template.templ`, From: 12, To: 113, Lines: []int{12, 3, 31, 5, 44, 6, 56, 7, 66, 7, 78, 10, 88, 11, 98, 11}}
@@ -0,0 +1,54 @@
+package templ
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// CacheKeyer is implemented by components that render the same output whenever
+// their inputs are the same. The key identifies the component and its inputs,
+// so that renders of the same component with the same inputs can be cached, or
+// skipped. Two components with the same key must render the same output.
+type CacheKeyer interface {
+	CacheKey() string
+}
+
+// CacheKey returns the cache key of the component, and whether it has one.
+func CacheKey(c Component) (key string, ok bool) {
+	ck, ok := c.(CacheKeyer)
+	if !ok {
+		return "", false
+	}
+	return ck.CacheKey(), true
+}
+
+// WithCacheKey returns the component with a cache key, see CacheKeyer. Use
+// NewCacheKey to create a key from the inputs of the component.
+func WithCacheKey(key string, c Component) Component {
+	return cacheKeyComponent{Component: c, key: key}
+}
+
+type cacheKeyComponent struct {
+	Component
+	key string
+}
+
+func (c cacheKeyComponent) CacheKey() string {
+	return c.key
+}
+
+// NewCacheKey creates a cache key from the name of a component and its inputs,
+// e.g. templ.NewCacheKey("product", p.ID, p.Name, p.Price). The inputs are
+// encoded as JSON, so they must be values that can be encoded by json.Marshal.
+func NewCacheKey(name string, inputs ...any) (key string, err error) {
+	b := append([]byte(name), 0)
+	for i, input := range inputs {
+		if b, err = appendJSON(b, input); err != nil {
+			return "", fmt.Errorf("templ: failed to create cache key for %s, input %d: %w", name, i, err)
+		}
+		b = append(b, 0)
+	}
+	sum := sha256.Sum256(b)
+	return name + "_" + hex.EncodeToString(sum[:16]), nil
+}
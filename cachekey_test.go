@@ -0,0 +1,67 @@
+package templ_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCacheKey(t *testing.T) {
+	t.Run("components without a cache key don't have one", func(t *testing.T) {
+		if _, ok := templ.CacheKey(templ.Raw("Hello")); ok {
+			t.Error("expected no cache key")
+		}
+	})
+	t.Run("WithCacheKey sets the cache key", func(t *testing.T) {
+		key, ok := templ.CacheKey(templ.WithCacheKey("hello", templ.Raw("Hello")))
+		if !ok {
+			t.Fatal("expected a cache key")
+		}
+		if diff := cmp.Diff("hello", key); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestNewCacheKey(t *testing.T) {
+	mustKey := func(name string, inputs ...any) string {
+		t.Helper()
+		key, err := templ.NewCacheKey(name, inputs...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return key
+	}
+	t.Run("the same inputs create the same key", func(t *testing.T) {
+		a := mustKey("product", 1, "Widget", map[string]any{"price": 9.99, "tags": []string{"a"}})
+		b := mustKey("product", 1, "Widget", map[string]any{"tags": []string{"a"}, "price": 9.99})
+		if a != b {
+			t.Errorf("expected the same key, got %q and %q", a, b)
+		}
+	})
+	t.Run("different inputs create different keys", func(t *testing.T) {
+		keys := map[string]bool{
+			mustKey("product", 1, "Widget"):    true,
+			mustKey("product", 2, "Widget"):    true,
+			mustKey("product", "1Widget"):      true,
+			mustKey("product", "1", "Widget"):  true,
+			mustKey("productRow", 1, "Widget"): true,
+		}
+		if len(keys) != 5 {
+			t.Errorf("expected 5 different keys, got %d", len(keys))
+		}
+	})
+	t.Run("keys start with the name of the component", func(t *testing.T) {
+		key := mustKey("product", 1)
+		if diff := cmp.Diff("product_", key[:len("product_")]); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("inputs that can't be encoded return an error", func(t *testing.T) {
+		if _, err := templ.NewCacheKey("product", math.NaN()); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,49 @@
+//go:build go1.23
+
+package templ
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+)
+
+var errInvalidChunkSize = errors.New("templ: chunk size must be greater than zero")
+
+// RenderChunks returns an iterator over the output of the component in chunks of
+// chunkSize bytes, e.g. to upload the parts of a large document to object
+// storage. The component is rendered in a new goroutine as the iterator is
+// advanced, see NewReader. All chunks are chunkSize bytes, except the last.
+//
+// The chunk is reused by the next iteration, so it must be copied to keep it. If
+// rendering fails, the error is returned after the output that was rendered.
+// Stopping the iteration early cancels rendering.
+//
+// Generated components write their output once they've rendered, so to keep
+// memory use bounded, a large document should be made of many components, e.g.
+// one for each row of a table.
+func RenderChunks(ctx context.Context, c Component, chunkSize int) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		if chunkSize <= 0 {
+			yield(nil, errInvalidChunkSize)
+			return
+		}
+		r := NewReader(ctx, c)
+		defer r.Close()
+		chunk := make([]byte, chunkSize)
+		for {
+			n, err := io.ReadFull(r, chunk)
+			if n > 0 && !yield(chunk[:n], nil) {
+				return
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+//go:build go1.23
+
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderChunks(t *testing.T) {
+	renderErr := errors.New("render error")
+	tests := []struct {
+		name           string
+		input          templ.Component
+		chunkSize      int
+		expectedChunks []string
+		expectedErr    error
+	}{
+		{
+			name:           "output is split into chunks",
+			input:          templ.Raw("abcdefgh"),
+			chunkSize:      3,
+			expectedChunks: []string{"abc", "def", "gh"},
+		},
+		{
+			name:           "output that fits in a chunk is a single chunk",
+			input:          templ.Raw("abc"),
+			chunkSize:      3,
+			expectedChunks: []string{"abc"},
+		},
+		{
+			name:           "components that don't write anything have no chunks",
+			input:          templ.NopComponent,
+			chunkSize:      3,
+			expectedChunks: nil,
+		},
+		{
+			name: "render errors are returned after the output",
+			input: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				if _, err := io.WriteString(w, "abcd"); err != nil {
+					return err
+				}
+				return renderErr
+			}),
+			chunkSize:      3,
+			expectedChunks: []string{"abc", "d"},
+			expectedErr:    renderErr,
+		},
+		{
+			name:           "the chunk size must be greater than zero",
+			input:          templ.Raw("abc"),
+			chunkSize:      0,
+			expectedChunks: nil,
+			expectedErr:    errors.New("templ: chunk size must be greater than zero"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var chunks []string
+			var err error
+			for chunk, chunkErr := range templ.RenderChunks(context.Background(), tt.input, tt.chunkSize) {
+				if chunkErr != nil {
+					err = chunkErr
+					continue
+				}
+				chunks = append(chunks, string(chunk))
+			}
+			if diff := cmp.Diff(tt.expectedChunks, chunks); diff != "" {
+				t.Error(diff)
+			}
+			if (tt.expectedErr == nil) != (err == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+	t.Run("stopping early cancels rendering", func(t *testing.T) {
+		canceled := make(chan error, 1)
+		c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			for {
+				if _, err := io.WriteString(w, "row"); err != nil {
+					<-ctx.Done()
+					canceled <- ctx.Err()
+					return err
+				}
+			}
+		})
+		for range templ.RenderChunks(context.Background(), c, 2) {
+			break
+		}
+		if err := <-canceled; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package analyzecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config is the configuration of components to analyze, read from a JSON file.
+type Config struct {
+	Components []Component `json:"components"`
+}
+
+// Component to render and analyze.
+type Component struct {
+	// Name of the component, used in the report.
+	Name string `json:"name"`
+	// Imports required by the Expression, e.g. "github.com/example/app/components".
+	Imports []string `json:"imports"`
+	// Expression is a Go expression that returns the templ.Component to render,
+	// e.g. "components.Home(fixtures.HomePage)".
+	Expression string `json:"expression"`
+	// Budget that the rendered output must not exceed.
+	Budget Budget `json:"budget"`
+}
+
+// Budget sets limits on the rendered output. Zero values are not checked.
+type Budget struct {
+	// Size is the maximum size of the output in bytes.
+	Size int `json:"size"`
+	// GzipSize is the maximum size of the gzip compressed output in bytes.
+	GzipSize int `json:"gzipSize"`
+	// Nodes is the maximum number of elements in the output.
+	Nodes int `json:"nodes"`
+	// ScriptBytes is the maximum number of bytes of inline script.
+	ScriptBytes int `json:"scriptBytes"`
+	// StyleBytes is the maximum number of bytes of inline style.
+	StyleBytes int `json:"styleBytes"`
+}
+
+// Check returns a message for each budget that the report exceeds.
+func (b Budget) Check(r Report) (exceeded []string) {
+	check := func(name string, actual, budget int) {
+		if budget > 0 && actual > budget {
+			exceeded = append(exceeded, fmt.Sprintf("%s of %d exceeds budget of %d", name, actual, budget))
+		}
+	}
+	check("size", r.Size, b.Size)
+	check("gzip size", r.GzipSize, b.GzipSize)
+	check("node count", r.Nodes, b.Nodes)
+	check("script bytes", r.ScriptBytes, b.ScriptBytes)
+	check("style bytes", r.StyleBytes, b.StyleBytes)
+	return exceeded
+}
+
+func loadConfig(fileName string) (c Config, err error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return c, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse config %q: %w", fileName, err)
+	}
+	if len(c.Components) == 0 {
+		return c, fmt.Errorf("no components found in config %q", fileName)
+	}
+	for i, cc := range c.Components {
+		if cc.Expression == "" {
+			return c, fmt.Errorf("component %d in config %q has no expression", i, fileName)
+		}
+		if cc.Name == "" {
+			c.Components[i].Name = cc.Expression
+		}
+	}
+	return c, nil
+}
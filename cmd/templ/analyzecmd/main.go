@@ -0,0 +1,59 @@
+package analyzecmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+)
+
+type Arguments struct {
+	// Path to a directory within the Go module that contains the components.
+	Path string
+	// ConfigFile lists the components to analyze, and their budgets.
+	ConfigFile string
+}
+
+// Run renders the components listed in the config file, and reports the
+// size of their output. An error is returned if any budget is exceeded.
+func Run(ctx context.Context, w io.Writer, args Arguments) (err error) {
+	config, err := loadConfig(args.ConfigFile)
+	if err != nil {
+		return err
+	}
+	outputs, err := render(ctx, args.Path, config.Components)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]Report, len(outputs))
+	for i, output := range outputs {
+		if reports[i], err = Measure(output); err != nil {
+			return fmt.Errorf("failed to measure %q: %w", config.Components[i].Name, err)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tSIZE\tGZIP\tNODES\tSCRIPT\tSTYLE")
+	for i, r := range reports {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n", config.Components[i].Name, r.Size, r.GzipSize, r.Nodes, r.ScriptBytes, r.StyleBytes)
+	}
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	var exceeded int
+	for i, r := range reports {
+		for _, msg := range config.Components[i].Budget.Check(r) {
+			color.New(color.FgRed).Fprint(w, "(✗) ")
+			fmt.Fprintf(w, "%s: %s\n", config.Components[i].Name, msg)
+			exceeded++
+		}
+	}
+	if exceeded > 0 {
+		return fmt.Errorf("%d budget(s) exceeded", exceeded)
+	}
+	return nil
+}
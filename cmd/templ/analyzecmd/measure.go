@@ -0,0 +1,75 @@
+package analyzecmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// Report of the rendered output of a component.
+type Report struct {
+	// Size of the output in bytes.
+	Size int
+	// GzipSize is the size of the gzip compressed output in bytes.
+	GzipSize int
+	// Nodes is the number of elements in the output.
+	Nodes int
+	// ScriptBytes is the number of bytes within script elements.
+	ScriptBytes int
+	// StyleBytes is the number of bytes within style elements and style attributes.
+	StyleBytes int
+}
+
+// Measure the rendered HTML output of a component.
+func Measure(output []byte) (r Report, err error) {
+	r.Size = len(output)
+
+	var gz bytes.Buffer
+	gzw := gzip.NewWriter(&gz)
+	if _, err = gzw.Write(output); err != nil {
+		return r, err
+	}
+	if err = gzw.Close(); err != nil {
+		return r, err
+	}
+	r.GzipSize = gz.Len()
+
+	z := html.NewTokenizer(bytes.NewReader(output))
+	var inScript, inStyle bool
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return r, nil
+			}
+			return r, z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			r.Nodes++
+			name, hasAttr := z.TagName()
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				if string(k) == "style" {
+					r.StyleBytes += len(v)
+				}
+			}
+			if tt == html.StartTagToken {
+				inScript = string(name) == "script"
+				inStyle = string(name) == "style"
+			}
+		case html.EndTagToken:
+			inScript, inStyle = false, false
+		case html.TextToken:
+			if inScript {
+				r.ScriptBytes += len(z.Raw())
+			}
+			if inStyle {
+				r.StyleBytes += len(z.Raw())
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package analyzecmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Report
+	}{
+		{
+			name:     "empty output",
+			input:    ``,
+			expected: Report{},
+		},
+		{
+			name:  "elements are counted",
+			input: `<div><p>Hello</p><br/><img src="a.png"></div>`,
+			expected: Report{
+				Size:  45,
+				Nodes: 4,
+			},
+		},
+		{
+			name:  "script and style contents are counted",
+			input: `<style>.a{color:red}</style><script>alert("</div>")</script><div style="color:blue"></div>`,
+			expected: Report{
+				Size:        90,
+				Nodes:       3,
+				ScriptBytes: 15,
+				StyleBytes:  23,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Measure([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual, cmpopts.IgnoreFields(Report{}, "GzipSize")); diff != "" {
+				t.Error(diff)
+			}
+			if actual.GzipSize == 0 {
+				t.Error("expected gzip size to be set")
+			}
+		})
+	}
+}
+
+func TestBudgetCheck(t *testing.T) {
+	b := Budget{Size: 100, GzipSize: 50, Nodes: 0}
+	actual := b.Check(Report{Size: 101, GzipSize: 50, Nodes: 1000})
+	expected := []string{"size of 101 exceeds budget of 100"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRenderProgram(t *testing.T) {
+	program, err := renderProgram([]Component{
+		{
+			Imports:    []string{"github.com/example/app/components", "github.com/a-h/templ"},
+			Expression: `components.Home("Alice")`,
+		},
+		{
+			Imports:    []string{"github.com/example/app/components"},
+			Expression: `templ.Raw("<div></div>")`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"\t\"github.com/example/app/components\"\n",
+		"\t\tcomponents.Home(\"Alice\"),\n\t\ttempl.Raw(\"<div></div>\"),\n",
+	} {
+		if !strings.Contains(string(program), expected) {
+			t.Errorf("expected program to contain %q, got:\n%s", expected, program)
+		}
+	}
+	if n := strings.Count(string(program), `"github.com/a-h/templ"`); n != 1 {
+		t.Errorf("expected templ to be imported once, got %d", n)
+	}
+}
@@ -0,0 +1,100 @@
+package analyzecmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// renderProgram returns the source of a Go program that renders each component
+// to a file named <index>.html in the directory passed as its first argument.
+func renderProgram(components []Component) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by templ analyze - DO NOT EDIT.\n\n")
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n")
+	imports := map[string]bool{}
+	for _, imp := range []string{"context", "fmt", "os", "path/filepath", "github.com/a-h/templ"} {
+		imports[imp] = true
+		b.WriteString(strconv.Quote(imp) + "\n")
+	}
+	for _, c := range components {
+		for _, imp := range c.Imports {
+			if imports[imp] {
+				continue
+			}
+			imports[imp] = true
+			b.WriteString(strconv.Quote(imp) + "\n")
+		}
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("func main() {\n")
+	b.WriteString("components := []templ.Component{\n")
+	for _, c := range components {
+		b.WriteString(c.Expression + ",\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString(`for i, c := range components {
+	f, err := os.Create(filepath.Join(os.Args[1], fmt.Sprintf("%d.html", i)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create output file: %v\n", err)
+		os.Exit(1)
+	}
+	if err = c.Render(context.Background(), f); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render component %d: %v\n", i, err)
+		os.Exit(1)
+	}
+	if err = f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to close output file: %v\n", err)
+		os.Exit(1)
+	}
+}
+}
+`)
+	return format.Source(b.Bytes())
+}
+
+// render the components by building and running a Go program within the
+// module at path, so that the expressions can use the module's packages.
+func render(ctx context.Context, path string, components []Component) (outputs [][]byte, err error) {
+	program, err := renderProgram(components)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create render program, check the component expressions and imports: %w", err)
+	}
+
+	// Directories that start with "." are ignored by ./... patterns.
+	programDir, err := os.MkdirTemp(path, ".templ-analyze-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create program directory: %w", err)
+	}
+	defer os.RemoveAll(programDir)
+	if err = os.WriteFile(filepath.Join(programDir, "main.go"), program, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write program: %w", err)
+	}
+
+	outputDir, err := os.MkdirTemp("", "templ-analyze-output-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	defer os.RemoveAll(outputDir)
+
+	cmd := exec.CommandContext(ctx, "go", "run", "./"+filepath.Base(programDir), outputDir)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to render components: %w\n%s", err, output)
+	}
+
+	outputs = make([][]byte, len(components))
+	for i := range components {
+		outputs[i], err = os.ReadFile(filepath.Join(outputDir, fmt.Sprintf("%d.html", i)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read output of %q: %w", components[i].Name, err)
+		}
+	}
+	return outputs, nil
+}
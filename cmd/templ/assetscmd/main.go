@@ -0,0 +1,93 @@
+package assetscmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFileName is the name of the manifest written to the output directory.
+const ManifestFileName = "templ-assets.json"
+
+type Arguments struct {
+	// Path to the directory that contains the static assets.
+	Path string
+	// OutputPath is the directory that fingerprinted copies of the assets, and the
+	// manifest, are written to.
+	OutputPath string
+}
+
+// Run copies each file in the Path to the OutputPath with a hash of its contents
+// added to its name, e.g. app.css is copied to app.3f9ab2c1.css, and writes a
+// manifest that maps the original names to the fingerprinted names.
+func Run(w io.Writer, args Arguments) (err error) {
+	src, err := filepath.Abs(args.Path)
+	if err != nil {
+		return err
+	}
+	dst, err := filepath.Abs(args.OutputPath)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return fmt.Errorf("the output path must be different to the asset path")
+	}
+	manifest := make(map[string]string)
+	err = filepath.WalkDir(src, func(fileName string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Skip the output directory if it's inside the asset directory.
+			if fileName == dst {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(src, fileName)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return fmt.Errorf("failed to read asset: %w", err)
+		}
+		name := filepath.ToSlash(rel)
+		fingerprinted := fingerprint(name, data)
+		target := filepath.Join(dst, filepath.FromSlash(fingerprinted))
+		if err = os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(target, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write asset: %w", err)
+		}
+		manifest[name] = fingerprinted
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dst, ManifestFileName), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	fmt.Fprintf(w, "Fingerprinted %d assets\n", len(manifest))
+	return nil
+}
+
+// fingerprint adds a hash of the data to the name, before the file extension.
+func fingerprint(name string, data []byte) string {
+	hash := sha256.Sum256(data)
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(hash[:4]) + ext
+}
@@ -0,0 +1,61 @@
+package assetscmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "static")
+	if err := os.MkdirAll(filepath.Join(src, "js"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "app.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "js", "app.js"), []byte("alert(1)"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(src, "dist")
+	for i := 0; i < 2; i++ {
+		// The output directory is skipped when the command is run again.
+		if err := Run(io.Discard, Arguments{Path: src, OutputPath: dst}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, ManifestFileName))
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var actual map[string]string
+	if err = json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("failed to parse manifest: %v", err)
+	}
+	expected := map[string]string{
+		"app.css":   fingerprint("app.css", []byte("body{}")),
+		"js/app.js": fingerprint("js/app.js", []byte("alert(1)")),
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	for _, file := range actual {
+		if _, err := os.Stat(filepath.Join(dst, file)); err != nil {
+			t.Errorf("expected fingerprinted file: %v", err)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	actual := fingerprint("css/app.css", []byte("body{}"))
+	if filepath.Ext(actual) != ".css" || filepath.Dir(actual) != "css" || len(actual) != len("css/app.12345678.css") {
+		t.Errorf("unexpected fingerprinted name %q", actual)
+	}
+}
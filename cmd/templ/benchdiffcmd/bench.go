@@ -0,0 +1,196 @@
+package benchdiffcmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Result is the mean of the runs of a benchmark.
+type Result struct {
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+	runs        int
+	// samples are the values of each run, by metric, e.g. ns/op.
+	samples map[string][]float64
+}
+
+// gomaxprocsSuffix is added to benchmark names by go test, e.g. BenchmarkX-8.
+var gomaxprocsSuffix = regexp.MustCompile(`-\d+$`)
+
+// ParseResults parses the output of go test -bench -benchmem, and returns the
+// mean result of each benchmark.
+func ParseResults(r io.Reader) (results map[string]Result, err error) {
+	results = make(map[string]Result)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		name := gomaxprocsSuffix.ReplaceAllString(fields[0], "")
+		result := results[name]
+		if result.samples == nil {
+			result.samples = make(map[string][]float64)
+		}
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid benchmark line %q: %w", scanner.Text(), err)
+			}
+			result.samples[fields[i+1]] = append(result.samples[fields[i+1]], value)
+			switch fields[i+1] {
+			case "ns/op":
+				result.NsPerOp += value
+			case "B/op":
+				result.BytesPerOp += value
+			case "allocs/op":
+				result.AllocsPerOp += value
+			}
+		}
+		result.runs++
+		results[name] = result
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	for name, result := range results {
+		n := float64(result.runs)
+		results[name] = Result{
+			NsPerOp:     result.NsPerOp / n,
+			BytesPerOp:  result.BytesPerOp / n,
+			AllocsPerOp: result.AllocsPerOp / n,
+			runs:        result.runs,
+			samples:     result.samples,
+		}
+	}
+	return results, nil
+}
+
+// Comparison of the results of a benchmark at two revisions.
+type Comparison struct {
+	Name       string
+	Base, Head Result
+}
+
+// significance is the p-value below which a difference between the runs of a
+// benchmark at two revisions isn't considered to be noise.
+const significance = 0.05
+
+// Regressions returns the metrics that have increased by more than the
+// threshold percentage, e.g. ns/op, where the difference between the runs at
+// each revision is statistically significant. At least 4 runs of each revision
+// are needed for a difference to be significant.
+func (c Comparison) Regressions(threshold float64) (metrics []string) {
+	for _, m := range []struct {
+		name       string
+		base, head float64
+	}{
+		{name: "ns/op", base: c.Base.NsPerOp, head: c.Head.NsPerOp},
+		{name: "B/op", base: c.Base.BytesPerOp, head: c.Head.BytesPerOp},
+		{name: "allocs/op", base: c.Base.AllocsPerOp, head: c.Head.AllocsPerOp},
+	} {
+		if delta(m.base, m.head) <= threshold {
+			continue
+		}
+		if mannWhitneyP(c.Base.samples[m.name], c.Head.samples[m.name]) >= significance {
+			continue
+		}
+		metrics = append(metrics, m.name)
+	}
+	return metrics
+}
+
+// mannWhitneyP returns the two-sided p-value of the Mann-Whitney U test that the
+// samples are from the same distribution, as used by benchstat. The p-value is
+// exact, ignoring ties.
+func mannWhitneyP(x, y []float64) float64 {
+	if len(x) == 0 || len(y) == 0 {
+		return 1
+	}
+	var u float64
+	for _, a := range x {
+		for _, b := range y {
+			switch {
+			case a > b:
+				u++
+			case a == b:
+				u += 0.5
+			}
+		}
+	}
+	// counts[k] is the number of orderings of the samples in which U is k.
+	counts := uCounts(len(x), len(y))
+	var total float64
+	for _, c := range counts {
+		total += c
+	}
+	tail := math.Min(u, float64(len(x)*len(y))-u)
+	var cumulative float64
+	for k := 0; float64(k) <= tail; k++ {
+		cumulative += counts[k]
+	}
+	return math.Min(1, 2*cumulative/total)
+}
+
+// uCounts returns the number of orderings of samples of size m and n in which
+// the Mann-Whitney U statistic is each value from 0 to m*n.
+func uCounts(m, n int) []float64 {
+	// prev[j] are the counts for samples of size i-1 and j, and curr[j] for size i and j.
+	prev := make([][]float64, n+1)
+	for j := range prev {
+		prev[j] = []float64{1}
+	}
+	for i := 1; i <= m; i++ {
+		curr := make([][]float64, n+1)
+		curr[0] = []float64{1}
+		for j := 1; j <= n; j++ {
+			counts := make([]float64, i*j+1)
+			// The largest value is in the first sample, so it's greater than the j
+			// values of the second sample.
+			for u, c := range prev[j] {
+				counts[u+j] += c
+			}
+			// The largest value is in the second sample.
+			for u, c := range curr[j-1] {
+				counts[u] += c
+			}
+			curr[j] = counts
+		}
+		prev = curr
+	}
+	return prev[n]
+}
+
+// Compare returns the comparisons of the benchmarks that have results at both
+// revisions, sorted by name.
+func Compare(base, head map[string]Result) (comparisons []Comparison) {
+	for name, h := range head {
+		b, ok := base[name]
+		if !ok {
+			continue
+		}
+		comparisons = append(comparisons, Comparison{Name: name, Base: b, Head: h})
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].Name < comparisons[j].Name
+	})
+	return comparisons
+}
+
+// delta returns the percentage change from base to head.
+func delta(base, head float64) float64 {
+	if base == 0 {
+		if head == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (head - base) / base * 100
+}
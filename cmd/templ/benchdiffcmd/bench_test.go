@@ -0,0 +1,97 @@
+package benchdiffcmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const benchmarkOutput = `goos: linux
+goarch: amd64
+pkg: github.com/a-h/templ/benchmarks/templ
+BenchmarkLargeTable-8       	     900	   1300000 ns/op	  535824 B/op	    7441 allocs/op
+BenchmarkLargeTable-8       	     900	   1100000 ns/op	  535824 B/op	    7441 allocs/op
+BenchmarkTemplParser-8      	    5000	    217577 ns/op
+PASS
+ok  	github.com/a-h/templ/benchmarks/templ	4.650s
+`
+
+func TestParseResults(t *testing.T) {
+	results, err := ParseResults(strings.NewReader(benchmarkOutput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]Result{
+		"BenchmarkLargeTable":  {NsPerOp: 1200000, BytesPerOp: 535824, AllocsPerOp: 7441},
+		"BenchmarkTemplParser": {NsPerOp: 217577},
+	}
+	if diff := cmp.Diff(expected, results, cmpopts.IgnoreUnexported(Result{})); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// runs returns the result of a benchmark that was run once for each ns/op value.
+	runs := func(allocsPerOp float64, nsPerOp ...float64) Result {
+		r := Result{samples: map[string][]float64{}}
+		for _, ns := range nsPerOp {
+			r.NsPerOp += ns / float64(len(nsPerOp))
+			r.samples["ns/op"] = append(r.samples["ns/op"], ns)
+			r.samples["allocs/op"] = append(r.samples["allocs/op"], allocsPerOp)
+		}
+		r.AllocsPerOp = allocsPerOp
+		return r
+	}
+	base := map[string]Result{
+		"BenchmarkA":       runs(10, 100, 101, 99, 100, 100),
+		"BenchmarkB":       runs(10, 100, 101, 99, 100, 100),
+		"BenchmarkNoisy":   runs(10, 100, 60, 140, 100, 100),
+		"BenchmarkRemoved": runs(0, 100),
+	}
+	head := map[string]Result{
+		"BenchmarkB":     runs(12, 105, 104, 106, 105, 105),
+		"BenchmarkA":     runs(10, 90, 91, 89, 90, 90),
+		"BenchmarkNoisy": runs(10, 150, 70, 130, 110, 90),
+		"BenchmarkAdded": runs(0, 100),
+	}
+	comparisons := Compare(base, head)
+	expected := []Comparison{
+		{Name: "BenchmarkA", Base: base["BenchmarkA"], Head: head["BenchmarkA"]},
+		{Name: "BenchmarkB", Base: base["BenchmarkB"], Head: head["BenchmarkB"]},
+		{Name: "BenchmarkNoisy", Base: base["BenchmarkNoisy"], Head: head["BenchmarkNoisy"]},
+	}
+	if diff := cmp.Diff(expected, comparisons, cmpopts.IgnoreUnexported(Result{})); diff != "" {
+		t.Fatal(diff)
+	}
+	if regressions := comparisons[0].Regressions(10); len(regressions) != 0 {
+		t.Errorf("expected no regressions for a faster benchmark, got %v", regressions)
+	}
+	if diff := cmp.Diff([]string{"allocs/op"}, comparisons[1].Regressions(10)); diff != "" {
+		t.Error(diff)
+	}
+	if regressions := comparisons[2].Regressions(1); len(regressions) != 0 {
+		t.Errorf("expected no regressions for a difference that isn't significant, got %v", regressions)
+	}
+}
+
+func TestMannWhitneyP(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     []float64
+		expected float64
+	}{
+		{name: "samples that don't overlap", x: []float64{1, 2, 3, 4, 5}, y: []float64{6, 7, 8, 9, 10}, expected: 2.0 / 252},
+		{name: "identical samples", x: []float64{1, 1, 1}, y: []float64{1, 1, 1}, expected: 1},
+		{name: "single runs are never significant", x: []float64{1}, y: []float64{2}, expected: 1},
+		{name: "no runs", x: nil, y: []float64{2}, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, mannWhitneyP(tt.x, tt.y), cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package benchdiffcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+)
+
+type Arguments struct {
+	// Base is the git revision to compare against, e.g. main.
+	Base string
+	// Head is the git revision to compare. If empty, the working tree is used.
+	Head string
+	// Path of the directory that contains the benchmarks, relative to the root
+	// of the repository.
+	Path string
+	// Bench is a regular expression that selects the benchmarks to run.
+	Bench string
+	// Count is the number of times to run each benchmark.
+	Count int
+	// Threshold is the percentage increase of a metric that's reported as a
+	// regression.
+	Threshold float64
+}
+
+// Run generates the code of the benchmarks with the templ generator of each
+// revision, runs the benchmarks, and compares the results. An error is
+// returned if any benchmark has regressed by more than the threshold.
+func Run(ctx context.Context, w io.Writer, args Arguments) (err error) {
+	root, err := command(ctx, ".", "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("failed to find the root of the git repository: %w", err)
+	}
+	root = strings.TrimSpace(root)
+
+	fmt.Fprintf(w, "Running benchmarks at %s...\n", args.Base)
+	base, err := benchmarkRevision(ctx, root, args.Base, args)
+	if err != nil {
+		return err
+	}
+	head := args.Head
+	if head == "" {
+		head = "the working tree"
+	}
+	fmt.Fprintf(w, "Running benchmarks at %s...\n", head)
+	headResults, err := benchmarkRevision(ctx, root, args.Head, args)
+	if err != nil {
+		return err
+	}
+
+	comparisons := Compare(base, headResults)
+	if len(comparisons) == 0 {
+		return fmt.Errorf("no benchmarks were found at both revisions")
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tBASE NS/OP\tHEAD NS/OP\tDELTA\tBASE ALLOCS/OP\tHEAD ALLOCS/OP\tDELTA")
+	for _, c := range comparisons {
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%+.1f%%\t%.0f\t%.0f\t%+.1f%%\n", c.Name,
+			c.Base.NsPerOp, c.Head.NsPerOp, delta(c.Base.NsPerOp, c.Head.NsPerOp),
+			c.Base.AllocsPerOp, c.Head.AllocsPerOp, delta(c.Base.AllocsPerOp, c.Head.AllocsPerOp))
+	}
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	var regressions int
+	for _, c := range comparisons {
+		if metrics := c.Regressions(args.Threshold); len(metrics) > 0 {
+			color.New(color.FgRed).Fprint(w, "(✗) ")
+			fmt.Fprintf(w, "%s: %s increased by more than %g%%\n", c.Name, strings.Join(metrics, ", "), args.Threshold)
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d benchmark(s) regressed", regressions)
+	}
+	return nil
+}
+
+// benchmarkRevision checks out the revision in a temporary git worktree, and
+// returns the results of its benchmarks. If the revision is empty, the
+// benchmarks of the working tree are run.
+func benchmarkRevision(ctx context.Context, root, revision string, args Arguments) (results map[string]Result, err error) {
+	dir := root
+	if revision != "" {
+		if dir, err = os.MkdirTemp("", "templ-benchdiff-"); err != nil {
+			return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		if _, err = command(ctx, root, "git", "worktree", "add", "--detach", dir, revision); err != nil {
+			return nil, fmt.Errorf("failed to check out %s: %w", revision, err)
+		}
+		defer func() {
+			_, _ = command(context.Background(), root, "git", "worktree", "remove", "--force", dir)
+		}()
+	}
+	// Generate the code with the templ generator of the revision.
+	if _, err = command(ctx, dir, "go", "run", "./cmd/templ", "generate", "-path", args.Path, "-include-version=false"); err != nil {
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+	pkgs := "./" + filepath.ToSlash(filepath.Clean(args.Path)) + "/..."
+	output, err := command(ctx, dir, "go", "test", "-run", "^$", "-bench", args.Bench, "-benchmem", "-count", strconv.Itoa(args.Count), pkgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run benchmarks: %w", err)
+	}
+	return ParseResults(strings.NewReader(output))
+}
+
+func command(ctx context.Context, dir, name string, args ...string) (stdout string, err error) {
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,90 @@
+package fmtcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	parser "github.com/a-h/templ/parser/v2"
+)
+
+// configFileName is the name of the file that configures templ fmt for a project.
+// It's read from the directory being formatted, or the closest parent directory.
+const configFileName = "templ-fmt.json"
+
+type config struct {
+	// VoidElements is "self-closing" (<br/>, the default), or "html" (<br>).
+	VoidElements string `json:"voidElements"`
+	// SelfClosingElements is "keep" (<div/>, the default), or "expand" (<div></div>).
+	SelfClosingElements string `json:"selfClosingElements"`
+	// OptionalEndTags is "required" (the default), or "close", which allows the
+	// end tags of elements such as <li> to be left out, and adds them.
+	OptionalEndTags string `json:"optionalEndTags"`
+}
+
+// Options are the parsing and formatting options of a project.
+type Options struct {
+	Parse  parser.ParseOptions
+	Format parser.FormatOptions
+}
+
+func (c config) options() (opts Options, err error) {
+	switch c.VoidElements {
+	case "", "self-closing":
+	case "html":
+		opts.Format.OmitVoidElementSlash = true
+	default:
+		return opts, fmt.Errorf("invalid voidElements value %q, expected \"self-closing\" or \"html\"", c.VoidElements)
+	}
+	switch c.SelfClosingElements {
+	case "", "keep":
+	case "expand":
+		opts.Format.ExpandSelfClosingElements = true
+	default:
+		return opts, fmt.Errorf("invalid selfClosingElements value %q, expected \"keep\" or \"expand\"", c.SelfClosingElements)
+	}
+	switch c.OptionalEndTags {
+	case "", "required":
+	case "close":
+		opts.Parse.OptionalEndTags = true
+	default:
+		return opts, fmt.Errorf("invalid optionalEndTags value %q, expected \"required\" or \"close\"", c.OptionalEndTags)
+	}
+	return opts, nil
+}
+
+// LoadOptions reads the parsing and formatting options from the config file in
+// dir, or its closest parent directory. If there's no config file, the default
+// options are returned.
+func LoadOptions(dir string) (opts Options, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return opts, fmt.Errorf("failed to get absolute path of %q: %w", dir, err)
+	}
+	if fi, err := os.Stat(dir); err == nil && !fi.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		fileName := filepath.Join(dir, configFileName)
+		data, err := os.ReadFile(fileName)
+		if err == nil {
+			var c config
+			if err = json.Unmarshal(data, &c); err != nil {
+				return opts, fmt.Errorf("failed to parse %q: %w", fileName, err)
+			}
+			if opts, err = c.options(); err != nil {
+				return opts, fmt.Errorf("%s: %w", fileName, err)
+			}
+			return opts, nil
+		}
+		if !os.IsNotExist(err) {
+			return opts, fmt.Errorf("failed to read %q: %w", fileName, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return opts, nil
+		}
+		dir = parent
+	}
+}
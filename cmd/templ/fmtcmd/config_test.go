@@ -0,0 +1,59 @@
+package fmtcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	parser "github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadFormatOptions(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "components", "forms")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	t.Run("without a config file, the defaults are used", func(t *testing.T) {
+		opts, err := LoadOptions(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(Options{}, opts); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the config file is read from a parent directory", func(t *testing.T) {
+		config := `{ "voidElements": "html", "selfClosingElements": "expand", "optionalEndTags": "close" }`
+		if err := os.WriteFile(filepath.Join(root, configFileName), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		opts, err := LoadOptions(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := Options{
+			Parse: parser.ParseOptions{
+				OptionalEndTags: true,
+			},
+			Format: parser.FormatOptions{
+				OmitVoidElementSlash:      true,
+				ExpandSelfClosingElements: true,
+			},
+		}
+		if diff := cmp.Diff(expected, opts); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		config := `{ "voidElements": "xhtml" }`
+		if err := os.WriteFile(filepath.Join(dir, configFileName), []byte(config), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadOptions(dir); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
@@ -2,31 +2,57 @@ package fmtcmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"time"
 
+	"github.com/a-h/templ/cmd/templ/gitignore"
 	"github.com/a-h/templ/cmd/templ/processor"
 	"github.com/a-h/templ/cmd/templ/sloghandler"
+	"github.com/a-h/templ/imports"
 	parser "github.com/a-h/templ/parser/v2"
 	"github.com/natefinch/atomic"
 )
 
 type Arguments struct {
-	ToStdout    bool
-	Files       []string
+	ToStdout bool
+	// Files are the templ files, directories and patterns of templ files to
+	// format, see processor.ExpandFiles.
+	Files []string
+	// FilesFrom is a file that lists more files to format, one per line, or "-"
+	// to read the list from stdin.
+	FilesFrom   string
 	LogLevel    string
 	WorkerCount int
+	// GitIgnore skips directories that are ignored by git.
+	GitIgnore bool
+	// Imports adds the imports of packages that are used by the templates, and
+	// removes the imports that aren't used.
+	Imports bool
 }
 
 func Run(w io.Writer, args Arguments) (err error) {
 	// If no files are provided, read from stdin and write to stdout.
-	if len(args.Files) == 0 {
-		return format(writeToStdout, readFromStdin)
+	if len(args.Files) == 0 && args.FilesFrom == "" {
+		opts, err := LoadOptions(".")
+		if err != nil {
+			return err
+		}
+		return format(writeToStdout, readFromStdin, opts, args.Imports)
+	}
+	files := args.Files
+	if args.FilesFrom != "" {
+		list, err := processor.ReadFileListFrom(args.FilesFrom)
+		if err != nil {
+			return err
+		}
+		files = append(files[:len(files):len(files)], list...)
 	}
 
 	level := slog.LevelInfo.Level()
@@ -45,16 +71,59 @@ func Run(w io.Writer, args Arguments) (err error) {
 	if args.ToStdout {
 		log = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	}
+	var options formatOptions
 	process := func(fileName string) error {
+		opts, err := options.get(fileName)
+		if err != nil {
+			return err
+		}
 		read := readFromFile(fileName)
 		write := writeToFile
 		if args.ToStdout {
 			write = writeToStdout
 		}
-		return format(write, read)
+		return format(write, read, opts, args.Imports)
+	}
+	// A single directory is formatted as its files are found.
+	if len(files) == 1 {
+		if fi, err := os.Stat(files[0]); err == nil && fi.IsDir() {
+			f := NewFormatter(log, files[0], process, args.WorkerCount)
+			if args.GitIgnore {
+				f.Ignore = gitignore.New(files[0])
+			}
+			return f.Run()
+		}
+	}
+	fileNames, err := processor.ExpandFiles(files, args.GitIgnore)
+	if err != nil {
+		return err
 	}
-	dir := args.Files[0]
-	return NewFormatter(log, dir, process, args.WorkerCount).Run()
+	f := NewFormatter(log, ".", process, args.WorkerCount)
+	f.Files = fileNames
+	return f.Run()
+}
+
+// formatOptions loads the options of each directory once.
+type formatOptions struct {
+	m    sync.Mutex
+	dirs map[string]Options
+}
+
+func (fo *formatOptions) get(fileName string) (opts Options, err error) {
+	dir := filepath.Dir(fileName)
+	fo.m.Lock()
+	defer fo.m.Unlock()
+	if opts, ok := fo.dirs[dir]; ok {
+		return opts, nil
+	}
+	if opts, err = LoadOptions(dir); err != nil {
+		return opts, err
+	}
+	if fo.dirs == nil {
+		fo.dirs = make(map[string]Options)
+	}
+	fo.dirs[dir] = opts
+	return opts, nil
 }
 
 type Formatter struct {
@@ -62,6 +131,11 @@ type Formatter struct {
 	Dir         string
 	Process     func(fileName string) error
 	WorkerCount int
+	// Ignore skips the directories that are ignored by git. If nil, no
+	// directories are ignored.
+	Ignore *gitignore.Matcher
+	// Files are formatted instead of the templ files in Dir, if set.
+	Files []string
 }
 
 func NewFormatter(log *slog.Logger, dir string, process func(fileName string) error, workerCount int) *Formatter {
@@ -81,7 +155,18 @@ func (f *Formatter) Run() (err error) {
 	start := time.Now()
 	results := make(chan processor.Result)
 	f.Log.Debug("Walking directory", slog.String("path", f.Dir))
-	go processor.Process(f.Dir, f.Process, f.WorkerCount, results)
+	if f.Files != nil {
+		templates := make(chan string)
+		go func() {
+			defer close(templates)
+			for _, fileName := range f.Files {
+				templates <- fileName
+			}
+		}()
+		go processor.ProcessChannel(templates, f.Dir, f.Process, f.WorkerCount, results)
+	} else {
+		go processor.Process(f.Dir, f.Ignore, f.Process, f.WorkerCount, results)
+	}
 	var successCount, errorCount int
 	for r := range results {
 		if r.Error != nil {
@@ -134,17 +219,22 @@ func writeToFile(fileName, tgt string) error {
 	return atomic.WriteFile(fileName, bytes.NewBufferString(tgt))
 }
 
-func format(write writer, read reader) (err error) {
+func format(write writer, read reader, opts Options, updateImports bool) (err error) {
 	fileName, src, err := read()
 	if err != nil {
 		return err
 	}
-	t, err := parser.ParseString(src)
+	t, err := parser.ParseStringWithOptions(src, opts.Parse)
 	if err != nil {
-		return err
+		return errors.New(parser.FormatError(src, err))
+	}
+	if updateImports {
+		if t, err = imports.Process(fileName, t); err != nil {
+			return fmt.Errorf("failed to update imports: %w", err)
+		}
 	}
 	w := new(bytes.Buffer)
-	if err = t.Write(w); err != nil {
+	if err = t.WriteWithOptions(w, opts.Format); err != nil {
 		return fmt.Errorf("formatting error: %w", err)
 	}
 	return write(fileName, w.String())
@@ -0,0 +1,152 @@
+package generatecmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// classesFile collects the literal class names used in templates, and writes them
+// to a file, so that CSS frameworks that scan source files for class names, such
+// as Tailwind, can find the classes that are only used in templates.
+type classesFile struct {
+	m        sync.Mutex
+	fileName string
+	// fileToClasses maps the absolute paths of template files to the classes
+	// they use.
+	fileToClasses map[string][]string
+}
+
+// EnableClassesFile writes the literal class names used in templates to fileName.
+// The classes of templFileNames are read, so that they're kept when only some of
+// the templates are generated. Templates that fail to parse are skipped.
+func (h *FSEventHandler) EnableClassesFile(fileName string, templFileNames []string) {
+	h.classes = &classesFile{
+		fileName:      fileName,
+		fileToClasses: make(map[string][]string),
+	}
+	for _, templFileName := range templFileNames {
+		t, err := parser.Parse(templFileName)
+		if err != nil {
+			continue
+		}
+		h.classes.fileToClasses[absPath(templFileName)] = literalClasses(t)
+	}
+}
+
+// absPath returns the absolute path of the file, so that the classes of a file
+// have the same key however its path was given.
+func absPath(fileName string) string {
+	if abs, err := filepath.Abs(fileName); err == nil {
+		return abs
+	}
+	return fileName
+}
+
+// writeClasses updates the classes used by a template, and writes the classes
+// file if it has changed.
+func (h *FSEventHandler) writeClasses(templFileName string, t parser.TemplateFile) (updated bool, err error) {
+	h.classes.m.Lock()
+	defer h.classes.m.Unlock()
+	h.classes.fileToClasses[absPath(templFileName)] = literalClasses(t)
+
+	unique := make(map[string]struct{})
+	for _, classes := range h.classes.fileToClasses {
+		for _, class := range classes {
+			unique[class] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(unique))
+	for class := range unique {
+		sorted = append(sorted, class)
+	}
+	sort.Strings(sorted)
+	var b bytes.Buffer
+	for _, class := range sorted {
+		b.WriteString(class)
+		b.WriteString("\n")
+	}
+
+	if !h.UpsertHash(h.classes.fileName, sha256.Sum256(b.Bytes())) {
+		return false, nil
+	}
+	if err = h.fileWriter(h.classes.fileName, b.Bytes()); err != nil {
+		return false, fmt.Errorf("failed to write classes file %q: %w", h.classes.fileName, err)
+	}
+	return true, nil
+}
+
+// literalClasses returns the class names in the constant class attributes of the
+// template, and the string literals in its class expressions, e.g. "a" and "b" in
+// class={ "a", templ.KV("b", isB) }.
+func literalClasses(t parser.TemplateFile) (classes []string) {
+	for _, n := range t.Nodes {
+		if ht, ok := n.(parser.HTMLTemplate); ok {
+			classes = appendNodeClasses(classes, ht.Children)
+		}
+	}
+	return classes
+}
+
+func appendNodeClasses(classes []string, nodes []parser.Node) []string {
+	for _, n := range nodes {
+		if e, ok := n.(parser.Element); ok {
+			classes = appendAttributeClasses(classes, e.Attributes)
+		}
+		if cn, ok := n.(parser.CompositeNode); ok {
+			classes = appendNodeClasses(classes, cn.ChildNodes())
+		}
+	}
+	return classes
+}
+
+func appendAttributeClasses(classes []string, attrs []parser.Attribute) []string {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case parser.ConstantAttribute:
+			if strings.EqualFold(attr.Name, "class") {
+				classes = append(classes, strings.Fields(attr.Value)...)
+			}
+		case parser.ExpressionAttribute:
+			if strings.EqualFold(attr.Name, "class") {
+				classes = append(classes, stringLiteralFields(attr.Expression.Value)...)
+			}
+		case parser.ConditionalAttribute:
+			classes = appendAttributeClasses(classes, attr.Then)
+			classes = appendAttributeClasses(classes, attr.Else)
+		}
+	}
+	return classes
+}
+
+// stringLiteralFields returns the whitespace separated fields of the string
+// literals in the Go expression.
+func stringLiteralFields(expr string) (fields []string) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("", fset.Base(), len(expr))
+	var s scanner.Scanner
+	s.Init(f, []byte(expr), nil, 0)
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return fields
+		}
+		if tok != token.STRING {
+			continue
+		}
+		value, err := strconv.Unquote(lit)
+		if err != nil {
+			continue
+		}
+		fields = append(fields, strings.Fields(value)...)
+	}
+}
@@ -0,0 +1,68 @@
+package generatecmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLiteralClasses(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Button(primary bool) {
+	<button class="px-4 py-2" if primary { class="bg-blue-500" } else { class="bg-gray-500" }>
+		for _, item := range items {
+			<span class={ "text-sm", templ.KV("font-bold", primary), ` + "`italic underline`" + ` }>{ item }</span>
+		}
+		<div data-class="not-a-class"></div>
+	</button>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := []string{"px-4", "py-2", "bg-blue-500", "bg-gray-500", "text-sm", "font-bold", "italic", "underline"}
+	if diff := cmp.Diff(expected, literalClasses(tf)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClassesFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, class string) string {
+		t.Helper()
+		fileName := filepath.Join(dir, name)
+		if err := os.WriteFile(fileName, []byte("package main\n\ntempl Page() {\n\t<div class=\""+class+"\"></div>\n}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return fileName
+	}
+	homeFileName := write("home.templ", "text-sm old")
+	write("about.templ", "text-lg")
+	classesFileName := filepath.Join(dir, "classes.txt")
+
+	// When a single file is generated, the classes of the other files are read
+	// from the templates, so the classes that a file no longer uses are removed.
+	h := NewFSEventHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), dir, false, nil, false, false, false)
+	h.EnableClassesFile(classesFileName, []string{homeFileName, filepath.Join(dir, "about.templ")})
+	write("home.templ", "text-sm new")
+	tf, err := parser.Parse(homeFileName)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	if _, err = h.writeClasses(homeFileName, tf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual, err := os.ReadFile(classesFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("new\ntext-lg\ntext-sm\n", string(actual)); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -2,15 +2,22 @@ package generatecmd
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"go/build/constraint"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,6 +31,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/cli/browser"
 	"github.com/fsnotify/fsnotify"
+	"github.com/skip2/go-qrcode"
 )
 
 func NewGenerate(log *slog.Logger, args Arguments) (g *Generate) {
@@ -40,6 +48,17 @@ func NewGenerate(log *slog.Logger, args Arguments) (g *Generate) {
 type Generate struct {
 	Log  *slog.Logger
 	Args *Arguments
+	// Out is written to with output that isn't logged, e.g. QR codes. If nil,
+	// os.Stdout is used.
+	Out io.Writer
+}
+
+// out returns the writer for output that isn't logged.
+func (cmd Generate) out() io.Writer {
+	if cmd.Out == nil {
+		return os.Stdout
+	}
+	return cmd.Out
 }
 
 type GenerationEvent struct {
@@ -50,11 +69,29 @@ type GenerationEvent struct {
 
 func (cmd Generate) Run(ctx context.Context) (err error) {
 	if cmd.Args.NotifyProxy {
-		return proxy.NotifyProxy(cmd.Args.ProxyBind, cmd.Args.ProxyPort)
+		if cmd.Args.ProxyBind == "" {
+			cmd.Args.ProxyBind = "127.0.0.1"
+		}
+		return proxy.NotifyProxyWithToken(cmd.Args.ProxyBind, cmd.Args.ProxyPort, cmd.Args.NotifyToken)
 	}
 	if cmd.Args.Watch && cmd.Args.FileName != "" {
 		return fmt.Errorf("cannot watch a single file, remove the -f or -watch flag")
 	}
+	fileList := len(cmd.Args.Files) > 0 || cmd.Args.FilesFrom != ""
+	if fileList && (cmd.Args.Watch || cmd.Args.FileName != "") {
+		return fmt.Errorf("a list of files can't be combined with the -watch or -f flags")
+	}
+	switch cmd.Args.WatchStrategy {
+	case "", WatchStrategyFSNotify, WatchStrategyPoll:
+	default:
+		return fmt.Errorf("unknown watch strategy %q, use %q or %q", cmd.Args.WatchStrategy, WatchStrategyFSNotify, WatchStrategyPoll)
+	}
+	if cmd.Args.Diff {
+		cmd.Args.DryRun = true
+	}
+	if cmd.Args.DryRun && (cmd.Args.Watch || cmd.Args.ToStdout) {
+		return fmt.Errorf("a dry run can't be combined with the -watch or -stdout flags")
+	}
 	if cmd.Args.FileName == "" && cmd.Args.ToStdout {
 		return fmt.Errorf("only a single file can be output to stdout, add the -f flag to specify the file to generate code for")
 	}
@@ -64,6 +101,33 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 		}()
 	}
 
+	if cmd.Args.PIDFile != "" {
+		if err = writePIDFile(cmd.Args.PIDFile); err != nil {
+			return err
+		}
+		defer func() {
+			if err := removePIDFile(cmd.Args.PIDFile); err != nil {
+				cmd.Log.Error("Failed to remove pid file", slog.Any("error", err))
+			}
+		}()
+	}
+
+	// Stop watching if the parent process exits, so that the command isn't orphaned
+	// when the terminal, or the task runner that started templ, is closed.
+	if cmd.Args.Watch {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-run.ParentExited(ctx):
+				cmd.Log.Info("Parent process exited, stopping")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
 	// Use absolute path.
 	if !path.IsAbs(cmd.Args.Path) {
 		cmd.Args.Path, err = filepath.Abs(cmd.Args.Path)
@@ -80,16 +144,75 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	if cmd.Args.IncludeTimestamp {
 		opts = append(opts, generator.WithTimestamp(time.Now()))
 	}
+	if cmd.Args.Instrument {
+		opts = append(opts, generator.WithInstrumentation())
+	}
+	if cmd.Args.StrictHTML {
+		opts = append(opts, generator.WithStrictHTML())
+	}
+	if cmd.Args.PermissiveStringExpressions {
+		opts = append(opts, generator.WithPermissiveStringExpressions())
+	}
+	if cmd.Args.Minify {
+		opts = append(opts, generator.WithMinify())
+	}
+	if cmd.Args.Behaviors {
+		opts = append(opts, generator.WithBehaviors())
+	}
+	var header string
+	if cmd.Args.HeaderFile != "" {
+		b, err := os.ReadFile(cmd.Args.HeaderFile)
+		if err != nil {
+			return fmt.Errorf("failed to read header file: %w", err)
+		}
+		header = string(b)
+		opts = append(opts, generator.WithHeader(header))
+	}
+	if cmd.Args.BuildConstraint != "" {
+		if _, err := constraint.Parse("//go:build " + strings.TrimPrefix(cmd.Args.BuildConstraint, "//go:build ")); err != nil {
+			return fmt.Errorf("invalid build constraint %q: %w", cmd.Args.BuildConstraint, err)
+		}
+		opts = append(opts, generator.WithBuildConstraint(cmd.Args.BuildConstraint))
+	}
 
 	if cmd.Args.ToStdout {
 		cmd.Log = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 	}
 
-	// Check the version of the templ module.
-	if err := modcheck.Check(cmd.Args.Path); err != nil {
-		cmd.Log.Warn("templ version check: " + err.Error())
+	// In a go.work workspace, the modules of the workspace are generated and watched,
+	// including modules outside of the path.
+	modules, err := modcheck.WorkspaceModules(cmd.Args.Path)
+	if err != nil {
+		return err
+	}
+	if len(modules) > 0 {
+		cmd.Log.Info("Using go.work workspace", slog.Any("modules", modules))
+	} else {
+		modules = []string{cmd.Args.Path}
+	}
+	roots, err := modcheck.WatchRoots(cmd.Args.Path)
+	if err != nil {
+		return err
+	}
+	filter := cmd.Args.walkFilter()
+	var fileNames []string
+	if fileList {
+		if fileNames, err = cmd.Args.expandFiles(); err != nil {
+			return err
+		}
+	}
+
+	// Check the version of the templ module of each Go module.
+	if cmd.Args.RuntimeVersion == "" {
+		for _, m := range modules {
+			if err := modcheck.Check(m); err != nil {
+				cmd.Log.Warn("templ version check: "+err.Error(), slog.String("module", m))
+			}
+		}
 	}
 
+	// The first error of each file, listed once generation has completed.
+	var failed failures
 	fseh := NewFSEventHandler(
 		cmd.Log,
 		cmd.Args.Path,
@@ -99,6 +222,46 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 		cmd.Args.KeepOrphanedFiles,
 		cmd.Args.ToStdout,
 	)
+	fseh.failures = &failed
+	var dr *dryRun
+	if cmd.Args.DryRun {
+		dr = newDryRun(cmd.Args.Diff)
+		fseh.enableDryRun(dr)
+	}
+	if cmd.Args.StaticChunks {
+		// When some of the files are generated, keep the chunks of the other files in their directories.
+		fseh.EnableStaticChunks(cmd.Args.FileName != "" || fileList)
+		fseh.SetStaticChunksHeader(header, cmd.Args.BuildConstraint)
+	}
+	if cmd.Args.TailwindClassesFile != "" {
+		// When some of the files are generated, keep the classes of the other files.
+		var templFileNames []string
+		if cmd.Args.FileName != "" || fileList {
+			if templFileNames, err = walkTemplFiles(roots, filter); err != nil {
+				return err
+			}
+		}
+		fseh.EnableClassesFile(cmd.Args.TailwindClassesFile, templFileNames)
+	}
+	if cmd.Args.IncludeVersion {
+		fseh.EnableSourceHash()
+	}
+	fseh.EnableRuntimeCheck(cmd.Args.RuntimeVersion)
+	if cmd.Args.HostPath != "" {
+		fseh.EnableHostPaths(cmd.Args.HostPath)
+	}
+	if cmd.Args.OutputDir != "" {
+		if err = fseh.EnableOutputDir(cmd.Args.OutputDir); err != nil {
+			return err
+		}
+		roots = appendOutputDirRoot(roots, fseh.outputDir)
+	}
+	if cmd.Args.ScriptTypes {
+		fseh.EnableScriptTypes()
+	}
+	if cmd.Args.ESBuildCommand != "" {
+		fseh.EnableScriptBundling(cmd.Args.ESBuildCommand)
+	}
 
 	// If we're processing a single file, don't bother setting up the channels/multithreaing.
 	if cmd.Args.FileName != "" {
@@ -106,7 +269,16 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			Name: cmd.Args.FileName,
 			Op:   fsnotify.Create,
 		})
-		return err
+		if err != nil || dr == nil {
+			return err
+		}
+		if err := dr.writeSummary(cmd.out()); err != nil {
+			cmd.Log.Warn("Failed to write dry run summary", slog.Any("error", err))
+		}
+		if dr.len() > 0 {
+			return fmt.Errorf("%d generated files would change, run templ generate to update them", dr.len())
+		}
+		return nil
 	}
 
 	// Start timer.
@@ -123,6 +295,8 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	errs := make(chan error)
 	// Tracks whether errors occurred during the generation process.
 	var errorCount atomic.Int64
+	// Set when generation stops at the first error.
+	var stopped atomic.Bool
 	// For triggering actions after generation has completed.
 	postGeneration := make(chan *GenerationEvent, 256)
 	// Used to check that the post-generation handler has completed.
@@ -142,7 +316,11 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			slog.String("path", cmd.Args.Path),
 			slog.Bool("devMode", cmd.Args.Watch),
 		)
-		if err := watcher.WalkFiles(ctx, cmd.Args.Path, events); err != nil {
+		if fileList {
+			sendFiles(ctx, fileNames, events)
+			return
+		}
+		if err := walkRoots(ctx, roots, filter, events); err != nil {
 			cmd.Log.Error("WalkFiles failed, exiting", slog.Any("error", err))
 			errs <- FatalError{Err: fmt.Errorf("failed to walk files: %w", err)}
 			return
@@ -151,8 +329,27 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			cmd.Log.Debug("Dev mode not enabled, process can finish early")
 			return
 		}
-		cmd.Log.Info("Watching files")
-		rw, err := watcher.Recursive(ctx, cmd.Args.Path, events, errs)
+		cmd.Log.Info("Watching files", slog.String("strategy", cmd.Args.watchStrategy()))
+		var rw watcher.Watcher
+		if cmd.Args.watchStrategy() == WatchStrategyPoll {
+			rw, err = watcher.Poll(ctx, roots[0], filter, cmd.Args.pollInterval(), cmd.Args.PollHash, events, errs)
+		} else {
+			var frw *watcher.RecursiveWatcher
+			if frw, err = watcher.Recursive(ctx, roots[0], filter, events, errs); err == nil {
+				rw = frw
+				if cmd.Args.DetectMissedEvents {
+					go frw.MissedEvents(missedEventsInterval, func(name string) {
+						cmd.Log.Warn("File system events are not being received, use -watch-strategy poll to poll for changes instead", slog.String("file", name))
+					})
+				}
+			}
+		}
+		for _, root := range roots[1:] {
+			if err != nil {
+				break
+			}
+			err = rw.Add(root)
+		}
 		if err != nil {
 			cmd.Log.Error("Recursive watcher setup failed, exiting", slog.Any("error", err))
 			errs <- FatalError{Err: fmt.Errorf("failed to setup recursive watcher: %w", err)}
@@ -184,8 +381,33 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			cmd.Args.KeepOrphanedFiles,
 			cmd.Args.ToStdout,
 		)
+		fseh.failures = &failed
+		if cmd.Args.StaticChunks {
+			fseh.EnableStaticChunks(false)
+			fseh.SetStaticChunksHeader(header, cmd.Args.BuildConstraint)
+		}
+		if cmd.Args.TailwindClassesFile != "" {
+			fseh.EnableClassesFile(cmd.Args.TailwindClassesFile, nil)
+		}
+		if cmd.Args.IncludeVersion {
+			fseh.EnableSourceHash()
+		}
+		fseh.EnableRuntimeCheck(cmd.Args.RuntimeVersion)
+		if cmd.Args.HostPath != "" {
+			fseh.EnableHostPaths(cmd.Args.HostPath)
+		}
+		if cmd.Args.OutputDir != "" {
+			_ = fseh.EnableOutputDir(cmd.Args.OutputDir)
+		}
+		if cmd.Args.ScriptTypes {
+			fseh.EnableScriptTypes()
+		}
+		if cmd.Args.ESBuildCommand != "" {
+			fseh.EnableScriptBundling(cmd.Args.ESBuildCommand)
+		}
 		errorCount.Store(0)
-		if err := watcher.WalkFiles(ctx, cmd.Args.Path, events); err != nil {
+		failed.reset()
+		if err := walkRoots(ctx, roots, filter, events); err != nil {
 			cmd.Log.Error("Post dev mode WalkFiles failed", slog.Any("error", err))
 			errs <- FatalError{Err: fmt.Errorf("failed to walk files: %w", err)}
 			return
@@ -200,6 +422,10 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 		defer close(postGeneration)
 		cmd.Log.Debug("Starting event handler")
 		for event := range events {
+			if stopped.Load() {
+				// Skip the rest of the files, but keep reading events so that the walk completes.
+				continue
+			}
 			eventsWG.Add(1)
 			sem <- struct{}{}
 			go func(event fsnotify.Event) {
@@ -209,6 +435,9 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 				goUpdated, textUpdated, err := fseh.HandleEvent(ctx, event)
 				if err != nil {
 					cmd.Log.Error("Event handler failed", slog.Any("error", err))
+					if !cmd.Args.KeepGoing && !cmd.Args.Watch && stopped.CompareAndSwap(false, true) {
+						cmd.Log.Info("Stopping at the first error, use -keep-going to generate the remaining files")
+					}
 					errs <- err
 				}
 				if goUpdated || textUpdated {
@@ -259,12 +488,18 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 					break
 				}
 				postGenerationEventsWG.Add(1)
-				if cmd.Args.Command != "" && goUpdated {
+				if cmd.Args.Command != "" && goUpdated && !cmd.Args.DryRun {
 					cmd.Log.Debug("Executing command", slog.String("command", cmd.Args.Command))
 					if _, err := run.Run(ctx, cmd.Args.Path, cmd.Args.Command); err != nil {
 						cmd.Log.Error("Error executing command", slog.Any("error", err))
 					}
 				}
+				if cmd.Args.TailwindCommand != "" && fseh.ClassesUpdated.Swap(false) {
+					cmd.Log.Debug("Executing Tailwind command", slog.String("command", cmd.Args.TailwindCommand))
+					if _, err := run.Run(ctx, cmd.Args.Path, cmd.Args.TailwindCommand); err != nil {
+						cmd.Log.Error("Error executing Tailwind command", slog.Any("error", err))
+					}
+				}
 				if !firstPostGenerationExecuted {
 					cmd.Log.Debug("First post-generation event received, starting proxy")
 					firstPostGenerationExecuted = true
@@ -276,7 +511,7 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 				// Send server-sent event.
 				if p != nil && (textUpdated || goUpdated) {
 					cmd.Log.Debug("Sending reload event")
-					p.SendSSE("message", "reload")
+					p.Reload()
 				}
 				postGenerationEventsWG.Done()
 				// Reset timer.
@@ -315,9 +550,20 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	}
 
 	// Check for errors after everything has completed.
+	if err := failed.writeSummary(cmd.out()); err != nil {
+		cmd.Log.Warn("Failed to write error summary", slog.Any("error", err))
+	}
+	if dr != nil {
+		if err := dr.writeSummary(cmd.out()); err != nil {
+			cmd.Log.Warn("Failed to write dry run summary", slog.Any("error", err))
+		}
+	}
 	if errorCount.Load() > 0 {
 		return fmt.Errorf("generation completed with %d errors", errorCount.Load())
 	}
+	if dr != nil && dr.len() > 0 {
+		return fmt.Errorf("%d generated files would change, run templ generate to update them", dr.len())
+	}
 
 	cmd.Log.Info(
 		"Complete",
@@ -328,53 +574,273 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 }
 
 func (cmd *Generate) StartProxy(ctx context.Context) (p *proxy.Handler, err error) {
-	if cmd.Args.Proxy == "" {
-		cmd.Log.Debug("No proxy URL specified, not starting proxy")
+	if cmd.Args.Proxy == "" && cmd.Args.StaticDir == "" {
+		cmd.Log.Debug("No proxy URL or static directory specified, not starting proxy")
 		return nil, nil
 	}
 	var target *url.URL
-	target, err = url.Parse(cmd.Args.Proxy)
-	if err != nil {
-		return nil, FatalError{Err: fmt.Errorf("failed to parse proxy URL: %w", err)}
+	if cmd.Args.Proxy != "" {
+		target, err = url.Parse(cmd.Args.Proxy)
+		if err != nil {
+			return nil, FatalError{Err: fmt.Errorf("failed to parse proxy URL: %w", err)}
+		}
 	}
 	if cmd.Args.ProxyPort == 0 {
 		cmd.Args.ProxyPort = 7331
 	}
+	container := inContainer()
 	if cmd.Args.ProxyBind == "" {
 		cmd.Args.ProxyBind = "127.0.0.1"
+		if container {
+			// Connections from the host arrive on the network interface of the
+			// container, not the loopback interface.
+			cmd.Args.ProxyBind = "0.0.0.0"
+			cmd.Log.Info("Running in a container, listening on all interfaces", slog.String("proxybind", cmd.Args.ProxyBind))
+		}
+		if cmd.Args.QRCode {
+			// Phones connect to the proxy over the local network.
+			cmd.Args.ProxyBind = "0.0.0.0"
+			cmd.Log.Info("Printing a QR code, listening on all interfaces", slog.String("proxybind", cmd.Args.ProxyBind))
+		}
+	}
+	if cmd.Args.ProxyExternalURL != "" {
+		if _, err = url.Parse(cmd.Args.ProxyExternalURL); err != nil {
+			return nil, FatalError{Err: fmt.Errorf("failed to parse proxy external URL: %w", err)}
+		}
 	}
 	p = proxy.New(cmd.Args.ProxyBind, cmd.Args.ProxyPort, target)
+	p.ExternalURL = cmd.Args.ProxyExternalURL
+	p.Morph = cmd.Args.ProxyMorph
+	// A token generated for the notify listener isn't known to local callers, so
+	// only a token that has been set is required by the proxy.
+	p.Token = cmd.Args.NotifyToken
+	if p.Token == "" && !isLoopback(cmd.Args.ProxyBind) {
+		cmd.Log.Warn("Reload events can be sent by anything that can connect to the proxy, set --notify-token to require a token", slog.String("proxybind", cmd.Args.ProxyBind))
+	}
+	if cmd.Args.ProxyPingInterval > 0 {
+		p.SetPingInterval(cmd.Args.ProxyPingInterval)
+	}
+	if cmd.Args.StaticDir != "" {
+		p.StaticDir = cmd.Args.StaticDir
+		cmd.Log.Info("Serving static files", slog.String("dir", p.StaticDir))
+		go watchStaticDir(ctx, p.StaticDir, DefaultPollInterval, func(changed []string) {
+			cmd.Log.Debug("Static files changed, sending reload event", slog.Any("files", changed))
+			p.Reload(proxy.StaticURLs(changed)...)
+		})
+	}
+	for _, r := range cmd.Args.ProxyRoutes {
+		prefix, routeURL, ok := strings.Cut(r, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, FatalError{Err: fmt.Errorf("invalid proxy route %q, expected <prefix>=<url>, e.g. /api=http://localhost:4000", r)}
+		}
+		routeTarget, err := url.Parse(routeURL)
+		if err != nil || routeTarget.Host == "" {
+			return nil, FatalError{Err: fmt.Errorf("invalid proxy route %q, failed to parse URL %q", r, routeURL)}
+		}
+		p.AddRoute(prefix, routeTarget)
+		cmd.Log.Info("Proxying route", slog.String("prefix", prefix), slog.String("to", routeTarget.String()))
+	}
+	if cmd.Args.ProxyLog {
+		p.AccessLog = cmd.Log
+		for _, pattern := range strings.Split(cmd.Args.ProxyLogExclude, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				p.AccessLogExclude = append(p.AccessLogExclude, pattern)
+			}
+		}
+	}
+	browserURL := p.URL
+	if p.ExternalURL != "" {
+		browserURL = p.ExternalURL
+	}
 	go func() {
-		cmd.Log.Info("Proxying", slog.String("from", p.URL), slog.String("to", p.Target.String()))
+		if p.Target != nil {
+			cmd.Log.Info("Proxying", slog.String("from", browserURL), slog.String("to", p.Target.String()))
+		} else {
+			cmd.Log.Info("Serving", slog.String("from", browserURL))
+		}
 		if err := http.ListenAndServe(fmt.Sprintf("%s:%d", cmd.Args.ProxyBind, cmd.Args.ProxyPort), p); err != nil {
 			cmd.Log.Error("Proxy failed", slog.Any("error", err))
 		}
 	}()
-	if !cmd.Args.OpenBrowser {
-		cmd.Log.Debug("Not opening browser")
+	if cmd.Args.NotifyPort > 0 {
+		if err = cmd.startNotifyListener(p); err != nil {
+			return nil, err
+		}
+	}
+	openBrowser := cmd.Args.OpenBrowser && !container
+	if !openBrowser && !cmd.Args.QRCode {
+		cmd.Log.Debug("Not opening browser", slog.Bool("container", container))
 		return p, nil
 	}
 	go func() {
-		cmd.Log.Debug("Waiting for proxy to be ready", slog.String("url", p.URL))
-		backoff := backoff.NewExponentialBackOff()
-		backoff.InitialInterval = time.Second
-		var client http.Client
-		client.Timeout = 1 * time.Second
-		for {
-			if _, err := client.Get(p.URL); err == nil {
-				break
-			}
-			d := backoff.NextBackOff()
-			cmd.Log.Debug(
-				"Proxy not ready, retrying",
-				slog.String("url", p.URL),
-				slog.Any("backoff", d),
-			)
-			time.Sleep(d)
-		}
-		if err := browser.OpenURL(p.URL); err != nil {
+		if !cmd.waitForTarget(ctx, p.Target) {
+			return
+		}
+		if cmd.Args.QRCode {
+			cmd.printQRCode(p)
+		}
+		if !openBrowser {
+			return
+		}
+		if err := browser.OpenURL(browserURL); err != nil {
 			cmd.Log.Error("Failed to open browser", slog.Any("error", err))
 		}
 	}()
 	return p, nil
 }
+
+// waitForTarget waits until the target of the proxy responds without a server
+// error, so that the browser isn't opened at a page that fails to load while the
+// server starts. It returns false if the context is cancelled first. If there's
+// no target, because the proxy only serves static files, it returns immediately.
+func (cmd *Generate) waitForTarget(ctx context.Context, target *url.URL) bool {
+	if target == nil {
+		return true
+	}
+	cmd.Log.Debug("Waiting for proxy target to be ready", slog.String("url", target.String()))
+	backoff := backoff.NewExponentialBackOff()
+	backoff.InitialInterval = time.Second
+	backoff.MaxElapsedTime = 0
+	var client http.Client
+	client.Timeout = 1 * time.Second
+	for {
+		resp, err := client.Get(target.String())
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return true
+			}
+		}
+		d := backoff.NextBackOff()
+		cmd.Log.Debug(
+			"Proxy target not ready, retrying",
+			slog.String("url", target.String()),
+			slog.Any("backoff", d),
+		)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(d):
+		}
+	}
+}
+
+// printQRCode prints a QR code of the URL that other devices on the local network,
+// e.g. phones, can connect to the proxy with.
+func (cmd *Generate) printQRCode(p *proxy.Handler) {
+	u, err := lanURL(p.ExternalURL, cmd.Args.ProxyBind, cmd.Args.ProxyPort)
+	if err != nil {
+		cmd.Log.Warn("Failed to print QR code", slog.Any("error", err))
+		return
+	}
+	code, err := qrcode.New(u, qrcode.Medium)
+	if err != nil {
+		cmd.Log.Warn("Failed to print QR code", slog.String("url", u), slog.Any("error", err))
+		return
+	}
+	// Light modules are drawn with blocks, for terminals with a dark background.
+	fmt.Fprintf(cmd.out(), "\n%s\nScan to open %s\n\n", code.ToSmallString(false), u)
+}
+
+// isLoopback returns true if the bind address only accepts connections from this
+// machine.
+func isLoopback(bind string) bool {
+	if bind == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(bind)
+	return ip != nil && ip.IsLoopback()
+}
+
+// lanURL returns the URL of the proxy on the local network. If the proxy listens
+// on all interfaces, the first private IPv4 address of the machine is used.
+func lanURL(externalURL, bind string, port int) (string, error) {
+	if externalURL != "" {
+		return externalURL, nil
+	}
+	host := bind
+	ip := net.ParseIP(bind)
+	switch {
+	case bind == "localhost" || ip != nil && ip.IsLoopback():
+		return "", fmt.Errorf("the proxy only listens on %s, which other devices can't connect to, use -proxybind 0.0.0.0 to listen on all interfaces", bind)
+	case ip != nil && ip.IsUnspecified():
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return "", fmt.Errorf("failed to get network addresses: %w", err)
+		}
+		host = ""
+		for _, addr := range addrs {
+			if n, ok := addr.(*net.IPNet); ok && n.IP.To4() != nil && n.IP.IsPrivate() {
+				host = n.IP.String()
+				break
+			}
+		}
+		if host == "" {
+			return "", fmt.Errorf("no local network address found")
+		}
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// startNotifyListener starts a second listener that only accepts reload events
+// with the notify token, so that a remote build machine can trigger reloads
+// through a forwarded port, without access to the proxy. If no token is set, a
+// random token is generated.
+func (cmd *Generate) startNotifyListener(p *proxy.Handler) error {
+	if cmd.Args.NotifyBind == "" {
+		cmd.Args.NotifyBind = cmd.Args.ProxyBind
+	}
+	if cmd.Args.NotifyToken == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return FatalError{Err: fmt.Errorf("failed to generate notify token: %w", err)}
+		}
+		cmd.Args.NotifyToken = hex.EncodeToString(b)
+		cmd.Log.Info("Generated notify token, pass it to templ generate --notify-proxy with --notify-token, or the TEMPL_NOTIFY_TOKEN environment variable", slog.String("token", cmd.Args.NotifyToken))
+	}
+	addr := net.JoinHostPort(cmd.Args.NotifyBind, strconv.Itoa(cmd.Args.NotifyPort))
+	go func() {
+		cmd.Log.Info("Listening for reload events", slog.String("address", addr))
+		if err := http.ListenAndServe(addr, p.NotifyHandler(cmd.Args.NotifyToken)); err != nil {
+			cmd.Log.Error("Notify listener failed", slog.Any("error", err))
+		}
+	}()
+	return nil
+}
+
+// sendFiles sends a Create event for each file, until the context is cancelled.
+func sendFiles(ctx context.Context, fileNames []string, out chan fsnotify.Event) {
+	for _, fileName := range fileNames {
+		select {
+		case <-ctx.Done():
+			return
+		case out <- fsnotify.Event{Name: fileName, Op: fsnotify.Create}:
+		}
+	}
+}
+
+// walkRoots walks the file tree of each root, sending a Create event for each file.
+func walkRoots(ctx context.Context, roots []string, filter watcher.Filter, out chan fsnotify.Event) error {
+	for _, root := range roots {
+		if err := watcher.WalkFiles(ctx, root, filter, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkTemplFiles returns the templ files in the file tree of each root.
+func walkTemplFiles(roots []string, filter watcher.Filter) (fileNames []string, err error) {
+	events := make(chan fsnotify.Event)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errs <- walkRoots(context.Background(), roots, filter, events)
+	}()
+	for event := range events {
+		if strings.HasSuffix(event.Name, ".templ") {
+			fileNames = append(fileNames, event.Name)
+		}
+	}
+	return fileNames, <-errs
+}
@@ -0,0 +1,95 @@
+package generatecmd
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/generatecmd/proxy"
+	"github.com/skip2/go-qrcode"
+)
+
+func TestLANURL(t *testing.T) {
+	t.Run("the external URL is used if set", func(t *testing.T) {
+		got, err := lanURL("https://dev.example.com", "127.0.0.1", 7331)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "https://dev.example.com" {
+			t.Errorf("expected the external URL, got %q", got)
+		}
+	})
+	t.Run("a specific bind address is used", func(t *testing.T) {
+		got, err := lanURL("", "192.168.1.2", 7331)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "http://192.168.1.2:7331" {
+			t.Errorf("expected http://192.168.1.2:7331, got %q", got)
+		}
+	})
+	t.Run("loopback addresses are an error", func(t *testing.T) {
+		for _, bind := range []string{"127.0.0.1", "::1", "localhost"} {
+			if _, err := lanURL("", bind, 7331); err == nil {
+				t.Errorf("%s: expected an error, got nil", bind)
+			}
+		}
+	})
+	t.Run("if the proxy listens on all interfaces, a private address is used", func(t *testing.T) {
+		got, err := lanURL("", "0.0.0.0", 7331)
+		if err != nil {
+			// The machine running the tests may not be connected to a network.
+			t.Skipf("no local network address: %v", err)
+		}
+		u, err := url.Parse(got)
+		if err != nil {
+			t.Fatalf("invalid URL %q: %v", got, err)
+		}
+		if ip := net.ParseIP(u.Hostname()); ip == nil || !ip.IsPrivate() || u.Port() != "7331" {
+			t.Errorf("expected a private address with port 7331, got %q", got)
+		}
+	})
+}
+
+func TestPrintQRCode(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	t.Run("the LAN URL is printed as a QR code", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd := &Generate{Log: log, Args: &Arguments{ProxyBind: "192.168.1.2", ProxyPort: 7331}, Out: out}
+		cmd.printQRCode(&proxy.Handler{})
+		code, err := qrcode.New("http://192.168.1.2:7331", qrcode.Medium)
+		if err != nil {
+			t.Fatalf("failed to create QR code: %v", err)
+		}
+		expected := "\n" + code.ToSmallString(false) + "\nScan to open http://192.168.1.2:7331\n\n"
+		if out.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+		}
+	})
+	t.Run("nothing is printed if other devices can't connect to the proxy", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd := &Generate{Log: log, Args: &Arguments{ProxyBind: "127.0.0.1", ProxyPort: 7331}, Out: out}
+		cmd.printQRCode(&proxy.Handler{})
+		if out.Len() != 0 {
+			t.Errorf("expected no output, got:\n%s", out.String())
+		}
+	})
+}
+
+func TestIsLoopback(t *testing.T) {
+	for bind, expected := range map[string]bool{
+		"127.0.0.1":   true,
+		"::1":         true,
+		"localhost":   true,
+		"0.0.0.0":     false,
+		"192.168.1.2": false,
+		"":            false,
+	} {
+		if actual := isLoopback(bind); actual != expected {
+			t.Errorf("%q: expected %v, got %v", bind, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package generatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inContainer returns true if templ is running in a Docker or Podman container.
+func inContainer() bool {
+	for _, name := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hostPaths maps the paths of files in a container to the paths of the same files
+// on the host, so that the file names in error messages can be opened by editors
+// running on the host.
+type hostPaths struct {
+	// dir is the directory in the container that is mounted from hostDir.
+	dir     string
+	hostDir string
+}
+
+// fileName returns the path of the file on the host. Files outside of the mounted
+// directory are returned unchanged.
+func (hp *hostPaths) fileName(name string) string {
+	if hp == nil {
+		return name
+	}
+	rel, err := filepath.Rel(hp.dir, name)
+	if err != nil || !filepath.IsLocal(rel) {
+		return name
+	}
+	// The host may be Windows, even though the container is Linux.
+	sep := "/"
+	if strings.Contains(hp.hostDir, `\`) {
+		sep = `\`
+	}
+	return strings.TrimRight(hp.hostDir, `/\`) + sep + strings.ReplaceAll(filepath.ToSlash(rel), "/", sep)
+}
+
+// text replaces the path of the file in s with the path on the host.
+func (hp *hostPaths) text(s, name string) string {
+	if hp == nil {
+		return s
+	}
+	return strings.ReplaceAll(s, name, hp.fileName(name))
+}
+
+// error replaces the path of the file in the message of err with the path on the
+// host.
+func (hp *hostPaths) error(err error, name string) error {
+	if hp == nil || err == nil {
+		return err
+	}
+	return hostPathError{err: err, name: name, hostName: hp.fileName(name)}
+}
+
+type hostPathError struct {
+	err            error
+	name, hostName string
+}
+
+func (e hostPathError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.name, e.hostName)
+}
+
+func (e hostPathError) Unwrap() error {
+	return e.err
+}
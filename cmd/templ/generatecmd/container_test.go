@@ -0,0 +1,60 @@
+package generatecmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHostPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostDir  string
+		fileName string
+		expected string
+	}{
+		{
+			name:     "files in the mounted directory are mapped to the host directory",
+			hostDir:  "/home/user/app",
+			fileName: "/app/components/button.templ",
+			expected: "/home/user/app/components/button.templ",
+		},
+		{
+			name:     "Windows host directories use backslashes",
+			hostDir:  `C:\Users\user\app\`,
+			fileName: "/app/components/button.templ",
+			expected: `C:\Users\user\app\components\button.templ`,
+		},
+		{
+			name:     "files outside of the mounted directory are unchanged",
+			hostDir:  "/home/user/app",
+			fileName: "/go/pkg/mod/button.templ",
+			expected: "/go/pkg/mod/button.templ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hp := &hostPaths{dir: "/app", hostDir: tt.hostDir}
+			if got := hp.fileName(tt.fileName); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+	t.Run("file names in errors are mapped, and the error is wrapped", func(t *testing.T) {
+		hp := &hostPaths{dir: "/app", hostDir: "/home/user/app"}
+		cause := errors.New("cause")
+		err := hp.error(fmt.Errorf("%s generation error: %w", "/app/button.templ", cause), "/app/button.templ")
+		if expected := "/home/user/app/button.templ generation error: cause"; err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+		if !errors.Is(err, cause) {
+			t.Error("expected the error to wrap the cause")
+		}
+	})
+	t.Run("a nil mapping leaves file names unchanged", func(t *testing.T) {
+		var hp *hostPaths
+		if got := hp.fileName("/app/button.templ"); got != "/app/button.templ" {
+			t.Errorf("expected the file name to be unchanged, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package generatecmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected string
+	}{
+		{
+			name:     "files that are the same have no diff",
+			before:   "a\nb\n",
+			after:    "a\nb\n",
+			expected: "",
+		},
+		{
+			name:   "created files are all added lines",
+			before: "",
+			after:  "a\nb\n",
+			expected: `--- a/x_templ.go
++++ b/x_templ.go
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			name:   "deleted files are all removed lines",
+			before: "a\n",
+			after:  "",
+			expected: `--- a/x_templ.go
++++ b/x_templ.go
+@@ -1,1 +0,0 @@
+-a
+`,
+		},
+		{
+			name:   "changes are shown with three lines of context",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			after:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			expected: `--- a/x_templ.go
++++ b/x_templ.go
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			name:   "changes that are far apart are in separate hunks",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			after:  "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			expected: `--- a/x_templ.go
++++ b/x_templ.go
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -7,4 +7,4 @@
+ 7
+ 8
+ 9
+-10
++ten
+`,
+		},
+		{
+			name:   "changes that are close together share a hunk",
+			before: "1\n2\n3\n4\n5\n6\n",
+			after:  "1\n2\nthree\n4\nfive\n6\n7\n",
+			expected: `--- a/x_templ.go
++++ b/x_templ.go
+@@ -1,6 +1,7 @@
+ 1
+ 2
+-3
++three
+ 4
+-5
++five
+ 6
++7
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := unifiedDiff("x_templ.go", tt.before, tt.after)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package generatecmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// dryRun records the files that generation would change, instead of changing
+// them.
+type dryRun struct {
+	m sync.Mutex
+	// changes maps the names of the files that would change to how they would
+	// change, e.g. "created".
+	changes map[string]string
+	// diffs maps the names of the files that would change to the differences
+	// between their current and generated contents. If nil, diffs aren't recorded.
+	diffs map[string]string
+}
+
+func newDryRun(diff bool) (d *dryRun) {
+	d = &dryRun{
+		changes: make(map[string]string),
+	}
+	if diff {
+		d.diffs = make(map[string]string)
+	}
+	return d
+}
+
+// enableDryRun records the files that would be changed in d, instead of changing
+// them.
+func (h *FSEventHandler) enableDryRun(d *dryRun) {
+	h.writer = d.write
+	h.fileWriter = d.write
+	h.remover = d.remove
+}
+
+// write records the file as created or updated, unless it already has the
+// contents.
+func (d *dryRun) write(fileName string, contents []byte) error {
+	existing, err := os.ReadFile(fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil && bytes.Equal(existing, contents) {
+		return nil
+	}
+	change := "updated"
+	if err != nil {
+		change = "created"
+	}
+	d.record(fileName, change, string(existing), string(contents))
+	return nil
+}
+
+// remove records the file as deleted.
+func (d *dryRun) remove(fileName string) error {
+	existing, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	d.record(fileName, "deleted", string(existing), "")
+	return nil
+}
+
+func (d *dryRun) record(fileName, change, before, after string) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	name := displayName(fileName)
+	d.changes[name] = change
+	if d.diffs != nil {
+		d.diffs[name] = unifiedDiff(filepath.ToSlash(name), before, after)
+	}
+}
+
+// len returns the number of files that would change.
+func (d *dryRun) len() int {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return len(d.changes)
+}
+
+// writeSummary writes the files that would change, sorted by name, followed by
+// their diffs.
+func (d *dryRun) writeSummary(w io.Writer) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	fileNames := make([]string, 0, len(d.changes))
+	for fileName := range d.changes {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	var sb strings.Builder
+	for _, fileName := range fileNames {
+		fmt.Fprintf(&sb, "%s: would be %s\n", fileName, d.changes[fileName])
+	}
+	for _, fileName := range fileNames {
+		sb.WriteString(d.diffs[fileName])
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// displayName returns the name of the file relative to the working directory,
+// if it's within it.
+func displayName(fileName string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fileName
+	}
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return fileName
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return fileName
+	}
+	return rel
+}
@@ -0,0 +1,65 @@
+package generatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	unchanged := filepath.Join(dir, "unchanged_templ.go")
+	updated := filepath.Join(dir, "updated_templ.go")
+	deleted := filepath.Join(dir, "deleted_templ.go")
+	for _, name := range []string{unchanged, updated, deleted} {
+		if err := os.WriteFile(name, []byte("package a\n"), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	d := newDryRun(false)
+	if err := d.write(unchanged, []byte("package a\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.write(updated, []byte("package b\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.write(filepath.Join(dir, "created_templ.go"), []byte("package a\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.remove(deleted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d.len() != 3 {
+		t.Errorf("expected 3 changes, got %d", d.len())
+	}
+	var sb strings.Builder
+	if err := d.writeSummary(&sb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := strings.Join([]string{
+		filepath.Join(dir, "created_templ.go") + ": would be created",
+		deleted + ": would be deleted",
+		updated + ": would be updated",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, sb.String()); diff != "" {
+		t.Error(diff)
+	}
+	for _, name := range []string{unchanged, updated, deleted} {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if string(b) != "package a\n" {
+			t.Errorf("%s: expected the file to be unchanged, got %q", name, string(b))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "created_templ.go")); !os.IsNotExist(err) {
+		t.Errorf("expected the file not to be created, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package generatecmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// scriptBundleGlobalName is the variable that the bundled module is assigned to
+// in the body of the script template.
+const scriptBundleGlobalName = "templ_7745c5c3_Script"
+
+// EnableScriptBundling bundles the TypeScript file alongside each template, e.g.
+// components.ts for components.templ, into the script templates of the template
+// that have empty bodies. Each script template calls the function exported from
+// the TypeScript file with the same name. The command is the esbuild executable.
+func (h *FSEventHandler) EnableScriptBundling(command string) {
+	h.esbuildCommand = command
+}
+
+// scriptSourceFileName returns the TypeScript file alongside the template.
+func scriptSourceFileName(templFileName string) string {
+	return strings.TrimSuffix(templFileName, ".templ") + ".ts"
+}
+
+// isScriptSourceFile returns true if the file is a TypeScript file that may be
+// bundled into the scripts of a template.
+func isScriptSourceFile(fileName string) bool {
+	return strings.HasSuffix(fileName, ".ts") && !strings.HasSuffix(fileName, ".d.ts")
+}
+
+// bundleScripts sets the body of each script template that has an empty body to
+// the bundled function of the same name exported by the TypeScript file alongside
+// the template.
+func (h *FSEventHandler) bundleScripts(ctx context.Context, templFileName string, t parser.TemplateFile) error {
+	tsFileName := scriptSourceFileName(templFileName)
+	if _, err := os.Stat(tsFileName); err != nil {
+		if os.IsNotExist(err) {
+			h.setScriptInputs(templFileName, nil)
+			return nil
+		}
+		return err
+	}
+	var inputs []string
+	for i, n := range t.Nodes {
+		st, ok := n.(parser.ScriptTemplate)
+		if !ok || strings.TrimSpace(st.Value) != "" {
+			continue
+		}
+		bundle, bundleInputs, err := h.bundleScript(ctx, tsFileName, st.Name.Value)
+		if err != nil {
+			return fmt.Errorf("failed to bundle script %s from %q: %w", st.Name.Value, tsFileName, err)
+		}
+		inputs = append(inputs, bundleInputs...)
+		st.Value = bundle + "return " + scriptBundleGlobalName + "." + st.Name.Value + ".apply(this, arguments);"
+		t.Nodes[i] = st
+	}
+	h.setScriptInputs(templFileName, inputs)
+	return nil
+}
+
+// bundleScript runs esbuild to bundle the named export of the TypeScript file,
+// and its imports, into a script that assigns the module to a variable. The
+// inputs are the absolute paths of the files in the bundle.
+func (h *FSEventHandler) bundleScript(ctx context.Context, tsFileName, name string) (bundle string, inputs []string, err error) {
+	dir, file := filepath.Split(tsFileName)
+	if dir, err = filepath.Abs(dir); err != nil {
+		return "", nil, err
+	}
+	metafile, err := os.CreateTemp("", "templ-esbuild-*.json")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create esbuild metafile: %w", err)
+	}
+	metafile.Close()
+	defer os.Remove(metafile.Name())
+	cmd := exec.CommandContext(ctx, h.esbuildCommand,
+		"--bundle",
+		"--format=iife",
+		"--global-name="+scriptBundleGlobalName,
+		"--loader=ts",
+		"--minify",
+		"--log-level=error",
+		"--resolve-dir="+dir,
+		"--metafile="+metafile.Name(),
+	)
+	// The paths of the inputs in the metafile are relative to the working directory.
+	cmd.Dir = dir
+	// The entrypoint only exports the function used by the script template, so
+	// that other exports are removed from the bundle.
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("export { %s } from %q;\n", name, "./"+file))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if inputs, err = readMetafileInputs(metafile.Name(), dir); err != nil {
+		return "", nil, err
+	}
+	return stdout.String(), inputs, nil
+}
+
+// readMetafileInputs returns the absolute paths of the input files listed in an
+// esbuild metafile. The entrypoint read from stdin isn't a file, so it's skipped.
+func readMetafileInputs(fileName, dir string) (inputs []string, err error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read esbuild metafile: %w", err)
+	}
+	var metafile struct {
+		Inputs map[string]json.RawMessage `json:"inputs"`
+	}
+	if err = json.Unmarshal(data, &metafile); err != nil {
+		return nil, fmt.Errorf("failed to parse esbuild metafile: %w", err)
+	}
+	for input := range metafile.Inputs {
+		if input == "<stdin>" {
+			continue
+		}
+		if !filepath.IsAbs(input) {
+			input = filepath.Join(dir, filepath.FromSlash(input))
+		}
+		inputs = append(inputs, input)
+	}
+	sort.Strings(inputs)
+	return inputs, nil
+}
+
+// setScriptInputs records the files that are bundled into the scripts of a
+// template, so that the template is regenerated when any of them change.
+func (h *FSEventHandler) setScriptInputs(templFileName string, inputs []string) {
+	h.scriptInputsMutex.Lock()
+	defer h.scriptInputsMutex.Unlock()
+	if h.scriptInputs == nil {
+		h.scriptInputs = make(map[string][]string)
+	}
+	if len(inputs) == 0 {
+		delete(h.scriptInputs, templFileName)
+		return
+	}
+	h.scriptInputs[templFileName] = inputs
+}
+
+// scriptDependents returns the templates that bundle the TypeScript file: the
+// template alongside it, and the templates whose bundles import it.
+func (h *FSEventHandler) scriptDependents(tsFileName string) (templFileNames []string) {
+	unique := make(map[string]struct{})
+	templFileName := strings.TrimSuffix(tsFileName, ".ts") + ".templ"
+	if _, err := os.Stat(templFileName); err == nil {
+		unique[templFileName] = struct{}{}
+	}
+	abs, err := filepath.Abs(tsFileName)
+	if err != nil {
+		abs = tsFileName
+	}
+	h.scriptInputsMutex.Lock()
+	for templFileName, inputs := range h.scriptInputs {
+		for _, input := range inputs {
+			if input == abs {
+				unique[templFileName] = struct{}{}
+				break
+			}
+		}
+	}
+	h.scriptInputsMutex.Unlock()
+	for templFileName := range unique {
+		templFileNames = append(templFileNames, templFileName)
+	}
+	sort.Strings(templFileNames)
+	return templFileNames
+}
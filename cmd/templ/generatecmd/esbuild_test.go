@@ -0,0 +1,70 @@
+package generatecmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBundleScripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake esbuild command is a shell script")
+	}
+	dir := t.TempDir()
+	// The fake esbuild command writes the entrypoint it's given as a comment, and
+	// lists a module imported by the TypeScript file in the metafile.
+	esbuild := filepath.Join(dir, "esbuild")
+	script := `#!/bin/sh
+for arg in "$@"; do
+	case "$arg" in
+	--metafile=*) printf '{"inputs":{"<stdin>":{},"components.ts":{},"lib/format.ts":{}}}' > "${arg#--metafile=}" ;;
+	esac
+done
+printf '/*%s*/' "$(cat)"
+`
+	if err := os.WriteFile(esbuild, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	templFileName := filepath.Join(dir, "components.templ")
+	if err := os.WriteFile(scriptSourceFileName(templFileName), []byte("export function greet(name: string) {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tf, err := parser.ParseString(`package main
+
+script greet(name string) {
+}
+
+script log(message string) {
+	console.log(message);
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	h := &FSEventHandler{}
+	h.EnableScriptBundling(esbuild)
+	if err = h.bundleScripts(context.Background(), templFileName, tf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `/*export { greet } from "./components.ts";*/return templ_7745c5c3_Script.greet.apply(this, arguments);`
+	if diff := cmp.Diff(expected, tf.Nodes[0].(parser.ScriptTemplate).Value); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff("\tconsole.log(message);\n", tf.Nodes[1].(parser.ScriptTemplate).Value); diff != "" {
+		t.Errorf("expected scripts with bodies to be unchanged: %s", diff)
+	}
+	t.Run("templates are regenerated when the modules they import change", func(t *testing.T) {
+		if diff := cmp.Diff([]string{templFileName}, h.scriptDependents(filepath.Join(dir, "lib", "format.ts"))); diff != "" {
+			t.Error(diff)
+		}
+		if dependents := h.scriptDependents(filepath.Join(dir, "other.ts")); len(dependents) != 0 {
+			t.Errorf("expected no dependents, got %v", dependents)
+		}
+	})
+}
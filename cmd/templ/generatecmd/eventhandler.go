@@ -5,16 +5,24 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
+	"go/ast"
 	"go/format"
+	goparser "go/parser"
+	"go/token"
 	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/a-h/parse"
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/generatecmd/modcheck"
 	"github.com/a-h/templ/cmd/templ/visualize"
 	"github.com/a-h/templ/generator"
 	"github.com/a-h/templ/parser/v2"
@@ -47,6 +55,8 @@ func NewFSEventHandler(
 		DevMode:                    devMode,
 		keepOrphanedFiles:          keepOrphanedFiles,
 		writer:                     writeToFile,
+		fileWriter:                 writeToFile,
+		remover:                    os.Remove,
 	}
 	if toStdout {
 		fseh.writer = writeToStdout
@@ -73,9 +83,221 @@ type FSEventHandler struct {
 	Errors                     []error
 	keepOrphanedFiles          bool
 	writer                     func(string, []byte) error
+	// fileWriter writes the other files generated from templ files, and remover
+	// deletes generated files that are no longer needed.
+	fileWriter func(string, []byte) error
+	remover    func(string) error
+	// staticChunks maps directories to the static chunks shared by their templates.
+	// If nil, static chunks are not deduplicated.
+	staticChunks      map[string]*staticChunkFile
+	staticChunksMutex *sync.Mutex
+	// readStaticChunks loads the existing static chunks of each directory before
+	// adding to them, for when only some of the files in a directory are generated.
+	readStaticChunks bool
+	// staticChunksHeader and staticChunksBuildConstraint are written at the top
+	// of each static chunks file.
+	staticChunksHeader          string
+	staticChunksBuildConstraint string
+	// classes writes the literal class names used in templates to a file. If nil,
+	// the file isn't written.
+	classes *classesFile
+	// ClassesUpdated is set when the classes file is written.
+	ClassesUpdated atomic.Bool
+	// failures collects the first error of each file that fails to generate. If
+	// nil, errors are only logged.
+	failures *failures
+	// scriptTypes writes TypeScript declarations of the functions of script
+	// templates to _templ.d.ts files.
+	scriptTypes bool
+	// esbuildCommand bundles TypeScript files into script templates. If empty,
+	// scripts aren't bundled.
+	esbuildCommand string
+	// scriptInputs maps templates to the TypeScript files bundled into their
+	// scripts, including the modules that they import.
+	scriptInputs      map[string][]string
+	scriptInputsMutex sync.Mutex
+	// sourceHash includes the hash of each templ file in its generated code.
+	sourceHash bool
+	// runtimes are the templ modules that generated code is checked against, by
+	// the directory of the Go module that the code is generated in. If nil, the
+	// generated code isn't checked.
+	runtimes       map[string]*modcheck.Runtime
+	runtimesMutex  sync.Mutex
+	runtimeVersion string
+	// hostPaths maps the file names in errors to paths on the host, when templ
+	// runs in a container. If nil, file names aren't mapped.
+	hostPaths *hostPaths
+	// outputDir is the directory that generated code is written to, mirroring the
+	// source tree. If empty, code is generated next to each templ file.
+	outputDir string
+}
+
+// EnableRuntimeCheck checks that the templ module required by the Go module of each
+// file has the functions and types that the generated code uses, so that a mismatch
+// between the version of the templ CLI and the version of the module is a clear
+// error, instead of a compile error. If version is set, that version of the templ
+// module is used instead.
+func (h *FSEventHandler) EnableRuntimeCheck(version string) {
+	h.runtimes = map[string]*modcheck.Runtime{}
+	h.runtimeVersion = version
+}
+
+// getRuntime returns the templ module of the Go module that contains dir, or nil
+// if its source isn't available.
+func (h *FSEventHandler) getRuntime(dir string) *modcheck.Runtime {
+	root, err := modcheck.WalkUp(dir)
+	if err != nil {
+		return nil
+	}
+	h.runtimesMutex.Lock()
+	defer h.runtimesMutex.Unlock()
+	if rt, ok := h.runtimes[root]; ok {
+		return rt
+	}
+	rt, err := modcheck.FindRuntime(root, h.runtimeVersion)
+	if err != nil {
+		// Without a runtime version, problems with the go.mod file are reported by the version check.
+		if h.runtimeVersion != "" {
+			h.Log.Warn("templ runtime check: " + err.Error())
+		}
+	} else if rt.Dir == "" {
+		h.Log.Warn("templ runtime check: the source of templ " + rt.Version + " wasn't found, so the generated code isn't checked against it, run `go mod download github.com/a-h/templ@" + rt.Version + "` to download it")
+		rt = nil
+	}
+	h.runtimes[root] = rt
+	return rt
+}
+
+// EnableSourceHash includes the hash of each templ file in the generated code, so
+// that templ verify can find generated files that are stale.
+func (h *FSEventHandler) EnableSourceHash() {
+	h.sourceHash = true
+}
+
+// EnableHostPaths reports errors with the paths of files on the host, when the
+// directory is mounted into a container from hostDir.
+func (h *FSEventHandler) EnableHostPaths(hostDir string) {
+	h.hostPaths = &hostPaths{dir: h.dir, hostDir: hostDir}
+}
+
+// EnableScriptTypes writes TypeScript declarations of the functions of the script
+// templates in each file, and of the props of its islands, to a _templ.d.ts file.
+func (h *FSEventHandler) EnableScriptTypes() {
+	h.scriptTypes = true
+}
+
+// staticChunksFileName is the name of the file that contains the static chunks
+// shared by the templates in a directory.
+const staticChunksFileName = "templ_static_chunks.go"
+
+type staticChunkFile struct {
+	m      sync.Mutex
+	chunks *generator.StaticChunks
+	// used maps the generated Go files of the directory to the names of the
+	// chunks that they reference. Only the chunks that are used are written.
+	used map[string][]string
+}
+
+// EnableStaticChunks deduplicates long static HTML chunks into package-level
+// constants, written to a templ_static_chunks.go file in each directory. If
+// readExisting is true, the chunks already in the file that the other generated
+// files in the directory use are kept.
+func (h *FSEventHandler) EnableStaticChunks(readExisting bool) {
+	h.staticChunks = make(map[string]*staticChunkFile)
+	h.staticChunksMutex = &sync.Mutex{}
+	h.readStaticChunks = readExisting
+}
+
+// SetStaticChunksHeader sets the comment and build constraint written at the top
+// of the static chunks files, so that they match the rest of the generated code.
+func (h *FSEventHandler) SetStaticChunksHeader(comment, buildConstraint string) {
+	h.staticChunksHeader = comment
+	h.staticChunksBuildConstraint = buildConstraint
+}
+
+func (h *FSEventHandler) getStaticChunks(dir string) (scf *staticChunkFile, err error) {
+	h.staticChunksMutex.Lock()
+	defer h.staticChunksMutex.Unlock()
+	if scf, ok := h.staticChunks[dir]; ok {
+		return scf, nil
+	}
+	scf = &staticChunkFile{chunks: generator.NewStaticChunks(), used: make(map[string][]string)}
+	if err = scf.chunks.SetHeader(h.staticChunksHeader, h.staticChunksBuildConstraint); err != nil {
+		return nil, err
+	}
+	if h.readStaticChunks {
+		src, err := os.ReadFile(filepath.Join(dir, staticChunksFileName))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read static chunks: %w", err)
+		}
+		if err == nil {
+			if err = scf.chunks.ReadGo(src); err != nil {
+				return nil, err
+			}
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read static chunks directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_templ.go") {
+				continue
+			}
+			fileName := filepath.Join(dir, entry.Name())
+			src, err := os.ReadFile(fileName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read static chunks: %w", err)
+			}
+			scf.used[fileName] = generator.StaticChunkNames(src)
+		}
+	}
+	h.staticChunks[dir] = scf
+	return scf, nil
+}
+
+// writeStaticChunks records the chunks that the generated Go code of a file
+// uses, and writes the chunks that are used by the files of its directory, if
+// they have changed. The file is removed if none of the chunks are used.
+func (h *FSEventHandler) writeStaticChunks(scf *staticChunkFile, targetFileName string, goCode []byte) (updated bool, err error) {
+	scf.m.Lock()
+	defer scf.m.Unlock()
+	scf.used[targetFileName] = generator.StaticChunkNames(goCode)
+	names := make(map[string]struct{})
+	for _, fileNames := range scf.used {
+		for _, name := range fileNames {
+			names[name] = struct{}{}
+		}
+	}
+	chunks := scf.chunks.Subset(names)
+	fileName := filepath.Join(filepath.Dir(targetFileName), staticChunksFileName)
+	if chunks.Len() == 0 {
+		h.UpsertHash(fileName, [sha256.Size]byte{})
+		if _, err = os.Stat(fileName); err != nil {
+			return false, nil
+		}
+		if err = h.remover(fileName); err != nil {
+			return false, fmt.Errorf("failed to remove static chunks file %q: %w", fileName, err)
+		}
+		return true, nil
+	}
+	var b bytes.Buffer
+	if err = chunks.WriteGo(&b); err != nil {
+		return false, err
+	}
+	if !h.UpsertHash(fileName, sha256.Sum256(b.Bytes())) {
+		return false, nil
+	}
+	if err = h.writer(fileName, b.Bytes()); err != nil {
+		return false, fmt.Errorf("failed to write static chunks file %q: %w", fileName, err)
+	}
+	return true, nil
 }
 
 func writeToFile(fileName string, contents []byte) error {
+	// The directory may not exist yet if code is generated to an output directory.
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
 	return os.WriteFile(fileName, contents, 0o644)
 }
 
@@ -87,16 +309,18 @@ func writeToStdout(_ string, contents []byte) error {
 func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event) (goUpdated, textUpdated bool, err error) {
 	// Handle _templ.go files.
 	if !event.Has(fsnotify.Remove) && strings.HasSuffix(event.Name, "_templ.go") {
-		_, err = os.Stat(strings.TrimSuffix(event.Name, "_templ.go") + ".templ")
-		if !os.IsNotExist(err) {
+		templFileName := h.sourceFileName(event.Name, "_templ.go")
+		_, err = os.Stat(templFileName)
+		if !os.IsNotExist(err) && (err != nil || samePath(h.outputFileName(templFileName, "_templ.go"), event.Name)) {
 			return false, false, err
 		}
-		// File is orphaned.
+		// File is orphaned, or was generated next to the templ file before the
+		// output directory was set.
 		if h.keepOrphanedFiles {
 			return false, false, nil
 		}
 		h.Log.Debug("Deleting orphaned Go file", slog.String("file", event.Name))
-		if err = os.Remove(event.Name); err != nil {
+		if err = h.remover(event.Name); err != nil {
 			h.Log.Warn("Failed to remove orphaned file", slog.Any("error", err))
 		}
 		return true, false, nil
@@ -108,12 +332,41 @@ func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event)
 			return false, true, nil
 		}
 		h.Log.Debug("Deleting watch mode file", slog.String("file", event.Name))
-		if err = os.Remove(event.Name); err != nil {
+		if err = h.remover(event.Name); err != nil {
 			h.Log.Warn("Failed to remove watch mode text file", slog.Any("error", err))
 			return false, false, nil
 		}
 		return false, false, nil
 	}
+	// Handle _templ.d.ts files.
+	if !event.Has(fsnotify.Remove) && strings.HasSuffix(event.Name, "_templ.d.ts") {
+		templFileName := strings.TrimSuffix(event.Name, "_templ.d.ts") + ".templ"
+		if _, err = os.Stat(templFileName); !os.IsNotExist(err) || h.keepOrphanedFiles {
+			return false, false, nil
+		}
+		h.Log.Debug("Deleting orphaned TypeScript declaration file", slog.String("file", event.Name))
+		if err = h.remover(event.Name); err != nil {
+			h.Log.Warn("Failed to remove orphaned file", slog.Any("error", err))
+		}
+		return false, false, nil
+	}
+
+	// Handle TypeScript files that are bundled into the scripts of templates.
+	if h.esbuildCommand != "" && !event.Has(fsnotify.Remove) && isScriptSourceFile(event.Name) {
+		for _, templFileName := range h.scriptDependents(event.Name) {
+			// Regenerate the template, even though it hasn't been updated.
+			h.fileNameToLastModTimeMutex.Lock()
+			delete(h.fileNameToLastModTime, templFileName)
+			h.fileNameToLastModTimeMutex.Unlock()
+			templGoUpdated, templTextUpdated, err := h.HandleEvent(ctx, fsnotify.Event{Name: templFileName, Op: fsnotify.Write})
+			if err != nil {
+				return goUpdated, textUpdated, err
+			}
+			goUpdated = goUpdated || templGoUpdated
+			textUpdated = textUpdated || templTextUpdated
+		}
+		return goUpdated, textUpdated, nil
+	}
 
 	// Handle .templ files.
 	if !strings.HasSuffix(event.Name, ".templ") {
@@ -130,17 +383,36 @@ func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event)
 	start := time.Now()
 	goUpdated, textUpdated, diag, err := h.generate(ctx, event.Name)
 	if err != nil {
-		h.Log.Error(
-			"Error generating code",
-			slog.String("file", event.Name),
-			slog.Any("error", err),
-		)
+		// Log each of the syntax errors of the file, with the line that contains it.
+		errs := []error{err}
+		var parseErrors parser.ParseErrors
+		var parseError parse.ParseError
+		if errors.As(err, &parseErrors) {
+			errs = errs[:0]
+			for _, pe := range parseErrors {
+				errs = append(errs, pe)
+			}
+		} else if errors.As(err, &parseError) {
+			errs = []error{parseError}
+		}
+		src, _ := os.ReadFile(event.Name)
+		for _, err := range errs {
+			h.Log.Error(
+				"Error generating code",
+				slog.String("file", h.hostPaths.fileName(event.Name)),
+				slog.String("error", h.hostPaths.text(parser.FormatError(string(src), err), event.Name)),
+			)
+		}
 		h.SetError(event.Name, true)
-		return goUpdated, textUpdated, fmt.Errorf("failed to generate code for %q: %w", event.Name, err)
+		if h.failures != nil {
+			h.failures.add(h.hostPaths.fileName(event.Name), h.hostPaths.text(strings.TrimPrefix(errs[0].Error(), event.Name+" "), event.Name))
+		}
+		return goUpdated, textUpdated, h.hostPaths.error(fmt.Errorf("failed to generate code for %q: %w", event.Name, err), event.Name)
 	}
 	if len(diag) > 0 {
 		for _, d := range diag {
 			h.Log.Warn(d.Message,
+				slog.String("file", h.hostPaths.fileName(event.Name)),
 				slog.String("from", fmt.Sprintf("%d:%d", d.Range.From.Line, d.Range.From.Col)),
 				slog.String("to", fmt.Sprintf("%d:%d", d.Range.To.Line, d.Range.To.Col)),
 			)
@@ -148,7 +420,7 @@ func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event)
 		return
 	}
 	if errorCleared, errorCount := h.SetError(event.Name, false); errorCleared {
-		h.Log.Info("Error cleared", slog.String("file", event.Name), slog.Int("errors", errorCount))
+		h.Log.Info("Error cleared", slog.String("file", h.hostPaths.fileName(event.Name)), slog.Int("errors", errorCount))
 	}
 	h.Log.Debug("Generated code", slog.String("file", event.Name), slog.Duration("in", time.Since(start)))
 
@@ -195,11 +467,20 @@ func (h *FSEventHandler) UpsertHash(fileName string, hash [sha256.Size]byte) (up
 // generate Go code for a single template.
 // If a basePath is provided, the filename included in error messages is relative to it.
 func (h *FSEventHandler) generate(ctx context.Context, fileName string) (goUpdated, textUpdated bool, diagnostics []parser.Diagnostic, err error) {
-	t, err := parser.Parse(fileName)
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return false, false, nil, fmt.Errorf("%s parsing error: %w", fileName, err)
+	}
+	t, err := parser.ParseString(string(src))
 	if err != nil {
 		return false, false, nil, fmt.Errorf("%s parsing error: %w", fileName, err)
 	}
-	targetFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	if h.esbuildCommand != "" {
+		if err = h.bundleScripts(ctx, fileName, t); err != nil {
+			return false, false, nil, fmt.Errorf("%s script bundling error: %w", fileName, err)
+		}
+	}
+	targetFileName := h.outputFileName(fileName, "_templ.go")
 
 	// Only use relative filenames to the basepath for filenames in runtime error messages.
 	absFilePath, err := filepath.Abs(fileName)
@@ -211,8 +492,20 @@ func (h *FSEventHandler) generate(ctx context.Context, fileName string) (goUpdat
 		return false, false, nil, fmt.Errorf("failed to get relative path for %q: %w", fileName, err)
 	}
 
+	opts := append(h.genOpts[:len(h.genOpts):len(h.genOpts)], generator.WithFileName(relFilePath))
+	if h.sourceHash {
+		opts = append(opts, generator.WithSourceHash(generator.SourceHash(src)))
+	}
+	var scf *staticChunkFile
+	if h.staticChunks != nil && !h.DevMode {
+		if scf, err = h.getStaticChunks(filepath.Dir(targetFileName)); err != nil {
+			return false, false, nil, fmt.Errorf("%s generation error: %w", fileName, err)
+		}
+		opts = append(opts, generator.WithStaticChunks(scf.chunks))
+	}
+
 	var b bytes.Buffer
-	sourceMap, literals, err := generator.Generate(t, &b, append(h.genOpts, generator.WithFileName(relFilePath))...)
+	sourceMap, literals, err := generator.Generate(t, &b, opts...)
 	if err != nil {
 		return false, false, nil, fmt.Errorf("%s generation error: %w", fileName, err)
 	}
@@ -222,6 +515,18 @@ func (h *FSEventHandler) generate(ctx context.Context, fileName string) (goUpdat
 		return false, false, nil, fmt.Errorf("%s source formatting error: %w", fileName, err)
 	}
 
+	if h.runtimes != nil {
+		if rt := h.getRuntime(filepath.Dir(absFilePath)); rt != nil {
+			missing, err := rt.Missing(formattedGoCode)
+			if err != nil {
+				return false, false, nil, fmt.Errorf("%s runtime check error: %w", fileName, err)
+			}
+			if len(missing) > 0 {
+				return false, false, nil, fmt.Errorf("%s: the generated code uses %s, which templ %s doesn't have, upgrade it with `go get github.com/a-h/templ@%s`, or use the templ CLI of the same version", fileName, strings.Join(missing, ", "), rt, templ.Version())
+			}
+		}
+	}
+
 	// Hash output, and write out the file if the goCodeHash has changed.
 	goCodeHash := sha256.Sum256(formattedGoCode)
 	if h.UpsertHash(targetFileName, goCodeHash) {
@@ -231,13 +536,40 @@ func (h *FSEventHandler) generate(ctx context.Context, fileName string) (goUpdat
 		}
 	}
 
+	// Write the static chunks shared with other files in the directory.
+	if scf != nil {
+		chunksUpdated, err := h.writeStaticChunks(scf, targetFileName, formattedGoCode)
+		if err != nil {
+			return false, false, nil, err
+		}
+		goUpdated = goUpdated || chunksUpdated
+	}
+
+	// Write the class names used in the template.
+	if h.classes != nil {
+		classesUpdated, err := h.writeClasses(fileName, t)
+		if err != nil {
+			return false, false, nil, err
+		}
+		if classesUpdated {
+			h.ClassesUpdated.Store(true)
+		}
+	}
+
+	// Write the TypeScript declarations of the script templates.
+	if h.scriptTypes {
+		if err = h.writeScriptTypes(fileName, t); err != nil {
+			return false, false, nil, err
+		}
+	}
+
 	// Add the txt file if it has changed.
 	if len(literals) > 0 {
-		txtFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.txt"
+		txtFileName := h.outputFileName(fileName, "_templ.txt")
 		txtHash := sha256.Sum256([]byte(literals))
 		if h.UpsertHash(txtFileName, txtHash) {
 			textUpdated = true
-			if err = os.WriteFile(txtFileName, []byte(literals), 0o644); err != nil {
+			if err = h.fileWriter(txtFileName, []byte(literals)); err != nil {
 				return false, false, nil, fmt.Errorf("failed to write string literal file %q: %w", txtFileName, err)
 			}
 		}
@@ -255,6 +587,57 @@ func (h *FSEventHandler) generate(ctx context.Context, fileName string) (goUpdat
 	return goUpdated, textUpdated, parsedDiagnostics, err
 }
 
+// writeScriptTypes writes the TypeScript declarations of the script templates in
+// the file, if it has changed. The declaration file is removed if the file has
+// nothing to declare.
+func (h *FSEventHandler) writeScriptTypes(fileName string, t parser.TemplateFile) error {
+	definitions, err := generator.ScriptTypeDefinitions(t, parsePackageGoFiles(filepath.Dir(fileName))...)
+	if err != nil {
+		return fmt.Errorf("%s script type generation error: %w", fileName, err)
+	}
+	dtsFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.d.ts"
+	if definitions == "" {
+		h.UpsertHash(dtsFileName, [sha256.Size]byte{})
+		if _, err = os.Stat(dtsFileName); err != nil {
+			return nil
+		}
+		if err = h.remover(dtsFileName); err != nil {
+			return fmt.Errorf("failed to remove script type file %q: %w", dtsFileName, err)
+		}
+		return nil
+	}
+	if !h.UpsertHash(dtsFileName, sha256.Sum256([]byte(definitions))) {
+		return nil
+	}
+	if err = h.fileWriter(dtsFileName, []byte(definitions)); err != nil {
+		return fmt.Errorf("failed to write script type file %q: %w", dtsFileName, err)
+	}
+	return nil
+}
+
+// parsePackageGoFiles parses the Go files in the directory, so that the types
+// that script templates and islands use can be declared. Generated files, test
+// files, and files that don't parse are skipped.
+func parsePackageGoFiles(dir string) (files []*ast.File) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_templ.go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := goparser.ParseFile(fset, filepath.Join(dir, name), nil, goparser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
 func generateSourceMapVisualisation(ctx context.Context, templFileName, goFileName string, sourceMap *parser.SourceMap) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -0,0 +1,70 @@
+package generatecmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// failures collects the first error of each file that failed to generate, so
+// that they can be listed once generation has completed.
+type failures struct {
+	m      sync.Mutex
+	errors map[string]string
+}
+
+// add records the error of the file, unless the file already has one. Only the
+// first line of the error is kept.
+func (f *failures) add(fileName, err string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.errors == nil {
+		f.errors = make(map[string]string)
+	}
+	if _, ok := f.errors[fileName]; !ok {
+		f.errors[fileName], _, _ = strings.Cut(err, "\n")
+	}
+}
+
+// len returns the number of files that failed.
+func (f *failures) len() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return len(f.errors)
+}
+
+// reset forgets the failures, e.g. before all files are generated again.
+func (f *failures) reset() {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.errors = nil
+}
+
+// writeSummary writes the number of files that failed, and the first error of
+// each, sorted by file name.
+func (f *failures) writeSummary(w io.Writer) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if len(f.errors) == 0 {
+		return nil
+	}
+	fileNames := make([]string, 0, len(f.errors))
+	for fileName := range f.errors {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	var sb strings.Builder
+	if len(fileNames) == 1 {
+		sb.WriteString("\n1 file failed to generate:\n")
+	} else {
+		fmt.Fprintf(&sb, "\n%d files failed to generate:\n", len(fileNames))
+	}
+	for _, fileName := range fileNames {
+		fmt.Fprintf(&sb, "  %s: %s\n", fileName, f.errors[fileName])
+	}
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,51 @@
+package generatecmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFailures(t *testing.T) {
+	t.Run("nothing is written if no files failed", func(t *testing.T) {
+		var f failures
+		var sb strings.Builder
+		if err := f.writeSummary(&sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.Len() != 0 {
+			t.Errorf("expected no output, got %q", sb.String())
+		}
+	})
+	t.Run("the first line of the first error of each file is listed, sorted by file name", func(t *testing.T) {
+		var f failures
+		f.add("pages/home.templ", "unbalanced '}': line 4, col 2")
+		f.add("components/button.templ", "mismatched end tag: line 6, col 1\n  6 | \t</div>")
+		f.add("pages/home.templ", "a later error")
+		if f.len() != 2 {
+			t.Errorf("expected 2 failures, got %d", f.len())
+		}
+		var sb strings.Builder
+		if err := f.writeSummary(&sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `
+2 files failed to generate:
+  components/button.templ: mismatched end tag: line 6, col 1
+  pages/home.templ: unbalanced '}': line 4, col 2
+
+`
+		if diff := cmp.Diff(expected, sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("reset forgets the failures", func(t *testing.T) {
+		var f failures
+		f.add("a.templ", "error")
+		f.reset()
+		if f.len() != 0 {
+			t.Errorf("expected no failures, got %d", f.len())
+		}
+	})
+}
@@ -5,9 +5,17 @@ import (
 	_ "embed"
 	"io"
 	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
 
 	_ "net/http/pprof"
 
+	"github.com/a-h/templ/cmd/templ/generatecmd/proxy"
+	"github.com/a-h/templ/cmd/templ/generatecmd/sse"
+	"github.com/a-h/templ/cmd/templ/generatecmd/watcher"
+	"github.com/a-h/templ/cmd/templ/gitignore"
+	"github.com/a-h/templ/cmd/templ/processor"
 	"github.com/a-h/templ/cmd/templ/sloghandler"
 )
 
@@ -26,10 +34,177 @@ type Arguments struct {
 	GenerateSourceMapVisualisations bool
 	IncludeVersion                  bool
 	IncludeTimestamp                bool
-	LogLevel                        string
+	Instrument                      bool
+	StrictHTML                      bool
+	// PermissiveStringExpressions allows the values of string expressions to be
+	// numbers, booleans, fmt.Stringer and error values, as well as strings.
+	PermissiveStringExpressions bool
+	Minify                      bool
+	// StaticChunks deduplicates static HTML chunks into package-level constants.
+	StaticChunks bool
+	// TailwindClassesFile is the file to write the literal class names used in templates to.
+	TailwindClassesFile string
+	// TailwindCommand is run in watch mode when the classes file changes.
+	TailwindCommand string
+	// ScriptTypes writes TypeScript declarations of script template functions.
+	ScriptTypes bool
+	// Behaviors writes script templates in event handler attributes as behaviors.
+	Behaviors bool
+	// HeaderFile is a file whose contents are written as a comment at the top of
+	// every generated Go file, e.g. a license header.
+	HeaderFile string
+	// BuildConstraint is a //go:build expression added to every generated Go file.
+	BuildConstraint string
+	// ESBuildCommand is the esbuild executable used to bundle TypeScript files into script templates.
+	ESBuildCommand string
+	// RuntimeVersion is the version of the templ module that the generated code is
+	// checked against. If empty, the version in the go.mod file is used.
+	RuntimeVersion string
+	// WatchStrategy is how changes are detected in watch mode, either "fsnotify"
+	// or "poll".
+	WatchStrategy string
+	// PollInterval is the time between scans of the file tree when polling.
+	PollInterval time.Duration
+	// PollHash compares the contents of files, instead of their modification
+	// times, when polling.
+	PollHash bool
+	// DetectMissedEvents scans the file tree at an interval when watching with
+	// fsnotify, and logs a warning if a file changes without an event.
+	DetectMissedEvents bool
+	// ProxyExternalURL is the URL that browsers connect to the proxy with, e.g. when
+	// the proxy runs in a container with a port mapping.
+	ProxyExternalURL string
+	// NotifyBind and NotifyPort are the address of a second listener that only
+	// accepts reload events, authenticated with NotifyToken, so that a remote build
+	// machine can trigger reloads. If NotifyPort is 0, there is no second listener.
+	NotifyBind  string
+	NotifyPort  int
+	NotifyToken string
+	// HostPath is the directory on the host that the path is mounted from, when
+	// templ runs in a container. File names in errors are reported relative to it.
+	HostPath string
+	// OutputDir is the directory that generated code is written to, mirroring the
+	// directory structure of Path. If empty, code is generated next to each templ
+	// file.
+	OutputDir string
+	// StaticDir is a directory of files that the proxy serves itself, with the
+	// reload script inserted into HTML files.
+	StaticDir string
+	// ProxyRoutes send requests with paths that start with a prefix to another URL,
+	// in the form prefix=url, e.g. /api=http://localhost:4000.
+	ProxyRoutes []string
+	// ProxyLog logs each request made through the proxy, except for those with paths
+	// that match one of the comma separated ProxyLogExclude patterns.
+	ProxyLog        bool
+	ProxyLogExclude string
+	// ProxyMorph updates pages in place on reload, instead of reloading them.
+	ProxyMorph bool
+	// ProxyPingInterval is the time between the pings sent to browsers connected
+	// for reload events. If zero, DefaultProxyPingInterval is used.
+	ProxyPingInterval time.Duration
+	// QRCode prints a QR code of the URL of the proxy on the local network.
+	QRCode bool
+	// PIDFile is the file to write the pid of templ to.
+	PIDFile  string
+	LogLevel string
 	// PPROFPort is the port to run the pprof server on.
 	PPROFPort         int
 	KeepOrphanedFiles bool
+	// KeepGoing generates the rest of the files when a file fails to generate,
+	// instead of stopping at the first error. Watch mode always keeps going.
+	KeepGoing bool
+	// DryRun reports the files that generation would change, instead of changing
+	// them. Generation fails if any file would change.
+	DryRun bool
+	// Diff prints the changes that generation would make, and implies DryRun.
+	Diff bool
+	// FollowSymlinks walks and watches symlinked directories.
+	FollowSymlinks bool
+	// ExcludeDirs are patterns of directories that aren't walked or watched, see
+	// watcher.Filter. If nil, watcher.DefaultExclude is used.
+	ExcludeDirs []string
+	// GitIgnore skips directories that are ignored by git.
+	GitIgnore bool
+	// Files are the templ files, directories and patterns of templ files to
+	// generate code for, instead of walking Path, see processor.ExpandFiles.
+	Files []string
+	// FilesFrom is a file that lists more files to generate code for, one per
+	// line, or "-" to read the list from stdin.
+	FilesFrom string
+}
+
+const (
+	// WatchStrategyFSNotify watches for file system events.
+	WatchStrategyFSNotify = "fsnotify"
+	// WatchStrategyPoll scans the file tree for changes at an interval.
+	WatchStrategyPoll = "poll"
+)
+
+// DefaultProxyLogExclude are the paths of static assets, which aren't included in
+// the proxy log by default.
+var DefaultProxyLogExclude = strings.Join(proxy.DefaultAccessLogExclude, ",")
+
+// DefaultExcludeDirs are the directories that aren't searched for templ files by
+// default.
+var DefaultExcludeDirs = strings.Join(watcher.DefaultExclude, ",")
+
+// DefaultProxyPingInterval is the default time between the pings sent to browsers
+// connected for reload events.
+const DefaultProxyPingInterval = sse.DefaultPingInterval
+
+// DefaultPollInterval is the time between scans of the file tree when polling.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// missedEventsInterval is the time between scans of the file tree that check
+// whether file system events are being received, see DetectMissedEvents.
+const missedEventsInterval = 30 * time.Second
+
+func (a Arguments) walkFilter() (f watcher.Filter) {
+	f = watcher.Filter{
+		FollowSymlinks: a.FollowSymlinks,
+		Exclude:        a.ExcludeDirs,
+		Scripts:        a.ESBuildCommand != "",
+	}
+	if a.GitIgnore {
+		f.Ignore = gitignore.New(a.Path)
+	}
+	return f
+}
+
+// expandFiles returns the absolute paths of the templ files in Files and
+// FilesFrom.
+func (a Arguments) expandFiles() (fileNames []string, err error) {
+	files := a.Files
+	if a.FilesFrom != "" {
+		list, err := processor.ReadFileListFrom(a.FilesFrom)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files[:len(files):len(files)], list...)
+	}
+	if fileNames, err = processor.ExpandFiles(files, a.GitIgnore); err != nil {
+		return nil, err
+	}
+	for i, fileName := range fileNames {
+		if fileNames[i], err = filepath.Abs(fileName); err != nil {
+			return nil, err
+		}
+	}
+	return fileNames, nil
+}
+
+func (a Arguments) watchStrategy() string {
+	if a.WatchStrategy == "" {
+		return WatchStrategyFSNotify
+	}
+	return a.WatchStrategy
+}
+
+func (a Arguments) pollInterval() time.Duration {
+	if a.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return a.PollInterval
 }
 
 func Run(ctx context.Context, w io.Writer, args Arguments) (err error) {
@@ -46,5 +221,7 @@ func Run(ctx context.Context, w io.Writer, args Arguments) (err error) {
 		AddSource: args.LogLevel == "debug",
 		Level:     level,
 	}))
-	return NewGenerate(log, args).Run(ctx)
+	g := NewGenerate(log, args)
+	g.Out = w
+	return g.Run(ctx)
 }
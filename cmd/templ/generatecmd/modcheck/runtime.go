@@ -0,0 +1,214 @@
+package modcheck
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+const templModulePath = "github.com/a-h/templ"
+
+// Runtime is the templ module that generated code is compiled with.
+type Runtime struct {
+	// Version of the module, e.g. v0.2.663, or empty if the module is replaced
+	// with a local directory.
+	Version string
+	// Dir that contains the source of the module, or empty if it isn't available,
+	// e.g. because the module hasn't been downloaded.
+	Dir string
+	// symbols are the exported identifiers of the templ package.
+	symbols map[string]bool
+}
+
+// FindRuntime finds the templ module required by the go.mod file of the module
+// that contains dir, including replace directives. If version is set, the module
+// of that version is used instead.
+func FindRuntime(dir, version string) (rt *Runtime, err error) {
+	root, err := WalkUp(dir)
+	if err != nil {
+		return nil, err
+	}
+	modFile := filepath.Join(root, "go.mod")
+	m, err := os.ReadFile(modFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod file: %w", err)
+	}
+	mf, err := modfile.Parse(modFile, patchGoVersion(m), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod file: %w", err)
+	}
+	rt = &Runtime{}
+	path := templModulePath
+	switch {
+	case version != "":
+		rt.Version = version
+	case mf.Module != nil && mf.Module.Mod.Path == templModulePath:
+		// The go.mod file is for templ itself.
+		rt.Version, rt.Dir = templ.Version(), root
+	default:
+		for _, r := range mf.Require {
+			if r.Mod.Path == templModulePath {
+				rt.Version = r.Mod.Version
+			}
+		}
+		if rt.Version == "" {
+			return nil, fmt.Errorf("templ not found in go.mod file, run `go get github.com/a-h/templ` to install it")
+		}
+		for _, r := range mf.Replace {
+			if r.Old.Path != templModulePath || (r.Old.Version != "" && r.Old.Version != rt.Version) {
+				continue
+			}
+			if r.New.Version == "" {
+				// Replaced with a local directory.
+				rt.Version, rt.Dir = "", r.New.Path
+				if !filepath.IsAbs(rt.Dir) {
+					rt.Dir = filepath.Join(root, rt.Dir)
+				}
+				break
+			}
+			path, rt.Version = r.New.Path, r.New.Version
+		}
+	}
+	if rt.Dir == "" {
+		rt.Dir = moduleCacheDir(path, rt.Version)
+	}
+	if rt.Dir == "" {
+		return rt, nil
+	}
+	if rt.symbols, err = exportedSymbols(rt.Dir); err != nil {
+		return nil, err
+	}
+	if len(rt.symbols) == 0 {
+		rt.Dir = ""
+	}
+	return rt, nil
+}
+
+// moduleCacheDir returns the directory of the module in the module cache, or an
+// empty string if the module hasn't been downloaded.
+func moduleCacheDir(path, version string) string {
+	cache := os.Getenv("GOMODCACHE")
+	if cache == "" {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+			gopath = filepath.Join(home, "go")
+		}
+		cache = filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return ""
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(cache, escapedPath+"@"+escapedVersion)
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	return dir
+}
+
+// exportedSymbols returns the exported top-level identifiers of the Go package in
+// dir, ignoring build constraints.
+func exportedSymbols(dir string) (symbols map[string]bool, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templ module directory: %w", err)
+	}
+	symbols = map[string]bool{}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := goparser.ParseFile(fset, filepath.Join(dir, name), nil, goparser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse templ module file: %w", err)
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					symbols[d.Name.Name] = d.Name.IsExported()
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						symbols[s.Name.Name] = s.Name.IsExported()
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							symbols[n.Name] = n.IsExported()
+						}
+					}
+				}
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// Missing returns the identifiers of the templ package that the Go code uses, but
+// that the runtime doesn't have, sorted by name. If the source of the runtime
+// isn't available, nothing is missing.
+func (rt *Runtime) Missing(code []byte) (missing []string, err error) {
+	if rt.Dir == "" {
+		return nil, nil
+	}
+	f, err := goparser.ParseFile(token.NewFileSet(), "", code, goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	var name string
+	for _, imp := range f.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == templModulePath {
+			name = "templ"
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+	seen := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == name && !rt.symbols[sel.Sel.Name] && !seen[sel.Sel.Name] {
+			seen[sel.Sel.Name] = true
+			missing = append(missing, "templ."+sel.Sel.Name)
+		}
+		return true
+	})
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// String returns the version of the runtime, or its directory if it's replaced
+// with a local directory.
+func (rt *Runtime) String() string {
+	if rt.Version == "" {
+		return rt.Dir
+	}
+	return rt.Version
+}
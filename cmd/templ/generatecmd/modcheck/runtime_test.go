@@ -0,0 +1,94 @@
+package modcheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRuntime(t *testing.T) {
+	const code = `package main
+
+import "github.com/a-h/templ"
+
+func page() templ.Component {
+	return templ.Raw(templ.EscapeString("a") + templ.NewFeature())
+}
+`
+	writeFiles := func(t *testing.T, dir string, files map[string]string) {
+		t.Helper()
+		for name, contents := range files {
+			name = filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	runtimeFiles := map[string]string{
+		"runtime.go":      "package templ\n\ntype Component interface{}\n\nfunc Raw(s string) Component { return nil }\n\nfunc EscapeString(s string) string { return s }\n\nfunc unexported() {}\n",
+		"runtime_test.go": "package templ\n\nfunc NewFeature() string { return \"\" }\n",
+	}
+
+	t.Run("modules replaced with a local directory are checked", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"app/go.mod": "module app\n\ngo 1.21\n\nrequire github.com/a-h/templ v0.2.1\n\nreplace github.com/a-h/templ => ../templ\n",
+		})
+		writeFiles(t, filepath.Join(dir, "templ"), runtimeFiles)
+		rt, err := FindRuntime(filepath.Join(dir, "app"), "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		missing, err := rt.Missing([]byte(code))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]string{"templ.NewFeature"}, missing); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("modules are read from the module cache", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := filepath.Join(dir, "cache")
+		t.Setenv("GOMODCACHE", cache)
+		writeFiles(t, dir, map[string]string{
+			"app/go.mod": "module app\n\ngo 1.21\n\nrequire github.com/a-h/templ v0.2.1\n",
+		})
+		writeFiles(t, filepath.Join(cache, "github.com", "a-h", "templ@v0.2.2"), runtimeFiles)
+
+		rt, err := FindRuntime(filepath.Join(dir, "app"), "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rt.Version != "v0.2.1" || rt.Dir != "" {
+			t.Errorf("expected v0.2.1 to be unavailable, got %q in %q", rt.Version, rt.Dir)
+		}
+		if missing, _ := rt.Missing([]byte(code)); len(missing) != 0 {
+			t.Errorf("expected nothing to be missing when the source isn't available, got %v", missing)
+		}
+
+		rt, err = FindRuntime(filepath.Join(dir, "app"), "v0.2.2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		missing, err := rt.Missing([]byte(code))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]string{"templ.NewFeature"}, missing); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("modules without templ are an error", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"go.mod": "module app\n\ngo 1.21\n"})
+		if _, err := FindRuntime(dir, ""); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package modcheck
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// WorkspaceModules returns the directories of the modules used by the go.work
+// file in dir, or nil if dir doesn't contain a go.work file.
+func WorkspaceModules(dir string) (modules []string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	workFile := filepath.Join(dir, "go.work")
+	data, err := os.ReadFile(workFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.work file: %w", err)
+	}
+	wf, err := modfile.ParseWork(workFile, patchGoVersion(data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.work file: %w", err)
+	}
+	for _, use := range wf.Use {
+		moduleDir := use.Path
+		if !filepath.IsAbs(moduleDir) {
+			moduleDir = filepath.Join(dir, moduleDir)
+		}
+		modules = append(modules, filepath.Clean(moduleDir))
+	}
+	return modules, nil
+}
+
+// WatchRoots returns the directories to walk to find the templ files of dir. If dir
+// contains a go.work file, the modules of the workspace that are outside of dir
+// are included.
+func WatchRoots(dir string) (roots []string, err error) {
+	modules, err := WorkspaceModules(dir)
+	if err != nil {
+		return nil, err
+	}
+	roots = []string{dir}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for _, m := range modules {
+		if rel, err := filepath.Rel(abs, m); err == nil && filepath.IsLocal(rel) {
+			continue
+		}
+		roots = append(roots, m)
+	}
+	return roots, nil
+}
@@ -0,0 +1,52 @@
+package modcheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWorkspaceModules(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "repo")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("directories without a go.work file have no modules", func(t *testing.T) {
+		modules, err := WorkspaceModules(root)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if modules != nil {
+			t.Errorf("expected no modules, got %v", modules)
+		}
+	})
+
+	work := "go 1.22.1\n\nuse (\n\t.\n\t./services/api\n\t../shared\n)\n"
+	if err := os.WriteFile(filepath.Join(root, "go.work"), []byte(work), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("the modules of the workspace are returned", func(t *testing.T) {
+		modules, err := WorkspaceModules(root)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{root, filepath.Join(root, "services", "api"), filepath.Join(dir, "shared")}
+		if diff := cmp.Diff(expected, modules); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("modules outside of the directory are watched", func(t *testing.T) {
+		roots, err := WatchRoots(root)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{root, filepath.Join(dir, "shared")}
+		if diff := cmp.Diff(expected, roots); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package generatecmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/a-h/templ/cmd/templ/processor"
+)
+
+// EnableOutputDir writes the generated Go code to a directory that mirrors the
+// source tree, instead of next to each templ file, e.g. with an output directory
+// of internal/gen, the code generated from components/button.templ is written to
+// internal/gen/components/button_templ.go.
+func (h *FSEventHandler) EnableOutputDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of output directory %q: %w", dir, err)
+	}
+	if err = os.MkdirAll(abs, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory %q: %w", dir, err)
+	}
+	h.outputDir = abs
+	return nil
+}
+
+// outputFileName returns the name of a file generated from a templ file, with the
+// suffix, e.g. _templ.go, in place of the .templ extension, see
+// processor.OutputFileName. Templ files outside the root directory are
+// generated next to the templ file.
+func (h *FSEventHandler) outputFileName(templFileName, suffix string) string {
+	next := processor.OutputFileName(h.dir, "", templFileName, suffix)
+	if h.outputDir == "" {
+		return next
+	}
+	abs, err := filepath.Abs(templFileName)
+	if err != nil {
+		return next
+	}
+	if name := processor.OutputFileName(h.dir, h.outputDir, abs, suffix); name != processor.OutputFileName(h.dir, "", abs, suffix) {
+		return name
+	}
+	return next
+}
+
+// sourceFileName returns the name of the templ file that a generated file, with
+// the suffix, was generated from.
+func (h *FSEventHandler) sourceFileName(outputFileName, suffix string) string {
+	next := processor.SourceFileName(h.dir, "", outputFileName, suffix)
+	if h.outputDir == "" {
+		return next
+	}
+	abs, err := filepath.Abs(outputFileName)
+	if err != nil {
+		return next
+	}
+	if name := processor.SourceFileName(h.dir, h.outputDir, abs, suffix); name != processor.SourceFileName(h.dir, "", abs, suffix) {
+		return name
+	}
+	return next
+}
+
+// samePath returns true if the file names are the same file, once they're made
+// absolute.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// appendOutputDirRoot adds the output directory to the roots that are walked and
+// watched, if it's outside them, so that orphaned generated files are removed.
+func appendOutputDirRoot(roots []string, outputDir string) []string {
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(abs, outputDir); err == nil && filepath.IsLocal(rel) {
+			return roots
+		}
+	}
+	return append(roots, outputDir)
+}
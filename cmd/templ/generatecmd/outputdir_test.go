@@ -0,0 +1,91 @@
+package generatecmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "components"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	templFileName := filepath.Join(dir, "components", "button.templ")
+	if err := os.WriteFile(templFileName, []byte("package components\n\ntempl Button() {\n\t<button></button>\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	h := NewFSEventHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), dir, false, nil, false, false, false)
+	if err := h.EnableOutputDir(filepath.Join(dir, "internal", "gen")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generatedFileName := filepath.Join(dir, "internal", "gen", "components", "button_templ.go")
+	if diff := cmp.Diff(generatedFileName, h.outputFileName(templFileName, "_templ.go")); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(templFileName, h.sourceFileName(generatedFileName, "_templ.go")); diff != "" {
+		t.Error(diff)
+	}
+
+	ctx := context.Background()
+	t.Run("code is generated in the output directory", func(t *testing.T) {
+		if _, _, err := h.HandleEvent(ctx, fsnotify.Event{Name: templFileName, Op: fsnotify.Create}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(generatedFileName); err != nil {
+			t.Errorf("expected the generated file to exist: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "components", "button_templ.go")); !os.IsNotExist(err) {
+			t.Errorf("expected no generated file next to the templ file, got %v", err)
+		}
+	})
+	t.Run("generated files in the output directory are kept", func(t *testing.T) {
+		if _, _, err := h.HandleEvent(ctx, fsnotify.Event{Name: generatedFileName, Op: fsnotify.Create}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(generatedFileName); err != nil {
+			t.Errorf("expected the generated file to exist: %v", err)
+		}
+	})
+	t.Run("files generated next to the templ file before the output directory was set are removed", func(t *testing.T) {
+		staleFileName := filepath.Join(dir, "components", "button_templ.go")
+		if err := os.WriteFile(staleFileName, []byte("package components\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := h.HandleEvent(ctx, fsnotify.Event{Name: staleFileName, Op: fsnotify.Create}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(staleFileName); !os.IsNotExist(err) {
+			t.Errorf("expected the stale file to be removed, got %v", err)
+		}
+	})
+	t.Run("orphaned files in the output directory are removed", func(t *testing.T) {
+		if err := os.Remove(templFileName); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := h.HandleEvent(ctx, fsnotify.Event{Name: generatedFileName, Op: fsnotify.Create}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(generatedFileName); !os.IsNotExist(err) {
+			t.Errorf("expected the orphaned file to be removed, got %v", err)
+		}
+	})
+}
+
+func TestAppendOutputDirRoot(t *testing.T) {
+	dir := t.TempDir()
+	inside := filepath.Join(dir, "internal", "gen")
+	if diff := cmp.Diff([]string{dir}, appendOutputDirRoot([]string{dir}, inside)); diff != "" {
+		t.Errorf("expected an output directory inside a root not to be added: %s", diff)
+	}
+	outside := filepath.Join(filepath.Dir(dir), "gen")
+	if diff := cmp.Diff([]string{dir, outside}, appendOutputDirRoot([]string{dir}, outside)); diff != "" {
+		t.Errorf("expected an output directory outside the roots to be added: %s", diff)
+	}
+}
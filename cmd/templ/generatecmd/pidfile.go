@@ -0,0 +1,48 @@
+package generatecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/generatecmd/run"
+)
+
+// writePIDFile writes the pid of templ to the file, so that task runners can stop
+// templ, and the command it runs, with a signal. If the file contains the pid of
+// another templ process that is still running, an error is returned. Files left
+// behind by a templ process that has exited are overwritten.
+func writePIDFile(fileName string) error {
+	data, err := os.ReadFile(fileName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read pid file: %w", err)
+	}
+	if err == nil {
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err == nil && pid != os.Getpid() && run.IsRunning(pid) {
+			return fmt.Errorf("templ is already running with pid %d, stop it, or delete the pid file %q", pid, fileName)
+		}
+	}
+	if err = os.WriteFile(fileName, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+	return nil
+}
+
+// removePIDFile removes the pid file, unless it has been overwritten by another
+// templ process.
+func removePIDFile(fileName string) error {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		return nil
+	}
+	return os.Remove(fileName)
+}
@@ -0,0 +1,62 @@
+package generatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPIDFile(t *testing.T) {
+	t.Run("the pid is written to the file, and the file is removed", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "templ.pid")
+		if err := writePIDFile(fileName); err != nil {
+			t.Fatalf("failed to write pid file: %v", err)
+		}
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatalf("failed to read pid file: %v", err)
+		}
+		if got, want := strings.TrimSpace(string(data)), strconv.Itoa(os.Getpid()); got != want {
+			t.Errorf("expected pid %q, got %q", want, got)
+		}
+		if err = removePIDFile(fileName); err != nil {
+			t.Fatalf("failed to remove pid file: %v", err)
+		}
+		if _, err = os.Stat(fileName); !os.IsNotExist(err) {
+			t.Errorf("expected pid file to be removed, got %v", err)
+		}
+	})
+	t.Run("a pid file of a running process is an error", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "templ.pid")
+		// The parent of the test process is running.
+		if err := os.WriteFile(fileName, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+			t.Fatalf("failed to write pid file: %v", err)
+		}
+		if err := writePIDFile(fileName); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+	t.Run("a pid file of an exited process is overwritten", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "templ.pid")
+		if err := os.WriteFile(fileName, []byte("not a pid"), 0644); err != nil {
+			t.Fatalf("failed to write pid file: %v", err)
+		}
+		if err := writePIDFile(fileName); err != nil {
+			t.Fatalf("expected the pid file to be overwritten, got %v", err)
+		}
+	})
+	t.Run("a pid file overwritten by another process isn't removed", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "templ.pid")
+		if err := os.WriteFile(fileName, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+			t.Fatalf("failed to write pid file: %v", err)
+		}
+		if err := removePIDFile(fileName); err != nil {
+			t.Fatalf("failed to remove pid file: %v", err)
+		}
+		if _, err := os.Stat(fileName); err != nil {
+			t.Errorf("expected pid file to be kept, got %v", err)
+		}
+	})
+}
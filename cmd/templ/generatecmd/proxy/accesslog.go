@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultAccessLogExclude are the paths of static assets, which aren't included in
+// the access log by default.
+var DefaultAccessLogExclude = []string{"*.css", "*.js", "*.map", "*.png", "*.jpg", "*.jpeg", "*.gif", "*.svg", "*.ico", "*.webp", "*.woff", "*.woff2"}
+
+// accessLogEntry collects the details of a request as it's proxied.
+type accessLogEntry struct {
+	start time.Time
+	// upstream is the time taken for the target to respond.
+	upstream time.Duration
+	// scriptInjected is true if the reload script was inserted into the response.
+	scriptInjected bool
+	// static is true if the response is an HTML file from the static directory.
+	static bool
+}
+
+type accessLogEntryKey struct{}
+
+func accessLogEntryFromRequest(r *http.Request) *accessLogEntry {
+	if r == nil {
+		return nil
+	}
+	e, _ := r.Context().Value(accessLogEntryKey{}).(*accessLogEntry)
+	return e
+}
+
+// excludeFromAccessLog returns true if the path matches one of the patterns.
+// Patterns that contain a slash are matched against the whole path, e.g.
+// /static/*, and other patterns are matched against the last element of the
+// path, e.g. *.css.
+func excludeFromAccessLog(patterns []string, urlPath string) bool {
+	for _, pattern := range patterns {
+		name := path.Base(urlPath)
+		if strings.Contains(pattern, "/") {
+			name = urlPath
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// serveWithAccessLog serves the request, and logs it once the response has been
+// written.
+func (p *Handler) serveWithAccessLog(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == eventsPath && r.Method == http.MethodGet {
+		// The event stream stays open until the page is closed or reloaded, so it's
+		// logged when the reload script connects.
+		p.AccessLog.Info("Reload script connected", slog.String("path", r.URL.Path), slog.String("referer", r.Referer()))
+		p.serveHTTP(w, r)
+		return
+	}
+	e := &accessLogEntry{start: time.Now()}
+	r = r.WithContext(context.WithValue(r.Context(), accessLogEntryKey{}, e))
+	rw := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	p.serveHTTP(rw, r)
+	attrs := []any{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.RequestURI()),
+		slog.Int("status", rw.status),
+		slog.Int64("bytes", rw.bytes),
+		slog.Duration("duration", time.Since(e.start)),
+	}
+	if e.upstream > 0 {
+		attrs = append(attrs, slog.Duration("upstream", e.upstream), slog.Bool("reloadScript", e.scriptInjected))
+	}
+	if e.static {
+		attrs = append(attrs, slog.Bool("static", true), slog.Bool("reloadScript", e.scriptInjected))
+	}
+	p.AccessLog.Info("Proxy request", attrs...)
+}
+
+// responseRecorder records the status code and the number of bytes written.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rw *responseRecorder) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status, rw.wroteHeader = status, true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseRecorder) Write(b []byte) (n int, err error) {
+	rw.wroteHeader = true
+	n, err = rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap allows http.ResponseController to flush streamed responses.
+func (rw *responseRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestExcludeFromAccessLog(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/", expected: false},
+		{path: "/about", expected: false},
+		{path: "/static/app.css", expected: true},
+		{path: "/favicon.ico", expected: true},
+		{path: "/assets/logo.txt", expected: true},
+		{path: "/other/logo.txt", expected: false},
+	}
+	patterns := append(DefaultAccessLogExclude, "/assets/*")
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := excludeFromAccessLog(patterns, tt.path); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = io.WriteString(w, "<html><body>Page</body></html>")
+		case "/fragment":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = io.WriteString(w, "<div>Fragment</div>")
+		case "/app.css":
+			w.Header().Set("Content-Type", "text/css")
+			_, _ = io.WriteString(w, "body {}")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer target.Close()
+	u, err := url.Parse(target.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing URL: %v", err)
+	}
+	var buf bytes.Buffer
+	handler := New("127.0.0.1", 0, u)
+	handler.AccessLog = slog.New(slog.NewJSONHandler(&buf, nil))
+	handler.AccessLogExclude = DefaultAccessLogExclude
+	proxyServer := httptest.NewServer(handler)
+	defer proxyServer.Close()
+
+	for _, path := range []string{"/", "/fragment", "/app.css", "/missing"} {
+		resp, err := http.Get(proxyServer.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error getting %q: %v", path, err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	type entry struct {
+		Msg          string
+		Method       string
+		Path         string
+		Status       int
+		Bytes        int64
+		ReloadScript bool
+	}
+	var entries []entry
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e entry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("failed to decode log entry: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	expected := []entry{
+		{Msg: "Proxy request", Method: "GET", Path: "/", Status: 200, Bytes: int64(len("<html><body>Page" + scriptTag + "</body></html>")), ReloadScript: true},
+		{Msg: "Proxy request", Method: "GET", Path: "/fragment", Status: 200, Bytes: int64(len("<div>Fragment</div>")), ReloadScript: false},
+		{Msg: "Proxy request", Method: "GET", Path: "/missing", Status: 404, Bytes: int64(len("404 page not found\n")), ReloadScript: false},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expected), len(entries), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+}
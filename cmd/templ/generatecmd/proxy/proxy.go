@@ -3,19 +3,27 @@ package proxy
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"math"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/a-h/templ/cmd/templ/generatecmd/sse"
+	"github.com/a-h/templ/profile"
 
 	_ "embed"
 )
@@ -25,11 +33,44 @@ var script string
 
 const scriptTag = `<script src="/_templ/reload/script.js"></script>`
 
+const eventsPath = "/_templ/reload/events"
+
+// clientsPath lists the browsers connected to the events endpoint.
+const clientsPath = "/_templ/reload/clients"
+
 type Handler struct {
 	URL    string
 	Target *url.URL
 	p      *httputil.ReverseProxy
 	sse    *sse.Handler
+	// routes send requests with specific path prefixes to other targets, longest
+	// prefix first.
+	routes []route
+
+	// ExternalURL is the URL that browsers connect to the proxy with, if it's
+	// different to the URL that the proxy listens on, e.g. because the proxy runs
+	// in a container with a port mapping. The reload script connects to the events
+	// endpoint at this URL.
+	ExternalURL string
+	// AccessLog logs each request made through the proxy, except for those with
+	// paths that match the AccessLogExclude patterns. If nil, requests aren't
+	// logged.
+	AccessLog        *slog.Logger
+	AccessLogExclude []string
+	// StaticDir is a directory of files that the proxy serves itself, e.g. the
+	// output of a static site generator. The reload script is inserted into HTML
+	// files. Requests for files that don't exist are sent to the target.
+	StaticDir string
+	// Morph updates pages in place on reload, instead of reloading them, so that
+	// focus, scroll positions and open dialogs are kept. See Reload.
+	Morph bool
+	// Token, if set, must be sent in an Authorization: Bearer header with requests
+	// to send reload events, so that other machines on the network can't reload
+	// the browser.
+	Token string
+
+	profileMutex sync.Mutex
+	profile      *profile.Frame
 }
 
 func updateGzipResponse(r *http.Response) error {
@@ -43,6 +84,9 @@ func updateGzipResponse(r *http.Response) error {
 		return err
 	}
 	updated := insertScriptTagIntoBody(string(body))
+	if e := accessLogEntryFromRequest(r.Request); e != nil {
+		e.scriptInjected = updated != string(body)
+	}
 	var buf bytes.Buffer
 	gzw := gzip.NewWriter(&buf)
 	defer gzw.Close()
@@ -66,6 +110,9 @@ func updatePlainResponse(r *http.Response) error {
 		return err
 	}
 	updated := insertScriptTagIntoBody(string(body))
+	if e := accessLogEntryFromRequest(r.Request); e != nil {
+		e.scriptInjected = updated != string(body)
+	}
 	r.Body = io.NopCloser(strings.NewReader(updated))
 	r.ContentLength = int64(len(updated))
 	r.Header.Set("Content-Length", strconv.Itoa(len(updated)))
@@ -91,6 +138,23 @@ func modifyResponse(r *http.Response) error {
 }
 
 func New(bind string, port int, target *url.URL) *Handler {
+	host := bind
+	if ip := net.ParseIP(bind); ip != nil && ip.IsUnspecified() {
+		// The proxy listens on all interfaces, including the loopback interface.
+		host = "localhost"
+	}
+	h := &Handler{
+		URL:    fmt.Sprintf("http://%s", net.JoinHostPort(host, strconv.Itoa(port))),
+		Target: target,
+		sse:    sse.New(),
+	}
+	if target != nil {
+		h.p = h.newReverseProxy(target)
+	}
+	return h
+}
+
+func (h *Handler) newReverseProxy(target *url.URL) *httputil.ReverseProxy {
 	p := httputil.NewSingleHostReverseProxy(target)
 	p.ErrorLog = log.New(os.Stderr, "Proxy to target error: ", 0)
 	p.Transport = &roundTripper{
@@ -98,46 +162,287 @@ func New(bind string, port int, target *url.URL) *Handler {
 		initialDelay:    100 * time.Millisecond,
 		backoffExponent: 1.5,
 	}
-	p.ModifyResponse = modifyResponse
-	return &Handler{
-		URL:    fmt.Sprintf("http://%s:%d", bind, port),
-		Target: target,
-		p:      p,
-		sse:    sse.New(),
+	p.ModifyResponse = func(r *http.Response) error {
+		if e := accessLogEntryFromRequest(r.Request); e != nil {
+			e.upstream = time.Since(e.start)
+		}
+		h.recordProfile(r)
+		return modifyResponse(r)
+	}
+	return p
+}
+
+// route sends requests with paths that start with the prefix to another target.
+type route struct {
+	prefix string
+	target *url.URL
+	p      *httputil.ReverseProxy
+}
+
+// AddRoute sends requests with paths that start with the prefix, e.g. /api, to the
+// target, instead of the default target of the proxy. The path is matched on
+// segment boundaries, so /api matches /api and /api/users, but not /apis. The
+// path isn't changed. If the paths of more than one route match, the route with
+// the longest prefix is used.
+func (h *Handler) AddRoute(prefix string, target *url.URL) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	h.routes = append(h.routes, route{prefix: prefix, target: target, p: h.newReverseProxy(target)})
+	sort.SliceStable(h.routes, func(i, j int) bool {
+		return len(h.routes[i].prefix) > len(h.routes[j].prefix)
+	})
+}
+
+// reverseProxy returns the reverse proxy of the route that matches the path.
+func (h *Handler) reverseProxy(path string) *httputil.ReverseProxy {
+	for _, r := range h.routes {
+		if r.prefix == "/" || path == r.prefix || strings.HasPrefix(path, r.prefix+"/") {
+			return r.p
+		}
+	}
+	return h.p
+}
+
+// serveStatic serves the file at the path of the request from the static
+// directory, inserting the reload script into HTML files. Directories are served
+// with their index.html file, and paths without an extension, e.g. /about, are
+// also served from about.html or about/index.html. It returns false if there's no
+// file, so that the request can be sent to the target.
+func (p *Handler) serveStatic(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	fsys := http.Dir(p.StaticDir)
+	name := path.Clean("/" + r.URL.Path)
+	candidates := []string{name}
+	if strings.HasSuffix(r.URL.Path, "/") || name == "/" {
+		candidates = []string{path.Join(name, "index.html")}
+	} else if path.Ext(name) == "" {
+		candidates = append(candidates, name+".html", path.Join(name, "index.html"))
+	}
+	for _, candidate := range candidates {
+		f, err := fsys.Open(candidate)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+		if ext := path.Ext(candidate); ext != ".html" && ext != ".htm" {
+			http.ServeContent(w, r, candidate, fi.ModTime(), f)
+			return true
+		}
+		b, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, "failed to read file", http.StatusInternalServerError)
+			return true
+		}
+		body := insertScriptTagIntoBody(string(b))
+		if e := accessLogEntryFromRequest(r); e != nil {
+			e.static, e.scriptInjected = true, body != string(b)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		http.ServeContent(w, r, candidate, fi.ModTime(), strings.NewReader(body))
+		return true
+	}
+	return false
+}
+
+// maxProfileHeaderSize is the size of the largest render profile header that
+// is decoded, so that a large profile can't use up the memory of the proxy.
+const maxProfileHeaderSize = 1 << 20
+
+// recordProfile stores the render profile sent by the target, if there is one.
+func (p *Handler) recordProfile(r *http.Response) {
+	v := r.Header.Get(profile.HeaderName)
+	if v == "" {
+		return
+	}
+	r.Header.Del(profile.HeaderName)
+	if len(v) > maxProfileHeaderSize {
+		fmt.Printf("render profile is larger than %d bytes, not recorded\n", maxProfileHeaderSize)
+		return
+	}
+	f, err := profile.Decode(v)
+	if err != nil {
+		fmt.Printf("failed to decode render profile: %v\n", err)
+		return
+	}
+	p.profileMutex.Lock()
+	defer p.profileMutex.Unlock()
+	p.profile = f
+}
+
+const noProfileMessage = `No render profile has been recorded.
+
+To record render profiles, generate templates with the -instrument flag, wrap your HTTP handler with profile.Handler from the github.com/a-h/templ/profile package, then load a page through the proxy.
+`
+
+func (p *Handler) serveProfile(w http.ResponseWriter) {
+	p.profileMutex.Lock()
+	f := p.profile
+	p.profileMutex.Unlock()
+	if f == nil {
+		http.Error(w, noProfileMessage, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := f.WriteFlameGraph(w); err != nil {
+		fmt.Printf("failed to write render profile: %v\n", err)
 	}
 }
 
 func (p *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.AccessLog != nil && !excludeFromAccessLog(p.AccessLogExclude, r.URL.Path) {
+		p.serveWithAccessLog(w, r)
+		return
+	}
+	p.serveHTTP(w, r)
+}
+
+func (p *Handler) serveHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/_templ/reload/script.js" {
 		// Provides a script that reloads the page.
 		w.Header().Add("Content-Type", "text/javascript")
-		_, err := io.WriteString(w, script)
+		_, err := io.WriteString(w, p.script())
 		if err != nil {
 			fmt.Printf("failed to write script: %v\n", err)
 		}
 		return
 	}
-	if r.URL.Path == "/_templ/reload/events" {
+	if r.URL.Path == clientsPath {
+		// Lists the connected clients, to debug pages that don't reload.
+		p.serveClients(w)
+		return
+	}
+	if r.URL.Path == "/_templ/profile" {
+		// Provides a flame graph of the most recent render profile.
+		p.serveProfile(w)
+		return
+	}
+	if r.URL.Path == eventsPath {
 		switch r.Method {
 		case http.MethodGet:
+			if p.ExternalURL != "" {
+				// The page may be loaded from another origin than the external URL.
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
 			// Provides a list of messages including a reload message.
 			p.sse.ServeHTTP(w, r)
 			return
 		case http.MethodPost:
+			if p.Token != "" && !authorized(r, p.Token) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
 			// Send a reload message to all connected clients.
-			p.sse.Send("message", "reload")
+			p.Reload()
 			return
 		}
 		http.Error(w, "only GET or POST method allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	p.p.ServeHTTP(w, r)
+	if p.StaticDir != "" && p.serveStatic(w, r) {
+		return
+	}
+	rp := p.reverseProxy(r.URL.Path)
+	if rp == nil {
+		// There's no target, only static files.
+		http.NotFound(w, r)
+		return
+	}
+	rp.ServeHTTP(w, r)
+}
+
+func (p *Handler) serveClients(w http.ResponseWriter) {
+	clients := p.sse.Clients()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(struct {
+		Count   int          `json:"count"`
+		Clients []sse.Client `json:"clients"`
+	}{
+		Count:   len(clients),
+		Clients: clients,
+	})
+	if err != nil {
+		fmt.Printf("failed to write clients: %v\n", err)
+	}
+}
+
+// script returns the reload script, which connects to the events endpoint of the
+// external URL, if there is one, and reconnects if it misses three pings.
+func (p *Handler) script() string {
+	s := script
+	if p.ExternalURL != "" {
+		eventsURL := strings.TrimSuffix(p.ExternalURL, "/") + eventsPath
+		s = strings.Replace(s, strconv.Quote(eventsPath), strconv.Quote(eventsURL), 1)
+	}
+	timeout := 3 * p.sse.PingInterval.Milliseconds()
+	return strings.Replace(s, "const templ_reloadTimeout = 15000;", fmt.Sprintf("const templ_reloadTimeout = %d;", timeout), 1)
+}
+
+// SetPingInterval sets the time between the pings sent to browsers, which they use
+// to detect that the connection to the proxy has been lost. It must be called
+// before the proxy is started.
+func (p *Handler) SetPingInterval(d time.Duration) {
+	p.sse.PingInterval = d
 }
 
 func (p *Handler) SendSSE(eventType string, data string) {
 	p.sse.Send(eventType, data)
 }
 
+// Reload sends an event to the browsers connected to the proxy to reload the pages
+// at the URL paths, e.g. /about, or all pages if there are none. If Morph is set,
+// the reload script fetches the page again, and updates the DOM in place,
+// otherwise the page is reloaded.
+func (p *Handler) Reload(urls ...string) {
+	if !p.Morph {
+		p.sse.Send("message", "reload")
+		return
+	}
+	if len(urls) == 0 {
+		urls = []string{"*"}
+	}
+	data, err := json.Marshal(urls)
+	if err != nil {
+		fmt.Printf("failed to encode morph event: %v\n", err)
+		return
+	}
+	p.sse.Send("morph", string(data))
+}
+
+// StaticURLs returns the URL paths of the pages that serve the files of the
+// static directory, see StaticDir. The names are slash separated paths, relative
+// to the static directory. If any of the files aren't HTML, e.g. a stylesheet,
+// all pages may have changed, so no URL paths are returned.
+func StaticURLs(names []string) (urls []string) {
+	for _, name := range names {
+		name = "/" + strings.TrimPrefix(name, "/")
+		ext := path.Ext(name)
+		if ext != ".html" && ext != ".htm" {
+			return nil
+		}
+		urls = append(urls, name)
+		if path.Base(name) == "index.html" {
+			dir := path.Dir(name)
+			if dir != "/" {
+				urls = append(urls, dir)
+				dir += "/"
+			}
+			urls = append(urls, dir)
+			continue
+		}
+		urls = append(urls, strings.TrimSuffix(name, ext))
+	}
+	return urls
+}
+
 type roundTripper struct {
 	maxRetries      int
 	initialDelay    time.Duration
@@ -190,12 +495,57 @@ func (rt *roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
 	return nil, fmt.Errorf("max retries reached")
 }
 
+// NotifyHandler returns a handler that only accepts requests to send a reload
+// event to the clients of the proxy, so that the handler can listen on a port
+// that is forwarded to a remote build machine, without exposing the proxy. Each
+// request must have the token in an Authorization: Bearer header.
+func (p *Handler) NotifyHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != eventsPath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST method allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !authorized(r, token) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		p.Reload()
+	})
+}
+
+// authorized returns true if the request has the token in an Authorization:
+// Bearer header.
+func authorized(r *http.Request, token string) bool {
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
 func NotifyProxy(host string, port int) error {
-	urlStr := fmt.Sprintf("http://%s:%d/_templ/reload/events", host, port)
+	return NotifyProxyWithToken(host, port, "")
+}
+
+// NotifyProxyWithToken sends a reload event to the proxy, authenticating with the
+// token, if it isn't empty.
+func NotifyProxyWithToken(host string, port int, token string) error {
+	urlStr := fmt.Sprintf("http://%s%s", net.JoinHostPort(host, strconv.Itoa(port)), eventsPath)
 	req, err := http.NewRequest(http.MethodPost, urlStr, nil)
 	if err != nil {
 		return err
 	}
-	_, err = http.DefaultClient.Do(req)
-	return err
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to notify proxy: %s", resp.Status)
+	}
+	return nil
 }
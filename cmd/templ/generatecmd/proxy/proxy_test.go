@@ -5,16 +5,22 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/profile"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -305,4 +311,426 @@ func TestProxy(t *testing.T) {
 			t.Fatalf("timeout waiting for sse response")
 		}
 	})
+	t.Run("profile: the most recent render profile sent by the target is shown at /_templ/profile", func(t *testing.T) {
+		// Arrange.
+		target := httptest.NewServer(profile.Handler(templ.Handler(templ.Trace("pkg.Page", templ.Raw("<html><body>Page</body></html>")))))
+		defer target.Close()
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		proxyServer := httptest.NewServer(New("0.0.0.0", 0, u))
+		defer proxyServer.Close()
+
+		// Assert there is no profile until a page is loaded.
+		resp, err := http.Get(proxyServer.URL + "/_templ/profile")
+		if err != nil {
+			t.Fatalf("unexpected error getting profile: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d before a page is loaded, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+
+		// Act.
+		resp, err = http.Get(proxyServer.URL + "/page")
+		if err != nil {
+			t.Fatalf("unexpected error getting page: %v", err)
+		}
+		resp.Body.Close()
+		if resp.Header.Get(profile.HeaderName) != "" {
+			t.Errorf("expected the profile header to be removed by the proxy")
+		}
+		resp, err = http.Get(proxyServer.URL + "/_templ/profile")
+		if err != nil {
+			t.Fatalf("unexpected error getting profile: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading profile: %v", err)
+		}
+
+		// Assert.
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		for _, expected := range []string{"GET /page", "pkg.Page"} {
+			if !strings.Contains(string(body), expected) {
+				t.Errorf("expected profile to contain %q, got:\n%s", expected, string(body))
+			}
+		}
+	})
+	t.Run("profile: render profiles larger than the limit are not decoded", func(t *testing.T) {
+		// Arrange.
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(profile.HeaderName, strings.Repeat("A", maxProfileHeaderSize+4))
+			w.Write([]byte("<html><body>Page</body></html>"))
+		}))
+		defer target.Close()
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		proxyServer := httptest.NewServer(New("0.0.0.0", 0, u))
+		defer proxyServer.Close()
+
+		// Act.
+		resp, err := http.Get(proxyServer.URL + "/page")
+		if err != nil {
+			t.Fatalf("unexpected error getting page: %v", err)
+		}
+		resp.Body.Close()
+		resp, err = http.Get(proxyServer.URL + "/_templ/profile")
+		if err != nil {
+			t.Fatalf("unexpected error getting profile: %v", err)
+		}
+		resp.Body.Close()
+
+		// Assert.
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+	t.Run("external URL: the reload script connects to the events endpoint of the external URL", func(t *testing.T) {
+		u, err := url.Parse("http://localhost:8080")
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		handler := New("0.0.0.0", 7331, u)
+		if handler.URL != "http://localhost:7331" {
+			t.Errorf("expected the URL of a proxy listening on all interfaces to use localhost, got %q", handler.URL)
+		}
+		handler.ExternalURL = "http://localhost:8000/"
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		resp, err := http.Get(proxyServer.URL + "/_templ/reload/script.js")
+		if err != nil {
+			t.Fatalf("unexpected error getting script: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading script: %v", err)
+		}
+		expected := `new EventSource("http://localhost:8000/_templ/reload/events")`
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected script to contain %q, got:\n%s", expected, string(body))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyServer.URL+"/_templ/reload/events", nil)
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error getting events: %v", err)
+		}
+		defer resp.Body.Close()
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("expected events to be allowed from any origin, got %q", got)
+		}
+	})
+	t.Run("notify handler: reload events are only sent with the token", func(t *testing.T) {
+		u, err := url.Parse("http://localhost:8080")
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		handler := New("127.0.0.1", 0, u)
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+		notifyServer := httptest.NewServer(handler.NotifyHandler("secret"))
+		defer notifyServer.Close()
+		notifyURL, err := url.Parse(notifyServer.URL)
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		port, err := strconv.Atoi(notifyURL.Port())
+		if err != nil {
+			t.Fatalf("unexpected error parsing port: %v", err)
+		}
+
+		// The proxied site and the event stream aren't served by the notify handler.
+		for _, path := range []string{"/", "/_templ/reload/script.js", "/_templ/profile"} {
+			resp, err := http.Get(notifyServer.URL + path)
+			if err != nil {
+				t.Fatalf("unexpected error getting %q: %v", path, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("expected %q to return status %d, got %d", path, http.StatusNotFound, resp.StatusCode)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyServer.URL+"/_templ/reload/events", nil)
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error getting events: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if err = NotifyProxyWithToken(notifyURL.Hostname(), port, "wrong"); err == nil {
+			t.Error("expected an error notifying with the wrong token")
+		}
+		if err = NotifyProxyWithToken(notifyURL.Hostname(), port, "secret"); err != nil {
+			t.Fatalf("unexpected error notifying proxy: %v", err)
+		}
+		scanner := bufio.NewScanner(resp.Body)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+			if scanner.Text() == "data: reload" {
+				return
+			}
+		}
+		t.Errorf("expected a reload event, got:\n%s", strings.Join(lines, "\n"))
+	})
+	t.Run("morph: reload events contain the URL paths of the pages to update", func(t *testing.T) {
+		handler := New("127.0.0.1", 0, nil)
+		handler.Morph = true
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyServer.URL+"/_templ/reload/events", nil)
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error getting events: %v", err)
+		}
+		defer resp.Body.Close()
+
+		handler.Reload("/about", "/about.html")
+		handler.Reload()
+		scanner := bufio.NewScanner(resp.Body)
+		var events []string
+		for scanner.Scan() && len(events) < 2 {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok && data != "ping" {
+				events = append(events, data)
+			}
+		}
+		sort.Strings(events)
+		if diff := cmp.Diff([]string{`["*"]`, `["/about","/about.html"]`}, events); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("ping interval: the reload script reconnects after three missed pings", func(t *testing.T) {
+		handler := New("127.0.0.1", 0, nil)
+		handler.SetPingInterval(2 * time.Second)
+		if expected := "const templ_reloadTimeout = 6000;"; !strings.Contains(handler.script(), expected) {
+			t.Errorf("expected the script to contain %q", expected)
+		}
+	})
+	t.Run("clients: connected clients are listed, and removed once they disconnect", func(t *testing.T) {
+		handler := New("127.0.0.1", 0, nil)
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		getClients := func() (clients struct {
+			Count   int `json:"count"`
+			Clients []struct {
+				UserAgent string `json:"userAgent"`
+			} `json:"clients"`
+		}) {
+			resp, err := http.Get(proxyServer.URL + "/_templ/reload/clients")
+			if err != nil {
+				t.Fatalf("unexpected error getting clients: %v", err)
+			}
+			defer resp.Body.Close()
+			if err = json.NewDecoder(resp.Body).Decode(&clients); err != nil {
+				t.Fatalf("unexpected error decoding clients: %v", err)
+			}
+			return clients
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyServer.URL+"/_templ/reload/events", nil)
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+		req.Header.Set("User-Agent", "test-browser")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error getting events: %v", err)
+		}
+		defer resp.Body.Close()
+
+		clients := getClients()
+		if clients.Count != 1 || len(clients.Clients) != 1 || clients.Clients[0].UserAgent != "test-browser" {
+			t.Fatalf("expected the client to be listed, got %+v", clients)
+		}
+
+		cancel()
+		deadline := time.Now().Add(time.Second)
+		for getClients().Count != 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("expected the client to be removed after disconnecting")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+	t.Run("token: reload events are only sent to the proxy with the token, if one is set", func(t *testing.T) {
+		handler := New("127.0.0.1", 0, nil)
+		handler.Token = "secret"
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+		proxyURL, err := url.Parse(proxyServer.URL)
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		port, err := strconv.Atoi(proxyURL.Port())
+		if err != nil {
+			t.Fatalf("unexpected error parsing port: %v", err)
+		}
+		if err = NotifyProxy(proxyURL.Hostname(), port); err == nil {
+			t.Error("expected an error notifying without a token")
+		}
+		if err = NotifyProxyWithToken(proxyURL.Hostname(), port, "wrong"); err == nil {
+			t.Error("expected an error notifying with the wrong token")
+		}
+		if err = NotifyProxyWithToken(proxyURL.Hostname(), port, "secret"); err != nil {
+			t.Errorf("unexpected error notifying with the token: %v", err)
+		}
+	})
+	t.Run("routes: requests are sent to the target of the longest matching prefix", func(t *testing.T) {
+		newTarget := func(name string) *url.URL {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, name+" "+r.URL.Path)
+			}))
+			t.Cleanup(server.Close)
+			u, err := url.Parse(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error parsing URL: %v", err)
+			}
+			return u
+		}
+		handler := New("127.0.0.1", 0, newTarget("app"))
+		handler.AddRoute("/api", newTarget("api"))
+		handler.AddRoute("/api/admin/", newTarget("admin"))
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		tests := map[string]string{
+			"/":                "app /",
+			"/apis":            "app /apis",
+			"/api":             "api /api",
+			"/api/users":       "api /api/users",
+			"/api/admin":       "admin /api/admin",
+			"/api/admin/users": "admin /api/admin/users",
+		}
+		for path, expected := range tests {
+			resp, err := http.Get(proxyServer.URL + path)
+			if err != nil {
+				t.Fatalf("unexpected error getting %q: %v", path, err)
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatalf("unexpected error reading body: %v", err)
+			}
+			if string(body) != expected {
+				t.Errorf("%s: expected %q, got %q", path, expected, string(body))
+			}
+		}
+	})
+	t.Run("static dir: files are served, with the reload script inserted into HTML files", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"index.html":       "<html><body>Home</body></html>",
+			"about.html":       "<html><body>About</body></html>",
+			"blog/index.html":  "<html><body>Blog</body></html>",
+			"css/site.css":     "body {}",
+			"fallthrough.json": "{}",
+		}
+		for name, contents := range files {
+			fileName := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "target "+r.URL.Path)
+		}))
+		defer target.Close()
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		withTarget := New("127.0.0.1", 0, u)
+		withTarget.StaticDir = dir
+		staticOnly := New("127.0.0.1", 0, nil)
+		staticOnly.StaticDir = dir
+
+		tests := []struct {
+			handler        *Handler
+			path           string
+			expectedStatus int
+			expectedBody   string
+		}{
+			{handler: withTarget, path: "/", expectedStatus: http.StatusOK, expectedBody: "<html><body>Home" + scriptTag + "</body></html>"},
+			{handler: withTarget, path: "/about", expectedStatus: http.StatusOK, expectedBody: "<html><body>About" + scriptTag + "</body></html>"},
+			{handler: withTarget, path: "/blog/", expectedStatus: http.StatusOK, expectedBody: "<html><body>Blog" + scriptTag + "</body></html>"},
+			{handler: withTarget, path: "/css/site.css", expectedStatus: http.StatusOK, expectedBody: "body {}"},
+			{handler: withTarget, path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "target /api/users"},
+			{handler: withTarget, path: "/../../etc/passwd", expectedStatus: http.StatusOK, expectedBody: "target /../../etc/passwd"},
+			{handler: staticOnly, path: "/about", expectedStatus: http.StatusOK, expectedBody: "<html><body>About" + scriptTag + "</body></html>"},
+			{handler: staticOnly, path: "/api/users", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		}
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("%s: expected status %d, got %d", tt.path, tt.expectedStatus, w.Code)
+			}
+			if diff := cmp.Diff(tt.expectedBody, w.Body.String()); diff != "" {
+				t.Errorf("%s: unexpected body:\n%s", tt.path, diff)
+			}
+		}
+	})
+}
+
+func TestStaticURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "HTML files are served at their path, and without the extension",
+			input:    []string{"about.html", "blog/post.htm"},
+			expected: []string{"/about.html", "/about", "/blog/post.htm", "/blog/post"},
+		},
+		{
+			name:     "index files are served at their directory",
+			input:    []string{"index.html", "blog/index.html"},
+			expected: []string{"/index.html", "/", "/blog/index.html", "/blog", "/blog/"},
+		},
+		{
+			name:     "other files may change any page",
+			input:    []string{"about.html", "site.css"},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, StaticURLs(tt.input)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
 }
@@ -1,6 +1,146 @@
-let templ_reloadSrc = new EventSource("/_templ/reload/events");
-templ_reloadSrc.onmessage = (event) => {
-	if (event && event.data === "reload") {
+// The proxy sends a ping every 5 seconds by default. If three are missed, e.g.
+// because the computer was asleep, the connection is replaced.
+const templ_reloadTimeout = 15000;
+
+// Only one tab of each browser connects to the proxy for reload events, and passes
+// them on to the other tabs, since browsers limit the number of connections to
+// each host. If the tab is closed, another tab takes over.
+const templ_reloadChannel = typeof BroadcastChannel !== "undefined" ? new BroadcastChannel("templ-reload") : null;
+if (templ_reloadChannel) {
+	templ_reloadChannel.onmessage = (event) => templ_handleEvent(event.data.type, event.data.data);
+}
+if (templ_reloadChannel && navigator.locks) {
+	navigator.locks.request("templ-reload", () => new Promise(() => templ_connect()));
+} else {
+	templ_connect();
+}
+
+function templ_connect() {
+	const src = new EventSource("/_templ/reload/events");
+	let timeout;
+	const heartbeat = () => {
+		clearTimeout(timeout);
+		timeout = setTimeout(() => {
+			src.close();
+			templ_connect();
+		}, templ_reloadTimeout);
+	};
+	const forward = (event) => {
+		heartbeat();
+		if (event.data === "ping") {
+			return;
+		}
+		// Other tabs are told first, since this tab may reload.
+		if (templ_reloadChannel) {
+			templ_reloadChannel.postMessage({ type: event.type, data: event.data });
+		}
+		templ_handleEvent(event.type, event.data);
+	};
+	heartbeat();
+	src.onmessage = forward;
+	src.addEventListener("morph", forward);
+}
+
+function templ_handleEvent(type, data) {
+	if (type === "message" && data === "reload") {
 		window.location.reload();
 	}
-};
+	if (type === "morph") {
+		templ_morph(JSON.parse(data));
+	}
+}
+
+// Morph events contain the URL paths of the pages that changed, or "*" for all
+// pages. The page is fetched again, and the DOM is updated in place, so that
+// focus, scroll positions, form values and open dialogs are kept.
+async function templ_morph(urls) {
+	if (!urls.includes("*") && !urls.includes(window.location.pathname)) {
+		return;
+	}
+	try {
+		const resp = await fetch(window.location.href, { headers: { "Accept": "text/html" } });
+		if (!resp.ok || !(resp.headers.get("Content-Type") || "").includes("text/html")) {
+			throw new Error(`unexpected response: ${resp.status}`);
+		}
+		const doc = new DOMParser().parseFromString(await resp.text(), "text/html");
+		document.title = doc.title;
+		templ_morphNode(document.body, doc.body);
+		templ_refreshStylesheets();
+	} catch (err) {
+		console.error("templ: failed to update page, reloading", err);
+		window.location.reload();
+	}
+}
+
+function templ_morphNode(from, to) {
+	if (from.nodeType !== to.nodeType || from.nodeName !== to.nodeName || (from.id || "") !== (to.id || "")) {
+		from.replaceWith(document.importNode(to, true));
+		return;
+	}
+	if (from.nodeType === Node.TEXT_NODE || from.nodeType === Node.COMMENT_NODE) {
+		if (from.nodeValue !== to.nodeValue) {
+			from.nodeValue = to.nodeValue;
+		}
+		return;
+	}
+	if (from.nodeType !== Node.ELEMENT_NODE) {
+		return;
+	}
+	templ_morphAttributes(from, to);
+	templ_morphChildren(from, to);
+}
+
+function templ_morphAttributes(from, to) {
+	for (const attr of Array.from(from.attributes)) {
+		// Dialogs and details elements opened by the user stay open.
+		if (attr.name === "open" && (from.nodeName === "DIALOG" || from.nodeName === "DETAILS")) {
+			continue;
+		}
+		if (!to.hasAttribute(attr.name)) {
+			from.removeAttribute(attr.name);
+		}
+	}
+	for (const attr of Array.from(to.attributes)) {
+		if (from.getAttribute(attr.name) !== attr.value) {
+			from.setAttribute(attr.name, attr.value);
+		}
+	}
+}
+
+function templ_morphChildren(from, to) {
+	const toChildren = Array.from(to.childNodes);
+	for (let i = 0; i < toChildren.length; i++) {
+		const child = toChildren[i];
+		let existing = from.childNodes[i];
+		// Elements with an id are matched by id, even if they've moved.
+		if (child.id) {
+			const match = Array.from(from.childNodes).find((n) => n.id === child.id);
+			if (match && match !== existing) {
+				from.insertBefore(match, existing || null);
+				existing = match;
+			}
+		}
+		if (!existing) {
+			from.appendChild(document.importNode(child, true));
+			continue;
+		}
+		templ_morphNode(existing, child);
+	}
+	while (from.childNodes.length > toChildren.length) {
+		from.lastChild.remove();
+	}
+}
+
+// Stylesheets aren't part of the body, so they're loaded again in case they've
+// changed, e.g. because Tailwind has regenerated them.
+function templ_refreshStylesheets() {
+	for (const link of Array.from(document.querySelectorAll('link[rel="stylesheet"]'))) {
+		const url = new URL(link.href);
+		url.searchParams.set("templ_reload", Date.now().toString());
+		const next = link.cloneNode();
+		next.href = url.toString();
+		next.onload = () => link.remove();
+		next.onerror = () => next.remove();
+		link.after(next);
+	}
+}
@@ -0,0 +1,47 @@
+//go:build windows
+
+package run
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var job struct {
+	once   sync.Once
+	handle windows.Handle
+	err    error
+}
+
+// killOnExit adds the process to a job object that is closed by Windows when templ
+// exits, for any reason. Closing the job object kills the process, and any child
+// processes it starts, so that the command isn't orphaned if templ is killed.
+func killOnExit(pid int) error {
+	job.once.Do(func() {
+		job.handle, job.err = windows.CreateJobObject(nil, nil)
+		if job.err != nil {
+			return
+		}
+		info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+			BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+				LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+			},
+		}
+		_, job.err = windows.SetInformationJobObject(job.handle, windows.JobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)))
+	})
+	if job.err != nil {
+		return fmt.Errorf("failed to create job object: %w", job.err)
+	}
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(process)
+	if err = windows.AssignProcessToJobObject(job.handle, process); err != nil {
+		return fmt.Errorf("failed to add process %d to job object: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package run
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// IsRunning returns true if a process with the given pid is running.
+func IsRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// ParentExited returns a channel that is closed when the parent process of templ
+// exits, e.g. because the terminal was closed, or the task runner that started
+// templ was killed. When the parent exits, the process is re-parented, so the
+// parent pid changes.
+func ParentExited(ctx context.Context) <-chan struct{} {
+	exited := make(chan struct{})
+	ppid := os.Getppid()
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if os.Getppid() != ppid {
+					close(exited)
+					return
+				}
+			}
+		}
+	}()
+	return exited
+}
@@ -0,0 +1,52 @@
+//go:build windows
+
+package run
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const stillActive = 259
+
+// IsRunning returns true if a process with the given pid is running.
+func IsRunning(pid int) bool {
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(process)
+	var code uint32
+	if err = windows.GetExitCodeProcess(process, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// ParentExited returns a channel that is closed when the parent process of templ
+// exits, e.g. because the terminal was closed, or the task runner that started
+// templ was killed. If the parent process can't be opened, the channel is never
+// closed.
+func ParentExited(ctx context.Context) <-chan struct{} {
+	exited := make(chan struct{})
+	parent, err := windows.OpenProcess(windows.SYNCHRONIZE, false, uint32(os.Getppid()))
+	if err != nil {
+		return exited
+	}
+	go func() {
+		defer windows.CloseHandle(parent)
+		for ctx.Err() == nil {
+			event, err := windows.WaitForSingleObject(parent, 1000)
+			if err != nil {
+				return
+			}
+			if event == windows.WAIT_OBJECT_0 {
+				close(exited)
+				return
+			}
+		}
+	}()
+	return exited
+}
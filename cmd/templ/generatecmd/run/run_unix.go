@@ -53,7 +53,7 @@ func Run(ctx context.Context, workingDir, input string) (cmd *exec.Cmd, err erro
 	cmd.Dir = workingDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.SysProcAttr = sysProcAttr()
 	running[input] = cmd
 	err = cmd.Start()
 	return
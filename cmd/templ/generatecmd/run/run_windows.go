@@ -64,6 +64,9 @@ func Run(ctx context.Context, workingDir, input string) (cmd *exec.Cmd, err erro
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	running[input] = cmd
-	err = cmd.Start()
-	return
+	if err = cmd.Start(); err != nil {
+		return cmd, err
+	}
+	err = killOnExit(cmd.Process.Pid)
+	return cmd, err
 }
@@ -0,0 +1,12 @@
+//go:build linux
+
+package run
+
+import "syscall"
+
+// sysProcAttr starts the command in a new process group, so that the command and
+// its child processes can be killed together. The command is also killed if templ
+// exits without killing it, e.g. because templ was sent SIGKILL.
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+}
@@ -0,0 +1,11 @@
+//go:build unix && !linux
+
+package run
+
+import "syscall"
+
+// sysProcAttr starts the command in a new process group, so that the command and
+// its child processes can be killed together.
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
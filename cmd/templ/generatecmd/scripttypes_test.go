@@ -0,0 +1,52 @@
+package generatecmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestScriptTypes(t *testing.T) {
+	dir := t.TempDir()
+	templFileName := filepath.Join(dir, "greet.templ")
+	dtsFileName := filepath.Join(dir, "greet_templ.d.ts")
+	h := NewFSEventHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), dir, false, nil, false, false, false)
+	h.EnableScriptTypes()
+	generate := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(templFileName, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := h.HandleEvent(context.Background(), fsnotify.Event{Name: templFileName, Op: fsnotify.Write}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	generate("package main\n\nscript greet(name string) {\n\tconsole.log(name);\n}\n")
+	if _, err := os.Stat(dtsFileName); err != nil {
+		t.Fatalf("expected the declaration file to be written: %v", err)
+	}
+	t.Run("the declaration file is removed when there is nothing to declare", func(t *testing.T) {
+		generate("package main\n\ntempl Greet() {\n\t<p>Hello</p>\n}\n")
+		if _, err := os.Stat(dtsFileName); !os.IsNotExist(err) {
+			t.Errorf("expected the declaration file to be removed, got %v", err)
+		}
+	})
+	t.Run("orphaned declaration files are removed", func(t *testing.T) {
+		orphanFileName := filepath.Join(dir, "removed_templ.d.ts")
+		if err := os.WriteFile(orphanFileName, []byte("declare function x(): void;\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := h.HandleEvent(context.Background(), fsnotify.Event{Name: orphanFileName, Op: fsnotify.Create}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(orphanFileName); !os.IsNotExist(err) {
+			t.Errorf("expected the orphaned declaration file to be removed, got %v", err)
+		}
+	})
+}
@@ -2,24 +2,52 @@ package sse
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+const (
+	// DefaultPingInterval is the default time between ping events, which browsers
+	// use to detect that the connection has been lost.
+	DefaultPingInterval = time.Second * 5
+	// DefaultWriteTimeout is the default maximum time taken to write an event to a
+	// client, after which the connection is assumed to be dead, and is closed.
+	DefaultWriteTimeout = time.Second * 10
+	// DefaultQueueSize is the default number of events that are queued for each
+	// client.
+	DefaultQueueSize = 16
+)
+
 func New() *Handler {
 	return &Handler{
-		m:        new(sync.Mutex),
-		requests: map[int64]chan event{},
+		PingInterval: DefaultPingInterval,
+		WriteTimeout: DefaultWriteTimeout,
+		QueueSize:    DefaultQueueSize,
+		m:            new(sync.Mutex),
+		requests:     map[int64]*client{},
 	}
 }
 
+// Handler sends events to connected clients. Each client has a queue of events,
+// so that a client that is slow to read events doesn't delay the others. Clients
+// that can't keep up, because their queue is full, are disconnected, and are
+// expected to reconnect. The fields must be set before clients connect.
 type Handler struct {
+	// PingInterval is the time between ping events.
+	PingInterval time.Duration
+	// WriteTimeout is the maximum time taken to write an event to a client.
+	WriteTimeout time.Duration
+	// QueueSize is the number of events that are queued for each client.
+	QueueSize int
+
 	m        *sync.Mutex
 	counter  int64
-	requests map[int64]chan event
+	requests map[int64]*client
 }
 
 type event struct {
@@ -27,19 +55,60 @@ type event struct {
 	Data string
 }
 
+// Client is a connected client.
+type Client struct {
+	ID          int64     `json:"id"`
+	RemoteAddr  string    `json:"remoteAddr"`
+	UserAgent   string    `json:"userAgent,omitempty"`
+	Referer     string    `json:"referer,omitempty"`
+	ConnectedAt time.Time `json:"connectedAt"`
+	// LastPing is the time that the last ping event was written to the client.
+	LastPing time.Time `json:"lastPing"`
+}
+
+type client struct {
+	info   Client
+	events chan event
+	// evicted is closed if the queue of events is full.
+	evicted chan struct{}
+}
+
+// evict disconnects the client. It must be called with the lock held.
+func (c *client) evict() {
+	select {
+	case <-c.evicted:
+	default:
+		close(c.evicted)
+	}
+}
+
 // Send an event to all connected clients.
 func (s *Handler) Send(eventType string, data string) {
 	s.m.Lock()
 	defer s.m.Unlock()
-	for _, f := range s.requests {
-		f := f
-		go func(f chan event) {
-			f <- event{
-				Type: eventType,
-				Data: data,
-			}
-		}(f)
+	for _, c := range s.requests {
+		select {
+		case c.events <- event{Type: eventType, Data: data}:
+		default:
+			// The client isn't keeping up, so it's disconnected, and will receive
+			// new events once it reconnects.
+			c.evict()
+		}
+	}
+}
+
+// Clients returns the connected clients, in the order that they connected.
+func (s *Handler) Clients() (clients []Client) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	clients = make([]Client, 0, len(s.requests))
+	for _, c := range s.requests {
+		clients = append(clients, c.info)
 	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ID < clients[j].ID
+	})
+	return clients
 }
 
 func (s *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -50,35 +119,59 @@ func (s *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 
 	id := atomic.AddInt64(&s.counter, 1)
+	c := &client{
+		info: Client{
+			ID:          id,
+			RemoteAddr:  r.RemoteAddr,
+			UserAgent:   r.UserAgent(),
+			Referer:     r.Referer(),
+			ConnectedAt: time.Now(),
+		},
+		events:  make(chan event, s.QueueSize),
+		evicted: make(chan struct{}),
+	}
 	s.m.Lock()
-	events := make(chan event)
-	s.requests[id] = events
+	s.requests[id] = c
 	s.m.Unlock()
 	defer func() {
 		s.m.Lock()
 		defer s.m.Unlock()
 		delete(s.requests, id)
-		close(events)
 	}()
 
+	rc := http.NewResponseController(w)
+	write := func(e event) error {
+		// A write to a client that has gone away without closing the connection,
+		// e.g. a laptop that has been put to sleep, blocks until the deadline.
+		if err := rc.SetWriteDeadline(time.Now().Add(s.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, e.Data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
 	timer := time.NewTimer(0)
-loop:
+	defer timer.Stop()
 	for {
 		select {
 		case <-timer.C:
-			if _, err := fmt.Fprintf(w, "event: message\ndata: ping\n\n"); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+			if err := write(event{Type: "message", Data: "ping"}); err != nil {
 				return
 			}
-			timer.Reset(time.Second * 5)
-		case e := <-events:
-			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, e.Data); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.m.Lock()
+			c.info.LastPing = time.Now()
+			s.m.Unlock()
+			timer.Reset(s.PingInterval)
+		case e := <-c.events:
+			if err := write(e); err != nil {
 				return
 			}
+		case <-c.evicted:
+			return
 		case <-r.Context().Done():
-			break loop
+			return
 		}
-		w.(http.Flusher).Flush()
 	}
 }
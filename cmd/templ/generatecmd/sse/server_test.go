@@ -0,0 +1,97 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stalledWriter blocks writes until it's released, like a browser tab that has
+// stopped reading events.
+type stalledWriter struct {
+	header  http.Header
+	release chan struct{}
+}
+
+func (w *stalledWriter) Header() http.Header { return w.header }
+func (w *stalledWriter) WriteHeader(int)     {}
+func (w *stalledWriter) Flush()              {}
+func (w *stalledWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return len(p), nil
+}
+
+// recordingWriter records the data of the events written to it.
+type recordingWriter struct {
+	header http.Header
+	m      sync.Mutex
+	data   []string
+}
+
+func (w *recordingWriter) Header() http.Header { return w.header }
+func (w *recordingWriter) WriteHeader(int)     {}
+func (w *recordingWriter) Flush()              {}
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	for _, line := range strings.Split(string(p), "\n") {
+		if data, ok := strings.CutPrefix(line, "data: "); ok && data != "ping" {
+			w.data = append(w.data, data)
+		}
+	}
+	return len(p), nil
+}
+
+func (w *recordingWriter) count() int {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return len(w.data)
+}
+
+func TestSlowClientsAreEvicted(t *testing.T) {
+	s := New()
+	s.QueueSize = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stalled := &stalledWriter{header: http.Header{}, release: make(chan struct{})}
+	stalledDone := make(chan struct{})
+	go func() {
+		defer close(stalledDone)
+		s.ServeHTTP(stalled, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+	}()
+	recorder := &recordingWriter{header: http.Header{}}
+	go s.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+	for len(s.Clients()) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Sending doesn't block on the stalled client, and the other client receives
+	// every event.
+	for i := 0; i < 10; i++ {
+		s.Send("message", "reload")
+		time.Sleep(time.Millisecond)
+	}
+	deadline := time.Now().Add(time.Second)
+	for recorder.count() != 10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 10 events, got %d", recorder.count())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The stalled client is disconnected once its write returns.
+	close(stalled.release)
+	select {
+	case <-stalledDone:
+	case <-time.After(time.Second):
+		t.Fatal("expected the stalled client to be disconnected")
+	}
+	if n := len(s.Clients()); n != 1 {
+		t.Errorf("expected 1 client, got %d", n)
+	}
+}
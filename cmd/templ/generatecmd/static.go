@@ -0,0 +1,84 @@
+package generatecmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// watchStaticDir scans the directory at each interval, and calls reload with the
+// names of the files that changed, once its files have changed, and then stopped
+// changing for an interval, e.g. because a static site generator has finished
+// writing them. Names are slash separated, and relative to the directory.
+func watchStaticDir(ctx context.Context, dir string, interval time.Duration, reload func(changed []string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	prev := staticDirFiles(dir)
+	changed := map[string]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		files := staticDirFiles(dir)
+		if names := changedFiles(prev, files); len(names) > 0 {
+			prev = files
+			for _, name := range names {
+				changed[name] = true
+			}
+			continue
+		}
+		if len(changed) > 0 {
+			names := make([]string, 0, len(changed))
+			for name := range changed {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			changed = map[string]bool{}
+			reload(names)
+		}
+	}
+}
+
+// staticDirFiles returns the sizes and modification times of the files in the
+// directory, by name.
+func staticDirFiles(dir string) (files map[string]string) {
+	files = map[string]string{}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		files[filepath.ToSlash(name)] = fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return files
+}
+
+// changedFiles returns the sorted names of the files that were created, updated
+// or removed.
+func changedFiles(prev, next map[string]string) (names []string) {
+	for name, state := range next {
+		if prev[name] != state {
+			names = append(names, name)
+		}
+	}
+	for name := range prev {
+		if _, ok := next[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,55 @@
+package generatecmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWatchStaticDir(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan []string, 10)
+	go watchStaticDir(ctx, dir, 20*time.Millisecond, func(changed []string) {
+		reloads <- changed
+	})
+	// Wait for the initial scan.
+	time.Sleep(50 * time.Millisecond)
+
+	// Several writes in quick succession, e.g. by a static site generator, cause a
+	// single reload once they have finished.
+	if err := os.Mkdir(filepath.Join(dir, "blog"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"index.html", "about.html", "blog/index.html"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte{byte(i)}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case changed := <-reloads:
+		if diff := cmp.Diff([]string{"about.html", "blog/index.html", "index.html"}, changed); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a reload")
+	}
+	select {
+	case <-reloads:
+		t.Error("expected a single reload")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	prev := map[string]string{"a.html": "1 1", "b.html": "1 1", "c.html": "1 1"}
+	next := map[string]string{"a.html": "1 1", "b.html": "2 2", "d.html": "1 1"}
+	if diff := cmp.Diff([]string{"b.html", "c.html", "d.html"}, changedFiles(prev, next)); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,71 @@
+package generatecmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestStaticChunks(t *testing.T) {
+	dir := t.TempDir()
+	header := `<header><nav><a href="/">Home</a><a href="/about">About</a><a href="/contact">Contact</a></nav></header>`
+	homeFileName := filepath.Join(dir, "home.templ")
+	aboutFileName := filepath.Join(dir, "about.templ")
+	chunksFileName := filepath.Join(dir, staticChunksFileName)
+	write := func(fileName, body string) {
+		t.Helper()
+		if err := os.WriteFile(fileName, []byte("package main\n\ntempl "+strings.TrimSuffix(filepath.Base(fileName), ".templ")+"() {\n\t"+body+"\n}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Each file is generated by a new handler that reads the existing chunks, as
+	// templ generate -f does.
+	generate := func(fileName string) {
+		t.Helper()
+		h := NewFSEventHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), dir, false, nil, false, false, false)
+		h.EnableStaticChunks(true)
+		if _, _, err := h.HandleEvent(context.Background(), fsnotify.Event{Name: fileName, Op: fsnotify.Write}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	chunkCount := func() int {
+		t.Helper()
+		src, err := os.ReadFile(chunksFileName)
+		if os.IsNotExist(err) {
+			return 0
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(src), "templ_7745c5c3_Static_")
+	}
+
+	write(homeFileName, header)
+	write(aboutFileName, header)
+	generate(homeFileName)
+	generate(aboutFileName)
+	if n := chunkCount(); n != 1 {
+		t.Fatalf("expected the shared header to be written as 1 chunk, got %d", n)
+	}
+
+	t.Run("chunks that are still used by other files are kept", func(t *testing.T) {
+		write(homeFileName, "<p>Home</p>")
+		generate(homeFileName)
+		if n := chunkCount(); n != 1 {
+			t.Errorf("expected 1 chunk, got %d", n)
+		}
+	})
+	t.Run("chunks that are no longer used are removed", func(t *testing.T) {
+		write(aboutFileName, "<p>About</p>")
+		generate(aboutFileName)
+		if _, err := os.Stat(chunksFileName); !os.IsNotExist(err) {
+			t.Errorf("expected the static chunks file to be removed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package watcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Poll creates a watcher that scans the file tree rooted at path for changes at
+// each interval, instead of relying on file system events. File system events
+// aren't received for files on network drives, WSL2 mounts of Windows drives, or
+// Docker bind mounts, but polling works everywhere.
+//
+// If hash is true, files are compared by the hash of their contents, instead of
+// their modification time and size, for file systems where modification times
+// aren't reliable.
+func Poll(
+	ctx context.Context,
+	path string,
+	filter Filter,
+	interval time.Duration,
+	hash bool,
+	out chan fsnotify.Event,
+	errors chan error,
+) (w *PollingWatcher, err error) {
+	w = &PollingWatcher{
+		ctx:      ctx,
+		filter:   filter,
+		interval: interval,
+		hash:     hash,
+		Events:   out,
+		Errors:   errors,
+		files:    map[string]fileState{},
+		done:     make(chan struct{}),
+	}
+	if err = w.Add(path); err != nil {
+		return nil, err
+	}
+	go w.loop()
+	return w, nil
+}
+
+type PollingWatcher struct {
+	ctx       context.Context
+	filter    Filter
+	interval  time.Duration
+	hash      bool
+	Events    chan fsnotify.Event
+	Errors    chan error
+	m         sync.Mutex
+	roots     []string
+	files     map[string]fileState
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// Add the file tree rooted at dir to the watcher. Files that already exist don't
+// produce events.
+func (w *PollingWatcher) Add(dir string) error {
+	files := map[string]fileState{}
+	if err := scan(dir, w.filter, w.hash, files); err != nil {
+		return err
+	}
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.roots = append(w.roots, dir)
+	for name, state := range files {
+		w.files[name] = state
+	}
+	return nil
+}
+
+func (w *PollingWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	return nil
+}
+
+func (w *PollingWatcher) loop() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-w.done:
+			return
+		case <-ticker.C:
+			for _, event := range w.poll() {
+				select {
+				case w.Events <- event:
+				case <-w.ctx.Done():
+					return
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// poll scans the watched file trees, and returns an event for each file that has
+// been created, written or removed since the previous scan.
+func (w *PollingWatcher) poll() (events []fsnotify.Event) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	files := map[string]fileState{}
+	for _, root := range w.roots {
+		if err := scan(root, w.filter, w.hash, files); err != nil {
+			select {
+			case w.Errors <- err:
+			case <-w.ctx.Done():
+			}
+			return nil
+		}
+	}
+	for name, state := range files {
+		prev, ok := w.files[name]
+		if !ok {
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Create})
+			continue
+		}
+		if w.changed(prev, state) {
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Write})
+		}
+	}
+	for name := range w.files {
+		if _, ok := files[name]; !ok {
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Remove})
+		}
+	}
+	w.files = files
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+	return events
+}
+
+func (w *PollingWatcher) changed(prev, next fileState) bool {
+	if w.hash {
+		return prev.hash != next.hash
+	}
+	return !prev.modTime.Equal(next.modTime) || prev.size != next.size
+}
+
+// scan adds the state of the templ related files in the file tree rooted at dir
+// to files.
+func scan(dir string, filter Filter, hash bool, files map[string]fileState) error {
+	return filter.walk(dir, dir, func(path string, isDir bool) error {
+		if isDir || !filter.includeFile(path) {
+			return nil
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			// The file was removed during the scan.
+			return nil
+		}
+		state := fileState{
+			modTime: fi.ModTime(),
+			size:    fi.Size(),
+		}
+		if hash {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			state.hash = sha256.Sum256(data)
+		}
+		files[path] = state
+		return nil
+	})
+}
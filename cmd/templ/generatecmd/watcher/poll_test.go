@@ -0,0 +1,131 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestPoll(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.templ")
+	if err := os.WriteFile(existing, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan fsnotify.Event)
+	w, err := Poll(ctx, dir, Filter{}, 10*time.Millisecond, false, events, make(chan error))
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	expect := func(name string, op fsnotify.Op) {
+		t.Helper()
+		select {
+		case event := <-events:
+			if event.Name != name || event.Op != op {
+				t.Errorf("expected %v %q, got %v %q", op, name, event.Op, event.Name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %v %q, got no event", op, name)
+		}
+	}
+
+	created := filepath.Join(dir, "sub", "created.templ")
+	if err := os.MkdirAll(filepath.Dir(created), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(created, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expect(created, fsnotify.Create)
+
+	if err := os.WriteFile(existing, []byte("ab"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expect(existing, fsnotify.Write)
+
+	if err := os.Remove(existing); err != nil {
+		t.Fatal(err)
+	}
+	expect(existing, fsnotify.Remove)
+
+	// Other files are ignored.
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("expected no event, got %v %q", event.Op, event.Name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPollHash(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.templ")
+	if err := os.WriteFile(name, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w := &PollingWatcher{
+		ctx:   context.Background(),
+		hash:  true,
+		files: map[string]fileState{},
+	}
+	if err := w.Add(dir); err != nil {
+		t.Fatalf("failed to add dir: %v", err)
+	}
+
+	// Changing the modification time doesn't change the hash.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(name, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if events := w.poll(); len(events) != 0 {
+		t.Errorf("expected no events, got %v", events)
+	}
+
+	if err := os.WriteFile(name, []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if events := w.poll(); len(events) != 1 || events[0].Op != fsnotify.Write {
+		t.Errorf("expected a write event, got %v", events)
+	}
+}
+
+func TestMissedEvents(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.templ")
+	if err := os.WriteFile(name, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The watcher doesn't receive any file system events.
+	rw := &RecursiveWatcher{
+		ctx:   ctx,
+		roots: []string{dir},
+	}
+	missed := make(chan string, 1)
+	go rw.MissedEvents(10*time.Millisecond, func(name string) {
+		missed <- name
+	})
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(name, []byte("ab"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-missed:
+		if got != name {
+			t.Errorf("expected %q, got %q", name, got)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the missed event to be reported")
+	}
+}
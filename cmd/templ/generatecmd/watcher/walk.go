@@ -0,0 +1,129 @@
+package watcher
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/gitignore"
+)
+
+// DefaultExclude are the directories that aren't walked unless Filter.Exclude is
+// set.
+var DefaultExclude = []string{"vendor", "node_modules"}
+
+// Filter decides which directories are walked, and watched for changes.
+// Directories with names that start with . or _ are always skipped, since
+// they're ignored by the Go tool.
+type Filter struct {
+	// FollowSymlinks walks symlinked directories. Each directory is only walked
+	// once, even if it can be reached through more than one symlink, so symlink
+	// cycles are skipped.
+	FollowSymlinks bool
+	// Exclude are patterns, in the syntax of path.Match, of directories that
+	// aren't walked. Patterns that contain a / are matched against the path of
+	// the directory relative to the root, and other patterns against its name.
+	// If nil, DefaultExclude is used. Set an empty slice to walk all directories.
+	Exclude []string
+	// Ignore skips the directories that are ignored by git, unless the root is
+	// ignored. If nil, no directories are ignored.
+	Ignore *gitignore.Matcher
+	// Scripts includes TypeScript files, for when they're bundled into the
+	// scripts of templates.
+	Scripts bool
+}
+
+// skipDir returns true if the directory in the file tree rooted at root isn't
+// walked.
+func (f Filter) skipDir(root, dir string) bool {
+	if dir == root {
+		return false
+	}
+	name := filepath.Base(dir)
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	// Roots are walked even if they're ignored, e.g. an output directory.
+	if f.Ignore.Ignored(dir, true) && !f.Ignore.Ignored(root, true) {
+		return true
+	}
+	exclude := f.Exclude
+	if exclude == nil {
+		exclude = DefaultExclude
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		rel = dir
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range exclude {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if ok, _ := path.Match(strings.Trim(pattern, "/"), target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls visit for dir, and each of the files and directories beneath it
+// that the filter doesn't skip, in lexical order. Directories are visited before
+// their contents. The filter applies to the paths relative to root, which is dir,
+// or a directory that contains it. Files and directories that can't be read are
+// skipped. If visit returns an error, the walk stops and returns it.
+func (f Filter) walk(root, dir string, visit func(path string, isDir bool) error) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil
+	}
+	if !fi.IsDir() {
+		return visit(dir, false)
+	}
+	if f.skipDir(root, dir) {
+		return nil
+	}
+	// visited are the real paths of the directories that have been walked.
+	visited := map[string]bool{}
+	var walkDir func(dir string) error
+	walkDir = func(dir string) error {
+		if f.FollowSymlinks {
+			real, err := filepath.EvalSymlinks(dir)
+			if err != nil || visited[real] {
+				return nil
+			}
+			visited[real] = true
+		}
+		if err := visit(dir, true); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil
+		}
+		for _, entry := range entries {
+			name := filepath.Join(dir, entry.Name())
+			isDir := entry.IsDir()
+			if !isDir && entry.Type()&os.ModeSymlink != 0 && f.FollowSymlinks {
+				fi, err := os.Stat(name)
+				isDir = err == nil && fi.IsDir()
+			}
+			if !isDir {
+				if err := visit(name, false); err != nil {
+					return err
+				}
+				continue
+			}
+			if f.skipDir(root, name) {
+				continue
+			}
+			if err := walkDir(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walkDir(dir)
+}
@@ -0,0 +1,137 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/gitignore"
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWalkFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"a.templ",
+		"components/b.templ",
+		"components/vendor/c.templ",
+		"vendor/d.templ",
+		"node_modules/pkg/e.templ",
+		"web/dist/f.templ",
+		".git/g.templ",
+		"_tmp/h.templ",
+		"other/i.templ",
+	} {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte("a"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A symlink to another directory, and a symlink cycle.
+	if err := os.Symlink(filepath.Join(dir, "other"), filepath.Join(dir, "components", "linked")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(dir, filepath.Join(dir, "other", "cycle")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A repository that ignores the build output directory.
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("/web/dist/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	walk := func(filter Filter) (names []string) {
+		t.Helper()
+		out := make(chan fsnotify.Event)
+		go func() {
+			defer close(out)
+			if err := WalkFiles(context.Background(), dir, filter, out); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+		for event := range out {
+			rel, err := filepath.Rel(dir, event.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{
+			name:   "vendor and node_modules directories are skipped at any depth by default",
+			filter: Filter{},
+			expected: []string{
+				"a.templ",
+				"components/b.templ",
+				"other/i.templ",
+				"web/dist/f.templ",
+			},
+		},
+		{
+			name:   "the default exclusions can be overridden",
+			filter: Filter{Exclude: []string{}},
+			expected: []string{
+				"a.templ",
+				"components/b.templ",
+				"components/vendor/c.templ",
+				"node_modules/pkg/e.templ",
+				"other/i.templ",
+				"vendor/d.templ",
+				"web/dist/f.templ",
+			},
+		},
+		{
+			name:   "patterns that contain a slash match the path relative to the root",
+			filter: Filter{Exclude: []string{"web/dist", "vendor"}},
+			expected: []string{
+				"a.templ",
+				"components/b.templ",
+				"node_modules/pkg/e.templ",
+				"other/i.templ",
+			},
+		},
+		{
+			name:   "directories ignored by git are skipped",
+			filter: Filter{Ignore: gitignore.New(dir)},
+			expected: []string{
+				"a.templ",
+				"components/b.templ",
+				"other/i.templ",
+			},
+		},
+		{
+			name:   "symlinked directories are followed once, and cycles are skipped",
+			filter: Filter{FollowSymlinks: true},
+			expected: []string{
+				"a.templ",
+				"components/b.templ",
+				"components/linked/i.templ",
+				"web/dist/f.templ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, walk(tt.filter)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
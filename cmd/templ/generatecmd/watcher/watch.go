@@ -2,8 +2,6 @@ package watcher
 
 import (
 	"context"
-	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -15,6 +13,7 @@ import (
 func Recursive(
 	ctx context.Context,
 	path string,
+	filter Filter,
 	out chan fsnotify.Event,
 	errors chan error,
 ) (w *RecursiveWatcher, err error) {
@@ -25,6 +24,7 @@ func Recursive(
 	w = &RecursiveWatcher{
 		ctx:    ctx,
 		w:      fsnw,
+		filter: filter,
 		Events: out,
 		Errors: errors,
 		timers: make(map[timerKey]*time.Timer),
@@ -34,16 +34,10 @@ func Recursive(
 }
 
 // WalkFiles walks the file tree rooted at path, sending a Create event for each
-// file it encounters.
-func WalkFiles(ctx context.Context, path string, out chan fsnotify.Event) (err error) {
-	return filepath.WalkDir(path, func(path string, info os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() && shouldSkipDir(path) {
-			return filepath.SkipDir
-		}
-		if !shouldIncludeFile(path) {
+// file it encounters in the directories that the filter doesn't skip.
+func WalkFiles(ctx context.Context, path string, filter Filter, out chan fsnotify.Event) (err error) {
+	return filter.walk(path, path, func(path string, isDir bool) error {
+		if isDir || !filter.includeFile(path) {
 			return nil
 		}
 		out <- fsnotify.Event{
@@ -54,7 +48,8 @@ func WalkFiles(ctx context.Context, path string, out chan fsnotify.Event) (err e
 	})
 }
 
-func shouldIncludeFile(name string) bool {
+// includeFile returns true if the file is a templ related file.
+func (f Filter) includeFile(name string) bool {
 	if strings.HasSuffix(name, ".templ") {
 		return true
 	}
@@ -64,16 +59,34 @@ func shouldIncludeFile(name string) bool {
 	if strings.HasSuffix(name, "_templ.txt") {
 		return true
 	}
+	if strings.HasSuffix(name, "_templ.d.ts") {
+		return true
+	}
+	// TypeScript files may be bundled into the scripts of templates.
+	if f.Scripts && strings.HasSuffix(name, ".ts") {
+		return true
+	}
 	return false
 }
 
+// Watcher watches file trees for changes to templ related files.
+type Watcher interface {
+	Add(dir string) error
+	Close() error
+}
+
 type RecursiveWatcher struct {
 	ctx     context.Context
 	w       *fsnotify.Watcher
+	filter  Filter
 	Events  chan fsnotify.Event
 	Errors  chan error
 	timerMu sync.Mutex
 	timers  map[timerKey]*time.Timer
+	// seen is the time that the last event for each file was received.
+	seenMu sync.Mutex
+	seen   map[string]time.Time
+	roots  []string
 }
 
 type timerKey struct {
@@ -102,14 +115,20 @@ func (w *RecursiveWatcher) loop() {
 				return
 			}
 			if event.Has(fsnotify.Create) {
-				if err := w.Add(event.Name); err != nil {
+				if err := w.add(event.Name); err != nil {
 					w.Errors <- err
 				}
 			}
 			// Only notify on templ related files.
-			if !shouldIncludeFile(event.Name) {
+			if !w.filter.includeFile(event.Name) {
 				continue
 			}
+			w.seenMu.Lock()
+			if w.seen == nil {
+				w.seen = make(map[string]time.Time)
+			}
+			w.seen[event.Name] = time.Now()
+			w.seenMu.Unlock()
 			tk := timerKeyFromEvent(event)
 			w.timerMu.Lock()
 			t, ok := w.timers[tk]
@@ -133,32 +152,84 @@ func (w *RecursiveWatcher) loop() {
 	}
 }
 
+// Add the file tree rooted at dir to the watcher.
 func (w *RecursiveWatcher) Add(dir string) error {
-	return filepath.WalkDir(dir, func(dir string, info os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		if !info.IsDir() {
+	w.seenMu.Lock()
+	w.roots = append(w.roots, dir)
+	w.seenMu.Unlock()
+	return w.add(dir)
+}
+
+func (w *RecursiveWatcher) add(dir string) error {
+	return w.filter.walk(w.root(dir), dir, func(dir string, isDir bool) error {
+		if !isDir {
 			return nil
 		}
-		if shouldSkipDir(dir) {
-			return filepath.SkipDir
-		}
 		return w.w.Add(dir)
 	})
 }
 
-func shouldSkipDir(dir string) bool {
-	if dir == "." {
-		return false
-	}
-	if dir == "vendor" || dir == "node_modules" {
-		return true
+// root returns the watched root that contains dir, or dir if it's a root.
+func (w *RecursiveWatcher) root(dir string) string {
+	w.seenMu.Lock()
+	defer w.seenMu.Unlock()
+	for _, root := range w.roots {
+		if rel, err := filepath.Rel(root, dir); err == nil && filepath.IsLocal(rel) {
+			return root
+		}
 	}
-	_, name := path.Split(dir)
-	// These directories are ignored by the Go tool.
-	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
-		return true
+	return dir
+}
+
+// MissedEvents scans the watched file trees at each interval, and calls f with the
+// name of the first file that changed without an event being received, e.g.
+// because the files are on a network drive, a WSL2 mount of a Windows drive, or a
+// Docker bind mount. A change found by a scan is only reported if no event for the
+// file has been received by the following scan, to give the event time to arrive.
+// MissedEvents returns when the context is cancelled, or after f is called.
+func (w *RecursiveWatcher) MissedEvents(interval time.Duration, f func(name string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var prev map[string]fileState
+	var prevStart time.Time
+	// The files that changed in the previous scan, and the start time of the scan
+	// before that, after which an event for each file should have been received.
+	var changed []string
+	var since time.Time
+	for {
+		start := time.Now()
+		w.seenMu.Lock()
+		roots := append([]string(nil), w.roots...)
+		w.seenMu.Unlock()
+		files := map[string]fileState{}
+		for _, root := range roots {
+			if err := scan(root, w.filter, false, files); err != nil {
+				return
+			}
+		}
+		w.seenMu.Lock()
+		for _, name := range changed {
+			if seen, ok := w.seen[name]; !ok || seen.Before(since) {
+				w.seenMu.Unlock()
+				f(name)
+				return
+			}
+		}
+		w.seenMu.Unlock()
+		changed = nil
+		if prev != nil {
+			for name, state := range files {
+				if p, ok := prev[name]; !ok || !p.modTime.Equal(state.modTime) || p.size != state.size {
+					changed = append(changed, name)
+				}
+			}
+		}
+		since = prevStart
+		prev, prevStart = files, start
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-	return false
 }
@@ -123,3 +123,21 @@ func TestWatchDoesNotDebounceSeparateEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterIncludesScriptsOnlyWhenBundling(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{name: "components.ts", filter: Filter{}, expected: false},
+		{name: "components.ts", filter: Filter{Scripts: true}, expected: true},
+		{name: "components_templ.d.ts", filter: Filter{}, expected: true},
+		{name: "components.templ", filter: Filter{}, expected: true},
+	}
+	for _, tt := range tests {
+		if actual := tt.filter.includeFile(tt.name); actual != tt.expected {
+			t.Errorf("%s with scripts %v: expected %v, got %v", tt.name, tt.filter.Scripts, tt.expected, actual)
+		}
+	}
+}
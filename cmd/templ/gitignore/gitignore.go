@@ -0,0 +1,237 @@
+// Package gitignore finds the paths that are ignored by git, using the .gitignore
+// files of the repository that contains them, without running git.
+package gitignore
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Matcher reports whether paths in a git repository are ignored. A nil Matcher
+// ignores nothing.
+type Matcher struct {
+	// root is the directory that contains the .git directory.
+	root string
+	m    sync.Mutex
+	// patterns maps directories, relative to the root, to the patterns of their
+	// .gitignore files.
+	patterns map[string][]pattern
+	// ignoredDirs caches whether each directory, relative to the root, is ignored.
+	ignoredDirs map[string]bool
+}
+
+// New returns a Matcher for the git repository that contains dir, or nil if dir
+// isn't in a git repository, or is itself ignored, e.g. a build output directory
+// that has been passed to a command explicitly.
+func New(dir string) *Matcher {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	for root := abs; ; {
+		// .git is a file in worktrees and submodules.
+		if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+			m := &Matcher{
+				root:        root,
+				patterns:    make(map[string][]pattern),
+				ignoredDirs: make(map[string]bool),
+			}
+			m.patterns["."] = append(readPatterns(filepath.Join(root, ".git", "info", "exclude"), "."), readPatterns(filepath.Join(root, ".gitignore"), ".")...)
+			if m.Ignored(abs, true) {
+				return nil
+			}
+			return m
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return nil
+		}
+		root = parent
+	}
+}
+
+// Ignored returns true if the file or directory at path is ignored, either by
+// its own patterns, or because a directory that contains it is ignored.
+func (m *Matcher) Ignored(name string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(m.root, abs)
+	if err != nil || rel == "." || !filepath.IsLocal(rel) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	m.m.Lock()
+	defer m.m.Unlock()
+	if parent := path.Dir(rel); parent != "." && m.ignoredDir(parent) {
+		return true
+	}
+	if isDir {
+		return m.ignoredDir(rel)
+	}
+	return m.match(rel, false)
+}
+
+// ignoredDir returns true if the directory, or a directory that contains it, is
+// ignored. Once a directory is ignored, git doesn't look inside it, so its
+// contents can't be included again by a negated pattern.
+func (m *Matcher) ignoredDir(rel string) bool {
+	if ignored, ok := m.ignoredDirs[rel]; ok {
+		return ignored
+	}
+	ignored := false
+	if parent := path.Dir(rel); parent != "." {
+		ignored = m.ignoredDir(parent)
+	}
+	if !ignored {
+		ignored = m.match(rel, true)
+	}
+	m.ignoredDirs[rel] = ignored
+	return ignored
+}
+
+// match returns true if the last pattern that matches the path, of the .gitignore
+// files of the directories that contain it, isn't negated.
+func (m *Matcher) match(rel string, isDir bool) (ignored bool) {
+	for _, dir := range parentDirs(rel) {
+		for _, p := range m.dirPatterns(dir) {
+			if p.match(rel, isDir) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// dirPatterns returns the patterns of the .gitignore file of the directory.
+func (m *Matcher) dirPatterns(dir string) []pattern {
+	if patterns, ok := m.patterns[dir]; ok {
+		return patterns
+	}
+	patterns := readPatterns(filepath.Join(m.root, filepath.FromSlash(dir), ".gitignore"), dir)
+	m.patterns[dir] = patterns
+	return patterns
+}
+
+// parentDirs returns the directories that contain the path, from the root down.
+func parentDirs(rel string) (dirs []string) {
+	dirs = []string{"."}
+	for i := 0; i < len(rel); i++ {
+		if rel[i] == '/' {
+			dirs = append(dirs, rel[:i])
+		}
+	}
+	return dirs
+}
+
+type pattern struct {
+	// base is the directory of the .gitignore file, relative to the root.
+	base string
+	// segments of the pattern, split by /. If the pattern doesn't contain a /,
+	// other than at the end, it matches the name of the path at any depth.
+	segments []string
+	anchored bool
+	negate   bool
+	dirOnly  bool
+}
+
+func readPatterns(fileName, base string) (patterns []pattern) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if p, ok := parsePattern(scanner.Text(), base); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// parsePattern parses a line of a .gitignore file, see
+// https://git-scm.com/docs/gitignore#_pattern_format
+func parsePattern(line, base string) (p pattern, ok bool) {
+	line = strings.TrimSuffix(line, "\r")
+	// Trailing spaces are ignored, unless they're escaped.
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false
+	}
+	p.base = base
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return p, false
+	}
+	p.anchored = strings.Contains(line, "/")
+	p.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+	return p, true
+}
+
+// match returns true if the pattern matches the path, relative to the root.
+func (p pattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.base != "." {
+		if !strings.HasPrefix(rel, p.base+"/") {
+			return false
+		}
+		rel = rel[len(p.base)+1:]
+	}
+	if !p.anchored {
+		return matchSegment(p.segments[0], path.Base(rel))
+	}
+	return matchSegments(p.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments matches the segments of a pattern against the segments of a path,
+// where ** matches any number of segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				// A trailing ** matches everything inside a directory, but not
+				// the directory itself.
+				return len(name) > 0
+			}
+			for i := range name {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 || !matchSegment(pattern[0], name[0]) {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func matchSegment(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
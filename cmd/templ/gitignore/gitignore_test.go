@@ -0,0 +1,84 @@
+package gitignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		name = filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".git/info/exclude", "local/\n")
+	write(".gitignore", `# Build output.
+dist/
+/tmp
+*.log
+!keep.log
+docs/**/generated
+assets/**
+`)
+	write("web/.gitignore", "build\n!dist/\n")
+
+	m := New(filepath.Join(root, "web"))
+	if m == nil {
+		t.Fatal("expected a matcher for a directory in a repository")
+	}
+
+	tests := []struct {
+		name     string
+		isDir    bool
+		expected bool
+	}{
+		{name: "components", isDir: true, expected: false},
+		{name: "components/button.templ", expected: false},
+		{name: "dist", isDir: true, expected: true},
+		{name: "web/dist", isDir: true, expected: false},
+		{name: "dist/page.templ", expected: true},
+		{name: "dist", isDir: false, expected: false},
+		{name: "tmp", isDir: true, expected: true},
+		{name: "web/tmp", isDir: true, expected: false},
+		{name: "debug.log", expected: true},
+		{name: "web/debug.log", expected: true},
+		{name: "keep.log", expected: false},
+		{name: "web/build", isDir: true, expected: true},
+		{name: "build", isDir: true, expected: false},
+		{name: "docs/a/b/generated", isDir: true, expected: true},
+		{name: "docs/generated", isDir: true, expected: true},
+		{name: "assets", isDir: true, expected: false},
+		{name: "assets/logo.templ", expected: true},
+		{name: "local/page.templ", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := m.Ignored(filepath.Join(root, filepath.FromSlash(tt.name)), tt.isDir)
+			if actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+
+	t.Run("ignored directories have no matcher, so that their contents aren't ignored", func(t *testing.T) {
+		if m := New(filepath.Join(root, "dist")); m != nil {
+			t.Error("expected no matcher for an ignored directory")
+		}
+	})
+	t.Run("directories outside of a repository have no matcher", func(t *testing.T) {
+		if m := New(t.TempDir()); m != nil {
+			t.Skip("the temporary directory is in a git repository")
+		}
+		var m *Matcher
+		if m.Ignored("dist", true) {
+			t.Error("expected a nil matcher to ignore nothing")
+		}
+	})
+}
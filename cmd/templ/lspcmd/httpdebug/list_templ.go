@@ -17,6 +17,7 @@ func list(uris []string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `httpdebug.list`, &templ_7745c5c3_SourceLines_5bc88cb7)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -27,6 +28,9 @@ func list(uris []string) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		for _, uri := range uris {
+			if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<tr><td>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -91,3 +95,5 @@ func list(uris []string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_5bc88cb7 = templ.SourceLines{FileName: `cmd/templ/lspcmd/httpdebug/list.templ`, From: 12, To: 97, Lines: []int{12, 3, 30, 12, 39, 14, 51, 15, 60, 16, 69, 17, 78, 18}}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 
 	"github.com/a-h/protocol"
 	"github.com/a-h/templ/cmd/templ/lspcmd/httpdebug"
@@ -27,6 +28,14 @@ type Arguments struct {
 	PPROF bool
 	// HTTPDebug sets the HTTP endpoint to listen on. Leave empty for no web debug.
 	HTTPDebug string
+	// NoSnippets disables the completions of templ declarations and control flow statements.
+	NoSnippets bool
+	// Path is the directory that the templ files are in, see templ generate -path.
+	// It's only used with OutputDir.
+	Path string
+	// OutputDir is the directory that the Go code is generated in, see templ
+	// generate -output-dir. If empty, the Go code is next to each templ file.
+	OutputDir string
 }
 
 func Run(w io.Writer, args Arguments) (err error) {
@@ -90,17 +99,25 @@ func run(ctx context.Context, log *zap.Logger, templStream jsonrpc2.Stream, args
 		os.Exit(1)
 	}
 
+	fileNames, err := newFileNames(args.Path, args.OutputDir)
+	if err != nil {
+		return err
+	}
+
 	cache := proxy.NewSourceMapCache()
 	diagnosticCache := proxy.NewDiagnosticCache()
 
 	log.Info("creating gopls client")
 	clientProxy, clientInit := proxy.NewClient(log, cache, diagnosticCache)
+	clientProxy.FileNames = fileNames
 	_, goplsConn, goplsServer := protocol.NewClient(context.Background(), clientProxy, jsonrpc2.NewStream(rwc), log)
 	defer goplsConn.Close()
 
 	log.Info("creating proxy")
 	// Create the proxy to sit between.
 	serverProxy, serverInit := proxy.NewServer(log, goplsServer, cache, diagnosticCache)
+	serverProxy.NoSnippets = args.NoSnippets
+	serverProxy.FileNames = fileNames
 
 	// Create templ server.
 	log.Info("creating templ server")
@@ -135,3 +152,21 @@ func run(ctx context.Context, log *zap.Logger, templStream jsonrpc2.Stream, args
 	log.Info("shutdown complete")
 	return
 }
+
+// newFileNames returns the conversion between templ and Go file names for the
+// directories, relative to the working directory.
+func newFileNames(path, outputDir string) (fn proxy.FileNames, err error) {
+	if outputDir == "" {
+		return fn, nil
+	}
+	if path == "" {
+		path = "."
+	}
+	if fn.Root, err = filepath.Abs(path); err != nil {
+		return fn, fmt.Errorf("failed to get absolute path of %q: %w", path, err)
+	}
+	if fn.OutputDir, err = filepath.Abs(outputDir); err != nil {
+		return fn, fmt.Errorf("failed to get absolute path of %q: %w", outputDir, err)
+	}
+	return fn, nil
+}
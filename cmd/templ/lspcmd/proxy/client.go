@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
 	"go.uber.org/zap"
 )
 
@@ -21,6 +22,8 @@ type Client struct {
 	Target          lsp.Client
 	SourceMapCache  *SourceMapCache
 	DiagnosticCache *DiagnosticCache
+	// FileNames converts the URIs of generated Go files to the URIs of their templ files.
+	FileNames FileNames
 }
 
 func NewClient(log *zap.Logger, cache *SourceMapCache, diagnosticCache *DiagnosticCache) (c *Client, init func(lsp.Client)) {
@@ -59,7 +62,8 @@ func (p Client) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagn
 		p.Log.Info(fmt.Sprintf("client <- server: PublishDiagnostics: [%d]", i), zap.Any("diagnostic", diagnostic))
 	}
 	// Get the sourcemap from the cache.
-	uri := strings.TrimSuffix(string(params.URI), "_templ.go") + ".templ"
+	_, templURI := p.FileNames.convertTemplGoToTemplURI(params.URI)
+	uri := string(templURI)
 	sourceMap, ok := p.SourceMapCache.Get(uri)
 	if !ok {
 		p.Log.Error("unable to complete because the sourcemap for the URI doesn't exist in the cache", zap.String("uri", uri))
@@ -69,28 +73,14 @@ func (p Client) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagn
 	// Rewrite the positions.
 	for i := 0; i < len(params.Diagnostics); i++ {
 		item := params.Diagnostics[i]
-		start, ok := sourceMap.SourcePositionFromTarget(item.Range.Start.Line, item.Range.Start.Character)
+		r, ok := sourceMap.SourceRangeFromTarget(
+			parser.NewPosition(0, item.Range.Start.Line, item.Range.Start.Character),
+			parser.NewPosition(0, item.Range.End.Line, item.Range.End.Character),
+		)
 		if !ok {
 			continue
 		}
-		if item.Range.Start.Line == item.Range.End.Line {
-			length := item.Range.End.Character - item.Range.Start.Character
-			item.Range.Start.Line = start.Line
-			item.Range.Start.Character = start.Col
-			item.Range.End.Line = start.Line
-			item.Range.End.Character = start.Col + length
-			params.Diagnostics[i] = item
-			p.Log.Info(fmt.Sprintf("diagnostic [%d] rewritten", i), zap.Any("diagnostic", item))
-			continue
-		}
-		end, ok := sourceMap.SourcePositionFromTarget(item.Range.End.Line, item.Range.End.Character)
-		if !ok {
-			continue
-		}
-		item.Range.Start.Line = start.Line
-		item.Range.Start.Character = start.Col
-		item.Range.End.Line = end.Line
-		item.Range.End.Character = end.Col
+		item.Range = lsp.Range{Start: rangePosition(r.From), End: rangePosition(r.To)}
 		params.Diagnostics[i] = item
 		p.Log.Info(fmt.Sprintf("diagnostic [%d] rewritten", i), zap.Any("diagnostic", item))
 	}
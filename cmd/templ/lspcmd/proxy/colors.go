@@ -0,0 +1,244 @@
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// cssColor matches hex, rgb(), rgba(), hsl() and hsla() colors.
+var cssColor = regexp.MustCompile(`#[0-9a-fA-F]{3,8}\b|\b(rgba?|hsla?)\(\s*([0-9.]+(?:deg|%)?)\s*[,\s]\s*([0-9.]+%?)\s*[,\s]\s*([0-9.]+%?)\s*(?:[,/]\s*([0-9.]+%?)\s*)?\)`)
+
+// documentColors returns the colors in the properties of css templates, and in
+// style attributes. Colors in Go expressions aren't returned.
+func documentColors(src string, template parser.TemplateFile) (colors []lsp.ColorInformation) {
+	positions := newSourcePositions(src)
+	add := func(from, to int) {
+		for _, m := range cssColor.FindAllStringIndex(src[from:to], -1) {
+			c, ok := parseColor(src[from+m[0] : from+m[1]])
+			if !ok {
+				continue
+			}
+			colors = append(colors, lsp.ColorInformation{
+				Range: positions.rangeOf(from+m[0], from+m[1]),
+				Color: c,
+			})
+		}
+	}
+	for _, n := range template.Nodes {
+		if ct, ok := n.(parser.CSSTemplate); ok {
+			for _, r := range cssTemplateText(src, ct) {
+				add(r[0], r[1])
+			}
+		}
+	}
+	walkTemplates(template, func(n parser.Node) {
+		for _, attr := range constantAttributes(n) {
+			if !strings.EqualFold(attr.Name, "style") {
+				continue
+			}
+			if from, to, ok := attributeValue(src, attr); ok {
+				add(from, to)
+			}
+		}
+	})
+	return colors
+}
+
+// cssTemplateText returns the start and end indexes of the text of the body of
+// the css template, excluding its Go expressions. The body ends at the first
+// line that starts with a closing brace.
+func cssTemplateText(src string, ct parser.CSSTemplate) (ranges [][2]int) {
+	from := int(ct.Expression.Range.To.Index)
+	if from < 0 || from > len(src) {
+		return nil
+	}
+	to := len(src)
+	if end := strings.Index(src[from:], "\n}"); end >= 0 {
+		to = from + end
+	}
+	for _, p := range ct.Properties {
+		ep, ok := p.(parser.ExpressionCSSProperty)
+		if !ok {
+			continue
+		}
+		exprFrom, exprTo := int(ep.Value.Expression.Range.From.Index), int(ep.Value.Expression.Range.To.Index)
+		if exprFrom < from || exprTo > to {
+			continue
+		}
+		ranges = append(ranges, [2]int{from, exprFrom})
+		from = exprTo
+	}
+	return append(ranges, [2]int{from, to})
+}
+
+// parseColor parses a CSS hex, rgb(), rgba(), hsl() or hsla() color.
+func parseColor(s string) (c lsp.Color, ok bool) {
+	if strings.HasPrefix(s, "#") {
+		return parseHexColor(s[1:])
+	}
+	m := cssColor.FindStringSubmatch(s)
+	if m == nil || m[1] == "" {
+		return c, false
+	}
+	c.Alpha = 1
+	if m[5] != "" {
+		if c.Alpha, ok = parseCSSNumber(m[5], 1); !ok {
+			return c, false
+		}
+	}
+	if strings.HasPrefix(m[1], "rgb") {
+		values := make([]float64, 3)
+		for i, v := range m[2:5] {
+			if values[i], ok = parseCSSNumber(v, 255); !ok {
+				return c, false
+			}
+			values[i] /= 255
+		}
+		c.Red, c.Green, c.Blue = values[0], values[1], values[2]
+		return clampColor(c), true
+	}
+	hue, err := strconv.ParseFloat(strings.TrimSuffix(m[2], "deg"), 64)
+	if err != nil || strings.HasSuffix(m[2], "%") || !strings.HasSuffix(m[3], "%") || !strings.HasSuffix(m[4], "%") {
+		return c, false
+	}
+	saturation, ok1 := parseCSSNumber(m[3], 1)
+	lightness, ok2 := parseCSSNumber(m[4], 1)
+	if !ok1 || !ok2 {
+		return c, false
+	}
+	c.Red, c.Green, c.Blue = hslToRGB(hue, saturation, lightness)
+	return clampColor(c), true
+}
+
+// parseCSSNumber parses a number, or a percentage of the scale.
+func parseCSSNumber(s string, scale float64) (v float64, ok bool) {
+	percentage := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	if percentage {
+		v = v / 100 * scale
+	}
+	return v, true
+}
+
+func parseHexColor(hex string) (c lsp.Color, ok bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var expanded strings.Builder
+		for _, r := range hex {
+			expanded.WriteRune(r)
+			expanded.WriteRune(r)
+		}
+		hex = expanded.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return c, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return c, false
+	}
+	return lsp.Color{
+		Red:   float64(v>>24&0xff) / 255,
+		Green: float64(v>>16&0xff) / 255,
+		Blue:  float64(v>>8&0xff) / 255,
+		Alpha: float64(v&0xff) / 255,
+	}, true
+}
+
+// hslToRGB converts a hue in degrees, and a saturation and lightness from 0 to
+// 1, to red, green and blue values from 0 to 1.
+func hslToRGB(hue, saturation, lightness float64) (r, g, b float64) {
+	hue = math.Mod(math.Mod(hue, 360)+360, 360) / 360
+	if saturation == 0 {
+		return lightness, lightness, lightness
+	}
+	q := lightness * (1 + saturation)
+	if lightness >= 0.5 {
+		q = lightness + saturation - lightness*saturation
+	}
+	p := 2*lightness - q
+	channel := func(t float64) float64 {
+		t = math.Mod(t+1, 1)
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 1.0/2:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		}
+		return p
+	}
+	return channel(hue + 1.0/3), channel(hue), channel(hue - 1.0/3)
+}
+
+func clampColor(c lsp.Color) lsp.Color {
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	return lsp.Color{Red: clamp(c.Red), Green: clamp(c.Green), Blue: clamp(c.Blue), Alpha: clamp(c.Alpha)}
+}
+
+// colorPresentations returns the ways that the color can be written in CSS, as
+// hex, rgb() and hsl() colors.
+func colorPresentations(c lsp.Color, r lsp.Range) (presentations []lsp.ColorPresentation) {
+	c = clampColor(c)
+	red, green, blue := to255(c.Red), to255(c.Green), to255(c.Blue)
+	hue, saturation, lightness := rgbToHSL(c.Red, c.Green, c.Blue)
+	labels := []string{
+		fmt.Sprintf("#%02x%02x%02x", red, green, blue),
+		fmt.Sprintf("rgb(%d, %d, %d)", red, green, blue),
+		fmt.Sprintf("hsl(%d, %d%%, %d%%)", hue, saturation, lightness),
+	}
+	if c.Alpha < 1 {
+		alpha := strconv.FormatFloat(math.Round(c.Alpha*100)/100, 'f', -1, 64)
+		labels = []string{
+			fmt.Sprintf("#%02x%02x%02x%02x", red, green, blue, to255(c.Alpha)),
+			fmt.Sprintf("rgba(%d, %d, %d, %s)", red, green, blue, alpha),
+			fmt.Sprintf("hsla(%d, %d%%, %d%%, %s)", hue, saturation, lightness, alpha),
+		}
+	}
+	for _, label := range labels {
+		presentations = append(presentations, lsp.ColorPresentation{
+			Label:    label,
+			TextEdit: &lsp.TextEdit{Range: r, NewText: label},
+		})
+	}
+	return presentations
+}
+
+func to255(v float64) int {
+	return int(math.Round(v * 255))
+}
+
+// rgbToHSL converts red, green and blue values from 0 to 1 to a hue in degrees,
+// and a saturation and lightness in percent.
+func rgbToHSL(r, g, b float64) (hue, saturation, lightness int) {
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (hi + lo) / 2
+	if hi == lo {
+		return 0, 0, int(math.Round(l * 100))
+	}
+	d := hi - lo
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h = math.Mod(h*60+360, 360)
+	return int(math.Round(h)), int(math.Round(s * 100)), int(math.Round(l * 100))
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestDocumentColors(t *testing.T) {
+	input := `package main
+
+css button(border string) {
+	color: #ff0000;
+	border: { border };
+	background-color: rgba(0, 0, 255, 0.5);
+}
+
+templ Page() {
+	<p style="color: hsl(120, 100%, 50%)">Green</p>
+	<a href="#fff">Not a color</a>
+}
+`
+	template, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := []lsp.ColorInformation{
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 8}, End: lsp.Position{Line: 3, Character: 15}},
+			Color: lsp.Color{Red: 1, Alpha: 1},
+		},
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 5, Character: 19}, End: lsp.Position{Line: 5, Character: 39}},
+			Color: lsp.Color{Blue: 1, Alpha: 0.5},
+		},
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 9, Character: 18}, End: lsp.Position{Line: 9, Character: 37}},
+			Color: lsp.Color{Green: 1, Alpha: 1},
+		},
+	}
+	actual := documentColors(input, template)
+	if diff := cmp.Diff(expected, actual, cmpopts.EquateApprox(0, 0.001)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected lsp.Color
+		ok       bool
+	}{
+		{input: "#fff", expected: lsp.Color{Red: 1, Green: 1, Blue: 1, Alpha: 1}, ok: true},
+		{input: "#00ff0080", expected: lsp.Color{Green: 1, Alpha: 128.0 / 255}, ok: true},
+		{input: "#abcde", ok: false},
+		{input: "rgb(255 0 0)", expected: lsp.Color{Red: 1, Alpha: 1}, ok: true},
+		{input: "rgb(100%, 50%, 0%)", expected: lsp.Color{Red: 1, Green: 0.5, Alpha: 1}, ok: true},
+		{input: "hsla(240deg, 100%, 50%, 25%)", expected: lsp.Color{Blue: 1, Alpha: 0.25}, ok: true},
+		{input: "hsl(240, 100, 50)", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, ok := parseColor(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("expected ok to be %v, got %v", tt.ok, ok)
+			}
+			if diff := cmp.Diff(tt.expected, actual, cmpopts.EquateApprox(0, 0.001)); ok && diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestColorPresentations(t *testing.T) {
+	r := lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 9}}
+	tests := []struct {
+		name     string
+		color    lsp.Color
+		expected []string
+	}{
+		{
+			name:     "opaque colors",
+			color:    lsp.Color{Red: 1, Green: 0.5, Alpha: 1},
+			expected: []string{"#ff8000", "rgb(255, 128, 0)", "hsl(30, 100%, 50%)"},
+		},
+		{
+			name:     "transparent colors",
+			color:    lsp.Color{Blue: 1, Alpha: 0.5},
+			expected: []string{"#0000ff80", "rgba(0, 0, 255, 0.5)", "hsla(240, 100%, 50%, 0.5)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual []string
+			for _, p := range colorPresentations(tt.color, r) {
+				if p.TextEdit == nil || p.TextEdit.Range != r || p.TextEdit.NewText != p.Label {
+					t.Errorf("expected an edit of the range to %q, got %v", p.Label, p.TextEdit)
+				}
+				actual = append(actual, p.Label)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
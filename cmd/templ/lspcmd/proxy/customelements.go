@@ -0,0 +1,80 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+)
+
+// customElementsManifestFileName is the name of the Custom Elements Manifest file
+// that declares the web components used in a project.
+// https://github.com/webcomponents/custom-elements-manifest
+const customElementsManifestFileName = "custom-elements.json"
+
+type customElementsManifest struct {
+	Modules []struct {
+		Declarations []customElementDeclaration `json:"declarations"`
+	} `json:"modules"`
+}
+
+type customElementDeclaration struct {
+	TagName     string `json:"tagName"`
+	Description string `json:"description"`
+	Attributes  []struct {
+		Name string `json:"name"`
+	} `json:"attributes"`
+}
+
+// loadCustomElementSnippets reads the custom elements manifest in dir, if present,
+// and returns completion snippets for each declared custom element.
+func loadCustomElementSnippets(dir string) (snippets []lsp.CompletionItem, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, customElementsManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", customElementsManifestFileName, err)
+	}
+	return parseCustomElementSnippets(data)
+}
+
+func parseCustomElementSnippets(data []byte) (snippets []lsp.CompletionItem, err error) {
+	var manifest customElementsManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", customElementsManifestFileName, err)
+	}
+	for _, m := range manifest.Modules {
+		for _, d := range m.Declarations {
+			// Declarations also include functions, mixins and classes that are not custom elements.
+			if d.TagName == "" {
+				continue
+			}
+			snippets = append(snippets, customElementSnippet(d))
+		}
+	}
+	sort.Slice(snippets, func(i, j int) bool {
+		return snippets[i].Label < snippets[j].Label
+	})
+	return snippets, nil
+}
+
+func customElementSnippet(d customElementDeclaration) lsp.CompletionItem {
+	var sb strings.Builder
+	sb.WriteString(d.TagName)
+	for i, attr := range d.Attributes {
+		sb.WriteString(fmt.Sprintf(` %s="${%d}"`, attr.Name, i+1))
+	}
+	sb.WriteString(">${0}</" + d.TagName + ">")
+	return lsp.CompletionItem{
+		Label:            d.TagName,
+		Detail:           d.Description,
+		InsertText:       sb.String(),
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	}
+}
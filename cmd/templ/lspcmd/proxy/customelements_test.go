@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCustomElementSnippets(t *testing.T) {
+	manifest := `{
+  "schemaVersion": "1.0.0",
+  "modules": [
+    {
+      "kind": "javascript-module",
+      "path": "src/user-card.js",
+      "declarations": [
+        {
+          "kind": "class",
+          "name": "UserCard",
+          "tagName": "user-card",
+          "customElement": true,
+          "description": "Displays a user.",
+          "attributes": [{ "name": "name" }, { "name": "avatar-url" }]
+        },
+        {
+          "kind": "function",
+          "name": "formatName"
+        }
+      ]
+    },
+    {
+      "kind": "javascript-module",
+      "path": "src/app-shell.js",
+      "declarations": [
+        {
+          "kind": "class",
+          "name": "AppShell",
+          "tagName": "app-shell",
+          "customElement": true
+        }
+      ]
+    }
+  ]
+}`
+	actual, err := parseCustomElementSnippets([]byte(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []lsp.CompletionItem{
+		{
+			Label:            "app-shell",
+			InsertText:       `app-shell>${0}</app-shell>`,
+			Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+			InsertTextFormat: lsp.InsertTextFormatSnippet,
+		},
+		{
+			Label:            "user-card",
+			Detail:           "Displays a user.",
+			InsertText:       `user-card name="${1}" avatar-url="${2}">${0}</user-card>`,
+			Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+			InsertTextFormat: lsp.InsertTextFormatSnippet,
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadCustomElementSnippetsWithoutManifest(t *testing.T) {
+	actual, err := loadCustomElementSnippets(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actual) != 0 {
+		t.Errorf("expected no snippets, got %d", len(actual))
+	}
+}
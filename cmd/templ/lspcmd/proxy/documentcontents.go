@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
 	"go.uber.org/zap"
 )
 
@@ -84,6 +85,9 @@ func NewDocument(log *zap.Logger, s string) *Document {
 type Document struct {
 	Log   *zap.Logger
 	Lines []string
+	// Parser reuses the unchanged templates of the document when it's parsed
+	// after an edit.
+	Parser parser.IncrementalParser
 }
 
 func (d *Document) LineLengths() (lens []int) {
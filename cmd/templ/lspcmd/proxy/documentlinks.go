@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// linkAttributes are the attributes whose URLs are returned as document links.
+var linkAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+}
+
+// componentName matches the name of a component in a call, e.g. components.Button.
+var componentName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*`)
+
+// componentLink is the data of a link to the definition of a component, which
+// is found when the link is resolved.
+type componentLink struct {
+	URI      lsp.DocumentURI `json:"uri"`
+	Position lsp.Position    `json:"position"`
+}
+
+// documentLinks returns links for the http and https URLs of href and src
+// attributes, and for the @ calls of components. The targets of calls aren't
+// set, they're found by DocumentLinkResolve.
+func documentLinks(templURI lsp.DocumentURI, src string, template parser.TemplateFile) (links []lsp.DocumentLink) {
+	positions := newSourcePositions(src)
+	walkTemplates(template, func(n parser.Node) {
+		for _, attr := range constantAttributes(n) {
+			if link, ok := urlLink(src, positions, attr); ok {
+				links = append(links, link)
+			}
+		}
+		switch n := n.(type) {
+		case parser.TemplElementExpression:
+			if link, ok := componentLinkOf(templURI, src, positions, n.Expression); ok {
+				links = append(links, link)
+			}
+		case parser.CallTemplateExpression:
+			if link, ok := componentLinkOf(templURI, src, positions, n.Expression); ok {
+				links = append(links, link)
+			}
+		}
+	})
+	return links
+}
+
+// urlLink returns a link for the URL of the attribute, if it's the http or https
+// URL of an href or src attribute.
+func urlLink(src string, positions sourcePositions, attr parser.ConstantAttribute) (link lsp.DocumentLink, ok bool) {
+	if !linkAttributes[strings.ToLower(attr.Name)] {
+		return link, false
+	}
+	u, err := url.Parse(strings.TrimSpace(attr.Value))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return link, false
+	}
+	from, to, ok := attributeValue(src, attr)
+	if !ok {
+		return link, false
+	}
+	return lsp.DocumentLink{
+		Range:  positions.rangeOf(from, to),
+		Target: lsp.DocumentURI(u.String()),
+	}, true
+}
+
+// componentLinkOf returns a link for the name of the component that's called,
+// e.g. the components.Button of @components.Button("Save").
+func componentLinkOf(templURI lsp.DocumentURI, src string, positions sourcePositions, expr parser.Expression) (link lsp.DocumentLink, ok bool) {
+	name := componentName.FindString(expr.Value)
+	if name == "" {
+		return link, false
+	}
+	from := int(expr.Range.From.Index)
+	if from < 0 || from+len(name) > len(src) || src[from:from+len(name)] != name {
+		return link, false
+	}
+	// The definition is found from the last part of the name, e.g. Button.
+	definition := from + strings.LastIndex(name, ".") + 1
+	return lsp.DocumentLink{
+		Range:   positions.rangeOf(from, from+len(name)),
+		Tooltip: "Go to " + name,
+		Data: componentLink{
+			URI:      templURI,
+			Position: positions.position(definition),
+		},
+	}, true
+}
+
+// parseComponentLink returns the data of a link to a component, if the link is
+// one. The data is decoded from JSON by the protocol, so it's encoded again to
+// read it.
+func parseComponentLink(data interface{}) (cl componentLink, ok bool) {
+	if data == nil {
+		return cl, false
+	}
+	if cl, ok = data.(componentLink); ok {
+		return cl, true
+	}
+	if err := decodeParams(data, &cl); err != nil || cl.URI == "" {
+		return cl, false
+	}
+	return cl, true
+}
+
+// locationURI returns a URI that opens the location, e.g. file:///home.templ#L12,3.
+func locationURI(loc lsp.Location) lsp.DocumentURI {
+	return lsp.DocumentURI(fmt.Sprintf("%s#L%d,%d", loc.URI, loc.Range.Start.Line+1, loc.Range.Start.Character+1))
+}
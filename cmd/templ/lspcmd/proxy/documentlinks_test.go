@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDocumentLinks(t *testing.T) {
+	input := `package main
+
+templ Page() {
+	<a href="https://templ.guide/">Docs</a>
+	<a href="/about">About</a>
+	<img src='http://example.com/logo.png'/>
+	if true {
+		@components.Button("Save")
+	}
+	@Layout() {
+		<script src="https://unpkg.com/htmx.org"></script>
+	}
+}
+`
+	template, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	uri := lsp.DocumentURI("file:///example/page.templ")
+	expected := []lsp.DocumentLink{
+		{
+			Range:  lsp.Range{Start: lsp.Position{Line: 3, Character: 10}, End: lsp.Position{Line: 3, Character: 30}},
+			Target: "https://templ.guide/",
+		},
+		{
+			Range:  lsp.Range{Start: lsp.Position{Line: 5, Character: 11}, End: lsp.Position{Line: 5, Character: 38}},
+			Target: "http://example.com/logo.png",
+		},
+		{
+			Range:   lsp.Range{Start: lsp.Position{Line: 7, Character: 3}, End: lsp.Position{Line: 7, Character: 20}},
+			Tooltip: "Go to components.Button",
+			Data:    componentLink{URI: uri, Position: lsp.Position{Line: 7, Character: 14}},
+		},
+		{
+			Range:   lsp.Range{Start: lsp.Position{Line: 9, Character: 2}, End: lsp.Position{Line: 9, Character: 8}},
+			Tooltip: "Go to Layout",
+			Data:    componentLink{URI: uri, Position: lsp.Position{Line: 9, Character: 2}},
+		},
+		{
+			Range:  lsp.Range{Start: lsp.Position{Line: 10, Character: 15}, End: lsp.Position{Line: 10, Character: 41}},
+			Target: "https://unpkg.com/htmx.org",
+		},
+	}
+	actual := documentLinks(uri, input, template)
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParseComponentLink(t *testing.T) {
+	// Data is decoded from JSON as a map.
+	data := map[string]interface{}{
+		"uri":      "file:///example/page.templ",
+		"position": map[string]interface{}{"line": 7.0, "character": 14.0},
+	}
+	actual, ok := parseComponentLink(data)
+	if !ok {
+		t.Fatal("expected the data to be parsed")
+	}
+	expected := componentLink{URI: "file:///example/page.templ", Position: lsp.Position{Line: 7, Character: 14}}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	if _, ok := parseComponentLink(nil); ok {
+		t.Error("expected links without data not to be parsed")
+	}
+}
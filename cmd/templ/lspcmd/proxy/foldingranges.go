@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// foldingRanges returns the ranges of the templ, css and script templates, and
+// of the HTML elements and control flow blocks within them, that can be folded.
+//
+// The parser only records where nodes start, so each range ends at the line
+// before its closing line, which is the first line after the contents of the
+// node that starts with its closing brace or end tag, as it does in formatted
+// templates. The closing line isn't folded, in the same way as gopls.
+func foldingRanges(src string, template parser.TemplateFile) []lsp.FoldingRange {
+	f := &folder{
+		lines: strings.Split(src, "\n"),
+	}
+	for i, n := range template.Nodes {
+		start, ok := startLine(n)
+		if !ok {
+			continue
+		}
+		// Templates end before the next Go code or template.
+		limit := len(f.lines) - 1
+		for _, next := range template.Nodes[i+1:] {
+			if line, ok := startLine(next); ok {
+				limit = line - 1
+				break
+			}
+		}
+		switch n := n.(type) {
+		case parser.HTMLTemplate:
+			closing := f.foldTemplate(start, limit)
+			f.nodes(n.Children, closing)
+		case parser.CSSTemplate, parser.ScriptTemplate:
+			f.foldTemplate(start, limit)
+		}
+	}
+	return f.ranges
+}
+
+type folder struct {
+	lines  []string
+	ranges []lsp.FoldingRange
+}
+
+// foldTemplate folds the template that starts at the start line, and returns its
+// closing line, the last line up to the limit that starts with a closing brace.
+// Unlike nodes within templates, the closing brace of a template is always at the
+// start of the line, so braces in the code of script templates aren't matched.
+func (f *folder) foldTemplate(start, limit int) (closing int) {
+	for closing = limit; closing > start; closing-- {
+		if strings.HasPrefix(f.lines[closing], "}") {
+			f.add(start, closing-1)
+			return closing
+		}
+	}
+	return limit
+}
+
+// nodes folds the nodes, and returns the last line of the last node. Each node
+// ends before the next node that has a position, or at the limit.
+func (f *folder) nodes(nodes []parser.Node, limit int) (end int) {
+	end = -1
+	for i, n := range nodes {
+		nodeLimit := limit
+		for _, next := range nodes[i+1:] {
+			if line, ok := startLine(next); ok {
+				nodeLimit = min(line, limit)
+				break
+			}
+		}
+		end = max(end, f.node(n, nodeLimit))
+	}
+	return end
+}
+
+// node folds the node, and the nodes within it, and returns its last line.
+func (f *folder) node(n parser.Node, limit int) (end int) {
+	start, ok := startLine(n)
+	if !ok {
+		return -1
+	}
+	switch n := n.(type) {
+	case parser.Element:
+		if len(n.Children) == 0 {
+			return start
+		}
+		return f.fold(start, f.nodes(n.Children, limit), limit, "</"+n.Name+">")
+	case parser.IfExpression:
+		return f.foldIf(start, n, limit)
+	case parser.SwitchExpression:
+		return f.foldSwitch(start, n, limit)
+	case parser.ForExpression:
+		return f.fold(start, f.nodes(n.Children, limit), limit, "}")
+	case parser.TemplElementExpression:
+		if len(n.Children) == 0 {
+			return start
+		}
+		return f.fold(start, f.nodes(n.Children, limit), limit, "}")
+	case parser.BlockExpression:
+		return f.fold(start, f.nodes(n.Children, limit), limit, "}")
+	}
+	return start
+}
+
+// foldIf folds each branch of the if expression. The else branch starts on the
+// line that closes the previous branch.
+func (f *folder) foldIf(start int, n parser.IfExpression, limit int) (end int) {
+	branchLimit := func(i int) int {
+		if i < len(n.ElseIfs) {
+			return min(int(n.ElseIfs[i].Expression.Range.From.Line), limit)
+		}
+		return limit
+	}
+	end = f.fold(start, f.nodes(n.Then, branchLimit(0)), branchLimit(0), "}")
+	for i, elseIf := range n.ElseIfs {
+		start := int(elseIf.Expression.Range.From.Line)
+		end = f.fold(start, f.nodes(elseIf.Then, branchLimit(i+1)), branchLimit(i+1), "}")
+	}
+	if len(n.Else) > 0 {
+		end = f.fold(end, f.nodes(n.Else, limit), limit, "}")
+	}
+	return end
+}
+
+// foldSwitch folds the switch expression, and each of its cases, which end
+// before the next case, or the closing brace of the switch.
+func (f *folder) foldSwitch(start int, n parser.SwitchExpression, limit int) (end int) {
+	caseStarts := make([]int, len(n.Cases))
+	last := start
+	for i, c := range n.Cases {
+		caseStarts[i] = int(c.Expression.Range.From.Line)
+		caseLimit := limit
+		if i+1 < len(n.Cases) {
+			caseLimit = min(int(n.Cases[i+1].Expression.Range.From.Line), limit)
+		}
+		last = max(last, caseStarts[i], f.nodes(c.Children, caseLimit))
+	}
+	end = f.fold(start, last, limit, "}")
+	for i, caseStart := range caseStarts {
+		caseEnd := end
+		if i+1 < len(caseStarts) {
+			caseEnd = caseStarts[i+1]
+		}
+		f.add(caseStart, caseEnd-1)
+	}
+	return end
+}
+
+// fold folds the node that starts at the start line, and returns its closing
+// line, the first line after the last line of its contents, up to the limit,
+// that starts with the closing text. If there isn't a closing line, the node
+// isn't folded, and the last line of its contents is returned.
+func (f *folder) fold(start, last, limit int, closing string) (end int) {
+	for line := max(start, last) + 1; line <= limit && line < len(f.lines); line++ {
+		if strings.HasPrefix(strings.TrimSpace(f.lines[line]), closing) {
+			f.add(start, line-1)
+			return line
+		}
+	}
+	return max(start, last)
+}
+
+// add adds the range, if it's more than one line.
+func (f *folder) add(start, end int) {
+	if end <= start {
+		return
+	}
+	f.ranges = append(f.ranges, lsp.FoldingRange{
+		StartLine: uint32(start),
+		EndLine:   uint32(end),
+	})
+}
+
+// startLine returns the line that the node starts on, if the parser records it.
+func startLine(n any) (line int, ok bool) {
+	switch n := n.(type) {
+	case parser.TemplateFileGoExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.HTMLTemplate:
+		return int(n.Expression.Range.From.Line), true
+	case parser.CSSTemplate:
+		return int(n.Expression.Range.From.Line), true
+	case parser.ScriptTemplate:
+		return int(n.Name.Range.From.Line), true
+	case parser.Element:
+		return int(n.NameRange.From.Line), true
+	case parser.IfExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.SwitchExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.ForExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.TemplElementExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.CallTemplateExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.StringExpression:
+		return int(n.Expression.Range.From.Line), true
+	case parser.BlockExpression:
+		return int(n.NameRange.From.Line), true
+	}
+	return 0, false
+}
@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFoldingRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []lsp.FoldingRange
+	}{
+		{
+			name: "templates and elements",
+			input: `package main
+
+templ List(items []string) {
+	<ul>
+		<li>
+			First
+		</li>
+		<li>Second</li>
+	</ul>
+}
+
+css red() {
+	color: red;
+	background: white;
+}
+
+script hello() {
+	if (true) {
+		alert("hello");
+	}
+}
+`,
+			expected: []lsp.FoldingRange{
+				{StartLine: 2, EndLine: 8},
+				{StartLine: 4, EndLine: 5},
+				{StartLine: 3, EndLine: 7},
+				{StartLine: 11, EndLine: 13},
+				{StartLine: 16, EndLine: 19},
+			},
+		},
+		{
+			name: "control flow",
+			input: `package main
+
+templ Items(items []string) {
+	for _, item := range items {
+		if item == "" {
+			<div>
+				Empty
+			</div>
+		} else if item == "-" {
+			Dash
+		} else {
+			{ item }
+		}
+	}
+	switch len(items) {
+		case 0:
+			None
+		case 1:
+			One
+	}
+}
+`,
+			expected: []lsp.FoldingRange{
+				{StartLine: 2, EndLine: 19},
+				{StartLine: 5, EndLine: 6},
+				{StartLine: 4, EndLine: 7},
+				{StartLine: 8, EndLine: 9},
+				{StartLine: 10, EndLine: 11},
+				{StartLine: 3, EndLine: 12},
+				{StartLine: 14, EndLine: 18},
+				{StartLine: 15, EndLine: 16},
+				{StartLine: 17, EndLine: 18},
+			},
+		},
+		{
+			name: "nested elements with the same name",
+			input: `package main
+
+templ Nested() {
+	<div>
+		<div>
+			Inner
+		</div>
+	</div>
+	@Layout() {
+		<p>Content</p>
+		<p>More</p>
+	}
+}
+`,
+			expected: []lsp.FoldingRange{
+				{StartLine: 2, EndLine: 11},
+				{StartLine: 4, EndLine: 5},
+				{StartLine: 3, EndLine: 6},
+				{StartLine: 8, EndLine: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			actual := foldingRanges(tt.input, template)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
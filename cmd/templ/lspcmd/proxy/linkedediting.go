@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// linkedEditingRanges is the result of a textDocument/linkedEditingRange
+// request, which the protocol package doesn't have a type for.
+type linkedEditingRanges struct {
+	Ranges []lsp.Range `json:"ranges"`
+}
+
+// tagNameRanges returns the ranges of the names of the start and end tags of the
+// element that has a tag name at the position. Void and self-closing elements
+// don't have an end tag, so there are no ranges to edit together.
+func tagNameRanges(src string, template parser.TemplateFile, pos lsp.Position) (ranges []lsp.Range, ok bool) {
+	lineStarts := newSourcePositions(src)
+	if int(pos.Line) >= len(lineStarts) {
+		return nil, false
+	}
+	index := lineStarts[pos.Line] + int(pos.Character)
+	walkTemplates(template, func(n parser.Node) {
+		e, isElement := n.(parser.Element)
+		if ok || !isElement {
+			return
+		}
+		start := span{from: int(e.NameRange.From.Index), to: int(e.NameRange.To.Index)}
+		if index < start.from {
+			return
+		}
+		_, to, hasSpan := parser.NodeSpan(src, e)
+		if !hasSpan {
+			return
+		}
+		// The end tag is at the end of the element, e.g. </div>.
+		end := span{from: to - len(e.Name) - 1, to: to - 1}
+		if end.from-2 < start.to || src[end.from-2:end.to+1] != "</"+e.Name+">" {
+			return
+		}
+		for _, s := range []span{start, end} {
+			if s.from <= index && index <= s.to {
+				ranges, ok = []lsp.Range{lineStarts.rangeOf(start.from, start.to), lineStarts.rangeOf(end.from, end.to)}, true
+			}
+		}
+	})
+	return ranges, ok
+}
@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTagNameRanges(t *testing.T) {
+	input := `package main
+
+templ List() {
+	<ul>
+		<li>Item</li>
+		<br/>
+		<img src="a.png">
+	</ul>
+}
+`
+	tests := []struct {
+		name     string
+		position lsp.Position
+		expected []lsp.Range
+	}{
+		{
+			name:     "start tags are linked to end tags",
+			position: lsp.Position{Line: 3, Character: 2},
+			expected: []lsp.Range{
+				{Start: lsp.Position{Line: 3, Character: 2}, End: lsp.Position{Line: 3, Character: 4}},
+				{Start: lsp.Position{Line: 7, Character: 3}, End: lsp.Position{Line: 7, Character: 5}},
+			},
+		},
+		{
+			name:     "end tags are linked to start tags",
+			position: lsp.Position{Line: 4, Character: 14},
+			expected: []lsp.Range{
+				{Start: lsp.Position{Line: 4, Character: 3}, End: lsp.Position{Line: 4, Character: 5}},
+				{Start: lsp.Position{Line: 4, Character: 12}, End: lsp.Position{Line: 4, Character: 14}},
+			},
+		},
+		{
+			name:     "self-closing elements have no linked ranges",
+			position: lsp.Position{Line: 5, Character: 4},
+		},
+		{
+			name:     "void elements have no linked ranges",
+			position: lsp.Position{Line: 6, Character: 4},
+		},
+		{
+			name:     "text has no linked ranges",
+			position: lsp.Position{Line: 4, Character: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := parser.ParseString(input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			actual, ok := tagNameRanges(input, template, tt.position)
+			if ok != (tt.expected != nil) {
+				t.Fatalf("expected ok to be %v, got %v", tt.expected != nil, ok)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/imports"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// organizeImports returns the edits that add the missing imports of the template,
+// and remove its unused imports, in the same way as formatting does. Unlike
+// formatting, only the Go code that contains the imports is changed, so that the
+// edits can be applied before the document is formatted, e.g. by an editor that
+// runs the source.organizeImports code action on save.
+func organizeImports(fileName, src string, template parser.TemplateFile) (edits []lsp.TextEdit, err error) {
+	processed, err := imports.Process(fileName, template)
+	if err != nil {
+		return nil, err
+	}
+	// The Go code keeps its range when its imports are updated, so it can be
+	// matched with the code in the document.
+	before := map[parser.Range]string{}
+	for _, n := range template.Nodes {
+		if e, ok := n.(parser.TemplateFileGoExpression); ok {
+			before[e.Expression.Range] = e.Expression.Value
+		}
+	}
+	for _, n := range processed.Nodes {
+		e, ok := n.(parser.TemplateFileGoExpression)
+		if !ok {
+			continue
+		}
+		value, existing := before[e.Expression.Range]
+		if !existing {
+			// Imports that are added to a file without Go code are added after the
+			// package.
+			to := template.Package.Expression.Range.To
+			edits = append(edits, lsp.TextEdit{
+				Range:   lsp.Range{Start: rangePosition(to), End: rangePosition(to)},
+				NewText: "\n\n" + e.Expression.Value,
+			})
+			continue
+		}
+		delete(before, e.Expression.Range)
+		if value != e.Expression.Value {
+			edits = append(edits, replaceGoCode(src, e.Expression.Range, e.Expression.Value))
+		}
+	}
+	// Go code that only contained unused imports is removed.
+	for r := range before {
+		edits = append(edits, replaceGoCode(src, r, ""))
+	}
+	return edits, nil
+}
+
+// replaceGoCode replaces the Go code in the range of the document, keeping the
+// whitespace around it, unless the code is removed.
+func replaceGoCode(src string, r parser.Range, value string) lsp.TextEdit {
+	if value != "" && r.From.Index >= 0 && r.To.Index <= int64(len(src)) && r.From.Index <= r.To.Index {
+		code := src[r.From.Index:r.To.Index]
+		leading := code[:len(code)-len(strings.TrimLeft(code, " \t\r\n"))]
+		trailing := code[len(strings.TrimRight(code, " \t\r\n")):]
+		value = leading + value + trailing
+	}
+	return lsp.TextEdit{
+		Range:   lsp.Range{Start: rangePosition(r.From), End: rangePosition(r.To)},
+		NewText: value,
+	}
+}
+
+func rangePosition(p parser.Position) lsp.Position {
+	return lsp.Position{Line: p.Line, Character: p.Col}
+}
@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOrganizeImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "missing imports are added after the package",
+			input: `package main
+
+templ Name(name string) {
+	<div>{ strings.ToUpper(name) }</div>
+}
+`,
+			expected: `package main
+
+import "strings"
+
+templ Name(name string) {
+	<div>{ strings.ToUpper(name) }</div>
+}
+`,
+		},
+		{
+			name: "missing imports are added to the existing imports",
+			input: `package main
+
+import "fmt"
+
+templ Name(name string) {
+	<div>{ fmt.Sprint(name) }{ strings.ToUpper(name) }</div>
+}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+templ Name(name string) {
+	<div>{ fmt.Sprint(name) }{ strings.ToUpper(name) }</div>
+}
+`,
+		},
+		{
+			name: "unused imports are removed without formatting the template",
+			input: `package main
+
+import "strings"
+
+templ Name(name string) {
+<div>{ name }</div>
+}
+`,
+			expected: `package main
+
+templ Name(name string) {
+<div>{ name }</div>
+}
+`,
+		},
+		{
+			name: "templates with the right imports are unchanged",
+			input: `package main
+
+import "strings"
+
+templ Name(name string) {
+<div>{ strings.ToUpper(name) }</div>
+}
+`,
+			expected: `package main
+
+import "strings"
+
+templ Name(name string) {
+<div>{ strings.ToUpper(name) }</div>
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			edits, err := organizeImports("/tmp/example/template.templ", tt.input, template)
+			if err != nil {
+				t.Fatalf("failed to organize imports: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, applyEdits(tt.input, edits)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// applyEdits applies non-overlapping edits to the text, in reverse order.
+func applyEdits(text string, edits []lsp.TextEdit) string {
+	lines := strings.SplitAfter(text, "\n")
+	offset := func(p lsp.Position) int {
+		var o int
+		for _, l := range lines[:p.Line] {
+			o += len(l)
+		}
+		return o + int(p.Character)
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return offset(edits[i].Range.Start) > offset(edits[j].Range.Start)
+	})
+	for _, e := range edits {
+		text = text[:offset(e.Range.Start)] + e.NewText + text[offset(e.Range.End):]
+	}
+	return text
+}
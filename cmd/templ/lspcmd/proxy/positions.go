@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"sort"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// sourcePositions converts byte indexes of a templ file to positions, with the
+// same line and column numbering as the parser.
+type sourcePositions []int
+
+func newSourcePositions(src string) (lineStarts sourcePositions) {
+	lineStarts = sourcePositions{0}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	return lineStarts
+}
+
+func (lineStarts sourcePositions) position(index int) lsp.Position {
+	line := sort.Search(len(lineStarts), func(i int) bool {
+		return lineStarts[i] > index
+	}) - 1
+	return lsp.Position{
+		Line:      uint32(line),
+		Character: uint32(index - lineStarts[line]),
+	}
+}
+
+func (lineStarts sourcePositions) rangeOf(from, to int) lsp.Range {
+	return lsp.Range{Start: lineStarts.position(from), End: lineStarts.position(to)}
+}
+
+// attributeValue returns the indexes of the start and end of the value of the
+// attribute in the source, excluding the quotes. The parser only records the
+// range of the name, so the value is found after it, e.g. href="/".
+func attributeValue(src string, attr parser.ConstantAttribute) (from, to int, ok bool) {
+	from = int(attr.NameRange.To.Index)
+	if from < 0 || from > len(src) {
+		return 0, 0, false
+	}
+	rest := src[from:]
+	trimmed := strings.TrimLeft(rest, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "=") {
+		return 0, 0, false
+	}
+	trimmed = strings.TrimLeft(trimmed[1:], " \t\r\n")
+	if trimmed == "" || (trimmed[0] != '"' && trimmed[0] != '\'') {
+		return 0, 0, false
+	}
+	from += len(rest) - len(trimmed) + 1
+	end := strings.IndexByte(src[from:], trimmed[0])
+	if end < 0 {
+		return 0, 0, false
+	}
+	return from, from + end, true
+}
+
+// walkTemplates calls f for each node of the templ templates of the file, and
+// each of the nodes within them.
+func walkTemplates(template parser.TemplateFile, f func(n parser.Node)) {
+	for _, n := range template.Nodes {
+		if t, ok := n.(parser.HTMLTemplate); ok {
+			walkNodes(t.Children, f)
+		}
+	}
+}
+
+func walkNodes(nodes []parser.Node, f func(n parser.Node)) {
+	for _, n := range nodes {
+		f(n)
+		if cn, ok := n.(parser.CompositeNode); ok {
+			walkNodes(cn.ChildNodes(), f)
+		}
+	}
+}
+
+// constantAttributes returns the constant attributes of the element, including
+// the attributes of its conditional attributes.
+func constantAttributes(n parser.Node) (attrs []parser.ConstantAttribute) {
+	var add func(attributes []parser.Attribute)
+	add = func(attributes []parser.Attribute) {
+		for _, attr := range attributes {
+			switch attr := attr.(type) {
+			case parser.ConstantAttribute:
+				attrs = append(attrs, attr)
+			case parser.ConditionalAttribute:
+				add(attr.Then)
+				add(attr.Else)
+			}
+		}
+	}
+	switch n := n.(type) {
+	case parser.Element:
+		add(n.Attributes)
+	case parser.RawElement:
+		add(n.Attributes)
+	}
+	return attrs
+}
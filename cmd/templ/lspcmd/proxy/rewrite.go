@@ -5,20 +5,43 @@ import (
 	"strings"
 
 	lsp "github.com/a-h/protocol"
+	"go.lsp.dev/uri"
+
+	"github.com/a-h/templ/cmd/templ/processor"
 )
 
-func convertTemplToGoURI(templURI lsp.DocumentURI) (isTemplFile bool, goURI lsp.DocumentURI) {
+// FileNames converts the URIs of templ files to the URIs of the Go files that
+// are generated from them, and back.
+type FileNames struct {
+	// Root is the absolute path of the directory that the templ files are in,
+	// see templ generate -path.
+	Root string
+	// OutputDir is the absolute path of the directory that the Go files are
+	// generated in, see templ generate -output-dir. If empty, the Go files are
+	// next to the templ files.
+	OutputDir string
+}
+
+func (fn FileNames) convertTemplToGoURI(templURI lsp.DocumentURI) (isTemplFile bool, goURI lsp.DocumentURI) {
 	base, fileName := path.Split(string(templURI))
 	if !strings.HasSuffix(fileName, ".templ") {
 		return
 	}
-	return true, lsp.DocumentURI(base + (strings.TrimSuffix(fileName, ".templ") + "_templ.go"))
+	if fn.OutputDir == "" {
+		return true, lsp.DocumentURI(base + (strings.TrimSuffix(fileName, ".templ") + "_templ.go"))
+	}
+	goFileName := processor.OutputFileName(fn.Root, fn.OutputDir, uri.URI(templURI).Filename(), "_templ.go")
+	return true, lsp.DocumentURI(uri.File(goFileName))
 }
 
-func convertTemplGoToTemplURI(goURI lsp.DocumentURI) (isTemplGoFile bool, templURI lsp.DocumentURI) {
+func (fn FileNames) convertTemplGoToTemplURI(goURI lsp.DocumentURI) (isTemplGoFile bool, templURI lsp.DocumentURI) {
 	base, fileName := path.Split(string(goURI))
 	if !strings.HasSuffix(fileName, "_templ.go") {
 		return
 	}
-	return true, lsp.DocumentURI(base + (strings.TrimSuffix(fileName, "_templ.go") + ".templ"))
+	if fn.OutputDir == "" {
+		return true, lsp.DocumentURI(base + (strings.TrimSuffix(fileName, "_templ.go") + ".templ"))
+	}
+	templFileName := processor.SourceFileName(fn.Root, fn.OutputDir, uri.URI(goURI).Filename(), "_templ.go")
+	return true, lsp.DocumentURI(uri.File(templFileName))
 }
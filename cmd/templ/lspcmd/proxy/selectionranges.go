@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"sort"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// span is the start and end index of a part of a templ file.
+type span struct {
+	from, to int
+}
+
+func (s span) contains(other span) bool {
+	return s.from <= other.from && other.to <= s.to
+}
+
+// selectionRanges returns the ranges that the selection at each of the positions
+// expands through, from the innermost, e.g. a Go expression or the value of an
+// attribute, to the attribute, the elements and blocks that contain it, and the
+// template.
+func selectionRanges(src string, template parser.TemplateFile, positions []lsp.Position) (result []lsp.SelectionRange) {
+	lineStarts := newSourcePositions(src)
+	spans := selectionSpans(src, template)
+	for _, pos := range positions {
+		index := len(src)
+		if int(pos.Line) < len(lineStarts) {
+			index = min(lineStarts[pos.Line]+int(pos.Character), len(src))
+		}
+		var containing []span
+		for _, s := range spans {
+			if s.from <= index && index <= s.to {
+				containing = append(containing, s)
+			}
+		}
+		sort.SliceStable(containing, func(i, j int) bool {
+			return containing[i].to-containing[i].from < containing[j].to-containing[j].from
+		})
+		// Each range must contain the previous range.
+		var chain []span
+		for _, s := range containing {
+			if len(chain) == 0 || (s != chain[len(chain)-1] && s.contains(chain[len(chain)-1])) {
+				chain = append(chain, s)
+			}
+		}
+		if len(chain) == 0 {
+			chain = []span{{from: index, to: index}}
+		}
+		var sr *lsp.SelectionRange
+		for i := len(chain) - 1; i >= 0; i-- {
+			sr = &lsp.SelectionRange{
+				Range:  lineStarts.rangeOf(chain[i].from, chain[i].to),
+				Parent: sr,
+			}
+		}
+		result = append(result, *sr)
+	}
+	return result
+}
+
+// selectionSpans returns the spans of the templ templates of the file, and of
+// the nodes, attributes and Go expressions within them.
+func selectionSpans(src string, template parser.TemplateFile) (spans []span) {
+	add := func(from, to int) {
+		if from >= 0 && from <= to && to <= len(src) {
+			spans = append(spans, span{from: from, to: to})
+		}
+	}
+	addExpression := func(e parser.Expression) {
+		add(int(e.Range.From.Index), int(e.Range.To.Index))
+	}
+	lineStarts := newSourcePositions(src)
+	for _, n := range template.Nodes {
+		t, ok := n.(parser.HTMLTemplate)
+		if !ok {
+			continue
+		}
+		// Templates start at the start of the line of the templ keyword, and end at
+		// the first line that starts with a closing brace.
+		from := int(t.Expression.Range.To.Index)
+		if from > len(src) {
+			continue
+		}
+		if end := strings.Index(src[from:], "\n}"); end >= 0 {
+			add(lineStarts[t.Expression.Range.From.Line], from+end+2)
+		}
+		addExpression(t.Expression)
+		parser.Inspect(t, func(n any) bool {
+			switch n := n.(type) {
+			case parser.Node:
+				if from, to, ok := parser.NodeSpan(src, n); ok {
+					add(from, to)
+				}
+			case parser.Attribute:
+				spans = append(spans, attributeSpans(src, n)...)
+			}
+			switch n := n.(type) {
+			case parser.Element:
+				add(int(n.NameRange.From.Index), int(n.NameRange.To.Index))
+			case parser.BlockExpression:
+				add(int(n.NameRange.From.Index), int(n.NameRange.To.Index))
+			case parser.IfExpression:
+				addExpression(n.Expression)
+			case parser.ElseIfExpression:
+				addExpression(n.Expression)
+			case parser.ForExpression:
+				addExpression(n.Expression)
+			case parser.SwitchExpression:
+				addExpression(n.Expression)
+			case parser.CaseExpression:
+				addExpression(n.Expression)
+			case parser.TemplElementExpression:
+				addExpression(n.Expression)
+			case parser.CallTemplateExpression:
+				addExpression(n.Expression)
+			case parser.StringExpression:
+				addExpression(n.Expression)
+			}
+			return true
+		})
+	}
+	return spans
+}
+
+// attributeSpans returns the spans of the attribute, its name, and its value.
+func attributeSpans(src string, attr parser.Attribute) (spans []span) {
+	switch attr := attr.(type) {
+	case parser.BoolConstantAttribute:
+		spans = append(spans, span{from: int(attr.NameRange.From.Index), to: int(attr.NameRange.To.Index)})
+	case parser.ConstantAttribute:
+		name := span{from: int(attr.NameRange.From.Index), to: int(attr.NameRange.To.Index)}
+		spans = append(spans, name)
+		if from, to, ok := attributeValue(src, attr); ok {
+			// The attribute includes the closing quote of the value.
+			spans = append(spans, span{from: from, to: to}, span{from: name.from, to: to + 1})
+		}
+	case parser.ExpressionAttribute:
+		name := span{from: int(attr.NameRange.From.Index), to: int(attr.NameRange.To.Index)}
+		value := span{from: int(attr.Expression.Range.From.Index), to: int(attr.Expression.Range.To.Index)}
+		spans = append(spans, name, value)
+		if value.to <= len(src) {
+			if end := strings.IndexByte(src[value.to:], '}'); end >= 0 {
+				spans = append(spans, span{from: name.from, to: value.to + end + 1})
+			}
+		}
+	}
+	return spans
+}
@@ -0,0 +1,91 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelectionRanges(t *testing.T) {
+	input := `package main
+
+templ List(items []string) {
+	<ul class="list" id={ "items" }>
+		for _, item := range items {
+			<li>{ item }</li>
+		}
+	</ul>
+}
+`
+	tests := []struct {
+		name     string
+		position lsp.Position
+		expected []string
+	}{
+		{
+			name:     "expressions expand to elements, blocks and the template",
+			position: lsp.Position{Line: 5, Character: 12},
+			expected: []string{
+				"item",
+				"{ item }",
+				"<li>{ item }</li>",
+				"for _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}",
+				"<ul class=\"list\" id={ \"items\" }>\n\t\tfor _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}\n\t</ul>",
+				input[len("package main\n\n") : len(input)-1],
+			},
+		},
+		{
+			name:     "attribute values expand to the attribute",
+			position: lsp.Position{Line: 3, Character: 14},
+			expected: []string{
+				"list",
+				"class=\"list\"",
+				"<ul class=\"list\" id={ \"items\" }>\n\t\tfor _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}\n\t</ul>",
+				input[len("package main\n\n") : len(input)-1],
+			},
+		},
+		{
+			name:     "expression attributes expand to the attribute",
+			position: lsp.Position{Line: 3, Character: 25},
+			expected: []string{
+				"\"items\"",
+				"id={ \"items\" }",
+				"<ul class=\"list\" id={ \"items\" }>\n\t\tfor _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}\n\t</ul>",
+				input[len("package main\n\n") : len(input)-1],
+			},
+		},
+		{
+			name:     "positions outside of templates are empty ranges",
+			position: lsp.Position{Line: 0, Character: 3},
+			expected: []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := parser.ParseString(input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			result := selectionRanges(input, template, []lsp.Position{tt.position})
+			if len(result) != 1 {
+				t.Fatalf("expected 1 selection range, got %d", len(result))
+			}
+			var actual []string
+			for sr := &result[0]; sr != nil; sr = sr.Parent {
+				actual = append(actual, textOf(input, sr.Range))
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func textOf(src string, r lsp.Range) string {
+	lineStarts := newSourcePositions(src)
+	from := lineStarts[r.Start.Line] + int(r.Start.Character)
+	to := lineStarts[r.End.Line] + int(r.End.Character)
+	return src[from:to]
+}
@@ -2,14 +2,18 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/a-h/parse"
 	lsp "github.com/a-h/protocol"
 	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/fmtcmd"
 	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/imports"
 	"github.com/a-h/templ/parser/v2"
 	"go.lsp.dev/uri"
 	"go.uber.org/zap"
@@ -37,6 +41,12 @@ type Server struct {
 	DiagnosticCache *DiagnosticCache
 	TemplSource     *DocumentContents
 	GoSource        map[string]string
+	// CustomElementSnippets are completions for the custom elements declared in the workspace.
+	CustomElementSnippets []lsp.CompletionItem
+	// NoSnippets disables the completions of templ declarations and control flow statements.
+	NoSnippets bool
+	// FileNames converts the URIs of templ files to the URIs of their generated Go files.
+	FileNames FileNames
 }
 
 func NewServer(log *zap.Logger, target lsp.Server, cache *SourceMapCache, diagnosticCache *DiagnosticCache) (s *Server, init func(lsp.Client)) {
@@ -57,7 +67,7 @@ func NewServer(log *zap.Logger, target lsp.Server, cache *SourceMapCache, diagno
 func (p *Server) updatePosition(templURI lsp.DocumentURI, current lsp.Position) (ok bool, goURI lsp.DocumentURI, updated lsp.Position) {
 	log := p.Log.With(zap.String("uri", string(templURI)))
 	var isTemplFile bool
-	if isTemplFile, goURI = convertTemplToGoURI(templURI); !isTemplFile {
+	if isTemplFile, goURI = p.FileNames.convertTemplToGoURI(templURI); !isTemplFile {
 		return false, templURI, current
 	}
 	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
@@ -84,16 +94,13 @@ func (p *Server) convertTemplRangeToGoRange(templURI lsp.DocumentURI, input lsp.
 	if !ok {
 		return
 	}
-	// Map from the source position to target Go position.
-	start, ok := sourceMap.TargetPositionFromSource(input.Start.Line, input.Start.Character)
-	if ok {
-		output.Start.Line = start.Line
-		output.Start.Character = start.Col
-	}
-	end, ok := sourceMap.TargetPositionFromSource(input.End.Line, input.End.Character)
+	// Map from the source range to the target Go range.
+	r, ok := sourceMap.TargetRangeFromSource(
+		parser.NewPosition(0, input.Start.Line, input.Start.Character),
+		parser.NewPosition(0, input.End.Line, input.End.Character),
+	)
 	if ok {
-		output.End.Line = end.Line
-		output.End.Character = end.Col
+		output = lsp.Range{Start: rangePosition(r.From), End: rangePosition(r.To)}
 	}
 	return
 }
@@ -104,46 +111,67 @@ func (p *Server) convertGoRangeToTemplRange(templURI lsp.DocumentURI, input lsp.
 	if !ok {
 		return
 	}
-	// Map from the source position to target Go position.
-	start, ok := sourceMap.SourcePositionFromTarget(input.Start.Line, input.Start.Character)
+	// Map from the target Go range to the source range.
+	r, ok := sourceMap.SourceRangeFromTarget(
+		parser.NewPosition(0, input.Start.Line, input.Start.Character),
+		parser.NewPosition(0, input.End.Line, input.End.Character),
+	)
 	if ok {
-		output.Start.Line = start.Line
-		output.Start.Character = start.Col
-	}
-	end, ok := sourceMap.SourcePositionFromTarget(input.End.Line, input.End.Character)
-	if ok {
-		output.End.Line = end.Line
-		output.End.Character = end.Col
+		output = lsp.Range{Start: rangePosition(r.From), End: rangePosition(r.To)}
 	}
 	return
 }
 
 // parseTemplate parses the templ file content, and notifies the end user via the LSP about how it went.
 func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText string) (template parser.TemplateFile, ok bool, err error) {
-	template, err = parser.ParseString(templateText)
+	// Only the templates that were edited are parsed again.
+	parseString := parser.ParseString
+	if d, ok := p.TemplSource.Get(string(uri)); ok {
+		parseString = d.Parser.ParseString
+	}
+	template, err = parseString(templateText)
 	if err != nil {
 		msg := &lsp.PublishDiagnosticsParams{
 			URI: uri,
-			Diagnostics: []lsp.Diagnostic{
-				{
-					Severity: lsp.DiagnosticSeverityError,
-					Code:     "",
-					Source:   "templ",
-					Message:  err.Error(),
-				},
-			},
 		}
+		// All of the errors in the file are reported at once.
+		parseErrors, isParseErrors := err.(parser.ParseErrors)
 		if pe, isParserError := err.(parse.ParseError); isParserError {
-			msg.Diagnostics[0].Range = lsp.Range{
-				Start: lsp.Position{
-					Line:      uint32(pe.Pos.Line),
-					Character: uint32(pe.Pos.Col),
-				},
-				End: lsp.Position{
-					Line:      uint32(pe.Pos.Line),
-					Character: uint32(pe.Pos.Col),
+			parseErrors, isParseErrors = parser.ParseErrors{pe}, true
+		}
+		if !isParseErrors {
+			msg.Diagnostics = append(msg.Diagnostics, lsp.Diagnostic{
+				Severity: lsp.DiagnosticSeverityError,
+				Code:     "",
+				Source:   "templ",
+				Message:  err.Error(),
+			})
+		}
+		for _, pe := range parseErrors {
+			d := lsp.Diagnostic{
+				Severity: lsp.DiagnosticSeverityError,
+				Code:     "",
+				Source:   "templ",
+				Message:  pe.Error(),
+				Range: lsp.Range{
+					Start: lsp.Position{
+						Line:      uint32(pe.Pos.Line),
+						Character: uint32(pe.Pos.Col),
+					},
+					End: lsp.Position{
+						Line:      uint32(pe.Pos.Line),
+						Character: uint32(pe.Pos.Col),
+					},
 				},
 			}
+			if help, ok := parser.Help(templateText, pe); ok {
+				d.Code = help.Code
+				d.CodeDescription = &lsp.CodeDescription{Href: lsp.URI(help.URL())}
+				if help.Suggestion != "" {
+					d.Message += "\n" + help.Suggestion
+				}
+			}
+			msg.Diagnostics = append(msg.Diagnostics, d)
 		}
 		msg.Diagnostics = p.DiagnosticCache.AddGoDiagnostics(string(uri), msg.Diagnostics)
 		err = p.Client.PublishDiagnostics(ctx, msg)
@@ -218,6 +246,12 @@ func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (
 	}
 	result.Capabilities.ExecuteCommandProvider.Commands = []string{}
 	result.Capabilities.DocumentFormattingProvider = true
+	result.Capabilities.FoldingRangeProvider = true
+	result.Capabilities.ColorProvider = true
+	result.Capabilities.SelectionRangeProvider = true
+	result.Capabilities.LinkedEditingRangeProvider = true
+	result.Capabilities.DocumentLinkProvider = &lsp.DocumentLinkOptions{ResolveProvider: true}
+	result.Capabilities.CodeActionProvider = withOrganizeImports(result.Capabilities.CodeActionProvider)
 	result.Capabilities.SemanticTokensProvider = nil
 	result.Capabilities.DocumentRangeFormattingProvider = false
 	result.Capabilities.TextDocumentSync = lsp.TextDocumentSyncOptions{
@@ -231,9 +265,31 @@ func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (
 	result.ServerInfo.Name = "templ-lsp"
 	result.ServerInfo.Version = templ.Version()
 
+	p.loadCustomElements(params)
+
 	return result, err
 }
 
+// loadCustomElements loads completions for the custom elements declared in the
+// root of each workspace folder.
+func (p *Server) loadCustomElements(params *lsp.InitializeParams) {
+	dirs := make([]string, 0, len(params.WorkspaceFolders)+1)
+	for _, wf := range params.WorkspaceFolders {
+		dirs = append(dirs, uri.URI(wf.URI).Filename())
+	}
+	if len(dirs) == 0 && params.RootURI != "" {
+		dirs = append(dirs, params.RootURI.Filename())
+	}
+	for _, dir := range dirs {
+		snippets, err := loadCustomElementSnippets(dir)
+		if err != nil {
+			p.Log.Warn("failed to load custom elements", zap.String("dir", dir), zap.Error(err))
+			continue
+		}
+		p.CustomElementSnippets = append(p.CustomElementSnippets, snippets...)
+	}
+}
+
 func (p *Server) Initialized(ctx context.Context, params *lsp.InitializedParams) (err error) {
 	p.Log.Info("client -> server: Initialized")
 	defer p.Log.Info("client -> server: Initialized end")
@@ -273,39 +329,144 @@ func (p *Server) SetTrace(ctx context.Context, params *lsp.SetTraceParams) (err
 func (p *Server) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (result []lsp.CodeAction, err error) {
 	p.Log.Info("client -> server: CodeAction")
 	defer p.Log.Info("client -> server: CodeAction end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.CodeAction(ctx, params)
 	}
 	templURI := params.TextDocument.URI
+	// Imports are organized by templ, since gopls would organize the imports of
+	// the generated Go code.
+	if includesKind(params.Context.Only, lsp.SourceOrganizeImports) {
+		if action, ok := p.organizeImportsAction(ctx, templURI); ok {
+			result = append(result, action)
+		}
+		if onlyKind(params.Context.Only, lsp.SourceOrganizeImports) {
+			return result, nil
+		}
+	}
 	params.TextDocument.URI = goURI
-	result, err = p.Target.CodeAction(ctx, params)
+	actions, err := p.Target.CodeAction(ctx, params)
 	if err != nil {
 		return
 	}
-	for i := 0; i < len(result); i++ {
-		r := result[i]
+	for i := 0; i < len(actions); i++ {
+		r := actions[i]
+		if r.Kind == lsp.SourceOrganizeImports {
+			continue
+		}
 		// Rewrite the Diagnostics range field.
 		for di := 0; di < len(r.Diagnostics); di++ {
 			r.Diagnostics[di].Range = p.convertGoRangeToTemplRange(templURI, r.Diagnostics[di].Range)
 		}
 		// Rewrite the DocumentChanges.
-		for dci := 0; dci < len(r.Edit.DocumentChanges); dci++ {
-			dc := r.Edit.DocumentChanges[0]
-			for ei := 0; ei < len(dc.Edits); ei++ {
-				dc.Edits[ei].Range = p.convertGoRangeToTemplRange(templURI, dc.Edits[ei].Range)
+		if r.Edit != nil {
+			for dci := 0; dci < len(r.Edit.DocumentChanges); dci++ {
+				dc := r.Edit.DocumentChanges[dci]
+				for ei := 0; ei < len(dc.Edits); ei++ {
+					dc.Edits[ei].Range = p.convertGoRangeToTemplRange(templURI, dc.Edits[ei].Range)
+				}
+				dc.TextDocument.URI = templURI
+				r.Edit.DocumentChanges[dci] = dc
 			}
-			dc.TextDocument.URI = templURI
 		}
-		result[i] = r
+		result = append(result, r)
 	}
 	return
 }
 
+// organizeImportsAction returns the source.organizeImports code action of the
+// templ file, if its imports need to change.
+func (p *Server) organizeImportsAction(ctx context.Context, templURI lsp.DocumentURI) (action lsp.CodeAction, ok bool) {
+	d, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return action, false
+	}
+	template, ok, err := p.parseTemplate(ctx, templURI, d.String())
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		return action, false
+	}
+	edits, err := organizeImports(templURI.Filename(), d.String(), template)
+	if err != nil {
+		p.Log.Warn("failed to organize imports", zap.Error(err))
+		return action, false
+	}
+	if len(edits) == 0 {
+		return action, false
+	}
+	return lsp.CodeAction{
+		Title: "Organize Imports",
+		Kind:  lsp.SourceOrganizeImports,
+		Edit: &lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{templURI: edits},
+		},
+	}, true
+}
+
+// includesKind returns true if the code actions of the kind are requested. If
+// no kinds are requested, all kinds are.
+func includesKind(only []lsp.CodeActionKind, kind lsp.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, k := range only {
+		if k == kind || strings.HasPrefix(string(kind), string(k)+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyKind returns true if only the code actions of the kind are requested.
+func onlyKind(only []lsp.CodeActionKind, kind lsp.CodeActionKind) bool {
+	if len(only) == 0 {
+		return false
+	}
+	for _, k := range only {
+		if k != kind {
+			return false
+		}
+	}
+	return true
+}
+
+// withOrganizeImports adds source.organizeImports to the code action kinds that
+// gopls provides. If gopls doesn't list the kinds, e.g. because the client can't
+// request them, the provider is returned as it is.
+func withOrganizeImports(provider interface{}) interface{} {
+	var kinds []lsp.CodeActionKind
+	switch provider := provider.(type) {
+	case lsp.CodeActionOptions:
+		kinds = provider.CodeActionKinds
+	case *lsp.CodeActionOptions:
+		if provider == nil {
+			return provider
+		}
+		kinds = provider.CodeActionKinds
+	case map[string]interface{}:
+		values, _ := provider["codeActionKinds"].([]interface{})
+		for _, v := range values {
+			if k, ok := v.(string); ok {
+				kinds = append(kinds, lsp.CodeActionKind(k))
+			}
+		}
+	default:
+		return provider
+	}
+	for _, k := range kinds {
+		if k == lsp.SourceOrganizeImports {
+			return lsp.CodeActionOptions{CodeActionKinds: kinds}
+		}
+	}
+	return lsp.CodeActionOptions{CodeActionKinds: append(kinds, lsp.SourceOrganizeImports)}
+}
+
 func (p *Server) CodeLens(ctx context.Context, params *lsp.CodeLensParams) (result []lsp.CodeLens, err error) {
 	p.Log.Info("client -> server: CodeLens")
 	defer p.Log.Info("client -> server: CodeLens end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.CodeLens(ctx, params)
 	}
@@ -335,43 +496,34 @@ func (p *Server) CodeLensResolve(ctx context.Context, params *lsp.CodeLens) (res
 func (p *Server) ColorPresentation(ctx context.Context, params *lsp.ColorPresentationParams) (result []lsp.ColorPresentation, err error) {
 	p.Log.Info("client -> server: ColorPresentation ColorPresentation")
 	defer p.Log.Info("client -> server: ColorPresentation end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, _ := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.ColorPresentation(ctx, params)
 	}
-	templURI := params.TextDocument.URI
-	params.TextDocument.URI = goURI
-	result, err = p.Target.ColorPresentation(ctx, params)
-	if err != nil {
-		return
-	}
-	if result == nil {
-		return
-	}
-	for i := 0; i < len(result); i++ {
-		r := result[i]
-		if r.TextEdit != nil {
-			r.TextEdit.Range = p.convertGoRangeToTemplRange(templURI, r.TextEdit.Range)
-		}
-		result[i] = r
-	}
-	return
+	return colorPresentations(params.Color, params.Range), nil
 }
 
 func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (result *lsp.CompletionList, err error) {
 	p.Log.Info("client -> server: Completion")
 	defer p.Log.Info("client -> server: Completion end")
 	if params.Context != nil && params.Context.TriggerCharacter == "<" {
+		items := make([]lsp.CompletionItem, 0, len(htmlSnippets)+len(p.CustomElementSnippets))
+		items = append(items, htmlSnippets...)
+		items = append(items, p.CustomElementSnippets...)
 		result = &lsp.CompletionList{
-			Items: htmlSnippets,
+			Items: items,
 		}
 		return
 	}
 	// Get the sourcemap from the cache.
 	templURI := params.TextDocument.URI
+	snippets := p.completionSnippets(templURI, params.TextDocumentPositionParams.Position)
 	var ok bool
 	ok, params.TextDocument.URI, params.TextDocumentPositionParams.Position = p.updatePosition(templURI, params.TextDocumentPositionParams.Position)
 	if !ok {
+		if len(snippets) > 0 {
+			return &lsp.CompletionList{Items: snippets}, nil
+		}
 		return nil, nil
 	}
 	// Call the target.
@@ -381,6 +533,9 @@ func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (
 		return
 	}
 	if result == nil {
+		if len(snippets) > 0 {
+			result = &lsp.CompletionList{Items: snippets}
+		}
 		return
 	}
 	// Rewrite the result positions.
@@ -409,9 +564,24 @@ func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (
 		}
 		result.Items[i] = item
 	}
+	result.Items = append(result.Items, snippets...)
 	return
 }
 
+// completionSnippets returns the snippets of templ declarations and control flow
+// statements that can be completed at the position, unless they're disabled.
+func (p *Server) completionSnippets(templURI lsp.DocumentURI, pos lsp.Position) []lsp.CompletionItem {
+	if p.NoSnippets {
+		return nil
+	}
+	doc, ok := p.TemplSource.Get(string(templURI))
+	if !ok || int(pos.Line) >= len(doc.Lines) {
+		return nil
+	}
+	line := doc.Lines[pos.Line]
+	return snippetsFor(line[:min(int(pos.Character), len(line))])
+}
+
 var completionWithImport = regexp.MustCompile(`^.*\(from\s(".+")\)$`)
 
 func getPackageFromItemDetail(pkg string) string {
@@ -487,7 +657,7 @@ func (p *Server) Declaration(ctx context.Context, params *lsp.DeclarationParams)
 		return
 	}
 	for i := 0; i < len(result); i++ {
-		if isTemplGoFile, templURI := convertTemplGoToTemplURI(result[i].URI); isTemplGoFile {
+		if isTemplGoFile, templURI := p.FileNames.convertTemplGoToTemplURI(result[i].URI); isTemplGoFile {
 			result[i].URI = templURI
 			result[i].Range = p.convertGoRangeToTemplRange(templURI, result[i].Range)
 		}
@@ -514,7 +684,7 @@ func (p *Server) Definition(ctx context.Context, params *lsp.DefinitionParams) (
 		return
 	}
 	for i := 0; i < len(result); i++ {
-		if isTemplGoFile, templURI := convertTemplGoToTemplURI(result[i].URI); isTemplGoFile {
+		if isTemplGoFile, templURI := p.FileNames.convertTemplGoToTemplURI(result[i].URI); isTemplGoFile {
 			result[i].URI = templURI
 			result[i].Range = p.convertGoRangeToTemplRange(templURI, result[i].Range)
 		}
@@ -525,7 +695,7 @@ func (p *Server) Definition(ctx context.Context, params *lsp.DefinitionParams) (
 func (p *Server) DidChange(ctx context.Context, params *lsp.DidChangeTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidChange", zap.Any("params", params))
 	defer p.Log.Info("client -> server: DidChange end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		p.Log.Error("not a templ file")
 		return
@@ -586,7 +756,7 @@ func (p *Server) DidChangeWorkspaceFolders(ctx context.Context, params *lsp.DidC
 func (p *Server) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidClose")
 	defer p.Log.Info("client -> server: DidClose end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.DidClose(ctx, params)
 	}
@@ -601,7 +771,7 @@ func (p *Server) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentP
 func (p *Server) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidOpen", zap.String("uri", string(params.TextDocument.URI)))
 	defer p.Log.Info("client -> server: DidOpen end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.DidOpen(ctx, params)
 	}
@@ -636,7 +806,7 @@ func (p *Server) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentPar
 func (p *Server) DidSave(ctx context.Context, params *lsp.DidSaveTextDocumentParams) (err error) {
 	p.Log.Info("client -> server: DidSave")
 	defer p.Log.Info("client -> server: DidSave end")
-	if isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI); isTemplFile {
+	if isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI); isTemplFile {
 		params.TextDocument.URI = goURI
 	}
 	return p.Target.DidSave(ctx, params)
@@ -645,23 +815,27 @@ func (p *Server) DidSave(ctx context.Context, params *lsp.DidSaveTextDocumentPar
 func (p *Server) DocumentColor(ctx context.Context, params *lsp.DocumentColorParams) (result []lsp.ColorInformation, err error) {
 	p.Log.Info("client -> server: DocumentColor")
 	defer p.Log.Info("client -> server: DocumentColor end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, _ := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return p.Target.DocumentColor(ctx, params)
 	}
-	templURI := params.TextDocument.URI
-	params.TextDocument.URI = goURI
-	result, err = p.Target.DocumentColor(ctx, params)
+	// The colors are in the CSS of the templ file, which gopls doesn't see.
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return []lsp.ColorInformation{}, nil
+	}
+	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, d.String())
 	if err != nil {
-		return
+		p.Log.Error("parseTemplate failure", zap.Error(err))
 	}
-	if result == nil {
-		return
+	if !ok {
+		return []lsp.ColorInformation{}, nil
 	}
-	for i := 0; i < len(result); i++ {
-		result[i].Range = p.convertGoRangeToTemplRange(templURI, result[i].Range)
+	result = documentColors(d.String(), template)
+	if result == nil {
+		result = []lsp.ColorInformation{}
 	}
-	return
+	return result, nil
 }
 
 func (p *Server) DocumentHighlight(ctx context.Context, params *lsp.DocumentHighlightParams) (result []lsp.DocumentHighlight, err error) {
@@ -673,13 +847,44 @@ func (p *Server) DocumentHighlight(ctx context.Context, params *lsp.DocumentHigh
 func (p *Server) DocumentLink(ctx context.Context, params *lsp.DocumentLinkParams) (result []lsp.DocumentLink, err error) {
 	p.Log.Info("client -> server: DocumentLink", zap.String("uri", string(params.TextDocument.URI)))
 	defer p.Log.Info("client -> server: DocumentLink end")
-	return
+	isTemplFile, _ := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
+	if !isTemplFile {
+		return p.Target.DocumentLink(ctx, params)
+	}
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return
+	}
+	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, d.String())
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		return nil, nil
+	}
+	return documentLinks(params.TextDocument.URI, d.String(), template), nil
 }
 
 func (p *Server) DocumentLinkResolve(ctx context.Context, params *lsp.DocumentLink) (result *lsp.DocumentLink, err error) {
 	p.Log.Info("client -> server: DocumentLinkResolve")
 	defer p.Log.Info("client -> server: DocumentLinkResolve end")
-	isTemplFile, goURI := convertTemplToGoURI(params.Target)
+	// Links to components are resolved to the definition of the component.
+	if cl, ok := parseComponentLink(params.Data); ok {
+		locations, err := p.Definition(ctx, &lsp.DefinitionParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: cl.URI},
+				Position:     cl.Position,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(locations) > 0 {
+			params.Target = locationURI(locations[0])
+		}
+		return params, nil
+	}
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.Target)
 	if !isTemplFile {
 		return p.Target.DocumentLinkResolve(ctx, params)
 	}
@@ -716,9 +921,26 @@ func (p *Server) ExecuteCommand(ctx context.Context, params *lsp.ExecuteCommandP
 func (p *Server) FoldingRanges(ctx context.Context, params *lsp.FoldingRangeParams) (result []lsp.FoldingRange, err error) {
 	p.Log.Info("client -> server: FoldingRanges")
 	defer p.Log.Info("client -> server: FoldingRanges end")
-	// There are no folding ranges in templ files.
-	// return p.Target.FoldingRanges(ctx, params)
-	return []lsp.FoldingRange{}, nil
+	isTemplFile, _ := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
+	if !isTemplFile {
+		return p.Target.FoldingRanges(ctx, params)
+	}
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return []lsp.FoldingRange{}, nil
+	}
+	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, d.String())
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		return []lsp.FoldingRange{}, nil
+	}
+	result = foldingRanges(d.String(), template)
+	if result == nil {
+		result = []lsp.FoldingRange{}
+	}
+	return result, nil
 }
 
 func (p *Server) Formatting(ctx context.Context, params *lsp.DocumentFormattingParams) (result []lsp.TextEdit, err error) {
@@ -730,11 +952,25 @@ func (p *Server) Formatting(ctx context.Context, params *lsp.DocumentFormattingP
 	if err != nil {
 		p.Log.Error("parseTemplate failure", zap.Error(err))
 	}
+	opts, err := fmtcmd.LoadOptions(filepath.Dir(params.TextDocument.URI.Filename()))
+	if err != nil {
+		p.Log.Warn("failed to load format options, using defaults", zap.Error(err))
+	}
+	if opts.Parse.OptionalEndTags {
+		// The end tags that were left out are added by formatting.
+		template, err = parser.ParseStringWithOptions(d.String(), opts.Parse)
+		ok = err == nil
+	}
 	if !ok {
 		return
 	}
+	if processed, err := imports.Process(params.TextDocument.URI.Filename(), template); err != nil {
+		p.Log.Warn("failed to update imports", zap.Error(err))
+	} else {
+		template = processed
+	}
 	w := new(strings.Builder)
-	err = template.Write(w)
+	err = template.WriteWithOptions(w, opts.Format)
 	if err != nil {
 		p.Log.Error("handleFormatting: faled to write template", zap.Error(err))
 		return
@@ -858,7 +1094,7 @@ func (p *Server) RangeFormatting(ctx context.Context, params *lsp.DocumentRangeF
 	templURI := params.TextDocument.URI
 	// Rewrite the request.
 	var isTemplURI bool
-	isTemplURI, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
+	isTemplURI, params.TextDocument.URI = p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplURI {
 		err = fmt.Errorf("not a templ file")
 		return
@@ -883,7 +1119,7 @@ func (p *Server) References(ctx context.Context, params *lsp.ReferenceParams) (r
 	templURI := params.TextDocument.URI
 	// Rewrite the request.
 	var isTemplURI bool
-	isTemplURI, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
+	isTemplURI, params.TextDocument.URI = p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplURI {
 		err = fmt.Errorf("not a templ file")
 		return
@@ -941,7 +1177,7 @@ func (p *Server) WillSave(ctx context.Context, params *lsp.WillSaveTextDocumentP
 	p.Log.Info("client -> server: WillSave")
 	defer p.Log.Info("client -> server: WillSave end")
 	var ok bool
-	ok, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
+	ok, params.TextDocument.URI = p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !ok {
 		p.Log.Error("not a templ file")
 		return nil
@@ -952,7 +1188,15 @@ func (p *Server) WillSave(ctx context.Context, params *lsp.WillSaveTextDocumentP
 func (p *Server) WillSaveWaitUntil(ctx context.Context, params *lsp.WillSaveTextDocumentParams) (result []lsp.TextEdit, err error) {
 	p.Log.Info("client -> server: WillSaveWaitUntil")
 	defer p.Log.Info("client -> server: WillSaveWaitUntil end")
-	return p.Target.WillSaveWaitUntil(ctx, params)
+	if isTemplFile, _ := p.FileNames.convertTemplToGoURI(params.TextDocument.URI); !isTemplFile {
+		return p.Target.WillSaveWaitUntil(ctx, params)
+	}
+	// Like gopls, willSaveWaitUntil isn't advertised, but clients that send it
+	// get the same edits as Formatting, which also organizes the imports. Since
+	// the edits of the source.organizeImports code action are the same as the
+	// import changes that formatting makes, formatting after organizing imports
+	// doesn't change the imports again.
+	return p.Formatting(ctx, &lsp.DocumentFormattingParams{TextDocument: params.TextDocument})
 }
 
 func (p *Server) ShowDocument(ctx context.Context, params *lsp.ShowDocumentParams) (result *lsp.ShowDocumentResult, err error) {
@@ -1024,7 +1268,7 @@ func (p *Server) OutgoingCalls(ctx context.Context, params *lsp.CallHierarchyOut
 func (p *Server) SemanticTokensFull(ctx context.Context, params *lsp.SemanticTokensParams) (result *lsp.SemanticTokens, err error) {
 	p.Log.Info("client -> server: SemanticTokensFull")
 	defer p.Log.Info("client -> server: SemanticTokensFull end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return nil, nil
 	}
@@ -1035,7 +1279,7 @@ func (p *Server) SemanticTokensFull(ctx context.Context, params *lsp.SemanticTok
 func (p *Server) SemanticTokensFullDelta(ctx context.Context, params *lsp.SemanticTokensDeltaParams) (result interface{} /* SemanticTokens | SemanticTokensDelta */, err error) {
 	p.Log.Info("client -> server: SemanticTokensFullDelta")
 	defer p.Log.Info("client -> server: SemanticTokensFullDelta end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return nil, nil
 	}
@@ -1046,7 +1290,7 @@ func (p *Server) SemanticTokensFullDelta(ctx context.Context, params *lsp.Semant
 func (p *Server) SemanticTokensRange(ctx context.Context, params *lsp.SemanticTokensRangeParams) (result *lsp.SemanticTokens, err error) {
 	p.Log.Info("client -> server: SemanticTokensRange")
 	defer p.Log.Info("client -> server: SemanticTokensRange end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
+	isTemplFile, goURI := p.FileNames.convertTemplToGoURI(params.TextDocument.URI)
 	if !isTemplFile {
 		return nil, nil
 	}
@@ -1081,5 +1325,113 @@ func (p *Server) Moniker(ctx context.Context, params *lsp.MonikerParams) (result
 func (p *Server) Request(ctx context.Context, method string, params interface{}) (result interface{}, err error) {
 	p.Log.Info("client -> server: Request")
 	defer p.Log.Info("client -> server: Request end")
+	// The protocol package doesn't have methods for selection ranges and linked
+	// editing ranges, or templ's own requests.
+	switch method {
+	case textNodesMethod:
+		var tnp textNodesParams
+		if err = decodeParams(params, &tnp); err != nil {
+			return nil, err
+		}
+		return p.TextNodes(ctx, &tnp)
+	case "textDocument/selectionRange":
+		var srp lsp.SelectionRangeParams
+		if err = decodeParams(params, &srp); err != nil {
+			return nil, err
+		}
+		if isTemplFile, _ := p.FileNames.convertTemplToGoURI(srp.TextDocument.URI); isTemplFile {
+			return p.SelectionRanges(ctx, &srp)
+		}
+	case "textDocument/linkedEditingRange":
+		var lerp lsp.TextDocumentPositionParams
+		if err = decodeParams(params, &lerp); err != nil {
+			return nil, err
+		}
+		if isTemplFile, _ := p.FileNames.convertTemplToGoURI(lerp.TextDocument.URI); isTemplFile {
+			return p.LinkedEditingRanges(ctx, &lerp)
+		}
+	}
 	return p.Target.Request(ctx, method, params)
 }
+
+// SelectionRanges returns the ranges that the selection at each position of the
+// templ file expands through.
+func (p *Server) SelectionRanges(ctx context.Context, params *lsp.SelectionRangeParams) (result []lsp.SelectionRange, err error) {
+	p.Log.Info("client -> server: SelectionRanges")
+	defer p.Log.Info("client -> server: SelectionRanges end")
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return []lsp.SelectionRange{}, nil
+	}
+	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, d.String())
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		return []lsp.SelectionRange{}, nil
+	}
+	result = selectionRanges(d.String(), template, params.Positions)
+	if result == nil {
+		result = []lsp.SelectionRange{}
+	}
+	return result, nil
+}
+
+// LinkedEditingRanges returns the ranges of the names of the start and end tags
+// of the element at the position of the templ file, so that both are edited
+// together. It returns nil if the position isn't in the name of a tag.
+func (p *Server) LinkedEditingRanges(ctx context.Context, params *lsp.TextDocumentPositionParams) (result *linkedEditingRanges, err error) {
+	p.Log.Info("client -> server: LinkedEditingRanges")
+	defer p.Log.Info("client -> server: LinkedEditingRanges end")
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return nil, nil
+	}
+	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, d.String())
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		return nil, nil
+	}
+	ranges, ok := tagNameRanges(d.String(), template, params.Position)
+	if !ok {
+		return nil, nil
+	}
+	return &linkedEditingRanges{Ranges: ranges}, nil
+}
+
+// TextNodes returns the text of the templ file that's shown to users.
+func (p *Server) TextNodes(ctx context.Context, params *textNodesParams) (result []textNode, err error) {
+	p.Log.Info("client -> server: TextNodes")
+	defer p.Log.Info("client -> server: TextNodes end")
+	result = []textNode{}
+	if isTemplFile, _ := p.FileNames.convertTemplToGoURI(params.TextDocument.URI); !isTemplFile {
+		return result, nil
+	}
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
+	if !ok {
+		return result, nil
+	}
+	template, ok, err := p.parseTemplate(ctx, params.TextDocument.URI, d.String())
+	if err != nil {
+		p.Log.Error("parseTemplate failure", zap.Error(err))
+	}
+	if !ok {
+		return result, nil
+	}
+	if nodes := textNodes(d.String(), template); nodes != nil {
+		result = nodes
+	}
+	return result, nil
+}
+
+// decodeParams decodes the params of a request that the protocol package doesn't
+// have a method for, which are decoded from JSON into maps.
+func decodeParams(params interface{}, v interface{}) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -1,6 +1,11 @@
 package proxy
 
-import lsp "github.com/a-h/protocol"
+import (
+	"strings"
+	"unicode"
+
+	lsp "github.com/a-h/protocol"
+)
 
 var htmlSnippets = []lsp.CompletionItem{
 	{
@@ -98,3 +103,96 @@ var htmlSnippets = []lsp.CompletionItem{
 		InsertTextFormat: lsp.InsertTextFormatSnippet,
 	},
 }
+
+// declarationSnippets are completions for the templ, css and script templates,
+// which are declared at the start of a line outside of templates.
+var declarationSnippets = []lsp.CompletionItem{
+	{
+		Label:  "templ",
+		Detail: "templ component",
+		InsertText: `templ ${1:Name}(${2}) {
+	${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+	{
+		Label:  "css",
+		Detail: "css template",
+		InsertText: `css ${1:className}() {
+	${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+	{
+		Label:  "script",
+		Detail: "script template",
+		InsertText: `script ${1:name}(${2}) {
+	${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+}
+
+// statementSnippets are completions for the control flow statements within
+// templates.
+var statementSnippets = []lsp.CompletionItem{
+	{
+		Label:  "if",
+		Detail: "if statement",
+		InsertText: `if ${1:condition} {
+	${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+	{
+		Label:  "if else",
+		Detail: "if/else statement",
+		InsertText: `if ${1:condition} {
+	${2}
+} else {
+	${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+	{
+		Label:  "for",
+		Detail: "for loop",
+		InsertText: `for ${1:_, item} := range ${2:items} {
+	${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+	{
+		Label:  "switch",
+		Detail: "switch statement",
+		InsertText: `switch ${1:value} {
+	case ${2:value}:
+		${0}
+}`,
+		Kind:             lsp.CompletionItemKind(lsp.CompletionItemKindSnippet),
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	},
+}
+
+// snippetsFor returns the snippets that can be completed after the text before
+// the cursor on its line, which must be a single word, or nothing. As templ
+// files are formatted, declarations start at the start of the line, and the
+// statements within templates are indented.
+func snippetsFor(before string) []lsp.CompletionItem {
+	word := strings.TrimLeft(before, " \t")
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			return nil
+		}
+	}
+	if word == before {
+		return declarationSnippets
+	}
+	return statementSnippets
+}
@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSnippetsFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		expected []lsp.CompletionItem
+	}{
+		{
+			name:     "declarations are completed at the start of a line",
+			before:   "tem",
+			expected: declarationSnippets,
+		},
+		{
+			name:     "declarations are completed on empty lines",
+			before:   "",
+			expected: declarationSnippets,
+		},
+		{
+			name:     "statements are completed on indented lines",
+			before:   "\t\tfo",
+			expected: statementSnippets,
+		},
+		{
+			name:   "snippets aren't completed after other text",
+			before: "\t<div>if",
+		},
+		{
+			name:   "snippets aren't completed after other words",
+			before: "\tif x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, snippetsFor(tt.before)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
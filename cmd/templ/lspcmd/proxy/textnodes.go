@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"html"
+	"sort"
+	"strings"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// textNodesMethod is a templ specific request that returns the text of a templ
+// file that's shown to users, so that spelling and grammar checkers can check
+// it without checking Go code, or the names and values of most attributes.
+const textNodesMethod = "templ/textNodes"
+
+// textAttributes are the attributes whose values are shown to users.
+var textAttributes = map[string]bool{
+	"alt":         true,
+	"aria-label":  true,
+	"placeholder": true,
+	"title":       true,
+}
+
+type textNodesParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+}
+
+// textNode is a part of a templ file that's shown to users.
+type textNode struct {
+	// Range of the text in the templ file.
+	Range lsp.Range `json:"range"`
+	// Text with its HTML character references decoded, e.g. &amp; is &.
+	Text string `json:"text"`
+	// Kind of the node, "text" or "attribute".
+	Kind string `json:"kind"`
+}
+
+// textNodes returns the text of the elements of the templ templates, and the
+// values of the attributes that are shown to users, e.g. alt and title, in the
+// order they're in the file. The text of script and style elements isn't shown
+// to users, so it isn't returned.
+func textNodes(src string, template parser.TemplateFile) (nodes []textNode) {
+	positions := newSourcePositions(src)
+	walkTemplates(template, func(n parser.Node) {
+		if t, ok := n.(parser.Text); ok {
+			nodes = append(nodes, textNode{
+				Range: lsp.Range{Start: rangePosition(t.Range.From), End: rangePosition(t.Range.To)},
+				Text:  html.UnescapeString(t.Value),
+				Kind:  "text",
+			})
+			return
+		}
+		for _, attr := range constantAttributes(n) {
+			if !textAttributes[strings.ToLower(attr.Name)] {
+				continue
+			}
+			if from, to, ok := attributeValue(src, attr); ok && from < to {
+				nodes = append(nodes, textNode{
+					Range: positions.rangeOf(from, to),
+					Text:  html.UnescapeString(src[from:to]),
+					Kind:  "attribute",
+				})
+			}
+		}
+	})
+	// The branches of if expressions aren't walked in order.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].Range.Start, nodes[j].Range.Start
+		return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+	})
+	return nodes
+}
@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"testing"
+
+	lsp "github.com/a-h/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTextNodes(t *testing.T) {
+	input := `package main
+
+templ Greeting(name string, ok bool) {
+	<img src="/logo.png" alt="Our logo"/>
+	if ok {
+		<p>Hello, { name }</p>
+	} else {
+		<p title="Error">Fish &amp; chips</p>
+	}
+	<script>var x = "not text";</script>
+}
+`
+	template, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := []textNode{
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 27}, End: lsp.Position{Line: 3, Character: 35}},
+			Text:  "Our logo",
+			Kind:  "attribute",
+		},
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 5, Character: 5}, End: lsp.Position{Line: 5, Character: 12}},
+			Text:  "Hello, ",
+			Kind:  "text",
+		},
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 7, Character: 12}, End: lsp.Position{Line: 7, Character: 17}},
+			Text:  "Error",
+			Kind:  "attribute",
+		},
+		{
+			Range: lsp.Range{Start: lsp.Position{Line: 7, Character: 19}, End: lsp.Position{Line: 7, Character: 35}},
+			Text:  "Fish & chips",
+			Kind:  "text",
+		},
+	}
+	if diff := cmp.Diff(expected, textNodes(input, template)); diff != "" {
+		t.Error(diff)
+	}
+}
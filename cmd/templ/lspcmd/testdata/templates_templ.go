@@ -19,6 +19,7 @@ func Page(count int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.Page`, &templ_7745c5c3_SourceLines_562407f7)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -47,3 +48,5 @@ func Page(count int) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_562407f7 = templ.SourceLines{FileName: `cmd/templ/lspcmd/testdata/templates.templ`, From: 14, To: 50, Lines: []int{14, 5, 33, 13}}
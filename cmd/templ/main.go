@@ -8,12 +8,19 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
+	"syscall"
 
 	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/analyzecmd"
+	"github.com/a-h/templ/cmd/templ/assetscmd"
+	"github.com/a-h/templ/cmd/templ/benchdiffcmd"
 	"github.com/a-h/templ/cmd/templ/fmtcmd"
 	"github.com/a-h/templ/cmd/templ/generatecmd"
 	"github.com/a-h/templ/cmd/templ/lspcmd"
 	"github.com/a-h/templ/cmd/templ/migratecmd"
+	"github.com/a-h/templ/cmd/templ/rewritecmd"
+	"github.com/a-h/templ/cmd/templ/verifycmd"
 	"github.com/fatih/color"
 )
 
@@ -33,8 +40,13 @@ See docs at https://templ.guide
 commands:
   generate   Generates Go code from templ files
   fmt        Formats templ files
+  rewrite    Rewrites component calls and attributes in templ files
   lsp        Starts a language server for templ files
   migrate    Migrates v1 templ files to v2 format
+  analyze    Reports the output size of components, and checks size budgets
+  assets     Fingerprints static assets, and writes a manifest for templ.Asset
+  benchdiff  Compares the performance of generated code between two git revisions
+  verify     Checks that generated files are up to date
   version    Prints the version
 `
 
@@ -50,8 +62,18 @@ func run(w io.Writer, args []string) (code int) {
 		return migrateCmd(w, args[2:])
 	case "fmt":
 		return fmtCmd(w, args[2:])
+	case "rewrite":
+		return rewriteCmd(w, args[2:])
 	case "lsp":
 		return lspCmd(w, args[2:])
+	case "analyze":
+		return analyzeCmd(w, args[2:])
+	case "assets":
+		return assetsCmd(w, args[2:])
+	case "benchdiff":
+		return benchdiffCmd(w, args[2:])
+	case "verify":
+		return verifyCmd(w, args[2:])
 	case "version":
 		fmt.Fprintln(w, templ.Version())
 		return 0
@@ -63,40 +85,120 @@ func run(w io.Writer, args []string) (code int) {
 	return 0
 }
 
-const generateUsageText = `usage: templ generate [<args>...]
+const generateUsageText = `usage: templ generate [<args>...] [<files>...]
 
 Generates Go code from templ files.
 
+Generates code for the templ files, directories and glob patterns of templ files that are listed after the args, if any, instead of all files in -path, e.g. templ generate $(git diff --name-only -- '*.templ')
+
 Args:
   -path <path>
     Generates code for all files in path. (default .)
+  -output-dir <dir>
+    Set the directory to write generated Go code to, mirroring the directory structure of -path, e.g. internal/gen, instead of writing it next to each templ file.
+  -exclude-dirs <patterns>
+    Set the comma separated names, or paths relative to -path, of directories to skip when looking for templ files, e.g. "vendor,node_modules,web/dist". Directories with names that start with . or _ are always skipped. Set to "" to skip no other directories. (default "vendor,node_modules")
+  -gitignore
+    Set to false to look for templ files in directories that are ignored by git. (default true)
+  -follow-symlinks
+    Set to true to look for templ files in symlinked directories. Directories that can be reached through more than one symlink, including symlink cycles, are only generated once.
   -f <file>
     Optionally generates code for a single file, e.g. -f header.templ
+  -files-from <file>
+    Generates code for the files listed in the file, one per line, instead of all files in -path. Use - to read the list from stdin, e.g. git diff --name-only -- '*.templ' | templ generate -files-from -
   -stdout
     Prints to stdout instead of writing generated files to the filesystem.
     Only applicable when -f is used.
+  -dry-run
+    Set to true to list the generated files that would be created, updated or deleted, without changing them. Exits with a non-zero exit code if any file would change.
+  -diff
+    Set to true to print the changes that would be made to generated files, without changing them. Implies -dry-run.
   -sourceMapVisualisations
     Set to true to generate HTML files to visualise the templ code and its corresponding Go code.
   -include-version
-    Set to false to skip inclusion of the templ version in the generated code. (default true)
+    Set to false to skip inclusion of the templ version, and the hash of the templ file, in the generated code. (default true)
   -include-timestamp
     Set to true to include the current time in the generated code.
+  -instrument
+    Set to true to wrap generated templates with templ.Trace, so that rendering can be traced.
+  -strict
+    Set to true to fail generation if templates contain invalid HTML, e.g. a <div> inside a <p>, duplicate attributes, or unknown elements.
+  -permissive-string-expressions
+    Set to true to allow the values of string expressions, e.g. { name }, and of attribute expressions, e.g. title={ name }, to be numbers, booleans, fmt.Stringer and error values, as well as strings. Values of other types return an error when the template is rendered.
+  -minify
+    Set to true to remove insignificant whitespace from the generated HTML.
+  -static-chunks
+    Set to true to move long static HTML chunks into package-level constants in a templ_static_chunks.go file, so that chunks shared by templates are only included in the binary once.
+  -tailwind-classes <file>
+    Writes the literal class names used in templates to the file, so that Tailwind can find them.
+  -tailwind-cmd <cmd>
+    Set the command to run in watch mode when the class names in the -tailwind-classes file change, e.g. "npx tailwindcss -i input.css -o static/output.css".
+  -script-types
+    Set to true to write TypeScript declarations of the functions of script templates, and of the props of islands, to _templ.d.ts files.
+  -behaviors
+    Set to true to attach script templates used in on* attributes with data-templ-on attributes, instead of inline event handlers.
+  -header-file <file>
+    Set a file, e.g. a license header, whose contents are written as a comment at the top of every generated Go file.
+  -build-constraint <expr>
+    Set a build constraint that's added to every generated Go file, e.g. "!tinygo".
+  -esbuild <cmd>
+    Set the esbuild executable used to bundle the TypeScript file alongside each template into its script templates with empty bodies, e.g. "./node_modules/.bin/esbuild".
+  -runtime-version <version>
+    Set the version of the templ module to check the generated code against, e.g. v0.2.598. (default the version in go.mod)
   -watch
     Set to true to watch the path for changes and regenerate code.
+  -watch-strategy <strategy>
+    Set how changes are detected in watch mode, either fsnotify, or poll to scan for changes on network drives, WSL2 mounts and Docker bind mounts. (default fsnotify)
+  -poll-interval <duration>
+    Set the time between scans for changes when polling. (default 500ms)
+  -poll-hash
+    Set to true to compare the contents of files, instead of their modification times, when polling.
+  -detect-missed-events
+    Set to true to scan for changes every 30s when watching with fsnotify, and warn if a file changes without a file system event being received.
   -cmd <cmd>
     Set the command to run after generating code.
   -proxy
     Set the URL to proxy after generating code and executing the command.
+  -static-dir <dir>
+    Set a directory of static files for the proxy to serve itself, e.g. the output of a static site generator, with the reload script inserted into HTML files. Files that don't exist are requested from the -proxy URL, if set.
+  -proxy-route <prefix=url>
+    Set the URL to proxy requests with paths that start with the prefix to, instead of the -proxy URL, e.g. /api=http://localhost:4000. Can be set more than once.
   -proxyport
     The port the proxy will listen on. (default 7331)
   -proxybind
-    The address the proxy will listen on. (default 127.0.0.1)
+    The address the proxy will listen on. (default 127.0.0.1, or 0.0.0.0 in a container or with -qr)
+  -proxy-log
+    Set to true to log each request made through the proxy, with its status, size, the time taken by the proxy target, and whether the reload script was inserted.
+  -proxy-log-exclude <patterns>
+    Set the comma separated paths to leave out of the proxy log, e.g. "*.css,/static/*". (default static assets, e.g. *.css, *.js and *.png)
+  -proxy-morph
+    Set to true to update pages in place on reload, keeping focus, scroll positions and open dialogs, instead of reloading them.
+  -proxy-ping-interval <duration>
+    Set the time between the pings sent to browsers connected for reload events. Browsers reconnect if they miss three pings. (default 5s)
+  -open
+    Set to false to not open the browser at the proxy URL once the proxy target is ready. Also -open-browser. (default true)
+  -qr
+    Set to true to print a QR code of the URL of the proxy on the local network, for testing on phones.
+  -proxy-external-url <url>
+    Set the URL that browsers connect to the proxy with, if it's different to the proxy address, e.g. http://localhost:8000 when the proxy runs in a container with a port mapping.
+  -host-path <dir>
+    Set the directory on the host that the path is mounted from, when templ runs in a container, so that errors show the paths of files on the host.
+  -pidfile <file>
+    Writes the process ID of templ to the file, and removes it on exit.
+  -notify-port <port>
+    Set the port of a second listener that only accepts reload events sent with the notify token, so that a remote build machine can trigger reloads. (default disabled)
+  -notify-bind <address>
+    The address the reload event listener will listen on. (default the proxybind address)
+  -notify-token <token>
+    Set the token that reload events must be sent to the proxy and the reload event listener with, and that -notify-proxy sends. (default $TEMPL_NOTIFY_TOKEN, or a generated token for the reload event listener only)
   -notify-proxy
     If present, the command will issue a reload event to the proxy 127.0.0.1:7331, or use proxyport and proxybind to specify a different address.
   -w
     Number of workers to use when generating code. (default runtime.NumCPUs)
   -pprof
     Port to run the pprof server on.
+  -keep-going
+    Set to true to generate the rest of the files when a file fails to generate, instead of stopping at the first error. (default false)
   -keep-orphaned-files
     Keeps orphaned generated templ files. (default false)
   -v
@@ -116,6 +218,10 @@ Examples:
 
     templ generate -f header.templ
 
+  Generate code for the templ files that have changed:
+
+    templ generate $(git diff --name-only -- '*.templ')
+
   Watch the current directory and subdirectories for changes and regenerate code:
 
     templ generate -watch
@@ -125,20 +231,60 @@ func generateCmd(w io.Writer, args []string) (code int) {
 	cmd := flag.NewFlagSet("generate", flag.ExitOnError)
 	cmd.SetOutput(w)
 	fileNameFlag := cmd.String("f", "", "")
+	filesFromFlag := cmd.String("files-from", "", "")
 	pathFlag := cmd.String("path", ".", "")
+	outputDirFlag := cmd.String("output-dir", "", "")
+	excludeDirsFlag := cmd.String("exclude-dirs", generatecmd.DefaultExcludeDirs, "")
+	followSymlinksFlag := cmd.Bool("follow-symlinks", false, "")
+	gitIgnoreFlag := cmd.Bool("gitignore", true, "")
 	toStdoutFlag := cmd.Bool("stdout", false, "")
+	dryRunFlag := cmd.Bool("dry-run", false, "")
+	diffFlag := cmd.Bool("diff", false, "")
 	sourceMapVisualisationsFlag := cmd.Bool("source-map-visualisations", false, "")
 	includeVersionFlag := cmd.Bool("include-version", true, "")
 	includeTimestampFlag := cmd.Bool("include-timestamp", false, "")
+	instrumentFlag := cmd.Bool("instrument", false, "")
+	strictFlag := cmd.Bool("strict", false, "")
+	permissiveStringExpressionsFlag := cmd.Bool("permissive-string-expressions", false, "")
+	minifyFlag := cmd.Bool("minify", false, "")
+	staticChunksFlag := cmd.Bool("static-chunks", false, "")
+	tailwindClassesFlag := cmd.String("tailwind-classes", "", "")
+	tailwindCmdFlag := cmd.String("tailwind-cmd", "", "")
+	scriptTypesFlag := cmd.Bool("script-types", false, "")
+	behaviorsFlag := cmd.Bool("behaviors", false, "")
+	headerFileFlag := cmd.String("header-file", "", "")
+	buildConstraintFlag := cmd.String("build-constraint", "", "")
+	esbuildFlag := cmd.String("esbuild", "", "")
+	runtimeVersionFlag := cmd.String("runtime-version", "", "")
 	watchFlag := cmd.Bool("watch", false, "")
+	watchStrategyFlag := cmd.String("watch-strategy", generatecmd.WatchStrategyFSNotify, "")
+	pollIntervalFlag := cmd.Duration("poll-interval", generatecmd.DefaultPollInterval, "")
+	pollHashFlag := cmd.Bool("poll-hash", false, "")
+	detectMissedEventsFlag := cmd.Bool("detect-missed-events", false, "")
 	openBrowserFlag := cmd.Bool("open-browser", true, "")
+	cmd.BoolVar(openBrowserFlag, "open", true, "")
+	qrFlag := cmd.Bool("qr", false, "")
+	proxyLogFlag := cmd.Bool("proxy-log", false, "")
+	staticDirFlag := cmd.String("static-dir", "", "")
+	var proxyRoutes multiFlag
+	cmd.Var(&proxyRoutes, "proxy-route", "")
+	proxyLogExcludeFlag := cmd.String("proxy-log-exclude", generatecmd.DefaultProxyLogExclude, "")
+	proxyMorphFlag := cmd.Bool("proxy-morph", false, "")
+	proxyPingIntervalFlag := cmd.Duration("proxy-ping-interval", generatecmd.DefaultProxyPingInterval, "")
 	cmdFlag := cmd.String("cmd", "", "")
 	proxyFlag := cmd.String("proxy", "", "")
 	proxyPortFlag := cmd.Int("proxyport", 7331, "")
-	proxyBindFlag := cmd.String("proxybind", "127.0.0.1", "")
+	proxyBindFlag := cmd.String("proxybind", "", "")
+	proxyExternalURLFlag := cmd.String("proxy-external-url", "", "")
+	hostPathFlag := cmd.String("host-path", "", "")
+	notifyPortFlag := cmd.Int("notify-port", 0, "")
+	notifyBindFlag := cmd.String("notify-bind", "", "")
+	notifyTokenFlag := cmd.String("notify-token", os.Getenv("TEMPL_NOTIFY_TOKEN"), "")
 	notifyProxyFlag := cmd.Bool("notify-proxy", false, "")
+	pidFileFlag := cmd.String("pidfile", "", "")
 	workerCountFlag := cmd.Int("w", runtime.NumCPU(), "")
 	pprofPortFlag := cmd.Int("pprof", 0, "")
+	keepGoingFlag := cmd.Bool("keep-going", false, "")
 	keepOrphanedFilesFlag := cmd.Bool("keep-orphaned-files", false, "")
 	verboseFlag := cmd.Bool("v", false, "")
 	logLevelFlag := cmd.String("log-level", "info", "")
@@ -156,7 +302,7 @@ func generateCmd(w io.Writer, args []string) (code int) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-signalChan
 		fmt.Fprintln(w, "Stopping...")
@@ -164,8 +310,15 @@ func generateCmd(w io.Writer, args []string) (code int) {
 	}()
 	err = generatecmd.Run(ctx, w, generatecmd.Arguments{
 		FileName:                        *fileNameFlag,
+		Files:                           cmd.Args(),
+		FilesFrom:                       *filesFromFlag,
 		Path:                            *pathFlag,
 		ToStdout:                        *toStdoutFlag,
+		ExcludeDirs:                     splitList(*excludeDirsFlag),
+		FollowSymlinks:                  *followSymlinksFlag,
+		GitIgnore:                       *gitIgnoreFlag,
+		DryRun:                          *dryRunFlag,
+		Diff:                            *diffFlag,
 		Watch:                           *watchFlag,
 		OpenBrowser:                     *openBrowserFlag,
 		Command:                         *cmdFlag,
@@ -177,9 +330,41 @@ func generateCmd(w io.Writer, args []string) (code int) {
 		GenerateSourceMapVisualisations: *sourceMapVisualisationsFlag,
 		IncludeVersion:                  *includeVersionFlag,
 		IncludeTimestamp:                *includeTimestampFlag,
+		Instrument:                      *instrumentFlag,
+		StrictHTML:                      *strictFlag,
+		PermissiveStringExpressions:     *permissiveStringExpressionsFlag,
+		Minify:                          *minifyFlag,
+		StaticChunks:                    *staticChunksFlag,
+		TailwindClassesFile:             *tailwindClassesFlag,
+		TailwindCommand:                 *tailwindCmdFlag,
+		ScriptTypes:                     *scriptTypesFlag,
+		Behaviors:                       *behaviorsFlag,
+		HeaderFile:                      *headerFileFlag,
+		BuildConstraint:                 *buildConstraintFlag,
+		ESBuildCommand:                  *esbuildFlag,
+		RuntimeVersion:                  *runtimeVersionFlag,
+		WatchStrategy:                   *watchStrategyFlag,
+		PollInterval:                    *pollIntervalFlag,
+		PollHash:                        *pollHashFlag,
+		DetectMissedEvents:              *detectMissedEventsFlag,
+		ProxyExternalURL:                *proxyExternalURLFlag,
+		HostPath:                        *hostPathFlag,
+		OutputDir:                       *outputDirFlag,
+		NotifyPort:                      *notifyPortFlag,
+		NotifyBind:                      *notifyBindFlag,
+		NotifyToken:                     *notifyTokenFlag,
+		QRCode:                          *qrFlag,
+		ProxyLog:                        *proxyLogFlag,
+		StaticDir:                       *staticDirFlag,
+		ProxyRoutes:                     proxyRoutes,
+		ProxyLogExclude:                 *proxyLogExcludeFlag,
+		ProxyMorph:                      *proxyMorphFlag,
+		ProxyPingInterval:               *proxyPingIntervalFlag,
+		PIDFile:                         *pidFileFlag,
 		LogLevel:                        logLevel,
 		PPROFPort:                       *pprofPortFlag,
 		KeepOrphanedFiles:               *keepOrphanedFilesFlag,
+		KeepGoing:                       *keepGoingFlag,
 	})
 	if err != nil {
 		color.New(color.FgRed).Fprint(w, "(✗) ")
@@ -241,9 +426,24 @@ Format file or directory to stdout:
 
   templ fmt -stdout FILE
 
+Format files, directories and glob patterns of files:
+
+  templ fmt $(git diff --name-only -- '*.templ')
+  templ fmt 'components/*.templ'
+
+Format the files listed in stdin, one per line:
+
+  git diff --name-only --cached -- '*.templ' | templ fmt -files-from -
+
 Args:
   -stdout
     Prints to stdout instead of in-place format
+  -files-from <file>
+    Formats the files listed in the file, one per line. Use - to read the list from stdin.
+  -gitignore
+    Set to false to format templ files in directories that are ignored by git. (default true)
+  -imports
+    Set to true to add the imports of packages that are used, and remove unused imports, like goimports.
   -v
     Set log verbosity level to "debug". (default "info")
   -log-level
@@ -265,6 +465,9 @@ func fmtCmd(w io.Writer, args []string) (code int) {
 	verboseFlag := cmd.Bool("v", false, "")
 	logLevelFlag := cmd.String("log-level", "info", "")
 	stdout := cmd.Bool("stdout", false, "")
+	filesFromFlag := cmd.String("files-from", "", "")
+	gitIgnoreFlag := cmd.Bool("gitignore", true, "")
+	importsFlag := cmd.Bool("imports", false, "")
 
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
@@ -280,8 +483,11 @@ func fmtCmd(w io.Writer, args []string) (code int) {
 	err = fmtcmd.Run(w, fmtcmd.Arguments{
 		ToStdout:    *stdout,
 		Files:       cmd.Args(),
+		FilesFrom:   *filesFromFlag,
 		LogLevel:    logLevel,
 		WorkerCount: *workerCountFlag,
+		GitIgnore:   *gitIgnoreFlag,
+		Imports:     *importsFlag,
 	})
 	if err != nil {
 		return 1
@@ -289,6 +495,90 @@ func fmtCmd(w io.Writer, args []string) (code int) {
 	return 0
 }
 
+const rewriteUsageText = `usage: templ rewrite -rule <rule> [<args>...]
+
+Rewrites the templ files in a directory with rules of the form
+"pattern -> replacement", and formats the files that are changed.
+
+Expression rules rewrite the Go expressions of templates, e.g. component calls,
+like gofmt -r. Single-character lowercase identifiers in the pattern match any
+expression:
+
+  templ rewrite -rule 'Button(x) -> NewButton(x, "primary")'
+
+Attribute rules rename attributes, or the attributes of an element:
+
+  templ rewrite -rule '[hx-ws] -> [ws-connect]'
+  templ rewrite -rule 'button[variant] -> button[kind]'
+
+Args:
+  -rule <rule>
+    The rewrite rule. Can be set more than once, the rules are applied in order.
+  -path <path>
+    Rewrites all files in path. (default .)
+  -dry-run
+    Lists the files that would be changed, without writing them.
+  -gitignore
+    Set to false to rewrite templ files in directories that are ignored by git. (default true)
+  -w
+    Number of workers to use when rewriting files. (default runtime.NumCPUs)
+  -help
+    Print help and exit.
+`
+
+// splitList splits a comma separated flag value. An empty value is an empty list.
+func splitList(s string) (items []string) {
+	items = []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// multiFlag is a flag that can be set more than once.
+type multiFlag []string
+
+func (r *multiFlag) String() string {
+	return strings.Join(*r, ", ")
+}
+
+func (r *multiFlag) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+func rewriteCmd(w io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("rewrite", flag.ExitOnError)
+	cmd.SetOutput(w)
+	var rules multiFlag
+	cmd.Var(&rules, "rule", "")
+	pathFlag := cmd.String("path", ".", "")
+	dryRunFlag := cmd.Bool("dry-run", false, "")
+	gitIgnoreFlag := cmd.Bool("gitignore", true, "")
+	workerCountFlag := cmd.Int("w", runtime.NumCPU(), "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag || len(rules) == 0 {
+		fmt.Fprint(w, rewriteUsageText)
+		return
+	}
+	err = rewritecmd.Run(w, rewritecmd.Arguments{
+		Path:        *pathFlag,
+		Rules:       rules,
+		DryRun:      *dryRunFlag,
+		WorkerCount: *workerCountFlag,
+		GitIgnore:   *gitIgnoreFlag,
+	})
+	if err != nil {
+		color.New(color.FgRed).Fprint(w, "(✗) ")
+		fmt.Fprintln(w, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
 const lspUsageText = `usage: templ lsp [<args> ...]
 
 Starts a language server for templ.
@@ -306,6 +596,13 @@ Args:
     Enable pprof web server (default address is localhost:9999)
   -http string
     Enable http debug server by setting a listen address (e.g. localhost:7474)
+  -noSnippets
+    Disable the completion of snippets for templ, css and script templates, and
+    if, for and switch statements.
+  -path <path>
+    The directory that templ generate -path was set to, used with -output-dir. (default .)
+  -output-dir <dir>
+    Set the directory that templ generate -output-dir writes the generated Go code to.
 `
 
 func lspCmd(w io.Writer, args []string) (code int) {
@@ -317,6 +614,9 @@ func lspCmd(w io.Writer, args []string) (code int) {
 	helpFlag := cmd.Bool("help", false, "")
 	pprofFlag := cmd.Bool("pprof", false, "")
 	httpDebugFlag := cmd.String("http", "", "")
+	noSnippetsFlag := cmd.Bool("noSnippets", false, "")
+	pathFlag := cmd.String("path", ".", "")
+	outputDirFlag := cmd.String("output-dir", "", "")
 	err := cmd.Parse(args)
 	if err != nil || *helpFlag {
 		fmt.Fprint(w, lspUsageText)
@@ -328,6 +628,9 @@ func lspCmd(w io.Writer, args []string) (code int) {
 		GoplsRPCTrace: *goplsRPCTrace,
 		PPROF:         *pprofFlag,
 		HTTPDebug:     *httpDebugFlag,
+		NoSnippets:    *noSnippetsFlag,
+		Path:          *pathFlag,
+		OutputDir:     *outputDirFlag,
 	})
 	if err != nil {
 		fmt.Fprintln(w, err.Error())
@@ -335,3 +638,203 @@ func lspCmd(w io.Writer, args []string) (code int) {
 	}
 	return 0
 }
+
+const analyzeUsageText = `usage: templ analyze [<args> ...]
+
+Renders components with fixtures, and reports the output size, gzip size,
+number of elements, and inline script and style bytes of each component.
+
+Exits with a non-zero exit code if a budget in the config file is exceeded.
+
+Args:
+  -config <file>
+    The JSON file that lists the components to analyze. (default templ-budget.json)
+  -path <path>
+    A directory within the Go module that contains the components. (default .)
+  -help
+    Print help and exit.
+
+Example config:
+
+  {
+    "components": [
+      {
+        "name": "home page",
+        "imports": ["github.com/example/app/components", "github.com/example/app/fixtures"],
+        "expression": "components.Home(fixtures.HomePage)",
+        "budget": { "size": 50000, "gzipSize": 10000, "nodes": 800, "scriptBytes": 5000, "styleBytes": 2000 }
+      }
+    ]
+  }
+`
+
+func analyzeCmd(w io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("analyze", flag.ExitOnError)
+	cmd.SetOutput(w)
+	configFlag := cmd.String("config", "templ-budget.json", "")
+	pathFlag := cmd.String("path", ".", "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag {
+		fmt.Fprint(w, analyzeUsageText)
+		return
+	}
+	err = analyzecmd.Run(context.Background(), w, analyzecmd.Arguments{
+		Path:       *pathFlag,
+		ConfigFile: *configFlag,
+	})
+	if err != nil {
+		color.New(color.FgRed).Fprint(w, "(✗) ")
+		fmt.Fprintln(w, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const assetsUsageText = `usage: templ assets [<args>...]
+
+Copies static assets to the output directory with a hash of their contents in
+their file names, e.g. app.css is copied to app.3f9ab2c1.css, and writes a
+templ-assets.json manifest to the output directory.
+
+Load the manifest with templ.LoadAssetManifest, and set it with
+templ.SetAssetManifest, so that templ.Asset("app.css") returns the URL of the
+fingerprinted file.
+
+Args:
+  -path <path>
+    The directory that contains the static assets. (default static)
+  -out <path>
+    The directory to write fingerprinted assets and the manifest to. (default dist)
+  -help
+    Print help and exit.
+`
+
+func assetsCmd(w io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("assets", flag.ExitOnError)
+	cmd.SetOutput(w)
+	pathFlag := cmd.String("path", "static", "")
+	outFlag := cmd.String("out", "dist", "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag {
+		fmt.Fprint(w, assetsUsageText)
+		return
+	}
+	err = assetscmd.Run(w, assetscmd.Arguments{
+		Path:       *pathFlag,
+		OutputPath: *outFlag,
+	})
+	if err != nil {
+		color.New(color.FgRed).Fprint(w, "(✗) ")
+		fmt.Fprintln(w, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const benchdiffUsageText = `usage: templ benchdiff -base <revision> [<args>...]
+
+Generates the code of the benchmarks with the templ generator of each git
+revision, runs the benchmarks, and compares the time and allocations per
+operation. Only benchmarks that exist at both revisions are compared.
+
+Exits with a non-zero exit code if a benchmark has regressed by more than the
+threshold, and the difference between the runs at each revision is
+statistically significant (p < 0.05 in a Mann-Whitney U test), which needs a
+count of at least 4.
+
+Args:
+  -base <revision>
+    The git revision to compare against, e.g. main.
+  -head <revision>
+    The git revision to compare. (default the working tree)
+  -path <path>
+    The directory that contains the benchmarks, relative to the root of the repository. (default benchmarks)
+  -bench <regexp>
+    Runs only the benchmarks that match the regular expression. (default .)
+  -count <n>
+    The number of times to run each benchmark. (default 5)
+  -threshold <percent>
+    The percentage increase of ns/op, B/op or allocs/op that's a regression. (default 10)
+  -help
+    Print help and exit.
+`
+
+func benchdiffCmd(w io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("benchdiff", flag.ExitOnError)
+	cmd.SetOutput(w)
+	baseFlag := cmd.String("base", "", "")
+	headFlag := cmd.String("head", "", "")
+	pathFlag := cmd.String("path", "benchmarks", "")
+	benchFlag := cmd.String("bench", ".", "")
+	countFlag := cmd.Int("count", 5, "")
+	thresholdFlag := cmd.Float64("threshold", 10, "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag || *baseFlag == "" {
+		fmt.Fprint(w, benchdiffUsageText)
+		return
+	}
+	err = benchdiffcmd.Run(context.Background(), w, benchdiffcmd.Arguments{
+		Base:      *baseFlag,
+		Head:      *headFlag,
+		Path:      *pathFlag,
+		Bench:     *benchFlag,
+		Count:     *countFlag,
+		Threshold: *thresholdFlag,
+	})
+	if err != nil {
+		color.New(color.FgRed).Fprint(w, "(✗) ")
+		fmt.Fprintln(w, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const verifyUsageText = `usage: templ verify [<args>...]
+
+Checks that the generated Go file of each templ file is up to date, using the
+templ version and the hash of the templ file that templ generate includes in
+the generated code.
+
+Exits with a non-zero exit code if a generated file is missing, the templ file
+has changed since it was generated, or it was generated by a different version
+of templ, e.g. to fail a CI build.
+
+Args:
+  -path <path>
+    Verifies the generated files of all templ files in path. (default .)
+  -output-dir <dir>
+    Set the directory that the generated Go code was written to with templ generate -output-dir.
+  -gitignore
+    Set to false to verify templ files in directories that are ignored by git. (default true)
+  -help
+    Print help and exit.
+`
+
+func verifyCmd(w io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("verify", flag.ExitOnError)
+	cmd.SetOutput(w)
+	pathFlag := cmd.String("path", ".", "")
+	outputDirFlag := cmd.String("output-dir", "", "")
+	gitIgnoreFlag := cmd.Bool("gitignore", true, "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || *helpFlag {
+		fmt.Fprint(w, verifyUsageText)
+		return
+	}
+	err = verifycmd.Run(w, verifycmd.Arguments{
+		Path:      *pathFlag,
+		Version:   templ.Version(),
+		GitIgnore: *gitIgnoreFlag,
+		OutputDir: *outputDirFlag,
+	})
+	if err != nil {
+		color.New(color.FgRed).Fprint(w, "(✗) ")
+		fmt.Fprintln(w, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
@@ -63,6 +63,12 @@ func TestMain(t *testing.T) {
 			expected:     fmtUsageText,
 			expectedCode: 0,
 		},
+		{
+			name:         `"templ rewrite" without rules prints usage`,
+			args:         []string{"templ", "rewrite"},
+			expected:     rewriteUsageText,
+			expectedCode: 0,
+		},
 		{
 			name:         `"templ generate --help" prints usage`,
 			args:         []string{"templ", "generate", "--help"},
@@ -75,6 +81,18 @@ func TestMain(t *testing.T) {
 			expected:     lspUsageText,
 			expectedCode: 0,
 		},
+		{
+			name:         `"templ analyze --help" prints usage`,
+			args:         []string{"templ", "analyze", "--help"},
+			expected:     analyzeUsageText,
+			expectedCode: 0,
+		},
+		{
+			name:         `"templ assets --help" prints usage`,
+			args:         []string{"templ", "assets", "--help"},
+			expected:     assetsUsageText,
+			expectedCode: 0,
+		},
 	}
 
 	for _, test := range tests {
@@ -39,7 +39,7 @@ func processSingleFile(w io.Writer, fileName string) error {
 func processPath(w io.Writer, path string) (err error) {
 	start := time.Now()
 	results := make(chan processor.Result)
-	go processor.Process(path, migrate, workerCount, results)
+	go processor.Process(path, nil, migrate, workerCount, results)
 	var successCount, errorCount int
 	for r := range results {
 		if r.Error != nil {
@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// OutputFileName returns the name of a file generated from a templ file, with
+// the suffix, e.g. _templ.go, in place of the .templ extension. If outputDir is
+// set, the file is written to outputDir, mirroring the directory tree of root,
+// see templ generate -output-dir, e.g. with an output directory of internal/gen,
+// the code generated from components/button.templ is internal/gen/components/button_templ.go.
+// Templ files outside root are generated next to the templ file.
+//
+// root, outputDir and the file name must all be absolute, or all be relative to
+// the same directory.
+func OutputFileName(root, outputDir, templFileName, suffix string) string {
+	base := strings.TrimSuffix(templFileName, ".templ")
+	if outputDir == "" {
+		return base + suffix
+	}
+	rel, err := filepath.Rel(root, base)
+	if err != nil || !filepath.IsLocal(rel) {
+		return base + suffix
+	}
+	return filepath.Join(outputDir, rel) + suffix
+}
+
+// SourceFileName returns the name of the templ file that a generated file, with
+// the suffix, was generated from. It's the inverse of OutputFileName.
+func SourceFileName(root, outputDir, outputFileName, suffix string) string {
+	base := strings.TrimSuffix(outputFileName, suffix)
+	if outputDir == "" {
+		return base + ".templ"
+	}
+	rel, err := filepath.Rel(outputDir, base)
+	if err != nil || !filepath.IsLocal(rel) {
+		return base + ".templ"
+	}
+	return filepath.Join(root, rel) + ".templ"
+}
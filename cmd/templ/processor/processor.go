@@ -1,12 +1,17 @@
 package processor
 
 import (
+	"fmt"
+	"io"
 	"io/fs"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/a-h/templ/cmd/templ/gitignore"
 )
 
 type Result struct {
@@ -15,18 +20,22 @@ type Result struct {
 	Error    error
 }
 
-func Process(dir string, f func(fileName string) error, workerCount int, results chan<- Result) {
+// Process calls f for each of the templ files in the directory, in workerCount
+// goroutines. Directories that ignore matches are skipped.
+func Process(dir string, ignore *gitignore.Matcher, f func(fileName string) error, workerCount int, results chan<- Result) {
 	templates := make(chan string)
 	go func() {
 		defer close(templates)
-		if err := FindTemplates(dir, templates); err != nil {
+		if err := FindTemplates(dir, ignore, templates); err != nil {
 			results <- Result{Error: err}
 		}
 	}()
 	ProcessChannel(templates, dir, f, workerCount, results)
 }
 
-func shouldSkipDir(dir string) bool {
+// ShouldSkipDir returns true for directories that are ignored by the Go tool,
+// and vendor and node_modules directories.
+func ShouldSkipDir(dir string) bool {
 	if dir == "." {
 		return false
 	}
@@ -41,12 +50,15 @@ func shouldSkipDir(dir string) bool {
 	return false
 }
 
-func FindTemplates(srcPath string, output chan<- string) (err error) {
+// FindTemplates sends the name of each templ file in the directory to output.
+// Directories that ignore matches are skipped. If ignore is nil, no directories
+// are ignored.
+func FindTemplates(srcPath string, ignore *gitignore.Matcher, output chan<- string) (err error) {
 	return filepath.Walk(srcPath, func(currentPath string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() && shouldSkipDir(currentPath) {
+		if info.IsDir() && (ShouldSkipDir(currentPath) || currentPath != srcPath && ignore.Ignored(currentPath, true)) {
 			return filepath.SkipDir
 		}
 		if !info.IsDir() && strings.HasSuffix(currentPath, ".templ") {
@@ -75,3 +87,97 @@ func ProcessChannel(templates <-chan string, dir string, f func(fileName string)
 	}
 	wg.Wait()
 }
+
+// ExpandFiles returns the templ files that the args refer to, in order, without
+// duplicates. Each arg is the name of a templ file, a directory, whose templ files
+// are found by FindTemplates, or a pattern in the syntax of filepath.Match, e.g.
+// "components/*.templ". If gitIgnore is true, the directories in a directory that
+// are ignored by git are skipped.
+//
+// Files that aren't templ files, and templ files that don't exist, are skipped, so
+// that the output of commands such as git diff --name-only can be passed without
+// filtering it.
+func ExpandFiles(args []string, gitIgnore bool) (fileNames []string, err error) {
+	seen := map[string]bool{}
+	add := func(fileName string) {
+		if !seen[fileName] {
+			seen[fileName] = true
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	for _, arg := range args {
+		fi, err := os.Stat(arg)
+		if err != nil && strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+			}
+			for _, match := range matches {
+				if strings.HasSuffix(match, ".templ") {
+					add(match)
+				}
+			}
+			continue
+		}
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !fi.IsDir() {
+			if strings.HasSuffix(arg, ".templ") {
+				add(arg)
+			}
+			continue
+		}
+		var ignore *gitignore.Matcher
+		if gitIgnore {
+			ignore = gitignore.New(arg)
+		}
+		templates := make(chan string)
+		var findErr error
+		go func() {
+			defer close(templates)
+			findErr = FindTemplates(arg, ignore, templates)
+		}()
+		for fileName := range templates {
+			add(fileName)
+		}
+		if findErr != nil {
+			return nil, findErr
+		}
+	}
+	return fileNames, nil
+}
+
+// ReadFileList reads a list of file names, separated by newlines or NUL
+// characters, e.g. from the output of git diff --name-only, or git diff -z.
+func ReadFileList(r io.Reader) (fileNames []string, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file list: %w", err)
+	}
+	for _, fileName := range strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == '\n' || r == '\r' || r == 0
+	}) {
+		if fileName = strings.TrimSpace(fileName); fileName != "" {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	return fileNames, nil
+}
+
+// ReadFileListFrom reads a list of file names from the file, or from stdin if
+// the name is "-".
+func ReadFileListFrom(name string) (fileNames []string, err error) {
+	if name == "-" {
+		return ReadFileList(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file list: %w", err)
+	}
+	defer f.Close()
+	return ReadFileList(f)
+}
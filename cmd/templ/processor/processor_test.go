@@ -2,13 +2,17 @@ package processor
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestFindTemplates(t *testing.T) {
 	t.Run("returns an error if the directory does not exist", func(t *testing.T) {
 		output := make(chan string)
-		err := FindTemplates("nonexistent", output)
+		err := FindTemplates("nonexistent", nil, output)
 		if err == nil {
 			t.Fatal("expected error, but got nil")
 		}
@@ -17,3 +21,145 @@ func TestFindTemplates(t *testing.T) {
 		}
 	})
 }
+
+func TestExpandFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.templ", "b.templ", "c.go", "sub/d.templ", "sub/e.templ", "_skip/f.templ"} {
+		fileName := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fileName, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "files are returned in order",
+			args:     []string{"b.templ", "a.templ"},
+			expected: []string{"b.templ", "a.templ"},
+		},
+		{
+			name:     "files that aren't templ files, or don't exist, are skipped",
+			args:     []string{"a.templ", "c.go", "deleted.templ"},
+			expected: []string{"a.templ"},
+		},
+		{
+			name:     "directories are expanded",
+			args:     []string{"sub"},
+			expected: []string{"sub/d.templ", "sub/e.templ"},
+		},
+		{
+			name:     "patterns are expanded",
+			args:     []string{"*.templ", "*/e.templ"},
+			expected: []string{"a.templ", "b.templ", "sub/e.templ"},
+		},
+		{
+			name:     "duplicates are removed",
+			args:     []string{"sub/d.templ", "sub", "sub/*.templ"},
+			expected: []string{"sub/d.templ", "sub/e.templ"},
+		},
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ExpandFiles(tt.args, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := range actual {
+				actual[i] = filepath.ToSlash(actual[i])
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadFileList(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "newlines",
+			input:    "a.templ\nb.templ\n",
+			expected: []string{"a.templ", "b.templ"},
+		},
+		{
+			name:     "windows newlines and blank lines",
+			input:    "a.templ\r\n\r\nb.templ",
+			expected: []string{"a.templ", "b.templ"},
+		},
+		{
+			name:     "NUL characters",
+			input:    "a.templ\x00b c.templ\x00",
+			expected: []string{"a.templ", "b c.templ"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ReadFileList(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestOutputFileName(t *testing.T) {
+	root := filepath.FromSlash("/app")
+	tests := []struct {
+		name      string
+		outputDir string
+		templFile string
+		expected  string
+	}{
+		{
+			name:      "without an output directory, the file is next to the templ file",
+			templFile: "/app/components/button.templ",
+			expected:  "/app/components/button_templ.go",
+		},
+		{
+			name:      "the output directory mirrors the directories of the root",
+			outputDir: "/app/internal/gen",
+			templFile: "/app/components/button.templ",
+			expected:  "/app/internal/gen/components/button_templ.go",
+		},
+		{
+			name:      "files outside the root are next to the templ file",
+			outputDir: "/app/internal/gen",
+			templFile: "/other/button.templ",
+			expected:  "/other/button_templ.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := filepath.FromSlash(tt.outputDir)
+			templFile := filepath.FromSlash(tt.templFile)
+			actual := OutputFileName(root, outputDir, templFile, "_templ.go")
+			if diff := cmp.Diff(filepath.FromSlash(tt.expected), actual); diff != "" {
+				t.Error(diff)
+			}
+			if source := SourceFileName(root, outputDir, actual, "_templ.go"); source != templFile {
+				t.Errorf("expected the source file of %q to be %q, got %q", actual, templFile, source)
+			}
+		})
+	}
+}
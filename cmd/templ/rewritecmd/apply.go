@@ -0,0 +1,83 @@
+package rewritecmd
+
+import parser "github.com/a-h/templ/parser/v2"
+
+// Apply rewrites the templates of the file with the rules, and returns the
+// number of changes. The children of a node are rewritten before the node.
+func Apply(tf *parser.TemplateFile, rules ...Rule) (changes int) {
+	a := &applier{rules: rules}
+	for _, n := range tf.Nodes {
+		if t, ok := n.(parser.HTMLTemplate); ok {
+			parser.Walk(a, t)
+		}
+	}
+	return a.changes
+}
+
+type applier struct {
+	rules   []Rule
+	changes int
+	// stack of the nodes that are being walked.
+	stack []any
+}
+
+// Visit rewrites the children of a node once they've been walked. The nodes
+// passed to Visit are copies, but their lists of children share memory with the
+// template, so the children are replaced in place.
+func (a *applier) Visit(node any) parser.Visitor {
+	if node != nil {
+		a.stack = append(a.stack, node)
+		return a
+	}
+	node = a.stack[len(a.stack)-1]
+	a.stack = a.stack[:len(a.stack)-1]
+	switch n := node.(type) {
+	case parser.HTMLTemplate:
+		a.nodes(n.Children)
+	case parser.Element:
+		a.attributes(n.Attributes)
+		a.nodes(n.Children)
+	case parser.RawElement:
+		a.attributes(n.Attributes)
+	case parser.ConditionalAttribute:
+		a.attributes(n.Then)
+		a.attributes(n.Else)
+	case parser.TemplElementExpression:
+		a.nodes(n.Children)
+	case parser.IfExpression:
+		// The else if branches are walked, and rewritten, separately.
+		a.nodes(n.Then)
+		a.nodes(n.Else)
+	case parser.ElseIfExpression:
+		a.nodes(n.Then)
+	case parser.CaseExpression:
+		a.nodes(n.Children)
+	case parser.ForExpression:
+		a.nodes(n.Children)
+	case parser.BlockExpression:
+		a.nodes(n.Children)
+	}
+	return nil
+}
+
+func (a *applier) rewrite(node any) any {
+	for _, r := range a.rules {
+		if rewritten, ok := r.Rewrite(node); ok {
+			node = rewritten
+			a.changes++
+		}
+	}
+	return node
+}
+
+func (a *applier) nodes(nodes []parser.Node) {
+	for i, n := range nodes {
+		nodes[i] = a.rewrite(n).(parser.Node)
+	}
+}
+
+func (a *applier) attributes(attrs []parser.Attribute) {
+	for i, attr := range attrs {
+		attrs[i] = a.rewrite(attr).(parser.Attribute)
+	}
+}
@@ -0,0 +1,108 @@
+package rewritecmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/a-h/templ/cmd/templ/fmtcmd"
+	"github.com/a-h/templ/cmd/templ/gitignore"
+	"github.com/a-h/templ/cmd/templ/processor"
+	parser "github.com/a-h/templ/parser/v2"
+	"github.com/natefinch/atomic"
+)
+
+type Arguments struct {
+	// Path to the directory of templ files to rewrite.
+	Path string
+	// Rules of the form "pattern -> replacement", see ParseRule.
+	Rules []string
+	// DryRun lists the files that would be changed, without writing them.
+	DryRun      bool
+	WorkerCount int
+	// GitIgnore skips directories that are ignored by git.
+	GitIgnore bool
+}
+
+func Run(w io.Writer, args Arguments) (err error) {
+	if len(args.Rules) == 0 {
+		return errors.New("no rewrite rules provided")
+	}
+	rules := make([]Rule, len(args.Rules))
+	for i, r := range args.Rules {
+		if rules[i], err = ParseRule(r); err != nil {
+			return err
+		}
+	}
+	opts, err := fmtcmd.LoadOptions(args.Path)
+	if err != nil {
+		return err
+	}
+	if args.WorkerCount == 0 {
+		args.WorkerCount = runtime.NumCPU()
+	}
+
+	var m sync.Mutex
+	changes := map[string]int{}
+	process := func(fileName string) error {
+		n, err := rewriteFile(fileName, rules, opts, args.DryRun)
+		if err != nil || n == 0 {
+			return err
+		}
+		m.Lock()
+		defer m.Unlock()
+		changes[fileName] = n
+		return nil
+	}
+	var ignore *gitignore.Matcher
+	if args.GitIgnore {
+		ignore = gitignore.New(args.Path)
+	}
+	results := make(chan processor.Result)
+	go processor.Process(args.Path, ignore, process, args.WorkerCount, results)
+	var errs []error
+	for r := range results {
+		if r.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.FileName, r.Error))
+		}
+	}
+
+	fileNames := make([]string, 0, len(changes))
+	for fileName := range changes {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		fmt.Fprintf(w, "%s: %d changes\n", fileName, changes[fileName])
+	}
+	verb := "Rewrote"
+	if args.DryRun {
+		verb = "Would rewrite"
+	}
+	fmt.Fprintf(w, "%s %d files\n", verb, len(fileNames))
+	return errors.Join(errs...)
+}
+
+func rewriteFile(fileName string, rules []Rule, opts fmtcmd.Options, dryRun bool) (changes int, err error) {
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return 0, err
+	}
+	tf, err := parser.ParseStringWithOptions(string(src), opts.Parse)
+	if err != nil {
+		return 0, errors.New(parser.FormatError(string(src), err))
+	}
+	if changes = Apply(&tf, rules...); changes == 0 || dryRun {
+		return changes, nil
+	}
+	w := new(bytes.Buffer)
+	if err = tf.WriteWithOptions(w, opts.Format); err != nil {
+		return 0, fmt.Errorf("formatting error: %w", err)
+	}
+	return changes, atomic.WriteFile(fileName, w)
+}
@@ -0,0 +1,70 @@
+package rewritecmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.templ": "package main\n\ntempl a() {\n\t@Button(\"a\")\n}\n",
+		"b.templ": "package main\n\ntempl b() {\n\t<p>b</p>\n}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	args := Arguments{
+		Path:   dir,
+		Rules:  []string{"Button(x) -> NewButton(x)"},
+		DryRun: true,
+	}
+
+	t.Run("dry runs don't write files", func(t *testing.T) {
+		var sb strings.Builder
+		if err := Run(&sb, args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := filepath.Join(dir, "a.templ") + ": 1 changes\nWould rewrite 1 files\n"
+		if diff := cmp.Diff(expected, sb.String()); diff != "" {
+			t.Error(diff)
+		}
+		actual, err := os.ReadFile(filepath.Join(dir, "a.templ"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(files["a.templ"], string(actual)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("changed files are written", func(t *testing.T) {
+		args.DryRun = false
+		var sb strings.Builder
+		if err := Run(&sb, args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for name, expected := range map[string]string{
+			"a.templ": "package main\n\ntempl a() {\n\t@NewButton(\"a\")\n}\n",
+			"b.templ": files["b.templ"],
+		} {
+			actual, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(expected, string(actual)); diff != "" {
+				t.Errorf("%s: %s", name, diff)
+			}
+		}
+	})
+	t.Run("invalid rules are an error", func(t *testing.T) {
+		if err := Run(&strings.Builder{}, Arguments{Path: dir, Rules: []string{"Button(x)"}}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
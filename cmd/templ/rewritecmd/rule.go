@@ -0,0 +1,356 @@
+package rewritecmd
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	parser "github.com/a-h/templ/parser/v2"
+)
+
+// A Rule rewrites a node of a template. The node is a parser.Node, e.g.
+// parser.Element, or a parser.Attribute. Rewrite returns the new node, which
+// must be of the same kind, and true, or the node and false if it's unchanged.
+type Rule interface {
+	Rewrite(node any) (any, bool)
+}
+
+// RuleFunc is an adapter to allow the use of a function as a Rule.
+type RuleFunc func(node any) (any, bool)
+
+func (f RuleFunc) Rewrite(node any) (any, bool) {
+	return f(node)
+}
+
+// ParseRule parses a rule of the form "pattern -> replacement".
+//
+// Attribute rules rename attributes, e.g. "[hx-ws] -> [ws-connect]", or only
+// the attributes of an element, e.g. "button[variant] -> button[kind]".
+//
+// Other rules rewrite the Go expressions of templates, e.g. the call sites of
+// components, in the same way as gofmt -r: "Button(x) -> NewButton(x, "primary")".
+// Single-character lowercase identifiers in the pattern are wildcards that
+// match any expression, and are replaced with the matched expression in the
+// replacement.
+func ParseRule(s string) (Rule, error) {
+	pattern, replacement, ok := strings.Cut(s, "->")
+	if !ok {
+		return nil, fmt.Errorf("rewrite rule %q must be of the form 'pattern -> replacement'", s)
+	}
+	pattern, replacement = strings.TrimSpace(pattern), strings.TrimSpace(replacement)
+	if attributeSelector.MatchString(pattern) {
+		return parseAttributeRule(s, pattern, replacement)
+	}
+	return parseExpressionRule(s, pattern, replacement)
+}
+
+// attributeSelector matches element[attribute].
+var attributeSelector = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*)?\[([^\[\]=\s]+)\]$`)
+
+type attributeRule struct {
+	element string
+	from    string
+	to      string
+}
+
+func parseAttributeRule(s, pattern, replacement string) (Rule, error) {
+	p := attributeSelector.FindStringSubmatch(pattern)
+	r := attributeSelector.FindStringSubmatch(replacement)
+	if r == nil {
+		return nil, fmt.Errorf("rewrite rule %q: replacement must be an attribute, e.g. [name]", s)
+	}
+	if r[1] != "" && r[1] != p[1] {
+		return nil, fmt.Errorf("rewrite rule %q: attribute rules can't rename elements", s)
+	}
+	return attributeRule{element: p[1], from: p[2], to: r[2]}, nil
+}
+
+func (r attributeRule) Rewrite(node any) (any, bool) {
+	switch n := node.(type) {
+	case parser.Element:
+		if r.element != "" && r.element != n.Name {
+			return node, false
+		}
+		var changed bool
+		n.Attributes, changed = r.rename(n.Attributes)
+		return n, changed
+	case parser.RawElement:
+		if r.element != "" && r.element != n.Name {
+			return node, false
+		}
+		var changed bool
+		n.Attributes, changed = r.rename(n.Attributes)
+		return n, changed
+	}
+	return node, false
+}
+
+func (r attributeRule) rename(attrs []parser.Attribute) (renamed []parser.Attribute, changed bool) {
+	renamed = make([]parser.Attribute, len(attrs))
+	for i, attr := range attrs {
+		switch a := attr.(type) {
+		case parser.BoolConstantAttribute:
+			if a.Name == r.from {
+				a.Name, changed = r.to, true
+			}
+			attr = a
+		case parser.ConstantAttribute:
+			if a.Name == r.from {
+				a.Name, changed = r.to, true
+			}
+			attr = a
+		case parser.BoolExpressionAttribute:
+			if a.Name == r.from {
+				a.Name, changed = r.to, true
+			}
+			attr = a
+		case parser.ExpressionAttribute:
+			if a.Name == r.from {
+				a.Name, changed = r.to, true
+			}
+			attr = a
+		case parser.ConditionalAttribute:
+			var thenChanged, elseChanged bool
+			a.Then, thenChanged = r.rename(a.Then)
+			a.Else, elseChanged = r.rename(a.Else)
+			changed = changed || thenChanged || elseChanged
+			attr = a
+		}
+		renamed[i] = attr
+	}
+	if !changed {
+		return attrs, false
+	}
+	return renamed, true
+}
+
+type expressionRule struct {
+	pattern     ast.Expr
+	replacement ast.Expr
+}
+
+func parseExpressionRule(s, pattern, replacement string) (Rule, error) {
+	p, err := goparser.ParseExpr(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite rule %q: invalid pattern: %w", s, err)
+	}
+	r, err := goparser.ParseExpr(replacement)
+	if err != nil {
+		return nil, fmt.Errorf("rewrite rule %q: invalid replacement: %w", s, err)
+	}
+	return expressionRule{pattern: p, replacement: r}, nil
+}
+
+func (r expressionRule) Rewrite(node any) (any, bool) {
+	var changed bool
+	switch n := node.(type) {
+	case parser.TemplElementExpression:
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, changed
+	case parser.CallTemplateExpression:
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, changed
+	case parser.StringExpression:
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, changed
+	case parser.ExpressionAttribute:
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, changed
+	case parser.BoolExpressionAttribute:
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, changed
+	case parser.KeyExpressionAttribute:
+		var keyChanged bool
+		n.Key.Value, keyChanged = r.rewrite(n.Key.Value)
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, keyChanged || changed
+	case parser.SpreadAttributes:
+		n.Expression.Value, changed = r.rewrite(n.Expression.Value)
+		return n, changed
+	}
+	return node, false
+}
+
+// rewrite the matches of the pattern in the Go expression. Expressions that
+// don't match are returned as they are, to keep their formatting.
+func (r expressionRule) rewrite(expr string) (string, bool) {
+	fset := token.NewFileSet()
+	x, err := goparser.ParseExprFrom(fset, "", expr, 0)
+	if err != nil {
+		return expr, false
+	}
+	var changed bool
+	rewritten := apply(func(v reflect.Value) reflect.Value {
+		m := map[string]reflect.Value{}
+		if !match(m, reflect.ValueOf(r.pattern), v) {
+			return v
+		}
+		changed = true
+		return subst(m, reflect.ValueOf(r.replacement))
+	}, reflect.ValueOf(x))
+	if !changed {
+		return expr, false
+	}
+	var sb strings.Builder
+	if err = printer.Fprint(&sb, fset, rewritten.Interface()); err != nil {
+		return expr, false
+	}
+	return sb.String(), true
+}
+
+var (
+	identType     = reflect.TypeOf((*ast.Ident)(nil))
+	objectPtrType = reflect.TypeOf((*ast.Object)(nil))
+	positionType  = reflect.TypeOf(token.NoPos)
+)
+
+func isWildcard(name string) bool {
+	r, size := utf8.DecodeRuneInString(name)
+	return size == len(name) && unicode.IsLower(r)
+}
+
+// apply replaces each node of the expression with the result of f, starting
+// with the innermost nodes.
+func apply(f func(reflect.Value) reflect.Value, val reflect.Value) reflect.Value {
+	if !val.IsValid() || val.Type() == objectPtrType {
+		return val
+	}
+	switch v := reflect.Indirect(val); v.Kind() {
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i)
+			setValue(e, apply(f, e))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if e := v.Field(i); e.CanSet() {
+				setValue(e, apply(f, e))
+			}
+		}
+	case reflect.Interface:
+		if e := v.Elem(); e.IsValid() {
+			setValue(v, apply(f, e))
+		}
+	}
+	return f(val)
+}
+
+// setValue sets x to y, unless the replacement can't be used in its place,
+// e.g. a call that replaces the name of a selector.
+func setValue(x, y reflect.Value) {
+	if y.IsValid() && y.Type().AssignableTo(x.Type()) {
+		x.Set(y)
+	}
+}
+
+// match reports whether the pattern matches the value. Wildcards are added to
+// m, and a wildcard that's used more than once must match the same expression.
+func match(m map[string]reflect.Value, pattern, val reflect.Value) bool {
+	if m != nil && pattern.IsValid() && pattern.Type() == identType {
+		name := pattern.Interface().(*ast.Ident).Name
+		if isWildcard(name) && val.IsValid() {
+			if _, ok := val.Interface().(ast.Expr); ok && !val.IsNil() {
+				if old, ok := m[name]; ok {
+					return match(nil, old, val)
+				}
+				m[name] = val
+				return true
+			}
+		}
+	}
+	if !pattern.IsValid() || !val.IsValid() {
+		return !pattern.IsValid() && !val.IsValid()
+	}
+	if pattern.Type() != val.Type() {
+		return false
+	}
+	switch pattern.Type() {
+	case identType:
+		return pattern.Interface().(*ast.Ident).Name == val.Interface().(*ast.Ident).Name
+	case objectPtrType, positionType:
+		return true
+	}
+	p, v := reflect.Indirect(pattern), reflect.Indirect(val)
+	if !p.IsValid() || !v.IsValid() {
+		return !p.IsValid() && !v.IsValid()
+	}
+	switch p.Kind() {
+	case reflect.Slice:
+		if p.Len() != v.Len() {
+			return false
+		}
+		for i := 0; i < p.Len(); i++ {
+			if !match(m, p.Index(i), v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < p.NumField(); i++ {
+			if !match(m, p.Field(i), v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		return match(m, p.Elem(), v.Elem())
+	}
+	return p.Interface() == v.Interface()
+}
+
+// subst returns a copy of the pattern, with the wildcards replaced by the
+// matched expressions in m.
+func subst(m map[string]reflect.Value, pattern reflect.Value) reflect.Value {
+	if !pattern.IsValid() {
+		return reflect.Value{}
+	}
+	if pattern.Type() == identType {
+		if v, ok := m[pattern.Interface().(*ast.Ident).Name]; ok {
+			return v
+		}
+	}
+	switch pattern.Type() {
+	case positionType, objectPtrType:
+		// The positions of the replacement are in the rule, not the template.
+		return reflect.Zero(pattern.Type())
+	}
+	switch p := pattern; p.Kind() {
+	case reflect.Slice:
+		if p.IsNil() {
+			return p
+		}
+		v := reflect.MakeSlice(p.Type(), p.Len(), p.Len())
+		for i := 0; i < p.Len(); i++ {
+			v.Index(i).Set(subst(m, p.Index(i)))
+		}
+		return v
+	case reflect.Struct:
+		v := reflect.New(p.Type()).Elem()
+		for i := 0; i < p.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				f.Set(subst(m, p.Field(i)))
+			}
+		}
+		return v
+	case reflect.Pointer:
+		v := reflect.New(p.Type()).Elem()
+		if elem := p.Elem(); elem.IsValid() {
+			v.Set(subst(m, elem).Addr())
+		}
+		return v
+	case reflect.Interface:
+		v := reflect.New(p.Type()).Elem()
+		if elem := p.Elem(); elem.IsValid() {
+			v.Set(subst(m, elem))
+		}
+		return v
+	}
+	return pattern
+}
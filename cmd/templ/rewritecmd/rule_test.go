@@ -0,0 +1,280 @@
+package rewritecmd
+
+import (
+	"strings"
+	"testing"
+
+	parser "github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name            string
+		rules           []string
+		input           string
+		expected        string
+		expectedChanges int
+	}{
+		{
+			name:  "wildcards match any expression",
+			rules: []string{`Button(x) -> NewButton(x, "primary")`},
+			input: `package main
+
+templ page(label string) {
+	@Button(label)
+	@Button("Save " + label) {
+		<span>Icon</span>
+	}
+	@Other(label)
+}
+`,
+			expected: `package main
+
+templ page(label string) {
+	@NewButton(label, "primary")
+	@NewButton("Save "+label, "primary") {
+		<span>Icon</span>
+	}
+	@Other(label)
+}
+`,
+			expectedChanges: 2,
+		},
+		{
+			name:  "calls are rewritten within expressions and attributes",
+			rules: []string{`ui.Icon(n) -> icons.Get(n)`},
+			input: `package main
+
+templ page() {
+	@Layout(ui.Icon("home"))
+	<button icon={ ui.Icon("save") }>{ label(ui.Icon("x")) }</button>
+}
+`,
+			expected: `package main
+
+templ page() {
+	@Layout(icons.Get("home"))
+	<button icon={ icons.Get("save") }>{ label(icons.Get("x")) }</button>
+}
+`,
+			expectedChanges: 3,
+		},
+		{
+			name:  "a wildcard that is used twice must match the same expression",
+			rules: []string{`Pair(x, x) -> Single(x)`},
+			input: `package main
+
+templ page() {
+	@Pair(a, a)
+	@Pair(a, b)
+}
+`,
+			expected: `package main
+
+templ page() {
+	@Single(a)
+	@Pair(a, b)
+}
+`,
+			expectedChanges: 1,
+		},
+		{
+			name:  "attributes are renamed",
+			rules: []string{`[hx-ws] -> [ws-connect]`},
+			input: `package main
+
+templ page(url string) {
+	<div hx-ws="connect:/chat"></div>
+	<div hx-ws={ url }></div>
+	<div
+		if url != "" {
+			hx-ws={ url }
+		}
+	></div>
+}
+`,
+			expected: `package main
+
+templ page(url string) {
+	<div ws-connect="connect:/chat"></div>
+	<div ws-connect={ url }></div>
+	<div
+		if url != "" {
+			ws-connect={ url }
+		}
+	></div>
+}
+`,
+			expectedChanges: 3,
+		},
+		{
+			name:  "attributes of an element are renamed",
+			rules: []string{`button[variant] -> button[kind]`},
+			input: `package main
+
+templ page() {
+	<button variant="primary"></button>
+	<a variant="primary"></a>
+}
+`,
+			expected: `package main
+
+templ page() {
+	<button kind="primary"></button>
+	<a variant="primary"></a>
+}
+`,
+			expectedChanges: 1,
+		},
+		{
+			name: "rules are applied in order",
+			rules: []string{
+				`Button(x) -> NewButton(x)`,
+				`NewButton(x) -> ui.Button(x)`,
+			},
+			input: `package main
+
+templ page() {
+	for _, label := range labels {
+		if label != "" {
+			@Button(label)
+		}
+	}
+}
+`,
+			expected: `package main
+
+templ page() {
+	for _, label := range labels {
+		if label != "" {
+			@ui.Button(label)
+		}
+	}
+}
+`,
+			expectedChanges: 2,
+		},
+		{
+			name:  "nodes within branches and blocks are rewritten",
+			rules: []string{`Button(x) -> NewButton(x)`},
+			input: `package main
+
+templ page(kind string) {
+	if kind == "a" {
+		@Button("a")
+	} else if kind == "b" {
+		<div>
+			@Button("b")
+		</div>
+	} else {
+		@Button("c")
+	}
+	switch kind {
+		case "d":
+			@Button("d")
+	}
+	@Layout() {
+		@Button("e")
+	}
+}
+`,
+			expected: `package main
+
+templ page(kind string) {
+	if kind == "a" {
+		@NewButton("a")
+	} else if kind == "b" {
+		<div>
+			@NewButton("b")
+		</div>
+	} else {
+		@NewButton("c")
+	}
+	switch kind {
+		case "d":
+			@NewButton("d")
+	}
+	@Layout() {
+		@NewButton("e")
+	}
+}
+`,
+			expectedChanges: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []Rule
+			for _, s := range tt.rules {
+				r, err := ParseRule(s)
+				if err != nil {
+					t.Fatalf("failed to parse rule: %v", err)
+				}
+				rules = append(rules, r)
+			}
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			changes := Apply(&tf, rules...)
+			if changes != tt.expectedChanges {
+				t.Errorf("expected %d changes, got %d", tt.expectedChanges, changes)
+			}
+			var sb strings.Builder
+			if err = tf.Write(&sb); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRuleFunc(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ page() {
+	<img src="a.png"/>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	addAlt := RuleFunc(func(node any) (any, bool) {
+		e, ok := node.(parser.Element)
+		if !ok || e.Name != "img" {
+			return node, false
+		}
+		e.Attributes = append(e.Attributes, parser.ConstantAttribute{Name: "alt"})
+		return e, true
+	})
+	if changes := Apply(&tf, addAlt); changes != 1 {
+		t.Errorf("expected 1 change, got %d", changes)
+	}
+	var sb strings.Builder
+	if err = tf.Write(&sb); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	if !strings.Contains(sb.String(), `<img src="a.png" alt=""/>`) {
+		t.Errorf("expected alt attribute, got:\n%s", sb.String())
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []string{
+		`Button(x)`,
+		`Button(x -> NewButton(x)`,
+		`Button(x) -> NewButton(x`,
+		`[variant] -> kind`,
+		`button[variant] -> a[variant]`,
+	}
+	for _, rule := range tests {
+		t.Run(rule, func(t *testing.T) {
+			if _, err := ParseRule(rule); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package verifycmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/gitignore"
+	"github.com/a-h/templ/cmd/templ/processor"
+	"github.com/a-h/templ/generator"
+)
+
+type Arguments struct {
+	// Path to the directory of templ files to verify.
+	Path string
+	// Version of templ that the generated files must have been generated by.
+	Version string
+	// GitIgnore skips directories that are ignored by git.
+	GitIgnore bool
+	// OutputDir is the directory that the generated code is in, mirroring the
+	// directory tree of Path, see templ generate -output-dir. If empty, the
+	// generated code is next to each templ file.
+	OutputDir string
+}
+
+// Problem is a generated file that's stale, missing, or was generated by another
+// version of templ.
+type Problem struct {
+	FileName string
+	Message  string
+}
+
+// Run checks that the generated Go file of each templ file in the path is up to
+// date, using the version and source hash that templ generate writes to the start
+// of the file. Each problem is written to w, and an error is returned if there are
+// any problems.
+func Run(w io.Writer, args Arguments) error {
+	var ignore *gitignore.Matcher
+	if args.GitIgnore {
+		ignore = gitignore.New(args.Path)
+	}
+	problems, err := Verify(args.Path, args.OutputDir, args.Version, ignore)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s: %s\n", p.FileName, p.Message)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d generated files are out of date, run templ generate to update them", len(problems))
+	}
+	return nil
+}
+
+// Verify returns the problems with the generated files of the templ files in the
+// directory, sorted by file name. Directories that ignore matches are skipped.
+// If outputDir is set, the generated files are expected to be in outputDir,
+// instead of next to the templ files, see templ generate -output-dir.
+func Verify(dir, outputDir, version string, ignore *gitignore.Matcher) (problems []Problem, err error) {
+	// The directories must both be absolute, or both be relative, to find the
+	// path of each templ file relative to dir.
+	if outputDir != "" && filepath.IsAbs(dir) != filepath.IsAbs(outputDir) {
+		if dir, err = filepath.Abs(dir); err != nil {
+			return nil, err
+		}
+		if outputDir, err = filepath.Abs(outputDir); err != nil {
+			return nil, err
+		}
+	}
+	templates := map[string]bool{}
+	generated := map[string]bool{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (processor.ShouldSkipDir(path) || ignore.Ignored(path, true)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".templ") {
+			templates[path] = true
+		}
+		if strings.HasSuffix(path, "_templ.go") && outputDir == "" {
+			generated[path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if outputDir != "" {
+		err = filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(path, "_templ.go") {
+				generated[path] = true
+			}
+			return nil
+		})
+		// Nothing has been generated yet.
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	for fileName := range templates {
+		target := processor.OutputFileName(dir, outputDir, fileName, "_templ.go")
+		delete(generated, target)
+		message, err := verifyFile(fileName, target, version)
+		if err != nil {
+			return nil, err
+		}
+		if message != "" {
+			problems = append(problems, Problem{FileName: target, Message: message})
+		}
+	}
+	// Generated files without a templ file are left behind when a templ file is
+	// deleted or renamed.
+	for fileName := range generated {
+		if isGeneratedByTempl(fileName) {
+			problems = append(problems, Problem{FileName: fileName, Message: "the templ file has been deleted"})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].FileName < problems[j].FileName
+	})
+	return problems, nil
+}
+
+func verifyFile(fileName, target, version string) (message string, err error) {
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+	code, err := os.ReadFile(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "not generated", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	m := generator.ReadMetadata(code)
+	if m.Version == "" || m.SourceHash == "" {
+		return "no version or source hash, generate with -include-version", nil
+	}
+	if m.SourceHash != generator.SourceHash(src) {
+		return "the templ file has changed since it was generated", nil
+	}
+	if version != "" && m.Version != version {
+		return fmt.Sprintf("generated by templ %s, but templ %s is in use", m.Version, version), nil
+	}
+	return "", nil
+}
+
+// isGeneratedByTempl returns true if the comments before the package clause of
+// the file contain the header of generated templ code. Build constraints and
+// license headers can come before it.
+func isGeneratedByTempl(fileName string) bool {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == generatedHeader {
+			return true
+		}
+		if line != "" && !strings.HasPrefix(line, "//") {
+			return false
+		}
+	}
+	return false
+}
+
+const generatedHeader = "// Code generated by templ - DO NOT EDIT."
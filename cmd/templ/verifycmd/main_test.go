@@ -0,0 +1,109 @@
+package verifycmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	generated := func(version, src string) string {
+		return "// Code generated by templ - DO NOT EDIT.\n\n// templ: version: " + version + "\n// templ: source: " + generator.SourceHash([]byte(src)) + "\npackage main\n"
+	}
+	const src = "package main\n\ntempl a() {\n}\n"
+
+	write("current.templ", src)
+	write("current_templ.go", generated("v0.2.2", src))
+	write("changed.templ", src+"\n")
+	write("changed_templ.go", generated("v0.2.2", src))
+	write("old.templ", src)
+	write("old_templ.go", generated("v0.2.1", src))
+	write("missing.templ", src)
+	write("unstamped.templ", src)
+	write("unstamped_templ.go", "// Code generated by templ - DO NOT EDIT.\n\npackage main\n")
+	write("deleted_templ.go", generated("v0.2.2", src))
+	write("constrained_templ.go", "//go:build dev\n\n"+generated("v0.2.2", src))
+	write("other_templ.go", "package main\n")
+	write("mentioned_templ.go", "package main\n\n// Code generated by templ - DO NOT EDIT.\n")
+
+	problems, err := Verify(dir, "", "v0.2.2", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Problem{
+		{FileName: filepath.Join(dir, "changed_templ.go"), Message: "the templ file has changed since it was generated"},
+		{FileName: filepath.Join(dir, "constrained_templ.go"), Message: "the templ file has been deleted"},
+		{FileName: filepath.Join(dir, "deleted_templ.go"), Message: "the templ file has been deleted"},
+		{FileName: filepath.Join(dir, "missing_templ.go"), Message: "not generated"},
+		{FileName: filepath.Join(dir, "old_templ.go"), Message: "generated by templ v0.2.1, but templ v0.2.2 is in use"},
+		{FileName: filepath.Join(dir, "unstamped_templ.go"), Message: "no version or source hash, generate with -include-version"},
+	}
+	if diff := cmp.Diff(expected, problems); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("problems are an error", func(t *testing.T) {
+		var sb strings.Builder
+		err := Run(&sb, Arguments{Path: dir, Version: "v0.2.2"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if lines := strings.Count(sb.String(), "\n"); lines != len(expected) {
+			t.Errorf("expected %d lines, got %d:\n%s", len(expected), lines, sb.String())
+		}
+	})
+}
+
+func TestVerifyOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const src = "package components\n\ntempl a() {\n}\n"
+	generated := "// Code generated by templ - DO NOT EDIT.\n\n// templ: version: v0.2.2\n// templ: source: " + generator.SourceHash([]byte(src)) + "\npackage components\n"
+
+	write("src/components/current.templ", src)
+	write("gen/components/current_templ.go", generated)
+	write("src/components/missing.templ", src)
+	write("gen/components/deleted_templ.go", generated)
+
+	problems, err := Verify(filepath.Join(dir, "src"), filepath.Join(dir, "gen"), "v0.2.2", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Problem{
+		{FileName: filepath.Join(dir, "gen", "components", "deleted_templ.go"), Message: "the templ file has been deleted"},
+		{FileName: filepath.Join(dir, "gen", "components", "missing_templ.go"), Message: "not generated"},
+	}
+	if diff := cmp.Diff(expected, problems); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("a missing output directory has no generated files", func(t *testing.T) {
+		problems, err := Verify(filepath.Join(dir, "src"), filepath.Join(dir, "other"), "v0.2.2", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(problems) != 2 {
+			t.Errorf("expected 2 files that aren't generated, got %v", problems)
+		}
+	})
+}
@@ -50,6 +50,7 @@ func combine(templFileName string, left, right templ.Component) templ.Component
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `visualize.combine`, &templ_7745c5c3_SourceLines_78393794)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -129,6 +130,9 @@ func combine(templFileName string, left, right templ.Component) templ.Component
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = left.Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -159,6 +163,9 @@ func combine(templFileName string, left, right templ.Component) templ.Component
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = right.Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -174,6 +181,8 @@ func combine(templFileName string, left, right templ.Component) templ.Component
 	})
 }
 
+var templ_7745c5c3_SourceLines_78393794 = templ.SourceLines{FileName: `cmd/templ/visualize/sourcemapvisualisation.templ`, From: 45, To: 182, Lines: []int{45, 17, 64, 20, 77, 27, 89, 28, 99, 1, 111, 29, 121, 1, 136, 30, 144, 32, 154, 1, 169, 33}}
+
 func highlight(sourceId, targetId string) templ.ComponentScript {
 	return templ.ComponentScript{
 		Name: `__templ_highlight_ae80`,
@@ -188,6 +197,7 @@ func highlight(sourceId, targetId string) templ.ComponentScript {
 }`,
 		Call:       templ.SafeScript(`__templ_highlight_ae80`, sourceId, targetId),
 		CallInline: templ.SafeScriptInline(`__templ_highlight_ae80`, sourceId, targetId),
+		Params:     []any{sourceId, targetId},
 	}
 }
 
@@ -205,6 +215,7 @@ func removeHighlight(sourceId, targetId string) templ.ComponentScript {
 }`,
 		Call:       templ.SafeScript(`__templ_removeHighlight_58f2`, sourceId, targetId),
 		CallInline: templ.SafeScriptInline(`__templ_removeHighlight_58f2`, sourceId, targetId),
+		Params:     []any{sourceId, targetId},
 	}
 }
 
@@ -216,6 +227,7 @@ func mappedCharacter(s string, sourceID, targetID string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `visualize.mappedCharacter`, &templ_7745c5c3_SourceLines_7dc0d3d9)
 		templ_7745c5c3_Var10 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var10 == nil {
 			templ_7745c5c3_Var10 = templ.NopComponent
@@ -252,7 +264,15 @@ func mappedCharacter(s string, sourceID, targetID string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" onMouseOut=\"")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.ScriptDataArgsAttribute(templ_7745c5c3_Var13))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(" onMouseOut=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -261,7 +281,15 @@ func mappedCharacter(s string, sourceID, targetID string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\">")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.ScriptDataArgsAttribute(templ_7745c5c3_Var14))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -284,3 +312,5 @@ func mappedCharacter(s string, sourceID, targetID string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_7dc0d3d9 = templ.SourceLines{FileName: `cmd/templ/visualize/sourcemapvisualisation.templ`, From: 222, To: 314, Lines: []int{222, 62, 236, 63, 250, 1, 262, 63, 279, 63, 297, 63}}
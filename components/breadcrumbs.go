@@ -0,0 +1,45 @@
+package components
+
+import (
+	"context"
+	"io"
+)
+
+// Breadcrumb is a link in a Breadcrumbs trail.
+type Breadcrumb struct {
+	Label string
+	URL   string
+}
+
+// BreadcrumbsClasses are the classes of the elements of Breadcrumbs.
+type BreadcrumbsClasses struct {
+	// Nav is the class of the <nav> element.
+	Nav string
+	// List is the class of the <ol> element.
+	List string
+	// Item is the class of each <li> element.
+	Item string
+	// Link is the class of each link.
+	Link string
+	// Current is the class of the last item, which is the current page.
+	Current string
+}
+
+// Breadcrumbs is the trail of links from the home page to the current page. The
+// last breadcrumb is the current page, so it isn't a link.
+type Breadcrumbs struct {
+	Items []Breadcrumb
+	// Label is the accessible name of the navigation, which defaults to
+	// Breadcrumb.
+	Label   string
+	Classes BreadcrumbsClasses
+}
+
+// Render the navigation, with a list of links.
+func (b Breadcrumbs) Render(ctx context.Context, w io.Writer) error {
+	return breadcrumbsTemplate(b).Render(ctx, w)
+}
+
+func (b Breadcrumbs) label() string {
+	return valueOrDefault(b.Label, "Breadcrumb")
+}
@@ -0,0 +1,30 @@
+// Package components contains headless, unstyled building blocks for server
+// rendered applications: pagination, sortable table headers, breadcrumbs,
+// navigation links, responsive images, and SEO meta tags.
+//
+// The components render semantic HTML with ARIA attributes, and no styles. Each
+// component accepts classes for its elements, so that they can be styled with
+// any CSS framework.
+package components
+
+import (
+	"github.com/a-h/templ"
+)
+
+// class returns a class attribute, or no attributes if the class is empty.
+func class(classes ...string) templ.Attributes {
+	var c string
+	for _, s := range classes {
+		if s == "" {
+			continue
+		}
+		if c != "" {
+			c += " "
+		}
+		c += s
+	}
+	if c == "" {
+		return templ.Attributes{}
+	}
+	return templ.Attributes{"class": c}
+}
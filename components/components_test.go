@@ -0,0 +1,301 @@
+package components
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPageWindow(t *testing.T) {
+	tests := []struct {
+		current, total, size int
+		expected             []int
+	}{
+		{current: 1, total: 0, size: 2, expected: nil},
+		{current: 1, total: 1, size: 2, expected: []int{1}},
+		{current: 1, total: 5, size: 2, expected: []int{1, 2, 3, 4, 5}},
+		{current: 10, total: 20, size: 2, expected: []int{1, Gap, 8, 9, 10, 11, 12, Gap, 20}},
+		{current: 4, total: 20, size: 1, expected: []int{1, 2, 3, 4, 5, Gap, 20}},
+		{current: 20, total: 20, size: 2, expected: []int{1, Gap, 18, 19, 20}},
+		{current: 99, total: 20, size: 2, expected: []int{1, Gap, 18, 19, 20}},
+	}
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.expected, PageWindow(tt.current, tt.total, tt.size)); diff != "" {
+			t.Errorf("PageWindow(%d, %d, %d):\n%s", tt.current, tt.total, tt.size, diff)
+		}
+	}
+}
+
+func TestPageCount(t *testing.T) {
+	tests := []struct {
+		items, pageSize, expected int
+	}{
+		{items: 0, pageSize: 10, expected: 1},
+		{items: 10, pageSize: 10, expected: 1},
+		{items: 11, pageSize: 10, expected: 2},
+		{items: 11, pageSize: 0, expected: 1},
+	}
+	for _, tt := range tests {
+		if actual := PageCount(tt.items, tt.pageSize); actual != tt.expected {
+			t.Errorf("PageCount(%d, %d): expected %d, got %d", tt.items, tt.pageSize, tt.expected, actual)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	s := ParseSort(url.Values{"sort": {"-name"}}, "sort")
+	if diff := cmp.Diff(Sort{Column: "name", Descending: true}, s); diff != "" {
+		t.Error(diff)
+	}
+	if actual := s.Toggle("name").String(); actual != "name" {
+		t.Errorf("expected the order to be reversed, got %q", actual)
+	}
+	if actual := s.Toggle("age").String(); actual != "age" {
+		t.Errorf("expected another column to be sorted in ascending order, got %q", actual)
+	}
+}
+
+func render(t *testing.T, c templ.Component) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := c.Render(context.Background(), &sb); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	return sb.String()
+}
+
+func TestPagination(t *testing.T) {
+	u, _ := url.Parse("/search?q=cats&page=2")
+	p := QueryPagination(u, "page", 3)
+	p.Classes = PaginationClasses{Link: "link", Current: "current", Disabled: "disabled"}
+	expected := `<nav aria-label="Pagination"><ul>` +
+		`<li><a href="/search?page=1&amp;q=cats" rel="prev" class="link">Previous</a></li>` +
+		`<li><a href="/search?page=1&amp;q=cats" class="link">1</a></li>` +
+		`<li><a href="/search?page=2&amp;q=cats" aria-current="page" class="link current">2</a></li>` +
+		`<li><a href="/search?page=3&amp;q=cats" class="link">3</a></li>` +
+		`<li><a href="/search?page=3&amp;q=cats" rel="next" class="link">Next</a></li>` +
+		`</ul></nav>`
+	if diff := cmp.Diff(expected, render(t, p)); diff != "" {
+		t.Error(diff)
+	}
+
+	p.Current = 3
+	if actual := render(t, p); !strings.Contains(actual, `<li class="disabled"><span aria-disabled="true">Next</span></li>`) {
+		t.Errorf("expected the next item to be disabled on the last page, got %s", actual)
+	}
+}
+
+func TestSortableHeader(t *testing.T) {
+	u, _ := url.Parse("/users?page=2&sort=name")
+	h := SortableHeader{
+		Label:   "Name",
+		Column:  "name",
+		Sort:    ParseSort(u.Query(), "sort"),
+		URL:     u,
+		Classes: SortableHeaderClasses{Ascending: "asc"},
+	}
+	expected := `<th aria-sort="ascending" class="asc"><a href="/users?page=2&amp;sort=-name">Name</a></th>`
+	if diff := cmp.Diff(expected, render(t, h)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	b := Breadcrumbs{
+		Items: []Breadcrumb{
+			{Label: "Home", URL: "/"},
+			{Label: "Users", URL: "/users"},
+			{Label: "Alice"},
+		},
+		Classes: BreadcrumbsClasses{Current: "current"},
+	}
+	expected := `<nav aria-label="Breadcrumb"><ol>` +
+		`<li><a href="/">Home</a></li>` +
+		`<li><a href="/users">Users</a></li>` +
+		`<li class="current" aria-current="page">Alice</li>` +
+		`</ol></nav>`
+	if diff := cmp.Diff(expected, render(t, b)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNavLink(t *testing.T) {
+	u, _ := url.Parse("/settings/profile")
+	ctx := templ.WithRequestURL(context.Background(), u)
+	classes := NavLinkClasses{Link: "link", Active: "active"}
+	tests := []struct {
+		name     string
+		link     NavLink
+		expected string
+	}{
+		{
+			name:     "links to the current page are active",
+			link:     NavLink{Label: "Profile", URL: "/settings/profile", Classes: classes},
+			expected: `<a href="/settings/profile" aria-current="page" class="link active">Profile</a>`,
+		},
+		{
+			name:     "links to other pages aren't active",
+			link:     NavLink{Label: "Settings", URL: "/settings", Classes: classes},
+			expected: `<a href="/settings" class="link">Settings</a>`,
+		},
+		{
+			name:     "links are active for the pages below their URL with Prefix",
+			link:     NavLink{Label: "Settings", URL: "/settings", Prefix: true, Classes: classes},
+			expected: `<a href="/settings" aria-current="page" class="link active">Settings</a>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.link.Render(ctx, &sb); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    Image
+		expected string
+	}{
+		{
+			name:     "images are lazy loaded, with their dimensions",
+			image:    Image{Src: "/cat.jpg", Alt: "A cat", Width: 800, Height: 600},
+			expected: `<img src="/cat.jpg" alt="A cat" width="800" height="600" loading="lazy" decoding="async">`,
+		},
+		{
+			name: "eager images have a high priority",
+			image: Image{
+				Src:    "/hero.jpg",
+				Srcset: []ImageCandidate{{URL: "/hero.jpg", Density: 1}, {URL: "/hero@2x.jpg", Density: 2}},
+				Eager:  true,
+				Class:  "hero",
+			},
+			expected: `<img src="/hero.jpg" srcset="/hero.jpg 1x, /hero@2x.jpg 2x" alt="" class="hero" fetchpriority="high" decoding="async">`,
+		},
+		{
+			name: "commas and spaces in srcset URLs are escaped, and unsafe URLs are sanitized",
+			image: Image{
+				Src:    "javascript:alert(1)",
+				Srcset: []ImageCandidate{{URL: "/a,b c.jpg", Width: 400}, {URL: "javascript:alert(1)", Width: 800}},
+				Sizes:  "50vw",
+			},
+			expected: `<img src="about:invalid#TemplFailedSanitizationURL" srcset="/a%2Cb%20c.jpg 400w, about:invalid#TemplFailedSanitizationURL 800w" sizes="50vw" alt="" loading="lazy" decoding="async">`,
+		},
+		{
+			name: "images with sources are wrapped in a picture",
+			image: Image{
+				Src:    "/cat-800.jpg",
+				Alt:    "A cat",
+				Width:  800,
+				Height: 600,
+				Sizes:  "100vw",
+				Sources: []ImageSource{
+					{Type: "image/avif", Srcset: []ImageCandidate{{URL: "/cat-800.avif", Width: 800}}},
+					{Media: "(max-width: 400px)", Srcset: []ImageCandidate{{URL: "/cat-square.jpg", Width: 400}}, Sizes: "400px", Width: 400, Height: 400},
+				},
+			},
+			expected: `<picture>` +
+				`<source type="image/avif" srcset="/cat-800.avif 800w" sizes="100vw">` +
+				`<source media="(max-width: 400px)" srcset="/cat-square.jpg 400w" sizes="400px" width="400" height="400">` +
+				`<img src="/cat-800.jpg" sizes="100vw" alt="A cat" width="800" height="600" loading="lazy" decoding="async">` +
+				`</picture>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, render(t, tt.image)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAssetSrcset(t *testing.T) {
+	templ.SetAssetManifest(templ.AssetManifest{
+		BasePath: "/static/",
+		Files:    map[string]string{"cat-400.jpg": "cat-400.1a2b.jpg", "cat-800.jpg": "cat-800.3c4d.jpg"},
+	})
+	t.Cleanup(func() { templ.SetAssetManifest(templ.AssetManifest{}) })
+	expected := "/static/cat-400.1a2b.jpg 400w, /static/cat-800.3c4d.jpg 800w"
+	if diff := cmp.Diff(expected, srcset(AssetSrcset("cat-%d.jpg", 400, 800))); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     Meta
+		expected string
+	}{
+		{
+			name: "the Open Graph tags default to the title, description, canonical URL and image",
+			meta: Meta{
+				Title:       "Cats & dogs",
+				Description: "A post about pets.",
+				Canonical:   "https://example.com/posts/pets",
+				Image:       MetaImage{URL: "https://example.com/pets.jpg", Alt: "A cat and a dog", Width: 1200, Height: 630},
+				Twitter:     TwitterCard{Site: "@example"},
+			},
+			expected: `<title>Cats &amp; dogs</title>` +
+				`<meta name="description" content="A post about pets.">` +
+				`<link rel="canonical" href="https://example.com/posts/pets">` +
+				`<meta property="og:type" content="website">` +
+				`<meta property="og:title" content="Cats &amp; dogs">` +
+				`<meta property="og:description" content="A post about pets.">` +
+				`<meta property="og:url" content="https://example.com/posts/pets">` +
+				`<meta property="og:image" content="https://example.com/pets.jpg">` +
+				`<meta property="og:image:alt" content="A cat and a dog">` +
+				`<meta property="og:image:width" content="1200">` +
+				`<meta property="og:image:height" content="630">` +
+				`<meta name="twitter:card" content="summary_large_image">` +
+				`<meta name="twitter:site" content="@example">`,
+		},
+		{
+			name: "the Open Graph fields take precedence, and unsafe URLs are sanitized",
+			meta: Meta{
+				Title:     "Home",
+				Robots:    "noindex",
+				Canonical: "javascript:alert(1)",
+				OpenGraph: OpenGraph{Type: "article", Title: "Welcome", SiteName: "Example"},
+			},
+			expected: `<title>Home</title>` +
+				`<meta name="robots" content="noindex">` +
+				`<link rel="canonical" href="about:invalid#TemplFailedSanitizationURL">` +
+				`<meta property="og:type" content="article">` +
+				`<meta property="og:title" content="Welcome">` +
+				`<meta property="og:url" content="about:invalid#TemplFailedSanitizationURL">` +
+				`<meta property="og:site_name" content="Example">` +
+				`<meta name="twitter:card" content="summary">`,
+		},
+		{
+			name: "structured data is encoded so that it can't close the script element",
+			meta: Meta{
+				StructuredData: []any{map[string]any{"@type": "Article", "headline": "</script><script>alert(1)</script>"}},
+			},
+			expected: `<meta property="og:type" content="website">` +
+				`<meta name="twitter:card" content="summary">` +
+				`<script type="application/ld+json">{"@type":"Article","headline":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"}</script>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, render(t, tt.meta)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// ImageCandidate is a file of an image, at one of the sizes or pixel densities
+// in a srcset attribute.
+type ImageCandidate struct {
+	URL string
+	// Width is the width of the file in pixels, e.g. 800 for 800w. The browser
+	// picks the file that suits the sizes of the image.
+	Width int
+	// Density is the pixel density that the file is for, e.g. 2 for 2x. It's
+	// ignored if Width is set.
+	Density float64
+}
+
+// AssetSrcset returns the candidates of an image that's been resized to each
+// of the widths, with the URLs of the files from the asset manifest, see
+// templ.Asset. The format is the name of the files, with a verb for the width,
+// e.g. AssetSrcset("hero-%d.jpg", 400, 800, 1600).
+func AssetSrcset(format string, widths ...int) []ImageCandidate {
+	candidates := make([]ImageCandidate, len(widths))
+	for i, w := range widths {
+		candidates[i] = ImageCandidate{URL: string(templ.Asset(fmt.Sprintf(format, w))), Width: w}
+	}
+	return candidates
+}
+
+// ImageSource is a <source> of a <picture>, for another format of the image,
+// e.g. image/avif, or another crop of it for a media query.
+type ImageSource struct {
+	// Type is the MIME type of the files, e.g. image/webp. Browsers that don't
+	// support the type skip the source.
+	Type string
+	// Media is a media query, e.g. (min-width: 800px).
+	Media  string
+	Srcset []ImageCandidate
+	// Sizes defaults to the sizes of the image.
+	Sizes string
+	// Width and Height are the dimensions of the files, if they have another
+	// aspect ratio than the image.
+	Width, Height int
+}
+
+// Image is a responsive <img>. Its dimensions are always rendered, so that the
+// browser reserves space for the image before it loads, and the page doesn't
+// shift. Images are lazy loaded and decoded asynchronously, unless Eager is set.
+//
+// If the image has Sources, it's wrapped in a <picture>.
+type Image struct {
+	// Src is the URL of the image, for browsers that don't support srcset.
+	Src string
+	// Alt is the text alternative of the image. It's always rendered, since an
+	// empty alt marks an image as decorative.
+	Alt string
+	// Width and Height are the intrinsic dimensions of the image in pixels.
+	Width, Height int
+	Srcset        []ImageCandidate
+	// Sizes is the width of the image in the layout, for each media condition,
+	// e.g. (min-width: 800px) 50vw, 100vw.
+	Sizes   string
+	Sources []ImageSource
+	// Eager loads the image immediately with a high priority, for images that
+	// are visible when the page loads, e.g. a hero image.
+	Eager bool
+	Class string
+}
+
+// Render the image.
+func (img Image) Render(ctx context.Context, w io.Writer) error {
+	return imageTemplate(img).Render(ctx, w)
+}
+
+func (img Image) src() string {
+	return string(templ.URL(img.Src))
+}
+
+func (s ImageSource) sizes(img Image) string {
+	if s.Sizes != "" {
+		return s.Sizes
+	}
+	return img.Sizes
+}
+
+// srcset returns the value of a srcset attribute. Commas and whitespace
+// separate the candidates, so they're escaped in the URLs.
+func srcset(candidates []ImageCandidate) string {
+	var sb strings.Builder
+	for i, c := range candidates {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(srcsetEscaper.Replace(string(templ.URL(c.URL))))
+		switch {
+		case c.Width > 0:
+			sb.WriteString(" " + strconv.Itoa(c.Width) + "w")
+		case c.Density > 0:
+			sb.WriteString(" " + strconv.FormatFloat(c.Density, 'f', -1, 64) + "x")
+		}
+	}
+	return sb.String()
+}
+
+var srcsetEscaper = strings.NewReplacer(",", "%2C", " ", "%20", "\t", "%09", "\n", "%0A", "\r", "%0D", "\f", "%0C")
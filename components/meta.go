@@ -0,0 +1,147 @@
+package components
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// MetaImage is the image that's shown when a page is shared.
+type MetaImage struct {
+	URL string
+	// Alt describes the image, for people that can't see it.
+	Alt string
+	// Width and Height are the dimensions of the image in pixels. They let
+	// crawlers render the preview before the image is downloaded. Twitter cards
+	// don't have them.
+	Width, Height int
+}
+
+func (i MetaImage) url() string {
+	return string(templ.URL(i.URL))
+}
+
+// OpenGraph renders the Open Graph <meta> tags of a page, which are used for
+// the previews of pages shared on social networks and in chat apps.
+type OpenGraph struct {
+	// Type is the type of the page, e.g. article. It defaults to website.
+	Type        string
+	Title       string
+	Description string
+	// URL is the canonical URL of the page.
+	URL   string
+	Image MetaImage
+	// SiteName is the name of the site, e.g. Example Blog.
+	SiteName string
+	// Locale is the locale of the page, e.g. en_GB.
+	Locale string
+}
+
+// Render the Open Graph tags.
+func (og OpenGraph) Render(ctx context.Context, w io.Writer) error {
+	return openGraphTemplate(og).Render(ctx, w)
+}
+
+func (og OpenGraph) ogType() string {
+	if og.Type == "" {
+		return "website"
+	}
+	return og.Type
+}
+
+func (og OpenGraph) url() string {
+	return string(templ.URL(og.URL))
+}
+
+// TwitterCard renders the Twitter card <meta> tags of a page. Fields that
+// aren't set fall back to the Open Graph tags of the page.
+type TwitterCard struct {
+	// Card is the type of card, e.g. summary_large_image. It defaults to
+	// summary_large_image if the card has an image, and summary otherwise.
+	Card string
+	// Site is the account of the site, e.g. @example.
+	Site string
+	// Creator is the account of the author, e.g. @alice.
+	Creator     string
+	Title       string
+	Description string
+	Image       MetaImage
+}
+
+// Render the Twitter card tags.
+func (tc TwitterCard) Render(ctx context.Context, w io.Writer) error {
+	return twitterCardTemplate(tc).Render(ctx, w)
+}
+
+func (tc TwitterCard) card(hasImage bool) string {
+	if tc.Card != "" {
+		return tc.Card
+	}
+	if hasImage {
+		return "summary_large_image"
+	}
+	return "summary"
+}
+
+// Canonical renders a <link rel="canonical">, the URL that search engines index
+// the page as, e.g. without tracking parameters.
+func Canonical(url string) templ.Component {
+	return canonicalTemplate(string(templ.URL(url)))
+}
+
+// JSONLD renders a <script type="application/ld+json"> element that contains
+// structured data about the page, e.g. a schema.org Article, encoded as JSON.
+// See templ.JSONScript.
+func JSONLD(v any) templ.Component {
+	return templ.JSONScript("", v).WithType("application/ld+json")
+}
+
+// Meta renders the <title>, description, canonical link, Open Graph and Twitter
+// card tags, and structured data of a page, to be rendered in its <head>. The
+// title, description, canonical URL and image are used for the Open Graph tags,
+// unless the OpenGraph fields are set.
+type Meta struct {
+	Title       string
+	Description string
+	// Canonical is the canonical URL of the page.
+	Canonical string
+	// Robots tells crawlers whether to index the page, e.g. noindex.
+	Robots    string
+	Image     MetaImage
+	OpenGraph OpenGraph
+	Twitter   TwitterCard
+	// StructuredData are JSON-LD values, e.g. map[string]any{"@context":
+	// "https://schema.org", "@type": "Article", "headline": title}.
+	StructuredData []any
+}
+
+// Render the tags.
+func (m Meta) Render(ctx context.Context, w io.Writer) error {
+	return metaTemplate(m).Render(ctx, w)
+}
+
+func (m Meta) openGraph() OpenGraph {
+	og := m.OpenGraph
+	if og.Title == "" {
+		og.Title = m.Title
+	}
+	if og.Description == "" {
+		og.Description = m.Description
+	}
+	if og.URL == "" {
+		og.URL = m.Canonical
+	}
+	if og.Image.URL == "" {
+		og.Image = m.Image
+	}
+	return og
+}
+
+func (m Meta) twitterCard() TwitterCard {
+	tc := m.Twitter
+	if tc.Card == "" {
+		tc.Card = tc.card(tc.Image.URL != "" || m.openGraph().Image.URL != "")
+	}
+	return tc
+}
@@ -0,0 +1,40 @@
+package components
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// NavLinkClasses are the classes of a NavLink.
+type NavLinkClasses struct {
+	// Link is the class of the link.
+	Link string
+	// Active is added to the class of the link, if it links to the current page.
+	Active string
+}
+
+// NavLink is a link in a navigation menu. If it links to the current page, see
+// templ.IsActivePath, it's marked with aria-current="page" and the active
+// class.
+type NavLink struct {
+	Label string
+	URL   string
+	// Prefix marks the link as active for the pages below its URL too, e.g.
+	// /settings/profile for a link to /settings.
+	Prefix  bool
+	Classes NavLinkClasses
+}
+
+// Render the link.
+func (l NavLink) Render(ctx context.Context, w io.Writer) error {
+	return navLinkTemplate(l).Render(ctx, w)
+}
+
+func (l NavLink) isActive(ctx context.Context) bool {
+	if l.Prefix {
+		return templ.IsActivePath(ctx, l.URL, templ.MatchPrefix())
+	}
+	return templ.IsActivePath(ctx, l.URL)
+}
@@ -0,0 +1,147 @@
+package components
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// Gap is the page number of a gap in a page window, rendered as an ellipsis.
+const Gap = 0
+
+// PageWindow returns the page numbers to link to, out of total pages, when the
+// current page is shown. The first and last pages, and the pages within size of
+// the current page are included. Omitted pages are replaced by a Gap, unless a
+// single page is omitted, in which case that page is included instead, e.g.
+// PageWindow(10, 20, 2) returns [1 0 8 9 10 11 12 0 20].
+func PageWindow(current, total, size int) (pages []int) {
+	if total < 1 {
+		return nil
+	}
+	current = max(1, min(current, total))
+	from, to := max(1, current-size), min(total, current+size)
+	if from <= 3 {
+		from = 1
+	}
+	if to >= total-2 {
+		to = total
+	}
+	if from > 1 {
+		pages = append(pages, 1, Gap)
+	}
+	for p := from; p <= to; p++ {
+		pages = append(pages, p)
+	}
+	if to < total {
+		pages = append(pages, Gap, total)
+	}
+	return pages
+}
+
+// PageCount returns the number of pages required for the items, which is at
+// least 1.
+func PageCount(items, pageSize int) int {
+	if items <= 0 || pageSize <= 0 {
+		return 1
+	}
+	return (items + pageSize - 1) / pageSize
+}
+
+// ParsePage returns the page number in the query string parameter, or 1 if the
+// parameter is missing or invalid.
+func ParsePage(q url.Values, param string) int {
+	page, err := strconv.Atoi(q.Get(param))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
+// WithQuery returns the URL with the query string parameter set to the value,
+// keeping the other parameters, e.g. to link to another page of the same
+// search results.
+func WithQuery(u *url.URL, param, value string) string {
+	copied := *u
+	q := copied.Query()
+	q.Set(param, value)
+	copied.RawQuery = q.Encode()
+	return copied.String()
+}
+
+// PaginationClasses are the classes of the elements of a Pagination.
+type PaginationClasses struct {
+	// Nav is the class of the <nav> element.
+	Nav string
+	// List is the class of the <ul> element.
+	List string
+	// Item is the class of each <li> element.
+	Item string
+	// Link is the class of each link.
+	Link string
+	// Current is added to the class of the link to the current page.
+	Current string
+	// Disabled is added to the class of the previous and next items, if there
+	// is no previous or next page.
+	Disabled string
+	// Gap is the class of the ellipsis.
+	Gap string
+}
+
+// Pagination links to the pages of a list, e.g. search results.
+type Pagination struct {
+	// Current page, starting at 1.
+	Current int
+	// Total number of pages.
+	Total int
+	// Window is the number of pages either side of the current page to link
+	// to. See PageWindow.
+	Window int
+	// URL returns the URL of a page.
+	URL func(page int) string
+	// Previous and Next are the labels of the links to the previous and next
+	// pages. They default to Previous and Next.
+	Previous, Next string
+	// Label is the accessible name of the navigation, which defaults to
+	// Pagination.
+	Label   string
+	Classes PaginationClasses
+}
+
+// QueryPagination returns a pagination that links to pages of the URL, with the
+// page number in the query string parameter, e.g. ?page=2.
+func QueryPagination(u *url.URL, param string, total int) Pagination {
+	return Pagination{
+		Current: ParsePage(u.Query(), param),
+		Total:   total,
+		Window:  2,
+		URL: func(page int) string {
+			return WithQuery(u, param, strconv.Itoa(page))
+		},
+	}
+}
+
+// Render the navigation, with links to the previous and next pages, and the
+// pages in the window around the current page.
+func (p Pagination) Render(ctx context.Context, w io.Writer) error {
+	return paginationTemplate(p).Render(ctx, w)
+}
+
+func (p Pagination) label() string {
+	return valueOrDefault(p.Label, "Pagination")
+}
+
+func (p Pagination) previous() string {
+	return valueOrDefault(p.Previous, "Previous")
+}
+
+func (p Pagination) next() string {
+	return valueOrDefault(p.Next, "Next")
+}
+
+func valueOrDefault(v, d string) string {
+	if v == "" {
+		return d
+	}
+	return v
+}
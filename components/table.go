@@ -0,0 +1,106 @@
+package components
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Sort is the sort order of a table.
+type Sort struct {
+	// Column to sort by. If empty, the table isn't sorted.
+	Column string
+	// Descending is true if the column is sorted in descending order.
+	Descending bool
+}
+
+// ParseSort returns the sort order in the query string parameter, e.g. name is
+// sorted by name in ascending order, and -name is sorted by name in descending
+// order.
+func ParseSort(q url.Values, param string) Sort {
+	v := q.Get(param)
+	if column, ok := strings.CutPrefix(v, "-"); ok {
+		return Sort{Column: column, Descending: true}
+	}
+	return Sort{Column: v}
+}
+
+// String returns the query string value of the sort order. See ParseSort.
+func (s Sort) String() string {
+	if s.Descending {
+		return "-" + s.Column
+	}
+	return s.Column
+}
+
+// Toggle returns the sort order after the header of the column is clicked. If
+// the table is sorted by the column, the order is reversed, otherwise the table
+// is sorted by the column in ascending order.
+func (s Sort) Toggle(column string) Sort {
+	if s.Column == column {
+		return Sort{Column: column, Descending: !s.Descending}
+	}
+	return Sort{Column: column}
+}
+
+// ariaSort returns the value of the aria-sort attribute of the column header.
+func (s Sort) ariaSort(column string) string {
+	switch {
+	case s.Column != column:
+		return "none"
+	case s.Descending:
+		return "descending"
+	default:
+		return "ascending"
+	}
+}
+
+// SortableHeaderClasses are the classes of the elements of a SortableHeader.
+type SortableHeaderClasses struct {
+	// Header is the class of the <th> element.
+	Header string
+	// Link is the class of the link.
+	Link string
+	// Ascending and Descending are added to the class of the header, if the
+	// table is sorted by the column.
+	Ascending, Descending string
+}
+
+// SortableHeader is a table column header that links to the table sorted by the
+// column.
+type SortableHeader struct {
+	// Label of the column.
+	Label string
+	// Column is the name of the column in the query string.
+	Column string
+	// Sort is the current sort order of the table.
+	Sort Sort
+	// URL of the table.
+	URL *url.URL
+	// Param is the name of the query string parameter, which defaults to sort.
+	Param   string
+	Classes SortableHeaderClasses
+}
+
+// Render the <th> element. The aria-sort attribute is set, so that screen readers
+// announce the sort order.
+func (h SortableHeader) Render(ctx context.Context, w io.Writer) error {
+	return sortableHeaderTemplate(h).Render(ctx, w)
+}
+
+// href returns the URL of the table sorted by the column. The page number of
+// the table is kept.
+func (h SortableHeader) href() string {
+	return WithQuery(h.URL, valueOrDefault(h.Param, "sort"), h.Sort.Toggle(h.Column).String())
+}
+
+func (h SortableHeader) headerClass() string {
+	switch h.Sort.ariaSort(h.Column) {
+	case "ascending":
+		return h.Classes.Ascending
+	case "descending":
+		return h.Classes.Descending
+	}
+	return ""
+}
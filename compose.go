@@ -0,0 +1,79 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// Join returns a component that renders the components in order. Rendering
+// stops at the first component that returns an error.
+func Join(components ...Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, c := range components {
+			if c == nil {
+				continue
+			}
+			if err := c.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Map returns a component that renders the component returned by f for each
+// of the items, e.g. templ.Map(emails, emailRow). Like Join, nil components
+// are skipped.
+func Map[T any](items []T, f func(item T) Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, item := range items {
+			c := f(item)
+			if c == nil {
+				continue
+			}
+			if err := c.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// If returns the component if the condition is true, and a component that
+// renders nothing if it's false.
+func If(condition bool, c Component) Component {
+	if !condition {
+		return NopComponent
+	}
+	return c
+}
+
+// Wrap returns a component that renders the component with the children. If the
+// children return an error, or panic, the rest of the component, e.g. its end
+// tags or footer, is still rendered, and the error of the children is returned
+// once the component has rendered. It's used by @wrap, e.g. @wrap Layout() { }.
+func Wrap(c Component, children Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		var childrenErr error
+		wrapped := ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					if !shouldRecover(ctx, r) {
+						panic(r)
+					}
+					err = newPanicError(r)
+				}
+				// The error is returned once the component has rendered.
+				if childrenErr == nil {
+					childrenErr = err
+				}
+				err = nil
+			}()
+			return children.Render(ctx, w)
+		})
+		if err := c.Render(WithChildren(ctx, wrapped), w); err != nil {
+			return err
+		}
+		return childrenErr
+	})
+}
@@ -0,0 +1,117 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func text(s string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	})
+}
+
+func TestCompose(t *testing.T) {
+	failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return errors.New("failed")
+	})
+	// layout renders its children twice, within tags.
+	layout := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		children := templ.GetChildren(ctx)
+		for i := 0; i < 2; i++ {
+			if _, err := io.WriteString(w, "<main>"); err != nil {
+				return err
+			}
+			if err := children.Render(ctx, w); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "</main>"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	tests := []struct {
+		name          string
+		component     templ.Component
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:      "Join renders the components in order",
+			component: templ.Join(text("a"), nil, text("b")),
+			expected:  "ab",
+		},
+		{
+			name:          "Join stops at the first error",
+			component:     templ.Join(text("a"), failing, text("b")),
+			expected:      "a",
+			expectedError: true,
+		},
+		{
+			name:      "Join without components renders nothing",
+			component: templ.Join(),
+		},
+		{
+			name: "Map renders a component for each item",
+			component: templ.Map([]int{1, 2, 3}, func(i int) templ.Component {
+				return text(strings.Repeat("*", i) + " ")
+			}),
+			expected: "* ** *** ",
+		},
+		{
+			name: "Map skips nil components",
+			component: templ.Map([]int{1, 2, 3}, func(i int) templ.Component {
+				if i == 2 {
+					return nil
+				}
+				return text(strings.Repeat("*", i) + " ")
+			}),
+			expected: "* *** ",
+		},
+		{
+			name:      "If renders the component if the condition is true",
+			component: templ.Join(templ.If(true, text("a")), templ.If(false, text("b"))),
+			expected:  "a",
+		},
+		{
+			name:      "Wrap renders the component with the children",
+			component: templ.Wrap(layout, text("a")),
+			expected:  "<main>a</main><main>a</main>",
+		},
+		{
+			name:          "Wrap renders the rest of the component if the children fail",
+			component:     templ.Wrap(layout, templ.Join(text("a"), failing)),
+			expected:      "<main>a</main><main>a</main>",
+			expectedError: true,
+		},
+		{
+			name: "Wrap renders the rest of the component if the children panic",
+			component: templ.Wrap(layout, templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				panic("oops")
+			})),
+			expected:      "<main></main><main></main>",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			err := tt.component.Render(context.Background(), &sb)
+			if tt.expectedError != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package templ
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+// CriticalCSS renders the component, collecting the CSS of the css components
+// it uses into a single <style> element that's inserted before the closing
+// </head> tag, so that the styles are available before the page is painted. If
+// the output has no </head> tag, the <style> element is written first, after the
+// <!DOCTYPE> if there is one.
+//
+// Classes registered with the CSSMiddleware are skipped, since they're included
+// in the linked stylesheet.
+func CriticalCSS(c Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		ctx, v := getContext(ctx)
+		if v.criticalCSS != nil {
+			// The CSS is already being collected by a parent.
+			return c.Render(ctx, w)
+		}
+		v.criticalCSS = new(strings.Builder)
+		defer func() { v.criticalCSS = nil }()
+
+		buf := GetBuffer()
+		defer ReleaseBuffer(buf)
+		if err = c.Render(ctx, buf); err != nil {
+			return err
+		}
+		if v.criticalCSS.Len() == 0 {
+			_, err = w.Write(buf.Bytes())
+			return err
+		}
+		html := buf.Bytes()
+		lower := bytes.ToLower(html)
+		index := bytes.Index(lower, []byte("</head>"))
+		if index < 0 {
+			index = afterDoctype(lower)
+		}
+		if _, err = w.Write(html[:index]); err != nil {
+			return err
+		}
+		if _, err = io.WriteString(w, `<style type="text/css">`+v.criticalCSS.String()+`</style>`); err != nil {
+			return err
+		}
+		_, err = w.Write(html[index:])
+		return err
+	})
+}
+
+// afterDoctype returns the index after the <!DOCTYPE> at the start of the HTML,
+// or 0 if there isn't one.
+func afterDoctype(lower []byte) int {
+	start := len(lower) - len(bytes.TrimLeft(lower, " \t\r\n\f"))
+	if !bytes.HasPrefix(lower[start:], []byte("<!doctype")) {
+		return 0
+	}
+	end := bytes.IndexByte(lower[start:], '>')
+	if end < 0 {
+		return 0
+	}
+	return start + end + 1
+}
@@ -0,0 +1,93 @@
+package templ_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCriticalCSS(t *testing.T) {
+	red := templ.ComponentCSSClass{ID: "red", Class: templ.SafeCSS(".red{color:red;}")}
+	blue := templ.ComponentCSSClass{ID: "blue", Class: templ.SafeCSS(".blue{color:blue;}")}
+	button := func(class templ.ComponentCSSClass) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if err := templ.RenderCSSItems(ctx, w, class); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, `<button class="`+class.ClassName()+`"></button>`)
+			return err
+		})
+	}
+	page := func(head string, buttons ...templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, head); err != nil {
+				return err
+			}
+			for _, b := range buttons {
+				if err := b.Render(ctx, w); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	tests := []struct {
+		name     string
+		input    templ.Component
+		expected string
+	}{
+		{
+			name:     "styles are inserted before the closing head tag, once per class",
+			input:    page(`<html><head></head>`, button(red), button(blue), button(red)),
+			expected: `<html><head><style type="text/css">.red{color:red;}.blue{color:blue;}</style></head><button class="red"></button><button class="blue"></button><button class="red"></button>`,
+		},
+		{
+			name:     "styles are written first if there is no head",
+			input:    page(``, button(red)),
+			expected: `<style type="text/css">.red{color:red;}</style><button class="red"></button>`,
+		},
+		{
+			name:     "styles are written after the doctype if there is no head",
+			input:    page("<!DOCTYPE html>\n<body>", button(red)),
+			expected: "<!DOCTYPE html><style type=\"text/css\">.red{color:red;}</style>\n<body><button class=\"red\"></button>",
+		},
+		{
+			name:     "nothing is inserted if no styles are used",
+			input:    page(`<head></head>`),
+			expected: `<head></head>`,
+		},
+		{
+			name:     "nested components are collected by the outermost",
+			input:    page(`<head></head>`, templ.CriticalCSS(button(red))),
+			expected: `<head><style type="text/css">.red{color:red;}</style></head><button class="red"></button>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := templ.CriticalCSS(tt.input).Render(context.Background(), w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("classes in the global stylesheet are skipped", func(t *testing.T) {
+		h := templ.Handler(page(`<head></head>`, button(red), button(blue)), templ.WithCriticalCSS())
+		mw := templ.NewCSSMiddleware(h, blue)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		expected := `<head><style type="text/css">.red{color:red;}</style></head><button class="red"></button><button class="blue"></button>`
+		if diff := cmp.Diff(expected, w.Body.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// DefaultCSRFFieldName is the name of the hidden input rendered by CSRF, if the
+// CSRFMiddleware doesn't set a field name.
+const DefaultCSRFFieldName = "csrf_token"
+
+const csrfContextKey = contextKeyType(3)
+
+type csrfToken struct {
+	fieldName string
+	token     string
+}
+
+// WithCSRFToken returns a context that contains the CSRF token, and the name of
+// the form field that it's submitted in, so that it's rendered by CSRF.
+func WithCSRFToken(ctx context.Context, fieldName, token string) context.Context {
+	return context.WithValue(ctx, csrfContextKey, csrfToken{fieldName: fieldName, token: token})
+}
+
+// GetCSRFToken returns the CSRF token of the context, and the name of the form
+// field that it's submitted in. The token is empty if the context doesn't
+// contain one.
+func GetCSRFToken(ctx context.Context) (fieldName, token string) {
+	t, _ := ctx.Value(csrfContextKey).(csrfToken)
+	return t.fieldName, t.token
+}
+
+// CSRF renders a hidden input that contains the CSRF token of the context, for
+// use inside forms. Nothing is rendered if the context doesn't contain a token.
+func CSRF() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		fieldName, token := GetCSRFToken(ctx)
+		if token == "" {
+			return nil
+		}
+		return writeStrings(w, `<input type="hidden" name="`, EscapeString(fieldName), `" value="`, EscapeString(token), `">`)
+	})
+}
+
+// CSRFMeta renders a <meta name="csrf-token"> element that contains the CSRF
+// token of the context, for use by client-side scripts. Nothing is rendered if
+// the context doesn't contain a token.
+func CSRFMeta() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, token := GetCSRFToken(ctx)
+		if token == "" {
+			return nil
+		}
+		return writeStrings(w, `<meta name="csrf-token" content="`, EscapeString(token), `">`)
+	})
+}
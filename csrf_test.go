@@ -0,0 +1,57 @@
+package templ_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCSRF(t *testing.T) {
+	page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := templ.CSRFMeta().Render(ctx, w); err != nil {
+			return err
+		}
+		return templ.CSRF().Render(ctx, w)
+	})
+	tests := []struct {
+		name      string
+		fieldName string
+		token     string
+		expected  string
+	}{
+		{
+			name:      "the token is rendered in a hidden input and a meta element",
+			fieldName: templ.DefaultCSRFFieldName,
+			token:     `abc"def`,
+			expected:  `<meta name="csrf-token" content="abc&#34;def"><input type="hidden" name="csrf_token" value="abc&#34;def">`,
+		},
+		{
+			name:      "the field name can be changed",
+			fieldName: "gorilla.csrf.Token",
+			token:     "abc",
+			expected:  `<meta name="csrf-token" content="abc"><input type="hidden" name="gorilla.csrf.Token" value="abc">`,
+		},
+		{
+			name:      "nothing is rendered without a token",
+			fieldName: templ.DefaultCSRFFieldName,
+			expected:  ``,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mw := templ.NewCSRFMiddleware(templ.Handler(page), func(r *http.Request) string { return tt.token })
+			mw.FieldName = tt.fieldName
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if diff := cmp.Diff(tt.expected, w.Body.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
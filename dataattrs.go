@@ -0,0 +1,61 @@
+package templ
+
+import (
+	"sort"
+	"strings"
+)
+
+// DataAttrs returns data-* attributes to spread onto an element, e.g.
+//
+//	<div { templ.DataAttrs(map[string]any{"userId": u.ID, "tags": u.Tags})... }>
+//
+// Names are converted to data attribute names as they are by the dataset
+// property of elements, e.g. userId is rendered as data-user-id. Names may also
+// be given in that form, with or without the data- prefix. Strings are used as
+// is, and other values are JSON encoded. Values are HTML escaped when they're
+// rendered. Names that aren't valid attribute names, and values that can't be
+// JSON encoded, are skipped.
+//
+// If names are converted to the same attribute name, e.g. userId and user-id,
+// the value of the name that sorts first is used.
+func DataAttrs(values map[string]any) Attributes {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := Attributes{}
+	for _, key := range keys {
+		value := values[key]
+		name := dataAttributeName(key)
+		if _, exists := attrs[name]; exists || !isValidAttributeName(name) {
+			continue
+		}
+		if s, ok := value.(string); ok {
+			attrs[name] = s
+			continue
+		}
+		data, err := marshalJSON(value)
+		if err != nil {
+			continue
+		}
+		attrs[name] = string(data)
+	}
+	return attrs
+}
+
+// dataAttributeName returns the name of the data attribute of a dataset
+// property, e.g. data-user-id for userId.
+func dataAttributeName(key string) string {
+	key = strings.TrimPrefix(key, "data-")
+	var sb strings.Builder
+	sb.WriteString("data-")
+	for _, r := range key {
+		if r >= 'A' && r <= 'Z' {
+			sb.WriteByte('-')
+			r += 'a' - 'A'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
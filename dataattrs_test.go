@@ -0,0 +1,77 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDataAttrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]any
+		expected templ.Attributes
+	}{
+		{
+			name:     "strings are used as is",
+			input:    map[string]any{"controller": "search"},
+			expected: templ.Attributes{"data-controller": "search"},
+		},
+		{
+			name: "other values are JSON encoded",
+			input: map[string]any{
+				"count":   3,
+				"enabled": true,
+				"tags":    []string{"a", "b"},
+				"user":    struct{ Name string }{Name: "Ada"},
+				"missing": nil,
+			},
+			expected: templ.Attributes{
+				"data-count":   "3",
+				"data-enabled": "true",
+				"data-tags":    `["a","b"]`,
+				"data-user":    `{"Name":"Ada"}`,
+				"data-missing": "null",
+			},
+		},
+		{
+			name:     "names are converted as they are by the dataset property",
+			input:    map[string]any{"userId": "1", "event-name": "signup", "data-page": "home"},
+			expected: templ.Attributes{"data-user-id": "1", "data-event-name": "signup", "data-page": "home"},
+		},
+		{
+			name:     "the value of the name that sorts first is used for names that are converted to the same name",
+			input:    map[string]any{"userId": "3", "user-id": "2", "data-user-id": "1"},
+			expected: templ.Attributes{"data-user-id": "1"},
+		},
+		{
+			name:     "invalid names and values that can't be encoded are skipped",
+			input:    map[string]any{`a"b`: "x", "fn": func() {}, "ok": "y"},
+			expected: templ.Attributes{"data-ok": "y"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := templ.DataAttrs(tt.input)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDataAttrsAreEscaped(t *testing.T) {
+	var sb strings.Builder
+	attrs := templ.DataAttrs(map[string]any{"props": map[string]any{"title": `"><script>`}})
+	if err := templ.RenderAttributes(context.Background(), &sb, attrs); err != nil {
+		t.Fatalf("failed to render attributes: %v", err)
+	}
+	expected := ` data-props="{&#34;title&#34;:&#34;\&#34;\u003e\u003cscript\u003e&#34;}"`
+	if diff := cmp.Diff(expected, sb.String()); diff != "" {
+		t.Error(diff)
+	}
+}
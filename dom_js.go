@@ -0,0 +1,39 @@
+//go:build js && wasm
+
+package templ
+
+import (
+	"context"
+	"errors"
+	"syscall/js"
+)
+
+// RenderToElement renders the component in the browser, and replaces the
+// contents of the DOM element with the output, e.g. to render a widget in a
+// program compiled with GOOS=js GOARCH=wasm.
+//
+// The output is set as the innerHTML of the element, so <script> elements in
+// the output aren't run.
+func RenderToElement(ctx context.Context, el js.Value, c Component) (err error) {
+	if el.IsNull() || el.IsUndefined() {
+		return errors.New("templ: cannot render to a null or undefined element")
+	}
+	b := GetBuffer()
+	defer ReleaseBuffer(b)
+	if err = c.Render(ctx, b); err != nil {
+		return err
+	}
+	el.Set("innerHTML", b.String())
+	return nil
+}
+
+// RenderToElementByID renders the component in the browser, and replaces the
+// contents of the DOM element that has the id with the output. See
+// RenderToElement.
+func RenderToElementByID(ctx context.Context, id string, c Component) (err error) {
+	el := js.Global().Get("document").Call("getElementById", id)
+	if el.IsNull() {
+		return errors.New("templ: element with id " + id + " not found")
+	}
+	return RenderToElement(ctx, el, c)
+}
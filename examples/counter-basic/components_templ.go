@@ -19,6 +19,7 @@ func counts(global, user int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.counts`, &templ_7745c5c3_SourceLines_828ffd90)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -61,6 +62,8 @@ func counts(global, user int) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_828ffd90 = templ.SourceLines{FileName: `examples/counter-basic/components.templ`, From: 14, To: 63, Lines: []int{14, 5, 33, 6, 46, 7}}
+
 func form() templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templ_7745c5c3_W.(*bytes.Buffer)
@@ -69,6 +72,7 @@ func form() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.form`, &templ_7745c5c3_SourceLines_148a3809)
 		templ_7745c5c3_Var4 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var4 == nil {
 			templ_7745c5c3_Var4 = templ.NopComponent
@@ -85,6 +89,8 @@ func form() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_148a3809 = templ.SourceLines{FileName: `examples/counter-basic/components.templ`, From: 67, To: 90, Lines: []int{67, 10}}
+
 func page(global, user int) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templ_7745c5c3_W.(*bytes.Buffer)
@@ -93,6 +99,7 @@ func page(global, user int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.page`, &templ_7745c5c3_SourceLines_3b6feed8)
 		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var5 == nil {
 			templ_7745c5c3_Var5 = templ.NopComponent
@@ -102,10 +109,16 @@ func page(global, user int) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = counts(global, user).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = form().Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -120,3 +133,5 @@ func page(global, user int) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_3b6feed8 = templ.SourceLines{FileName: `examples/counter-basic/components.templ`, From: 94, To: 135, Lines: []int{94, 17, 115, 41, 122, 42}}
@@ -34,6 +34,7 @@ func counts(global, session int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `components.counts`, &templ_7745c5c3_SourceLines_5b8147d4)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -120,6 +121,8 @@ func counts(global, session int) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_5b8147d4 = templ.SourceLines{FileName: `examples/counter/components/components.templ`, From: 29, To: 122, Lines: []int{29, 13, 47, 16, 57, 1, 70, 17, 82, 21, 92, 1, 105, 22}}
+
 func Page(global, session int) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templ_7745c5c3_W.(*bytes.Buffer)
@@ -128,6 +131,7 @@ func Page(global, session int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `components.Page`, &templ_7745c5c3_SourceLines_33467ccf)
 		templ_7745c5c3_Var8 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var8 == nil {
 			templ_7745c5c3_Var8 = templ.NopComponent
@@ -137,6 +141,9 @@ func Page(global, session int) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = counts(global, session).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -151,3 +158,5 @@ func Page(global, session int) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_33467ccf = templ.SourceLines{FileName: `examples/counter/components/components.templ`, From: 126, To: 160, Lines: []int{126, 30, 147, 55}}
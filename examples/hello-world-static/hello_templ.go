@@ -17,6 +17,7 @@ func hello(name string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.hello`, &templ_7745c5c3_SourceLines_6f942bcd)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -45,3 +46,5 @@ func hello(name string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_6f942bcd = templ.SourceLines{FileName: `examples/hello-world-static/hello.templ`, From: 12, To: 48, Lines: []int{12, 3, 31, 4}}
@@ -17,6 +17,7 @@ func Home() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.Home`, &templ_7745c5c3_SourceLines_ce37681a)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -32,3 +33,5 @@ func Home() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_ce37681a = templ.SourceLines{FileName: `examples/integration-chi/home.templ`, From: 12, To: 35, Lines: []int{12, 3}}
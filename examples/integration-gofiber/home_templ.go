@@ -17,6 +17,7 @@ func Home(name string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.Home`, &templ_7745c5c3_SourceLines_ce37681a)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -46,6 +47,8 @@ func Home(name string) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_ce37681a = templ.SourceLines{FileName: `examples/integration-gofiber/home.templ`, From: 12, To: 48, Lines: []int{12, 3, 31, 4}}
+
 func NotFound() templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templ_7745c5c3_W.(*bytes.Buffer)
@@ -54,6 +57,7 @@ func NotFound() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.NotFound`, &templ_7745c5c3_SourceLines_7cc791cf)
 		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var3 == nil {
 			templ_7745c5c3_Var3 = templ.NopComponent
@@ -69,3 +73,5 @@ func NotFound() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_7cc791cf = templ.SourceLines{FileName: `examples/integration-gofiber/home.templ`, From: 52, To: 75, Lines: []int{52, 7}}
@@ -17,6 +17,7 @@ func list(items []string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `main.list`, &templ_7745c5c3_SourceLines_63cb635f)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -27,6 +28,9 @@ func list(items []string) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		for _, item := range items {
+			if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<li>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -55,3 +59,5 @@ func list(items []string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_63cb635f = templ.SourceLines{FileName: `examples/syntax-and-usage/components/templsyntax.templ`, From: 12, To: 61, Lines: []int{12, 3, 30, 5, 39, 6}}
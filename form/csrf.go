@@ -0,0 +1,29 @@
+package form
+
+import (
+	"context"
+
+	"github.com/a-h/templ"
+)
+
+// CSRFFieldName is the name of the hidden input that contains the CSRF token.
+const CSRFFieldName = templ.DefaultCSRFFieldName
+
+// WithCSRFToken returns a context that contains the CSRF token, so that it's
+// rendered by CSRFField. It's equivalent to templ.WithCSRFToken with
+// CSRFFieldName.
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return templ.WithCSRFToken(ctx, CSRFFieldName, token)
+}
+
+// CSRFToken returns the CSRF token of the context, or an empty string.
+func CSRFToken(ctx context.Context) string {
+	_, token := templ.GetCSRFToken(ctx)
+	return token
+}
+
+// CSRFField renders a hidden input that contains the CSRF token of the context,
+// if the context contains one. See templ.CSRF.
+func CSRFField() templ.Component {
+	return templ.CSRF()
+}
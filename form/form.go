@@ -0,0 +1,222 @@
+// Package form renders the fields of HTML forms from structs, and decodes
+// submitted forms into structs.
+//
+// Fields are configured with struct tags:
+//
+//	type Signup struct {
+//		Email    string `form:"email,required" label:"Email address" type:"email"`
+//		Password string `form:"password,required" type:"password"`
+//		Age      int    `form:"age"`
+//		Terms    bool   `form:"terms" label:"I agree to the terms"`
+//	}
+//
+// The form tag sets the name of the field, and whether it's required. Fields
+// without a form tag are skipped. The label tag sets the label, which defaults
+// to the name of the struct field, and the type tag sets the type of the input,
+// which defaults to a type based on the type of the struct field.
+package form
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// Errors maps the names of fields to their validation error messages.
+type Errors map[string]string
+
+// Field of a form.
+type Field struct {
+	// Name of the field, used as the name and ID of the input.
+	Name string
+	// Label of the field.
+	Label string
+	// Type of the input, e.g. text, email, number, checkbox, or textarea.
+	Type string
+	// Value of the input.
+	Value string
+	// Checked is true if the field is a checkbox, and the value is true.
+	Checked bool
+	// Required is true if the field must have a value.
+	Required bool
+	// Error message of the field, if it's invalid.
+	Error string
+}
+
+// Render the label, input and error message of the field.
+func (f Field) Render(ctx context.Context, w io.Writer) error {
+	return fieldTemplate(f).Render(ctx, w)
+}
+
+// Fields returns the fields of the struct, with the values of the struct, so
+// that the form is repopulated if it's rendered after a validation failure.
+// Password fields are never populated.
+func Fields(v any, errs Errors) (fields []Field, err error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form: expected a struct, got %T", v)
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, required, ok := parseFormTag(sf)
+		if !ok {
+			continue
+		}
+		f := Field{
+			Name:     name,
+			Label:    sf.Tag.Get("label"),
+			Type:     sf.Tag.Get("type"),
+			Required: required,
+			Error:    errs[name],
+		}
+		if f.Label == "" {
+			f.Label = sf.Name
+		}
+		fv := rv.Field(i)
+		if f.Type == "" {
+			if f.Type, ok = inputTypes[fv.Kind()]; !ok {
+				return nil, fmt.Errorf("form: unsupported type %s of field %s", fv.Type(), sf.Name)
+			}
+		}
+		if fv.Kind() == reflect.Bool {
+			f.Checked = fv.Bool()
+		} else if f.Type != "password" {
+			f.Value = fmt.Sprint(fv.Interface())
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Render the CSRF token field, and the fields of the struct. See Fields.
+func Render(v any, errs Errors) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		fields, err := Fields(v, errs)
+		if err != nil {
+			return err
+		}
+		if err = CSRFField().Render(ctx, w); err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err = f.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var inputTypes = map[reflect.Kind]string{
+	reflect.String:  "text",
+	reflect.Bool:    "checkbox",
+	reflect.Int:     "number",
+	reflect.Int8:    "number",
+	reflect.Int16:   "number",
+	reflect.Int32:   "number",
+	reflect.Int64:   "number",
+	reflect.Uint:    "number",
+	reflect.Uint8:   "number",
+	reflect.Uint16:  "number",
+	reflect.Uint32:  "number",
+	reflect.Uint64:  "number",
+	reflect.Float32: "number",
+	reflect.Float64: "number",
+}
+
+func parseFormTag(sf reflect.StructField) (name string, required, ok bool) {
+	tag, ok := sf.Tag.Lookup("form")
+	if !ok || tag == "-" || !sf.IsExported() {
+		return "", false, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option == "required" {
+			required = true
+		}
+	}
+	return name, required, true
+}
+
+// Decode the values of the submitted form into the struct that dst points to.
+// Checkboxes that aren't in the form are set to false.
+func Decode(r *http.Request, dst any) (err error) {
+	if err = r.ParseForm(); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("form: expected a pointer to a struct, got %T", dst)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name, _, ok := parseFormTag(rt.Field(i))
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		value := r.Form.Get(name)
+		if fv.Kind() == reflect.Bool {
+			fv.SetBool(value != "" && value != "false" && value != "off")
+			continue
+		}
+		if _, ok := r.Form[name]; !ok {
+			continue
+		}
+		if err = setValue(fv, value); err != nil {
+			return fmt.Errorf("form: invalid value of field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setValue(fv reflect.Value, value string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			fv.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			fv.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			fv.SetFloat(0)
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
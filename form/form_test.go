@@ -0,0 +1,80 @@
+package form
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type signup struct {
+	Email    string `form:"email,required" label:"Email address" type:"email"`
+	Password string `form:"password,required" type:"password"`
+	Age      int    `form:"age"`
+	Terms    bool   `form:"terms" label:"I agree"`
+	Internal string
+}
+
+func TestFields(t *testing.T) {
+	v := signup{Email: "a@example.com", Password: "secret", Age: 42, Terms: true}
+	fields, err := Fields(v, Errors{"email": "Email is already registered"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Field{
+		{Name: "email", Label: "Email address", Type: "email", Value: "a@example.com", Required: true, Error: "Email is already registered"},
+		{Name: "password", Label: "Password", Type: "password", Required: true},
+		{Name: "age", Label: "Age", Type: "number", Value: "42"},
+		{Name: "terms", Label: "I agree", Type: "checkbox", Checked: true},
+	}
+	if diff := cmp.Diff(expected, fields); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRender(t *testing.T) {
+	ctx := WithCSRFToken(context.Background(), "abc")
+	v := struct {
+		Name string `form:"name"`
+	}{Name: `"><script>`}
+
+	w := new(strings.Builder)
+	if err := Render(v, Errors{"name": "Invalid name"}).Render(ctx, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `<input type="hidden" name="csrf_token" value="abc">` +
+		`<div class="field"><label for="name">Name</label> ` +
+		`<input type="text" id="name" name="name" value="&#34;&gt;&lt;script&gt;"> ` +
+		`<p class="error">Invalid name</p></div>`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(url.Values{
+		"email": {"a@example.com"},
+		"age":   {"42"},
+	}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	actual := signup{Terms: true, Internal: "unchanged"}
+	if err := Decode(r, &actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := signup{Email: "a@example.com", Age: 42, Internal: "unchanged"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDecodeInvalidValue(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?age=old", nil)
+	var actual signup
+	if err := Decode(r, &actual); err == nil {
+		t.Error("expected an error")
+	}
+}
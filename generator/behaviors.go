@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// isBehaviorAttribute returns true if the attribute is an event handler that's
+// written as a behavior, instead of an inline event handler.
+func (g *generator) isBehaviorAttribute(name string) bool {
+	return g.behaviors && strings.HasPrefix(strings.ToLower(name), "on")
+}
+
+// behaviorEvent returns the name of the event handled by an event handler
+// attribute, e.g. click for onClick.
+func behaviorEvent(attrName string) string {
+	return strings.ToLower(attrName[len("on"):])
+}
+
+// behaviorAttributes returns the event handler attributes of an element that are
+// written as behaviors, and whether any of them are within conditional attributes.
+func (g *generator) behaviorAttributes(attrs []parser.Attribute) (behaviors []parser.ExpressionAttribute, conditional bool) {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case parser.ExpressionAttribute:
+			if g.isBehaviorAttribute(attr.Name) {
+				behaviors = append(behaviors, attr)
+			}
+		case parser.ConditionalAttribute:
+			then, _ := g.behaviorAttributes(attr.Then)
+			els, _ := g.behaviorAttributes(attr.Else)
+			if len(then) > 0 || len(els) > 0 {
+				behaviors = append(behaviors, then...)
+				behaviors = append(behaviors, els...)
+				conditional = true
+			}
+		}
+	}
+	return behaviors, conditional
+}
+
+// writeBehaviorAttributes writes the single data-templ-on attribute that attaches
+// the scripts of the event handler attributes of an element, including those
+// within conditional attributes, to the element.
+func (g *generator) writeBehaviorAttributes(indentLevel int, attrs []parser.Attribute) (err error) {
+	behaviors, conditional := g.behaviorAttributes(attrs)
+	if len(behaviors) == 0 {
+		return nil
+	}
+	if !conditional {
+		// templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, templ.BehaviorAttributes(
+		if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, templ.BehaviorAttributes("); err != nil {
+			return err
+		}
+		for i, attr := range behaviors {
+			if i > 0 {
+				if _, err = g.w.Write(", "); err != nil {
+					return err
+				}
+			}
+			if err = g.writeBehavior(attr); err != nil {
+				return err
+			}
+		}
+		// ))
+		if _, err = g.w.Write("))\n"); err != nil {
+			return err
+		}
+		return g.writeErrorHandler(indentLevel)
+	}
+	// {
+	if _, err = g.w.WriteIndent(indentLevel, "{\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	// var templ_7745c5c3_Behaviors []templ.Behavior
+	if _, err = g.w.WriteIndent(indentLevel, "var templ_7745c5c3_Behaviors []templ.Behavior\n"); err != nil {
+		return err
+	}
+	if err = g.writeBehaviorAppends(indentLevel, attrs); err != nil {
+		return err
+	}
+	// templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, templ.BehaviorAttributes(templ_7745c5c3_Behaviors...))
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, templ.BehaviorAttributes(templ_7745c5c3_Behaviors...))\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	indentLevel--
+	// }
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
+// writeBehaviorAppends writes the statements that add the behaviors of the
+// attributes to templ_7745c5c3_Behaviors, with the same conditions as the
+// conditional attributes that contain them.
+func (g *generator) writeBehaviorAppends(indentLevel int, attrs []parser.Attribute) (err error) {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case parser.ExpressionAttribute:
+			if !g.isBehaviorAttribute(attr.Name) {
+				continue
+			}
+			// templ_7745c5c3_Behaviors = append(templ_7745c5c3_Behaviors, templ.On("click", script()))
+			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Behaviors = append(templ_7745c5c3_Behaviors, "); err != nil {
+				return err
+			}
+			if err = g.writeBehavior(attr); err != nil {
+				return err
+			}
+			if _, err = g.w.Write(")\n"); err != nil {
+				return err
+			}
+		case parser.ConditionalAttribute:
+			if behaviors, _ := g.behaviorAttributes([]parser.Attribute{attr}); len(behaviors) == 0 {
+				continue
+			}
+			// if x == y {
+			if _, err = g.w.WriteIndent(indentLevel, "if "); err != nil {
+				return err
+			}
+			var r parser.Range
+			if r, err = g.w.Write(attr.Expression.Value); err != nil {
+				return err
+			}
+			g.sourceMap.Add(attr.Expression, r)
+			if _, err = g.w.Write(" {\n"); err != nil {
+				return err
+			}
+			if err = g.writeBehaviorAppends(indentLevel+1, attr.Then); err != nil {
+				return err
+			}
+			if elseBehaviors, _ := g.behaviorAttributes(attr.Else); len(elseBehaviors) > 0 {
+				// } else {
+				if _, err = g.w.WriteIndent(indentLevel, "} else {\n"); err != nil {
+					return err
+				}
+				if err = g.writeBehaviorAppends(indentLevel+1, attr.Else); err != nil {
+					return err
+				}
+			}
+			// }
+			if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeBehavior writes the behavior of an event handler attribute, e.g.
+// templ.On("click", script()).
+func (g *generator) writeBehavior(attr parser.ExpressionAttribute) (err error) {
+	if _, err = g.w.Write("templ.On(" + createGoString(behaviorEvent(attr.Name)) + ", "); err != nil {
+		return err
+	}
+	var r parser.Range
+	if r, err = g.w.Write(attr.Expression.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(attr.Expression, r)
+	_, err = g.w.Write(")")
+	return err
+}
@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"slices"
+	"strconv"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// constantClasses returns the class names of a class attribute expression, if
+// every item of it is constant, e.g. class={ "btn", templ.KV("active", true) }.
+// The class names are then written as a constant attribute, instead of being
+// processed when the template is rendered. templNames are the names that refer
+// to the templ package, see templNames.
+func constantClasses(expr string, templNames []string) (classNames string, ok bool) {
+	// The expression is a list of items, e.g. "a", "b".
+	e, err := goparser.ParseExpr("[]any{" + expr + "}")
+	if err != nil {
+		return "", false
+	}
+	items, ok := constantClassItems(e.(*ast.CompositeLit).Elts, templNames)
+	if !ok {
+		return "", false
+	}
+	return templ.Classes(items...).String(), true
+}
+
+func constantClassItems(exprs []ast.Expr, templNames []string) (items []any, ok bool) {
+	items = make([]any, len(exprs))
+	for i, e := range exprs {
+		if items[i], ok = constantClassItem(e, templNames); !ok {
+			return nil, false
+		}
+	}
+	return items, true
+}
+
+// constantClassItem returns the value of a string literal, or of a call to
+// templ.Classes or templ.KV with constant arguments.
+func constantClassItem(e ast.Expr, templNames []string) (item any, ok bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return nil, false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.CallExpr:
+		if e.Ellipsis.IsValid() {
+			return nil, false
+		}
+		switch templFunctionName(e.Fun, templNames) {
+		case "Classes":
+			items, ok := constantClassItems(e.Args, templNames)
+			if !ok {
+				return nil, false
+			}
+			return templ.Classes(items...), true
+		case "KV":
+			if len(e.Args) != 2 {
+				return nil, false
+			}
+			key, ok := constantClassItem(e.Args[0], templNames)
+			if !ok {
+				return nil, false
+			}
+			value, ok := e.Args[1].(*ast.Ident)
+			if !ok || (value.Name != "true" && value.Name != "false") {
+				return nil, false
+			}
+			return templ.KV(key, value.Name == "true"), true
+		}
+	}
+	return nil, false
+}
+
+// templFunctionName returns the name of a function of the templ package, e.g.
+// "KV" for templ.KV.
+func templFunctionName(e ast.Expr, templNames []string) string {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || !slices.Contains(templNames, pkg.Name) {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// templNames returns the names that refer to the templ package within the
+// template: templ, which the generated code imports it as, and the names of
+// the imports of it in the file, e.g. an alias. Names that the template
+// declares a variable with, e.g. a parameter, don't refer to the package.
+func templNames(tf parser.TemplateFile, t parser.HTMLTemplate) (names []string) {
+	names = []string{"templ"}
+	for _, n := range tf.Nodes {
+		goExpr, ok := n.(parser.TemplateFileGoExpression)
+		if !ok {
+			continue
+		}
+		f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\n"+goExpr.Expression.Value, goparser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range f.Imports {
+			if imp.Name == nil || imp.Path.Value != `"github.com/a-h/templ"` {
+				continue
+			}
+			if name := imp.Name.Name; name != "_" && name != "." {
+				names = append(names, name)
+			}
+		}
+	}
+	// Variables can be declared by the parameters of the template, and the
+	// statements of for loops, and if and switch statements.
+	declarations := []string{t.Expression.Value}
+	parser.Inspect(t, func(n any) bool {
+		switch n := n.(type) {
+		case parser.ForExpression:
+			declarations = append(declarations, n.Expression.Value)
+		case parser.IfExpression:
+			declarations = append(declarations, n.Expression.Value)
+		case parser.ElseIfExpression:
+			declarations = append(declarations, n.Expression.Value)
+		case parser.SwitchExpression:
+			declarations = append(declarations, n.Expression.Value)
+		}
+		return true
+	})
+	return slices.DeleteFunc(names, func(name string) bool {
+		return slices.ContainsFunc(declarations, func(src string) bool {
+			return usesAsVariable(src, name)
+		})
+	})
+}
+
+// usesAsVariable returns true if the Go source contains the identifier, other
+// than as the package of a selector, e.g. templ.KV, so it may declare a
+// variable with the name.
+func usesAsVariable(src, name string) bool {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", fset.Base(), len(src)), []byte(src), nil, 0)
+	var isName bool
+	for {
+		_, tok, lit := s.Scan()
+		if isName && tok != token.PERIOD {
+			return true
+		}
+		if tok == token.EOF {
+			return false
+		}
+		isName = tok == token.IDENT && lit == name
+	}
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConstantClasses(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		expected   string
+		isConstant bool
+	}{
+		{
+			name:       "string literals are constant",
+			input:      `"btn", "btn-primary"`,
+			expected:   "btn btn-primary",
+			isConstant: true,
+		},
+		{
+			name:       "KV pairs with constant values are constant",
+			input:      `"btn", templ.KV("active", true), templ.KV("disabled", false)`,
+			expected:   "btn active",
+			isConstant: true,
+		},
+		{
+			name:       "calls to templ.Classes with constant arguments are constant",
+			input:      "templ.Classes(`a b`, templ.KV(templ.Classes(\"c\", \"a\"), true))",
+			expected:   "a b c",
+			isConstant: true,
+		},
+		{
+			name:  "variables aren't constant",
+			input: `"btn", templ.KV("active", isActive)`,
+		},
+		{
+			name:  "spread arguments aren't constant",
+			input: `templ.Classes(userClasses...)`,
+		},
+		{
+			name:  "calls to other functions aren't constant",
+			input: `"btn", button()`,
+		},
+		{
+			name:  "invalid expressions aren't constant",
+			input: `"btn",,`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := constantClasses(tt.input, []string{"templ"})
+			if ok != tt.isConstant {
+				t.Fatalf("expected constant %v, got %v", tt.isConstant, ok)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
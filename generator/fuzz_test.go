@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func FuzzGenerate(f *testing.F) {
+	files, _ := filepath.Glob("test-*/*.templ")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data))
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		tf, err := parser.ParseString(src)
+		if err != nil {
+			t.Skip()
+			return
+		}
+		// Errors are expected for invalid Go code, but the generator must not panic.
+		_, _, _ = Generate(tf, new(bytes.Buffer))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"html"
 	"io"
+	"math"
 	"path/filepath"
 	"reflect"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 
 	_ "embed"
 
+	"github.com/a-h/parse"
 	"github.com/a-h/templ/parser/v2"
 )
 
@@ -48,6 +50,71 @@ func WithFileName(name string) GenerateOpt {
 	}
 }
 
+// WithInstrumentation wraps each template with templ.Trace, so that rendering
+// can be traced by the templ.Tracer in the render context.
+func WithInstrumentation() GenerateOpt {
+	return func(g *generator) error {
+		g.instrument = true
+		return nil
+	}
+}
+
+// WithStrictHTML validates HTML templates against the HTML spec, and returns an
+// error if invalid nesting, duplicate attributes, or unknown elements or attributes
+// are found.
+func WithStrictHTML() GenerateOpt {
+	return func(g *generator) error {
+		g.strictHTML = true
+		return nil
+	}
+}
+
+// WithPermissiveStringExpressions allows the values of string expressions, e.g.
+// { name }, and of attribute expressions, e.g. title={ name }, to be numbers,
+// booleans, fmt.Stringer and error values, as well as strings. Values of other
+// types compile, but return an error when the template is rendered.
+func WithPermissiveStringExpressions() GenerateOpt {
+	return func(g *generator) error {
+		g.permissiveStrings = true
+		return nil
+	}
+}
+
+// WithMinify removes whitespace that doesn't affect how HTML is rendered, e.g.
+// the whitespace between block elements, and collapses runs of whitespace in
+// text to a single space. Whitespace in <pre> and <textarea> elements, and
+// between inline elements, is kept.
+func WithMinify() GenerateOpt {
+	return func(g *generator) error {
+		g.minify = true
+		return nil
+	}
+}
+
+// WithBehaviors writes script templates in on* event handler attributes as
+// behaviors, attached by a data-templ-on attribute, instead of inline event
+// handlers.
+func WithBehaviors() GenerateOpt {
+	return func(g *generator) error {
+		g.behaviors = true
+		return nil
+	}
+}
+
+// WithStaticChunks writes long static HTML chunks as references to package-level
+// constants collected in chunks, so that chunks shared by the templates of a
+// package are only included in the binary once. The constants are written by
+// chunks.WriteGo.
+func WithStaticChunks(chunks *StaticChunks) GenerateOpt {
+	return func(g *generator) error {
+		chunks.setPackage(g.tf.Package.Expression.Value)
+		g.w.literalWriter = &staticChunkLiteralWriter{
+			chunks: chunks,
+		}
+		return nil
+	}
+}
+
 func WithExtractStrings() GenerateOpt {
 	return func(g *generator) error {
 		g.w.literalWriter = &watchLiteralWriter{
@@ -82,25 +149,74 @@ type generator struct {
 	sourceMap   *parser.SourceMap
 	variableID  int
 	childrenVar string
+	// blocksVar is the variable that contains the blocks that override the
+	// blocks of the template being written, if the template uses blocks.
+	blocksVar string
+	// contentType of the template being written.
+	contentType parser.ContentType
+	// loopIndexVar is the variable that counts the iterations of the for loop
+	// being written, if the loop renders components.
+	loopIndexVar string
+	// templNames are the names that refer to the templ package in the template
+	// being written.
+	templNames []string
 
 	// version of templ.
 	version string
+	// sourceHash is the hash of the templ file.
+	sourceHash string
 	// generatedDate to include as a comment.
 	generatedDate string
 	// fileName to include in error messages if string expressions return an error.
 	fileName string
+	// instrument templates with templ.Trace.
+	instrument bool
+	// strictHTML validates templates against the HTML spec.
+	strictHTML bool
+	// permissiveStrings allows the values of string expressions to be numbers,
+	// booleans, fmt.Stringer and error values, as well as strings.
+	permissiveStrings bool
+	// minify removes insignificant whitespace from the output.
+	minify bool
+	// preformatted is true while writing the contents of a preformatted element, e.g. <pre>.
+	preformatted bool
+	// foreignContent is true while writing the contents of an <svg> or <math>
+	// element, in which elements follow XML rules.
+	foreignContent bool
+	// behaviors writes script templates in event handler attributes as behaviors.
+	behaviors bool
+	// header is a comment written at the top of the file, e.g. a license banner.
+	header string
+	// buildConstraint is a //go:build line written before the package clause.
+	buildConstraint string
+}
+
+// contentTypes maps the content type of non-HTML templates to the Content-Type
+// header returned when the template is rendered by templ.Handler.
+var contentTypes = map[parser.ContentType]string{
+	parser.ContentTypeXML:  "application/xml; charset=utf-8",
+	parser.ContentTypeText: "text/plain; charset=utf-8",
 }
 
 func (g *generator) generate() (err error) {
+	if err = g.validateHTML(); err != nil {
+		return
+	}
 	if err = g.writeCodeGeneratedComment(); err != nil {
 		return
 	}
 	if err = g.writeVersionComment(); err != nil {
 		return
 	}
+	if err = g.writeSourceHashComment(); err != nil {
+		return
+	}
 	if err = g.writeGeneratedDateComment(); err != nil {
 		return
 	}
+	if err = g.writeTemplateFileHeader(); err != nil {
+		return
+	}
 	if err = g.writePackage(); err != nil {
 		return
 	}
@@ -110,11 +226,35 @@ func (g *generator) generate() (err error) {
 	if err = g.writeTemplateNodes(); err != nil {
 		return
 	}
+	if err = g.writeTemplImportUsage(); err != nil {
+		return
+	}
 	return err
 }
 
+// validateHTML returns the HTML validation errors as parser.ParseErrors, so
+// that they're shown with the line of the template, like syntax errors, by
+// parser.FormatError.
+func (g *generator) validateHTML() (err error) {
+	if !g.strictHTML {
+		return nil
+	}
+	var errs parser.ParseErrors
+	for _, d := range parser.ValidateHTML(g.tf) {
+		errs = append(errs, parse.Error(d.Message, parse.Position{
+			Index: int(d.Range.From.Index),
+			Line:  int(d.Range.From.Line),
+			Col:   int(d.Range.From.Col),
+		}))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 func (g *generator) writeCodeGeneratedComment() (err error) {
-	_, err = g.w.Write("// Code generated by templ - DO NOT EDIT.\n\n")
+	_, err = g.w.Write(g.header + "// Code generated by templ - DO NOT EDIT.\n\n" + g.buildConstraint)
 	return err
 }
 
@@ -125,6 +265,13 @@ func (g *generator) writeVersionComment() (err error) {
 	return err
 }
 
+func (g *generator) writeSourceHashComment() (err error) {
+	if g.sourceHash != "" {
+		_, err = g.w.Write("// templ: source: " + g.sourceHash + "\n")
+	}
+	return err
+}
+
 func (g *generator) writeGeneratedDateComment() (err error) {
 	if g.generatedDate != "" {
 		_, err = g.w.Write("// templ: generated: " + g.generatedDate + "\n")
@@ -132,6 +279,28 @@ func (g *generator) writeGeneratedDateComment() (err error) {
 	return err
 }
 
+// writeTemplateFileHeader writes the comments before the package clause of the
+// template file, e.g. a //go:build line that requires the Go version that the
+// templates use.
+func (g *generator) writeTemplateFileHeader() (err error) {
+	if len(g.tf.Header) == 0 {
+		return nil
+	}
+	if g.version != "" || g.sourceHash != "" || g.generatedDate != "" {
+		if _, err = g.w.Write("\n"); err != nil {
+			return err
+		}
+	}
+	for _, h := range g.tf.Header {
+		var r parser.Range
+		if r, err = g.w.Write(h.Expression.Value); err != nil {
+			return err
+		}
+		g.sourceMap.Add(h.Expression, r)
+	}
+	return nil
+}
+
 func (g *generator) writePackage() error {
 	var r parser.Range
 	var err error
@@ -161,6 +330,19 @@ func (g *generator) templateNodeInfo() (hasTemplates bool, hasCSS bool) {
 	return
 }
 
+// writeTemplImportUsage uses the templ import in files that only contain Go
+// code, e.g. constants and helpers that are shared by the templates of the
+// package, so that the generated code compiles.
+func (g *generator) writeTemplImportUsage() (err error) {
+	for _, n := range g.tf.Nodes {
+		if _, ok := n.(parser.TemplateFileGoExpression); !ok {
+			return nil
+		}
+	}
+	_, err = g.w.Write("var _ templ.Component\n")
+	return err
+}
+
 func (g *generator) writeImports() error {
 	var err error
 	// Always import templ because it's the interface type of all templates.
@@ -243,32 +425,37 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "var templ_7745c5c3_CSSBuilder strings.Builder\n"); err != nil {
 			return err
 		}
-		for i := 0; i < len(n.Properties); i++ {
-			switch p := n.Properties[i].(type) {
-			case parser.ConstantCSSProperty:
-				// Constant CSS property values are not sanitized.
-				if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSBuilder.WriteString("+createGoString(p.String(true))+")\n"); err != nil {
-					return err
-				}
-			case parser.ExpressionCSSProperty:
-				// templ_7745c5c3_CSSBuilder.WriteString(templ.SanitizeCSS('name', p.Expression()))
-				if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSBuilder.WriteString(string(templ.SanitizeCSS(`%s`, ", p.Name)); err != nil {
-					return err
-				}
-				if r, err = g.w.Write(p.Value.Expression.Value); err != nil {
-					return err
-				}
-				g.sourceMap.Add(p.Value.Expression, r)
-				if _, err = g.w.Write(")))\n"); err != nil {
-					return err
-				}
-			default:
-				return fmt.Errorf("unknown CSS property type: %v", reflect.TypeOf(p))
+		var declarations, rules []parser.CSSProperty
+		for _, p := range n.Properties {
+			if _, isRule := p.(parser.CSSRule); isRule {
+				rules = append(rules, p)
+				continue
 			}
+			declarations = append(declarations, p)
 		}
-		if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSID := templ.CSSID(`%s`, templ_7745c5c3_CSSBuilder.String())\n", n.Name)); err != nil {
+		if err = g.writeCSSDeclarations(indentLevel, "templ_7745c5c3_CSSBuilder", declarations); err != nil {
 			return err
 		}
+		class := "`.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder.String() + `}`"
+		if len(rules) == 0 {
+			if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSID := templ.CSSID(`%s`, templ_7745c5c3_CSSBuilder.String())\n", n.Name)); err != nil {
+				return err
+			}
+		} else {
+			// Nested rules are written with a placeholder for the class selector, since the
+			// class name is a hash of the CSS.
+			// var templ_7745c5c3_CSSRulesBuilder strings.Builder
+			if _, err = g.w.WriteIndent(indentLevel, "var templ_7745c5c3_CSSRulesBuilder strings.Builder\n"); err != nil {
+				return err
+			}
+			if err = g.writeCSSRules(indentLevel, []string{cssSelectorPlaceholder}, rules); err != nil {
+				return err
+			}
+			if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSID := templ.CSSID(`%s`, templ_7745c5c3_CSSBuilder.String()+templ_7745c5c3_CSSRulesBuilder.String())\n", n.Name)); err != nil {
+				return err
+			}
+			class += " + strings.ReplaceAll(templ_7745c5c3_CSSRulesBuilder.String(), `" + cssSelectorPlaceholder + "`, `.`+templ_7745c5c3_CSSID)"
+		}
 		// return templ.CSS {
 		if _, err = g.w.WriteIndent(indentLevel, "return templ.ComponentCSSClass{\n"); err != nil {
 			return err
@@ -280,7 +467,7 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 				return err
 			}
 			// Class: templ.SafeCSS(".cssID{" + templ.CSSBuilder.String() + "}"),
-			if _, err = g.w.WriteIndent(indentLevel, "Class: templ.SafeCSS(`.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder.String() + `}`),\n"); err != nil {
+			if _, err = g.w.WriteIndent(indentLevel, "Class: templ.SafeCSS("+class+"),\n"); err != nil {
 				return err
 			}
 			indentLevel--
@@ -297,6 +484,109 @@ func (g *generator) writeCSS(n parser.CSSTemplate) error {
 	return nil
 }
 
+// cssSelectorPlaceholder is replaced with the class selector of a CSS component
+// when it's rendered.
+const cssSelectorPlaceholder = "templ_7745c5c3_CSSSelector"
+
+func (g *generator) writeCSSDeclarations(indentLevel int, builder string, properties []parser.CSSProperty) (err error) {
+	var r parser.Range
+	for _, p := range properties {
+		switch p := p.(type) {
+		case parser.ConstantCSSProperty:
+			// Constant CSS property values are not sanitized.
+			if _, err = g.w.WriteIndent(indentLevel, builder+".WriteString("+createGoString(p.String(true))+")\n"); err != nil {
+				return err
+			}
+		case parser.ExpressionCSSProperty:
+			// templ_7745c5c3_CSSBuilder.WriteString(templ.SanitizeCSS('name', p.Expression()))
+			if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("%s.WriteString(string(templ.SanitizeCSS(`%s`, ", builder, p.Name)); err != nil {
+				return err
+			}
+			if r, err = g.w.Write(p.Value.Expression.Value); err != nil {
+				return err
+			}
+			g.sourceMap.Add(p.Value.Expression, r)
+			if _, err = g.w.Write(")))\n"); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown CSS property type: %v", reflect.TypeOf(p))
+		}
+	}
+	return nil
+}
+
+// writeCSSRules writes nested rules as flat CSS rules, where parents are the
+// selectors of the enclosing rule. Media queries and other at-rules wrap the
+// declarations of the enclosing rule.
+func (g *generator) writeCSSRules(indentLevel int, parents []string, rules []parser.CSSProperty) (err error) {
+	for _, p := range rules {
+		rule := p.(parser.CSSRule)
+		var declarations, nested []parser.CSSProperty
+		for _, p := range rule.Properties {
+			if _, isRule := p.(parser.CSSRule); isRule {
+				nested = append(nested, p)
+				continue
+			}
+			declarations = append(declarations, p)
+		}
+		selectors := parents
+		if strings.HasPrefix(rule.Selector, "@") {
+			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSRulesBuilder.WriteString("+createGoString(rule.Selector+"{")+")\n"); err != nil {
+				return err
+			}
+		} else {
+			selectors = resolveCSSSelectors(parents, rule.Selector)
+		}
+		if len(declarations) > 0 {
+			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSRulesBuilder.WriteString("+createGoString(strings.Join(selectors, ",")+"{")+")\n"); err != nil {
+				return err
+			}
+			if err = g.writeCSSDeclarations(indentLevel, "templ_7745c5c3_CSSRulesBuilder", declarations); err != nil {
+				return err
+			}
+			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)\n"); err != nil {
+				return err
+			}
+		}
+		if err = g.writeCSSRules(indentLevel, selectors, nested); err != nil {
+			return err
+		}
+		if strings.HasPrefix(rule.Selector, "@") {
+			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveCSSSelectors combines each of the parent selectors with each selector in the
+// list. The & in a selector is replaced with the parent. Selectors without an & are
+// descendants of the parent.
+func resolveCSSSelectors(parents []string, selectorList string) (resolved []string) {
+	var selectors []string
+	for {
+		end := indexTopLevel(selectorList, ",")
+		if end < 0 {
+			selectors = append(selectors, strings.TrimSpace(selectorList))
+			break
+		}
+		selectors = append(selectors, strings.TrimSpace(selectorList[:end]))
+		selectorList = selectorList[end+1:]
+	}
+	for _, parent := range parents {
+		for _, selector := range selectors {
+			if strings.Contains(selector, "&") {
+				resolved = append(resolved, strings.ReplaceAll(selector, "&", parent))
+				continue
+			}
+			resolved = append(resolved, parent+" "+selector)
+		}
+	}
+	return resolved
+}
+
 func (g *generator) writeGoExpression(n parser.TemplateFileGoExpression) (err error) {
 	r, err := g.w.Write(n.Expression.Value)
 	if err != nil {
@@ -349,7 +639,10 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 	var err error
 	var indentLevel int
 
+	g.templNames = templNames(g.tf, t)
+
 	// func
+	from := g.w.Current.Line
 	if _, err = g.w.Write("func "); err != nil {
 		return err
 	}
@@ -358,13 +651,30 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 		return err
 	}
 	g.sourceMap.Add(t.Expression, r)
+	sourceLinesVar := "templ_7745c5c3_SourceLines_" + shortHash(g.templateName(t))
 	// templ.Component {
 	if _, err = g.w.Write(" templ.Component {\n"); err != nil {
 		return err
 	}
 	indentLevel++
 	// return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
-	if _, err = g.w.WriteIndent(indentLevel, "return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {\n"); err != nil {
+	componentFunc := "templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {\n"
+	closingParens := "})"
+	if g.instrument {
+		// return templ.Trace("pkg.Name", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		componentFunc = "templ.Trace(" + createGoString(g.templateName(t)) + ", " + componentFunc
+		closingParens += ")"
+	}
+	if contentType, ok := contentTypes[t.ContentType]; ok {
+		// return templ.ContentType("application/xml; charset=utf-8", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		componentFunc = "templ.ContentType(" + createGoString(contentType) + ", " + componentFunc
+		closingParens += ")"
+	}
+	g.contentType = t.ContentType
+	defer func() {
+		g.contentType = parser.ContentTypeHTML
+	}()
+	if _, err = g.w.WriteIndent(indentLevel, "return "+componentFunc); err != nil {
 		return err
 	}
 	{
@@ -376,6 +686,10 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.InitializeContext(ctx)\n"); err != nil {
 			return err
 		}
+		// defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, "pkg.Name", &templ_7745c5c3_SourceLines_1a2b3c4d)
+		if _, err = g.w.WriteIndent(indentLevel, "defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, "+createGoString(g.templateName(t))+", &"+sourceLinesVar+")\n"); err != nil {
+			return err
+		}
 		g.childrenVar = g.createVariableName()
 		// templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		// if templ_7745c5c3_Var1 == nil {
@@ -401,51 +715,246 @@ func (g *generator) writeTemplate(nodeIdx int, t parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.ClearChildren(ctx)\n"); err != nil {
 			return err
 		}
-		// Nodes.
-		if err = g.writeNodes(indentLevel, stripWhitespace(t.Children), nil); err != nil {
-			return err
-		}
-		// Return the buffer.
-		if _, err = g.w.WriteIndent(indentLevel, "if !templ_7745c5c3_IsBuffer {\n"); err != nil {
+		if err = g.writeBlocks(indentLevel, t); err != nil {
 			return err
 		}
-		{
-			indentLevel++
-			// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteTo(templ_7745c5c3_W)
-			if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteTo(templ_7745c5c3_W)\n"); err != nil {
+		children := t.Children
+		if t.Style != nil {
+			if children, err = g.writeScopedStyle(indentLevel, t); err != nil {
 				return err
 			}
-			indentLevel--
 		}
-		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		// Nodes.
+		if t.Extends.Value != "" {
+			err = g.writeExtends(indentLevel, t)
+		} else {
+			err = g.writeNodes(indentLevel, stripWhitespace(children), nil)
+		}
+		if err != nil {
 			return err
 		}
-		// return templ_7745c5c3_Err
-		if _, err = g.w.WriteIndent(indentLevel, "return templ_7745c5c3_Err\n"); err != nil {
+		if err = g.writeReturnBuffer(indentLevel); err != nil {
 			return err
 		}
 		indentLevel--
 	}
 	// })
-	if _, err = g.w.WriteIndent(indentLevel, "})\n"); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, closingParens+"\n"); err != nil {
 		return err
 	}
 	indentLevel--
 	// }
 
+	to := g.w.Current.Line
+	if _, err = g.w.WriteIndent(indentLevel, "}\n\n"); err != nil {
+		return err
+	}
+
+	// var templ_7745c5c3_SourceLines_1a2b3c4d = templ.SourceLines{...}
+	if _, err = g.w.WriteIndent(indentLevel, "var "+sourceLinesVar+" = "+g.sourceLines(from, to)+"\n"); err != nil {
+		return err
+	}
+
 	// Note: gofmt wants to remove a single empty line at the end of a file
 	// so we have to make sure we don't output one if this is the last node.
-	closingBrace := "}\n\n"
-	if nodeIdx+1 >= len(g.tf.Nodes) {
-		closingBrace = "}\n"
+	if nodeIdx+1 < len(g.tf.Nodes) {
+		if _, err = g.w.Write("\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBlocks gets the blocks that override the blocks of the template, if the
+// template extends another template, or contains blocks.
+func (g *generator) writeBlocks(indentLevel int, t parser.HTMLTemplate) (err error) {
+	g.blocksVar = ""
+	if !containsNestedBlocks(t) {
+		return nil
+	}
+	g.blocksVar = g.createVariableName()
+	// templ_7745c5c3_Var2 := templ.GetBlocks(ctx)
+	if _, err = g.w.WriteIndent(indentLevel, g.blocksVar+" := templ.GetBlocks(ctx)\n"); err != nil {
+		return err
+	}
+	if t.Extends.Value != "" {
+		// The blocks are passed on to the template that's extended.
+		return nil
+	}
+	// ctx = templ.ClearBlocks(ctx)
+	if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.ClearBlocks(ctx)\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// containsNestedBlocks returns true if the template renders blocks. Templates
+// that extend another template only render the blocks that are nested in the
+// blocks they override.
+func containsNestedBlocks(t parser.HTMLTemplate) bool {
+	if t.Extends.Value == "" {
+		return containsBlocks(t.Children)
+	}
+	for _, n := range t.Children {
+		if b, ok := n.(parser.BlockExpression); ok && containsBlocks(b.Children) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBlocks(nodes []parser.Node) (ok bool) {
+	for _, n := range nodes {
+		parser.Inspect(n, func(n any) bool {
+			if _, isBlock := n.(parser.BlockExpression); isBlock {
+				ok = true
+			}
+			return !ok
+		})
+	}
+	return ok
+}
+
+// writeExtends renders the template that the template extends, with the blocks
+// of the template.
+func (g *generator) writeExtends(indentLevel int, t parser.HTMLTemplate) (err error) {
+	var blocks []string
+	names := map[string]struct{}{}
+	for _, n := range t.Children {
+		b, ok := n.(parser.BlockExpression)
+		if !ok {
+			continue
+		}
+		if _, ok := names[b.Name]; ok {
+			return fmt.Errorf("%s: block %q is overridden more than once", g.templateName(t), b.Name)
+		}
+		names[b.Name] = struct{}{}
+		var name string
+		if name, err = g.writeChildrenComponent(indentLevel, b.Children); err != nil {
+			return err
+		}
+		blocks = append(blocks, createGoString(b.Name)+": "+name)
+	}
+	// ctx = templ.WithBlocks(ctx, map[string]templ.Component{"content": templ_7745c5c3_Var3})
+	if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.WithBlocks(ctx, map[string]templ.Component{"+strings.Join(blocks, ", ")+"})\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
+		return err
+	}
+	var r parser.Range
+	if r, err = g.w.Write(t.Extends.Value); err != nil {
+		return err
 	}
+	g.sourceMap.Add(t.Extends, r)
+	// .Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	return g.writeErrorHandler(indentLevel)
+}
 
-	if _, err = g.w.WriteIndent(indentLevel, closingBrace); err != nil {
+// writeBlockExpression renders the block that overrides the block, or the
+// children of the block if it isn't overridden.
+func (g *generator) writeBlockExpression(indentLevel int, n parser.BlockExpression) (err error) {
+	block := g.createVariableName()
+	// if templ_7745c5c3_Var3 := templ_7745c5c3_Var2["content"]; templ_7745c5c3_Var3 != nil {
+	if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("if %s := %s[%s]; %s != nil {\n", block, g.blocksVar, createGoString(n.Name), block)); err != nil {
+		return err
+	}
+	{
+		indentLevel++
+		if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_Err = %s.Render(ctx, templ_7745c5c3_Buffer)\n", block)); err != nil {
+			return err
+		}
+		if err = g.writeErrorHandler(indentLevel); err != nil {
+			return err
+		}
+		indentLevel--
+	}
+	if children := stripLeadingAndTrailingWhitespace(n.Children); len(children) > 0 {
+		if _, err = g.w.WriteIndent(indentLevel, "} else {\n"); err != nil {
+			return err
+		}
+		if err = g.writeNodes(indentLevel+1, children, nil); err != nil {
+			return err
+		}
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 		return err
 	}
 	return nil
 }
 
+// writeScopedStyle renders the style block of the template, with its selectors
+// scoped to a class that is added to the root elements of the template. The
+// root elements with the class added are returned.
+func (g *generator) writeScopedStyle(indentLevel int, t parser.HTMLTemplate) (children []parser.Node, err error) {
+	if t.ContentType != parser.ContentTypeHTML {
+		return nil, fmt.Errorf("%s: style blocks can only be used in HTML templates", g.templateName(t))
+	}
+	class := scopeClassName(g.templateName(t), t.Style.Contents)
+	css := scopeCSS(t.Style.Contents, "."+class)
+	// templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ.ComponentCSSClass{ID: "templ_scope_1234abcd", Class: templ.SafeCSS(".templ_scope_1234abcd .title{color:red;}")})
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ.ComponentCSSClass{ID: "+createGoString(class)+", Class: templ.SafeCSS("+createGoString(css)+")})\n"); err != nil {
+		return nil, err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return nil, err
+	}
+	return addScopeClass(t.Children, class), nil
+}
+
+// sourceLines returns a templ.SourceLines literal that maps the generated lines
+// of a template to the lines of the template file, so that panics are reported
+// at the line of the template.
+func (g *generator) sourceLines(from, to uint32) string {
+	var lines []string
+	for line := from; line <= to; line++ {
+		cols, ok := g.sourceMap.TargetLinesToSource[line]
+		if !ok {
+			continue
+		}
+		first := parser.Position{Col: math.MaxUint32}
+		for col, pos := range cols {
+			if col < first.Col {
+				first = parser.Position{Line: pos.Line, Col: col}
+			}
+		}
+		// Runtime line numbers start at 1.
+		lines = append(lines, strconv.Itoa(int(line)+1), strconv.Itoa(int(first.Line)+1))
+	}
+	return "templ.SourceLines{FileName: " + createGoString(g.fileName) +
+		", From: " + strconv.Itoa(int(from)+1) + ", To: " + strconv.Itoa(int(to)+1) +
+		", Lines: []int{" + strings.Join(lines, ", ") + "}}"
+}
+
+// shortHash returns a short, stable hash of the string, for use in identifiers.
+func shortHash(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:4])
+}
+
+// templateName returns the package qualified name of the template, e.g. "pkg.Name",
+// or "pkg.Receiver.Name" for templates that are methods.
+func (g *generator) templateName(t parser.HTMLTemplate) string {
+	pkg := strings.TrimSpace(strings.TrimPrefix(g.tf.Package.Expression.Value, "package"))
+	name := strings.TrimSpace(t.Expression.Value)
+	var receiver string
+	if strings.HasPrefix(name, "(") {
+		if end := strings.Index(name, ")"); end > 0 {
+			if fields := strings.Fields(name[1:end]); len(fields) > 0 {
+				receiver = strings.TrimLeft(fields[len(fields)-1], "*") + "."
+			}
+			name = strings.TrimSpace(name[end+1:])
+		}
+	}
+	if end := strings.IndexAny(name, "[("); end > 0 {
+		name = name[:end]
+	}
+	return pkg + "." + receiver + strings.TrimSpace(name)
+}
+
 func stripWhitespace(input []parser.Node) (output []parser.Node) {
 	for i, n := range input {
 		if _, isWhiteSpace := n.(parser.Whitespace); !isWhiteSpace {
@@ -499,6 +1008,8 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 	switch n := current.(type) {
 	case parser.DocType:
 		err = g.writeDocType(indentLevel, n)
+	case parser.XMLDeclaration:
+		err = g.writeXMLDeclaration(indentLevel, n)
 	case parser.Element:
 		err = g.writeElement(indentLevel, n)
 	case parser.HTMLComment:
@@ -509,6 +1020,10 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 		err = g.writeRawElement(indentLevel, n)
 	case parser.ForExpression:
 		err = g.writeForExpression(indentLevel, n, next)
+	case parser.BlockExpression:
+		err = g.writeBlockExpression(indentLevel, n)
+	case parser.BranchStatement:
+		err = g.writeBranchStatement(indentLevel, n)
 	case parser.CallTemplateExpression:
 		err = g.writeCallTemplateExpression(indentLevel, n)
 	case parser.TemplElementExpression:
@@ -521,6 +1036,8 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 		err = g.writeStringExpression(indentLevel, n.Expression)
 	case parser.Whitespace:
 		err = g.writeWhitespace(indentLevel, n)
+	case parser.TrimWhitespace:
+		// The whitespace around the marker is removed by the parser, and the marker isn't rendered.
 	case parser.Text:
 		err = g.writeText(indentLevel, n)
 	case parser.GoComment:
@@ -532,7 +1049,7 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 	// Write trailing whitespace, if there is a next node that might need the space.
 	// If the next node is inline or text, we might need it.
 	// If the current node is a block element, we don't need it.
-	needed := (isInlineOrText(current) && isInlineOrText(next))
+	needed := (g.isInlineOrText(current) && g.isInlineOrText(next))
 	if ws, ok := current.(parser.WhitespaceTrailer); ok && needed {
 		if err := g.writeWhitespaceTrailer(indentLevel, ws.Trailing()); err != nil {
 			return err
@@ -541,7 +1058,7 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 	return
 }
 
-func isInlineOrText(next parser.Node) bool {
+func (g *generator) isInlineOrText(next parser.Node) bool {
 	// While these are formatted as blocks when they're written in the HTML template.
 	// They're inline - i.e. there's no whitespace rendered around them at runtime for minification.
 	if next == nil {
@@ -554,7 +1071,12 @@ func isInlineOrText(next parser.Node) bool {
 		return true
 	case parser.ForExpression:
 		return true
+	case parser.BlockExpression:
+		return true
 	case parser.Element:
+		if g.minify && g.contentType == parser.ContentTypeHTML {
+			return isInlineElement(n)
+		}
 		return !n.IsBlockElement()
 	case parser.Text:
 		return true
@@ -564,16 +1086,35 @@ func isInlineOrText(next parser.Node) bool {
 	return false
 }
 
+// inlineElements are rendered inline by default, so the whitespace around them is significant.
+var inlineElements = map[string]struct{}{
+	"a": {}, "abbr": {}, "acronym": {}, "audio": {}, "b": {}, "bdi": {}, "bdo": {}, "big": {}, "br": {}, "button": {}, "canvas": {},
+	"cite": {}, "code": {}, "data": {}, "del": {}, "dfn": {}, "em": {}, "embed": {}, "i": {}, "iframe": {}, "img": {}, "input": {},
+	"ins": {}, "kbd": {}, "label": {}, "map": {}, "mark": {}, "math": {}, "meter": {}, "object": {}, "output": {}, "picture": {},
+	"progress": {}, "q": {}, "ruby": {}, "s": {}, "samp": {}, "select": {}, "slot": {}, "small": {}, "span": {}, "strike": {},
+	"strong": {}, "sub": {}, "sup": {}, "svg": {}, "textarea": {}, "time": {}, "tt": {}, "u": {}, "var": {}, "video": {}, "wbr": {},
+}
+
+// isInlineElement returns true if the element is inline. The display of custom elements
+// isn't known, so they're treated as inline.
+func isInlineElement(e parser.Element) bool {
+	if _, ok := inlineElements[e.Name]; ok {
+		return true
+	}
+	return strings.Contains(e.Name, "-")
+}
+
 func (g *generator) writeWhitespaceTrailer(indentLevel int, n parser.TrailingSpace) (err error) {
 	if n == parser.SpaceNone {
 		return nil
 	}
 	// Normalize whitespace for minified output. In HTML, a single space is equivalent to
-	// any number of spaces, tabs, or newlines.
-	if n == parser.SpaceVertical {
+	// any number of spaces, tabs, or newlines. In text, line breaks are significant.
+	if n == parser.SpaceVertical && g.contentType != parser.ContentTypeText {
 		n = parser.SpaceHorizontal
 	}
-	if _, err = g.w.WriteStringLiteral(indentLevel, string(n)); err != nil {
+	quoted := strconv.Quote(string(n))
+	if _, err = g.w.WriteStringLiteral(indentLevel, quoted[1:len(quoted)-1]); err != nil {
 		return err
 	}
 	return nil
@@ -586,6 +1127,14 @@ func (g *generator) writeDocType(indentLevel int, n parser.DocType) (err error)
 	return nil
 }
 
+func (g *generator) writeXMLDeclaration(indentLevel int, n parser.XMLDeclaration) (err error) {
+	quoted := strconv.Quote("<?xml " + strings.TrimSpace(n.Value) + "?>")
+	if _, err = g.w.WriteStringLiteral(indentLevel, quoted[1:len(quoted)-1]); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (g *generator) writeIfExpression(indentLevel int, n parser.IfExpression, nextNode parser.Node) (err error) {
 	var r parser.Range
 	// if
@@ -707,58 +1256,103 @@ func (g *generator) writeTemplElementExpression(indentLevel int, n parser.TemplE
 
 func (g *generator) writeBlockTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
 	var r parser.Range
-	childrenName := g.createVariableName()
-	if _, err = g.w.WriteIndent(indentLevel, childrenName+" := templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {\n"); err != nil {
+	if err = g.writeContextErrorHandler(indentLevel); err != nil {
 		return err
 	}
-	indentLevel++
-	if err := g.writeTemplBuffer(indentLevel); err != nil {
+	childrenName, err := g.writeChildrenComponent(indentLevel, n.Children)
+	if err != nil {
 		return err
 	}
-	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), nil); err != nil {
+	if n.Wrap {
+		// templ_7745c5c3_Err = templ.Wrap(
+		if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = templ.Wrap(`); err != nil {
+			return err
+		}
+	} else if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
-	// Return the buffer.
+	if r, err = g.w.Write(n.Expression.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(n.Expression, r)
+	if n.Wrap {
+		// , children).Render(ctx, templ_7745c5c3_Buffer)
+		_, err = g.w.Write(", " + childrenName + ").Render(ctx, templ_7745c5c3_Buffer)\n")
+	} else {
+		// .Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
+		_, err = g.w.Write(".Render(templ.WithChildren(ctx, " + childrenName + "), templ_7745c5c3_Buffer)\n")
+	}
+	if err != nil {
+		return err
+	}
+	if err = g.writeComponentErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeReturnBuffer writes the buffer to the writer, if it was created by the
+// component, and returns.
+func (g *generator) writeReturnBuffer(indentLevel int) (err error) {
 	if _, err = g.w.WriteIndent(indentLevel, "if !templ_7745c5c3_IsBuffer {\n"); err != nil {
 		return err
 	}
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteTo(templ_7745c5c3_W)
+	if _, err = g.w.WriteIndent(indentLevel+1, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteTo(templ_7745c5c3_W)\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		return err
+	}
+	// return templ_7745c5c3_Err
+	_, err = g.w.WriteIndent(indentLevel, "return templ_7745c5c3_Err\n")
+	return err
+}
+
+// writeChildrenComponent writes a variable that contains a component that
+// renders the nodes, and returns the name of the variable.
+func (g *generator) writeChildrenComponent(indentLevel int, nodes []parser.Node) (name string, err error) {
+	name = g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, name+" := templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {\n"); err != nil {
+		return name, err
+	}
+	indentLevel++
+	if err = g.writeTemplBuffer(indentLevel); err != nil {
+		return name, err
+	}
+	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(nodes), nil); err != nil {
+		return name, err
+	}
+	// Return the buffer.
+	if _, err = g.w.WriteIndent(indentLevel, "if !templ_7745c5c3_IsBuffer {\n"); err != nil {
+		return name, err
+	}
 	{
 		indentLevel++
 		// _, templ_7745c5c3_Err = io.Copy(templ_7745c5c3_W, templ_7745c5c3_Buffer)
 		if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = io.Copy(templ_7745c5c3_W, templ_7745c5c3_Buffer)\n"); err != nil {
-			return err
+			return name, err
 		}
 		indentLevel--
 	}
 	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
-		return err
+		return name, err
 	}
 	// return nil
 	if _, err = g.w.WriteIndent(indentLevel, "return templ_7745c5c3_Err\n"); err != nil {
-		return err
+		return name, err
 	}
 	indentLevel--
 	if _, err = g.w.WriteIndent(indentLevel, "})\n"); err != nil {
-		return err
+		return name, err
 	}
-	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
-		return err
-	}
-	if r, err = g.w.Write(n.Expression.Value); err != nil {
-		return err
-	}
-	g.sourceMap.Add(n.Expression, r)
-	// .Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
-	if _, err = g.w.Write(".Render(templ.WithChildren(ctx, " + childrenName + "), templ_7745c5c3_Buffer)\n"); err != nil {
-		return err
-	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
-		return err
-	}
-	return nil
+	return name, nil
 }
 
 func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n parser.TemplElementExpression) (err error) {
+	if err = g.writeContextErrorHandler(indentLevel); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
@@ -772,13 +1366,16 @@ func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n pa
 	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	if err = g.writeComponentErrorHandler(indentLevel); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTemplateExpression) (err error) {
+	if err = g.writeContextErrorHandler(indentLevel); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
@@ -792,7 +1389,7 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTe
 	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	if err = g.writeComponentErrorHandler(indentLevel); err != nil {
 		return err
 	}
 	return nil
@@ -800,6 +1397,25 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n parser.CallTe
 
 func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression, next parser.Node) (err error) {
 	var r parser.Range
+	// Count the iterations, so that errors include the index of the component that failed.
+	loopIndexVar := g.loopIndexVar
+	defer func() {
+		g.loopIndexVar = loopIndexVar
+	}()
+	g.loopIndexVar = ""
+	if rendersComponents(n.Children) {
+		g.loopIndexVar = g.createVariableName()
+		// templ_7745c5c3_Var1 := -1
+		if _, err = g.w.WriteIndent(indentLevel, g.loopIndexVar+" := -1\n"); err != nil {
+			return err
+		}
+	}
+	// outer:
+	if n.Label != "" {
+		if _, err = g.w.WriteIndent(indentLevel, n.Label+":\n"); err != nil {
+			return err
+		}
+	}
 	// for
 	if _, err = g.w.WriteIndent(indentLevel, `for `); err != nil {
 		return err
@@ -815,6 +1431,15 @@ func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression,
 	}
 	// Children.
 	indentLevel++
+	if g.loopIndexVar != "" {
+		// templ_7745c5c3_Var1++
+		if _, err = g.w.WriteIndent(indentLevel, g.loopIndexVar+"++\n"); err != nil {
+			return err
+		}
+	}
+	if err = g.writeContextErrorHandler(indentLevel); err != nil {
+		return err
+	}
 	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), next); err != nil {
 		return err
 	}
@@ -826,6 +1451,69 @@ func (g *generator) writeForExpression(indentLevel int, n parser.ForExpression,
 	return nil
 }
 
+// writeBranchStatement writes a break or continue statement, or returns from
+// the component, writing the buffer first.
+func (g *generator) writeBranchStatement(indentLevel int, n parser.BranchStatement) (err error) {
+	if n.Keyword == "return" {
+		return g.writeReturnBuffer(indentLevel)
+	}
+	// break outer
+	var r parser.Range
+	if r, err = g.w.WriteIndent(indentLevel, n.String()); err != nil {
+		return err
+	}
+	g.sourceMap.Add(parser.Expression{Value: n.String(), Range: n.Range}, r)
+	_, err = g.w.Write("\n")
+	return err
+}
+
+// rendersComponents returns true if the nodes render components, outside of
+// nested for loops.
+func rendersComponents(nodes []parser.Node) bool {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case parser.TemplElementExpression, parser.CallTemplateExpression:
+			return true
+		case parser.ForExpression:
+			continue
+		case parser.CompositeNode:
+			if rendersComponents(n.ChildNodes()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeComponentErrorHandler returns the error of a component. In a for loop,
+// the index of the iteration is added to the error.
+func (g *generator) writeComponentErrorHandler(indentLevel int) (err error) {
+	if g.loopIndexVar == "" {
+		return g.writeErrorHandler(indentLevel)
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err != nil {\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel+1, "return templ.ErrorAtIndex(templ_7745c5c3_Err, "+g.loopIndexVar+")\n"); err != nil {
+		return err
+	}
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
+// writeContextErrorHandler stops rendering if the context is cancelled, e.g.
+// because the client has disconnected, or a deadline has passed.
+func (g *generator) writeContextErrorHandler(indentLevel int) (err error) {
+	if _, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel+1, "return templ_7745c5c3_Err\n"); err != nil {
+		return err
+	}
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
 func (g *generator) writeErrorHandler(indentLevel int) (err error) {
 	_, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err != nil {\n")
 	if err != nil {
@@ -865,12 +1553,60 @@ func (g *generator) writeExpressionErrorHandler(indentLevel int, expression pars
 }
 
 func (g *generator) writeElement(indentLevel int, n parser.Element) (err error) {
-	if n.IsVoidElement() {
+	condition, n, ok, err := g.elementCondition(n)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return g.writeConditionalElement(indentLevel, n, condition)
+	}
+	// XML has no void elements, e.g. <link> is a standard element in RSS feeds.
+	if n.IsVoidElement() && g.contentType != parser.ContentTypeXML {
 		return g.writeVoidElement(indentLevel, n)
 	}
+	// Elements within <svg> or <math> without children are self-closing, e.g.
+	// <circle/>. Elsewhere, the HTML parser ignores the slash.
+	if g.isSelfClosing(n) {
+		return g.writeSelfClosingElement(indentLevel, n)
+	}
 	return g.writeStandardElement(indentLevel, n)
 }
 
+// isSelfClosing returns true if the element is a foreign element without
+// children.
+func (g *generator) isSelfClosing(n parser.Element) bool {
+	return g.isForeignElement(n.Name) && len(stripWhitespace(n.Children)) == 0
+}
+
+// isForeignElement returns true if the element being written is an SVG or
+// MathML element, i.e. an <svg> or <math> element, or an element within one.
+func (g *generator) isForeignElement(name string) bool {
+	return g.foreignContent || parser.Element{Name: name}.StartsForeignContent()
+}
+
+func (g *generator) writeSelfClosingElement(indentLevel int, n parser.Element) (err error) {
+	// <style type="text/css"></style>
+	if err = g.writeElementCSS(indentLevel, n); err != nil {
+		return err
+	}
+	// <script type="text/javascript"></script>
+	if err = g.writeElementScript(indentLevel, n); err != nil {
+		return err
+	}
+	// <circle
+	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s`, html.EscapeString(n.Name))); err != nil {
+		return err
+	}
+	if err = g.writeElementAttributes(indentLevel, n.Name, n.Attributes); err != nil {
+		return err
+	}
+	// />
+	if _, err = g.w.WriteStringLiteral(indentLevel, `/>`); err != nil {
+		return err
+	}
+	return err
+}
+
 func (g *generator) writeVoidElement(indentLevel int, n parser.Element) (err error) {
 	if len(n.Children) > 0 {
 		return fmt.Errorf("writeVoidElement: void element %q must not have child elements", n.Name)
@@ -904,7 +1640,92 @@ func (g *generator) writeVoidElement(indentLevel int, n parser.Element) (err err
 	return err
 }
 
+// elementCondition returns the condition of an element with an if attribute,
+// e.g. <a if={ hasLink } href={ url }>, and the element without the attribute.
+// Only standard HTML elements have conditions, custom elements, SVG and MathML
+// elements, and XML elements may have an if attribute of their own.
+func (g *generator) elementCondition(n parser.Element) (condition parser.Expression, element parser.Element, ok bool, err error) {
+	if !n.IsHTMLElement() || g.isForeignElement(n.Name) || g.contentType == parser.ContentTypeXML {
+		return condition, n, false, nil
+	}
+	element = n
+	element.Attributes = nil
+	for _, attr := range n.Attributes {
+		if attr, isExpr := attr.(parser.ExpressionAttribute); isExpr && attr.Name == "if" {
+			if ok {
+				return condition, n, false, fmt.Errorf("element %q: only one if attribute is allowed", n.Name)
+			}
+			condition, ok = attr.Expression, true
+			continue
+		}
+		element.Attributes = append(element.Attributes, attr)
+	}
+	if !ok {
+		return condition, n, false, nil
+	}
+	return condition, element, true, nil
+}
+
+// writeConditionalElement writes the tags of the element if the condition is
+// true. The children of standard elements are written either way.
+func (g *generator) writeConditionalElement(indentLevel int, n parser.Element, condition parser.Expression) (err error) {
+	var r parser.Range
+	// templ_7745c5c3_Var1 := bool(x == y)
+	// The conversion makes a non-bool condition a compile error at the
+	// expression, instead of at the if statement.
+	conditionVar := g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, conditionVar+" := bool("); err != nil {
+		return err
+	}
+	if r, err = g.w.Write(condition.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(condition, r)
+	if _, err = g.w.Write(")\n"); err != nil {
+		return err
+	}
+	writeIf := func(write func(indentLevel int) error) (err error) {
+		// if templ_7745c5c3_Var1 {
+		if _, err = g.w.WriteIndent(indentLevel, "if "+conditionVar+" {\n"); err != nil {
+			return err
+		}
+		if err = write(indentLevel + 1); err != nil {
+			return err
+		}
+		// }
+		_, err = g.w.WriteIndent(indentLevel, "}\n")
+		return err
+	}
+	// Void and self-closing elements have no children.
+	if (n.IsVoidElement() && g.contentType != parser.ContentTypeXML) || g.isSelfClosing(n) {
+		return writeIf(func(indentLevel int) error {
+			return g.writeElement(indentLevel, n)
+		})
+	}
+	if err = writeIf(func(indentLevel int) error {
+		return g.writeStandardElementOpenTag(indentLevel, n)
+	}); err != nil {
+		return err
+	}
+	if err = g.writeStandardElementChildren(indentLevel, n); err != nil {
+		return err
+	}
+	return writeIf(func(indentLevel int) error {
+		return g.writeStandardElementCloseTag(indentLevel, n)
+	})
+}
+
 func (g *generator) writeStandardElement(indentLevel int, n parser.Element) (err error) {
+	if err = g.writeStandardElementOpenTag(indentLevel, n); err != nil {
+		return err
+	}
+	if err = g.writeStandardElementChildren(indentLevel, n); err != nil {
+		return err
+	}
+	return g.writeStandardElementCloseTag(indentLevel, n)
+}
+
+func (g *generator) writeStandardElementOpenTag(indentLevel int, n parser.Element) (err error) {
 	if len(n.Attributes) == 0 {
 		// <div>
 		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s>`, html.EscapeString(n.Name))); err != nil {
@@ -931,24 +1752,58 @@ func (g *generator) writeStandardElement(indentLevel int, n parser.Element) (err
 			return err
 		}
 	}
-	// Children.
-	if err = g.writeNodes(indentLevel, stripWhitespace(n.Children), nil); err != nil {
-		return err
+	return err
+}
+
+func (g *generator) writeStandardElementChildren(indentLevel int, n parser.Element) (err error) {
+	if n.IsPreformattedElement() {
+		defer func(preformatted bool) { g.preformatted = preformatted }(g.preformatted)
+		g.preformatted = true
 	}
+	defer func(foreignContent bool) { g.foreignContent = foreignContent }(g.foreignContent)
+	g.foreignContent = g.isForeignElement(n.Name) && !n.IsHTMLIntegrationPoint()
+	return g.writeNodes(indentLevel, stripWhitespace(n.Children), nil)
+}
+
+func (g *generator) writeStandardElementCloseTag(indentLevel int, n parser.Element) (err error) {
 	// </div>
-	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`</%s>`, html.EscapeString(n.Name))); err != nil {
-		return err
-	}
+	_, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`</%s>`, html.EscapeString(n.Name)))
 	return err
 }
 
-func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAttribute) (result parser.ExpressionAttribute, ok bool, err error) {
+func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAttribute) (result parser.Attribute, ok bool, err error) {
 	var r parser.Range
 	name := html.EscapeString(attr.Name)
 	if name != "class" {
 		ok = false
 		return
 	}
+	// Constant class names don't need to be processed when the template is rendered.
+	if classNames, isConstant := constantClasses(attr.Expression.Value, g.templNames); isConstant {
+		// The expression is written for the source map, so that the LSP can find
+		// its definitions, but isn't run.
+		if _, err = g.w.WriteIndent(indentLevel, "if false {\n"); err != nil {
+			return
+		}
+		if _, err = g.w.WriteIndent(indentLevel+1, "_ = []any{"); err != nil {
+			return
+		}
+		if r, err = g.w.Write(attr.Expression.Value); err != nil {
+			return
+		}
+		g.sourceMap.Add(attr.Expression, r)
+		if _, err = g.w.Write("}\n"); err != nil {
+			return
+		}
+		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+			return
+		}
+		return parser.ConstantAttribute{
+			Name:      attr.Name,
+			Value:     classNames,
+			NameRange: attr.NameRange,
+		}, true, nil
+	}
 	// Create a class name for the style.
 	// The expression can either be expecting a templ.Classes call, or an expression that returns
 	// var templ_7745c5c3_CSSClassess = []any{
@@ -983,12 +1838,12 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr parser.ExpressionAtt
 func (g *generator) writeAttributesCSS(indentLevel int, attrs []parser.Attribute) (err error) {
 	for i := 0; i < len(attrs); i++ {
 		if attr, ok := attrs[i].(parser.ExpressionAttribute); ok {
-			attr, ok, err = g.writeAttributeCSS(indentLevel, attr)
+			result, ok, err := g.writeAttributeCSS(indentLevel, attr)
 			if err != nil {
 				return err
 			}
 			if ok {
-				attrs[i] = attr
+				attrs[i] = result
 			}
 		}
 		if cattr, ok := attrs[i].(parser.ConditionalAttribute); ok {
@@ -1020,40 +1875,53 @@ func isScriptAttribute(name string) bool {
 }
 
 func (g *generator) writeElementScript(indentLevel int, n parser.Element) (err error) {
-	var scriptExpressions []string
+	var scriptExpressions, behaviorExpressions []string
 	for _, attr := range n.Attributes {
-		scriptExpressions = append(scriptExpressions, getAttributeScripts(attr)...)
-	}
-	if len(scriptExpressions) == 0 {
-		return
+		scripts, behaviors := g.getAttributeScripts(attr)
+		scriptExpressions = append(scriptExpressions, scripts...)
+		behaviorExpressions = append(behaviorExpressions, behaviors...)
 	}
 	// Render the scripts before the element if required.
-	// templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, a, b, c)
-	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, "+strings.Join(scriptExpressions, ", ")+")\n"); err != nil {
-		return err
+	if len(scriptExpressions) > 0 {
+		// templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, a, b, c)
+		if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, "+strings.Join(scriptExpressions, ", ")+")\n"); err != nil {
+			return err
+		}
+		if err = g.writeErrorHandler(indentLevel); err != nil {
+			return err
+		}
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
-		return err
+	if len(behaviorExpressions) > 0 {
+		// templ_7745c5c3_Err = templ.RenderBehaviors(ctx, templ_7745c5c3_Buffer, a, b, c)
+		if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderBehaviors(ctx, templ_7745c5c3_Buffer, "+strings.Join(behaviorExpressions, ", ")+")\n"); err != nil {
+			return err
+		}
+		if err = g.writeErrorHandler(indentLevel); err != nil {
+			return err
+		}
 	}
 	return err
 }
 
-func getAttributeScripts(attr parser.Attribute) (scripts []string) {
+func (g *generator) getAttributeScripts(attr parser.Attribute) (scripts, behaviors []string) {
 	if attr, ok := attr.(parser.ConditionalAttribute); ok {
-		for _, attr := range attr.Then {
-			scripts = append(scripts, getAttributeScripts(attr)...)
-		}
-		for _, attr := range attr.Else {
-			scripts = append(scripts, getAttributeScripts(attr)...)
+		for _, attrs := range [][]parser.Attribute{attr.Then, attr.Else} {
+			for _, attr := range attrs {
+				s, b := g.getAttributeScripts(attr)
+				scripts = append(scripts, s...)
+				behaviors = append(behaviors, b...)
+			}
 		}
 	}
 	if attr, ok := attr.(parser.ExpressionAttribute); ok {
 		name := html.EscapeString(attr.Name)
-		if isScriptAttribute(name) {
+		if g.isBehaviorAttribute(name) {
+			behaviors = append(behaviors, attr.Expression.Value)
+		} else if isScriptAttribute(name) {
 			scripts = append(scripts, attr.Expression.Value)
 		}
 	}
-	return scripts
+	return scripts, behaviors
 }
 
 func (g *generator) writeBoolConstantAttribute(indentLevel int, attr parser.BoolConstantAttribute) (err error) {
@@ -1074,7 +1942,10 @@ func (g *generator) writeConstantAttribute(indentLevel int, attr parser.Constant
 	return nil
 }
 
-func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr parser.BoolExpressionAttribute) (err error) {
+// writeBoolExpressionAttribute writes the attribute if the expression is true.
+// SVG and MathML have no boolean attributes, so the attributes of foreign
+// elements are written with a value of true or false, e.g. focusable="false".
+func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr parser.BoolExpressionAttribute, isForeign bool) (err error) {
 	name := html.EscapeString(attr.Name)
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
@@ -1090,7 +1961,20 @@ func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr parser.Bo
 	if _, err = g.w.Write(` {` + "\n"); err != nil {
 		return err
 	}
-	{
+	if isForeign {
+		// name="true"
+		if _, err = g.w.WriteStringLiteral(indentLevel+1, fmt.Sprintf(` %s=\"true\"`, name)); err != nil {
+			return err
+		}
+		// } else {
+		if _, err = g.w.WriteIndent(indentLevel, `} else {`+"\n"); err != nil {
+			return err
+		}
+		// name="false"
+		if _, err = g.w.WriteStringLiteral(indentLevel+1, fmt.Sprintf(` %s=\"false\"`, name)); err != nil {
+			return err
+		}
+	} else {
 		indentLevel++
 		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(` %s`, name)); err != nil {
 			return err
@@ -1104,6 +1988,14 @@ func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr parser.Bo
 	return nil
 }
 
+// isURLAttribute returns true if the attribute value must be a templ.SafeURL.
+func isURLAttribute(elementName, attrName string) bool {
+	return (elementName == "a" && attrName == "href") ||
+		(elementName == "form" && attrName == "action") ||
+		// SVG links, e.g. <a xlink:href={ url }>, or <use xlink:href={ url }>.
+		attrName == "xlink:href"
+}
+
 func (g *generator) writeExpressionAttribute(indentLevel int, elementName string, attr parser.ExpressionAttribute) (err error) {
 	attrName := html.EscapeString(attr.Name)
 	// Name
@@ -1115,7 +2007,7 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 	if _, err = g.w.WriteStringLiteral(indentLevel, `\"`); err != nil {
 		return err
 	}
-	if (elementName == "a" && attr.Name == "href") || (elementName == "form" && attr.Name == "action") {
+	if isURLAttribute(elementName, attr.Name) {
 		vn := g.createVariableName()
 		// var vn templ.SafeURL =
 		if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" templ.SafeURL = "); err != nil {
@@ -1159,6 +2051,15 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 			if err = g.writeErrorHandler(indentLevel); err != nil {
 				return err
 			}
+			// Close quote.
+			if _, err = g.w.WriteStringLiteral(indentLevel, `\"`); err != nil {
+				return err
+			}
+			// The parameters of the call, if they're passed in a data attribute.
+			if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.ScriptDataArgsAttribute("+vn+"))\n"); err != nil {
+				return err
+			}
+			return g.writeErrorHandler(indentLevel)
 		} else {
 			var r parser.Range
 			vn := g.createVariableName()
@@ -1167,14 +2068,18 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 				return err
 			}
 			// vn, templ_7745c5c3_Err = templ.JoinStringErrs(
-			if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinStringErrs("); err != nil {
+			if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = "+g.joinStringErrs()); err != nil {
 				return err
 			}
 			// p.Name()
 			if r, err = g.w.Write(attr.Expression.Value); err != nil {
 				return err
 			}
-			g.sourceMap.Add(attr.Expression, r)
+			// The expressions of class attributes are replaced by writeAttributeCSS,
+			// and aren't in the source.
+			if attr.Expression.Range != (parser.Range{}) {
+				g.sourceMap.Add(attr.Expression, r)
+			}
 			// )
 			if _, err = g.w.Write(")\n"); err != nil {
 				return err
@@ -1201,6 +2106,45 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 	return nil
 }
 
+func (g *generator) writeKeyExpressionAttribute(indentLevel int, attr parser.KeyExpressionAttribute) (err error) {
+	var key, value string
+	if key, err = g.writeStringVar(indentLevel, attr.Key); err != nil {
+		return err
+	}
+	if value, err = g.writeStringVar(indentLevel, attr.Expression); err != nil {
+		return err
+	}
+	// templ_7745c5c3_Err = templ.RenderAttribute(templ_7745c5c3_Buffer, key, value)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderAttribute(templ_7745c5c3_Buffer, "+key+", "+value+")\n"); err != nil {
+		return err
+	}
+	return g.writeExpressionErrorHandler(indentLevel, attr.Key)
+}
+
+// writeStringVar writes a variable that contains the string value of the
+// expression, and returns the name of the variable.
+func (g *generator) writeStringVar(indentLevel int, expression parser.Expression) (vn string, err error) {
+	vn = g.createVariableName()
+	// var vn string
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
+		return vn, err
+	}
+	// vn, templ_7745c5c3_Err = templ.JoinStringErrs(
+	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = "+g.joinStringErrs()); err != nil {
+		return vn, err
+	}
+	var r parser.Range
+	if r, err = g.w.Write(expression.Value); err != nil {
+		return vn, err
+	}
+	g.sourceMap.Add(expression, r)
+	// )
+	if _, err = g.w.Write(")\n"); err != nil {
+		return vn, err
+	}
+	return vn, g.writeExpressionErrorHandler(indentLevel, expression)
+}
+
 func (g *generator) writeSpreadAttributes(indentLevel int, attr parser.SpreadAttributes) (err error) {
 	// templ.RenderAttributes(ctx, w, spreadAttrs)
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, `); err != nil {
@@ -1239,7 +2183,7 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 	}
 	{
 		indentLevel++
-		if err = g.writeElementAttributes(indentLevel, elementName, attr.Then); err != nil {
+		if err = g.writeAttributes(indentLevel, elementName, attr.Then); err != nil {
 			return err
 		}
 		indentLevel--
@@ -1251,7 +2195,7 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 		}
 		{
 			indentLevel++
-			if err = g.writeElementAttributes(indentLevel, elementName, attr.Else); err != nil {
+			if err = g.writeAttributes(indentLevel, elementName, attr.Else); err != nil {
 				return err
 			}
 			indentLevel--
@@ -1265,16 +2209,29 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 }
 
 func (g *generator) writeElementAttributes(indentLevel int, name string, attrs []parser.Attribute) (err error) {
+	if err = g.writeAttributes(indentLevel, name, attrs); err != nil {
+		return err
+	}
+	return g.writeBehaviorAttributes(indentLevel, attrs)
+}
+
+func (g *generator) writeAttributes(indentLevel int, name string, attrs []parser.Attribute) (err error) {
 	for i := 0; i < len(attrs); i++ {
+		if attr, ok := attrs[i].(parser.ExpressionAttribute); ok && g.isBehaviorAttribute(attr.Name) {
+			// Written together, in a single data-templ-on attribute.
+			continue
+		}
 		switch attr := attrs[i].(type) {
 		case parser.BoolConstantAttribute:
 			err = g.writeBoolConstantAttribute(indentLevel, attr)
 		case parser.ConstantAttribute:
 			err = g.writeConstantAttribute(indentLevel, attr)
 		case parser.BoolExpressionAttribute:
-			err = g.writeBoolExpressionAttribute(indentLevel, attr)
+			err = g.writeBoolExpressionAttribute(indentLevel, attr, g.isForeignElement(name))
 		case parser.ExpressionAttribute:
 			err = g.writeExpressionAttribute(indentLevel, name, attr)
+		case parser.KeyExpressionAttribute:
+			err = g.writeKeyExpressionAttribute(indentLevel, attr)
 		case parser.SpreadAttributes:
 			err = g.writeSpreadAttributes(indentLevel, attr)
 		case parser.ConditionalAttribute:
@@ -1337,6 +2294,15 @@ func (g *generator) createVariableName() string {
 	return "templ_7745c5c3_Var" + strconv.Itoa(g.variableID)
 }
 
+// joinStringErrs returns the start of the call that converts the value of a
+// string expression to a string.
+func (g *generator) joinStringErrs() string {
+	if g.permissiveStrings {
+		return "templ.JoinStringErrs("
+	}
+	return "templ.JoinStringErrs[string]("
+}
+
 func (g *generator) writeStringExpression(indentLevel int, e parser.Expression) (err error) {
 	if strings.TrimSpace(e.Value) == "" {
 		return
@@ -1348,7 +2314,7 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 		return err
 	}
 	// vn, templ_7745c5c3_Err = templ.JoinStringErrs(
-	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinStringErrs("); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = "+g.joinStringErrs()); err != nil {
 		return err
 	}
 	// p.Name()
@@ -1367,8 +2333,13 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 		return err
 	}
 
-	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(vn)
-	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(vn))
+	value := "templ.EscapeString(" + vn + ")"
+	if g.contentType == parser.ContentTypeText {
+		// Text is not escaped, since it's not HTML.
+		value = vn
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("+value+")\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -1389,11 +2360,32 @@ func (g *generator) writeWhitespace(indentLevel int, n parser.Whitespace) (err e
 }
 
 func (g *generator) writeText(indentLevel int, n parser.Text) (err error) {
+	if g.minify && !g.preformatted && g.contentType == parser.ContentTypeHTML {
+		n.Value = collapseWhitespace(n.Value)
+	}
 	quoted := strconv.Quote(n.Value)
 	_, err = g.w.WriteStringLiteral(indentLevel, quoted[1:len(quoted)-1])
 	return err
 }
 
+// collapseWhitespace replaces each run of whitespace with a single space.
+func collapseWhitespace(s string) string {
+	var sb strings.Builder
+	var inWhitespace bool
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !inWhitespace {
+				sb.WriteRune(' ')
+			}
+			inWhitespace = true
+			continue
+		}
+		inWhitespace = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
 func createGoString(s string) string {
 	var sb strings.Builder
 	sb.WriteRune('`')
@@ -1462,6 +2454,10 @@ func (g *generator) writeScript(t parser.ScriptTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "CallInline: templ.SafeScriptInline("+goFn+", "+stripTypes(t.Parameters.Value)+"),\n"); err != nil {
 			return err
 		}
+		// Params: []any{a, b, c},
+		if _, err = g.w.WriteIndent(indentLevel, "Params: []any{"+stripTypes(t.Parameters.Value)+"},\n"); err != nil {
+			return err
+		}
 		indentLevel--
 	}
 	// }
@@ -2,6 +2,14 @@ package generator
 
 import (
 	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ/parser/v2"
@@ -47,3 +55,565 @@ templ h1() {
 		t.Fatalf("failed to write Go expression: %v", err)
 	}
 }
+
+func TestGeneratorSourceMapMultiLineExpressions(t *testing.T) {
+	src := "package main\n\n" +
+		"templ list(a string) {\n" +
+		"\t<p class={ strings.Join([]string{\n" +
+		"\t\t\"a{\",\n" +
+		"\t}, \" \") }>\n" +
+		"\t\t{ fmt.Sprintf(\"%s {x}\",\n" +
+		"\t\t\ta) }\n" +
+		"\t\t{ `raw\n{string}` }\n" +
+		"\t</p>\n" +
+		"\tif strings.HasPrefix(a,\n" +
+		"\t\t\"}\") {\n" +
+		"\t\t<b></b>\n" +
+		"\t}\n" +
+		"}\n"
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, _, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	srcLines, tgtLines := strings.Split(src, "\n"), strings.Split(w.String(), "\n")
+	tests := []struct {
+		name string
+		code string
+	}{
+		{name: "string literals with braces in multi-line attribute expressions", code: `"a{"`},
+		{name: "the last line of multi-line attribute expressions", code: `}, " ")`},
+		{name: "string literals with braces in multi-line string expressions", code: `"%s {x}"`},
+		{name: "the last line of multi-line string expressions", code: "a)"},
+		{name: "raw strings", code: "{string}`"},
+		{name: "string literals with closing braces in if expressions", code: `"}")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := strings.Index(src, tt.code)
+			line := strings.Count(src[:index], "\n")
+			col := index - strings.LastIndex(src[:index], "\n") - 1
+			from, ok := sm.TargetPositionFromSource(uint32(line), uint32(col))
+			if !ok {
+				t.Fatalf("expected a target position for %d:%d", line, col)
+			}
+			if !strings.HasPrefix(tgtLines[from.Line][from.Col:], tt.code) {
+				t.Fatalf("expected %q at the target position, got %q", tt.code, tgtLines[from.Line][from.Col:])
+			}
+			to := parser.NewPosition(0, from.Line, from.Col+uint32(len(tt.code)))
+			r, ok := sm.SourceRangeFromTarget(from, to)
+			if !ok {
+				t.Fatalf("expected a source range for %v-%v", from, to)
+			}
+			actual := srcLines[r.From.Line][r.From.Col:r.To.Col]
+			if r.From.Line != r.To.Line || actual != tt.code {
+				t.Errorf("expected the source range to be %q at %d:%d, got %v", tt.code, line, col, r)
+			}
+		})
+	}
+	t.Run("the generated expressions of class attributes aren't mapped to the source", func(t *testing.T) {
+		to, ok := sm.TargetPositionFromSource(0, 0)
+		if !ok {
+			t.Fatal("expected the package to be mapped")
+		}
+		if !strings.HasPrefix(tgtLines[to.Line][to.Col:], "package main") {
+			t.Errorf("expected the package at the target position, got %q", tgtLines[to.Line][to.Col:])
+		}
+	})
+}
+
+func TestGeneratorInstrumentation(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Hello(name string) {
+	<div>{ name }</div>
+}
+
+templ (p Page) View() {
+	<div></div>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w, WithInstrumentation()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	for _, expected := range []string{
+		"return templ.Trace(`main.Hello`, templ.ComponentFunc(",
+		"return templ.Trace(`main.Page.View`, templ.ComponentFunc(",
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", expected, w.String())
+		}
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Errorf("generated code is not valid Go: %v", err)
+	}
+}
+
+func TestGeneratorStrictHTML(t *testing.T) {
+	src := `package main
+
+templ List() {
+	<p>
+		<ul class="a" class="b"></ul>
+	</p>
+}
+`
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	if _, _, err = Generate(tf, new(bytes.Buffer)); err != nil {
+		t.Fatalf("expected templates to be generated without strict HTML validation, got: %v", err)
+	}
+	_, _, err = Generate(tf, new(bytes.Buffer), WithStrictHTML())
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	expected := `duplicate attribute "class" on <ul>: line 5, col 16
+  5 | 		<ul class="a" class="b"></ul>
+    | 		              ^
+
+invalid nesting: <ul> cannot be placed inside <p>: line 5, col 3
+  5 | 		<ul class="a" class="b"></ul>
+    | 		 ^`
+	if diff := cmp.Diff(expected, parser.FormatError(src, err)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGeneratorPermissiveStringExpressions(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Count(n int, key string) {
+	<div title={ n } { "data-" + key }={ n }>{ n }</div>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if count := strings.Count(w.String(), "templ.JoinStringErrs[string](n)"); count != 3 {
+		t.Errorf("expected the attribute, key attribute and text to be converted with templ.JoinStringErrs[string](n), got %d:\n%s", count, w.String())
+	}
+	w.Reset()
+	if _, _, err = Generate(tf, w, WithPermissiveStringExpressions()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if count := strings.Count(w.String(), "templ.JoinStringErrs(n)"); count != 3 {
+		t.Errorf("expected the attribute, key attribute and text to be converted with templ.JoinStringErrs(n), got %d:\n%s", count, w.String())
+	}
+}
+
+func TestGeneratorMinify(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Form() {
+	<form>
+		<select>
+			<option>A</option>
+			<option>B</option>
+		</select>
+		<p>Some    text</p>
+		<span>C</span> <span>D</span>
+		<pre>  E    F  </pre>
+	</form>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	tests := []struct {
+		name     string
+		opts     []GenerateOpt
+		expected string
+	}{
+		{
+			name:     "without minification",
+			expected: `<form><select><option>A</option> <option>B</option></select><p>Some    text</p><span>C</span> <span>D</span><pre>  E    F  </pre></form>`,
+		},
+		{
+			name:     "with minification",
+			opts:     []GenerateOpt{WithMinify()},
+			expected: `<form><select><option>A</option><option>B</option></select><p>Some text</p><span>C</span> <span>D</span><pre>  E    F  </pre></form>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if _, _, err = Generate(tf, w, tt.opts...); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			if !strings.Contains(w.String(), strconv.Quote(tt.expected)) {
+				t.Errorf("expected generated code to contain %q, got:\n%s", tt.expected, w.String())
+			}
+		})
+	}
+}
+
+func TestGeneratorStaticChunks(t *testing.T) {
+	header := `<header><nav><a href="/">Home</a><a href="/about">About</a><a href="/contact">Contact</a></nav></header>`
+	files := []string{
+		"package main\n\ntempl Home(name string) {\n\t" + header + "\n\t<p>{ name }</p>\n}\n",
+		"package main\n\ntempl About(name string) {\n\t" + header + "\n\t<p>{ name }</p>\n}\n",
+	}
+	chunks := NewStaticChunks()
+	var outputs []string
+	for _, f := range files {
+		tf, err := parser.ParseString(f)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		w := new(bytes.Buffer)
+		if _, _, err = Generate(tf, w, WithStaticChunks(chunks)); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		outputs = append(outputs, w.String())
+	}
+	if chunks.Len() != 1 {
+		t.Fatalf("expected the shared header to be deduplicated into 1 chunk, got %d", chunks.Len())
+	}
+	b := new(bytes.Buffer)
+	if err := chunks.WriteGo(b); err != nil {
+		t.Fatalf("failed to write chunks: %v", err)
+	}
+	name := strings.Fields(strings.SplitN(b.String(), "const (\n", 2)[1])[0]
+	for i, output := range outputs {
+		if !strings.Contains(output, "templ_7745c5c3_Buffer.WriteString("+name+")") {
+			t.Errorf("expected output %d to reference %s, got:\n%s", i, name, output)
+		}
+		if !strings.Contains(output, `WriteString("</p>")`) {
+			t.Errorf("expected short chunks in output %d to be written inline, got:\n%s", i, output)
+		}
+	}
+
+	t.Run("chunks can be read back", func(t *testing.T) {
+		actual := NewStaticChunks()
+		if err := actual.ReadGo(b.Bytes()); err != nil {
+			t.Fatalf("failed to read chunks: %v", err)
+		}
+		actual.setPackage("package main")
+		ab := new(bytes.Buffer)
+		if err := actual.WriteGo(ab); err != nil {
+			t.Fatalf("failed to write chunks: %v", err)
+		}
+		if diff := cmp.Diff(b.String(), ab.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("only the chunks that are referenced are kept", func(t *testing.T) {
+		if diff := cmp.Diff([]string{name}, StaticChunkNames([]byte(outputs[0]))); diff != "" {
+			t.Error(diff)
+		}
+		if n := chunks.Subset(map[string]struct{}{name: {}}).Len(); n != 1 {
+			t.Errorf("expected 1 chunk, got %d", n)
+		}
+		if n := chunks.Subset(nil).Len(); n != 0 {
+			t.Errorf("expected no chunks, got %d", n)
+		}
+	})
+}
+
+func TestGeneratorBehaviors(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Button(name string) {
+	<button onclick={ greet(name) } onMouseOver={ highlight() } hx-on::click={ track() } type="button">Greet</button>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w, WithBehaviors()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	for _, expected := range []string{
+		`templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, track())`,
+		`templ.RenderBehaviors(ctx, templ_7745c5c3_Buffer, greet(name), highlight())`,
+		"templ.BehaviorAttributes(templ.On(`click`, greet(name)), templ.On(`mouseover`, highlight()))",
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", expected, w.String())
+		}
+	}
+	if strings.Contains(w.String(), "onclick=") {
+		t.Errorf("expected no inline event handlers, got:\n%s", w.String())
+	}
+	t.Run("behaviors in conditional attributes are combined into a single attribute", func(t *testing.T) {
+		tf, err := parser.ParseString(`package main
+
+templ Button(active bool) {
+	<button onclick={ greet() } if active { onmouseover={ highlight() } } else { class="inactive" }>Greet</button>
+}
+`)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		w := new(bytes.Buffer)
+		if _, _, err = Generate(tf, w, WithBehaviors()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if n := strings.Count(w.String(), "templ.BehaviorAttributes("); n != 1 {
+			t.Errorf("expected a single data-templ-on attribute, got %d:\n%s", n, w.String())
+		}
+		for _, expected := range []string{
+			"templ_7745c5c3_Behaviors = append(templ_7745c5c3_Behaviors, templ.On(`click`, greet()))",
+			"templ_7745c5c3_Behaviors = append(templ_7745c5c3_Behaviors, templ.On(`mouseover`, highlight()))",
+			"templ.BehaviorAttributes(templ_7745c5c3_Behaviors...)",
+		} {
+			if !strings.Contains(w.String(), expected) {
+				t.Errorf("expected generated code to contain %q, got:\n%s", expected, w.String())
+			}
+		}
+	})
+}
+
+func TestGeneratorHeader(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl Hello() {\n\t<div></div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	tests := []struct {
+		name            string
+		header          string
+		buildConstraint string
+		expected        string
+	}{
+		{
+			name:            "comments and build constraints are written before the package clause",
+			header:          "Copyright 2024 Example Ltd.\n\nSPDX-License-Identifier: MIT\n//nolint:all",
+			buildConstraint: "!js && !wasm",
+			expected:        "// Copyright 2024 Example Ltd.\n//\n// SPDX-License-Identifier: MIT\n//nolint:all\n\n// Code generated by templ - DO NOT EDIT.\n\n//go:build !js && !wasm\n\npackage main\n",
+		},
+		{
+			name:     "block comments are kept",
+			header:   "/*\n  Copyright 2024 Example Ltd.\n*/",
+			expected: "/*\n  Copyright 2024 Example Ltd.\n*/\n\n// Code generated by templ - DO NOT EDIT.\n\npackage main\n",
+		},
+		{
+			name:            "the //go:build prefix is optional",
+			buildConstraint: "//go:build linux",
+			expected:        "// Code generated by templ - DO NOT EDIT.\n\n//go:build linux\n\npackage main\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if _, _, err = Generate(tf, w, WithHeader(tt.header), WithBuildConstraint(tt.buildConstraint)); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()[:len(tt.expected)]); diff != "" {
+				t.Error(diff)
+			}
+			if _, err = format.Source(w.Bytes()); err != nil {
+				t.Errorf("generated code is not valid Go: %v", err)
+			}
+		})
+	}
+	t.Run("invalid build constraints are an error", func(t *testing.T) {
+		if _, _, err = Generate(tf, new(bytes.Buffer), WithBuildConstraint("linux &&")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("static chunk files have the same header", func(t *testing.T) {
+		chunks := NewStaticChunks()
+		if err := chunks.SetHeader("Copyright 2024 Example Ltd.", "!js"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		chunks.setPackage("package main")
+		chunks.add(strings.Repeat("a", minStaticChunkLength))
+		b := new(bytes.Buffer)
+		if err := chunks.WriteGo(b); err != nil {
+			t.Fatalf("failed to write chunks: %v", err)
+		}
+		expected := "// Copyright 2024 Example Ltd.\n\n// Code generated by templ - DO NOT EDIT.\n\n//go:build !js\n\npackage main\n"
+		if !strings.HasPrefix(b.String(), expected) {
+			t.Errorf("expected chunks to start with:\n%s\ngot:\n%s", expected, b.String())
+		}
+	})
+}
+
+func TestGeneratorTemplateFileHeader(t *testing.T) {
+	tf, err := parser.ParseString("//go:build go1.23\n\n// Package main renders a list.\npackage main\n\ntempl Hello() {\n\t<div></div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w, WithVersion("v0.0.0")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	expected := "// Code generated by templ - DO NOT EDIT.\n\n// templ: version: v0.0.0\n\n//go:build go1.23\n\n// Package main renders a list.\npackage main\n"
+	if diff := cmp.Diff(expected, w.String()[:min(len(expected), w.Len())]); diff != "" {
+		t.Error(diff)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Errorf("generated code is not valid Go: %v", err)
+	}
+}
+
+func TestGeneratorConditionalElementMultipleConditions(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Link(url string) {
+	<a if={ url != "" } if={ true } href={ templ.SafeURL(url) }>Link</a>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	_, _, err = Generate(tf, new(bytes.Buffer))
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	expected := `element "a": only one if attribute is allowed`
+	if diff := cmp.Diff(expected, err.Error()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGeneratorConstantClasses(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Button(isActive bool) {
+	<button class={ "btn", templ.KV("active", true) }></button>
+	<button class={ "btn", templ.KV("active", isActive) }></button>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if !strings.Contains(w.String(), `<button class=\"btn active\"></button>`) {
+		t.Errorf("expected the constant classes to be written as a constant attribute, got:\n%s", w.String())
+	}
+	if count := strings.Count(w.String(), "templ.RenderCSSItems("); count != 1 {
+		t.Errorf("expected the classes of only one element to be processed when rendered, got %d", count)
+	}
+}
+
+func TestGeneratorConstantClassesSourceMap(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Button() {
+	<button class={ "btn", templ.KV("active", true) }></button>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	sm, _, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	// The position of templ.KV.
+	tgt, ok := sm.TargetPositionFromSource(3, 24)
+	if !ok {
+		t.Fatalf("expected the class expression to be in the source map")
+	}
+	line := strings.Split(w.String(), "\n")[tgt.Line]
+	if !strings.HasPrefix(line[tgt.Col:], "templ.KV(") {
+		t.Errorf("expected the source map to point at templ.KV, got %q", line)
+	}
+}
+
+func TestGeneratorConstantClassesTemplNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		isConstant bool
+	}{
+		{
+			name: "an alias of the templ import",
+			input: `package main
+
+import t "github.com/a-h/templ"
+
+templ Button() {
+	<button class={ t.KV("active", true) }></button>
+}
+`,
+			isConstant: true,
+		},
+		{
+			name: "a parameter named templ",
+			input: `package main
+
+templ Button(templ Theme) {
+	<button class={ templ.KV("active", true) }></button>
+}
+`,
+		},
+		{
+			name: "a range variable named templ",
+			input: `package main
+
+templ Buttons(themes []Theme) {
+	for _, templ := range themes {
+		<button class={ templ.KV("active", true) }></button>
+	}
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			w := new(bytes.Buffer)
+			if _, _, err = Generate(tf, w); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			isConstant := strings.Contains(w.String(), `<button class=\"active\"></button>`)
+			if isConstant != tt.isConstant {
+				t.Errorf("expected constant %v, got %v:\n%s", tt.isConstant, isConstant, w.String())
+			}
+		})
+	}
+}
+
+func TestGeneratorConditionalElementNonBoolCondition(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+templ Link(url string) {
+	<a if={ url } href={ templ.SafeURL(url) }>Link</a>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "template_templ.go", w.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("failed to parse generated code: %v", err)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("main", fset, []*ast.File{f}, nil)
+	if err == nil {
+		t.Fatal("expected a type error, got nil")
+	}
+	if expected := "cannot convert url"; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected the error to contain %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"fmt"
+	"go/build/constraint"
+	"strings"
+)
+
+// WithHeader adds a comment to the top of the generated code, e.g. a license
+// banner, or linter directives. Lines of the comment that aren't Go comments are
+// commented out.
+func WithHeader(comment string) GenerateOpt {
+	return func(g *generator) error {
+		g.header = formatHeader(comment)
+		return nil
+	}
+}
+
+// WithBuildConstraint adds a //go:build line to the generated code, e.g. "!js",
+// so that the code is only built when the constraint is satisfied.
+func WithBuildConstraint(expr string) GenerateOpt {
+	return func(g *generator) (err error) {
+		g.buildConstraint, err = formatBuildConstraint(expr)
+		return err
+	}
+}
+
+// formatHeader returns the comment as Go comment lines, followed by a blank line.
+func formatHeader(comment string) string {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return ""
+	}
+	var sb strings.Builder
+	var inBlock bool
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			inBlock = !strings.Contains(trimmed, "*/")
+		case strings.HasPrefix(trimmed, "/*"):
+			inBlock = !strings.Contains(trimmed[2:], "*/")
+		case strings.HasPrefix(trimmed, "//"):
+		case trimmed == "":
+			line = "//"
+		default:
+			line = "// " + line
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if inBlock {
+		sb.WriteString("*/\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// formatBuildConstraint returns the //go:build line of the expression, followed
+// by a blank line, or an error if the expression isn't valid.
+func formatBuildConstraint(expr string) (string, error) {
+	expr = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(expr), "//go:build"))
+	if expr == "" {
+		return "", nil
+	}
+	line := "//go:build " + expr
+	if _, err := constraint.Parse(line); err != nil {
+		return "", fmt.Errorf("invalid build constraint %q: %w", expr, err)
+	}
+	return line + "\n\n", nil
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// WithSourceHash includes the hash of the source of the templ file in the generated
+// code, so that generated files that are stale can be found. See SourceHash.
+func WithSourceHash(hash string) GenerateOpt {
+	return func(g *generator) error {
+		g.sourceHash = hash
+		return nil
+	}
+}
+
+// SourceHash returns the hash of the source of a templ file, e.g. sha256:1a2b...
+func SourceHash(src []byte) string {
+	sum := sha256.Sum256(src)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Metadata is the information about how a Go file was generated, which is written
+// in comments at the start of the file, e.g.
+//
+//	// templ: version: v0.2.663
+//	// templ: source: sha256:1a2b...
+type Metadata struct {
+	// Version of templ that generated the file, or empty if it wasn't included.
+	Version string
+	// SourceHash is the hash of the templ file, or empty if it wasn't included.
+	SourceHash string
+}
+
+// ReadMetadata reads the metadata from the comments of generated Go code. The
+// comments are read until the package clause.
+func ReadMetadata(code []byte) (m Metadata) {
+	scanner := bufio.NewScanner(bytes.NewReader(code))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "// templ: "), ": ")
+		if !ok || !strings.HasPrefix(line, "// templ: ") {
+			continue
+		}
+		switch key {
+		case "version":
+			m.Version = value
+		case "source":
+			m.SourceHash = value
+		}
+	}
+	return m
+}
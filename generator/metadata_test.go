@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMetadata(t *testing.T) {
+	src := "package main\n\ntempl a() {\n}\n"
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, _, err = Generate(tf, w, WithVersion("v0.2.1"), WithSourceHash(SourceHash([]byte(src)))); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	expected := Metadata{
+		Version:    "v0.2.1",
+		SourceHash: "sha256:1acfa48c69183a5e17b0c38c86c4344501a63b0c6649f4cf3c12841d6515ddce",
+	}
+	if diff := cmp.Diff(expected, ReadMetadata(w.Bytes())); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestReadMetadata(t *testing.T) {
+	code := "// Code generated by templ - DO NOT EDIT.\n\n// templ: version: v0.2.1\n// templ: source: sha256:abc\n// templ: generated: 2024-01-01T00:00:00Z\npackage main\n\n// templ: version: v0.0.0\n"
+	expected := Metadata{Version: "v0.2.1", SourceHash: "sha256:abc"}
+	if diff := cmp.Diff(expected, ReadMetadata([]byte(code))); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// scopeClassName returns the class that scopes the style block of a template.
+func scopeClassName(templateName, css string) string {
+	hash := sha256.Sum256([]byte(templateName + "\n" + css))
+	return "templ_scope_" + hex.EncodeToString(hash[:4])
+}
+
+// groupingAtRules contain rules, so the selectors of the rules are scoped. The
+// contents of other at-rules, e.g. @keyframes and @font-face, are left unchanged.
+var groupingAtRules = map[string]struct{}{
+	"@media": {}, "@supports": {}, "@container": {}, "@layer": {}, "@document": {},
+}
+
+// scopeCSS prefixes each selector in the CSS with the scope selector, so that
+// only the descendants of an element with the scope class are matched. The
+// :scope pseudo-class matches the element with the scope class.
+func scopeCSS(css, scope string) string {
+	var sb strings.Builder
+	writeScopedRules(&sb, removeCSSComments(css), scope)
+	return sb.String()
+}
+
+func writeScopedRules(sb *strings.Builder, css, scope string) {
+	for {
+		css = strings.TrimSpace(css)
+		if css == "" {
+			return
+		}
+		end := indexTopLevel(css, "{;")
+		if end < 0 {
+			sb.WriteString(css)
+			return
+		}
+		prelude := strings.TrimSpace(css[:end])
+		if css[end] == ';' {
+			// Statement at-rules, e.g. @import.
+			sb.WriteString(prelude + ";")
+			css = css[end+1:]
+			continue
+		}
+		bodyEnd := matchingBrace(css, end)
+		body := css[end+1 : bodyEnd]
+		css = css[min(bodyEnd+1, len(css)):]
+		if strings.HasPrefix(prelude, "@") {
+			sb.WriteString(prelude + "{")
+			if _, isGrouping := groupingAtRules[strings.ToLower(strings.Fields(prelude)[0])]; isGrouping {
+				writeScopedRules(sb, body, scope)
+			} else {
+				sb.WriteString(strings.TrimSpace(body))
+			}
+			sb.WriteString("}")
+			continue
+		}
+		sb.WriteString(scopeSelectors(prelude, scope) + "{" + strings.TrimSpace(body) + "}")
+	}
+}
+
+func scopeSelectors(selectors, scope string) string {
+	var scoped []string
+	for {
+		end := indexTopLevel(selectors, ",")
+		if end < 0 {
+			end = len(selectors)
+		}
+		selector := strings.TrimSpace(selectors[:end])
+		if strings.Contains(selector, ":scope") {
+			scoped = append(scoped, strings.ReplaceAll(selector, ":scope", scope))
+		} else if selector != "" {
+			scoped = append(scoped, scope+" "+selector)
+		}
+		if end == len(selectors) {
+			return strings.Join(scoped, ",")
+		}
+		selectors = selectors[end+1:]
+	}
+}
+
+// indexTopLevel returns the index of the first of the chars that isn't in a
+// string or brackets, or -1.
+func indexTopLevel(s, chars string) int {
+	var depth int
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\'':
+			i = skipCSSString(s, i)
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && strings.IndexByte(chars, c) >= 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// matchingBrace returns the index of the brace that closes the brace at start,
+// or the length of s if it's not closed.
+func matchingBrace(s string, start int) int {
+	var depth int
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			i = skipCSSString(s, i)
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// skipCSSString returns the index of the quote that ends the string at start.
+func skipCSSString(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[start]:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func removeCSSComments(css string) string {
+	var sb strings.Builder
+	for i := 0; i < len(css); i++ {
+		switch {
+		case css[i] == '"' || css[i] == '\'':
+			end := min(skipCSSString(css, i), len(css)-1)
+			sb.WriteString(css[i : end+1])
+			i = end
+		case strings.HasPrefix(css[i:], "/*"):
+			end := strings.Index(css[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			i += end + 3
+		default:
+			sb.WriteByte(css[i])
+		}
+	}
+	return sb.String()
+}
+
+// addScopeClass adds the class to the root elements of the nodes.
+func addScopeClass(nodes []parser.Node, class string) []parser.Node {
+	scoped := make([]parser.Node, len(nodes))
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case parser.Element:
+			n.Attributes = addClassAttribute(n.Attributes, class)
+			scoped[i] = n
+		case parser.IfExpression:
+			n.Then = addScopeClass(n.Then, class)
+			elseIfs := make([]parser.ElseIfExpression, len(n.ElseIfs))
+			for j, elseIf := range n.ElseIfs {
+				elseIf.Then = addScopeClass(elseIf.Then, class)
+				elseIfs[j] = elseIf
+			}
+			n.ElseIfs = elseIfs
+			n.Else = addScopeClass(n.Else, class)
+			scoped[i] = n
+		case parser.SwitchExpression:
+			cases := make([]parser.CaseExpression, len(n.Cases))
+			for j, c := range n.Cases {
+				c.Children = addScopeClass(c.Children, class)
+				cases[j] = c
+			}
+			n.Cases = cases
+			scoped[i] = n
+		case parser.ForExpression:
+			n.Children = addScopeClass(n.Children, class)
+			scoped[i] = n
+		case parser.BlockExpression:
+			n.Children = addScopeClass(n.Children, class)
+			scoped[i] = n
+		default:
+			scoped[i] = n
+		}
+	}
+	return scoped
+}
+
+func addClassAttribute(attrs []parser.Attribute, class string) []parser.Attribute {
+	result := make([]parser.Attribute, len(attrs), len(attrs)+1)
+	copy(result, attrs)
+	for i, attr := range result {
+		switch attr := attr.(type) {
+		case parser.ConstantAttribute:
+			if strings.EqualFold(attr.Name, "class") {
+				attr.Value = strings.TrimSpace(attr.Value + " " + class)
+				result[i] = attr
+				return result
+			}
+		case parser.ExpressionAttribute:
+			if strings.EqualFold(attr.Name, "class") {
+				// Append, so that the source map of the expression is unchanged.
+				attr.Expression.Value += ", " + createGoString(class)
+				result[i] = attr
+				return result
+			}
+		}
+	}
+	return append(result, parser.ConstantAttribute{Name: "class", Value: class})
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScopeCSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "selectors are prefixed with the scope",
+			input:    `h1, .a > .b { color: red; }`,
+			expected: `.s h1,.s .a > .b{color: red;}`,
+		},
+		{
+			name:     ":scope is replaced with the scope",
+			input:    `:scope { margin: 0; } :scope > p:hover { color: blue; }`,
+			expected: `.s{margin: 0;}.s > p:hover{color: blue;}`,
+		},
+		{
+			name:     "commas in functions and attribute selectors are not split",
+			input:    `:is(h1, h2), [data-x="a,b"] { color: red; }`,
+			expected: `.s :is(h1, h2),.s [data-x="a,b"]{color: red;}`,
+		},
+		{
+			name:     "rules in grouping at-rules are scoped",
+			input:    `@media (min-width: 600px) { h1 { color: red; } }`,
+			expected: `@media (min-width: 600px){.s h1{color: red;}}`,
+		},
+		{
+			name:     "other at-rules are unchanged",
+			input:    `@import url("a.css"); @keyframes spin { from { opacity: 0; } to { opacity: 1; } }`,
+			expected: `@import url("a.css");@keyframes spin{from { opacity: 0; } to { opacity: 1; }}`,
+		},
+		{
+			name:     "braces in strings and comments are ignored",
+			input:    `/* { */ a::after { content: "}"; }`,
+			expected: `.s a::after{content: "}";}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, scopeCSS(tt.input, ".s")); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
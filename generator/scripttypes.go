@@ -0,0 +1,405 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// ScriptTypeDefinitions returns TypeScript declarations of the functions of the
+// script templates in the file, and of the props of the islands that it renders,
+// or an empty string if there are none.
+//
+// Struct types are declared as interfaces that describe their JSON encoding, in a
+// namespace with the name of the package, e.g. main.User. The declarations of the
+// types are found in the Go code of the template file, and in the Go files passed
+// in, which are usually the other Go files of the package.
+func ScriptTypeDefinitions(t parser.TemplateFile, goFiles ...*ast.File) (string, error) {
+	st := newScriptTypes(t, goFiles)
+	var sb strings.Builder
+	for _, n := range t.Nodes {
+		script, ok := n.(parser.ScriptTemplate)
+		if !ok {
+			continue
+		}
+		params, err := st.parameterTypes(script.Parameters.Value)
+		if err != nil {
+			return "", fmt.Errorf("script %s: %w", script.Name.Value, err)
+		}
+		sb.WriteString("// " + script.Name.Value + "\n")
+		sb.WriteString("declare function " + functionName(script.Name.Value, script.Value) + "(" + strings.Join(params, ", ") + "): void;\n")
+	}
+	if islands := st.islandPropTypes(t); len(islands) > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		// Interfaces with the same name are merged by TypeScript, so the props of
+		// the islands of all files are available as TemplIslandProps.
+		sb.WriteString("interface TemplIslandProps {\n")
+		for _, island := range islands {
+			sb.WriteString("\t" + propertyName(island[0]) + ": " + island[1] + ";\n")
+		}
+		sb.WriteString("}\n")
+	}
+	if sb.Len() == 0 {
+		return "", nil
+	}
+	st.writeDeclarations(&sb)
+	return "// Code generated by templ - DO NOT EDIT.\n\n" + sb.String(), nil
+}
+
+// scriptTypes converts Go types to TypeScript types, and keeps track of the
+// named types that are used, so that they can be declared.
+type scriptTypes struct {
+	// pkg is the name of the package, used as the namespace of the types.
+	pkg string
+	// decls are the type declarations of the package, by name.
+	decls map[string]*ast.TypeSpec
+	// used are the names of the declared struct types that have been referenced.
+	used map[string]bool
+	// expanding are the names of the other named types that are being converted.
+	expanding map[string]bool
+}
+
+func newScriptTypes(t parser.TemplateFile, goFiles []*ast.File) *scriptTypes {
+	st := &scriptTypes{
+		pkg:       strings.TrimSpace(strings.TrimPrefix(t.Package.Expression.Value, "package")),
+		decls:     map[string]*ast.TypeSpec{},
+		used:      map[string]bool{},
+		expanding: map[string]bool{},
+	}
+	for _, f := range goFiles {
+		st.addDecls(f)
+	}
+	for _, n := range t.Nodes {
+		e, ok := n.(parser.TemplateFileGoExpression)
+		if !ok {
+			continue
+		}
+		// Go code that doesn't parse is reported when the file is generated.
+		f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\n"+e.Expression.Value, goparser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		st.addDecls(f)
+	}
+	return st
+}
+
+func (st *scriptTypes) addDecls(f *ast.File) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.TypeParams == nil {
+				st.decls[ts.Name.Name] = ts
+			}
+		}
+	}
+}
+
+func (st *scriptTypes) parameterTypes(parameters string) (params []string, err error) {
+	expr, err := goparser.ParseExpr("func(" + parameters + ") {}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse parameters: %w", err)
+	}
+	fn, ok := expr.(*ast.FuncLit)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse parameters")
+	}
+	for _, field := range fn.Type.Params.List {
+		typ := st.parameterType(field.Type)
+		for _, name := range field.Names {
+			params = append(params, name.Name+": "+typ)
+		}
+	}
+	return params, nil
+}
+
+// parameterType returns the TypeScript type of a parameter of a script template.
+// Parameters are JSON encoded, except for time.Time values, which are passed as
+// Date objects.
+func (st *scriptTypes) parameterType(expr ast.Expr) string {
+	if isTime(expr) {
+		return "Date"
+	}
+	if t, ok := expr.(*ast.StarExpr); ok && isTime(t.X) {
+		return "Date | null"
+	}
+	return st.typeScriptType(expr)
+}
+
+// islandPropTypes returns the names of the islands rendered by the file, and the
+// TypeScript types of their props, sorted by name. The type of the props is
+// found from the composite literal passed to templ.Island, e.g.
+// @templ.Island("Counter", CounterProps{Count: 1}).
+func (st *scriptTypes) islandPropTypes(t parser.TemplateFile) (islands [][2]string) {
+	types := map[string]string{}
+	parser.Inspect(t, func(n any) bool {
+		tee, ok := n.(parser.TemplElementExpression)
+		if !ok {
+			return true
+		}
+		expr, err := goparser.ParseExpr(tee.Expression.Value)
+		if err != nil {
+			return true
+		}
+		ast.Inspect(expr, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isTemplIsland(call.Fun) || len(call.Args) != 2 {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+			types[name] = st.valueType(call.Args[1])
+			return true
+		})
+		return true
+	})
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		islands = append(islands, [2]string{name, types[name]})
+	}
+	return islands
+}
+
+func isTemplIsland(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Island" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "templ"
+}
+
+// valueType returns the TypeScript type of the JSON encoding of a Go value, if
+// the value is a composite literal, or unknown.
+func (st *scriptTypes) valueType(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return st.valueType(e.X)
+		}
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return st.typeScriptType(e.Type)
+		}
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return "string"
+		case token.INT, token.FLOAT:
+			return "number"
+		}
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return "boolean"
+		}
+		if e.Name == "nil" {
+			return "null"
+		}
+	}
+	return "unknown"
+}
+
+func isTime(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && sel.Sel.Name == "Time"
+}
+
+// typeScriptType returns the TypeScript type of the JSON encoding of a Go value
+// of the type.
+func (st *scriptTypes) typeScriptType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "byte", "rune":
+			return "number"
+		}
+		if spec, ok := st.decls[t.Name]; ok {
+			if _, ok := spec.Type.(*ast.StructType); ok {
+				st.used[t.Name] = true
+				return st.pkg + "." + t.Name
+			}
+			// Other named types are replaced by their underlying type, unless
+			// the type refers to itself.
+			if st.expanding[t.Name] {
+				return "unknown"
+			}
+			st.expanding[t.Name] = true
+			defer delete(st.expanding, t.Name)
+			return st.typeScriptType(spec.Type)
+		}
+	case *ast.SelectorExpr:
+		if isTime(t) {
+			// time.Time values are encoded as RFC 3339 strings.
+			return "string"
+		}
+	case *ast.StarExpr:
+		return st.typeScriptType(t.X) + " | null"
+	case *ast.ArrayType:
+		if elt, ok := t.Elt.(*ast.Ident); ok && elt.Name == "byte" && t.Len == nil {
+			// Byte slices are base64 encoded.
+			return "string"
+		}
+		elt := st.typeScriptType(t.Elt)
+		if strings.Contains(elt, " ") {
+			elt = "(" + elt + ")"
+		}
+		return elt + "[]"
+	case *ast.MapType:
+		return "Record<string, " + st.typeScriptType(t.Value) + ">"
+	case *ast.StructType:
+		fields := st.fields(t, map[string]bool{})
+		if len(fields) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(fields, " ") + " }"
+	}
+	return "unknown"
+}
+
+// fields returns the TypeScript properties of the JSON encoding of the struct,
+// e.g. "name: string;". The fields of embedded structs are included, unless the
+// embedded struct has been seen already.
+func (st *scriptTypes) fields(s *ast.StructType, seen map[string]bool) (fields []string) {
+	for _, field := range s.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		name, opts, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		typ, names := field.Type, field.Names
+		if len(names) == 0 {
+			embedded := typ
+			if star, ok := embedded.(*ast.StarExpr); ok {
+				embedded = star.X
+			}
+			ident, ok := embedded.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if spec, ok := st.decls[ident.Name]; ok && name == "" {
+				if es, ok := spec.Type.(*ast.StructType); ok {
+					if !seen[ident.Name] {
+						seen[ident.Name] = true
+						fields = append(fields, st.fields(es, seen)...)
+					}
+					continue
+				}
+			}
+			if !ast.IsExported(ident.Name) {
+				continue
+			}
+			names = []*ast.Ident{ident}
+		}
+		tsType := st.typeScriptType(typ)
+		if hasOption(opts, "string") && (tsType == "number" || tsType == "boolean") {
+			tsType = "string"
+		}
+		optional := ""
+		if hasOption(opts, "omitempty") || hasOption(opts, "omitzero") {
+			optional = "?"
+		}
+		for _, n := range names {
+			if !ast.IsExported(n.Name) {
+				continue
+			}
+			jsonName := name
+			if jsonName == "" {
+				jsonName = n.Name
+			}
+			fields = append(fields, propertyName(jsonName)+optional+": "+tsType+";")
+		}
+	}
+	return fields
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// propertyName quotes property names that aren't JavaScript identifiers.
+func propertyName(name string) string {
+	for i, r := range name {
+		if r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return strconv.Quote(name)
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// writeDeclarations declares the struct types that have been used, including the
+// types used by their fields, in order of name.
+func (st *scriptTypes) writeDeclarations(sb *strings.Builder) {
+	if len(st.used) == 0 {
+		return
+	}
+	// Converting the fields of a struct marks the types that they use.
+	fields := map[string][]string{}
+	for len(fields) < len(st.used) {
+		for name := range st.used {
+			if _, ok := fields[name]; !ok {
+				fields[name] = st.fields(st.decls[name].Type.(*ast.StructType), map[string]bool{name: true})
+			}
+		}
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sb.WriteString("\ndeclare namespace " + st.pkg + " {\n")
+	for i, name := range names {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\tinterface " + name + " {\n")
+		for _, f := range fields[name] {
+			sb.WriteString("\t\t" + f + "\n")
+		}
+		sb.WriteString("\t}\n")
+	}
+	sb.WriteString("}\n")
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	goparser "go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScriptTypeDefinitions(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+script greet(name string, count int, enabled bool, at time.Time, tags []string, scores map[string]float64, user *User, data []byte) {
+	console.log(name);
+}
+
+templ page() {
+	<div></div>
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	actual, err := ScriptTypeDefinitions(tf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	st := tf.Nodes[0].(parser.ScriptTemplate)
+	expected := `// Code generated by templ - DO NOT EDIT.
+
+// greet
+declare function ` + functionName(st.Name.Value, st.Value) + `(name: string, count: number, enabled: boolean, at: Date, tags: string[], scores: Record<string, number>, user: unknown | null, data: string): void;
+`
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestScriptTypeDefinitionsWithoutScripts(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl page() {\n\t<div></div>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	actual, err := ScriptTypeDefinitions(tf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != "" {
+		t.Errorf("expected no definitions, got %q", actual)
+	}
+}
+
+func TestScriptTypeDefinitionsOfStructs(t *testing.T) {
+	goFile, err := goparser.ParseFile(token.NewFileSet(), "types.go", `package components
+
+type Address struct {
+	Street string `+"`json:\"street\"`"+`
+	Tags   map[string]Tag
+}
+
+type Tag string
+`, 0)
+	if err != nil {
+		t.Fatalf("failed to parse Go file: %v", err)
+	}
+	tf, err := parser.ParseString(`package components
+
+type Base struct {
+	ID int64 ` + "`json:\"id,string\"`" + `
+}
+
+type User struct {
+	Base
+	Name     string    ` + "`json:\"name\"`" + `
+	Email    string    ` + "`json:\"email,omitempty\"`" + `
+	Password string    ` + "`json:\"-\"`" + `
+	Joined   time.Time ` + "`json:\"joined\"`" + `
+	Address  *Address  ` + "`json:\"address\"`" + `
+	Friends  []User    ` + "`json:\"friends\"`" + `
+	Data     []byte    ` + "`json:\"data-url\"`" + `
+	internal string
+}
+
+script greet(user User, at time.Time) {
+	console.log(user.name);
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	actual, err := ScriptTypeDefinitions(tf, goFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	st := tf.Nodes[1].(parser.ScriptTemplate)
+	expected := `// Code generated by templ - DO NOT EDIT.
+
+// greet
+declare function ` + functionName(st.Name.Value, st.Value) + `(user: components.User, at: Date): void;
+
+declare namespace components {
+	interface Address {
+		street: string;
+		Tags: Record<string, string>;
+	}
+
+	interface User {
+		id: string;
+		name: string;
+		email?: string;
+		joined: string;
+		address: components.Address | null;
+		friends: components.User[];
+		"data-url": string;
+	}
+}
+`
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestScriptTypeDefinitionsOfIslands(t *testing.T) {
+	tf, err := parser.ParseString(`package main
+
+type CounterProps struct {
+	Count int ` + "`json:\"count\"`" + `
+}
+
+templ page() {
+	@templ.Island("Counter", CounterProps{Count: 1})
+	@templ.Island("Chart", &ChartProps{}).Module("/chart.js")
+	@templ.Island("Clock", nil)
+}
+`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	actual, err := ScriptTypeDefinitions(tf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `// Code generated by templ - DO NOT EDIT.
+
+interface TemplIslandProps {
+	Chart: unknown;
+	Clock: null;
+	Counter: main.CounterProps;
+}
+
+declare namespace main {
+	interface CounterProps {
+		count: number;
+	}
+}
+`
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// minStaticChunkLength is the minimum length of a static chunk that is moved to a
+// package-level constant. Shorter chunks, e.g. "</div>", compress well and are
+// cheaper to write inline than to reference.
+const minStaticChunkLength = 64
+
+// StaticChunks collects the static HTML chunks of the templates in a package, so
+// that chunks shared between templates, e.g. headers or icons, are written once as
+// package-level constants, instead of once per template.
+//
+// A StaticChunks can be shared by concurrent calls to Generate for the files of
+// a single package.
+type StaticChunks struct {
+	m           sync.Mutex
+	pkg         string
+	nameToValue map[string]string
+	// header and buildConstraint are written at the top of the file.
+	header          string
+	buildConstraint string
+}
+
+// NewStaticChunks creates an empty set of static chunks.
+func NewStaticChunks() *StaticChunks {
+	return &StaticChunks{
+		nameToValue: make(map[string]string),
+	}
+}
+
+const staticChunkNamePrefix = "templ_7745c5c3_Static_"
+
+var staticChunkNamePattern = regexp.MustCompile(staticChunkNamePrefix + "[0-9a-f]{16}")
+
+// StaticChunkNames returns the names of the chunk constants that the generated
+// Go code references.
+func StaticChunkNames(goCode []byte) (names []string) {
+	for _, name := range staticChunkNamePattern.FindAll(goCode, -1) {
+		names = append(names, string(name))
+	}
+	return names
+}
+
+// add the Go string literal contents s, and returns the name of its constant.
+// The name is derived from the contents, so that it is stable across files.
+func (sc *StaticChunks) add(s string) (name string) {
+	hash := sha256.Sum256([]byte(s))
+	name = staticChunkNamePrefix + hex.EncodeToString(hash[:8])
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	sc.nameToValue[name] = s
+	return name
+}
+
+func (sc *StaticChunks) setPackage(pkg string) {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	sc.pkg = pkg
+}
+
+// SetHeader sets the comment and build constraint written at the top of the Go
+// file, as they are by WithHeader and WithBuildConstraint.
+func (sc *StaticChunks) SetHeader(comment, buildConstraint string) (err error) {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	sc.header = formatHeader(comment)
+	sc.buildConstraint, err = formatBuildConstraint(buildConstraint)
+	return err
+}
+
+// Subset returns the chunks with the names, e.g. the names that the generated
+// code of a package references, so that the chunks of templates that have
+// since changed aren't written.
+func (sc *StaticChunks) Subset(names map[string]struct{}) *StaticChunks {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	subset := &StaticChunks{
+		pkg:             sc.pkg,
+		nameToValue:     make(map[string]string),
+		header:          sc.header,
+		buildConstraint: sc.buildConstraint,
+	}
+	for name, value := range sc.nameToValue {
+		if _, ok := names[name]; ok {
+			subset.nameToValue[name] = value
+		}
+	}
+	return subset
+}
+
+// Len returns the number of chunks collected.
+func (sc *StaticChunks) Len() int {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	return len(sc.nameToValue)
+}
+
+// WriteGo writes a Go file that declares the constants of the collected chunks.
+func (sc *StaticChunks) WriteGo(w io.Writer) (err error) {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	names := make([]string, 0, len(sc.nameToValue))
+	for name := range sc.nameToValue {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(sc.header)
+	sb.WriteString("// Code generated by templ - DO NOT EDIT.\n\n")
+	sb.WriteString(sc.buildConstraint)
+	sb.WriteString(sc.pkg + "\n\n")
+	sb.WriteString("const (\n")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\t%s = \"%s\"\n", name, sc.nameToValue[name]))
+	}
+	sb.WriteString(")\n")
+	formatted, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return fmt.Errorf("failed to format static chunks: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// staticChunkLiteralWriter writes string literals that are at least
+// minStaticChunkLength long as references to package-level constants.
+type staticChunkLiteralWriter struct {
+	chunks  *StaticChunks
+	builder strings.Builder
+}
+
+func (w *staticChunkLiteralWriter) writeLiteral(inLiteral bool, s string) string {
+	w.builder.WriteString(s)
+	return ""
+}
+
+func (w *staticChunkLiteralWriter) closeLiteral(indent int) string {
+	s := w.builder.String()
+	w.builder.Reset()
+	if len(s) < minStaticChunkLength {
+		return `_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("` + s + "\")\n"
+	}
+	return "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(" + w.chunks.add(s) + ")\n"
+}
+
+func (w *staticChunkLiteralWriter) literals() string {
+	return ""
+}
+
+// ReadGo adds the chunks declared in a Go file written by WriteGo.
+func (sc *StaticChunks) ReadGo(src []byte) (err error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return fmt.Errorf("failed to parse static chunks: %w", err)
+	}
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok || len(vs.Names) != 1 || len(vs.Values) != 1 {
+				continue
+			}
+			lit, ok := vs.Values[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING || !strings.HasPrefix(lit.Value, `"`) {
+				continue
+			}
+			sc.nameToValue[vs.Names[0].Name] = lit.Value[1 : len(lit.Value)-1]
+		}
+	}
+	return nil
+}
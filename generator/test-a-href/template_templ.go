@@ -17,6 +17,7 @@ func render() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testahref.render`, &templ_7745c5c3_SourceLines_8c8ecf51)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -50,3 +51,5 @@ func render() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_8c8ecf51 = templ.SourceLines{FileName: `generator/test-a-href/template.templ`, From: 12, To: 53, Lines: []int{12, 3, 30, 5, 39, 6}}
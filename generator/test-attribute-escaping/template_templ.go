@@ -17,6 +17,7 @@ func BasicTemplate(url string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testhtml.BasicTemplate`, &templ_7745c5c3_SourceLines_6ee9f89f)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -41,3 +42,5 @@ func BasicTemplate(url string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_6ee9f89f = templ.SourceLines{FileName: `generator/test-attribute-escaping/template.templ`, From: 12, To: 44, Lines: []int{12, 3, 30, 5}}
@@ -0,0 +1,72 @@
+package testbranchstatements
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+func Test(t *testing.T) {
+	tests := []struct {
+		name      string
+		component templ.Component
+		expected  string
+	}{
+		{
+			name:      "continue skips to the next iteration",
+			component: cells([][]string{{"a", "", "b"}, {"c"}}),
+			expected:  `<ul><li>a</li><li>b</li><li>c</li></ul>`,
+		},
+		{
+			name:      "break with a label stops the outer loop",
+			component: cells([][]string{{"a", "stop", "b"}, {"c"}}),
+			expected:  `<ul><li>a</li></ul>`,
+		},
+		{
+			name:      "return stops rendering the template",
+			component: greeting(""),
+			expected:  `<p>Hello.</p>`,
+		},
+		{
+			name:      "the rest of the template is rendered if it doesn't return",
+			component: greeting("Alice"),
+			expected:  `<p>Hello, Alice.</p>`,
+		},
+		{
+			name:      "continue and return in a switch",
+			component: status([]int{200, 404, 201, 500, 202}),
+			expected:  `<p>200</p><p>201</p><p>Error</p>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// Output that's written before returning must be written to writers
+			// that aren't buffers, as well as to buffers.
+			writers := map[string]interface {
+				io.Writer
+				String() string
+			}{
+				"buffer":  new(bytes.Buffer),
+				"builder": new(strings.Builder),
+			}
+			for name, w := range writers {
+				if err := tt.component.Render(context.Background(), w); err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+				diff, err := htmldiff.DiffStrings(tt.expected, w.String())
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if diff != "" {
+					t.Errorf("%s:\n%s", name, diff)
+				}
+			}
+		})
+	}
+}
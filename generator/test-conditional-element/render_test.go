@@ -0,0 +1,65 @@
+package testconditionalelement
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func Test(t *testing.T) {
+	tests := []struct {
+		name      string
+		component templ.Component
+		expected  string
+	}{
+		{
+			name:      "the element is rendered if the condition is true",
+			component: link("Home", "/home"),
+			expected:  `<a href="/home" class="link"><span>Home</span></a>`,
+		},
+		{
+			name:      "only the children are rendered if the condition is false",
+			component: link("Home", ""),
+			expected:  `<span>Home</span>`,
+		},
+		{
+			name:      "void elements are rendered if the condition is true",
+			component: image("/cat.png"),
+			expected:  `<p><img src="/cat.png" alt="Image"> Caption</p>`,
+		},
+		{
+			name:      "void elements aren't rendered if the condition is false",
+			component: image(""),
+			expected:  `<p> Caption</p>`,
+		},
+		{
+			name:      "branch statements in the children apply to the enclosing loop",
+			component: menu([]string{"a", "", "b", "-", "c"}, true),
+			expected:  ` <strong>a</strong> <strong>b</strong> <strong>`,
+		},
+		{
+			name:      "branch statements in the children apply to the enclosing loop if the condition is false",
+			component: menu([]string{"a", "", "b", "-", "c"}, false),
+			expected:  ` a b `,
+		},
+		{
+			name:      "the if attribute of custom elements and SVG elements is rendered",
+			component: customElement("open"),
+			expected:  `<toggle-button if="open">Toggle</toggle-button> <svg><a if="open">Link</a></svg>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.component.Render(context.Background(), &sb); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if actual := sb.String(); actual != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, actual)
+			}
+		})
+	}
+}
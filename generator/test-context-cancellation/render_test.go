@@ -0,0 +1,51 @@
+package testcontextcancellation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func Test(t *testing.T) {
+	var rendered []string
+	item := func(s string, after func()) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			rendered = append(rendered, s)
+			after()
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+
+	t.Run("rendering stops when the context is cancelled", func(t *testing.T) {
+		rendered = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		items := []templ.Component{item("a", func() {}), item("b", cancel), item("c", func() {})}
+		err := list(items).Render(ctx, &strings.Builder{})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if actual := strings.Join(rendered, ""); actual != "ab" {
+			t.Errorf("expected rendering to stop after b, but rendered %q", actual)
+		}
+	})
+	t.Run("nothing is rendered if the context is already cancelled", func(t *testing.T) {
+		rendered = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := list([]templ.Component{item("a", func() {})}).Render(ctx, &strings.Builder{})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if len(rendered) != 0 {
+			t.Errorf("expected nothing to be rendered, but rendered %q", rendered)
+		}
+	})
+}
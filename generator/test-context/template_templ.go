@@ -21,6 +21,7 @@ func render() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testcontext.render`, &templ_7745c5c3_SourceLines_e8e19a8e)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -31,7 +32,7 @@ func render() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(ctx.Value(contextKeyName).(string))
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string](ctx.Value(contextKeyName).(string))
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-context/template.templ`, Line: 9, Col: 42}
 		}
@@ -70,3 +71,5 @@ func render() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_e8e19a8e = templ.SourceLines{FileName: `generator/test-context/template.templ`, From: 16, To: 73, Lines: []int{16, 7, 35, 9, 47, 10, 53, 13, 58, 15}}
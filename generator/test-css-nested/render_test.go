@@ -0,0 +1,29 @@
+package testcssnested
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test(t *testing.T) {
+	component := render()
+
+	w := new(strings.Builder)
+	if err := component.Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	class := button("#0000ff").ClassName()
+	expected := `<style type="text/css">` +
+		`.` + class + `{background-color:#0000ff;}` +
+		`.` + class + `:hover,.` + class + `:focus{background-color:#ff0000;}` +
+		`.` + class + ` > span{font-weight:bold;}` +
+		`@media (min-width: 600px){.` + class + `{padding:1em;}.` + class + `:hover{padding:2em;}}` +
+		`</style>` +
+		`<button class="` + class + `"><span>Click</span></button>`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,81 @@
+// Code generated by templ - DO NOT EDIT.
+
+package testcssnested
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import "context"
+import "io"
+import "bytes"
+import "strings"
+
+func button(color string) templ.CSSClass {
+	var templ_7745c5c3_CSSBuilder strings.Builder
+	templ_7745c5c3_CSSBuilder.WriteString(string(templ.SanitizeCSS(`background-color`, color)))
+	var templ_7745c5c3_CSSRulesBuilder strings.Builder
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`templ_7745c5c3_CSSSelector:hover,templ_7745c5c3_CSSSelector:focus{`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`background-color:#ff0000;`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`templ_7745c5c3_CSSSelector > span{`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`font-weight:bold;`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`@media (min-width: 600px){`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`templ_7745c5c3_CSSSelector{`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`padding:1em;`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`templ_7745c5c3_CSSSelector:hover{`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`padding:2em;`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)
+	templ_7745c5c3_CSSRulesBuilder.WriteString(`}`)
+	templ_7745c5c3_CSSID := templ.CSSID(`button`, templ_7745c5c3_CSSBuilder.String()+templ_7745c5c3_CSSRulesBuilder.String())
+	return templ.ComponentCSSClass{
+		ID:    templ_7745c5c3_CSSID,
+		Class: templ.SafeCSS(`.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder.String() + `}` + strings.ReplaceAll(templ_7745c5c3_CSSRulesBuilder.String(), `templ_7745c5c3_CSSSelector`, `.`+templ_7745c5c3_CSSID)),
+	}
+}
+
+func render() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templ_7745c5c3_W.(*bytes.Buffer)
+		if !templ_7745c5c3_IsBuffer {
+			templ_7745c5c3_Buffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
+		}
+		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testcssnested.render`, &templ_7745c5c3_SourceLines_09ec7e75)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		var templ_7745c5c3_Var2 = []any{button("#0000ff")}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var2...)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<button class=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs[string](templ.CSSClasses(templ_7745c5c3_Var2).String())
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-css-nested/template.templ`, Line: 1, Col: 0}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\"><span>Click</span></button>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if !templ_7745c5c3_IsBuffer {
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteTo(templ_7745c5c3_W)
+		}
+		return templ_7745c5c3_Err
+	})
+}
+
+var templ_7745c5c3_SourceLines_09ec7e75 = templ.SourceLines{FileName: `generator/test-css-nested/template.templ`, From: 38, To: 79, Lines: []int{38, 19, 52, 20}}
@@ -17,6 +17,7 @@ func render(d data) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `elseif.render`, &templ_7745c5c3_SourceLines_00c03775)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -28,7 +29,7 @@ func render(d data) templ.Component {
 		}
 		if d.IsTrue() {
 			var templ_7745c5c3_Var2 string
-			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs("True")
+			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string]("True")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 6, Col: 11}
 			}
@@ -38,7 +39,7 @@ func render(d data) templ.Component {
 			}
 		} else if !d.IsTrue() {
 			var templ_7745c5c3_Var3 string
-			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs("False")
+			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs[string]("False")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 8, Col: 12}
 			}
@@ -48,7 +49,7 @@ func render(d data) templ.Component {
 			}
 		} else {
 			var templ_7745c5c3_Var4 string
-			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs("Else")
+			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs[string]("Else")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 10, Col: 11}
 			}
@@ -63,7 +64,7 @@ func render(d data) templ.Component {
 		}
 		if 1 == 2 {
 			var templ_7745c5c3_Var5 string
-			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs("If")
+			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs[string]("If")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 15, Col: 9}
 			}
@@ -73,7 +74,7 @@ func render(d data) templ.Component {
 			}
 		} else if 1 == 1 {
 			var templ_7745c5c3_Var6 string
-			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs("ElseIf")
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs[string]("ElseIf")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 17, Col: 13}
 			}
@@ -88,7 +89,7 @@ func render(d data) templ.Component {
 		}
 		if 1 == 2 {
 			var templ_7745c5c3_Var7 string
-			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs("If")
+			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs[string]("If")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 22, Col: 9}
 			}
@@ -98,7 +99,7 @@ func render(d data) templ.Component {
 			}
 		} else if 1 == 3 {
 			var templ_7745c5c3_Var8 string
-			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs("ElseIf")
+			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs[string]("ElseIf")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 24, Col: 13}
 			}
@@ -108,7 +109,7 @@ func render(d data) templ.Component {
 			}
 		} else if 1 == 4 {
 			var templ_7745c5c3_Var9 string
-			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs("ElseIf")
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs[string]("ElseIf")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 26, Col: 13}
 			}
@@ -118,7 +119,7 @@ func render(d data) templ.Component {
 			}
 		} else if 1 == 1 {
 			var templ_7745c5c3_Var10 string
-			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs("OK")
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs[string]("OK")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-elseif/template.templ`, Line: 28, Col: 9}
 			}
@@ -137,3 +138,5 @@ func render(d data) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_00c03775 = templ.SourceLines{FileName: `generator/test-elseif/template.templ`, From: 12, To: 140, Lines: []int{12, 3, 30, 5, 32, 6, 40, 7, 42, 8, 52, 10, 65, 14, 67, 15, 75, 16, 77, 17, 90, 21, 92, 22, 100, 23, 102, 24, 110, 25, 112, 26, 120, 27, 122, 28}}
@@ -0,0 +1,117 @@
+//go:build go1.23
+
+package testforiterator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func Test(t *testing.T) {
+	tests := []struct {
+		name      string
+		component templ.Component
+		expected  string
+	}{
+		{
+			name:      "range over iter.Seq",
+			component: values(slices.Values([]string{"a", "b", "c"})),
+			expected:  `<div>a</div><div>b</div><div>c</div>`,
+		},
+		{
+			name:      "range over iter.Seq2",
+			component: pairs(slices.All([]string{"a", "b", "c"})),
+			expected:  `<ul><li>0: a</li><li>2: c</li></ul>`,
+		},
+		{
+			name:      "range over sorted map keys",
+			component: values(slices.Values(slices.Sorted(maps.Keys(map[string]int{"b": 2, "a": 1})))),
+			expected:  `<div>a</div><div>b</div>`,
+		},
+		{
+			name:      "range over int",
+			component: count(3),
+			expected:  `<span>0</span><span>1</span><span>2</span>`,
+		},
+		{
+			name:      "empty sequence",
+			component: values(slices.Values([]string(nil))),
+			expected:  ``,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.component.Render(context.Background(), &sb); err != nil {
+				t.Fatal(err)
+			}
+			if actual := sb.String(); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// sequence returns an iterator over the components, which records the number of
+// components that were yielded. Go panics if the iterator continues after the
+// loop has stopped, so it checks the result of yield.
+func sequence(yielded *int, components ...templ.Component) iter.Seq[templ.Component] {
+	return func(yield func(templ.Component) bool) {
+		for _, c := range components {
+			*yielded++
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+func TestStoppingEarly(t *testing.T) {
+	item := func(s string, after func() error) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, s); err != nil {
+				return err
+			}
+			return after()
+		})
+	}
+	ok := func() error { return nil }
+
+	t.Run("an error stops the iterator", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		var yielded int
+		seq := sequence(&yielded, item("a", ok), item("b", func() error { return errFailed }), item("c", ok))
+
+		err := list(seq).Render(context.Background(), &strings.Builder{})
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("expected the error of the component, got %v", err)
+		}
+		if yielded != 2 {
+			t.Errorf("expected the iterator to stop after 2 components, but it yielded %d", yielded)
+		}
+	})
+	t.Run("cancelling the context stops the iterator", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var yielded int
+		cancelled := func() error { cancel(); return nil }
+		seq := sequence(&yielded, item("a", ok), item("b", cancelled), item("c", ok))
+
+		err := list(seq).Render(ctx, &strings.Builder{})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if yielded != 3 {
+			t.Errorf("expected the iterator to stop at the third component, but it yielded %d", yielded)
+		}
+	})
+}
@@ -17,6 +17,7 @@ func render(content string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testcomment.render`, &templ_7745c5c3_SourceLines_83a6bb8a)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -27,7 +28,7 @@ func render(content string) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(content)
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string](content)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-go-comments/template.templ`, Line: 5, Col: 13}
 		}
@@ -45,3 +46,5 @@ func render(content string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_83a6bb8a = templ.SourceLines{FileName: `generator/test-go-comments/template.templ`, From: 12, To: 48, Lines: []int{12, 3, 31, 5}}
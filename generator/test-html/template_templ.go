@@ -17,6 +17,7 @@ func render(p person) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testhtml.render`, &templ_7745c5c3_SourceLines_a6139d3a)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -27,7 +28,7 @@ func render(p person) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(p.name)
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string](p.name)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-html/template.templ`, Line: 5, Col: 14}
 		}
@@ -40,7 +41,7 @@ func render(p person) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var3 string
-		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(`something with "quotes" and a <tag>`)
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs[string](`something with "quotes" and a <tag>`)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-html/template.templ`, Line: 6, Col: 104}
 		}
@@ -62,7 +63,7 @@ func render(p person) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var5 string
-		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(p.email)
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs[string](p.email)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-html/template.templ`, Line: 7, Col: 67}
 		}
@@ -110,3 +111,5 @@ func render(p person) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_a6139d3a = templ.SourceLines{FileName: `generator/test-html/template.templ`, From: 12, To: 113, Lines: []int{12, 3, 31, 5, 44, 6, 56, 7, 66, 7, 78, 10, 88, 11, 98, 11}}
@@ -17,6 +17,7 @@ func render(d data) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testif.render`, &templ_7745c5c3_SourceLines_76f91d1f)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -24,7 +25,7 @@ func render(d data) templ.Component {
 		ctx = templ.ClearChildren(ctx)
 		if d.IsTrue() {
 			var templ_7745c5c3_Var2 string
-			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs("True")
+			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string]("True")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-if/template.templ`, Line: 5, Col: 10}
 			}
@@ -34,7 +35,7 @@ func render(d data) templ.Component {
 			}
 		} else {
 			var templ_7745c5c3_Var3 string
-			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs("False")
+			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs[string]("False")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-if/template.templ`, Line: 7, Col: 11}
 			}
@@ -49,3 +50,5 @@ func render(d data) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_76f91d1f = templ.SourceLines{FileName: `generator/test-if/template.templ`, From: 12, To: 52, Lines: []int{12, 3, 26, 4, 28, 5, 38, 7}}
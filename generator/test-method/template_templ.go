@@ -21,6 +21,7 @@ func (d Data) Method() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testmethod.Data.Method`, &templ_7745c5c3_SourceLines_c7815452)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -31,7 +32,7 @@ func (d Data) Method() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(d.message)
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string](d.message)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-method/template.templ`, Line: 8, Col: 17}
 		}
@@ -49,3 +50,5 @@ func (d Data) Method() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_c7815452 = templ.SourceLines{FileName: `generator/test-method/template.templ`, From: 16, To: 52, Lines: []int{16, 7, 35, 8}}
@@ -0,0 +1,65 @@
+package testpanic
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test(t *testing.T) {
+	ok := func() string { return "a" }
+	panics := func() string {
+		var items []string
+		return items[1]
+	}
+
+	t.Run("panics are returned as errors with the component path and line of the template", func(t *testing.T) {
+		err := page([]func() string{ok, ok, panics}).Render(context.Background(), &strings.Builder{})
+
+		var re *templ.RenderError
+		if !errors.As(err, &re) {
+			t.Fatalf("expected a *templ.RenderError, got %v", err)
+		}
+		if diff := cmp.Diff("testpanic.page > testpanic.list > testpanic.wrapper[2]", re.ComponentPath()); diff != "" {
+			t.Error(diff)
+		}
+		var pe *templ.PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("expected a *templ.PanicError, got %v", err)
+		}
+		if pe.Line != 13 {
+			t.Errorf("expected the panic to be reported at line 13, got %d", pe.Line)
+		}
+		expectedMessage := "templ: failed to render testpanic.page > testpanic.list > testpanic.wrapper[2]: panic at generator/test-panic/template.templ:13: runtime error: index out of range [1] with length 0"
+		if diff := cmp.Diff(expectedMessage, err.Error()); diff != "" {
+			t.Error(diff)
+		}
+		var rte runtime.Error
+		if !errors.As(err, &rte) {
+			t.Error("expected the runtime error to be unwrapped")
+		}
+	})
+	t.Run("panics can be left unrecovered", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		ctx := templ.WithoutPanicRecovery(context.Background())
+		_ = page([]func() string{panics}).Render(ctx, &strings.Builder{})
+	})
+	t.Run("components that don't panic are unaffected", func(t *testing.T) {
+		var sb strings.Builder
+		if err := page([]func() string{ok}).Render(context.Background(), &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("<main><ul><div><li>a</li></div></ul></main>", sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package testplaintext
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+const expected = `User-agent: *
+Disallow: /admin&private
+Disallow: /<tmp>
+Sitemap: https://example.com/sitemap.xml`
+
+func Test(t *testing.T) {
+	component := robots([]string{"/admin&private", "/<tmp>"}, "https://example.com/sitemap.xml")
+
+	t.Run("text is not escaped, and line breaks are kept", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := component.Render(context.Background(), w); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the handler sets the plain text content type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		templ.Handler(component).ServeHTTP(w, httptest.NewRequest("GET", "/robots.txt", nil))
+		if diff := cmp.Diff("text/plain; charset=utf-8", w.Header().Get("Content-Type")); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package testpreformatted
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const expected = `<div>` +
+	"<pre><code class=\"language-go\">func main() &#123;\n\tfmt.Println(\"Hello\")\n}</code></pre>" +
+	"<textarea name=\"comment\">\n  Line 1\nLine 2\n</textarea>" +
+	"<pre>\n<b>Line 1\nLine 2</b> <i>a</i><i>b</i>  indented\n</pre>" +
+	`<p>Collapsed text</p>` +
+	`</div>`
+
+func Test(t *testing.T) {
+	component := sample("Line 1\nLine 2")
+
+	w := new(strings.Builder)
+	if err := component.Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,29 @@
+package testscopedcss
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const expected = `<style type="text/css">` +
+	`.templ_scope_1ab33acc{border: 1px solid #ccc;}` +
+	`.templ_scope_1ab33acc h1,.templ_scope_1ab33acc .subtitle > span{color: red;}` +
+	`@media (min-width: 600px){.templ_scope_1ab33acc h1{font-size: 2em;}}` +
+	`</style>` +
+	`<div class="card highlighted templ_scope_1ab33acc"><h1>A</h1></div>` +
+	`<div class="card templ_scope_1ab33acc"><h1>B</h1></div>`
+
+func Test(t *testing.T) {
+	component := cards()
+
+	w := new(strings.Builder)
+	if err := component.Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -16,6 +16,7 @@ func withParameters(a string, b string, c int) templ.ComponentScript {
 }`,
 		Call:       templ.SafeScript(`__templ_withParameters_1056`, a, b, c),
 		CallInline: templ.SafeScriptInline(`__templ_withParameters_1056`, a, b, c),
+		Params:     []any{a, b, c},
 	}
 }
 
@@ -26,6 +27,7 @@ func withoutParameters() templ.ComponentScript {
 }`,
 		Call:       templ.SafeScript(`__templ_withoutParameters_6bbf`),
 		CallInline: templ.SafeScriptInline(`__templ_withoutParameters_6bbf`),
+		Params:     []any{},
 	}
 }
 
@@ -37,23 +39,36 @@ func InlineJavascript(a string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testscriptinline.InlineJavascript`, &templ_7745c5c3_SourceLines_8e331ad0)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = withoutParameters().Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = withParameters(a, "test", 123).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = withoutParameters().Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = withParameters(a, "test", 123).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -64,3 +79,5 @@ func InlineJavascript(a string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_8e331ad0 = templ.SourceLines{FileName: `generator/test-script-inline/template.templ`, From: 34, To: 81, Lines: []int{34, 11, 51, 12, 58, 13, 65, 15, 72, 16}}
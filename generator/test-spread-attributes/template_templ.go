@@ -17,6 +17,7 @@ func BasicTemplate(spread templ.Attributes) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testspreadattributes.BasicTemplate`, &templ_7745c5c3_SourceLines_1793ed02)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -60,3 +61,5 @@ func BasicTemplate(spread templ.Attributes) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_1793ed02 = templ.SourceLines{FileName: `generator/test-spread-attributes/template.templ`, From: 12, To: 63, Lines: []int{12, 3, 30, 5, 38, 7, 39, 8, 48, 12, 49, 13}}
@@ -0,0 +1,24 @@
+package teststringconversions
+
+import (
+	_ "embed"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render(3, 9.99, true, 48*time.Hour)
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
@@ -28,6 +28,7 @@ func TestComponent(err error) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `teststringerrs.TestComponent`, &templ_7745c5c3_SourceLines_7a1e6d65)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -38,7 +39,7 @@ func TestComponent(err error) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs("raw")
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string]("raw")
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-string-errors/template.templ`, Line: 16, Col: 13}
 		}
@@ -51,7 +52,7 @@ func TestComponent(err error) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var3 string
-		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(funcWithNoError())
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs[string](funcWithNoError())
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-string-errors/template.templ`, Line: 17, Col: 25}
 		}
@@ -64,7 +65,7 @@ func TestComponent(err error) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var4 string
-		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(funcWithError(err))
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs[string](funcWithError(err))
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-string-errors/template.templ`, Line: 18, Col: 26}
 		}
@@ -82,3 +83,5 @@ func TestComponent(err error) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_7a1e6d65 = templ.SourceLines{FileName: `generator/test-string-errors/template.templ`, From: 23, To: 85, Lines: []int{23, 14, 42, 16, 55, 17, 68, 18}}
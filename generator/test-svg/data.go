@@ -0,0 +1,7 @@
+package testsvg
+
+import "strconv"
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,33 @@
+package testsvg
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Attribute names keep their case, e.g. viewBox. Empty elements are only
+// self-closing within <svg>, and not within <foreignObject>, which contains HTML.
+const expected = `<svg viewBox="0 0 100 100" preserveAspectRatio="xMidYMid meet" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" focusable="false">` +
+	`<defs><linearGradient id="fill" gradientUnits="userSpaceOnUse"><stop offset="0" stop-color="red"/></linearGradient></defs> ` +
+	`<rect x="0" y="0" width="8" height="20" fill="url(#fill)"/> ` +
+	`<rect x="10" y="0" width="8" height="40" fill="url(#fill)"/> ` +
+	`<a xlink:href="about:invalid#TemplFailedSanitizationURL"><text x="0" y="100">Chart</text></a> ` +
+	`<foreignObject width="100" height="10"><span></span></foreignObject>` +
+	`</svg> ` +
+	`<select><option hidden></option></select> ` +
+	`<text></text> <g></g>`
+
+func Test(t *testing.T) {
+	component := chart([]int{20, 40}, "javascript:alert(1)")
+
+	w := new(strings.Builder)
+	if err := component.Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -17,6 +17,7 @@ func render(input string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testswitch.render`, &templ_7745c5c3_SourceLines_5b38b97b)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -25,7 +26,7 @@ func render(input string) templ.Component {
 		switch input {
 		case "a":
 			var templ_7745c5c3_Var2 string
-			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs("it was 'a'")
+			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs[string]("it was 'a'")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-switch/template.templ`, Line: 6, Col: 17}
 			}
@@ -35,7 +36,7 @@ func render(input string) templ.Component {
 			}
 		default:
 			var templ_7745c5c3_Var3 string
-			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs("it was something else")
+			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs[string]("it was something else")
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-switch/template.templ`, Line: 8, Col: 28}
 			}
@@ -50,3 +51,5 @@ func render(input string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_5b38b97b = templ.SourceLines{FileName: `generator/test-switch/template.templ`, From: 12, To: 51, Lines: []int{12, 3, 26, 4, 27, 5, 28, 6, 36, 7, 37, 8}}
@@ -27,6 +27,7 @@ func greeting() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testgotemplates.greeting`, &templ_7745c5c3_SourceLines_e6cc4439)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -42,3 +43,5 @@ func greeting() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_e6cc4439 = templ.SourceLines{FileName: `generator/test-templ-in-go-template/template.templ`, From: 22, To: 45, Lines: []int{22, 13}}
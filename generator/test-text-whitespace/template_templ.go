@@ -17,6 +17,7 @@ func WhitespaceIsAddedWithinTemplStatements() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testtextwhitespace.WhitespaceIsAddedWithinTemplStatements`, &templ_7745c5c3_SourceLines_35090531)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -43,6 +44,8 @@ func WhitespaceIsAddedWithinTemplStatements() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_35090531 = templ.SourceLines{FileName: `generator/test-text-whitespace/template.templ`, From: 12, To: 45, Lines: []int{12, 3, 30, 6}}
+
 const WhitespaceIsAddedWithinTemplStatementsExpected = `<p>This is some text. So is this.</p>`
 
 func InlineElementsAreNotPadded() templ.Component {
@@ -53,6 +56,7 @@ func InlineElementsAreNotPadded() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testtextwhitespace.InlineElementsAreNotPadded`, &templ_7745c5c3_SourceLines_ce5b95e2)
 		templ_7745c5c3_Var2 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var2 == nil {
 			templ_7745c5c3_Var2 = templ.NopComponent
@@ -69,6 +73,8 @@ func InlineElementsAreNotPadded() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_ce5b95e2 = templ.SourceLines{FileName: `generator/test-text-whitespace/template.templ`, From: 51, To: 74, Lines: []int{51, 14}}
+
 const InlineElementsAreNotPaddedExpected = `<p>Inline text <b>is spaced properly</b> without adding extra spaces.</p>`
 
 func WhiteSpaceInHTMLIsNormalised() templ.Component {
@@ -79,6 +85,7 @@ func WhiteSpaceInHTMLIsNormalised() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testtextwhitespace.WhiteSpaceInHTMLIsNormalised`, &templ_7745c5c3_SourceLines_4ad12970)
 		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var3 == nil {
 			templ_7745c5c3_Var3 = templ.NopComponent
@@ -95,6 +102,8 @@ func WhiteSpaceInHTMLIsNormalised() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_4ad12970 = templ.SourceLines{FileName: `generator/test-text-whitespace/template.templ`, From: 80, To: 103, Lines: []int{80, 20}}
+
 const WhiteSpaceInHTMLIsNormalisedExpected = `<p>newlines and other whitespace are stripped but it is normalised like HTML.</p>`
 
 func WhiteSpaceAroundValues() templ.Component {
@@ -105,6 +114,7 @@ func WhiteSpaceAroundValues() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testtextwhitespace.WhiteSpaceAroundValues`, &templ_7745c5c3_SourceLines_52d2e957)
 		templ_7745c5c3_Var4 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var4 == nil {
 			templ_7745c5c3_Var4 = templ.NopComponent
@@ -115,7 +125,7 @@ func WhiteSpaceAroundValues() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var5 string
-		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs("strings")
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs[string]("strings")
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-text-whitespace/template.templ`, Line: 31, Col: 28}
 		}
@@ -134,6 +144,8 @@ func WhiteSpaceAroundValues() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_52d2e957 = templ.SourceLines{FileName: `generator/test-text-whitespace/template.templ`, From: 109, To: 145, Lines: []int{109, 30, 128, 31}}
+
 const WhiteSpaceAroundValuesExpected = `<p>templ allows strings to be included in sentences.</p>`
 
 const WhiteSpaceAroundTemplatedValuesExpected = `<div>templ allows whitespace around templated values.</div>`
@@ -146,6 +158,7 @@ func WhiteSpaceAroundTemplatedValues(prefix, statement string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testtextwhitespace.WhiteSpaceAroundTemplatedValues`, &templ_7745c5c3_SourceLines_2506e3b0)
 		templ_7745c5c3_Var6 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var6 == nil {
 			templ_7745c5c3_Var6 = templ.NopComponent
@@ -156,7 +169,7 @@ func WhiteSpaceAroundTemplatedValues(prefix, statement string) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var7 string
-		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(prefix)
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs[string](prefix)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-text-whitespace/template.templ`, Line: 39, Col: 14}
 		}
@@ -169,7 +182,7 @@ func WhiteSpaceAroundTemplatedValues(prefix, statement string) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var8 string
-		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(statement)
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs[string](statement)
 		if templ_7745c5c3_Err != nil {
 			return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-text-whitespace/template.templ`, Line: 39, Col: 28}
 		}
@@ -187,3 +200,5 @@ func WhiteSpaceAroundTemplatedValues(prefix, statement string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_2506e3b0 = templ.SourceLines{FileName: `generator/test-text-whitespace/template.templ`, From: 153, To: 202, Lines: []int{153, 38, 172, 39, 185, 39}}
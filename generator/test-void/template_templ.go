@@ -17,6 +17,7 @@ func render() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testvoid.render`, &templ_7745c5c3_SourceLines_e7592318)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -32,3 +33,5 @@ func render() templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_e7592318 = templ.SourceLines{FileName: `generator/test-void/template.templ`, From: 12, To: 35, Lines: []int{12, 3}}
@@ -19,6 +19,7 @@ func WhitespaceIsConsistentInIf(firstIf, secondIf bool) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testwhitespacearoundgokeywords.WhitespaceIsConsistentInIf`, &templ_7745c5c3_SourceLines_658e511d)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -55,6 +56,8 @@ func WhitespaceIsConsistentInIf(firstIf, secondIf bool) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_658e511d = templ.SourceLines{FileName: `generator/test-whitespace-around-go-keywords/template.templ`, From: 14, To: 57, Lines: []int{14, 5, 32, 7, 37, 9}}
+
 const WhitespaceIsConsistentInTrueIfExpected = `<button>Start</button> <button>If</button> <button>End</button>`
 const WhitespaceIsConsistentInTrueElseIfExpected = `<button>Start</button> <button>ElseIf</button> <button>End</button>`
 const WhitespaceIsConsistentInTrueElseExpected = `<button>Start</button> <button>Else</button> <button>End</button>`
@@ -67,6 +70,7 @@ func WhitespaceIsConsistentInFalseIf() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testwhitespacearoundgokeywords.WhitespaceIsConsistentInFalseIf`, &templ_7745c5c3_SourceLines_75f00e28)
 		templ_7745c5c3_Var2 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var2 == nil {
 			templ_7745c5c3_Var2 = templ.NopComponent
@@ -93,6 +97,8 @@ func WhitespaceIsConsistentInFalseIf() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_75f00e28 = templ.SourceLines{FileName: `generator/test-whitespace-around-go-keywords/template.templ`, From: 65, To: 98, Lines: []int{65, 21, 83, 23}}
+
 const WhitespaceIsConsistentInFalseIfExpected = `<button>Start</button> <button>End</button>`
 
 func WhitespaceIsConsistentInSwitch(i int) templ.Component {
@@ -103,6 +109,7 @@ func WhitespaceIsConsistentInSwitch(i int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testwhitespacearoundgokeywords.WhitespaceIsConsistentInSwitch`, &templ_7745c5c3_SourceLines_364fc1ec)
 		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var3 == nil {
 			templ_7745c5c3_Var3 = templ.NopComponent
@@ -135,6 +142,8 @@ func WhitespaceIsConsistentInSwitch(i int) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_364fc1ec = templ.SourceLines{FileName: `generator/test-whitespace-around-go-keywords/template.templ`, From: 104, To: 141, Lines: []int{104, 31, 122, 33, 123, 34, 127, 36}}
+
 const WhitespaceIsConsistentInOneSwitchExpected = `<button>Start</button> <button>1</button> <button>End</button>`
 const WhitespaceIsConsistentInDefaultSwitchExpected = `<button>Start</button> <button>default</button> <button>End</button>`
 
@@ -146,6 +155,7 @@ func WhitespaceIsConsistentInSwitchNoDefault() templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testwhitespacearoundgokeywords.WhitespaceIsConsistentInSwitchNoDefault`, &templ_7745c5c3_SourceLines_6b97cbbc)
 		templ_7745c5c3_Var4 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var4 == nil {
 			templ_7745c5c3_Var4 = templ.NopComponent
@@ -173,6 +183,8 @@ func WhitespaceIsConsistentInSwitchNoDefault() templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_6b97cbbc = templ.SourceLines{FileName: `generator/test-whitespace-around-go-keywords/template.templ`, From: 148, To: 181, Lines: []int{148, 45, 166, 47, 167, 48}}
+
 const WhitespaceIsConsistentInSwitchNoDefaultExpected = `<button>Start</button> <button>End</button>`
 
 func WhitespaceIsConsistentInFor(i int) templ.Component {
@@ -183,6 +195,7 @@ func WhitespaceIsConsistentInFor(i int) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `testwhitespacearoundgokeywords.WhitespaceIsConsistentInFor`, &templ_7745c5c3_SourceLines_d66180a1)
 		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var5 == nil {
 			templ_7745c5c3_Var5 = templ.NopComponent
@@ -193,12 +206,15 @@ func WhitespaceIsConsistentInFor(i int) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		for j := 0; j < i; j++ {
+			if templ_7745c5c3_Err = ctx.Err(); templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<button>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var6 string
-			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(j))
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs[string](fmt.Sprint(j))
 			if templ_7745c5c3_Err != nil {
 				return templ.Error{Err: templ_7745c5c3_Err, FileName: `generator/test-whitespace-around-go-keywords/template.templ`, Line: 59, Col: 25}
 			}
@@ -222,6 +238,8 @@ func WhitespaceIsConsistentInFor(i int) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_d66180a1 = templ.SourceLines{FileName: `generator/test-whitespace-around-go-keywords/template.templ`, From: 187, To: 236, Lines: []int{187, 56, 205, 58, 214, 59}}
+
 const WhitespaceIsConsistentInForZeroExpected = `<button>Start</button> <button>End</button>`
 const WhitespaceIsConsistentInForOneExpected = `<button>Start</button> <button>0</button> <button>End</button>`
 const WhitespaceIsConsistentInForThreeExpected = `<button>Start</button> <button>0</button> <button>1</button> <button>2</button> <button>End</button>`
@@ -0,0 +1,27 @@
+package testwhitespacetrim
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const expected = `<div class="toolbar">` +
+	`<button>Save</button> <button>Cancel</button> ` +
+	`<span>Formatting</span> ` +
+	`<button>Bold</button><button>Italic</button><button>Underline</button>` +
+	`</div>`
+
+func Test(t *testing.T) {
+	component := toolbar("Formatting")
+
+	w := new(strings.Builder)
+	if err := component.Render(context.Background(), w); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,67 @@
+package testwrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func Test(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name          string
+		content       templ.Component
+		expected      string
+		expectedError error
+		expectedPanic bool
+	}{
+		{
+			name: "the children are rendered within the layout",
+			content: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, "<p>Content</p>")
+				return err
+			}),
+			expected: `<!doctype html><html><body><main><h1>Title</h1><p>Content</p></main><footer>Footer</footer></body></html>`,
+		},
+		{
+			name: "the layout is rendered if the children return an error",
+			content: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				_, _ = io.WriteString(w, "<p>Partial")
+				return errFailed
+			}),
+			expected:      `<!doctype html><html><body><main><h1>Title</h1><p>Partial</main><footer>Footer</footer></body></html>`,
+			expectedError: errFailed,
+		},
+		{
+			name: "the layout is rendered if the children panic",
+			content: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				panic("oops")
+			}),
+			expected:      `<!doctype html><html><body><main><h1>Title</h1></main><footer>Footer</footer></body></html>`,
+			expectedPanic: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// Output is written to a bytes.Buffer as it's rendered, as it is when
+			// it's streamed, so the output that's rendered before the error is kept.
+			var buf bytes.Buffer
+			err := page(tt.content).Render(context.Background(), &buf)
+			if tt.expectedError != nil && !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+			var pe *templ.PanicError
+			if tt.expectedPanic && !errors.As(err, &pe) {
+				t.Errorf("expected a PanicError, got %v", err)
+			}
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, actual)
+			}
+		})
+	}
+}
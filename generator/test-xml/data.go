@@ -0,0 +1,6 @@
+package testxml
+
+type item struct {
+	title string
+	link  string
+}
@@ -0,0 +1,39 @@
+package testxml
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+const expected = `<?xml version="1.0" encoding="UTF-8"?>` +
+	`<rss version="2.0"><channel><title>News &amp; Views</title><link>https://example.com</link>` +
+	`<item><title>&lt;templ&gt; released</title><link>https://example.com/templ?a=1&amp;b=2</link></item>` +
+	`</channel></rss>`
+
+func Test(t *testing.T) {
+	component := feed("News & Views", []item{
+		{title: "<templ> released", link: "https://example.com/templ?a=1&b=2"},
+	})
+
+	t.Run("XML is rendered without void elements", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := component.Render(context.Background(), w); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the handler sets the XML content type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		templ.Handler(component).ServeHTTP(w, httptest.NewRequest("GET", "/feed.xml", nil))
+		if diff := cmp.Diff("application/xml; charset=utf-8", w.Header().Get("Content-Type")); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -16,10 +16,13 @@ require (
 	github.com/google/go-cmp v0.6.0
 	github.com/natefinch/atomic v1.0.1
 	github.com/rs/cors v1.8.3
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	go.lsp.dev/jsonrpc2 v0.10.0
 	go.lsp.dev/uri v0.3.0
 	go.uber.org/zap v1.24.0
 	golang.org/x/mod v0.12.0
+	golang.org/x/net v0.19.0
+	golang.org/x/sys v0.15.0
 	golang.org/x/tools v0.13.0
 )
 
@@ -34,8 +37,6 @@ require (
 	go.lsp.dev/pkg v0.0.0-20210717090340-384b27a52fb2 // indirect
 	go.uber.org/atomic v1.10.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
-	golang.org/x/net v0.19.0 // indirect
-	golang.org/x/sys v0.15.0 // indirect
 )
 
 // replace github.com/a-h/parse => /Users/adrian/github.com/a-h/parse
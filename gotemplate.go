@@ -0,0 +1,83 @@
+//go:build !tinygo
+
+package templ
+
+import (
+	"context"
+	"html/template"
+	"io"
+	texttemplate "text/template"
+)
+
+// FromGoHTML creates a templ Component from a Go html/template template.
+func FromGoHTML(t *template.Template, data any) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		return t.Execute(w, data)
+	})
+}
+
+// ToGoHTML renders the component to a Go html/template template.HTML string.
+func ToGoHTML(ctx context.Context, c Component) (s template.HTML, err error) {
+	b := GetBuffer()
+	defer ReleaseBuffer(b)
+	if err = c.Render(ctx, b); err != nil {
+		return
+	}
+	s = template.HTML(b.String())
+	return
+}
+
+// GoTemplate is a Go html/template or text/template template.
+type GoTemplate interface {
+	*template.Template | *texttemplate.Template
+}
+
+// FromGoTemplate creates a templ Component from a Go html/template or
+// text/template template. The output of html/template is escaped by the
+// template, so it's rendered as it is, like FromGoHTML. text/template doesn't
+// escape its output, so the output is HTML escaped, and rendered as text.
+func FromGoTemplate[T GoTemplate](t T, data any) Component {
+	tt, ok := any(t).(*texttemplate.Template)
+	if !ok {
+		return FromGoHTML(any(t).(*template.Template), data)
+	}
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		b := GetBuffer()
+		defer ReleaseBuffer(b)
+		if err = tt.Execute(b, data); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, EscapeString(b.String()))
+		return err
+	})
+}
+
+// ToGoTemplateFunc returns a function that renders templ components in Go
+// html/template templates, for use in a template.FuncMap, e.g.
+//
+//	t := template.New("page").Funcs(template.FuncMap{
+//		"templ": templ.ToGoTemplateFunc(ctx),
+//	})
+//
+// allows a component to be rendered with {{ templ .Header }}. The output of the
+// component isn't escaped.
+func ToGoTemplateFunc(ctx context.Context) func(c Component) (template.HTML, error) {
+	return func(c Component) (template.HTML, error) {
+		return ToGoHTML(ctx, c)
+	}
+}
+
+// ToGoTemplateFunc returns a function that renders the component in Go
+// html/template templates, for use in a template.FuncMap, e.g.
+//
+//	t := template.New("page").Funcs(template.FuncMap{
+//		"header": templ.ComponentFunc(header).ToGoTemplateFunc(),
+//	})
+//
+// allows the component to be rendered with {{ header }}. The component is
+// rendered with a background context, and its output isn't escaped.
+func (cf ComponentFunc) ToGoTemplateFunc() func() (template.HTML, error) {
+	return func() (template.HTML, error) {
+		return ToGoHTML(context.Background(), cf)
+	}
+}
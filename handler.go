@@ -0,0 +1,453 @@
+//go:build !tinygo
+
+package templ
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The net/http integration isn't available when building with TinyGo, which
+// doesn't support net/http on most targets. Components can still be rendered
+// with Render.
+
+// ComponentHandler is a http.Handler that renders components.
+type ComponentHandler struct {
+	Component    Component
+	Status       int
+	ContentType  string
+	ErrorHandler func(r *http.Request, err error) http.Handler
+	// Tracer, if set, is used to trace the rendering of the component.
+	Tracer Tracer
+	// Timeout, if set, is the maximum duration of rendering the component.
+	Timeout time.Duration
+	// Streaming, if set, writes output to the client as it's rendered, see
+	// WithStreaming.
+	Streaming bool
+	// CacheKeyETag, if set, derives the ETag from the cache key of the
+	// component, instead of the rendered output, see WithCacheKeyETag.
+	CacheKeyETag bool
+}
+
+const (
+	componentHandlerErrorMessage   = "templ: failed to render template"
+	componentHandlerTimeoutMessage = "templ: timed out rendering template"
+)
+
+// ServeHTTP implements the http.Handler interface.
+func (ch ComponentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := WithRequestURL(r.Context(), r.URL)
+	if ch.Tracer != nil {
+		ctx = WithTracer(ctx, ch.Tracer)
+	}
+	if ch.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ch.Timeout)
+		defer cancel()
+	}
+	if ch.Streaming {
+		ch.serveStreaming(ctx, w, r)
+		return
+	}
+	if etag, ok := ch.cacheKeyETag(); ok && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		ch.serveNotModified(w, etag)
+		return
+	}
+	// Since the component may error, write to a buffer first.
+	// This prevents partial responses from being written to the client.
+	buf := GetBuffer()
+	defer ReleaseBuffer(buf)
+	start := time.Now()
+	err := ch.Component.Render(ctx, buf)
+	if mr, ok := ch.Tracer.(RenderMetricsRecorder); ok {
+		mr.RecordRender(r, time.Since(start), buf.Len(), err)
+	}
+	if err != nil {
+		ch.serveError(w, r, err)
+		return
+	}
+	etag, ok := ch.cacheKeyETag()
+	if !ok {
+		etag, ok = ch.bodyETag(buf.Bytes())
+	}
+	if ok && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		ch.serveNotModified(w, etag)
+		return
+	}
+	ch.writeHeader(w, etag)
+	// Ignore write error like http.Error() does, because there is
+	// no way to recover at this point.
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeHeader writes the header of the response, with the ETag, if it's not
+// empty.
+func (ch ComponentHandler) writeHeader(w http.ResponseWriter, etag string) {
+	w.Header().Set("Content-Type", ch.ContentType)
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if ch.Status != 0 {
+		w.WriteHeader(ch.Status)
+	}
+}
+
+func (ch ComponentHandler) serveNotModified(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// hasETag returns true if responses with the status of the handler have an
+// ETag. Only successful responses are cached.
+func (ch ComponentHandler) hasETag() bool {
+	return ch.Status == 0 || ch.Status == http.StatusOK
+}
+
+// bodyETag returns the ETag of a successful response with the body.
+func (ch ComponentHandler) bodyETag(body []byte) (etag string, ok bool) {
+	if !ch.hasETag() {
+		return "", false
+	}
+	h := sha256.New()
+	h.Write([]byte(ch.ContentType + "\x00"))
+	h.Write(body)
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`, true
+}
+
+// cacheKeyETag returns the ETag of successful responses, derived from the cache
+// key of the component, if CacheKeyETag is set and the component has a key,
+// see CacheKeyer.
+func (ch ComponentHandler) cacheKeyETag() (etag string, ok bool) {
+	if !ch.CacheKeyETag || !ch.hasETag() {
+		return "", false
+	}
+	key, ok := CacheKey(ch.Component)
+	if !ok {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(ch.ContentType + "\x00key\x00" + key))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, true
+}
+
+// etagMatches returns true if the If-None-Match header contains the ETag, using
+// the weak comparison of RFC 9110.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func (ch ComponentHandler) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	// If the client has disconnected, there's nobody to send a response to.
+	if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+		return
+	}
+	if ch.ErrorHandler != nil {
+		w.Header().Set("Content-Type", ch.ContentType)
+		ch.ErrorHandler(r, err).ServeHTTP(w, r)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, componentHandlerTimeoutMessage, http.StatusServiceUnavailable)
+		return
+	}
+	http.Error(w, componentHandlerErrorMessage, http.StatusInternalServerError)
+}
+
+func (ch ComponentHandler) serveStreaming(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	sw := &streamingWriter{ch: ch, w: w, rc: http.NewResponseController(w)}
+	start := time.Now()
+	err := ch.Component.Render(ctx, sw)
+	if mr, ok := ch.Tracer.(RenderMetricsRecorder); ok {
+		mr.RecordRender(r, time.Since(start), sw.n, err)
+	}
+	if err != nil {
+		// Once output has been sent, the status can't be changed, so the error
+		// can't be reported to the client.
+		if !sw.wroteHeader {
+			ch.serveError(w, r, err)
+		}
+		return
+	}
+	if !sw.wroteHeader {
+		ch.writeHeader(w, "")
+	}
+}
+
+// streamingWriter writes the header of the response before the first write, and
+// flushes each write to the client.
+type streamingWriter struct {
+	ch          ComponentHandler
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	wroteHeader bool
+	n           int
+}
+
+func (sw *streamingWriter) Write(p []byte) (n int, err error) {
+	if !sw.wroteHeader {
+		sw.ch.writeHeader(sw.w, "")
+		sw.wroteHeader = true
+	}
+	n, err = sw.w.Write(p)
+	sw.n += n
+	if err != nil {
+		return n, err
+	}
+	// Not all response writers support flushing, e.g. some middleware.
+	if err = sw.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return n, err
+	}
+	return n, nil
+}
+
+var _ io.Writer = (*streamingWriter)(nil)
+
+// Handler creates a http.Handler that renders the template.
+//
+// Successful responses have an ETag, which is a hash of the content type and the
+// rendered output, and requests with a matching If-None-Match header get a 304
+// Not Modified response without a body. Streamed responses, see WithStreaming,
+// don't have an ETag, since the output isn't known until it's been sent.
+func Handler(c Component, options ...func(*ComponentHandler)) *ComponentHandler {
+	ch := &ComponentHandler{
+		Component:   c,
+		ContentType: "text/html; charset=utf-8",
+	}
+	if ct, ok := c.(ContentTyper); ok {
+		ch.ContentType = ct.ContentType()
+	}
+	for _, o := range options {
+		o(ch)
+	}
+	return ch
+}
+
+// WithStatus sets the HTTP status code returned by the ComponentHandler.
+func WithStatus(status int) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Status = status
+	}
+}
+
+// WithConentType sets the Content-Type header returned by the ComponentHandler.
+func WithContentType(contentType string) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.ContentType = contentType
+	}
+}
+
+// WithErrorHandler sets the error handler used if rendering fails.
+func WithErrorHandler(eh func(r *http.Request, err error) http.Handler) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.ErrorHandler = eh
+	}
+}
+
+// WithRenderTimeout sets the maximum duration of rendering the component. If
+// rendering takes longer, the ComponentHandler responds with 503 Service
+// Unavailable, or calls the error handler with context.DeadlineExceeded.
+//
+// Generated code checks the context before each iteration of a for loop, and
+// before rendering each child component, so rendering stops soon after the
+// timeout, or after the client disconnects.
+func WithRenderTimeout(d time.Duration) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Timeout = d
+	}
+}
+
+// WithStreaming writes the output of the component to the client as it's
+// rendered, and flushes each write, instead of rendering to a buffer and writing
+// the response once rendering finishes.
+//
+// Generated components write their output once they've rendered, so output is
+// streamed between components, e.g. the components rendered by Join, or by
+// a ComponentFunc that renders a component for each row of a report. If a
+// component fails after output has been sent, the error handler isn't called,
+// since the response has already started.
+func WithStreaming() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Streaming = true
+	}
+}
+
+// WithCacheKeyETag derives the ETag of responses from the cache key of the
+// component, see CacheKeyer, instead of the rendered output, so that requests
+// with a matching If-None-Match header get a 304 Not Modified response without
+// rendering the component. The cache key must change whenever the output of the
+// component changes. Components without a cache key are hashed after rendering.
+// It has no effect on streamed responses.
+func WithCacheKeyETag() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.CacheKeyETag = true
+	}
+}
+
+// WithCriticalCSS inlines the CSS of the css components used by the component
+// in the <head> of the page. See CriticalCSS.
+func WithCriticalCSS() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Component = CriticalCSS(ch.Component)
+	}
+}
+
+// WithTracing sets the Tracer used for components rendered by the ComponentHandler.
+// If the Tracer implements RenderMetricsRecorder, response metrics are also recorded.
+func WithTracing(t Tracer) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Tracer = t
+	}
+}
+
+// RenderMetricsRecorder can optionally be implemented by a Tracer to record
+// metrics about HTTP responses rendered by a ComponentHandler.
+type RenderMetricsRecorder interface {
+	RecordRender(r *http.Request, d time.Duration, size int, err error)
+}
+
+// NewCSSMiddleware creates HTTP middleware that renders a global stylesheet of ComponentCSSClass
+// CSS if the request path matches, or updates the HTTP context to ensure that any handlers that
+// use templ.Components skip rendering <style> elements for classes that are included in the global
+// stylesheet. By default, the stylesheet path is /styles/templ.css
+func NewCSSMiddleware(next http.Handler, classes ...CSSClass) CSSMiddleware {
+	return CSSMiddleware{
+		Path:       "/styles/templ.css",
+		CSSHandler: NewCSSHandler(classes...),
+		Next:       next,
+	}
+}
+
+// CSSMiddleware renders a global stylesheet.
+type CSSMiddleware struct {
+	Path       string
+	CSSHandler CSSHandler
+	Next       http.Handler
+}
+
+func (cssm CSSMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == cssm.Path {
+		cssm.CSSHandler.ServeHTTP(w, r)
+		return
+	}
+	// Add registered classes to the context.
+	ctx, v := getContext(r.Context())
+	for _, c := range cssm.CSSHandler.Classes {
+		v.addClass(c.ID)
+	}
+	// Serve the request. Templ components will use the updated context
+	// to know to skip rendering <style> elements for any component CSS
+	// classes that have been included in the global stylesheet.
+	cssm.Next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// NewCSSHandler creates a handler that serves a stylesheet containing the CSS of the
+// classes passed in. This is used by the CSSMiddleware to provide global stylesheets
+// for templ components.
+func NewCSSHandler(classes ...CSSClass) CSSHandler {
+	ccssc := make([]ComponentCSSClass, 0, len(classes))
+	for _, c := range classes {
+		ccss, ok := c.(ComponentCSSClass)
+		if !ok {
+			continue
+		}
+		ccssc = append(ccssc, ccss)
+	}
+	return CSSHandler{
+		Classes: ccssc,
+	}
+}
+
+// CSSHandler is a HTTP handler that serves CSS.
+type CSSHandler struct {
+	Logger  func(err error)
+	Classes []ComponentCSSClass
+}
+
+func (cssh CSSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css")
+	for _, c := range cssh.Classes {
+		_, err := w.Write([]byte(c.Class))
+		if err != nil && cssh.Logger != nil {
+			cssh.Logger(err)
+		}
+	}
+}
+
+// NewCSRFMiddleware creates HTTP middleware that adds the CSRF token of each
+// request to the context, so that it's rendered by CSRF and CSRFMeta.
+//
+// The token function returns the token of the request, e.g. csrf.Token from
+// github.com/gorilla/csrf, or nosurf.Token from github.com/justinas/nosurf. The
+// middleware must be used inside the middleware of the CSRF library, which
+// checks the token.
+func NewCSRFMiddleware(next http.Handler, token func(r *http.Request) string) CSRFMiddleware {
+	return CSRFMiddleware{
+		Next:      next,
+		Token:     token,
+		FieldName: DefaultCSRFFieldName,
+	}
+}
+
+// CSRFMiddleware adds the CSRF token of each request to the context.
+type CSRFMiddleware struct {
+	Next  http.Handler
+	Token func(r *http.Request) string
+	// FieldName is the name of the form field that the CSRF library reads the
+	// token from, e.g. gorilla.csrf.Token for github.com/gorilla/csrf.
+	FieldName string
+}
+
+func (csrfm CSRFMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := WithCSRFToken(r.Context(), csrfm.FieldName, csrfm.Token(r))
+	csrfm.Next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// NewURLResolverMiddleware creates HTTP middleware that adds the URL resolver
+// to the context of each request, so that it's used by URLFor.
+func NewURLResolverMiddleware(next http.Handler, resolver URLResolver) URLResolverMiddleware {
+	return URLResolverMiddleware{
+		Next:     next,
+		Resolver: resolver,
+	}
+}
+
+// URLResolverMiddleware adds the URL resolver to the context of each request.
+type URLResolverMiddleware struct {
+	Next     http.Handler
+	Resolver URLResolver
+}
+
+func (urm URLResolverMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := WithURLResolver(r.Context(), urm.Resolver)
+	urm.Next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// NewRequestURLMiddleware creates HTTP middleware that adds the URL of each
+// request to the context, so that it's used by IsActivePath and WithQuery when
+// components aren't rendered by Handler.
+func NewRequestURLMiddleware(next http.Handler) RequestURLMiddleware {
+	return RequestURLMiddleware{
+		Next: next,
+	}
+}
+
+// RequestURLMiddleware adds the URL of each request to the context.
+type RequestURLMiddleware struct {
+	Next http.Handler
+}
+
+func (rum RequestURLMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := WithRequestURL(r.Context(), r.URL)
+	rum.Next.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,173 @@
+package htmx
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// SwapStyle is how the response is swapped into the target element.
+type SwapStyle string
+
+const (
+	// InnerHTML replaces the contents of the target element.
+	InnerHTML SwapStyle = "innerHTML"
+	// OuterHTML replaces the target element.
+	OuterHTML SwapStyle = "outerHTML"
+	// BeforeBegin inserts the response before the target element.
+	BeforeBegin SwapStyle = "beforebegin"
+	// AfterBegin inserts the response before the first child of the target element.
+	AfterBegin SwapStyle = "afterbegin"
+	// BeforeEnd inserts the response after the last child of the target element.
+	BeforeEnd SwapStyle = "beforeend"
+	// AfterEnd inserts the response after the target element.
+	AfterEnd SwapStyle = "afterend"
+	// SwapDelete deletes the target element.
+	SwapDelete SwapStyle = "delete"
+	// SwapNone doesn't swap the response.
+	SwapNone SwapStyle = "none"
+)
+
+// Attributes merges the attributes into a single set of attributes, so that they
+// can be spread onto an element, e.g. <button { htmx.Attributes(htmx.Get("/"), htmx.Target("#id"))... }>.
+func Attributes(attrs ...templ.Attributes) templ.Attributes {
+	merged := templ.Attributes{}
+	for _, a := range attrs {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// Get issues a GET request to the URL.
+func Get(url string) templ.Attributes {
+	return templ.Attributes{"hx-get": url}
+}
+
+// Post issues a POST request to the URL.
+func Post(url string) templ.Attributes {
+	return templ.Attributes{"hx-post": url}
+}
+
+// Put issues a PUT request to the URL.
+func Put(url string) templ.Attributes {
+	return templ.Attributes{"hx-put": url}
+}
+
+// Patch issues a PATCH request to the URL.
+func Patch(url string) templ.Attributes {
+	return templ.Attributes{"hx-patch": url}
+}
+
+// Delete issues a DELETE request to the URL.
+func Delete(url string) templ.Attributes {
+	return templ.Attributes{"hx-delete": url}
+}
+
+// Target sets the element that the response is swapped into, e.g. #id, or this.
+func Target(selector string) templ.Attributes {
+	return templ.Attributes{"hx-target": selector}
+}
+
+// Swap sets how the response is swapped into the target, with optional modifiers,
+// e.g. Swap(OuterHTML, "transition:true").
+func Swap(style SwapStyle, modifiers ...string) templ.Attributes {
+	return templ.Attributes{"hx-swap": strings.Join(append([]string{string(style)}, modifiers...), " ")}
+}
+
+// Trigger sets the events that trigger the request, e.g. "click", or "keyup changed delay:500ms".
+func Trigger(triggers ...string) templ.Attributes {
+	return templ.Attributes{"hx-trigger": strings.Join(triggers, ", ")}
+}
+
+// Select sets the part of the response that's swapped into the target.
+func Select(selector string) templ.Attributes {
+	return templ.Attributes{"hx-select": selector}
+}
+
+// SelectOOB sets the parts of the response that are swapped out of band.
+func SelectOOB(selectors ...string) templ.Attributes {
+	return templ.Attributes{"hx-select-oob": strings.Join(selectors, ",")}
+}
+
+// SwapOOB marks the element as swapped out of band, by ID, when it's in a response.
+func SwapOOB(style SwapStyle) templ.Attributes {
+	return templ.Attributes{"hx-swap-oob": string(style)}
+}
+
+// PushURL pushes the URL of the request into the browser's history.
+func PushURL(push bool) templ.Attributes {
+	return templ.Attributes{"hx-push-url": boolString(push)}
+}
+
+// PushURLTo pushes the URL into the browser's history.
+func PushURLTo(url string) templ.Attributes {
+	return templ.Attributes{"hx-push-url": url}
+}
+
+// ReplaceURL replaces the current URL in the browser's history with the URL.
+func ReplaceURL(url string) templ.Attributes {
+	return templ.Attributes{"hx-replace-url": url}
+}
+
+// Boost converts the links and forms of the element's descendants to htmx requests.
+func Boost(boost bool) templ.Attributes {
+	return templ.Attributes{"hx-boost": boolString(boost)}
+}
+
+// Confirm shows a confirmation dialog with the message before the request is issued.
+func Confirm(message string) templ.Attributes {
+	return templ.Attributes{"hx-confirm": message}
+}
+
+// Indicator sets the element that has the htmx-request class during the request.
+func Indicator(selector string) templ.Attributes {
+	return templ.Attributes{"hx-indicator": selector}
+}
+
+// Include includes the values of the elements in the request.
+func Include(selector string) templ.Attributes {
+	return templ.Attributes{"hx-include": selector}
+}
+
+// DisabledElt disables the elements during the request.
+func DisabledElt(selector string) templ.Attributes {
+	return templ.Attributes{"hx-disabled-elt": selector}
+}
+
+// Sync synchronises the requests of the element with the requests of another, e.g. Sync("closest form", "abort").
+func Sync(selector, strategy string) templ.Attributes {
+	return templ.Attributes{"hx-sync": selector + ":" + strategy}
+}
+
+// Ext enables htmx extensions for the element and its descendants.
+func Ext(extensions ...string) templ.Attributes {
+	return templ.Attributes{"hx-ext": strings.Join(extensions, ",")}
+}
+
+// Vals adds the JSON encoded values to the parameters of the request.
+func Vals(values any) (templ.Attributes, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return templ.Attributes{"hx-vals": string(data)}, nil
+}
+
+// Headers adds the headers to the request.
+func Headers(headers map[string]string) (templ.Attributes, error) {
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return nil, err
+	}
+	return templ.Attributes{"hx-headers": string(data)}, nil
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
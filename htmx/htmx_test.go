@@ -0,0 +1,74 @@
+package htmx
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAttributes(t *testing.T) {
+	vals, err := Vals(map[string]int{"page": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attrs := Attributes(
+		Get("/items"),
+		Target("#items"),
+		Swap(OuterHTML, "transition:true"),
+		Trigger("click", "keyup changed delay:500ms"),
+		PushURL(true),
+		vals,
+	)
+	w := new(strings.Builder)
+	if err = templ.RenderAttributes(context.Background(), w, attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ` hx-get="/items"` +
+		` hx-push-url="true"` +
+		` hx-swap="outerHTML transition:true"` +
+		` hx-target="#items"` +
+		` hx-trigger="click, keyup changed delay:500ms"` +
+		` hx-vals="{&#34;page&#34;:2}"`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if IsRequest(r) {
+		t.Error("expected a request without the HX-Request header not to be detected")
+	}
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "items")
+	if !IsRequest(r) {
+		t.Error("expected the request to be detected")
+	}
+	if diff := cmp.Diff("items", TargetID(r)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	SetTrigger(w, "itemAdded", "listChanged")
+	SetRedirect(w, "/login")
+	SetReswap(w, InnerHTML)
+	if err := SetTriggerWithDetail(w, map[string]any{"showMessage": "Saved"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"Hx-Trigger":  `{"showMessage":"Saved"}`,
+		"Hx-Redirect": "/login",
+		"Hx-Reswap":   "innerHTML",
+	}
+	for name, value := range expected {
+		if diff := cmp.Diff(value, w.Header().Get(name)); diff != "" {
+			t.Errorf("%s: %s", name, diff)
+		}
+	}
+}
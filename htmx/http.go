@@ -0,0 +1,115 @@
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// IsRequest returns true if the request was issued by htmx.
+func IsRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// IsBoosted returns true if the request was issued by an element that uses hx-boost.
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get("HX-Boosted") == "true"
+}
+
+// IsHistoryRestoreRequest returns true if the request is for history restoration
+// after a miss in the local history cache.
+func IsHistoryRestoreRequest(r *http.Request) bool {
+	return r.Header.Get("HX-History-Restore-Request") == "true"
+}
+
+// CurrentURL returns the URL of the browser when the request was issued.
+func CurrentURL(r *http.Request) string {
+	return r.Header.Get("HX-Current-URL")
+}
+
+// TargetID returns the ID of the target element, if it has one.
+func TargetID(r *http.Request) string {
+	return r.Header.Get("HX-Target")
+}
+
+// TriggerID returns the ID of the element that triggered the request, if it has one.
+func TriggerID(r *http.Request) string {
+	return r.Header.Get("HX-Trigger")
+}
+
+// TriggerName returns the name of the element that triggered the request, if it has one.
+func TriggerName(r *http.Request) string {
+	return r.Header.Get("HX-Trigger-Name")
+}
+
+// Prompt returns the user's response to an hx-prompt.
+func Prompt(r *http.Request) string {
+	return r.Header.Get("HX-Prompt")
+}
+
+// SetTrigger triggers the client-side events when the response is received.
+func SetTrigger(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger", strings.Join(events, ", "))
+}
+
+// SetTriggerWithDetail triggers the client-side events when the response is
+// received, with the JSON encoded values as the details of the events.
+func SetTriggerWithDetail(w http.ResponseWriter, events map[string]any) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("HX-Trigger", string(data))
+	return nil
+}
+
+// SetTriggerAfterSwap triggers the client-side events after the response is swapped.
+func SetTriggerAfterSwap(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger-After-Swap", strings.Join(events, ", "))
+}
+
+// SetTriggerAfterSettle triggers the client-side events after the response has settled.
+func SetTriggerAfterSettle(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger-After-Settle", strings.Join(events, ", "))
+}
+
+// SetRedirect redirects the browser to the URL, with a full page reload.
+func SetRedirect(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Redirect", url)
+}
+
+// SetLocation redirects the browser to the URL without a full page reload, as
+// if a boosted link was followed.
+func SetLocation(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Location", url)
+}
+
+// SetRefresh makes the browser reload the page.
+func SetRefresh(w http.ResponseWriter) {
+	w.Header().Set("HX-Refresh", "true")
+}
+
+// SetPushURL pushes the URL into the browser's history.
+func SetPushURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Push-Url", url)
+}
+
+// SetReplaceURL replaces the current URL in the browser's history.
+func SetReplaceURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Replace-Url", url)
+}
+
+// SetRetarget changes the element that the response is swapped into.
+func SetRetarget(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Retarget", selector)
+}
+
+// SetReselect changes the part of the response that's swapped into the target.
+func SetReselect(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Reselect", selector)
+}
+
+// SetReswap changes how the response is swapped into the target.
+func SetReswap(w http.ResponseWriter, style SwapStyle, modifiers ...string) {
+	w.Header().Set("HX-Reswap", strings.Join(append([]string{string(style)}, modifiers...), " "))
+}
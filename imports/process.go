@@ -0,0 +1,146 @@
+// Package imports adds missing imports to templ files, and removes unused
+// imports from them, in the same way as goimports does for Go files.
+package imports
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/generator"
+	parser "github.com/a-h/templ/parser/v2"
+	"golang.org/x/tools/go/ast/astutil"
+	goimports "golang.org/x/tools/imports"
+)
+
+// Process updates the imports of the template file. The Go code generated
+// from the template is passed to goimports, and the imports that goimports
+// adds or removes are added to, or removed from, the Go code of the template
+// file. The file name is used to find the packages of the module.
+func Process(fileName string, tf parser.TemplateFile) (parser.TemplateFile, error) {
+	var code bytes.Buffer
+	if _, _, err := generator.Generate(tf, &code); err != nil {
+		return tf, fmt.Errorf("failed to generate code: %w", err)
+	}
+	before, err := importsOf(code.Bytes())
+	if err != nil {
+		return tf, err
+	}
+	goFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	processed, err := goimports.Process(goFileName, code.Bytes(), &goimports.Options{Comments: true, TabIndent: true, TabWidth: 8})
+	if err != nil {
+		return tf, fmt.Errorf("failed to process imports: %w", err)
+	}
+	after, err := importsOf(processed)
+	if err != nil {
+		return tf, err
+	}
+
+	var added, removed []importSpec
+	for spec := range after {
+		if _, ok := before[spec]; !ok {
+			added = append(added, spec)
+		}
+	}
+	for spec := range before {
+		if _, ok := after[spec]; !ok {
+			removed = append(removed, spec)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return tf, nil
+	}
+
+	// Imports are added to the Go code at the start of the file.
+	if len(added) > 0 {
+		if _, ok := firstNode(tf).(parser.TemplateFileGoExpression); !ok {
+			tf.Nodes = append([]parser.TemplateFileNode{parser.TemplateFileGoExpression{}}, tf.Nodes...)
+		}
+	}
+	nodes := make([]parser.TemplateFileNode, 0, len(tf.Nodes))
+	for i, n := range tf.Nodes {
+		if e, ok := n.(parser.TemplateFileGoExpression); ok {
+			var toAdd []importSpec
+			if i == 0 {
+				toAdd = added
+			}
+			if e.Expression.Value, err = updateImports(e.Expression.Value, toAdd, removed); err != nil {
+				return tf, err
+			}
+			if e.Expression.Value == "" {
+				continue
+			}
+			n = e
+		}
+		nodes = append(nodes, n)
+	}
+	tf.Nodes = nodes
+	return tf, nil
+}
+
+func firstNode(tf parser.TemplateFile) parser.TemplateFileNode {
+	if len(tf.Nodes) == 0 {
+		return nil
+	}
+	return tf.Nodes[0]
+}
+
+type importSpec struct {
+	name string
+	path string
+}
+
+func importsOf(code []byte) (specs map[importSpec]struct{}, err error) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", code, goparser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	specs = make(map[importSpec]struct{}, len(f.Imports))
+	for _, spec := range f.Imports {
+		specs[newImportSpec(spec)] = struct{}{}
+	}
+	return specs, nil
+}
+
+func newImportSpec(spec *ast.ImportSpec) (s importSpec) {
+	s.path, _ = strconv.Unquote(spec.Path.Value)
+	if spec.Name != nil {
+		s.name = spec.Name.Name
+	}
+	return s
+}
+
+const packagePrefix = "package p\n\n"
+
+// updateImports adds and removes imports from the Go code of a template file.
+// Code that doesn't import any of the removed imports, and that no imports are
+// added to, is returned as it is.
+func updateImports(code string, added, removed []importSpec) (updated string, err error) {
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "", packagePrefix+code, goparser.ParseComments)
+	if err != nil {
+		// The Go code of the template file can't be changed if it's invalid.
+		return code, nil
+	}
+	var changed bool
+	for _, spec := range removed {
+		changed = astutil.DeleteNamedImport(fset, f, spec.name, spec.path) || changed
+	}
+	for _, spec := range added {
+		changed = astutil.AddNamedImport(fset, f, spec.name, spec.path) || changed
+	}
+	if !changed {
+		return code, nil
+	}
+	var sb strings.Builder
+	if err = format.Node(&sb, fset, f); err != nil {
+		return code, fmt.Errorf("failed to format imports: %w", err)
+	}
+	updated = strings.TrimPrefix(sb.String(), strings.TrimSuffix(packagePrefix, "\n"))
+	return strings.TrimSpace(updated), nil
+}
@@ -0,0 +1,171 @@
+package imports
+
+import (
+	"strings"
+	"testing"
+
+	parser "github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "missing imports are added",
+			input: `package main
+
+templ page(name string) {
+	<p>{ strings.ToUpper(name) }</p>
+}
+`,
+			expected: `package main
+
+import "strings"
+
+templ page(name string) {
+	<p>{ strings.ToUpper(name) }</p>
+}
+`,
+		},
+		{
+			name: "missing imports are added to existing imports",
+			input: `package main
+
+import "fmt"
+
+templ page(name string) {
+	<p>{ fmt.Sprint(name) }</p>
+	<p>{ strings.ToUpper(name) }</p>
+}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+templ page(name string) {
+	<p>{ fmt.Sprint(name) }</p>
+	<p>{ strings.ToUpper(name) }</p>
+}
+`,
+		},
+		{
+			name: "unused imports are removed",
+			input: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+templ page(name string) {
+	<p>{ strings.ToUpper(name) }</p>
+}
+`,
+			expected: `package main
+
+import (
+	"strings"
+)
+
+templ page(name string) {
+	<p>{ strings.ToUpper(name) }</p>
+}
+`,
+		},
+		{
+			name: "imports used by Go code are kept",
+			input: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func upper(s string) string {
+	return strings.ToUpper(fmt.Sprint(s))
+}
+
+templ page(name string) {
+	<p>{ upper(name) }</p>
+}
+`,
+			expected: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func upper(s string) string {
+	return strings.ToUpper(fmt.Sprint(s))
+}
+
+templ page(name string) {
+	<p>{ upper(name) }</p>
+}
+`,
+		},
+		{
+			name: "import declarations without used imports are removed",
+			input: `package main
+
+import "fmt"
+
+templ page(name string) {
+	<p>{ name }</p>
+}
+`,
+			expected: `package main
+
+templ page(name string) {
+	<p>{ name }</p>
+}
+`,
+		},
+		{
+			name: "imports of templates and components are kept",
+			input: `package main
+
+import "github.com/a-h/templ"
+
+templ page(c templ.Component) {
+	@c
+}
+`,
+			expected: `package main
+
+import "github.com/a-h/templ"
+
+templ page(c templ.Component) {
+	@c
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			tf, err = Process("test.templ", tf)
+			if err != nil {
+				t.Fatalf("failed to process imports: %v", err)
+			}
+			var sb strings.Builder
+			if err = tf.Write(&sb); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
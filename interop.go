@@ -0,0 +1,55 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// Renderer is implemented by the components of other libraries that render
+// HTML to a writer, e.g. gomponents nodes.
+type Renderer interface {
+	Render(w io.Writer) error
+}
+
+// FromRenderer creates a templ Component from a component of another library,
+// e.g. a gomponents node. The output of the component isn't escaped.
+func FromRenderer(r Renderer) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return r.Render(w)
+	})
+}
+
+// FromWriterTo creates a templ Component from an io.WriterTo that writes HTML.
+// The output isn't escaped.
+func FromWriterTo(wt io.WriterTo) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := wt.WriteTo(w)
+		return err
+	})
+}
+
+// ToRenderer adapts a templ Component to the Renderer and io.WriterTo
+// interfaces, so that it can be used by other libraries, e.g. as a gomponents
+// node. The component is rendered with the context.
+func ToRenderer(ctx context.Context, c Component) ComponentRenderer {
+	return ComponentRenderer{ctx: ctx, component: c}
+}
+
+// ComponentRenderer renders a templ Component with a context. See ToRenderer.
+type ComponentRenderer struct {
+	ctx       context.Context
+	component Component
+}
+
+// Render renders the component to the writer.
+func (cr ComponentRenderer) Render(w io.Writer) error {
+	return cr.component.Render(cr.ctx, w)
+}
+
+// WriteTo renders the component to the writer, and returns the number of bytes
+// written.
+func (cr ComponentRenderer) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &countingWriter{w: w}
+	err = cr.component.Render(cr.ctx, cw)
+	return int64(cw.n), err
+}
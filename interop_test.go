@@ -0,0 +1,70 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+// node is shaped like a gomponents node.
+type node string
+
+func (n node) Render(w io.Writer) error {
+	_, err := io.WriteString(w, string(n))
+	return err
+}
+
+func TestInterop(t *testing.T) {
+	t.Run("renderers can be rendered as templ components", func(t *testing.T) {
+		var sb strings.Builder
+		if err := templ.FromRenderer(node("<p>a</p>")).Render(context.Background(), &sb); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if diff := cmp.Diff("<p>a</p>", sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("writer tos can be rendered as templ components", func(t *testing.T) {
+		var sb strings.Builder
+		if err := templ.FromWriterTo(bytes.NewBufferString("<p>a</p>")).Render(context.Background(), &sb); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if diff := cmp.Diff("<p>a</p>", sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("templ components can be rendered by other libraries", func(t *testing.T) {
+		type ctxKey struct{}
+		c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, ctx.Value(ctxKey{}).(string))
+			return err
+		})
+		r := templ.ToRenderer(context.WithValue(context.Background(), ctxKey{}, "<p>a</p>"), c)
+		var sb strings.Builder
+		if err := r.Render(&sb); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		n, err := r.WriteTo(&sb)
+		if err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		if n != 8 {
+			t.Errorf("expected 8 bytes to be written, got %d", n)
+		}
+		if diff := cmp.Diff("<p>a</p><p>a</p>", sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("errors are returned", func(t *testing.T) {
+		r := templ.ToRenderer(context.Background(), templ.Raw("", errors.New("failed")))
+		if err := r.Render(io.Discard); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
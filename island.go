@@ -0,0 +1,96 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// IslandComponent is a component that's hydrated on the client by a JavaScript
+// function. See Island.
+type IslandComponent struct {
+	// Name of the island, e.g. Counter.
+	Name string
+	// Props passed to the hydrate function, encoded as JSON.
+	Props any
+	// Src is the URL of a JavaScript module that exports the hydrate function,
+	// either with the name of the island, or as the default export. If empty,
+	// the hydrate function must be registered with templ_islands.register.
+	Src string
+}
+
+// Island returns a component that's hydrated on the client. The children of the
+// component are rendered on the server inside a wrapper element, and the props
+// are rendered in a JSON script element at the start of the wrapper, which is
+// removed before the island is hydrated.
+//
+// When the page has loaded, the hydrate function is called with the wrapper
+// element and the props, e.g. to render a Preact component into the element:
+//
+//	templ_islands.register("Counter", (el, props) => hydrate(h(Counter, props), el));
+func Island(name string, props any) IslandComponent {
+	return IslandComponent{Name: name, Props: props}
+}
+
+// Module returns an island that imports the hydrate function from the module at
+// the URL.
+func (ic IslandComponent) Module(src string) IslandComponent {
+	ic.Src = src
+	return ic
+}
+
+// islandLoader hydrates the islands of the document, and islands that are added
+// to the document after it has loaded.
+const islandLoader = `(function(){` +
+	`if(window.templ_islands)return;` +
+	`var fns={},ready=false;` +
+	`function hydrate(el){` +
+	`if(el.templ_hydrated)return;` +
+	`var n=el.getAttribute("data-templ-island"),src=el.getAttribute("data-templ-island-src"),f=fns[n];` +
+	`if(!f&&!src)return;` +
+	`el.templ_hydrated=true;` +
+	`var p=el.querySelector(":scope>script[data-templ-island-props]");` +
+	`var props=p?JSON.parse(p.textContent):null;if(p)p.remove();` +
+	`(f?Promise.resolve(f):import(src).then(function(m){return m[n]||m.default;}))` +
+	`.then(function(f){f(el,props);});}` +
+	`function scan(n){` +
+	`if(n.matches&&n.matches("[data-templ-island]"))hydrate(n);` +
+	`if(n.querySelectorAll)n.querySelectorAll("[data-templ-island]").forEach(hydrate);}` +
+	`function start(){ready=true;scan(document);` +
+	`new MutationObserver(function(ms){ms.forEach(function(m){m.addedNodes.forEach(scan);});})` +
+	`.observe(document.documentElement,{childList:true,subtree:true});}` +
+	`window.templ_islands={register:function(n,f){fns[n]=f;if(ready)scan(document);}};` +
+	`if(document.readyState==="loading")document.addEventListener("DOMContentLoaded",start);else start();` +
+	`})();`
+
+func (ic IslandComponent) Render(ctx context.Context, w io.Writer) (err error) {
+	ctx, v := getContext(ctx)
+	if !v.hasScriptBeenRendered("templ_islands") {
+		if err = writeStrings(w, `<script type="text/javascript">`, islandLoader, `</script>`); err != nil {
+			return err
+		}
+		v.addScript("templ_islands")
+	}
+	props, err := marshalJSON(ic.Props)
+	if err != nil {
+		return err
+	}
+	if err = writeStrings(w, `<div data-templ-island="`, EscapeString(ic.Name), `"`); err != nil {
+		return err
+	}
+	if ic.Src != "" {
+		if err = writeStrings(w, ` data-templ-island-src="`, EscapeString(ic.Src), `"`); err != nil {
+			return err
+		}
+	}
+	// The props are rendered inside the wrapper, so that they're available when
+	// the wrapper is added to the document, and belong to a single island even
+	// when islands are rendered by separate requests into the same page.
+	if err = writeStrings(w, `><script type="application/json" data-templ-island-props>`, string(props), `</script>`); err != nil {
+		return err
+	}
+	if err = GetChildren(ctx).Render(ClearChildren(ctx), w); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, `</div>`)
+	return err
+}
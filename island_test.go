@@ -0,0 +1,42 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsland(t *testing.T) {
+	type counterProps struct {
+		Count int `json:"count"`
+	}
+	ctx := templ.InitializeContext(context.Background())
+	ctx = templ.WithChildren(ctx, templ.Raw("<span>1</span>"))
+
+	w := new(strings.Builder)
+	if err := templ.Island("Counter", counterProps{Count: 1}).Render(ctx, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loader, island, ok := strings.Cut(w.String(), "</script>")
+	if !ok || !strings.HasPrefix(loader, `<script type="text/javascript">(function(){`) {
+		t.Fatalf("expected the loader to be rendered first, got %q", w.String())
+	}
+	expected := `<div data-templ-island="Counter"><script type="application/json" data-templ-island-props>{"count":1}</script><span>1</span></div>`
+	if diff := cmp.Diff(expected, island); diff != "" {
+		t.Error(diff)
+	}
+
+	// The loader is only rendered once.
+	w.Reset()
+	ctx = templ.WithChildren(ctx, templ.Raw("<span>1</span>"))
+	if err := templ.Island("Chart", []int{1, 2}).Module("/static/chart.js").Render(ctx, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = `<div data-templ-island="Chart" data-templ-island-src="/static/chart.js"><script type="application/json" data-templ-island-props>[1,2]</script><span>1</span></div>`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !tinygo
+
+package templ
+
+import "encoding/json"
+
+// marshalJSON encodes script parameters, island props and JSONScript values.
+func marshalJSON(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
@@ -0,0 +1,75 @@
+package templ
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAppendJSON(t *testing.T) {
+	values := []any{
+		nil,
+		"",
+		"text",
+		`quotes " and \ backslashes`,
+		"<script>&</script>",
+		"control\n\r\t\b\f\x00\x1f characters",
+		"unicode: ✓ \u2028 \u2029",
+		"invalid \xff utf-8",
+		true,
+		false,
+		0,
+		-42,
+		int8(-8),
+		int16(16),
+		int32(32),
+		int64(math.MinInt64),
+		uint(42),
+		uint8(8),
+		uint16(16),
+		uint32(32),
+		uint64(math.MaxUint64),
+		0.0,
+		1.5,
+		-0.000001,
+		0.0000001,
+		1e21,
+		123456789.123,
+		float32(3.14),
+		float32(1e-7),
+		[]any{1, "two", 3.0, nil, []any{true}},
+		[]any(nil),
+		[]string{"a", "<b>"},
+		[]string(nil),
+		map[string]any{"b": 1, "a": []any{"x"}, "c": map[string]any{"d": nil}},
+		map[string]any(nil),
+		map[string]string{"z": "1", "y": "&"},
+		struct {
+			Name string `json:"name"`
+		}{Name: "struct"},
+		time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	for _, v := range values {
+		expected, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to marshal %#v: %v", v, err)
+		}
+		actual, err := appendJSON(nil, v)
+		if err != nil {
+			t.Fatalf("failed to encode %#v: %v", v, err)
+		}
+		if diff := cmp.Diff(string(expected), string(actual)); diff != "" {
+			t.Errorf("%#v:\n%s", v, diff)
+		}
+	}
+	t.Run("NaN and infinite floats are an error", func(t *testing.T) {
+		for _, v := range []any{math.NaN(), math.Inf(1), float32(math.Inf(-1))} {
+			if _, err := appendJSON(nil, v); err == nil {
+				t.Errorf("%v: expected an error", v)
+			}
+		}
+	})
+}
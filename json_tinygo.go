@@ -0,0 +1,15 @@
+//go:build tinygo
+
+package templ
+
+// marshalJSON encodes script parameters, island props and JSONScript values.
+// TinyGo's reflection support is limited, so common values are encoded
+// without it, see appendJSON.
+//
+// Other values, such as structs, typed slices and maps with other value types,
+// are still encoded with encoding/json, which may fail, or panic, under
+// TinyGo. Convert them to map[string]any or []any values to encode them
+// without reflection.
+func marshalJSON(v any) ([]byte, error) {
+	return appendJSON(nil, v)
+}
@@ -0,0 +1,218 @@
+package templ
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RenderError is returned by generated components if a Go expression panics
+// while a component is rendering. It contains the path of the components that
+// were rendering, e.g. pkg.Page > pkg.Nav > pkg.NavItem[3].
+//
+// Errors returned by components, e.g. by a Go expression, aren't wrapped.
+type RenderError struct {
+	// Path of the components that were rendering, starting with the outermost.
+	Path []PathElement
+	// Err is the PanicError of the innermost component.
+	Err error
+}
+
+func (e *RenderError) Error() string {
+	return "templ: failed to render " + e.ComponentPath() + ": " + e.Err.Error()
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// ComponentPath returns the path of the components, e.g. pkg.Page > pkg.NavItem[3].
+func (e *RenderError) ComponentPath() string {
+	path := make([]string, len(e.Path))
+	for i, pe := range e.Path {
+		path[i] = pe.String()
+	}
+	return strings.Join(path, " > ")
+}
+
+// PathElement is a component in the path of a RenderError.
+type PathElement struct {
+	// Name of the component, e.g. pkg.NavItem.
+	Name string
+	// Index of the iteration of the for loop that rendered the component, or -1
+	// if the component wasn't rendered in a for loop.
+	Index int
+}
+
+func (pe PathElement) String() string {
+	if pe.Index < 0 {
+		return pe.Name
+	}
+	return pe.Name + "[" + strconv.Itoa(pe.Index) + "]"
+}
+
+// ErrorAtIndex is called by generated code when a component rendered in a for
+// loop fails, to record the index of the iteration in the path of the error.
+func ErrorAtIndex(err error, index int) error {
+	re, ok := err.(*RenderError)
+	if !ok || len(re.Path) == 0 {
+		return err
+	}
+	path := slices.Clone(re.Path)
+	path[0].Index = index
+	return &RenderError{Path: path, Err: re.Err}
+}
+
+// PanicError is the Err of a RenderError if a Go expression panics while a
+// component is rendering.
+type PanicError struct {
+	// Value passed to panic.
+	Value any
+	// FileName of the .templ file that was rendering, if known.
+	FileName string
+	// Line of the .templ file that was rendering, if known.
+	Line int
+	// Stack of the goroutine when it panicked.
+	Stack []byte
+	// pcs are the program counters of the stack, used to find the line.
+	pcs []uintptr
+}
+
+func (e *PanicError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("panic at %s:%d: %v", e.FileName, e.Line, e.Value)
+	}
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic, if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// SourceLines maps the lines of a template in a generated _templ.go file to the
+// lines of its .templ file.
+type SourceLines struct {
+	// FileName of the .templ file.
+	FileName string
+	// From and To are the first and last lines of the template in the generated file.
+	From, To int
+	// Lines are pairs of a line of the generated file, and the line of the .templ
+	// file it was generated from, ordered by the line of the generated file.
+	Lines []int
+}
+
+// find returns the line of the .templ file that the innermost frame of generated
+// code was generated from, if the frame is in the template.
+func (sl *SourceLines) find(pcs []uintptr) (line int, ok bool) {
+	if sl == nil || len(pcs) == 0 {
+		return 0, false
+	}
+	generatedFileName := strings.TrimSuffix(filepath.Base(sl.FileName), ".templ") + "_templ.go"
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if strings.HasSuffix(frame.File, "_templ.go") {
+			if sl.FileName != "" && filepath.Base(frame.File) != generatedFileName {
+				return 0, false
+			}
+			return sl.templLine(frame.Line)
+		}
+		if !more {
+			return 0, false
+		}
+	}
+}
+
+// templLine returns the line of the .templ file that the line of the generated
+// file was generated from, if the line is in the template.
+func (sl *SourceLines) templLine(generatedLine int) (line int, ok bool) {
+	if generatedLine < sl.From || generatedLine > sl.To {
+		return 0, false
+	}
+	for i := 0; i+1 < len(sl.Lines) && sl.Lines[i] <= generatedLine; i += 2 {
+		line = sl.Lines[i+1]
+	}
+	return line, line > 0
+}
+
+// newPanicError returns a PanicError for the value passed to panic. It's called
+// by the function that recovers the panic.
+func newPanicError(value any) *PanicError {
+	pe := &PanicError{
+		Value: value,
+		Stack: debug.Stack(),
+		pcs:   make([]uintptr, 64),
+	}
+	// Skip runtime.Callers, newPanicError and the function that recovered.
+	pe.pcs = pe.pcs[:runtime.Callers(3, pe.pcs)]
+	return pe
+}
+
+const panicRecoveryContextKey = contextKeyType(4)
+
+// WithoutPanicRecovery returns a context in which panics in components aren't
+// recovered, so that they crash the program, or are handled by the caller.
+func WithoutPanicRecovery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, panicRecoveryContextKey, true)
+}
+
+// shouldRecover returns false if the value passed to panic should be passed on
+// to the caller, because panic recovery is disabled in the context, or the value
+// is http.ErrAbortHandler, which aborts the response.
+func shouldRecover(ctx context.Context, r any) bool {
+	if r == http.ErrAbortHandler {
+		return false
+	}
+	disabled, _ := ctx.Value(panicRecoveryContextKey).(bool)
+	return !disabled
+}
+
+// RecoverPanic is deferred by generated code. If the component panics, the
+// panic is recovered, and returned as a RenderError that contains a PanicError.
+// If a component that the component rendered panicked, the component is added
+// to the path of the RenderError. Other errors are returned unchanged.
+func RecoverPanic(ctx context.Context, err *error, component string, sl *SourceLines) {
+	if r := recover(); r != nil {
+		if !shouldRecover(ctx, r) {
+			panic(r)
+		}
+		*err = newPanicError(r)
+	}
+	element := PathElement{Name: component, Index: -1}
+	switch e := (*err).(type) {
+	case *PanicError:
+		// The panic was recovered by this component, or by templ.Wrap.
+		*err = &RenderError{Path: []PathElement{element}, Err: sl.withLine(e)}
+	case *RenderError:
+		// The panic may have been in the children of a component, which are
+		// part of this component's template.
+		renderErr := e.Err
+		if pe, ok := e.Err.(*PanicError); ok {
+			renderErr = sl.withLine(pe)
+		}
+		*err = &RenderError{Path: append([]PathElement{element}, e.Path...), Err: renderErr}
+	}
+}
+
+// withLine returns a copy of the PanicError with the line of the .templ file
+// that panicked, if it's in the template, and the line isn't already known.
+func (sl *SourceLines) withLine(pe *PanicError) *PanicError {
+	if pe.Line > 0 {
+		return pe
+	}
+	line, ok := sl.find(pe.pcs)
+	if !ok {
+		return pe
+	}
+	withLine := *pe
+	withLine.FileName, withLine.Line = sl.FileName, line
+	return &withLine
+}
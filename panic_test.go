@@ -0,0 +1,108 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	panicking := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		defer templ.RecoverPanic(ctx, &err, "pkg.panicking", nil)
+		panic("boom")
+	})
+	errFailed := errors.New("failed")
+	failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		defer templ.RecoverPanic(ctx, &err, "pkg.failing", nil)
+		return errFailed
+	})
+	parent := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		defer templ.RecoverPanic(ctx, &err, "pkg.parent", nil)
+		return panicking.Render(ctx, w)
+	})
+
+	t.Run("panics are converted to errors", func(t *testing.T) {
+		err := parent.Render(context.Background(), &strings.Builder{})
+		if diff := cmp.Diff("templ: failed to render pkg.parent > pkg.panicking: panic: boom", err.Error()); diff != "" {
+			t.Error(diff)
+		}
+		var pe *templ.PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("expected a *templ.PanicError, got %T", err)
+		}
+		if !strings.Contains(string(pe.Stack), "panic") {
+			t.Errorf("expected the stack to be captured, got %q", pe.Stack)
+		}
+	})
+	t.Run("errors returned by components aren't wrapped", func(t *testing.T) {
+		err := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			defer templ.RecoverPanic(ctx, &err, "pkg.parent", nil)
+			return failing.Render(ctx, w)
+		}).Render(context.Background(), &strings.Builder{})
+		if err != errFailed {
+			t.Errorf("expected the error to be returned unchanged, got %v", err)
+		}
+	})
+	t.Run("http.ErrAbortHandler isn't recovered", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != http.ErrAbortHandler {
+				t.Errorf("expected http.ErrAbortHandler to be passed on, got %v", r)
+			}
+		}()
+		_ = templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			defer templ.RecoverPanic(ctx, &err, "pkg.aborting", nil)
+			panic(http.ErrAbortHandler)
+		}).Render(context.Background(), &strings.Builder{})
+	})
+	t.Run("panics aren't recovered if recovery is disabled", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected the panic to be passed on, got %v", r)
+			}
+		}()
+		_ = parent.Render(templ.WithoutPanicRecovery(context.Background()), &strings.Builder{})
+	})
+	t.Run("the index of the loop iteration is added to the path", func(t *testing.T) {
+		var itemErr error
+		err := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			defer templ.RecoverPanic(ctx, &err, "pkg.list", nil)
+			for i := 0; i < 5; i++ {
+				if i == 3 {
+					itemErr = panicking.Render(ctx, w)
+					return templ.ErrorAtIndex(itemErr, i)
+				}
+			}
+			return nil
+		}).Render(context.Background(), &strings.Builder{})
+		var re *templ.RenderError
+		if !errors.As(err, &re) {
+			t.Fatalf("expected a *templ.RenderError, got %T", err)
+		}
+		if diff := cmp.Diff("pkg.list > pkg.panicking[3]", re.ComponentPath()); diff != "" {
+			t.Error(diff)
+		}
+		if !errors.As(itemErr, &re) {
+			t.Fatalf("expected a *templ.RenderError, got %T", itemErr)
+		}
+		if diff := cmp.Diff("pkg.panicking", re.ComponentPath()); diff != "" {
+			t.Errorf("expected the error of the item to be unchanged:\n%s", diff)
+		}
+	})
+	t.Run("render errors that are wrapped keep their path", func(t *testing.T) {
+		wrapping := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			defer templ.RecoverPanic(ctx, &err, "pkg.wrapping", nil)
+			return fmt.Errorf("wrapped: %w", panicking.Render(ctx, w))
+		})
+		err := wrapping.Render(context.Background(), &strings.Builder{})
+		if diff := cmp.Diff("wrapped: templ: failed to render pkg.panicking: panic: boom", err.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+)
+
+var blockExpression parse.Parser[Node] = blockExpressionParser{}
+
+type blockExpressionParser struct {
+	mode nodeMode
+}
+
+var blockNameParser = parse.StringFrom(
+	parse.Letter,
+	parse.StringFrom(parse.AtMost(1000, parse.Any(parse.Letter, parse.ZeroToNine, parse.Rune('_')))),
+)
+
+func (p blockExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+	var r BlockExpression
+	start := pi.Index()
+
+	// Strip leading whitespace and look for `block `.
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return r, false, err
+	}
+	if !peekPrefix(pi, "block ") {
+		pi.Seek(start)
+		return r, false, nil
+	}
+	pi.Take(len("block "))
+
+	// Text that starts with "block", e.g. "block of text", isn't a block.
+	from := pi.Position()
+	if r.Name, ok, err = blockNameParser.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return r, false, err
+	}
+	r.NameRange = NewRange(from, pi.Position())
+	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return r, false, err
+	}
+
+	// Node contents.
+	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "block closing brace", p.mode)
+	var nodes Nodes
+	if nodes, ok, err = tnp.Parse(pi); err != nil || !ok {
+		err = parse.Error("block: expected nodes, but none were found", pi.Position())
+		return
+	}
+	r.Children = nodes.Nodes
+
+	// Read the required closing brace.
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		err = parse.Error("block: "+unterminatedMissingEnd, pi.Position())
+		return
+	}
+
+	return r, true, nil
+}
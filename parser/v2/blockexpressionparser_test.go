@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBlockExpressionParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			name: "block: simple",
+			input: `block content {
+					<p>Default</p>
+				}`,
+			expected: BlockExpression{
+				Name: "content",
+				NameRange: Range{
+					From: Position{Index: 6, Line: 0, Col: 6},
+					To:   Position{Index: 13, Line: 0, Col: 13},
+				},
+				Children: []Node{
+					Whitespace{Value: "\t\t\t\t\t"},
+					Element{
+						Name: "p",
+						NameRange: Range{
+							From: Position{Index: 22, Line: 1, Col: 6},
+							To:   Position{Index: 23, Line: 1, Col: 7},
+						},
+						Children: []Node{
+							Text{
+								Range: Range{
+									From: Position{
+										Index: 24,
+										Line:  1,
+										Col:   8,
+									},
+									To: Position{
+										Index: 31,
+										Line:  1,
+										Col:   15,
+									},
+								},
+								Value: "Default",
+							},
+						},
+						TrailingSpace: SpaceVertical,
+					},
+				},
+			},
+		},
+		{
+			name: "block: empty",
+			input: `block page_title {
+}`,
+			expected: BlockExpression{
+				Name: "page_title",
+				NameRange: Range{
+					From: Position{Index: 6, Line: 0, Col: 6},
+					To:   Position{Index: 16, Line: 0, Col: 16},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := blockExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestBlockExpressionParserText(t *testing.T) {
+	for _, input := range []string{
+		`block of text`,
+		`block 1 {`,
+		`blocks {`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			pi := parse.NewInput(input)
+			_, ok, err := blockExpression.Parse(pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Fatal("expected text not to be parsed as a block")
+			}
+			if pi.Index() != 0 {
+				t.Errorf("expected the input not to be consumed, but the index is %d", pi.Index())
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+var branchStatement parse.Parser[Node] = branchStatementParser{}
+
+type branchStatementParser struct{}
+
+// branchKeywords are the keywords of the statements that stop a for loop, or the
+// rendering of a template.
+var branchKeywords = []string{"break", "continue", "return"}
+
+// labelParser parses a Go identifier, used as the label of a for loop.
+var labelParser = parse.StringFrom(
+	parse.Any(parse.Letter, parse.Rune('_')),
+	parse.StringFrom(parse.AtMost(1000, parse.Any(parse.Letter, parse.ZeroToNine, parse.Rune('_')))),
+)
+
+// horizontalWhitespace parses spaces and tabs.
+var horizontalWhitespace = parse.StringFrom(parse.OneOrMore(parse.RuneIn(" \t")))
+
+// atStartOfLine returns true if the line only contains spaces and tabs before
+// the current position.
+func atStartOfLine(pi *parse.Input) bool {
+	pos := pi.Position()
+	pi.Seek(pos.Index - pos.Col)
+	before, _ := pi.Peek(pos.Col)
+	pi.Seek(pos.Index)
+	return strings.TrimLeft(before, " \t") == ""
+}
+
+// atEndOfLine returns true if the rest of the line is empty, or only contains
+// spaces and tabs.
+func atEndOfLine(pi *parse.Input) bool {
+	rest, _ := pi.Peek(-1)
+	rest = strings.TrimLeft(rest, " \t")
+	return rest == "" || strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n")
+}
+
+func (branchStatementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+	var r BranchStatement
+	start := pi.Index()
+
+	// Strip leading whitespace and look for the keyword.
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return r, false, err
+	}
+	for _, keyword := range branchKeywords {
+		if peekPrefix(pi, keyword) {
+			r.Keyword = keyword
+			break
+		}
+	}
+	// Text that contains a keyword, e.g. "return to the home page", isn't a
+	// statement, so the statement must be on its own line.
+	if r.Keyword == "" || !atStartOfLine(pi) {
+		pi.Seek(start)
+		return r, false, nil
+	}
+	from := pi.Position()
+	pi.Take(len(r.Keyword))
+
+	// break and continue can have a label.
+	if r.Keyword != "return" {
+		labelStart := pi.Index()
+		if _, ok, _ = horizontalWhitespace.Parse(pi); ok {
+			if r.Label, ok, _ = labelParser.Parse(pi); !ok {
+				pi.Seek(labelStart)
+			}
+		}
+	}
+	to := pi.Position()
+	if !atEndOfLine(pi) {
+		pi.Seek(start)
+		return r, false, nil
+	}
+	r.Range = NewRange(from, to)
+
+	return r, true, nil
+}
+
+// branchScope is the Go code that a break, continue or return statement is
+// within.
+type branchScope struct {
+	// labels of the enclosing for loops.
+	labels []string
+	// inFor and inSwitch are true within a for loop, or a switch statement.
+	inFor, inSwitch bool
+	// inFunc is true within the children of a component, or a block, which are
+	// rendered by a function, so return doesn't stop the template.
+	inFunc bool
+}
+
+// resolveBranchStatements checks the break, continue and return statements of
+// the nodes, and returns the nodes with the break and continue statements that
+// have a label that isn't the label of an enclosing for loop replaced with
+// text, since text such as "continue reading" isn't a statement.
+func resolveBranchStatements(pi *parse.Input, nodes []Node, scope branchScope) (resolved []Node, err error) {
+	resolved = nodes[:0]
+	for i := 0; i < len(nodes); i++ {
+		bs, ok := nodes[i].(BranchStatement)
+		if !ok {
+			n, err := resolveBranchStatementsInNode(pi, nodes[i], scope)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, n)
+			continue
+		}
+		if bs.Label == "" || slices.Contains(scope.labels, bs.Label) {
+			if err = checkBranchStatement(bs, scope); err != nil {
+				return nil, parse.Error(err.Error(), pi.PositionAt(int(bs.Range.From.Index)))
+			}
+			resolved = append(resolved, bs)
+			continue
+		}
+		t := Text{Range: bs.Range, Value: sourceText(pi, bs.Range)}
+		// The whitespace after the text is its trailing space.
+		if i+1 < len(nodes) {
+			if ws, ok := nodes[i+1].(Whitespace); ok {
+				t.TrailingSpace, _ = NewTrailingSpace(ws.Value)
+				i++
+			}
+		}
+		resolved = append(resolved, t)
+	}
+	return resolved, nil
+}
+
+// checkBranchStatement returns an error if the statement can't be used in the
+// scope, since the generated code wouldn't compile, or wouldn't stop the
+// template.
+func checkBranchStatement(bs BranchStatement, scope branchScope) error {
+	switch {
+	case bs.Keyword == "return" && scope.inFunc:
+		return errors.New("return: can't be used within the children of a component, or a block, which are rendered by a function")
+	case bs.Keyword == "break" && !scope.inFor && !scope.inSwitch:
+		return errors.New("break: not within a for loop or switch statement")
+	case bs.Keyword == "continue" && !scope.inFor:
+		return errors.New("continue: not within a for loop")
+	}
+	return nil
+}
+
+func resolveBranchStatementsInNode(pi *parse.Input, n Node, scope branchScope) (_ Node, err error) {
+	switch n := n.(type) {
+	case Element:
+		n.Children, err = resolveBranchStatements(pi, n.Children, scope)
+		return n, err
+	case TemplElementExpression:
+		// Children are rendered by a function, which can't branch to the loops
+		// around it.
+		n.Children, err = resolveBranchStatements(pi, n.Children, branchScope{inFunc: true})
+		return n, err
+	case BlockExpression:
+		n.Children, err = resolveBranchStatements(pi, n.Children, branchScope{inFunc: true})
+		return n, err
+	case IfExpression:
+		if n.Then, err = resolveBranchStatements(pi, n.Then, scope); err != nil {
+			return n, err
+		}
+		for i := range n.ElseIfs {
+			if n.ElseIfs[i].Then, err = resolveBranchStatements(pi, n.ElseIfs[i].Then, scope); err != nil {
+				return n, err
+			}
+		}
+		n.Else, err = resolveBranchStatements(pi, n.Else, scope)
+		return n, err
+	case SwitchExpression:
+		scope.inSwitch = true
+		for i := range n.Cases {
+			if n.Cases[i].Children, err = resolveBranchStatements(pi, n.Cases[i].Children, scope); err != nil {
+				return n, err
+			}
+		}
+		return n, nil
+	case ForExpression:
+		scope.inFor = true
+		if n.Label != "" {
+			scope.labels = append(scope.labels[:len(scope.labels):len(scope.labels)], n.Label)
+		}
+		n.Children, err = resolveBranchStatements(pi, n.Children, scope)
+		return n, err
+	}
+	return n, nil
+}
+
+// sourceText returns the source of the range.
+func sourceText(pi *parse.Input, r Range) string {
+	current := pi.Index()
+	defer pi.Seek(current)
+	pi.Seek(int(r.From.Index))
+	s, _ := pi.Take(int(r.To.Index - r.From.Index))
+	return s
+}
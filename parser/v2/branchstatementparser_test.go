@@ -0,0 +1,321 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBranchStatementParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected BranchStatement
+	}{
+		{
+			name:  "break",
+			input: "break\n",
+			expected: BranchStatement{
+				Keyword: "break",
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 5, Line: 0, Col: 5},
+				},
+			},
+		},
+		{
+			name:  "continue with a label",
+			input: "\t\tcontinue  outer \n",
+			expected: BranchStatement{
+				Keyword: "continue",
+				Label:   "outer",
+				Range: Range{
+					From: Position{Index: 2, Line: 0, Col: 2},
+					To:   Position{Index: 17, Line: 0, Col: 17},
+				},
+			},
+		},
+		{
+			name:  "return at the end of the input",
+			input: "return",
+			expected: BranchStatement{
+				Keyword: "return",
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 6, Line: 0, Col: 6},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := branchStatement.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestBranchStatementParserText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// from is the index of the input that the parser starts at.
+		from int
+	}{
+		{
+			name:  "text that starts with a keyword",
+			input: "return to the home page\n",
+		},
+		{
+			name:  "word that starts with a keyword",
+			input: "breakfast\n",
+		},
+		{
+			name:  "return with a label",
+			input: "return outer\n",
+		},
+		{
+			name:  "keyword after other content on the line",
+			input: "<b>Note:</b> continue\n",
+			from:  len("<b>Note:</b>"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			input.Seek(tt.from)
+			_, ok, err := branchStatement.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Fatalf("unexpected statement for input %q", tt.input)
+			}
+			if input.Index() != tt.from {
+				t.Errorf("expected the input to be at %d, got %d", tt.from, input.Index())
+			}
+		})
+	}
+}
+
+func TestBranchStatementsOnlyInControlFlow(t *testing.T) {
+	tf, err := ParseString(`package p
+
+templ list(items []string) {
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+		<a href="/next">
+			continue
+		</a>
+	}
+}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var statements []string
+	var text []string
+	Inspect(tf, func(n any) bool {
+		switch n := n.(type) {
+		case BranchStatement:
+			statements = append(statements, n.String())
+		case Text:
+			text = append(text, n.Value)
+		}
+		return true
+	})
+	if diff := cmp.Diff([]string{"continue"}, statements); diff != "" {
+		t.Errorf("unexpected statements:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"continue"}, text); diff != "" {
+		t.Errorf("unexpected text:\n%s", diff)
+	}
+}
+
+func TestBranchStatementLabels(t *testing.T) {
+	tf, err := ParseString(`package p
+
+templ list(groups [][]string) {
+	outer: for _, items := range groups {
+		for _, item := range items {
+			if item == "" {
+				continue outer
+			}
+			continue reading
+			<p>{ item }</p>
+		}
+	}
+	if len(groups) == 0 {
+		break outer
+	}
+}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var statements []string
+	var text []Text
+	Inspect(tf, func(n any) bool {
+		switch n := n.(type) {
+		case BranchStatement:
+			statements = append(statements, n.String())
+		case Text:
+			text = append(text, n)
+		}
+		return true
+	})
+	if diff := cmp.Diff([]string{"continue outer"}, statements); diff != "" {
+		t.Errorf("unexpected statements:\n%s", diff)
+	}
+	expected := []Text{
+		{
+			Value: "continue reading",
+			Range: Range{
+				From: Position{Index: 159, Line: 8, Col: 3},
+				To:   Position{Index: 175, Line: 8, Col: 19},
+			},
+			TrailingSpace: SpaceVertical,
+		},
+		{
+			Value: "break outer",
+			Range: Range{
+				From: Position{Index: 227, Line: 13, Col: 2},
+				To:   Position{Index: 238, Line: 13, Col: 13},
+			},
+			TrailingSpace: SpaceVertical,
+		},
+	}
+	if diff := cmp.Diff(expected, text); diff != "" {
+		t.Errorf("unexpected text:\n%s", diff)
+	}
+}
+
+func TestBranchStatementErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "break outside a loop",
+			input: `package p
+
+templ page(ok bool) {
+	if ok {
+		break
+	}
+}
+`,
+			expected: "break: not within a for loop or switch statement: line 4, col 2",
+		},
+		{
+			name: "continue within a switch",
+			input: `package p
+
+templ page(v int) {
+	switch v {
+		case 1:
+			continue
+	}
+}
+`,
+			expected: "continue: not within a for loop: line 5, col 3",
+		},
+		{
+			name: "return within the children of a component",
+			input: `package p
+
+templ page(ok bool) {
+	@layout() {
+		if ok {
+			return
+		}
+	}
+}
+`,
+			expected: "return: can't be used within the children of a component, or a block, which are rendered by a function: line 5, col 3",
+		},
+		{
+			name: "break within the children of a component within a loop",
+			input: `package p
+
+templ page(items []string) {
+	for _, item := range items {
+		@layout() {
+			if item == "" {
+				break
+			}
+		}
+	}
+}
+`,
+			expected: "break: not within a for loop or switch statement: line 6, col 4",
+		},
+		{
+			name: "return within a block",
+			input: `package p
+
+templ page(ok bool) {
+	block content {
+		if ok {
+			return
+		}
+	}
+}
+`,
+			expected: "return: can't be used within the children of a component, or a block, which are rendered by a function: line 5, col 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseString(tt.input)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if diff := cmp.Diff(tt.expected, err.Error()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestBranchStatementsWithinSwitchAndLoops(t *testing.T) {
+	_, err := ParseString(`package p
+
+templ page(v int, items []string) {
+	switch v {
+		case 1:
+			if len(items) == 0 {
+				break
+			}
+			return
+	}
+	for _, item := range items {
+		@layout() {
+			for range 3 {
+				if item == "" {
+					continue
+				}
+			}
+		}
+	}
+}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
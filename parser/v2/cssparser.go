@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/a-h/parse"
 )
 
@@ -20,9 +22,30 @@ var cssParser = parse.Func(func(pi *parse.Input) (r CSSTemplate, ok bool, err er
 	r.Name = exp.Name
 	r.Expression = exp.Expression
 
+	if r.Properties, ok, err = parseCSSProperties(pi); err != nil || !ok {
+		return
+	}
+	return r, true, nil
+})
+
+// parseCSSProperties parses CSS properties and nested rules, up to and including
+// the closing brace.
+func parseCSSProperties(pi *parse.Input) (properties []CSSProperty, ok bool, err error) {
+	properties = []CSSProperty{}
 	for {
 		var cssProperty CSSProperty
 
+		// Try for a nested rule.
+		// &:hover {
+		cssProperty, ok, err = cssRuleParser{}.Parse(pi)
+		if err != nil {
+			return
+		}
+		if ok {
+			properties = append(properties, cssProperty)
+			continue
+		}
+
 		// Try for an expression CSS declaration.
 		// background-color: { constants.BackgroundColor };
 		cssProperty, ok, err = expressionCSSPropertyParser.Parse(pi)
@@ -30,7 +53,7 @@ var cssParser = parse.Func(func(pi *parse.Input) (r CSSTemplate, ok bool, err er
 			return
 		}
 		if ok {
-			r.Properties = append(r.Properties, cssProperty)
+			properties = append(properties, cssProperty)
 			continue
 		}
 
@@ -41,7 +64,7 @@ var cssParser = parse.Func(func(pi *parse.Input) (r CSSTemplate, ok bool, err er
 			return
 		}
 		if ok {
-			r.Properties = append(r.Properties, cssProperty)
+			properties = append(properties, cssProperty)
 			continue
 		}
 
@@ -56,9 +79,46 @@ var cssParser = parse.Func(func(pi *parse.Input) (r CSSTemplate, ok bool, err er
 			return
 		}
 
-		return r, true, nil
+		return properties, true, nil
 	}
-})
+}
+
+// cssRuleParser parses a rule nested in a CSS component, e.g. a pseudo-class,
+// a child selector, or a media query.
+//
+//	&:hover {
+//	@media (min-width: 600px) {
+type cssRuleParser struct{}
+
+func (cssRuleParser) Parse(pi *parse.Input) (r CSSRule, ok bool, err error) {
+	start := pi.Index()
+
+	// Optional whitespace.
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	// The selector is the rest of the line, which ends with an open brace.
+	var line string
+	if line, ok, err = parse.StringUntil(parse.NewLine).Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return r, false, err
+	}
+	line = strings.TrimSpace(line)
+	if !strings.HasSuffix(line, "{") || line == "{" || isCSSExpressionPropertyStart(line) {
+		pi.Seek(start)
+		return r, false, nil
+	}
+	r.Selector = strings.TrimSpace(strings.TrimSuffix(line, "{"))
+	// \n
+	if _, _, err = parse.NewLine.Parse(pi); err != nil {
+		return
+	}
+
+	if r.Properties, ok, err = parseCSSProperties(pi); err != nil || !ok {
+		return
+	}
+	return r, true, nil
+}
 
 // css Func() {
 type cssExpression struct {
@@ -91,6 +151,17 @@ var cssExpressionParser = parse.Func(func(pi *parse.Input) (r cssExpression, ok
 	return r, true, nil
 })
 
+// isCSSExpressionPropertyStart returns true if the line is the start of an
+// expression property that spans lines, e.g. "color: {".
+func isCSSExpressionPropertyStart(line string) bool {
+	name, value, ok := strings.Cut(line, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.TrimSpace(value) != "{" {
+		return false
+	}
+	return strings.Trim(name, cssPropertyNameSubsequent) == ""
+}
+
 // CSS property name parser.
 var cssPropertyNameFirst = "abcdefghijklmnopqrstuvwxyz-"
 var cssPropertyNameSubsequent = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-"
@@ -167,7 +238,9 @@ var constantCSSPropertyParser = parse.Func(func(pi *parse.Input) (r ConstantCSSP
 		return
 	}
 	// <space>:<space>
-	if _, ok, err = parse.All(parse.OptionalWhitespace, parse.Rune(':'), parse.OptionalWhitespace).Parse(pi); err != nil || !ok {
+	// Line breaks after the colon are part of the value, so that the value isn't
+	// moved to the line of the name when it's formatted.
+	if _, ok, err = parse.All(parse.OptionalWhitespace, parse.Rune(':'), parse.StringUntil(parse.RuneNotIn(" \t"))).Parse(pi); err != nil || !ok {
 		pi.Seek(start)
 		return
 	}
@@ -205,6 +205,72 @@ background-color: #ffffff;
 				},
 			},
 		},
+		{
+			name: "css: nested rules",
+			input: `css Name() {
+color: #000000;
+&:hover {
+color: #ff0000;
+}
+@media (min-width: 600px) {
+font-size: 2em;
+> li {
+display: inline;
+}
+}
+}`,
+			expected: CSSTemplate{
+				Name: "Name",
+				Expression: Expression{
+					Value: "Name()",
+					Range: Range{
+						From: Position{
+							Index: 4,
+							Line:  0,
+							Col:   4,
+						},
+						To: Position{
+							Index: 10,
+							Line:  0,
+							Col:   10,
+						},
+					},
+				},
+				Properties: []CSSProperty{
+					ConstantCSSProperty{
+						Name:  "color",
+						Value: "#000000",
+					},
+					CSSRule{
+						Selector: "&:hover",
+						Properties: []CSSProperty{
+							ConstantCSSProperty{
+								Name:  "color",
+								Value: "#ff0000",
+							},
+						},
+					},
+					CSSRule{
+						Selector: "@media (min-width: 600px)",
+						Properties: []CSSProperty{
+							ConstantCSSProperty{
+								Name:  "font-size",
+								Value: "2em",
+							},
+							CSSRule{
+								Selector: "> li",
+								Properties: []CSSProperty{
+									ConstantCSSProperty{
+										Name:  "display",
+										Value: "inline",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "css: single expression property",
 			input: `css Name() {
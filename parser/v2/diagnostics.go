@@ -1,6 +1,11 @@
 package parser
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
 
 type diagnoser func(Node) ([]Diagnostic, error)
 
@@ -11,28 +16,18 @@ type Diagnostic struct {
 }
 
 func walkTemplate(t TemplateFile, f func(Node) bool) {
-	for _, n := range t.Nodes {
-		hn, ok := n.(HTMLTemplate)
-		if !ok {
-			continue
+	Inspect(t, func(n any) bool {
+		if n, ok := n.(Node); ok {
+			return f(n)
 		}
-		walkNodes(hn.Children, f)
-	}
-}
-func walkNodes(t []Node, f func(Node) bool) {
-	for _, n := range t {
-		if !f(n) {
-			continue
-		}
-		if h, ok := n.(CompositeNode); ok {
-			walkNodes(h.ChildNodes(), f)
-		}
-	}
+		return true
+	})
 }
 
 func Diagnose(t TemplateFile) ([]Diagnostic, error) {
 	diagnosers := []diagnoser{
 		legacyCallSyntaxDiagnoser,
+		newChildrenDiagnoser(t),
 	}
 	var diags []Diagnostic
 	var errs error
@@ -59,3 +54,101 @@ func legacyCallSyntaxDiagnoser(n Node) ([]Diagnostic, error) {
 	}
 	return nil, nil
 }
+
+// newChildrenDiagnoser returns a diagnoser that checks that the templates of
+// the file that are called with children render them. Templates render their
+// children with { children... }, or get them with templ.GetChildren(ctx).
+//
+// Templates that render children may be called without them, e.g. a layout
+// that's optional, so that isn't reported.
+func newChildrenDiagnoser(t TemplateFile) diagnoser {
+	rendersChildren := map[string]bool{}
+	for _, n := range t.Nodes {
+		ht, ok := n.(HTMLTemplate)
+		if !ok {
+			continue
+		}
+		name := templateFuncName(ht.Expression.Value)
+		if name == "" {
+			continue
+		}
+		var renders bool
+		Inspect(ht, func(n any) bool {
+			if _, ok := n.(ChildrenExpression); ok {
+				renders = true
+			}
+			if strings.Contains(nodeExpression(n), "templ.GetChildren(") {
+				renders = true
+			}
+			return !renders
+		})
+		rendersChildren[name] = renders
+	}
+	return func(n Node) ([]Diagnostic, error) {
+		e, ok := n.(TemplElementExpression)
+		if !ok {
+			return nil, nil
+		}
+		name := templateFuncName(e.Expression.Value)
+		renders, ok := rendersChildren[name]
+		if !ok {
+			return nil, nil
+		}
+		if len(e.Children) > 0 && !renders {
+			return []Diagnostic{{
+				Message: fmt.Sprintf("`%s` doesn't render `{ children... }`, so the children passed to it aren't rendered.", name),
+				Range:   e.Expression.Range,
+			}}, nil
+		}
+		return nil, nil
+	}
+}
+
+// nodeExpression returns the Go expression of the node, e.g. the condition of
+// an IfExpression, or an empty string if it has none.
+func nodeExpression(n any) string {
+	switch n := n.(type) {
+	case StringExpression:
+		return n.Expression.Value
+	case TemplElementExpression:
+		return n.Expression.Value
+	case CallTemplateExpression:
+		return n.Expression.Value
+	case IfExpression:
+		return n.Expression.Value
+	case ElseIfExpression:
+		return n.Expression.Value
+	case SwitchExpression:
+		return n.Expression.Value
+	case CaseExpression:
+		return n.Expression.Value
+	case ForExpression:
+		return n.Expression.Value
+	case ExpressionAttribute:
+		return n.Expression.Value
+	case BoolExpressionAttribute:
+		return n.Expression.Value
+	case SpreadAttributes:
+		return n.Expression.Value
+	case ConditionalAttribute:
+		return n.Expression.Value
+	}
+	return ""
+}
+
+// templateFuncName returns the name of the function that is declared, or
+// called, by the expression. Methods, and calls to functions of other packages,
+// return an empty string.
+func templateFuncName(expr string) string {
+	end := strings.IndexAny(expr, "([")
+	if end <= 0 {
+		return ""
+	}
+	name := strings.TrimSpace(expr[:end])
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return ""
+		}
+	}
+	return name
+}
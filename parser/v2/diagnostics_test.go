@@ -122,6 +122,97 @@ templ template () {
 				Range:   Range{Position{59, 5, 5}, Position{75, 5, 21}},
 			}},
 		},
+
+		// childrenDiagnoser
+
+		{
+			name: "childrenDiagnoser: children are passed to a template that renders them",
+			template: `
+package main
+
+templ layout() {
+	<main>{ children... }</main>
+}
+
+templ page() {
+	@layout() {
+		<p>Hello</p>
+	}
+}`,
+			want: nil,
+		},
+		{
+			name: "childrenDiagnoser: children are passed to a template that doesn't render them",
+			template: `
+package main
+
+templ heading(title string) {
+	<h1>{ title }</h1>
+}
+
+templ page() {
+	@heading("Home") {
+		<p>Hello</p>
+	}
+}`,
+			want: []Diagnostic{{
+				Message: "`heading` doesn't render `{ children... }`, so the children passed to it aren't rendered.",
+				Range:   Range{Position{85, 8, 2}, Position{100, 8, 17}},
+			}},
+		},
+		{
+			name: "childrenDiagnoser: templates that render children can be called without them",
+			template: `
+package main
+
+templ layout() {
+	if true {
+		<main>{ children... }</main>
+	}
+}
+
+templ page() {
+	@layout()
+}`,
+			want: nil,
+		},
+		{
+			name: "childrenDiagnoser: templates that get their children with templ.GetChildren render them",
+			template: `
+package main
+
+templ card() {
+	if children := templ.GetChildren(ctx); children != nil {
+		<div class="card">
+			@children
+		</div>
+	}
+}
+
+templ page() {
+	@card() {
+		<p>Hello</p>
+	}
+}`,
+			want: nil,
+		},
+		{
+			name: "childrenDiagnoser: templates of other packages aren't checked",
+			template: `
+package main
+
+templ layout() {
+	<main>{ children... }</main>
+}
+
+templ page() {
+	@components.layout()
+	@components.heading("Home") {
+		<p>Hello</p>
+	}
+}`,
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,28 @@
+// Package parser parses templ files into a syntax tree, and prints the tree as
+// formatted templ source. It's used by templ generate, templ fmt and the templ
+// LSP, and can be used to build other tools, e.g. linters and codemods.
+//
+// ParseString and Parse return a TemplateFile. Its Nodes are the templates, CSS
+// templates, script templates and Go code of the file, and the contents of the
+// templates are Nodes, e.g. Element, IfExpression and StringExpression.
+//
+// Walk and Inspect traverse the tree. NodeRange returns the position of a node
+// in the source, and Print writes a node as formatted templ source:
+//
+//	tf, err := parser.ParseString(src)
+//	if err != nil {
+//		return err
+//	}
+//	parser.Inspect(tf, func(n any) bool {
+//		if e, ok := n.(parser.Element); ok && e.Name == "img" {
+//			r, _ := parser.NodeRange(e)
+//			fmt.Printf("<img> at %v\n", r.From)
+//		}
+//		return true
+//	})
+//
+// The syntax tree follows the versioning of the templ module: exported types,
+// fields and functions aren't removed or changed in a minor release, but new
+// node types and fields may be added, so type switches over nodes should have
+// a default case.
+package parser
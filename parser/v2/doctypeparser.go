@@ -30,3 +30,26 @@ var docType = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
 
 	return r, true, nil
 })
+
+var xmlDeclarationStartParser = parse.String("<?xml ")
+var xmlDeclarationEndParser = parse.String("?>")
+
+var xmlDeclaration = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Position()
+	var r XMLDeclaration
+	if _, ok, err = xmlDeclarationStartParser.Parse(pi); err != nil || !ok {
+		return
+	}
+
+	// Once a declaration has started, take everything until the end.
+	if r.Value, ok, err = parse.StringUntil(xmlDeclarationEndParser).Parse(pi); err != nil || !ok {
+		err = parse.Error("unclosed XML declaration", start)
+		return
+	}
+	if _, ok, err = xmlDeclarationEndParser.Parse(pi); err != nil || !ok {
+		err = parse.Error("unclosed XML declaration", start)
+		return
+	}
+
+	return r, true, nil
+})
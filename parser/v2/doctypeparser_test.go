@@ -99,3 +99,48 @@ func TestDocTypeParserErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestXMLDeclarationParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected XMLDeclaration
+	}{
+		{
+			name:  "version",
+			input: `<?xml version="1.0"?>`,
+			expected: XMLDeclaration{
+				Value: `version="1.0"`,
+			},
+		},
+		{
+			name:  "version and encoding",
+			input: `<?xml version="1.0" encoding="UTF-8"?>`,
+			expected: XMLDeclaration{
+				Value: `version="1.0" encoding="UTF-8"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := xmlDeclaration.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+	t.Run("unclosed declarations are an error", func(t *testing.T) {
+		_, _, err := xmlDeclaration.Parse(parse.NewInput(`<?xml version="1.0"`))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -273,6 +273,50 @@ var expressionAttributeParser = parse.Func(func(pi *parse.Input) (attr Expressio
 	return attr, true, nil
 })
 
+var keyExpressionAttributeParser = parse.Func(func(pi *parse.Input) (attr KeyExpressionAttribute, ok bool, err error) {
+	start := pi.Index()
+
+	// Optional whitespace leader.
+	if _, ok, err = parse.OptionalWhitespace.Parse(pi); err != nil || !ok {
+		return
+	}
+
+	// Eat the first brace.
+	if _, ok, err = openBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+
+	// Key expression.
+	if attr.Key, err = parseGo("attribute key", pi, goexpression.Expression); err != nil {
+		return
+	}
+
+	// }={
+	// Spread attributes, e.g. { attrs... }, aren't followed by "=".
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+	if _, ok, err = parse.Or(parse.String("={ "), parse.String("={")).Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return
+	}
+
+	// Value expression.
+	if attr.Expression, err = parseGo("attribute value", pi, goexpression.Expression); err != nil {
+		return attr, false, err
+	}
+
+	// Eat the final brace.
+	if _, ok, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !ok {
+		err = parse.Error("key expression attribute: missing closing brace", pi.Position())
+		return
+	}
+
+	return attr, true, nil
+})
+
 var spreadAttributesParser = parse.Func(func(pi *parse.Input) (attr SpreadAttributes, ok bool, err error) {
 	start := pi.Index()
 
@@ -330,6 +374,9 @@ func (attributeParser) Parse(in *parse.Input) (out Attribute, ok bool, err error
 	if out, ok, err = boolConstantAttributeParser.Parse(in); err != nil || ok {
 		return
 	}
+	if out, ok, err = keyExpressionAttributeParser.Parse(in); err != nil || ok {
+		return
+	}
 	if out, ok, err = spreadAttributesParser.Parse(in); err != nil || ok {
 		return
 	}
@@ -359,9 +406,11 @@ func (attributesParser) Parse(in *parse.Input) (attributes []Attribute, ok bool,
 }
 
 // Element name.
+// Custom element names may also contain "." and "_".
+// https://html.spec.whatwg.org/multipage/custom-elements.html#valid-custom-element-name
 var (
 	elementNameFirst      = "abcdefghijklmnopqrstuvwxyz"
-	elementNameSubsequent = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-"
+	elementNameSubsequent = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-._"
 	elementNameParser     = parse.Func(func(in *parse.Input) (name string, ok bool, err error) {
 		start := in.Index()
 		var prefix, suffix string
@@ -384,9 +433,13 @@ var (
 // Element.
 var elementOpenClose elementOpenCloseParser
 
-type elementOpenCloseParser struct{}
+type elementOpenCloseParser struct {
+	// preformatted is true if the element is within a preformatted element, e.g. <pre>.
+	preformatted bool
+	mode         nodeMode
+}
 
-func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err error) {
+func (p elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err error) {
 	// Check the open tag.
 	var ot elementOpenTag
 	if ot, ok, err = elementOpenTagParser.Parse(pi); err != nil || !ok {
@@ -397,10 +450,32 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 	r.IndentAttrs = ot.IndentAttrs
 	r.NameRange = ot.NameRange
 
+	// Void elements can't have children, so the end tag is optional, e.g. <br> or <br></br>.
+	if r.IsVoidElement() && !p.mode.xml {
+		return parseVoidElementEnd(pi, r)
+	}
+
+	// Whitespace is significant within preformatted elements, so the children are kept as-is.
+	if p.preformatted || r.IsPreformattedElement() {
+		return parsePreformattedElementEnd(pi, r)
+	}
+
+	// Elements with optional end tags are closed by the start tag of some
+	// elements, e.g. <li> is closed by the next <li>, or by the end tag of
+	// the parent element.
+	_, hasOptionalEndTag := optionalEndTagClosers[r.Name]
+	hasOptionalEndTag = hasOptionalEndTag && p.mode.optionalEndTags && !p.mode.xml
+
 	// Once we've got an open tag, the rest must be present.
 	l := pi.Position().Line
+	contentStart := pi.Index()
 	var nodes Nodes
-	if nodes, ok, err = newTemplateNodeParser[any](nil, "").Parse(pi); err != nil || !ok {
+	if hasOptionalEndTag {
+		nodes, ok, err = newTemplateNodeParser(impliedEndTag(r.Name), "</"+r.Name+">", p.mode).Parse(pi)
+	} else {
+		nodes, ok, err = newTemplateNodeParser[any](nil, "", p.mode).Parse(pi)
+	}
+	if err != nil || !ok {
 		return
 	}
 	r.Children = nodes.Nodes
@@ -416,6 +491,10 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 	if err != nil {
 		return
 	}
+	if hasOptionalEndTag && (!ok || ct.Name != r.Name) {
+		pi.Seek(pos.Index)
+		return closeImplicitly(pi, r, l, contentStart), true, nil
+	}
 	if !ok {
 		err = parse.Error(fmt.Sprintf("<%s>: expected end tag not present or invalid tag contents", r.Name), pi.Position())
 		return
@@ -438,6 +517,123 @@ func (elementOpenCloseParser) Parse(pi *parse.Input) (r Element, ok bool, err er
 	return r, true, nil
 }
 
+// parsePreformattedElementEnd parses the children of a preformatted element, its end tag, and
+// its trailing whitespace.
+func parsePreformattedElementEnd(pi *parse.Input, r Element) (Element, bool, error) {
+	nodes, _, err := preformattedNodes.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.Children = nodes.Nodes
+
+	pos := pi.Position()
+	ct, ok, err := elementCloseTagParser.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	if !ok {
+		return r, false, parse.Error(fmt.Sprintf("<%s>: expected end tag not present or invalid tag contents", r.Name), pi.Position())
+	}
+	if ct.Name != r.Name {
+		return r, false, parse.Error(fmt.Sprintf("<%s>: mismatched end tag, expected '</%s>', got '</%s>'", r.Name, r.Name, ct.Name), pos)
+	}
+
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return r, false, err
+	}
+	return r, true, nil
+}
+
+// parseVoidElementEnd parses the optional end tag of a void element, and its trailing whitespace.
+func parseVoidElementEnd(pi *parse.Input, r Element) (Element, bool, error) {
+	start := pi.Index()
+	if _, _, err := parse.OptionalWhitespace.Parse(pi); err != nil {
+		return r, false, err
+	}
+	ct, ok, err := elementCloseTagParser.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	if !ok || ct.Name != r.Name {
+		pi.Seek(start)
+	}
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return r, false, err
+	}
+	return r, true, nil
+}
+
+// optionalEndTagClosers maps elements with optional end tags to the start tags that close them.
+// Unlike browsers, block elements such as <div> don't close a <p>, so that templates that
+// contain a <div> within a <p> continue to work. templ generate -strict reports them.
+// https://html.spec.whatwg.org/multipage/syntax.html#optional-tags
+var optionalEndTagClosers = map[string]map[string]struct{}{
+	"li":       {"li": {}},
+	"dt":       {"dt": {}, "dd": {}},
+	"dd":       {"dt": {}, "dd": {}},
+	"p":        {"p": {}},
+	"rt":       {"rt": {}, "rp": {}},
+	"rp":       {"rt": {}, "rp": {}},
+	"optgroup": {"optgroup": {}},
+	"option":   {"option": {}, "optgroup": {}},
+	"thead":    {"tbody": {}, "tfoot": {}},
+	"tbody":    {"tbody": {}, "tfoot": {}},
+	"tfoot":    {},
+	"tr":       {"tr": {}, "tbody": {}, "tfoot": {}},
+	"td":       {"td": {}, "th": {}, "tr": {}, "tbody": {}, "tfoot": {}},
+	"th":       {"td": {}, "th": {}, "tr": {}, "tbody": {}, "tfoot": {}},
+}
+
+// impliedEndTag matches the position at which an element with an optional end tag
+// is closed: an end tag, the end of a templ block, or a start tag that closes it.
+func impliedEndTag(name string) parse.Parser[bool] {
+	closers := optionalEndTagClosers[name]
+	return parse.Func(func(pi *parse.Input) (_ bool, ok bool, err error) {
+		start := pi.Index()
+		defer pi.Seek(start)
+		if s, _ := pi.Peek(2); s == "</" || strings.HasPrefix(s, "}") {
+			return true, true, nil
+		}
+		if _, ok, err = lt.Parse(pi); err != nil || !ok {
+			return false, false, err
+		}
+		var next string
+		if next, ok, err = elementNameParser.Parse(pi); err != nil || !ok {
+			return false, false, err
+		}
+		_, ok = closers[next]
+		return ok, ok, nil
+	})
+}
+
+// closeImplicitly closes an element with an omitted end tag. The whitespace
+// before the implied end tag separates the element from its next sibling, so
+// it's moved from the last child to the element.
+func closeImplicitly(pi *parse.Input, r Element, openTagLine int, contentStart int) Element {
+	if len(r.Children) > 0 {
+		last := len(r.Children) - 1
+		if ws, isWhitespace := r.Children[last].(Whitespace); isWhitespace {
+			r.TrailingSpace, _ = NewTrailingSpace(ws.Value)
+			r.Children = r.Children[:last]
+		} else {
+			r.Children[last], r.TrailingSpace = trimTrailingSpace(r.Children[last])
+		}
+	}
+	// Only indent the children if they span multiple lines, ignoring the trailing whitespace.
+	r.IndentChildren = openTagLine != pi.PositionAt(indexBeforeWhitespace(pi, contentStart)).Line
+	return r
+}
+
 // Element self-closing tag.
 var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, err error) {
 	start := pi.Index()
@@ -475,6 +671,7 @@ var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, e
 		pi.Seek(start)
 		return
 	}
+	e.SelfClosing = true
 
 	// Parse trailing whitespace.
 	ws, _, err := parse.Whitespace.Parse(pi)
@@ -492,13 +689,17 @@ var selfClosingElement = parse.Func(func(pi *parse.Input) (e Element, ok bool, e
 // Element
 var element elementParser
 
-type elementParser struct{}
+type elementParser struct {
+	// preformatted is true if the element is within a preformatted element, e.g. <pre>.
+	preformatted bool
+	mode         nodeMode
+}
 
-func (elementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+func (p elementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	start := pi.Position()
 
 	var r Element
-	if r, ok, err = parse.Any[Element](selfClosingElement, elementOpenClose).Parse(pi); err != nil || !ok {
+	if r, ok, err = parse.Any[Element](selfClosingElement, elementOpenCloseParser{preformatted: p.preformatted, mode: p.mode}).Parse(pi); err != nil || !ok {
 		return
 	}
 	var msgs []string
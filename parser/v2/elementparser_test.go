@@ -253,6 +253,33 @@ if test {
 				},
 			},
 		},
+		{
+			name:   "boolean expression attribute containing a closing brace in a string",
+			input:  ` noshade?={ flags["}"] }"`,
+			parser: StripType(boolExpressionAttributeParser),
+			expected: BoolExpressionAttribute{
+				Name: "noshade",
+				NameRange: Range{
+					From: Position{Index: 1, Line: 0, Col: 1},
+					To:   Position{Index: 8, Line: 0, Col: 8},
+				},
+				Expression: Expression{
+					Value: `flags["}"]`,
+					Range: Range{
+						From: Position{
+							Index: 12,
+							Line:  0,
+							Col:   12,
+						},
+						To: Position{
+							Index: 22,
+							Line:  0,
+							Col:   22,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:   "boolean expression attribute without spaces",
 			input:  ` noshade?={true}"`,
@@ -356,6 +383,43 @@ if test {
 				},
 			},
 		},
+		{
+			name:   "key expression attribute",
+			input:  ` { "data-" + key }={ value }"`,
+			parser: StripType(keyExpressionAttributeParser),
+			expected: KeyExpressionAttribute{
+				Key: Expression{
+					Value: `"data-" + key`,
+					Range: Range{
+						From: Position{
+							Index: 3,
+							Line:  0,
+							Col:   3,
+						},
+						To: Position{
+							Index: 16,
+							Line:  0,
+							Col:   16,
+						},
+					},
+				},
+				Expression: Expression{
+					Value: "value",
+					Range: Range{
+						From: Position{
+							Index: 21,
+							Line:  0,
+							Col:   21,
+						},
+						To: Position{
+							Index: 26,
+							Line:  0,
+							Col:   26,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:   "constant attribute",
 			input:  ` href="test"`,
@@ -472,6 +536,7 @@ if test {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 6, Line: 0, Col: 6},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "required",
@@ -494,6 +559,7 @@ if test {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 6, Line: 0, Col: 6},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "required",
@@ -565,6 +631,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 2, Line: 0, Col: 2},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "href",
@@ -586,6 +653,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 3, Line: 0, Col: 3},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					BoolExpressionAttribute{
 						Name: "noshade",
@@ -621,6 +689,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 2, Line: 0, Col: 2},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ExpressionAttribute{
 						Name: "href",
@@ -656,6 +725,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 2, Line: 0, Col: 2},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "href",
@@ -685,6 +755,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 2, Line: 0, Col: 2},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					SpreadAttributes{
 						Expression: Expression{
@@ -723,6 +794,69 @@ func TestElementParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "element: self-closing with key expression and spread attributes",
+			input: `<a {key}={value} { spread... }/>`,
+			expected: Element{
+				Name: "a",
+				NameRange: Range{
+					From: Position{Index: 1, Line: 0, Col: 1},
+					To:   Position{Index: 2, Line: 0, Col: 2},
+				},
+				SelfClosing: true,
+				Attributes: []Attribute{
+					KeyExpressionAttribute{
+						Key: Expression{
+							Value: "key",
+							Range: Range{
+								From: Position{
+									Index: 4,
+									Line:  0,
+									Col:   4,
+								},
+								To: Position{
+									Index: 7,
+									Line:  0,
+									Col:   7,
+								},
+							},
+						},
+						Expression: Expression{
+							Value: "value",
+							Range: Range{
+								From: Position{
+									Index: 10,
+									Line:  0,
+									Col:   10,
+								},
+								To: Position{
+									Index: 15,
+									Line:  0,
+									Col:   15,
+								},
+							},
+						},
+					},
+					SpreadAttributes{
+						Expression: Expression{
+							Value: "spread",
+							Range: Range{
+								From: Position{
+									Index: 19,
+									Line:  0,
+									Col:   19,
+								},
+								To: Position{
+									Index: 25,
+									Line:  0,
+									Col:   25,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:  "element: self-closing with multiple boolean attributes",
 			input: `<hr optionA optionB?={ true } optionC="other"/>`,
@@ -732,6 +866,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 3, Line: 0, Col: 3},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					BoolConstantAttribute{
 						Name: "optionA",
@@ -782,6 +917,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 2, Line: 0, Col: 2},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "href",
@@ -880,6 +1016,18 @@ func TestElementParser(t *testing.T) {
 				IndentAttrs: true,
 				Children: []Node{
 					Text{
+						Range: Range{
+							From: Position{
+								Index: 70,
+								Line:  4,
+								Col:   1,
+							},
+							To: Position{
+								Index: 74,
+								Line:  4,
+								Col:   5,
+							},
+						},
 						Value: "Test",
 					},
 				},
@@ -895,6 +1043,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 3, Line: 0, Col: 3},
 				},
+				SelfClosing: true,
 			},
 		},
 		{
@@ -906,6 +1055,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 3, Line: 0, Col: 3},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -931,6 +1081,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 3, Line: 0, Col: 3},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -986,6 +1137,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 3, Line: 0, Col: 3},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "style",
@@ -1088,7 +1240,21 @@ func TestElementParser(t *testing.T) {
 				},
 				IndentAttrs: true,
 				Children: []Node{
-					Text{Value: "Test"},
+					Text{
+						Range: Range{
+							From: Position{
+								Index: 66,
+								Line:  4,
+								Col:   1,
+							},
+							To: Position{
+								Index: 70,
+								Line:  4,
+								Col:   5,
+							},
+						},
+						Value: "Test",
+					},
 				},
 			},
 		},
@@ -1114,6 +1280,18 @@ func TestElementParser(t *testing.T) {
 				},
 				Children: []Node{
 					Text{
+						Range: Range{
+							From: Position{
+								Index: 3,
+								Line:  0,
+								Col:   3,
+							},
+							To: Position{
+								Index: 11,
+								Line:  0,
+								Col:   11,
+							},
+						},
 						Value: "The text",
 					},
 				},
@@ -1135,6 +1313,7 @@ func TestElementParser(t *testing.T) {
 							From: Position{Index: 4, Line: 0, Col: 4},
 							To:   Position{Index: 5, Line: 0, Col: 5},
 						},
+						SelfClosing: true,
 					},
 				},
 			},
@@ -1215,6 +1394,7 @@ func TestElementParser(t *testing.T) {
 									From: Position{Index: 14, Line: 0, Col: 14},
 									To:   Position{Index: 15, Line: 0, Col: 15},
 								},
+								SelfClosing: true,
 							},
 						},
 					},
@@ -1271,6 +1451,7 @@ func TestElementParser(t *testing.T) {
 					From: Position{Index: 1, Line: 0, Col: 1},
 					To:   Position{Index: 6, Line: 0, Col: 6},
 				},
+				SelfClosing: true,
 				Attributes: []Attribute{
 					ConstantAttribute{
 						Name:  "type",
@@ -1379,6 +1560,56 @@ func TestElementParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "element: custom element names can contain dots and underscores",
+			input: `<my-el.v2_beta data-userId="1" aria-labelledBy="a"></my-el.v2_beta>`,
+			expected: Element{
+				Name: "my-el.v2_beta",
+				NameRange: Range{
+					From: Position{Index: 1, Line: 0, Col: 1},
+					To:   Position{Index: 14, Line: 0, Col: 14},
+				},
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "data-userId",
+						Value: "1",
+						NameRange: Range{
+							From: Position{Index: 15, Line: 0, Col: 15},
+							To:   Position{Index: 26, Line: 0, Col: 26},
+						},
+					},
+					ConstantAttribute{
+						Name:  "aria-labelledBy",
+						Value: "a",
+						NameRange: Range{
+							From: Position{Index: 31, Line: 0, Col: 31},
+							To:   Position{Index: 46, Line: 0, Col: 46},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "element: customized built-in element",
+			input: `<button is="fancy-button"></button>`,
+			expected: Element{
+				Name: "button",
+				NameRange: Range{
+					From: Position{Index: 1, Line: 0, Col: 1},
+					To:   Position{Index: 7, Line: 0, Col: 7},
+				},
+				Attributes: []Attribute{
+					ConstantAttribute{
+						Name:  "is",
+						Value: "fancy-button",
+						NameRange: Range{
+							From: Position{Index: 8, Line: 0, Col: 8},
+							To:   Position{Index: 10, Line: 0, Col: 10},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// ErrorHelp explains a parse error, and suggests how to fix it.
+type ErrorHelp struct {
+	// Code of the error, e.g. T001.
+	Code string
+	// Explanation of the error.
+	Explanation string
+	// Suggestion of a likely fix, e.g. "did you mean class={ classes }?". It's
+	// empty if the fix can't be worked out from the source.
+	Suggestion string
+}
+
+// URL of the reference docs of the error code.
+func (h ErrorHelp) URL() string {
+	return "https://templ.guide/commands-and-tools/error-codes#" + strings.ToLower(h.Code)
+}
+
+type errorHelpRule struct {
+	code        string
+	match       func(msg string) bool
+	explanation string
+	// suggest a fix, given the error message, and the source from the error to
+	// the end of the line.
+	suggest func(msg, rest string) string
+}
+
+func msgContains(substrs ...string) func(msg string) bool {
+	return func(msg string) bool {
+		for _, s := range substrs {
+			if strings.Contains(msg, s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var (
+	unquotedAttributePattern   = regexp.MustCompile(`^([^\s"'=<>{}/]+)=([^\s"'=<>{}` + "`" + `]+)`)
+	unterminatedQuotePattern   = regexp.MustCompile(`^([^\s"'=<>{}/]+)=("|')`)
+	spreadWithoutDotsPattern   = regexp.MustCompile(`^\{\s*([^{}]*?)\s*\}`)
+	mismatchedEndTagPattern    = regexp.MustCompile(`expected '(</[^']*>)'`)
+	elementNamePattern         = regexp.MustCompile(`^<([^>]+)>:`)
+	templDeclarationPattern    = regexp.MustCompile(`^(templ\s+[A-Za-z_]\w*)\s*\{`)
+	endCommentLiteralPattern   = regexp.MustCompile(`expected end comment literal '([^']*)'`)
+	incompleteStatementPattern = regexp.MustCompile(`^(if|for|switch|else if)\b`)
+)
+
+var errorHelpRules = []errorHelpRule{
+	{
+		code:        "T001",
+		match:       msgContains("malformed open element"),
+		explanation: "the attributes of the element couldn't be parsed; attribute values must be quoted, or Go expressions in braces",
+		suggest: func(msg, rest string) string {
+			if m := unquotedAttributePattern.FindStringSubmatch(rest); m != nil {
+				return fmt.Sprintf("expressions in attributes must be quoted: did you mean %s={ %s }?", m[1], m[2])
+			}
+			if m := unterminatedQuotePattern.FindStringSubmatch(rest); m != nil && strings.Count(rest, m[2]) == 1 {
+				return fmt.Sprintf("the value of the %s attribute is missing its closing %s", m[1], m[2])
+			}
+			if m := spreadWithoutDotsPattern.FindStringSubmatch(rest); m != nil && !strings.HasSuffix(m[1], "...") {
+				return fmt.Sprintf("attributes are spread with ...: did you mean { %s... }?", m[1])
+			}
+			return ""
+		},
+	},
+	{
+		code:        "T002",
+		match:       msgContains("mismatched end tag"),
+		explanation: "the end tag doesn't match the element that it closes",
+		suggest: func(msg, rest string) string {
+			if m := mismatchedEndTagPattern.FindStringSubmatch(msg); m != nil {
+				return fmt.Sprintf("did you mean %s?", m[1])
+			}
+			return ""
+		},
+	},
+	{
+		code:        "T003",
+		match:       msgContains("expected end tag not present"),
+		explanation: "the element isn't closed; elements must have an end tag, or be self-closing, e.g. <br/>",
+		suggest: func(msg, rest string) string {
+			if m := elementNamePattern.FindStringSubmatch(msg); m != nil {
+				return fmt.Sprintf("did you forget </%s>?", m[1])
+			}
+			return ""
+		},
+	},
+	{
+		code:        "T004",
+		match:       msgContains("incomplete-statements"),
+		explanation: "if, for and switch statements must end with { on the same line, and be closed with }",
+		suggest: func(msg, rest string) string {
+			if m := incompleteStatementPattern.FindStringSubmatch(strings.TrimSpace(rest)); m != nil && !strings.HasSuffix(strings.TrimSpace(rest), "{") {
+				return fmt.Sprintf("did you mean %s {?", strings.TrimSpace(rest))
+			}
+			return ""
+		},
+	},
+	{
+		code:        "T005",
+		match:       msgContains("closing brace", "close brace", "too many closing braces", "unexpected brace count"),
+		explanation: "the braces of the template or expression don't match; each { must be closed by a }",
+	},
+	{
+		code:        "T006",
+		match:       msgContains("templ  declaration", "malformed templ expression"),
+		explanation: "templates are declared like Go functions, e.g. templ name(params) {",
+		suggest: func(msg, rest string) string {
+			if m := templDeclarationPattern.FindStringSubmatch(rest); m != nil {
+				return fmt.Sprintf("did you mean %s() {?", m[1])
+			}
+			return ""
+		},
+	},
+	{
+		code:        "T007",
+		match:       msgContains("invalid go expression", "unterminated (missing ') {')", "parameters missing"),
+		explanation: "the Go code couldn't be parsed; check for unbalanced brackets and quotes",
+	},
+	{
+		code:        "T008",
+		match:       msgContains("expected end comment literal", "unclosed DOCTYPE", "unclosed XML declaration"),
+		explanation: "the comment or declaration isn't closed",
+		suggest: func(msg, rest string) string {
+			if m := endCommentLiteralPattern.FindStringSubmatch(msg); m != nil && strings.TrimSpace(m[1]) != "" {
+				return fmt.Sprintf("did you forget %s?", m[1])
+			}
+			return ""
+		},
+	},
+}
+
+// Help explains the parse error, and suggests a fix using the source of the
+// template file. It returns false if there's no help for the error.
+func Help(src string, err parse.ParseError) (help ErrorHelp, ok bool) {
+	for _, r := range errorHelpRules {
+		if !r.match(err.Msg) {
+			continue
+		}
+		help = ErrorHelp{Code: r.code, Explanation: r.explanation}
+		if r.suggest != nil {
+			help.Suggestion = r.suggest(err.Msg, sourceRest(src, err.Pos.Index))
+		}
+		return help, true
+	}
+	return help, false
+}
+
+// FormatError formats the parse errors of the template file source, with the
+// line that contains each error, and help to fix it. Lines are numbered from
+// one, as in editors. Other errors are returned as-is.
+//
+//	<div>: malformed open element: line 4, col 6
+//	  4 | 	<div class=classes>
+//	    | 	     ^
+//	  T001: the attributes of the element couldn't be parsed; ...
+//	  help: expressions in attributes must be quoted: did you mean class={ classes }?
+//	  see https://templ.guide/commands-and-tools/error-codes#t001
+func FormatError(src string, err error) string {
+	var errs ParseErrors
+	switch err := err.(type) {
+	case ParseErrors:
+		errs = err
+	case parse.ParseError:
+		errs = ParseErrors{err}
+	default:
+		return err.Error()
+	}
+	var sb strings.Builder
+	for i, pe := range errs {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		writeError(&sb, src, pe)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func writeError(sb *strings.Builder, src string, pe parse.ParseError) {
+	// Positions are zero-based, but editors number lines from one.
+	lineNumber := fmt.Sprintf("%d", pe.Pos.Line+1)
+	fmt.Fprintf(sb, "%s: line %s, col %d\n", pe.Msg, lineNumber, pe.Pos.Col)
+
+	index := min(max(pe.Pos.Index, 0), len(src))
+	lineStart := strings.LastIndexByte(src[:index], '\n') + 1
+	line := src[lineStart:index] + sourceRest(src, index)
+	gutter := strings.Repeat(" ", len(lineNumber))
+	fmt.Fprintf(sb, "  %s | %s\n", lineNumber, line)
+	fmt.Fprintf(sb, "  %s | %s^\n", gutter, caretIndent(src[lineStart:index]))
+
+	help, ok := Help(src, pe)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(sb, "  %s: %s\n", help.Code, help.Explanation)
+	if help.Suggestion != "" {
+		fmt.Fprintf(sb, "  help: %s\n", help.Suggestion)
+	}
+	fmt.Fprintf(sb, "  see %s\n", help.URL())
+}
+
+// sourceRest returns the source from the index to the end of the line.
+func sourceRest(src string, index int) string {
+	rest := src[min(max(index, 0), len(src)):]
+	if end := strings.IndexByte(rest, '\n'); end >= 0 {
+		rest = rest[:end]
+	}
+	return strings.TrimSuffix(rest, "\r")
+}
+
+// caretIndent replaces the characters of the line prefix with spaces, keeping
+// tabs, so that a caret after it lines up with the character at the error.
+func caretIndent(prefix string) string {
+	var sb strings.Builder
+	for _, r := range prefix {
+		if r == '\t' {
+			sb.WriteRune('\t')
+			continue
+		}
+		sb.WriteRune(' ')
+	}
+	return sb.String()
+}
@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestErrorHelp(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ErrorHelp
+	}{
+		{
+			name: "unquoted attribute expressions",
+			input: `package p
+
+templ a(classes string) {
+	<div class=classes></div>
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T001",
+				Explanation: "the attributes of the element couldn't be parsed; attribute values must be quoted, or Go expressions in braces",
+				Suggestion:  "expressions in attributes must be quoted: did you mean class={ classes }?",
+			},
+		},
+		{
+			name: "unterminated attribute values",
+			input: `package p
+
+templ a() {
+	<a href="/home>Home</a>
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T001",
+				Explanation: "the attributes of the element couldn't be parsed; attribute values must be quoted, or Go expressions in braces",
+				Suggestion:  `the value of the href attribute is missing its closing "`,
+			},
+		},
+		{
+			name: "spread attributes without dots",
+			input: `package p
+
+templ a(attrs templ.Attributes) {
+	<div { attrs }></div>
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T001",
+				Explanation: "the attributes of the element couldn't be parsed; attribute values must be quoted, or Go expressions in braces",
+				Suggestion:  "attributes are spread with ...: did you mean { attrs... }?",
+			},
+		},
+		{
+			name: "mismatched end tags",
+			input: `package p
+
+templ a() {
+	<div>Hello</span>
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T002",
+				Explanation: "the end tag doesn't match the element that it closes",
+				Suggestion:  "did you mean </div>?",
+			},
+		},
+		{
+			name: "missing end tags",
+			input: `package p
+
+templ a() {
+	<div>Hello
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T003",
+				Explanation: "the element isn't closed; elements must have an end tag, or be self-closing, e.g. <br/>",
+				Suggestion:  "did you forget </div>?",
+			},
+		},
+		{
+			name: "statements without an opening brace",
+			input: `package p
+
+templ a(x bool) {
+	if x
+		<p>x</p>
+	}
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T004",
+				Explanation: "if, for and switch statements must end with { on the same line, and be closed with }",
+				Suggestion:  "did you mean if x {?",
+			},
+		},
+		{
+			name: "templates without parameters",
+			input: `package p
+
+templ a {
+	<p>x</p>
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T006",
+				Explanation: "templates are declared like Go functions, e.g. templ name(params) {",
+				Suggestion:  "did you mean templ a() {?",
+			},
+		},
+		{
+			name: "unclosed comments",
+			input: `package p
+
+templ a() {
+	<!-- comment
+}
+`,
+			expected: ErrorHelp{
+				Code:        "T008",
+				Explanation: "the comment or declaration isn't closed",
+				Suggestion:  "did you forget -->?",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseString(tt.input)
+			var pe parse.ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a parse error, got %v", err)
+			}
+			actual, ok := Help(tt.input, pe)
+			if !ok {
+				t.Fatalf("expected help for %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	t.Run("each parse error is shown with its line, and help", func(t *testing.T) {
+		input := "package p\n\ntempl a() {\n\t<div class=classes></div>\n\t<b>{ x </b>\n}\n"
+		_, err := ParseString(input)
+		expected := "<div>: malformed open element: line 4, col 6\n" +
+			"  4 | \t<div class=classes></div>\n" +
+			"    | \t     ^\n" +
+			"  T001: the attributes of the element couldn't be parsed; attribute values must be quoted, or Go expressions in braces\n" +
+			"  help: expressions in attributes must be quoted: did you mean class={ classes }?\n" +
+			"  see https://templ.guide/commands-and-tools/error-codes#t001\n" +
+			"\n" +
+			"string expression: missing close brace: line 5, col 6\n" +
+			"  5 | \t<b>{ x </b>\n" +
+			"    | \t     ^\n" +
+			"  T005: the braces of the template or expression don't match; each { must be closed by a }\n" +
+			"  see https://templ.guide/commands-and-tools/error-codes#t005"
+		if diff := cmp.Diff(expected, FormatError(input, err)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("errors without help only show the line", func(t *testing.T) {
+		input := "package p\n\ntempl a() {\n\t<p>\n}\n"
+		err := parse.Error("unexpected", parse.Position{Index: 24, Line: 3, Col: 1})
+		expected := "unexpected: line 4, col 1\n" +
+			"  4 | \t<p>\n" +
+			"    | \t^"
+		if diff := cmp.Diff(expected, FormatError(input, err)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		if actual := FormatError("", ErrLegacyFileFormat); actual != ErrLegacyFileFormat.Error() {
+			t.Errorf("expected %q, got %q", ErrLegacyFileFormat.Error(), actual)
+		}
+	})
+}
@@ -7,9 +7,11 @@ import (
 
 var forExpression parse.Parser[Node] = forExpressionParser{}
 
-type forExpressionParser struct{}
+type forExpressionParser struct {
+	mode nodeMode
+}
 
-func (_ forExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+func (p forExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	var r ForExpression
 	start := pi.Index()
 
@@ -17,24 +19,35 @@ func (_ forExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error)
 	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
 		return r, false, err
 	}
+	r.Label = parseForLabel(pi)
 	if !peekPrefix(pi, "for ") {
 		pi.Seek(start)
 		return r, false, nil
 	}
 
 	// Parse the Go for expression.
+	// Text that starts with a label, e.g. "Note: for more information", isn't a
+	// for loop.
 	if r.Expression, err = parseGo("for", pi, goexpression.For); err != nil {
+		if r.Label != "" {
+			pi.Seek(start)
+			return r, false, nil
+		}
 		return r, false, err
 	}
 
 	// Eat " {\n".
 	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !ok {
+		if r.Label != "" {
+			pi.Seek(start)
+			return r, false, nil
+		}
 		err = parse.Error("for: "+unterminatedMissingCurly, pi.PositionAt(start))
 		return
 	}
 
 	// Node contents.
-	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "for expression closing brace")
+	tnp := newControlFlowNodeParser(closeBraceWithOptionalPadding, "for expression closing brace", p.mode)
 	var nodes Nodes
 	if nodes, ok, err = tnp.Parse(pi); err != nil || !ok {
 		err = parse.Error("for: expected nodes, but none were found", pi.Position())
@@ -50,3 +63,20 @@ func (_ forExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error)
 
 	return r, true, nil
 }
+
+// parseForLabel parses the label of a for loop, e.g. "outer: ", if there is one.
+func parseForLabel(pi *parse.Input) (label string) {
+	start := pi.Index()
+	label, ok, _ := labelParser.Parse(pi)
+	if !ok || !peekPrefix(pi, ":") {
+		pi.Seek(start)
+		return ""
+	}
+	pi.Take(len(":"))
+	_, _, _ = horizontalWhitespace.Parse(pi)
+	if !peekPrefix(pi, "for ") {
+		pi.Seek(start)
+		return ""
+	}
+	return label
+}
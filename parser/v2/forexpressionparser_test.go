@@ -119,6 +119,33 @@ func TestForExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "for: with a label",
+			input: `outer: for _, row := range rows {
+					break outer
+				}`,
+			expected: ForExpression{
+				Label: "outer",
+				Expression: Expression{
+					Value: `_, row := range rows`,
+					Range: Range{
+						From: Position{Index: 11, Line: 0, Col: 11},
+						To:   Position{Index: 31, Line: 0, Col: 31},
+					},
+				},
+				Children: []Node{
+					BranchStatement{
+						Keyword: "break",
+						Label:   "outer",
+						Range: Range{
+							From: Position{Index: 39, Line: 1, Col: 5},
+							To:   Position{Index: 50, Line: 1, Col: 16},
+						},
+					},
+					Whitespace{Value: "\n\t\t\t\t"},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -138,6 +165,20 @@ func TestForExpressionParser(t *testing.T) {
 	}
 }
 
+func TestForExpressionParserLabelledText(t *testing.T) {
+	input := parse.NewInput("Note: for more information, see the docs.\n")
+	_, ok, err := forExpression.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected text that starts with a label not to be a for loop")
+	}
+	if input.Index() != 0 {
+		t.Errorf("expected the input to be at 0, got %d", input.Index())
+	}
+}
+
 func TestIncompleteFor(t *testing.T) {
 	t.Run("no opening brace", func(t *testing.T) {
 		input := parse.NewInput(`for with no brace`)
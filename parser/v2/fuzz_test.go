@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/txtar"
+)
+
+// addFormatSeeds adds the inputs of the format tests to the corpus of the fuzzer.
+func addFormatSeeds(f *testing.F) {
+	files, _ := filepath.Glob("testdata/*.txt")
+	for _, file := range files {
+		a, err := txtar.ParseFile(file)
+		if err != nil || len(a.Files) == 0 {
+			continue
+		}
+		f.Add(clean(a.Files[0].Data))
+	}
+}
+
+func FuzzFormat(f *testing.F) {
+	addFormatSeeds(f)
+	f.Fuzz(func(t *testing.T, src string) {
+		tf, err := ParseString(src)
+		if err != nil {
+			t.Skip()
+			return
+		}
+		var formatted bytes.Buffer
+		if err := tf.Write(&formatted); err != nil {
+			t.Skip()
+			return
+		}
+		// The formatted template must parse, and formatting it again must not
+		// change it.
+		tf, err = ParseString(formatted.String())
+		if err != nil {
+			t.Fatalf("failed to parse formatted template: %v\n%s", err, formatted.String())
+		}
+		var reformatted bytes.Buffer
+		if err := tf.Write(&reformatted); err != nil {
+			t.Fatalf("failed to format formatted template: %v\n%s", err, formatted.String())
+		}
+		if diff := cmp.Diff(formatted.String(), reformatted.String()); diff != "" {
+			t.Fatalf("formatting is not idempotent:\n%s", diff)
+		}
+	})
+}
@@ -17,6 +17,20 @@ var (
 	ErrExpectedNodeNotFound  = errors.New("parser error: expected node not found")
 )
 
+// Error is an error in the Go code, at the offset of the start of the content.
+type Error struct {
+	Offset int
+	Err    error
+}
+
+func (e Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
 var defaultRegexp = regexp.MustCompile(`^default\s*:`)
 
 func Case(content string) (start, end int, err error) {
@@ -25,7 +39,7 @@ func Case(content string) (start, end int, err error) {
 	}
 	prefix := "switch {\n"
 	src := prefix + content
-	start, end, err = extract(src, func(body []ast.Stmt) (start, end int, err error) {
+	start, end, err = extract(src, ":", func(body []ast.Stmt) (start, end int, err error) {
 		sw, ok := body[0].(*ast.SwitchStmt)
 		if !ok {
 			return 0, 0, ErrExpectedNodeNotFound
@@ -54,7 +68,7 @@ func If(content string) (start, end int, err error) {
 	if !strings.HasPrefix(content, "if") {
 		return 0, 0, ErrExpectedNodeNotFound
 	}
-	return extract(content, func(body []ast.Stmt) (start, end int, err error) {
+	return extract(content, "{", func(body []ast.Stmt) (start, end int, err error) {
 		stmt, ok := body[0].(*ast.IfStmt)
 		if !ok {
 			return 0, 0, ErrExpectedNodeNotFound
@@ -69,7 +83,7 @@ func For(content string) (start, end int, err error) {
 	if !strings.HasPrefix(content, "for") {
 		return 0, 0, ErrExpectedNodeNotFound
 	}
-	return extract(content, func(body []ast.Stmt) (start, end int, err error) {
+	return extract(content, "{", func(body []ast.Stmt) (start, end int, err error) {
 		stmt := body[0]
 		switch stmt := stmt.(type) {
 		case *ast.ForStmt:
@@ -89,7 +103,7 @@ func Switch(content string) (start, end int, err error) {
 	if !strings.HasPrefix(content, "switch") {
 		return 0, 0, ErrExpectedNodeNotFound
 	}
-	return extract(content, func(body []ast.Stmt) (start, end int, err error) {
+	return extract(content, "{", func(body []ast.Stmt) (start, end int, err error) {
 		stmt := body[0]
 		switch stmt := stmt.(type) {
 		case *ast.SwitchStmt:
@@ -124,6 +138,10 @@ func TemplExpression(src string) (start, end int, err error) {
 		pos, tok, lit := s.Scan()
 		stop, err := ep.Insert(pos, tok, lit)
 		if err != nil {
+			var eu ErrUnbalanced
+			if errors.As(err, &eu) {
+				return 0, 0, Error{Offset: int(eu.Pos) - 1, Err: err}
+			}
 			return 0, 0, err
 		}
 		if stop {
@@ -133,7 +151,51 @@ func TemplExpression(src string) (start, end int, err error) {
 	return 0, ep.End, nil
 }
 
+// Expression returns the end of the Go expression, or list of expressions, that
+// ends at a closing brace, e.g. the value of an attribute. The first closing
+// braces are tried in turn, and the expression must parse as Go code, so braces
+// in strings, comments, and composite and function literals are part of the
+// expression.
 func Expression(src string) (start, end int, err error) {
+	var to int
+	for i := 0; i < maxSliceArgsBraces; i++ {
+		brace := strings.IndexByte(src[to:], '}')
+		if brace < 0 {
+			break
+		}
+		to += brace
+		if end, ok := expressionEnd(src[:to]); ok {
+			return 0, end, nil
+		}
+		to++
+	}
+	// Invalid expressions are read up to the closing brace, so that the rest of
+	// the template can be parsed.
+	return scanExpression(src)
+}
+
+// expressionEnd returns the end of the last expression of the list of
+// expressions, excluding trailing whitespace and comments, if the content is a
+// valid list of expressions. The last expression can be followed by "...".
+func expressionEnd(content string) (end int, ok bool) {
+	prefix := "templ_expression("
+	e, err := parser.ParseExprFrom(token.NewFileSet(), "", prefix+content+")", 0)
+	if err != nil {
+		return 0, false
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return 0, false
+	}
+	end = int(call.Args[len(call.Args)-1].End()) - 1
+	if call.Ellipsis.IsValid() {
+		end = int(call.Ellipsis) - 1 + len("...")
+	}
+	return end - len(prefix), true
+}
+
+// scanExpression reads Go tokens up to an unmatched closing brace.
+func scanExpression(src string) (start, end int, err error) {
 	var s scanner.Scanner
 	fset := token.NewFileSet()
 	file := fset.AddFile("", fset.Base(), len(src))
@@ -184,7 +246,7 @@ loop:
 		case token.SEMICOLON:
 			continue
 		case token.ILLEGAL:
-			return 0, 0, fmt.Errorf("illegal token: %v", lit)
+			return 0, 0, Error{Offset: int(pos) - 1, Err: fmt.Errorf("illegal token: %v", lit)}
 		default:
 			end = int(pos) + len(tok.String()) - 1
 		}
@@ -193,11 +255,34 @@ loop:
 }
 
 func SliceArgs(content string) (expr string, err error) {
+	// Parsing the rest of the file for each expression is slow in large files, so
+	// try to parse up to each of the first closing braces on its own first.
+	var end int
+	for i := 0; i < maxSliceArgsBraces; i++ {
+		brace := strings.IndexByte(content[end:], '}')
+		if brace < 0 {
+			break
+		}
+		end += brace + 1
+		if expr, err = sliceArgsFrom(content[:end], true); err == nil {
+			return expr, nil
+		}
+	}
+	return sliceArgsFrom(content+"}", false)
+}
+
+// maxSliceArgsBraces is the number of closing braces that are tried as the end
+// of the arguments.
+const maxSliceArgsBraces = 8
+
+// sliceArgsFrom parses the content, which includes the closing brace. If strict
+// is true, the content must be valid Go code.
+func sliceArgsFrom(content string, strict bool) (expr string, err error) {
 	prefix := "package main\nvar templ_args = []any{"
-	src := prefix + content + "}"
+	src := prefix + content
 
 	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
-	if node == nil {
+	if node == nil || (strict && parseErr != nil) {
 		return expr, parseErr
 	}
 
@@ -226,6 +311,11 @@ func SliceArgs(content string) (expr string, err error) {
 		if hasCodeBetweenEndAndBrace {
 			to = int(decl.Rbrace) - 1
 		}
+		// Invalid expressions can end in whitespace, e.g. the missing
+		// selector of "a. ".
+		for to > from && unicode.IsSpace(rune(src[to-1])) {
+			to--
+		}
 		return false
 	})
 
@@ -234,11 +324,21 @@ func SliceArgs(content string) (expr string, err error) {
 
 // Func returns the Go code up to the opening brace of the function body.
 func Func(content string) (name, expr string, err error) {
+	// Try to parse the signature on its own first, as in extract.
+	if header, ok := firstLineEndingWith(content, "{"); ok {
+		if name, expr, err = funcFrom(header+"\n}", true); err == nil && len(expr) < len(header) {
+			return name, expr, nil
+		}
+	}
+	return funcFrom(content, false)
+}
+
+func funcFrom(content string, strict bool) (name, expr string, err error) {
 	prefix := "package main\n"
 	src := prefix + content
 
 	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
-	if node == nil {
+	if node == nil || (strict && parseErr != nil) {
 		return name, expr, parseErr
 	}
 
@@ -295,12 +395,48 @@ func inspectFirstNode(node ast.Node, f func(ast.Node) bool) {
 // logical block.
 type Extractor func(body []ast.Stmt) (start, end int, err error)
 
-func extract(content string, extractor Extractor) (start, end int, err error) {
+func extract(content string, headerEnd string, extractor Extractor) (start, end int, err error) {
+	// Parsing the rest of the file for each statement is slow in large files, so
+	// try to parse the statement on its own first, closing its block and the
+	// container function.
+	if header, ok := firstLineEndingWith(content, headerEnd); ok {
+		if start, end, err = extractFrom(header+"\n}\n}", true, extractor); err == nil && end <= len(header) {
+			return start, end, nil
+		}
+	}
+	return extractFrom(content, false, extractor)
+}
+
+// maxHeaderLines is the number of lines that are searched for the end of a
+// statement or function signature.
+const maxHeaderLines = 16
+
+// firstLineEndingWith returns the content up to the end of the first line that
+// ends with the suffix, ignoring trailing whitespace.
+func firstLineEndingWith(content, suffix string) (s string, ok bool) {
+	var end int
+	for i := 0; i < maxHeaderLines && end < len(content); i++ {
+		lineEnd := strings.IndexByte(content[end:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(content) - end
+		}
+		end += lineEnd
+		if strings.HasSuffix(strings.TrimRightFunc(content[:end], unicode.IsSpace), suffix) {
+			return content[:end], true
+		}
+		end++
+	}
+	return "", false
+}
+
+// extractFrom parses the content. If strict is true, the content must be valid
+// Go code.
+func extractFrom(content string, strict bool, extractor Extractor) (start, end int, err error) {
 	prefix := "package main\nfunc templ_container() {\n"
 	src := prefix + content
 
 	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
-	if node == nil {
+	if node == nil || (strict && parseErr != nil) {
 		return 0, 0, parseErr
 	}
 
@@ -1,7 +1,9 @@
 package goexpression
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -24,6 +26,10 @@ var ifTests = []testInput{
 		name:  "if multiple",
 		input: `x && y && (!z)`,
 	},
+	{
+		name:  "multiline composite literal",
+		input: "x == (T{\n\tA: 1,\n})",
+	},
 }
 
 func TestIf(t *testing.T) {
@@ -92,6 +98,26 @@ var forTests = []testInput{
 		name:  "channel receive",
 		input: `x := range channel`,
 	},
+	{
+		name:  "range over func",
+		input: `x := range seq`,
+	},
+	{
+		name:  "range over func with two values",
+		input: `k, v := range maps.All(m)`,
+	},
+	{
+		name:  "range over func without values",
+		input: `range items.All()`,
+	},
+	{
+		name:  "range over func literal",
+		input: `x := range func(yield func(int) bool) { yield(1) }`,
+	},
+	{
+		name:  "range over int",
+		input: `i := range 10`,
+	},
 }
 
 func TestFor(t *testing.T) {
@@ -354,6 +380,22 @@ var expressionTests = []testInput{
 		name:  "string concat",
 		input: `direction + "newest"`,
 	},
+	{
+		name:  "string literal containing a closing brace",
+		input: `"}"`,
+	},
+	{
+		name:  "function call with string containing braces",
+		input: `fmt.Sprintf("{%s}", name)`,
+	},
+	{
+		name:  "composite literal",
+		input: `Props{Name: "}", Items: []string{"a", "b"}}.Class()`,
+	},
+	{
+		name:  "comment containing a closing brace",
+		input: `value /* } */ + 1`,
+	},
 }
 
 func TestExpression(t *testing.T) {
@@ -490,6 +532,66 @@ func TestTemplExpression(t *testing.T) {
 	}
 }
 
+func TestExpressionErrorOffset(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		fn       func(string) (int, int, error)
+		expected int
+	}{
+		{
+			name:     "illegal token",
+			input:    "a + # }",
+			fn:       Expression,
+			expected: 4,
+		},
+		{
+			name:     "unclosed call",
+			input:    "comp(x, {\n}\n",
+			fn:       TemplExpression,
+			expected: 4,
+		},
+		{
+			name:     "unbalanced closer",
+			input:    "items[0)",
+			fn:       TemplExpression,
+			expected: 7,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.fn(tt.input)
+			var e Error
+			if !errors.As(err, &e) {
+				t.Fatalf("expected an Error, got %T: %v", err, err)
+			}
+			if e.Offset != tt.expected {
+				t.Errorf("expected offset %d, got %d", tt.expected, e.Offset)
+			}
+		})
+	}
+}
+
+func TestExpressionTrailingComment(t *testing.T) {
+	input := "value // }\n}"
+	_, end, err := Expression(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("value", input[:end]); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTemplExpressionUnterminated(t *testing.T) {
+	for _, input := range []string{"comp(x, {\n}\n", "comp(", "items[0"} {
+		if _, _, err := TemplExpression(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
 func FuzzTemplExpression(f *testing.F) {
 	suffixes := []string{
 		"",
@@ -576,6 +678,10 @@ var sliceArgsTests = []testInput{
 		name:  "package name, but no variable or function",
 		input: `fmt.`,
 	},
+	{
+		name:  "closing brace in string",
+		input: `fmt.Sprint("}", x)`,
+	},
 }
 
 func TestSliceArgs(t *testing.T) {
@@ -599,6 +705,18 @@ func TestSliceArgs(t *testing.T) {
 	}
 }
 
+func TestSliceArgsTrailingWhitespace(t *testing.T) {
+	for _, input := range []string{"a  }", "a. }", "a, b\t}", "\"\"0 }"} {
+		expr, err := SliceArgs(input)
+		if err != nil {
+			t.Fatalf("failed to parse slice args: %v", err)
+		}
+		if expected := strings.TrimSpace(strings.TrimSuffix(input, "}")); expr != expected {
+			t.Errorf("%q: expected %q, got %q", input, expected, expr)
+		}
+	}
+}
+
 func FuzzSliceArgs(f *testing.F) {
 	suffixes := []string{
 		"",
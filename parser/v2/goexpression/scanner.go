@@ -41,6 +41,8 @@ var goTokenCloseToOpen = map[token.Token]token.Token{
 
 type ErrUnbalanced struct {
 	Token token.Token
+	// Pos is the position of the token.
+	Pos token.Pos
 }
 
 func (e ErrUnbalanced) Error() string {
@@ -57,6 +59,7 @@ func NewExpressionParser() *ExpressionParser {
 
 type ExpressionParser struct {
 	Stack    Stack[token.Token]
+	Openers  Stack[token.Pos] // Positions of the tokens in the stack.
 	End      int
 	Previous token.Token
 	Fns      Stack[int] // Stack of function depths.
@@ -79,6 +82,13 @@ func (ep *ExpressionParser) Insert(pos token.Pos, tok token.Token, lit string) (
 	defer func() {
 		ep.Previous = tok
 	}()
+	// The scanner returns EOF forever, so the expression must end here.
+	if tok == token.EOF {
+		if len(ep.Stack) > 0 {
+			return false, ErrUnbalanced{Token: ep.Stack.Peek(), Pos: ep.Openers.Peek()}
+		}
+		return true, nil
+	}
 	// Handle function literals e.g. func() { fmt.Println("Hello") }
 	// By pushing the current depth onto the stack, we prevent stopping
 	// until we've closed the function.
@@ -93,6 +103,7 @@ func (ep *ExpressionParser) Insert(pos token.Pos, tok token.Token, lit string) (
 	if ep.Previous == token.LBRACK && tok == token.RBRACK {
 		// Pop a left square bracket from the stack.
 		ep.Stack.Pop()
+		ep.Openers.Pop()
 		// Push the current depth onto the slice stack.
 		ep.Slices.Push(len(ep.Stack))
 		ep.setEnd(pos, tok, lit)
@@ -114,6 +125,7 @@ func (ep *ExpressionParser) Insert(pos token.Pos, tok token.Token, lit string) (
 			}
 		}
 		ep.Stack.Push(tok)
+		ep.Openers.Push(pos)
 		ep.setEnd(pos, tok, lit)
 		return false, nil
 	}
@@ -123,11 +135,12 @@ func (ep *ExpressionParser) Insert(pos token.Pos, tok token.Token, lit string) (
 			return true, nil
 		}
 		actual := ep.Stack.Pop()
+		ep.Openers.Pop()
 		if !isCloser {
-			return false, ErrUnbalanced{tok}
+			return false, ErrUnbalanced{Token: tok, Pos: pos}
 		}
 		if actual != opener {
-			return false, ErrUnbalanced{tok}
+			return false, ErrUnbalanced{Token: tok, Pos: pos}
 		}
 		if tok == token.RBRACE {
 			// If we're closing a function, pop the function depth.
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -62,7 +63,13 @@ func parseGo(name string, pi *parse.Input, e extractor) (r Expression, err error
 	src, _ := pi.Peek(-1)
 	start, end, err := e(src)
 	if err != nil {
-		return r, parse.Error(fmt.Sprintf("%s: invalid go expression: %v", name, err.Error()), pi.Position())
+		// Report the error at its position within the expression, if it's known.
+		pos := pi.Position()
+		var ge goexpression.Error
+		if errors.As(err, &ge) {
+			pos = pi.PositionAt(from + ge.Offset)
+		}
+		return r, parse.Error(fmt.Sprintf("%s: invalid go expression: %v", name, err.Error()), pos)
 	}
 	expr := src[start:end]
 	pi.Take(end)
@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateHTML checks the HTML templates in the file against the rules of the
+// HTML spec that can be checked at generation time: invalid nesting, duplicate
+// attributes, and unknown elements and attributes. Void elements with children
+// are rejected by the parser.
+//
+// Custom elements, and attributes containing "-", ":", "@" or ".", such as
+// data-*, aria-*, hx-get or x-on:click, are not reported as unknown.
+func ValidateHTML(t TemplateFile) (diags []Diagnostic) {
+	for _, n := range t.Nodes {
+		ht, ok := n.(HTMLTemplate)
+		if !ok || ht.ContentType != ContentTypeHTML {
+			continue
+		}
+		var v htmlValidator
+		v.validateNodes(ht.Children, nil)
+		diags = append(diags, v.diags...)
+	}
+	return diags
+}
+
+type htmlValidator struct {
+	diags []Diagnostic
+}
+
+func (v *htmlValidator) add(r Range, format string, a ...any) {
+	v.diags = append(v.diags, Diagnostic{
+		Message: fmt.Sprintf(format, a...),
+		Range:   r,
+	})
+}
+
+// validateNodes validates the nodes, where ancestors are the names of the
+// enclosing elements, nearest last.
+func (v *htmlValidator) validateNodes(nodes []Node, ancestors []string) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case Element:
+			v.validateElement(n, ancestors)
+			v.validateNodes(n.Children, append(ancestors[:len(ancestors):len(ancestors)], n.Name))
+		case TemplElementExpression:
+			// Children are passed to another component, so the enclosing elements are unknown.
+			v.validateNodes(n.Children, nil)
+		case CompositeNode:
+			v.validateNodes(n.ChildNodes(), ancestors)
+		}
+	}
+}
+
+func (v *htmlValidator) validateElement(e Element, ancestors []string) {
+	v.validateAttributes(e, ancestors)
+	if isInForeignContent(ancestors) {
+		return
+	}
+	if !isKnownElement(e) {
+		v.add(e.NameRange, "unknown element <%s>", e.Name)
+	}
+	v.validateNesting(e, ancestors)
+}
+
+func (v *htmlValidator) validateAttributes(e Element, ancestors []string) {
+	checkKnown := isKnownElement(e) && !e.IsForeignElement() && !isInForeignContent(ancestors)
+	seen := make(map[string]struct{}, len(e.Attributes))
+	for _, attr := range e.Attributes {
+		name, nameRange, ok := attributeName(attr)
+		if !ok {
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, isDuplicate := seen[key]; isDuplicate {
+			v.add(nameRange, "duplicate attribute %q on <%s>", name, e.Name)
+			continue
+		}
+		seen[key] = struct{}{}
+		if checkKnown && !isKnownAttribute(key) {
+			v.add(nameRange, "unknown attribute %q on <%s>", name, e.Name)
+		}
+	}
+}
+
+func attributeName(attr Attribute) (name string, r Range, ok bool) {
+	switch attr := attr.(type) {
+	case BoolConstantAttribute:
+		return attr.Name, attr.NameRange, true
+	case ConstantAttribute:
+		return attr.Name, attr.NameRange, true
+	case BoolExpressionAttribute:
+		return attr.Name, attr.NameRange, true
+	case ExpressionAttribute:
+		return attr.Name, attr.NameRange, true
+	}
+	return "", Range{}, false
+}
+
+func (v *htmlValidator) validateNesting(e Element, ancestors []string) {
+	if len(ancestors) == 0 {
+		return
+	}
+	parent := ancestors[len(ancestors)-1]
+	if _, closesP := pClosingElements[e.Name]; closesP && hasAncestor(ancestors, "p") {
+		v.add(e.NameRange, "invalid nesting: <%s> cannot be placed inside <p>", e.Name)
+	}
+	if _, isNonNestable := nonNestableElements[e.Name]; isNonNestable && hasAncestor(ancestors, e.Name) {
+		v.add(e.NameRange, "invalid nesting: <%s> cannot be placed inside another <%s>", e.Name, e.Name)
+	}
+	allowedParents, hasRequiredParent := requiredParents[e.Name]
+	if !hasRequiredParent || parent == "template" || strings.Contains(parent, "-") {
+		return
+	}
+	for _, p := range allowedParents {
+		if p == parent {
+			return
+		}
+	}
+	v.add(e.NameRange, "invalid nesting: <%s> must be placed inside %s, not <%s>", e.Name, formatElementNames(allowedParents), parent)
+}
+
+func formatElementNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = "<" + n + ">"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+func hasAncestor(ancestors []string, name string) bool {
+	for _, a := range ancestors {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isInForeignContent(ancestors []string) bool {
+	return hasAncestor(ancestors, "svg") || hasAncestor(ancestors, "math")
+}
+
+func isKnownElement(e Element) bool {
+	if strings.Contains(e.Name, "-") || e.IsForeignElement() {
+		return true
+	}
+	_, ok := htmlElements[e.Name]
+	return ok
+}
+
+func isKnownAttribute(name string) bool {
+	if strings.ContainsAny(name, "-:@.") || strings.HasPrefix(name, "on") {
+		return true
+	}
+	_, ok := htmlAttributes[name]
+	return ok
+}
+
+// requiredParents are the elements that can only be placed inside specific elements.
+var requiredParents = map[string][]string{
+	"li":         {"ul", "ol", "menu"},
+	"dt":         {"dl", "div"},
+	"dd":         {"dl", "div"},
+	"tr":         {"table", "thead", "tbody", "tfoot"},
+	"td":         {"tr"},
+	"th":         {"tr"},
+	"thead":      {"table"},
+	"tbody":      {"table"},
+	"tfoot":      {"table"},
+	"caption":    {"table"},
+	"colgroup":   {"table"},
+	"col":        {"colgroup"},
+	"optgroup":   {"select"},
+	"option":     {"select", "datalist", "optgroup"},
+	"summary":    {"details"},
+	"figcaption": {"figure"},
+	"legend":     {"fieldset"},
+	"track":      {"audio", "video"},
+}
+
+// pClosingElements implicitly close an open <p> element.
+// https://html.spec.whatwg.org/multipage/grouping-content.html#the-p-element
+var pClosingElements = map[string]struct{}{
+	"address": {}, "article": {}, "aside": {}, "blockquote": {}, "details": {}, "dialog": {}, "div": {}, "dl": {}, "fieldset": {},
+	"figcaption": {}, "figure": {}, "footer": {}, "form": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}, "header": {},
+	"hgroup": {}, "hr": {}, "main": {}, "menu": {}, "nav": {}, "ol": {}, "p": {}, "pre": {}, "search": {}, "section": {}, "table": {}, "ul": {},
+}
+
+// nonNestableElements cannot contain an element of the same type.
+var nonNestableElements = map[string]struct{}{
+	"a": {}, "button": {}, "form": {}, "label": {},
+}
+
+var htmlElements = map[string]struct{}{
+	"a": {}, "abbr": {}, "address": {}, "area": {}, "article": {}, "aside": {}, "audio": {}, "b": {}, "base": {}, "bdi": {}, "bdo": {},
+	"blockquote": {}, "body": {}, "br": {}, "button": {}, "canvas": {}, "caption": {}, "cite": {}, "code": {}, "col": {}, "colgroup": {},
+	"data": {}, "datalist": {}, "dd": {}, "del": {}, "details": {}, "dfn": {}, "dialog": {}, "div": {}, "dl": {}, "dt": {}, "em": {},
+	"embed": {}, "fieldset": {}, "figcaption": {}, "figure": {}, "footer": {}, "form": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {},
+	"h6": {}, "head": {}, "header": {}, "hgroup": {}, "hr": {}, "html": {}, "i": {}, "iframe": {}, "img": {}, "input": {}, "ins": {},
+	"kbd": {}, "label": {}, "legend": {}, "li": {}, "link": {}, "main": {}, "map": {}, "mark": {}, "menu": {}, "meta": {}, "meter": {},
+	"nav": {}, "noscript": {}, "object": {}, "ol": {}, "optgroup": {}, "option": {}, "output": {}, "p": {}, "picture": {}, "pre": {},
+	"progress": {}, "q": {}, "rp": {}, "rt": {}, "ruby": {}, "s": {}, "samp": {}, "script": {}, "search": {}, "section": {}, "select": {},
+	"slot": {}, "small": {}, "source": {}, "span": {}, "strong": {}, "style": {}, "sub": {}, "summary": {}, "sup": {}, "table": {},
+	"tbody": {}, "td": {}, "template": {}, "textarea": {}, "tfoot": {}, "th": {}, "thead": {}, "time": {}, "title": {}, "tr": {},
+	"track": {}, "u": {}, "ul": {}, "var": {}, "video": {}, "wbr": {},
+}
+
+// htmlAttributes are the global attributes, and the attributes of all HTML elements.
+var htmlAttributes = map[string]struct{}{
+	// Global attributes.
+	"accesskey": {}, "autocapitalize": {}, "autocorrect": {}, "autofocus": {}, "class": {}, "contenteditable": {}, "dir": {},
+	"draggable": {}, "enterkeyhint": {}, "hidden": {}, "id": {}, "inert": {}, "inputmode": {}, "is": {}, "itemid": {}, "itemprop": {},
+	"itemref": {}, "itemscope": {}, "itemtype": {}, "lang": {}, "nonce": {}, "popover": {}, "role": {}, "slot": {}, "spellcheck": {},
+	"style": {}, "tabindex": {}, "title": {}, "translate": {}, "writingsuggestions": {},
+	// Element attributes.
+	"abbr": {}, "accept": {}, "action": {}, "allow": {}, "allowfullscreen": {}, "alt": {}, "as": {}, "async": {},
+	"autocomplete": {}, "autoplay": {}, "blocking": {}, "charset": {}, "checked": {}, "cite": {}, "closedby": {}, "color": {}, "cols": {},
+	"colspan": {}, "command": {}, "commandfor": {}, "content": {}, "controls": {}, "coords": {}, "crossorigin": {}, "data": {},
+	"datetime": {}, "decoding": {}, "default": {}, "defer": {}, "dirname": {}, "disabled": {}, "download": {}, "enctype": {},
+	"fetchpriority": {}, "for": {}, "form": {}, "formaction": {}, "formenctype": {}, "formmethod": {}, "formnovalidate": {},
+	"formtarget": {}, "headers": {}, "height": {}, "high": {}, "href": {}, "hreflang": {}, "http-equiv": {}, "imagesizes": {},
+	"imagesrcset": {}, "integrity": {}, "ismap": {}, "kind": {}, "label": {}, "list": {}, "loading": {}, "loop": {}, "low": {},
+	"max": {}, "maxlength": {}, "media": {}, "method": {}, "min": {}, "minlength": {}, "multiple": {}, "muted": {}, "name": {},
+	"nomodule": {}, "novalidate": {}, "open": {}, "optimum": {}, "pattern": {}, "ping": {}, "placeholder": {}, "playsinline": {},
+	"popovertarget": {}, "popovertargetaction": {}, "poster": {}, "preload": {}, "readonly": {}, "referrerpolicy": {}, "rel": {},
+	"required": {}, "reversed": {}, "rows": {}, "rowspan": {}, "sandbox": {}, "scope": {}, "selected": {},
+	"shadowrootclonable": {}, "shadowrootdelegatesfocus": {}, "shadowrootmode": {}, "shadowrootserializable": {}, "shape": {},
+	"size": {}, "sizes": {}, "span": {}, "src": {}, "srcdoc": {}, "srclang": {}, "srcset": {}, "start": {}, "step": {}, "target": {},
+	"type": {}, "usemap": {}, "value": {}, "width": {}, "wrap": {},
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []Diagnostic
+	}{
+		{
+			name: "valid HTML",
+			template: `
+package main
+
+templ template(items []string) {
+	<ul class="list" data-count="2" hx-get="/items" @click="open = true">
+		for _, item := range items {
+			<li>{ item }</li>
+		}
+	</ul>
+	<user-card name="a"></user-card>
+	<svg viewBox="0 0 10 10"><circle r="4"></circle><feGaussianBlur stdDeviation="2"/></svg>
+}`,
+			want: nil,
+		},
+		{
+			name: "block element inside p",
+			template: `
+package main
+
+templ template() {
+	<p><span><div></div></span></p>
+}`,
+			want: []Diagnostic{{
+				Message: "invalid nesting: <div> cannot be placed inside <p>",
+				Range:   Range{Position{45, 4, 11}, Position{48, 4, 14}},
+			}},
+		},
+		{
+			name: "li outside of a list",
+			template: `
+package main
+
+templ template() {
+	<div>
+		if true {
+			<li>Item</li>
+		}
+	</div>
+}`,
+			want: []Diagnostic{{
+				Message: "invalid nesting: <li> must be placed inside <ul>, <ol> or <menu>, not <div>",
+				Range:   Range{Position{57, 6, 4}, Position{59, 6, 6}},
+			}},
+		},
+		{
+			name: "li at the root of a template, or passed as children, is not checked",
+			template: `
+package main
+
+templ template() {
+	<li>Item</li>
+	<div>
+		@list() {
+			<li>Item</li>
+		}
+	</div>
+}`,
+			want: nil,
+		},
+		{
+			name: "nested links",
+			template: `
+package main
+
+templ template() {
+	<a href="/"><a href="/b">B</a></a>
+}`,
+			want: []Diagnostic{{
+				Message: "invalid nesting: <a> cannot be placed inside another <a>",
+				Range:   Range{Position{48, 4, 14}, Position{49, 4, 15}},
+			}},
+		},
+		{
+			name: "duplicate attributes",
+			template: `
+package main
+
+templ template(c string) {
+	<div class="a" CLASS={ c }></div>
+}`,
+			want: []Diagnostic{{
+				Message: `duplicate attribute "CLASS" on <div>`,
+				Range:   Range{Position{58, 4, 16}, Position{63, 4, 21}},
+			}},
+		},
+		{
+			name: "unknown elements and attributes",
+			template: `
+package main
+
+templ template() {
+	<dvi colour="red"></dvi>
+	<div colour="red"></div>
+}`,
+			want: []Diagnostic{
+				{
+					Message: "unknown element <dvi>",
+					Range:   Range{Position{36, 4, 2}, Position{39, 4, 5}},
+				},
+				{
+					Message: `unknown attribute "colour" on <div>`,
+					Range:   Range{Position{66, 5, 6}, Position{72, 5, 12}},
+				},
+			},
+		},
+		{
+			name: "XML templates are not validated",
+			template: `
+package main
+
+templ feed() xml {
+	<rss><channel></channel></rss>
+}`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString(tt.template)
+			if err != nil {
+				t.Fatalf("ParseString() error = %v", err)
+			}
+			got := ValidateHTML(tf)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("ValidateHTML() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,11 +7,16 @@ import (
 
 var ifExpression ifExpressionParser
 
-var untilElseIfElseOrEnd = parse.Any(StripType(elseIfExpression), StripType(elseExpression), StripType(closeBraceWithOptionalPadding))
+// untilElseIfElseOrEnd returns a parser of the end of the nodes of an if or else if block.
+func untilElseIfElseOrEnd(mode nodeMode) parse.Parser[any] {
+	return parse.Any(StripType(elseIfExpressionParser{mode: mode}), StripType(elseExpressionParser{mode: mode}), StripType(closeBraceWithOptionalPadding))
+}
 
-type ifExpressionParser struct{}
+type ifExpressionParser struct {
+	mode nodeMode
+}
 
-func (ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+func (p ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	var r IfExpression
 	start := pi.Index()
 
@@ -34,7 +39,7 @@ func (ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 
 	// Read the 'Then' nodes.
 	// If there's no match, there's a problem in the template nodes.
-	np := newTemplateNodeParser(untilElseIfElseOrEnd, "else expression or closing brace")
+	np := newControlFlowNodeParser(untilElseIfElseOrEnd(p.mode), "else expression or closing brace", p.mode)
 	var thenNodes Nodes
 	if thenNodes, ok, err = np.Parse(pi); err != nil || !ok {
 		err = parse.Error("if: expected nodes, but none were found", pi.Position())
@@ -43,13 +48,13 @@ func (ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	r.Then = thenNodes.Nodes
 
 	// Read the optional 'ElseIf' Nodes.
-	if r.ElseIfs, _, err = parse.ZeroOrMore(elseIfExpression).Parse(pi); err != nil {
+	if r.ElseIfs, _, err = parse.ZeroOrMore[ElseIfExpression](elseIfExpressionParser{mode: p.mode}).Parse(pi); err != nil {
 		return
 	}
 
 	// Read the optional 'Else' Nodes.
 	var elseNodes Nodes
-	if elseNodes, _, err = elseExpression.Parse(pi); err != nil {
+	if elseNodes, _, err = (elseExpressionParser{mode: p.mode}).Parse(pi); err != nil {
 		return
 	}
 	r.Else = elseNodes.Nodes
@@ -63,11 +68,11 @@ func (ifExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	return r, true, nil
 }
 
-var elseIfExpression parse.Parser[ElseIfExpression] = elseIfExpressionParser{}
-
-type elseIfExpressionParser struct{}
+type elseIfExpressionParser struct {
+	mode nodeMode
+}
 
-func (elseIfExpressionParser) Parse(pi *parse.Input) (r ElseIfExpression, ok bool, err error) {
+func (p elseIfExpressionParser) Parse(pi *parse.Input) (r ElseIfExpression, ok bool, err error) {
 	start := pi.Index()
 
 	// Check the prefix first.
@@ -93,7 +98,7 @@ func (elseIfExpressionParser) Parse(pi *parse.Input) (r ElseIfExpression, ok boo
 
 	// Read the 'Then' nodes.
 	// If there's no match, there's a problem in the template nodes.
-	np := newTemplateNodeParser(untilElseIfElseOrEnd, "else expression or closing brace")
+	np := newControlFlowNodeParser(untilElseIfElseOrEnd(p.mode), "else expression or closing brace", p.mode)
 	var thenNodes Nodes
 	if thenNodes, ok, err = np.Parse(pi); err != nil || !ok {
 		err = parse.Error("if: expected nodes, but none were found", pi.Position())
@@ -112,11 +117,11 @@ var endElseParser = parse.All(
 	parse.Rune('{'),
 	parse.OptionalWhitespace)
 
-var elseExpression parse.Parser[Nodes] = elseExpressionParser{}
-
-type elseExpressionParser struct{}
+type elseExpressionParser struct {
+	mode nodeMode
+}
 
-func (elseExpressionParser) Parse(in *parse.Input) (r Nodes, ok bool, err error) {
+func (p elseExpressionParser) Parse(in *parse.Input) (r Nodes, ok bool, err error) {
 	start := in.Index()
 
 	// } else {
@@ -126,7 +131,7 @@ func (elseExpressionParser) Parse(in *parse.Input) (r Nodes, ok bool, err error)
 	}
 
 	// Else contents
-	if r, ok, err = newTemplateNodeParser(closeBraceWithOptionalPadding, "else expression closing brace").Parse(in); err != nil || !ok {
+	if r, ok, err = newControlFlowNodeParser(closeBraceWithOptionalPadding, "else expression closing brace", p.mode).Parse(in); err != nil || !ok {
 		in.Seek(start)
 		return
 	}
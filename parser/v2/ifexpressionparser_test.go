@@ -162,7 +162,22 @@ func TestIfExpression(t *testing.T) {
 				},
 				Then: []Node{
 					Whitespace{Value: "  "},
-					Text{Value: "text", TrailingSpace: SpaceVertical},
+					Text{
+						Range: Range{
+							From: Position{
+								Index: 15,
+								Line:  1,
+								Col:   2,
+							},
+							To: Position{
+								Index: 19,
+								Line:  1,
+								Col:   6,
+							},
+						},
+						Value:         "text",
+						TrailingSpace: SpaceVertical,
+					},
 				},
 			},
 		},
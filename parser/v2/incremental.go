@@ -0,0 +1,308 @@
+package parser
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/a-h/parse"
+)
+
+// IncrementalParser parses successive versions of a template file, e.g. as the
+// file is edited in an editor. The templates, CSS templates and script templates
+// outside of the edited text are reused from the previous version, instead of
+// being parsed again, so that parsing a large file after a small edit is fast.
+//
+// The zero value is ready to use.
+type IncrementalParser struct {
+	m     sync.Mutex
+	src   string
+	nodes []cachedNode
+}
+
+type cachedNode struct {
+	// start and end index of the node in the source.
+	start, end int
+	node       TemplateFileNode
+}
+
+// ParseString parses the new version of the template file.
+func (p *IncrementalParser) ParseString(src string) (tf TemplateFile, err error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	c := newNodeCache(p.src, src, p.nodes)
+	parser := NewTemplateFileParser("main")
+	parser.cache = c
+	tf, ok, err := parser.Parse(parse.NewInput(src))
+	p.src, p.nodes = src, c.parsed
+	if err != nil {
+		return tf, err
+	}
+	if !ok {
+		err = ErrTemplateNotFound
+	}
+	return tf, err
+}
+
+// nodeCache contains the nodes of the previous version of a file that can be
+// reused in the new version, keyed by their start index in the new version.
+type nodeCache struct {
+	reusable map[int]reusableNode
+	// parsed nodes of the new version, including the reused nodes.
+	parsed []cachedNode
+}
+
+type reusableNode struct {
+	cachedNode
+	// shift of the positions of the node.
+	index, lines int64
+}
+
+// newNodeCache finds the nodes of the previous version that aren't changed by
+// the edit from the previous source to the new source. The edit is the text
+// between the common prefix and the common suffix of the sources.
+//
+// Nodes after the edit are reused if they start on a new line, so that the
+// columns of their positions don't change.
+func newNodeCache(prev, src string, nodes []cachedNode) *nodeCache {
+	c := &nodeCache{reusable: map[int]reusableNode{}}
+	var prefix int
+	for prefix < len(prev) && prefix < len(src) && prev[prefix] == src[prefix] {
+		prefix++
+	}
+	var suffix int
+	for suffix < len(prev)-prefix && suffix < len(src)-prefix && prev[len(prev)-1-suffix] == src[len(src)-1-suffix] {
+		suffix++
+	}
+	index := int64(len(src) - len(prev))
+	lines := int64(strings.Count(src[prefix:len(src)-suffix], "\n") - strings.Count(prev[prefix:len(prev)-suffix], "\n"))
+	for _, n := range nodes {
+		switch {
+		case n.end < prefix:
+			// Parsers may look ahead of the end of the node, so the node must end
+			// before the edit, not at it.
+			c.reusable[n.start] = reusableNode{cachedNode: n}
+		case n.start >= len(prev)-suffix && n.start > 0 && prev[n.start-1] == '\n' && src[n.start-1+int(index)] == '\n':
+			n.start += int(index)
+			n.end += int(index)
+			c.reusable[n.start] = reusableNode{cachedNode: n, index: index, lines: lines}
+		}
+	}
+	return c
+}
+
+// get the node that starts at the index, if it can be reused. The input is
+// moved to the end of the node.
+func (c *nodeCache) get(pi *parse.Input, start int) (n TemplateFileNode, ok bool) {
+	if c == nil {
+		return nil, false
+	}
+	r, ok := c.reusable[start]
+	if !ok {
+		return nil, false
+	}
+	n = r.node
+	if r.index != 0 || r.lines != 0 {
+		n = positionShift{index: r.index, lines: r.lines}.templateFileNode(n)
+	}
+	pi.Seek(r.end)
+	c.parsed = append(c.parsed, cachedNode{start: start, end: r.end, node: n})
+	return n, true
+}
+
+// add the node that was parsed from the start index to the current index, if
+// no errors were found while parsing it.
+func (c *nodeCache) add(pi *parse.Input, start, errorsBefore int, n TemplateFileNode) {
+	if c == nil || errorCount(pi) != errorsBefore {
+		return
+	}
+	c.parsed = append(c.parsed, cachedNode{start: start, end: pi.Index(), node: n})
+}
+
+// positionShift moves the positions of nodes after an edit by a number of
+// characters and lines.
+type positionShift struct {
+	index, lines int64
+}
+
+func (s positionShift) position(p Position) Position {
+	// Optional expressions that are missing, e.g. the expression of a template
+	// that doesn't extend another template, have no positions to move.
+	if p == (Position{}) {
+		return p
+	}
+	p.Index += s.index
+	p.Line = uint32(int64(p.Line) + s.lines)
+	return p
+}
+
+func (s positionShift) rng(r Range) Range {
+	return Range{From: s.position(r.From), To: s.position(r.To)}
+}
+
+func (s positionShift) expression(e Expression) Expression {
+	e.Range = s.rng(e.Range)
+	return e
+}
+
+// templateFileNode returns a copy of the node, with its positions moved.
+func (s positionShift) templateFileNode(n TemplateFileNode) TemplateFileNode {
+	switch n := n.(type) {
+	case HTMLTemplate:
+		n.Expression = s.expression(n.Expression)
+		n.Extends = s.expression(n.Extends)
+		n.Children = s.nodes(n.Children)
+		return n
+	case CSSTemplate:
+		n.Expression = s.expression(n.Expression)
+		n.Properties = s.cssProperties(n.Properties)
+		return n
+	case ScriptTemplate:
+		n.Name = s.expression(n.Name)
+		n.Parameters = s.expression(n.Parameters)
+		return n
+	case TemplateFileGoExpression:
+		n.Expression = s.expression(n.Expression)
+		return n
+	}
+	return n
+}
+
+func (s positionShift) cssProperties(props []CSSProperty) []CSSProperty {
+	if props == nil {
+		return nil
+	}
+	shifted := make([]CSSProperty, len(props))
+	for i, p := range props {
+		switch p := p.(type) {
+		case ExpressionCSSProperty:
+			p.Value = s.node(p.Value).(StringExpression)
+			shifted[i] = p
+		case CSSRule:
+			p.Properties = s.cssProperties(p.Properties)
+			shifted[i] = p
+		default:
+			shifted[i] = p
+		}
+	}
+	return shifted
+}
+
+func (s positionShift) nodes(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	shifted := make([]Node, len(nodes))
+	for i, n := range nodes {
+		shifted[i] = s.node(n)
+	}
+	return shifted
+}
+
+// node returns a copy of the node, with its positions moved. Nodes without
+// positions, e.g. Whitespace or HTMLComment, are returned as they are.
+func (s positionShift) node(n Node) Node {
+	switch n := n.(type) {
+	case Text:
+		n.Range = s.rng(n.Range)
+		return n
+	case Element:
+		n.NameRange = s.rng(n.NameRange)
+		n.Attributes = s.attributes(n.Attributes)
+		n.Children = s.nodes(n.Children)
+		return n
+	case RawElement:
+		n.Attributes = s.attributes(n.Attributes)
+		return n
+	case CallTemplateExpression:
+		n.Expression = s.expression(n.Expression)
+		return n
+	case TemplElementExpression:
+		n.Expression = s.expression(n.Expression)
+		n.Children = s.nodes(n.Children)
+		return n
+	case IfExpression:
+		n.Expression = s.expression(n.Expression)
+		n.Then = s.nodes(n.Then)
+		if n.ElseIfs != nil {
+			elseIfs := make([]ElseIfExpression, len(n.ElseIfs))
+			for i, elseIf := range n.ElseIfs {
+				elseIf.Expression = s.expression(elseIf.Expression)
+				elseIf.Then = s.nodes(elseIf.Then)
+				elseIfs[i] = elseIf
+			}
+			n.ElseIfs = elseIfs
+		}
+		n.Else = s.nodes(n.Else)
+		return n
+	case SwitchExpression:
+		n.Expression = s.expression(n.Expression)
+		if n.Cases != nil {
+			cases := make([]CaseExpression, len(n.Cases))
+			for i, c := range n.Cases {
+				c.Expression = s.expression(c.Expression)
+				c.Children = s.nodes(c.Children)
+				cases[i] = c
+			}
+			n.Cases = cases
+		}
+		return n
+	case ForExpression:
+		n.Expression = s.expression(n.Expression)
+		n.Children = s.nodes(n.Children)
+		return n
+	case BranchStatement:
+		n.Range = s.rng(n.Range)
+		return n
+	case BlockExpression:
+		n.NameRange = s.rng(n.NameRange)
+		n.Children = s.nodes(n.Children)
+		return n
+	case StringExpression:
+		n.Expression = s.expression(n.Expression)
+		return n
+	}
+	return n
+}
+
+func (s positionShift) attributes(attrs []Attribute) []Attribute {
+	if attrs == nil {
+		return nil
+	}
+	shifted := make([]Attribute, len(attrs))
+	for i, a := range attrs {
+		shifted[i] = s.attribute(a)
+	}
+	return shifted
+}
+
+func (s positionShift) attribute(a Attribute) Attribute {
+	switch a := a.(type) {
+	case BoolConstantAttribute:
+		a.NameRange = s.rng(a.NameRange)
+		return a
+	case ConstantAttribute:
+		a.NameRange = s.rng(a.NameRange)
+		return a
+	case BoolExpressionAttribute:
+		a.NameRange = s.rng(a.NameRange)
+		a.Expression = s.expression(a.Expression)
+		return a
+	case ExpressionAttribute:
+		a.NameRange = s.rng(a.NameRange)
+		a.Expression = s.expression(a.Expression)
+		return a
+	case KeyExpressionAttribute:
+		a.Key = s.expression(a.Key)
+		a.Expression = s.expression(a.Expression)
+		return a
+	case SpreadAttributes:
+		a.Expression = s.expression(a.Expression)
+		return a
+	case ConditionalAttribute:
+		a.Expression = s.expression(a.Expression)
+		a.Then = s.attributes(a.Then)
+		a.Else = s.attributes(a.Else)
+		return a
+	}
+	return a
+}
@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const incrementalTestFile = `package main
+
+import "fmt"
+
+templ header(title string) {
+	<h1 class="title">{ title }</h1>
+}
+
+css red() {
+	color: red;
+}
+
+templ list(items []string) {
+	<ul>
+		for _, item := range items {
+			<li>{ item }</li>
+		}
+	</ul>
+}
+
+script greet(name string) {
+	alert(name);
+}
+
+templ footer() {
+	<footer>{ fmt.Sprint(2024) }</footer>
+}
+
+templ nav(items []string, selected string) {
+	<nav class={ "nav" } hidden?={ len(items) == 0 } { attrs... } if selected != "" { aria-current="page" } else { data-empty }>
+		outer: for _, item := range items {
+			switch item {
+				case selected:
+					<b>{ item }</b>
+				default:
+					if item == "" {
+						continue outer
+					} else if item == "end" {
+						break outer
+					} else {
+						@link(item) {
+							{ item }
+						}
+					}
+			}
+		}
+	</nav>
+	<script>alert(1);</script>
+}
+
+templ page(title string) extends layout(title) {
+	block content {
+		<h1>{ title }</h1>
+	}
+}
+`
+
+func TestIncrementalParser(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(src string) string
+		// reused is the number of nodes of the previous version that are reused.
+		reused int
+	}{
+		{
+			name: "editing a template reuses the templates before and after it",
+			edit: func(src string) string {
+				return strings.Replace(src, "<li>{ item }</li>", "<li class=\"item\">{ item }</li>", 1)
+			},
+			reused: 6,
+		},
+		{
+			name: "adding lines moves the positions of the templates after the edit",
+			edit: func(src string) string {
+				return strings.Replace(src, "\t<h1", "\t<p>Before</p>\n\t<p>the title</p>\n\t<h1", 1)
+			},
+			reused: 6,
+		},
+		{
+			name: "removing lines moves the positions of the templates after the edit",
+			edit: func(src string) string {
+				return strings.Replace(src, "\t\tfor _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}\n", "", 1)
+			},
+			reused: 6,
+		},
+		{
+			name: "editing Go code between templates",
+			edit: func(src string) string {
+				return strings.Replace(src, "import \"fmt\"", "import (\n\t\"fmt\"\n\t\"strings\"\n)", 1)
+			},
+			reused: 7,
+		},
+		{
+			name: "adding a template",
+			edit: func(src string) string {
+				return strings.Replace(src, "script greet", "templ added() {\n\t<p>Added</p>\n}\n\nscript greet", 1)
+			},
+			reused: 7,
+		},
+		{
+			name: "templates with errors aren't reused",
+			edit: func(src string) string {
+				return strings.Replace(src, "<ul>", "<ul class=>", 1)
+			},
+			reused: 6,
+		},
+		{
+			name: "editing the start of a template",
+			edit: func(src string) string {
+				return strings.Replace(src, "templ footer() {", "templ footer(year int) {", 1)
+			},
+			reused: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p IncrementalParser
+			if _, err := p.ParseString(incrementalTestFile); err != nil {
+				t.Fatalf("failed to parse the original file: %v", err)
+			}
+			src := tt.edit(incrementalTestFile)
+
+			if reused := len(newNodeCache(p.src, src, p.nodes).reusable); reused != tt.reused {
+				t.Errorf("expected %d reused nodes, got %d", tt.reused, reused)
+			}
+
+			expected, expectedErr := ParseString(src)
+			actual, actualErr := p.ParseString(src)
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(errString(expectedErr), errString(actualErr)); diff != "" {
+				t.Error(diff)
+			}
+
+			// Undo the edit.
+			expected, _ = ParseString(incrementalTestFile)
+			actual, _ = p.ParseString(incrementalTestFile)
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("after undoing the edit:\n%s", diff)
+			}
+		})
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
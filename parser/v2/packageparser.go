@@ -1,15 +1,20 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/a-h/parse"
 )
 
+// packagePrefix is the package keyword, followed by whitespace, e.g. "package\t".
+var packagePrefix = parse.All(parse.String("package"), parse.StringFrom(parse.OneOrMore(parse.RuneIn(" \t\r"))))
+
 // Package.
 var pkg = parse.Func(func(pi *parse.Input) (pkg Package, ok bool, err error) {
 	start := pi.Position()
 
 	// Package prefix.
-	if _, ok, err = parse.String("package ").Parse(pi); err != nil || !ok {
+	if _, ok, err = packagePrefix.Parse(pi); err != nil || !ok {
 		return
 	}
 
@@ -19,6 +24,7 @@ var pkg = parse.Func(func(pi *parse.Input) (pkg Package, ok bool, err error) {
 		err = parse.Error("package literal not terminated", pi.Position())
 		return
 	}
+	exp = strings.TrimSpace(exp)
 	if len(exp) == 0 {
 		ok = false
 		err = parse.Error("package literal not terminated", start)
@@ -80,6 +80,27 @@ func TestPackageParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "package: tab separated, with carriage return",
+			input: "package\tparser\r\n",
+			expected: Package{
+				Expression: Expression{
+					Value: "package parser",
+					Range: Range{
+						From: Position{
+							Index: 0,
+							Line:  0,
+							Col:   0,
+						},
+						To: Position{
+							Index: 14,
+							Line:  0,
+							Col:   14,
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
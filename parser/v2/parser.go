@@ -9,18 +9,35 @@ var expressionFuncEnd = parse.All(parse.Rune(')'), openBraceWithOptionalPadding)
 
 // Template
 
-var template = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err error) {
+var template = templateParser{}
+
+type templateParser struct {
+	// optionalEndTags is true if the end tags of elements such as <li> can be
+	// left out. See ParseOptions.
+	optionalEndTags bool
+}
+
+func (p templateParser) Parse(pi *parse.Input) (r HTMLTemplate, ok bool, err error) {
 	// templ FuncName(p Person, other Other) {
 	var te templateExpression
 	if te, ok, err = templateExpressionParser.Parse(pi); err != nil || !ok {
 		return
 	}
 	r.Expression = te.Expression
+	r.ContentType = te.ContentType
+	r.Extends = te.Extends
+
+	// Optional scoped style block.
+	if r.Style, _, err = scopedStyleParser.Parse(pi); err != nil {
+		return
+	}
 
 	// Once we're in a template, we should expect some template whitespace, if/switch/for,
-	// or node string expressions etc.
+	// or node string expressions etc. The void elements and optional end tags of
+	// HTML don't apply to XML.
 	var nodes Nodes
-	nodes, ok, err = newTemplateNodeParser(closeBraceWithOptionalPadding, "template closing brace").Parse(pi)
+	mode := nodeMode{xml: r.ContentType == ContentTypeXML, optionalEndTags: p.optionalEndTags}
+	nodes, ok, err = newTemplateNodeParser(closeBraceWithOptionalPadding, "template closing brace", mode).Parse(pi)
 	if err != nil {
 		return
 	}
@@ -28,7 +45,12 @@ var template = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err er
 		err = parse.Error("templ: expected nodes in templ body, but found none", pi.Position())
 		return
 	}
-	r.Children = nodes.Nodes
+	if r.Children, err = resolveBranchStatements(pi, nodes.Nodes, branchScope{}); err != nil {
+		return r, false, err
+	}
+	if r.Extends.Value != "" && !onlyBlocks(r.Children) {
+		return r, false, parse.Error("templ: a template that extends another template can only contain blocks", pi.Position())
+	}
 
 	// Eat any whitespace.
 	_, _, err = parse.OptionalWhitespace.Parse(pi)
@@ -43,4 +65,16 @@ var template = parse.Func(func(pi *parse.Input) (r HTMLTemplate, ok bool, err er
 	}
 
 	return r, true, nil
-})
+}
+
+// onlyBlocks returns true if the nodes are blocks, comments or whitespace.
+func onlyBlocks(nodes []Node) bool {
+	for _, n := range nodes {
+		switch n.(type) {
+		case BlockExpression, GoComment, HTMLComment, Whitespace:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// Within preformatted elements, such as <pre> and <textarea>, whitespace is
+// significant, so text is kept as-is, and the whitespace after elements and
+// expressions is kept as text instead of being collapsed into TrailingSpace.
+// Otherwise, the nodes are the same as elsewhere in templates.
+var preformattedNodeParsers = []parse.Parser[Node]{
+	docType,
+	xmlDeclaration,
+	htmlComment,
+	goComment,
+	rawElements,
+	elementParser{preformatted: true},
+	ifExpression,
+	forExpression,
+	blockExpression,
+	switchExpression,
+	callTemplateExpression,
+	templElementExpression,
+	trimWhitespaceExpression,
+	childrenExpression,
+	stringExpression,
+	preformattedText,
+}
+
+var preformattedNodes = parse.Func(func(pi *parse.Input) (op Nodes, ok bool, err error) {
+	for {
+		var node Node
+		var matched bool
+		if node, matched, err = preformattedStatement(pi); err != nil {
+			return Nodes{}, false, err
+		}
+		if !matched {
+			start := pi.Index()
+			parsers := preformattedNodeParsers
+			// Parsers such as the for loop parser skip whitespace, but it's text here.
+			if r, _ := pi.Peek(1); r != "" && strings.TrimSpace(r) == "" {
+				parsers = []parse.Parser[Node]{preformattedText}
+			}
+			for _, p := range parsers {
+				if node, matched, err = p.Parse(pi); err != nil {
+					return Nodes{}, false, err
+				}
+				if matched {
+					break
+				}
+			}
+			if !matched {
+				return op, true, nil
+			}
+			if _, isText := node.(Text); !isText {
+				// Leave the trailing whitespace to be read as text.
+				pi.Seek(indexBeforeWhitespace(pi, start))
+				node, _ = trimTrailingSpace(node)
+			}
+		}
+		op.Nodes = append(op.Nodes, node)
+	}
+})
+
+// preformattedStatement parses an if, for, switch or block statement on lines
+// of its own. The indentation before the statement and the line break after it
+// aren't part of the contents of the element.
+func preformattedStatement(pi *parse.Input) (n Node, ok bool, err error) {
+	if !atStartOfLine(pi) {
+		return nil, false, nil
+	}
+	start := pi.Index()
+	_, _, _ = horizontalWhitespace.Parse(pi)
+	for _, p := range []parse.Parser[Node]{ifExpression, forExpression, switchExpression, blockExpression} {
+		if n, ok, err = p.Parse(pi); err != nil {
+			return nil, false, err
+		}
+		if ok {
+			_, _, _ = horizontalWhitespace.Parse(pi)
+			if _, ok, _ = parse.NewLine.Parse(pi); ok {
+				return n, true, nil
+			}
+			break
+		}
+	}
+	pi.Seek(start)
+	return nil, false, nil
+}
+
+// preformattedText reads text, including whitespace, until a tag or templ expression opens,
+// or to the end of the line, so that statements can start on the next line.
+var preformattedText = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	var t Text
+	if t.Value, ok, err = parse.StringUntil(parse.Any(parse.Rune('<'), parse.Rune('{'), parse.Rune('\n'))).Parse(pi); err != nil || !ok {
+		return
+	}
+	if nl, hasNewLine, _ := parse.NewLine.Parse(pi); hasNewLine {
+		t.Value += nl
+	}
+	return t, len(t.Value) > 0, nil
+})
+
+// indexBeforeWhitespace returns the index of the start of any whitespace before the current
+// position of the input, without going back further than from.
+func indexBeforeWhitespace(pi *parse.Input, from int) int {
+	pos := pi.Index()
+	defer pi.Seek(pos)
+	end := pos
+	for end > from {
+		pi.Seek(end - 1)
+		if s, _ := pi.Peek(1); strings.TrimSpace(s) != "" {
+			break
+		}
+		end--
+	}
+	return end
+}
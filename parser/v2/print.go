@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+// Print writes the node as formatted templ source, as templ fmt would. The
+// node can be a TemplateFile, a TemplateFileNode, a Node, an Attribute, or a
+// CSSProperty.
+//
+// The template file is printed with the default FormatOptions, and other nodes
+// are printed without indentation.
+func Print(w io.Writer, node any) error {
+	switch n := node.(type) {
+	case TemplateFile:
+		return n.Write(w)
+	case TemplateFileNode:
+		return n.Write(w, 0)
+	case Node:
+		return n.Write(w, 0)
+	case Attribute:
+		return n.Write(w, 0)
+	case CSSProperty:
+		return n.Write(w, 0)
+	}
+	return fmt.Errorf("parser.Print: unexpected node type %T", node)
+}
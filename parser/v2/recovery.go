@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/a-h/parse"
+)
+
+// ParseErrors are the syntax errors of a template file.
+//
+// When the parser finds an error in an element or block, it records the error,
+// skips to the next line, and carries on parsing the nodes of the parent, so
+// that all of the errors of a file are reported at once.
+type ParseErrors []parse.ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, pe := range e {
+		msgs[i] = pe.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+var untilEndTagOrNewLine = parse.StringUntil(parse.Any(parse.String("</"), parse.NewLine))
+
+// recoveringInputs maps the inputs that are recovering from errors to the
+// errors found so far.
+var recoveringInputs sync.Map
+
+// add the error, unless it has already been found. Errors in the children of a
+// node that failed are found again when the parent skips the node.
+func (e *ParseErrors) add(err error, pos parse.Position) {
+	pe, ok := err.(parse.ParseError)
+	if !ok {
+		pe = parse.Error(err.Error(), pos)
+	}
+	for _, existing := range *e {
+		if existing == pe {
+			return
+		}
+	}
+	*e = append(*e, pe)
+}
+
+// recoverFrom records the error of the node that starts at the index, and skips
+// the input to the line after the start of the node. Expressions are only
+// skipped up to an end tag on the same line, so that the end tag still closes
+// the element that contains the expression. It returns false if the input isn't
+// recovering from errors.
+func recoverFrom(pi *parse.Input, start int, err error) bool {
+	errs, ok := recoveringInputs.Load(pi)
+	if !ok {
+		return false
+	}
+	errs.(*ParseErrors).add(err, pi.Position())
+	pi.Seek(start)
+	if !peekPrefix(pi, "<") {
+		// Always move forward, in case the end tag is the node that failed.
+		_, _ = pi.Take(1)
+		if _, ok, _ := untilEndTagOrNewLine.Parse(pi); ok && peekPrefix(pi, "</") {
+			return true
+		}
+	}
+	_, _, _ = stringUntilNewLineOrEOF.Parse(pi)
+	_, _, _ = parse.NewLine.Parse(pi)
+	return true
+}
+
+// errorCount returns the number of errors found so far in the input.
+func errorCount(pi *parse.Input) int {
+	errs, ok := recoveringInputs.Load(pi)
+	if !ok {
+		return 0
+	}
+	return len(*errs.(*ParseErrors))
+}
+
+// parseWithRecovery parses the input, recording errors instead of stopping at
+// the first error. If errors were found, the first error is returned, or
+// ParseErrors if there's more than one.
+func parseWithRecovery[T any](pi *parse.Input, p func(*parse.Input) (T, bool, error)) (r T, ok bool, err error) {
+	errs := new(ParseErrors)
+	recoveringInputs.Store(pi, errs)
+	defer recoveringInputs.Delete(pi)
+	if r, ok, err = p(pi); err != nil {
+		if _, isParseError := err.(parse.ParseError); !isParseError && len(*errs) == 0 {
+			// e.g. ErrLegacyFileFormat.
+			return r, ok, err
+		}
+		errs.add(err, pi.Position())
+	}
+	switch len(*errs) {
+	case 0:
+		return r, ok, nil
+	case 1:
+		return r, false, (*errs)[0]
+	}
+	sort.SliceStable(*errs, func(i, j int) bool {
+		return (*errs)[i].Pos.Index < (*errs)[j].Pos.Index
+	})
+	return r, false, *errs
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseErrorRecovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		// templates that are parsed, despite the errors.
+		templates int
+	}{
+		{
+			name: "errors in elements, expressions and blocks of different templates are all reported",
+			input: `package p
+
+templ a() {
+	<div>
+		<p class=>bad</p>
+		<span>ok</span>
+		<b>{ x </b>
+	</div>
+}
+
+templ b() {
+	if x
+		<p>y</p>
+	}
+}
+
+templ c() {
+	<a href="x>link</a>
+}
+`,
+			expected: []string{
+				"<p>: malformed open element: line 4, col 5",
+				"string expression: missing close brace: line 6, col 7",
+				"if: unterminated (missing closing '{\\n') - https://templ.guide/syntax-and-usage/statements#incomplete-statements: line 11, col 1",
+				"<a>: malformed open element: line 17, col 4",
+			},
+			templates: 3,
+		},
+		{
+			name: "an end tag on the same line as an invalid expression closes its element",
+			input: `package p
+
+templ a() {
+	<ul>
+		for _, x := range xs {
+			<li>{ x.Name( }</li>
+		}
+	</ul>
+	@comp(x, {
+}
+`,
+			expected: []string{
+				"string expression: missing close brace: line 5, col 9",
+				"templ element: invalid go expression: unbalanced '(': line 8, col 6",
+			},
+			templates: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString(tt.input)
+			errs, ok := err.(ParseErrors)
+			if !ok {
+				t.Fatalf("expected ParseErrors, got %T: %v", err, err)
+			}
+			var actual []string
+			for _, pe := range errs {
+				actual = append(actual, pe.Error())
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			var templates int
+			for _, n := range tf.Nodes {
+				if _, isTemplate := n.(HTMLTemplate); isTemplate {
+					templates++
+				}
+			}
+			if templates != tt.templates {
+				t.Errorf("expected %d templates, got %d", tt.templates, templates)
+			}
+		})
+	}
+	t.Run("a single error is returned as a parse.ParseError", func(t *testing.T) {
+		_, err := ParseString("package p\n\ntempl a() {\n\t<p class=>bad</p>\n}\n")
+		if _, ok := err.(parse.ParseError); !ok {
+			t.Errorf("expected parse.ParseError, got %T: %v", err, err)
+		}
+	})
+	t.Run("sub-parsers stop at the first error", func(t *testing.T) {
+		_, _, err := template.Parse(parse.NewInput("templ a() {\n\t<p class=>bad</p>\n\t<b>{ x </b>\n}\n"))
+		if _, ok := err.(parse.ParseError); !ok {
+			t.Errorf("expected parse.ParseError, got %T: %v", err, err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// scopedStyleParser parses the optional style block at the start of a template.
+//
+//	style {
+//		.title { color: red; }
+//	}
+var scopedStyleParser = parse.Func(func(pi *parse.Input) (s *ScopedStyle, ok bool, err error) {
+	start := pi.Index()
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	if !peekPrefix(pi, "style {", "style{", "style\n") {
+		pi.Seek(start)
+		return nil, false, nil
+	}
+	pi.Take(len("style"))
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	if !peekPrefix(pi, "{") {
+		pi.Seek(start)
+		return nil, false, nil
+	}
+	pi.Take(1)
+
+	// Read until the matching closing brace, ignoring braces in strings and comments.
+	var sb strings.Builder
+	depth := 1
+	for {
+		var c string
+		if c, ok = pi.Take(1); !ok {
+			err = parse.Error("style: missing closing brace", pi.PositionAt(start))
+			return
+		}
+		switch c {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				// Eat the newline after the closing brace.
+				_, _, err = parse.Optional(parse.NewLine).Parse(pi)
+				return &ScopedStyle{Contents: sb.String()}, true, err
+			}
+		case `"`, "'":
+			var str string
+			if str, ok, _ = parse.StringUntil(parse.String(c)).Parse(pi); !ok {
+				err = parse.Error("style: unterminated string", pi.PositionAt(start))
+				return
+			}
+			pi.Take(1)
+			c += str + c
+		case "/":
+			if peekPrefix(pi, "*") {
+				var comment string
+				if comment, ok, _ = parse.StringUntil(parse.String("*/")).Parse(pi); !ok {
+					err = parse.Error("style: unterminated comment", pi.PositionAt(start))
+					return
+				}
+				pi.Take(2)
+				c += comment + "*/"
+			}
+		}
+		sb.WriteString(c)
+	}
+})
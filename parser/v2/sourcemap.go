@@ -16,12 +16,23 @@ func NewSourceMap() *SourceMap {
 type SourceMap struct {
 	SourceLinesToTarget map[uint32]map[uint32]Position
 	TargetLinesToSource map[uint32]map[uint32]Position
+	// expressions are the source and target ranges of the expressions that have
+	// been added, in the order they were added.
+	expressions []mappedExpression
+}
+
+type mappedExpression struct {
+	source, target Range
 }
 
 // Add an item to the lookup.
 func (sm *SourceMap) Add(src Expression, tgt Range) (updatedFrom Position) {
 	srcIndex := src.Range.From.Index
 	tgtIndex := tgt.From.Index
+	mapped := mappedExpression{
+		source: Range{From: src.Range.From},
+		target: Range{From: tgt.From},
+	}
 
 	lines := strings.Split(src.Value, "\n")
 	for lineIndex, line := range lines {
@@ -53,6 +64,10 @@ func (sm *SourceMap) Add(src Expression, tgt Range) (updatedFrom Position) {
 			tgtIndex++
 		}
 
+		// The expression ends after the last char of its last line.
+		mapped.source.To = NewPosition(srcIndex, srcLine, srcCol)
+		mapped.target.To = NewPosition(tgtIndex, tgtLine, tgtCol)
+
 		// LSPs include the newline char as a col.
 		if _, ok := sm.SourceLinesToTarget[srcLine]; !ok {
 			sm.SourceLinesToTarget[srcLine] = make(map[uint32]Position)
@@ -67,6 +82,7 @@ func (sm *SourceMap) Add(src Expression, tgt Range) (updatedFrom Position) {
 		srcIndex++
 		tgtIndex++
 	}
+	sm.expressions = append(sm.expressions, mapped)
 	return src.Range.From
 }
 
@@ -89,3 +105,93 @@ func (sm *SourceMap) SourcePositionFromTarget(line, col uint32) (src Position, o
 	src, ok = lm[col]
 	return
 }
+
+// TargetRangeFromSource looks up the target range using the source range. See
+// SourceRangeFromTarget.
+func (sm *SourceMap) TargetRangeFromSource(from, to Position) (tgt Range, ok bool) {
+	return sm.mapRange(from, to, func(e mappedExpression) (Range, Range) { return e.source, e.target }, sm.TargetPositionFromSource)
+}
+
+// SourceRangeFromTarget looks up the source range using the target range, e.g.
+// to map the range of a diagnostic in the generated Go code to the templ file.
+//
+// Unlike looking up the start and end positions separately, the range is
+// clamped to the expression that contains it, so that ranges that start before,
+// or end after, an expression, e.g. a diagnostic of a whole Go statement, still
+// map to the expression, instead of to the wrong lines, or to an empty range. Only
+// the line and column of the positions are used.
+func (sm *SourceMap) SourceRangeFromTarget(from, to Position) (src Range, ok bool) {
+	return sm.mapRange(from, to, func(e mappedExpression) (Range, Range) { return e.target, e.source }, sm.SourcePositionFromTarget)
+}
+
+// mapRange maps the range, using the direction function to get the ranges of an
+// expression that are mapped from and to, and the lookup function to map a
+// position.
+func (sm *SourceMap) mapRange(from, to Position, direction func(e mappedExpression) (from, to Range), lookup func(line, col uint32) (Position, bool)) (r Range, ok bool) {
+	if comparePositions(to, from) < 0 {
+		to = from
+	}
+	// Expressions added later take precedence, in the same way that they replace
+	// the positions of the expressions that were added before them.
+	e := -1
+	for i := len(sm.expressions) - 1; i >= 0; i-- {
+		if mappedFrom, _ := direction(sm.expressions[i]); rangeContains(mappedFrom, from) {
+			e = i
+			break
+		}
+	}
+	if e < 0 {
+		// The range may start before the expression, so the first expression that
+		// starts within the range is used.
+		var start Position
+		for i, expr := range sm.expressions {
+			mappedFrom, _ := direction(expr)
+			if !rangeContains(Range{From: from, To: to}, mappedFrom.From) {
+				continue
+			}
+			if e < 0 || comparePositions(mappedFrom.From, start) < 0 {
+				e, start = i, mappedFrom.From
+			}
+		}
+		if e < 0 {
+			return r, false
+		}
+	}
+	mappedFrom, mappedTo := direction(sm.expressions[e])
+	r = mappedTo
+	if rangeContains(mappedFrom, from) {
+		if r.From, ok = lookup(from.Line, from.Col); !ok {
+			r.From = mappedTo.From
+		}
+	}
+	if rangeContains(mappedFrom, to) {
+		if r.To, ok = lookup(to.Line, to.Col); !ok {
+			r.To = mappedTo.To
+		}
+	}
+	if comparePositions(r.To, r.From) < 0 {
+		r.To = r.From
+	}
+	return r, true
+}
+
+func rangeContains(r Range, p Position) bool {
+	return comparePositions(r.From, p) <= 0 && comparePositions(p, r.To) <= 0
+}
+
+// comparePositions compares the lines and columns of the positions.
+func comparePositions(a, b Position) int {
+	switch {
+	case a.Line != b.Line:
+		if a.Line < b.Line {
+			return -1
+		}
+		return 1
+	case a.Col != b.Col:
+		if a.Col < b.Col {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
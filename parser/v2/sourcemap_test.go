@@ -154,3 +154,78 @@ func keys[K comparable, V any](m map[K]V) (keys []K) {
 	}
 	return
 }
+
+func TestSourceMapRange(t *testing.T) {
+	// The expression "multi\nline\nmatch" is at line 1, col 2 of the source, and
+	// line 5, col 10 of the target.
+	sm := NewSourceMap()
+	sm.Add(NewExpression("multi\nline\nmatch", pos(2, 1, 2), pos(18, 3, 5)),
+		Range{From: NewPosition(30, 5, 10), To: NewPosition(46, 7, 5)})
+	var tests = []struct {
+		name       string
+		from, to   Position
+		expected   Range
+		expectedOK bool
+	}{
+		{
+			name:       "ranges within a line are mapped",
+			from:       NewPosition(0, 5, 10),
+			to:         NewPosition(0, 5, 15),
+			expected:   Range{From: NewPosition(2, 1, 2), To: NewPosition(7, 1, 7)},
+			expectedOK: true,
+		},
+		{
+			name:       "ranges across lines are mapped",
+			from:       NewPosition(0, 6, 1),
+			to:         NewPosition(0, 7, 3),
+			expected:   Range{From: NewPosition(9, 2, 1), To: NewPosition(16, 3, 3)},
+			expectedOK: true,
+		},
+		{
+			name:       "ranges that end after the expression end at the end of the expression",
+			from:       NewPosition(0, 6, 0),
+			to:         NewPosition(0, 9, 2),
+			expected:   Range{From: NewPosition(8, 2, 0), To: NewPosition(18, 3, 5)},
+			expectedOK: true,
+		},
+		{
+			name:       "ranges that start before the expression start at the start of the expression",
+			from:       NewPosition(0, 4, 0),
+			to:         NewPosition(0, 5, 12),
+			expected:   Range{From: NewPosition(2, 1, 2), To: NewPosition(4, 1, 4)},
+			expectedOK: true,
+		},
+		{
+			name:       "ranges around the expression are the whole expression",
+			from:       NewPosition(0, 4, 0),
+			to:         NewPosition(0, 8, 0),
+			expected:   Range{From: NewPosition(2, 1, 2), To: NewPosition(18, 3, 5)},
+			expectedOK: true,
+		},
+		{
+			name: "ranges outside of expressions are not mapped",
+			from: NewPosition(0, 8, 0),
+			to:   NewPosition(0, 9, 0),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := sm.SourceRangeFromTarget(tt.from, tt.to)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok to be %v, got %v", tt.expectedOK, ok)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+			if !ok {
+				return
+			}
+			// The source range maps back to the part of the target range that's
+			// within the expression.
+			if _, ok := sm.TargetRangeFromSource(actual.From, actual.To); !ok {
+				t.Error("expected the source range to map back to the target")
+			}
+		})
+	}
+}
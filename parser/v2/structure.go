@@ -25,9 +25,11 @@ var (
 	_ Node = IfExpression{}
 	_ Node = SwitchExpression{}
 	_ Node = ForExpression{}
+	_ Node = BlockExpression{}
 	_ Node = StringExpression{}
 	_ Node = Whitespace{}
 	_ Node = DocType{}
+	_ Node = XMLDeclaration{}
 )
 
 // Element nodes can have the following attributes.
@@ -36,6 +38,7 @@ var (
 	_ Attribute = ConstantAttribute{}
 	_ Attribute = BoolExpressionAttribute{}
 	_ Attribute = ExpressionAttribute{}
+	_ Attribute = KeyExpressionAttribute{}
 	_ Attribute = SpreadAttributes{}
 	_ Attribute = ConditionalAttribute{}
 )
@@ -7,9 +7,11 @@ import (
 
 var switchExpression parse.Parser[Node] = switchExpressionParser{}
 
-type switchExpressionParser struct{}
+type switchExpressionParser struct {
+	mode nodeMode
+}
 
-func (_ switchExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+func (p switchExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 	var r SwitchExpression
 	start := pi.Index()
 
@@ -35,7 +37,7 @@ func (_ switchExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err err
 	// Read the optional 'case' nodes.
 	for {
 		var ce CaseExpression
-		ce, ok, err = caseExpressionParser.Parse(pi)
+		ce, ok, err = caseExpressionParser{mode: p.mode}.Parse(pi)
 		if err != nil {
 			return
 		}
@@ -79,13 +81,17 @@ var caseExpressionStartParser = parse.Func(func(pi *parse.Input) (r Expression,
 	return r, true, nil
 })
 
-var caseExpressionParser = parse.Func(func(pi *parse.Input) (r CaseExpression, ok bool, err error) {
+type caseExpressionParser struct {
+	mode nodeMode
+}
+
+func (p caseExpressionParser) Parse(pi *parse.Input) (r CaseExpression, ok bool, err error) {
 	if r.Expression, ok, err = caseExpressionStartParser.Parse(pi); err != nil || !ok {
 		return
 	}
 
 	// Read until the next case statement, default, or end of the block.
-	pr := newTemplateNodeParser(parse.Any(StripType(closeBraceWithOptionalPadding), StripType(caseExpressionStartParser)), "closing brace or case expression")
+	pr := newControlFlowNodeParser(parse.Any(StripType(closeBraceWithOptionalPadding), StripType(caseExpressionStartParser)), "closing brace or case expression", p.mode)
 	var nodes Nodes
 	if nodes, ok, err = pr.Parse(pi); err != nil || !ok {
 		err = parse.Error("case: expected nodes, but none were found", pi.Position())
@@ -99,4 +105,4 @@ var caseExpressionParser = parse.Func(func(pi *parse.Input) (r CaseExpression, o
 	}
 
 	return r, true, nil
-})
+}
@@ -43,7 +43,23 @@ func isGoIdentifier(s string) bool {
 }
 
 func ParseString(template string) (TemplateFile, error) {
-	tf, ok, err := NewTemplateFileParser("main").Parse(parse.NewInput(template))
+	return ParseStringWithOptions(template, ParseOptions{})
+}
+
+// ParseOptions configure how template files are parsed.
+type ParseOptions struct {
+	// OptionalEndTags allows the end tags of elements such as <li>, <p> and <td>
+	// to be left out, as in HTML. The element is closed by the next element that
+	// closes it, e.g. the next <li>, or by the end tag of its parent. templ fmt
+	// uses it to add the end tags, see TemplateFile.WriteWithOptions.
+	OptionalEndTags bool
+}
+
+// ParseStringWithOptions parses the template file using the parsing options.
+func ParseStringWithOptions(template string, opts ParseOptions) (TemplateFile, error) {
+	p := NewTemplateFileParser("main")
+	p.Options = opts
+	tf, ok, err := p.Parse(parse.NewInput(template))
 	if err != nil {
 		return tf, err
 	}
@@ -65,11 +81,21 @@ var ErrTemplateNotFound = errors.New("template not found")
 
 type TemplateFileParser struct {
 	DefaultPackage string
+	// Options configure how the template file is parsed.
+	Options ParseOptions
+	// cache of the nodes of the previous version of the file. See IncrementalParser.
+	cache *nodeCache
 }
 
 var legacyPackageParser = parse.String("{% package")
 
+// Parse the template file. Errors in templates don't stop the parser, so that
+// all of the errors of the file are returned. See ParseErrors.
 func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, err error) {
+	return parseWithRecovery(pi, p.parse)
+}
+
+func (p TemplateFileParser) parse(pi *parse.Input) (tf TemplateFile, ok bool, err error) {
 	// If we're parsing a legacy file, complain that migration needs to happen.
 	_, ok, err = legacyPackageParser.Parse(pi)
 	if err != nil {
@@ -79,7 +105,8 @@ func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, er
 		return tf, false, ErrLegacyFileFormat
 	}
 
-	// Read until the package.
+	// Read until the package. If there's no package, there's no header either.
+	start := pi.Index()
 	for {
 		// Package.
 		// package name
@@ -98,6 +125,8 @@ func (p TemplateFileParser) Parse(pi *parse.Input) (tf TemplateFile, ok bool, er
 			return
 		}
 		if !ok {
+			tf.Header = nil
+			pi.Seek(start)
 			break
 		}
 		var newLine string
@@ -112,12 +141,23 @@ outer:
 	for {
 		// Optional templates, CSS, and script templates.
 		// templ Name(p Parameter)
+		start := pi.Index()
+		if n, ok := p.cache.get(pi, start); ok {
+			tf.Nodes = append(tf.Nodes, n)
+			_, _, _ = parse.OptionalWhitespace.Parse(pi)
+			continue
+		}
+		errs := errorCount(pi)
 		var tn HTMLTemplate
-		tn, ok, err = template.Parse(pi)
+		tn, ok, err = templateParser{optionalEndTags: p.Options.OptionalEndTags}.Parse(pi)
 		if err != nil {
+			if recoverFrom(pi, start, err) {
+				continue
+			}
 			return tf, false, err
 		}
 		if ok {
+			p.cache.add(pi, start, errs, tn)
 			tf.Nodes = append(tf.Nodes, tn)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
 			continue
@@ -127,9 +167,13 @@ outer:
 		var cn CSSTemplate
 		cn, ok, err = cssParser.Parse(pi)
 		if err != nil {
+			if recoverFrom(pi, start, err) {
+				continue
+			}
 			return tf, false, err
 		}
 		if ok {
+			p.cache.add(pi, start, errs, cn)
 			tf.Nodes = append(tf.Nodes, cn)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
 			continue
@@ -139,9 +183,13 @@ outer:
 		var sn ScriptTemplate
 		sn, ok, err = scriptTemplateParser.Parse(pi)
 		if err != nil {
+			if recoverFrom(pi, start, err) {
+				continue
+			}
 			return tf, false, err
 		}
 		if ok {
+			p.cache.add(pi, start, errs, sn)
 			tf.Nodes = append(tf.Nodes, sn)
 			_, _, _ = parse.OptionalWhitespace.Parse(pi)
 			continue
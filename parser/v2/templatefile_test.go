@@ -340,3 +340,52 @@ func TestDefaultPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStringWithOptionalEndTags(t *testing.T) {
+	input := `package p
+
+templ list() {
+<ul>
+<li>One
+<li>Two <span>2</span>
+</ul>
+<table><tr><td>a<td>b<tr><td>c</table>
+}
+
+templ feed() xml {
+	<item><p>a</p></item>
+}
+`
+	t.Run("by default, end tags are required", func(t *testing.T) {
+		if _, err := ParseString(input); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+	t.Run("end tags can be left out, and are added when the template is written", func(t *testing.T) {
+		tf, err := ParseStringWithOptions(input, ParseOptions{OptionalEndTags: true})
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		expected := `package p
+
+templ list() {
+	<ul>
+		<li>One</li>
+		<li>Two <span>2</span></li>
+	</ul>
+	<table><tr><td>a</td><td>b</td></tr><tr><td>c</td></tr></table>
+}
+
+templ feed() xml {
+	<item><p>a</p></item>
+}
+`
+		w := new(strings.Builder)
+		if err = tf.Write(w); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+		if diff := cmp.Diff(expected, w.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
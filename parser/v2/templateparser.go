@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
 )
 
 // TemplateExpression.
@@ -12,8 +13,52 @@ import (
 // templ Func(p Parameter) {
 // templ (data Data) Func(p Parameter) {
 // templ (data []string) Func(p Parameter) {
+// templ Func(p Parameter) xml {
 type templateExpression struct {
-	Expression Expression
+	Expression  Expression
+	ContentType ContentType
+	Extends     Expression
+}
+
+// contentTypeParser parses the optional content type that follows the parameters
+// of a template, e.g. the "xml" in "templ Sitemap() xml {".
+var contentTypeParser = parse.Func(func(pi *parse.Input) (ct ContentType, ok bool, err error) {
+	start := pi.Index()
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	for _, c := range []ContentType{ContentTypeXML, ContentTypeText} {
+		if peekPrefix(pi, string(c)+" ", string(c)+"{") {
+			pi.Take(len(c))
+			return c, true, nil
+		}
+	}
+	pi.Seek(start)
+	return ct, false, nil
+})
+
+// extendsParser parses the optional template that a template extends, e.g. the
+// "extends Base("Home")" in "templ Home() extends Base("Home") {".
+func extendsParser(pi *parse.Input) (r Expression, err error) {
+	start := pi.Index()
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	if !peekPrefix(pi, "extends ") {
+		pi.Seek(start)
+		return r, nil
+	}
+	pi.Take(len("extends"))
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	if r, err = parseGo("extends", pi, goexpression.TemplExpression); err != nil {
+		return r, err
+	}
+	if r.Value == "" {
+		return r, parse.Error("templ: expected the template to extend after `extends`", pi.Position())
+	}
+	return r, nil
 }
 
 var templateExpressionParser = parse.Func(func(pi *parse.Input) (r templateExpression, ok bool, err error) {
@@ -32,6 +77,16 @@ var templateExpressionParser = parse.Func(func(pi *parse.Input) (r templateExpre
 		return r, false, err
 	}
 
+	// Optional content type, e.g. xml.
+	if r.ContentType, _, err = contentTypeParser.Parse(pi); err != nil {
+		return r, false, err
+	}
+
+	// Optional template that's extended, e.g. extends Base("Home").
+	if r.Extends, err = extendsParser(pi); err != nil {
+		return r, false, err
+	}
+
 	// Eat " {\n".
 	if _, ok, err = parse.All(openBraceWithOptionalPadding, parse.StringFrom(parse.Optional(parse.NewLine))).Parse(pi); err != nil || !ok {
 		err = parse.Error("templ: malformed templ expression, expected `templ functionName() {`", pi.PositionAt(start))
@@ -47,35 +102,93 @@ const (
 )
 
 // Template node (element, call, if, switch, for, whitespace etc.)
-func newTemplateNodeParser[TUntil any](until parse.Parser[TUntil], untilName string) templateNodeParser[TUntil] {
+func newTemplateNodeParser[TUntil any](until parse.Parser[TUntil], untilName string, mode nodeMode) templateNodeParser[TUntil] {
 	return templateNodeParser[TUntil]{
 		until:     until,
 		untilName: untilName,
+		mode:      mode,
+	}
+}
+
+// newControlFlowNodeParser returns a parser of the nodes of an if, for or
+// switch block, which can include break, continue and return statements.
+func newControlFlowNodeParser[TUntil any](until parse.Parser[TUntil], untilName string, mode nodeMode) templateNodeParser[TUntil] {
+	return templateNodeParser[TUntil]{
+		until:      until,
+		untilName:  untilName,
+		statements: true,
+		mode:       mode,
 	}
 }
 
 type templateNodeParser[TUntil any] struct {
 	until     parse.Parser[TUntil]
 	untilName string
+	// statements is true if the nodes can include break, continue and return
+	// statements. Elsewhere, e.g. in elements, they're text.
+	statements bool
+	mode       nodeMode
+}
+
+// nodeMode is how the nodes of a template are parsed.
+type nodeMode struct {
+	// xml is true if the nodes are in an xml template, in which elements such
+	// as <link> aren't void, and end tags are required.
+	xml bool
+	// optionalEndTags is true if the end tags of elements such as <li> can be
+	// left out. See ParseOptions.
+	optionalEndTags bool
 }
 
 var rawElements = parse.Any[Node](styleElement, scriptElement)
 
-var templateNodeParsers = []parse.Parser[Node]{
-	docType,                // <!DOCTYPE html>
-	htmlComment,            // <!--
-	goComment,              // // or /*
-	rawElements,            // <text>, <>, or <style> element (special behaviour - contents are not parsed).
-	element,                // <a>, <br/> etc.
-	ifExpression,           // if {}
-	forExpression,          // for {}
-	switchExpression,       // switch {}
-	callTemplateExpression, // {! TemplateName(a, b, c) }
-	templElementExpression, // @TemplateName(a, b, c) { <div>Children</div> }
-	childrenExpression,     // { children... }
-	stringExpression,       // { "abc" }
-	whitespaceExpression,   // { " " }
-	textParser,             // anything &amp; everything accepted...
+// templateNodeParsers parse the nodes of templates, and controlFlowNodeParsers
+// parse the nodes of if, for and switch blocks, in each mode.
+var templateNodeParsers, controlFlowNodeParsers = newNodeParsers()
+
+func newNodeParsers() (template, controlFlow map[nodeMode][]parse.Parser[Node]) {
+	template = make(map[nodeMode][]parse.Parser[Node])
+	controlFlow = make(map[nodeMode][]parse.Parser[Node])
+	for _, mode := range []nodeMode{{}, {xml: true}, {optionalEndTags: true}, {xml: true, optionalEndTags: true}} {
+		template[mode] = newTemplateNodeParsers(mode)
+		controlFlow[mode] = append([]parse.Parser[Node]{branchStatement}, template[mode]...)
+	}
+	return template, controlFlow
+}
+
+func newTemplateNodeParsers(mode nodeMode) []parse.Parser[Node] {
+	return []parse.Parser[Node]{
+		docType,                                  // <!DOCTYPE html>
+		xmlDeclaration,                           // <?xml version="1.0"?>
+		htmlComment,                              // <!--
+		goComment,                                // // or /*
+		rawElements,                              // <text>, <>, or <style> element (special behaviour - contents are not parsed).
+		elementParser{mode: mode},                // <a>, <br/> etc.
+		ifExpressionParser{mode: mode},           // if {}
+		forExpressionParser{mode: mode},          // for {}
+		blockExpressionParser{mode: mode},        // block name {}
+		switchExpressionParser{mode: mode},       // switch {}
+		callTemplateExpression,                   // {! TemplateName(a, b, c) }
+		templElementExpressionParser{mode: mode}, // @TemplateName(a, b, c) { <div>Children</div> }
+		trimWhitespaceExpression,                 // {-}
+		childrenExpression,                       // { children... }
+		stringExpression,                         // { "abc" }
+		whitespaceExpression,                     // { " " }
+		textParser,                               // anything &amp; everything accepted...
+	}
+}
+
+// trimLastNode removes the whitespace at the end of the nodes.
+func trimLastNode(nodes []Node) []Node {
+	if len(nodes) == 0 {
+		return nodes
+	}
+	last := len(nodes) - 1
+	if _, isWhitespace := nodes[last].(Whitespace); isWhitespace {
+		return nodes[:last]
+	}
+	nodes[last], _ = trimTrailingSpace(nodes[last])
+	return nodes
 }
 
 func (p templateNodeParser[T]) Parse(pi *parse.Input) (op Nodes, ok bool, err error) {
@@ -96,13 +209,25 @@ func (p templateNodeParser[T]) Parse(pi *parse.Input) (op Nodes, ok bool, err er
 		// Attempt to parse a node.
 		// Loop through the parsers and try to parse a node.
 		var matched bool
-		for _, p := range templateNodeParsers {
+		nodeStart := pi.Index()
+		parsers := templateNodeParsers[p.mode]
+		if p.statements {
+			parsers = controlFlowNodeParsers[p.mode]
+		}
+		for _, p := range parsers {
 			var node Node
 			node, matched, err = p.Parse(pi)
 			if err != nil {
+				if recoverFrom(pi, nodeStart, err) {
+					matched = true
+					break
+				}
 				return Nodes{}, false, err
 			}
 			if matched {
+				if _, isTrim := node.(TrimWhitespace); isTrim {
+					op.Nodes = trimLastNode(op.Nodes)
+				}
 				op.Nodes = append(op.Nodes, node)
 				break
 			}
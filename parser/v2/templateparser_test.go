@@ -36,6 +36,121 @@ func TestTemplateParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "template: extends",
+			input: `templ Home(title string) extends Base(title) {
+	block content {
+	}
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Home(title string)",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 24, Line: 0, Col: 24},
+					},
+				},
+				Extends: Expression{
+					Value: "Base(title)",
+					Range: Range{
+						From: Position{Index: 33, Line: 0, Col: 33},
+						To:   Position{Index: 44, Line: 0, Col: 44},
+					},
+				},
+				Children: []Node{
+					BlockExpression{
+						Name: "content",
+						NameRange: Range{
+							From: Position{Index: 54, Line: 1, Col: 7},
+							To:   Position{Index: 61, Line: 1, Col: 14},
+						},
+						Children: []Node{Whitespace{Value: "\t"}},
+					},
+					Whitespace{Value: "\n"},
+				},
+			},
+		},
+		{
+			name: "template: templates that extend another template can only contain blocks",
+			input: `templ Home() extends Base() {
+	<p>Home</p>
+}`,
+			expectError: true,
+		},
+		{
+			name: "template: xml content type",
+			input: `templ Sitemap() xml {
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Sitemap()",
+					Range: Range{
+						From: Position{
+							Index: 6,
+							Line:  0,
+							Col:   6,
+						},
+						To: Position{
+							Index: 15,
+							Line:  0,
+							Col:   15,
+						},
+					},
+				},
+				ContentType: ContentTypeXML,
+			},
+		},
+		{
+			name: "template: scoped style",
+			input: `templ Card() {
+	style {
+		.title { content: "{"; } /* } */
+	}
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Card()",
+					Range: Range{
+						From: Position{
+							Index: 6,
+							Line:  0,
+							Col:   6,
+						},
+						To: Position{
+							Index: 12,
+							Line:  0,
+							Col:   12,
+						},
+					},
+				},
+				Style: &ScopedStyle{
+					Contents: "\n\t\t.title { content: \"{\"; } /* } */\n\t",
+				},
+			},
+		},
+		{
+			name: "template: text content type",
+			input: `templ Robots()text{
+}`,
+			expected: HTMLTemplate{
+				Expression: Expression{
+					Value: "Robots()",
+					Range: Range{
+						From: Position{
+							Index: 6,
+							Line:  0,
+							Col:   6,
+						},
+						To: Position{
+							Index: 14,
+							Line:  0,
+							Col:   14,
+						},
+					},
+				},
+				ContentType: ContentTypeText,
+			},
+		},
 		{
 			name: "template: with receiver",
 			input: `templ (data Data) Name() {
@@ -431,6 +546,7 @@ func TestTemplateParser(t *testing.T) {
 							From: Position{Index: 28, Line: 1, Col: 2},
 							To:   Position{Index: 33, Line: 1, Col: 7},
 						},
+						SelfClosing: true,
 						Attributes: []Attribute{
 							ConstantAttribute{
 								Name:  "type",
@@ -457,6 +573,7 @@ func TestTemplateParser(t *testing.T) {
 							From: Position{Index: 61, Line: 2, Col: 2},
 							To:   Position{Index: 66, Line: 2, Col: 7},
 						},
+						SelfClosing: true,
 						Attributes: []Attribute{
 							ConstantAttribute{
 								Name:  "type",
@@ -580,7 +697,21 @@ func TestTemplateParser(t *testing.T) {
 								},
 							},
 							Whitespace{Value: " "},
-							Text{Value: "Home"},
+							Text{
+								Range: Range{
+									From: Position{
+										Index: 48,
+										Line:  1,
+										Col:   36,
+									},
+									To: Position{
+										Index: 52,
+										Line:  1,
+										Col:   40,
+									},
+								},
+								Value: "Home",
+							},
 						},
 						TrailingSpace: SpaceVertical,
 					},
@@ -784,3 +915,44 @@ func TestTemplateParserErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateParserXMLElements(t *testing.T) {
+	input := parse.NewInput(`templ Feed() xml {
+	<link>https://example.com</link>
+	<item><p>a</p></item>
+	if ok {
+		<link>https://example.com/ok</link>
+	}
+}`)
+	actual, ok, err := template.Parse(input)
+	if err != nil || !ok {
+		t.Fatalf("unexpected failure, ok=%v, err=%v", ok, err)
+	}
+	var names []string
+	var children []int
+	var ifExpr IfExpression
+	for _, n := range actual.Children {
+		switch n := n.(type) {
+		case Element:
+			names = append(names, n.Name)
+			children = append(children, len(n.Children))
+		case IfExpression:
+			ifExpr = n
+		}
+	}
+	if diff := cmp.Diff([]string{"link", "item"}, names); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]int{1, 1}, children); diff != "" {
+		t.Errorf("expected HTML void elements to have children in XML templates:\n%s", diff)
+	}
+	var link Element
+	for _, n := range ifExpr.Then {
+		if e, isElement := n.(Element); isElement {
+			link = e
+		}
+	}
+	if link.Name != "link" || len(link.Children) != 1 {
+		t.Errorf("expected elements in if blocks of XML templates to have children, got %#v", link)
+	}
+}
@@ -1,22 +1,49 @@
 package parser
 
 import (
+	"go/ast"
+	goparser "go/parser"
+
 	"github.com/a-h/parse"
 	"github.com/a-h/templ/parser/v2/goexpression"
 )
 
-type templElementExpressionParser struct{}
+type templElementExpressionParser struct {
+	mode nodeMode
+}
 
 func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
+	start := pi.Index()
 	// Check the prefix first.
 	if _, ok, err = parse.Rune('@').Parse(pi); err != nil || !ok {
 		return
 	}
 
 	var r TemplElementExpression
+	// @wrap Layout() {
+	// If wrap isn't followed by a call, it's the name of a variable, e.g.
+	// @wrap {.
+	if afterAt := pi.Index(); peekPrefix(pi, "wrap ") {
+		pi.Take(len("wrap "))
+		_, _, _ = parse.OptionalWhitespace.Parse(pi)
+		if r.Expression, err = parseGo("templ element", pi, goexpression.TemplExpression); err == nil && isCall(r.Expression.Value) {
+			r.Wrap = true
+		} else {
+			r.Expression, err = Expression{}, nil
+			pi.Seek(afterAt)
+		}
+	}
+
 	// Parse the Go expression.
-	if r.Expression, err = parseGo("templ element", pi, goexpression.TemplExpression); err != nil {
-		return r, false, err
+	if !r.Wrap {
+		if r.Expression, err = parseGo("templ element", pi, goexpression.TemplExpression); err != nil {
+			return r, false, err
+		}
+	}
+	// An @ that isn't followed by an expression is text, e.g. "@0".
+	if r.Expression.Value == "" {
+		pi.Seek(start)
+		return r, false, nil
 	}
 
 	// Once we've got a start expression, check to see if there's an open brace for children. {\n.
@@ -26,13 +53,17 @@ func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, e
 		return
 	}
 	if !hasOpenBrace {
+		if r.Wrap {
+			err = parse.Error("@wrap "+r.Expression.Value+": missing children (expected '{')", pi.Position())
+			return r, false, err
+		}
 		return r, true, nil
 	}
 
 	// Once we've had the start of an element's children, we must conclude the block.
 
 	// Node contents.
-	np := newTemplateNodeParser(closeBraceWithOptionalPadding, "templ element closing brace")
+	np := newTemplateNodeParser(closeBraceWithOptionalPadding, "templ element closing brace", p.mode)
 	var nodes Nodes
 	if nodes, ok, err = np.Parse(pi); err != nil || !ok {
 		err = parse.Error("@"+r.Expression.Value+": expected nodes, but none were found", pi.Position())
@@ -50,3 +81,13 @@ func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, ok bool, e
 }
 
 var templElementExpression templElementExpressionParser
+
+// isCall returns true if the Go expression is a function call, e.g. Layout().
+func isCall(expr string) bool {
+	e, err := goparser.ParseExpr(expr)
+	if err != nil {
+		return false
+	}
+	_, ok := e.(*ast.CallExpr)
+	return ok
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/a-h/parse"
@@ -105,7 +106,20 @@ func TestTemplElementExpressionParser(t *testing.T) {
 				},
 				Children: []Node{
 					Whitespace{Value: "\n\t"},
-					Text{Value: "some words",
+					Text{
+						Range: Range{
+							From: Position{
+								Index: 18,
+								Line:  1,
+								Col:   1,
+							},
+							To: Position{
+								Index: 28,
+								Line:  1,
+								Col:   11,
+							},
+						},
+						Value:         "some words",
 						TrailingSpace: SpaceVertical,
 					},
 				},
@@ -139,6 +153,7 @@ func TestTemplElementExpressionParser(t *testing.T) {
 							From: Position{Index: 20, Line: 1, Col: 4},
 							To:   Position{Index: 21, Line: 1, Col: 5},
 						},
+						SelfClosing: true,
 						Attributes: []Attribute{
 							ConstantAttribute{
 								Name:  "href",
@@ -394,7 +409,21 @@ func TestTemplElementExpressionParser(t *testing.T) {
 							To:   Position{Index: 42, Line: 1, Col: 6},
 						},
 						Children: []Node{
-							Text{Value: "hello"},
+							Text{
+								Range: Range{
+									From: Position{
+										Index: 43,
+										Line:  1,
+										Col:   7,
+									},
+									To: Position{
+										Index: 48,
+										Line:  1,
+										Col:   12,
+									},
+								},
+								Value: "hello",
+							},
 						},
 						TrailingSpace: SpaceVertical,
 					},
@@ -420,6 +449,72 @@ func TestTemplElementExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "templelement: wrap, block with text",
+			input: `@wrap Layout() {
+	some words
+}`,
+			expected: TemplElementExpression{
+				Wrap: true,
+				Expression: Expression{
+					Value: "Layout()",
+					Range: Range{
+						From: Position{6, 0, 6},
+						To:   Position{14, 0, 14},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\n\t"},
+					Text{
+						Range: Range{
+							From: Position{18, 1, 1},
+							To:   Position{28, 1, 11},
+						},
+						Value:         "some words",
+						TrailingSpace: SpaceVertical,
+					},
+				},
+			},
+		},
+		{
+			name: "templelement: variable named wrap, block with text",
+			input: `@wrap {
+	some words
+}`,
+			expected: TemplElementExpression{
+				Expression: Expression{
+					Value: "wrap",
+					Range: Range{
+						From: Position{1, 0, 1},
+						To:   Position{5, 0, 5},
+					},
+				},
+				Children: []Node{
+					Whitespace{Value: "\n\t"},
+					Text{
+						Range: Range{
+							From: Position{9, 1, 1},
+							To:   Position{19, 1, 11},
+						},
+						Value:         "some words",
+						TrailingSpace: SpaceVertical,
+					},
+				},
+			},
+		},
+		{
+			name:  "templelement: variable named wrap, followed by text",
+			input: `@wrap <b>`,
+			expected: TemplElementExpression{
+				Expression: Expression{
+					Value: "wrap",
+					Range: Range{
+						From: Position{1, 0, 1},
+						To:   Position{5, 0, 5},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -438,3 +533,15 @@ func TestTemplElementExpressionParser(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplElementExpressionParserWrapWithoutChildren(t *testing.T) {
+	input := parse.NewInput(`@wrap Layout()` + "\n")
+	_, _, err := templElementExpression.Parse(input)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	expected := "@wrap Layout(): missing children (expected '{')"
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error to contain %q, got %q", expected, err.Error())
+	}
+}
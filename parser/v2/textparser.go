@@ -19,6 +19,7 @@ var textParser = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
 	if isWhitespace(t.Value) {
 		return t, false, nil
 	}
+	t.Range = NewRange(from, pi.Position())
 	if _, ok = pi.Peek(1); !ok {
 		err = parse.Error("textParser: unterminated text, expected tag open, templ expression open, or newline", from)
 		return
@@ -17,6 +17,18 @@ func TestTextParser(t *testing.T) {
 			name:  "Text ends at an element start",
 			input: `abcdef<a href="https://example.com">More</a>`,
 			expected: Text{
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 6,
+						Line:  0,
+						Col:   6,
+					},
+				},
 				Value: "abcdef",
 			},
 		},
@@ -24,6 +36,18 @@ func TestTextParser(t *testing.T) {
 			name:  "Text ends at a templ expression start",
 			input: `abcdef{%= "test" %}`,
 			expected: Text{
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 6,
+						Line:  0,
+						Col:   6,
+					},
+				},
 				Value: "abcdef",
 			},
 		},
@@ -31,6 +55,18 @@ func TestTextParser(t *testing.T) {
 			name:  "Text may contain spaces",
 			input: `abcdef ghijk{%= "test" %}`,
 			expected: Text{
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 12,
+						Line:  0,
+						Col:   12,
+					},
+				},
 				Value: "abcdef ghijk",
 			},
 		},
@@ -38,6 +74,18 @@ func TestTextParser(t *testing.T) {
 			name:  "Text may contain named references",
 			input: `abcdef&nbsp;ghijk{%= "test" %}`,
 			expected: Text{
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  0,
+						Col:   17,
+					},
+				},
 				Value: "abcdef&nbsp;ghijk",
 			},
 		},
@@ -45,6 +93,18 @@ func TestTextParser(t *testing.T) {
 			name:  "Text may contain base 10 numeric references",
 			input: `abcdef&#32;ghijk{%= "test" %}`,
 			expected: Text{
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 16,
+						Line:  0,
+						Col:   16,
+					},
+				},
 				Value: "abcdef&#32;ghijk",
 			},
 		},
@@ -52,6 +112,18 @@ func TestTextParser(t *testing.T) {
 			name:  "Text may contain hexadecimal numeric references",
 			input: `abcdef&#x20;ghijk{%= "test" %}`,
 			expected: Text{
+				Range: Range{
+					From: Position{
+						Index: 0,
+						Line:  0,
+						Col:   0,
+					},
+					To: Position{
+						Index: 17,
+						Line:  0,
+						Col:   17,
+					},
+				},
 				Value: "abcdef&#x20;ghijk",
 			},
 		},
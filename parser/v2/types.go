@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"go/format"
+	"html"
 	"io"
 	"strings"
 	"unicode"
@@ -124,20 +125,54 @@ type TemplateFile struct {
 }
 
 func (tf TemplateFile) Write(w io.Writer) error {
+	return tf.WriteWithOptions(w, FormatOptions{})
+}
+
+// FormatOptions configure how elements are written by TemplateFile.WriteWithOptions.
+type FormatOptions struct {
+	// OmitVoidElementSlash writes void elements as <br> instead of <br/>.
+	OmitVoidElementSlash bool
+	// ExpandSelfClosingElements writes self-closing elements, e.g. <div/>, as
+	// <div></div>. Void elements, and elements within <svg> and <math>, are kept
+	// self-closing.
+	ExpandSelfClosingElements bool
+}
+
+// formatOptions are the options of the nodes being written.
+type formatOptions struct {
+	FormatOptions
+	// foreignContent is true while writing the elements within an <svg> or
+	// <math> element, which are self-closing if they're empty.
+	foreignContent bool
+}
+
+// WriteWithOptions writes the formatted template file to w, using the formatting options.
+func (tf TemplateFile) WriteWithOptions(w io.Writer, options FormatOptions) error {
+	opts := formatOptions{FormatOptions: options}
 	for _, n := range tf.Header {
 		if err := n.Write(w, 0); err != nil {
 			return err
 		}
 	}
 	var indent int
-	if err := tf.Package.Write(w, indent); err != nil {
-		return err
-	}
-	if _, err := io.WriteString(w, "\n\n"); err != nil {
-		return err
+	// Files without a package declaration are invalid, but are written without
+	// adding blank lines, so that formatting them again doesn't change them.
+	if tf.Package.Expression.Value != "" {
+		if err := tf.Package.Write(w, indent); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n\n"); err != nil {
+			return err
+		}
 	}
 	for i := 0; i < len(tf.Nodes); i++ {
-		if err := tf.Nodes[i].Write(w, indent); err != nil {
+		var err error
+		if t, isTemplate := tf.Nodes[i].(HTMLTemplate); isTemplate {
+			err = t.writeWithOptions(w, indent, opts)
+		} else {
+			err = tf.Nodes[i].Write(w, indent)
+		}
+		if err != nil {
 			return err
 		}
 		if _, err := io.WriteString(w, getNodeWhitespace(tf.Nodes, i)); err != nil {
@@ -147,6 +182,19 @@ func (tf TemplateFile) Write(w io.Writer) error {
 	return nil
 }
 
+// optionsWriter is implemented by the nodes that contain elements, so that the
+// elements are written using the formatting options.
+type optionsWriter interface {
+	writeWithOptions(w io.Writer, indent int, opts formatOptions) error
+}
+
+func writeNodeWithOptions(w io.Writer, indent int, n Node, opts formatOptions) error {
+	if ow, ok := n.(optionsWriter); ok {
+		return ow.writeWithOptions(w, indent, opts)
+	}
+	return n.Write(w, indent)
+}
+
 func getNodeWhitespace(nodes []TemplateFileNode, i int) string {
 	if i == len(nodes)-1 {
 		return "\n"
@@ -178,13 +226,25 @@ type TemplateFileGoExpression struct {
 func (exp TemplateFileGoExpression) IsTemplateFileNode() bool { return true }
 func (exp TemplateFileGoExpression) Write(w io.Writer, indent int) error {
 	data, err := format.Source([]byte(exp.Expression.Value))
-	if err != nil {
+	if err != nil || startsTemplate(string(data)) {
+		// Don't format code into a line that would be parsed as a template.
 		return writeIndent(w, indent, exp.Expression.Value)
 	}
 	_, err = w.Write(data)
 	return err
 }
 
+// startsTemplate returns true if any line of the Go code starts like a templ, css
+// or script template declaration.
+func startsTemplate(code string) bool {
+	for _, l := range strings.Split(code, "\n") {
+		if strings.HasPrefix(l, "templ ") || strings.HasPrefix(l, "css ") || strings.HasPrefix(l, "script ") {
+			return true
+		}
+	}
+	return false
+}
+
 func writeLinesIndented(w io.Writer, level int, s string) (err error) {
 	indent := strings.Repeat("\t", level)
 	lines := strings.Split(s, "\n")
@@ -253,6 +313,9 @@ func (ws Whitespace) Write(w io.Writer, indent int) error {
 //	  color: #ffffff;
 //	  background-color: { constants.BackgroundColor };
 //	  background-image: url('./somewhere.png');
+//	  &:hover {
+//	    color: #ff0000;
+//	  }
 //	}
 type CSSTemplate struct {
 	Name       string
@@ -300,7 +363,7 @@ func (c ConstantCSSProperty) Write(w io.Writer, indent int) error {
 func (c ConstantCSSProperty) String(minified bool) string {
 	sb := new(strings.Builder)
 	sb.WriteString(c.Name)
-	if minified {
+	if minified || strings.HasPrefix(c.Value, "\n") {
 		sb.WriteString(":")
 	} else {
 		sb.WriteString(": ")
@@ -333,6 +396,35 @@ func (c ExpressionCSSProperty) Write(w io.Writer, indent int) error {
 	return nil
 }
 
+// CSSRule is a rule nested in a CSS component. The & in the selector refers to
+// the class of the component. Selectors without an & match descendants of the
+// component.
+//
+//	&:hover {
+//	  color: #ff0000;
+//	}
+//	@media (min-width: 600px) {
+//	  font-size: 2em;
+//	}
+type CSSRule struct {
+	// Selector of the rule, or an at-rule, e.g. "&:hover", "> li", or "@media (min-width: 600px)".
+	Selector   string
+	Properties []CSSProperty
+}
+
+func (c CSSRule) IsCSSProperty() bool { return true }
+func (c CSSRule) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, c.Selector, " {\n"); err != nil {
+		return err
+	}
+	for _, p := range c.Properties {
+		if err := p.Write(w, indent+1); err != nil {
+			return err
+		}
+	}
+	return writeIndent(w, indent, "}\n")
+}
+
 // <!DOCTYPE html>
 type DocType struct {
 	Value string
@@ -343,6 +435,16 @@ func (dt DocType) Write(w io.Writer, indent int) error {
 	return writeIndent(w, indent, "<!DOCTYPE ", dt.Value, ">")
 }
 
+// XMLDeclaration, e.g. <?xml version="1.0" encoding="UTF-8"?>
+type XMLDeclaration struct {
+	Value string
+}
+
+func (xd XMLDeclaration) IsNode() bool { return true }
+func (xd XMLDeclaration) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "<?xml ", strings.TrimSpace(xd.Value), "?>")
+}
+
 // HTMLTemplate definition.
 //
 //	templ Name(p Parameter) {
@@ -351,18 +453,73 @@ func (dt DocType) Write(w io.Writer, indent int) error {
 //	  }
 //	}
 type HTMLTemplate struct {
-	Expression Expression
-	Children   []Node
+	Expression  Expression
+	ContentType ContentType
+	// Extends is the template that the template extends, e.g. Base("Home"), or
+	// empty. The children of a template that extends another template are the
+	// blocks of the extended template that it overrides.
+	Extends Expression
+	// Style is the optional scoped style block at the start of the template.
+	Style    *ScopedStyle
+	Children []Node
+}
+
+// ScopedStyle is a block of CSS at the start of a template. Its selectors are
+// scoped to the template, by adding a class to the root elements of the template.
+//
+//	style {
+//		.title { color: red; }
+//	}
+type ScopedStyle struct {
+	// Contents of the block, excluding the braces.
+	Contents string
 }
 
+func (s ScopedStyle) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "style {", s.Contents, "}\n")
+}
+
+// ContentType of the output of a template.
+type ContentType string
+
+const (
+	// ContentTypeHTML is the default content type of templates.
+	ContentTypeHTML ContentType = ""
+	// ContentTypeXML templates output XML, e.g. sitemaps and RSS feeds.
+	//
+	//	templ Sitemap(urls []string) xml {
+	ContentTypeXML ContentType = "xml"
+	// ContentTypeText templates output plain text, e.g. robots.txt.
+	//
+	//	templ Robots() text {
+	ContentTypeText ContentType = "text"
+)
+
 func (t HTMLTemplate) IsTemplateFileNode() bool { return true }
 
 func (t HTMLTemplate) Write(w io.Writer, indent int) error {
+	return t.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (t HTMLTemplate) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
 	source := formatFunctionArguments(t.Expression.Value)
-	if err := writeIndent(w, indent, "templ ", string(source), " {\n"); err != nil {
+	contentType := ""
+	if t.ContentType != ContentTypeHTML {
+		contentType = " " + string(t.ContentType)
+	}
+	extends := ""
+	if t.Extends.Value != "" {
+		extends = " extends " + t.Extends.Value
+	}
+	if err := writeIndent(w, indent, "templ ", string(source), contentType, extends, " {\n"); err != nil {
 		return err
 	}
-	if err := writeNodesIndented(w, indent+1, t.Children); err != nil {
+	if t.Style != nil {
+		if err := t.Style.Write(w, indent+1); err != nil {
+			return err
+		}
+	}
+	if err := writeNodesIndented(w, indent+1, t.Children, opts); err != nil {
 		return err
 	}
 	if err := writeIndent(w, indent, "}"); err != nil {
@@ -425,10 +582,29 @@ var (
 	_ WhitespaceTrailer = Element{}
 	_ WhitespaceTrailer = Text{}
 	_ WhitespaceTrailer = StringExpression{}
+	_ WhitespaceTrailer = TrimWhitespace{}
 )
 
+// TrimWhitespace removes the whitespace on either side of it.
+// <span>A</span> {-} <span>B</span>
+type TrimWhitespace struct {
+	// TrailingSpace is kept when the template is formatted, but isn't rendered.
+	TrailingSpace TrailingSpace
+}
+
+func (tw TrimWhitespace) Trailing() TrailingSpace {
+	return tw.TrailingSpace
+}
+
+func (tw TrimWhitespace) IsNode() bool { return true }
+func (tw TrimWhitespace) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "{-}")
+}
+
 // Text node within the document.
 type Text struct {
+	// Range of the text within the templ file.
+	Range Range
 	// Value is the raw HTML encoded value.
 	Value string
 	// TrailingSpace lists what happens after the text.
@@ -453,6 +629,9 @@ type Element struct {
 	IndentChildren bool
 	TrailingSpace  TrailingSpace
 	NameRange      Range
+	// SelfClosing is true if the element was written as a self-closing tag,
+	// e.g. <div/>.
+	SelfClosing bool
 }
 
 func (e Element) Trailing() TrailingSpace {
@@ -463,12 +642,80 @@ var voidElements = map[string]struct{}{
 	"area": {}, "base": {}, "br": {}, "col": {}, "command": {}, "embed": {}, "hr": {}, "img": {}, "input": {}, "keygen": {}, "link": {}, "meta": {}, "param": {}, "source": {}, "track": {}, "wbr": {},
 }
 
+// preformattedElements preserve the whitespace in their contents.
+var preformattedElements = map[string]struct{}{
+	"pre": {}, "textarea": {},
+}
+
+// IsPreformattedElement returns true if whitespace within the element is significant.
+// Elements within a preformatted element, such as <code> within <pre>, are also preformatted.
+func (e Element) IsPreformattedElement() bool {
+	_, ok := preformattedElements[e.Name]
+	return ok
+}
+
 // https://www.w3.org/TR/2011/WD-html-markup-20110113/syntax.html#void-element
 func (e Element) IsVoidElement() bool {
 	_, ok := voidElements[e.Name]
 	return ok
 }
 
+// foreignElements are SVG and MathML elements that are not also HTML elements.
+var foreignElements = map[string]struct{}{
+	"svg": {}, "animate": {}, "animateMotion": {}, "animateTransform": {}, "circle": {}, "clipPath": {}, "defs": {}, "desc": {}, "ellipse": {},
+	"feBlend": {}, "feColorMatrix": {}, "feComponentTransfer": {}, "feComposite": {}, "feConvolveMatrix": {}, "feDiffuseLighting": {},
+	"feDisplacementMap": {}, "feDistantLight": {}, "feDropShadow": {}, "feFlood": {}, "feFuncA": {}, "feFuncB": {}, "feFuncG": {}, "feFuncR": {},
+	"feGaussianBlur": {}, "feImage": {}, "feMerge": {}, "feMergeNode": {}, "feMorphology": {}, "feOffset": {}, "fePointLight": {},
+	"feSpecularLighting": {}, "feSpotLight": {}, "feTile": {}, "feTurbulence": {}, "filter": {}, "foreignObject": {}, "g": {}, "image": {},
+	"line": {}, "linearGradient": {}, "marker": {}, "mask": {}, "metadata": {}, "mpath": {}, "path": {}, "pattern": {}, "polygon": {},
+	"polyline": {}, "radialGradient": {}, "rect": {}, "set": {}, "stop": {}, "switch": {}, "symbol": {}, "text": {}, "textPath": {},
+	"tspan": {}, "use": {}, "view": {},
+	// MathML.
+	"math": {}, "maction": {}, "annotation": {}, "annotation-xml": {}, "menclose": {}, "merror": {}, "mfrac": {}, "mi": {}, "mmultiscripts": {},
+	"mn": {}, "mo": {}, "mover": {}, "mpadded": {}, "mphantom": {}, "mprescripts": {}, "mroot": {}, "mrow": {}, "ms": {}, "mspace": {},
+	"msqrt": {}, "mstyle": {}, "msub": {}, "msubsup": {}, "msup": {}, "mtable": {}, "mtd": {}, "mtext": {}, "mtr": {}, "munder": {},
+	"munderover": {}, "none": {}, "semantics": {},
+}
+
+// IsForeignElement returns true if the element name is the name of an SVG or MathML
+// element. Whether an element is foreign depends on its ancestors, see
+// StartsForeignContent.
+// https://html.spec.whatwg.org/multipage/syntax.html#foreign-elements
+func (e Element) IsForeignElement() bool {
+	_, ok := foreignElements[e.Name]
+	return ok
+}
+
+// IsHTMLElement returns true if the element name is the name of a standard HTML
+// element, i.e. not a custom element, or an SVG or MathML element.
+func (e Element) IsHTMLElement() bool {
+	_, ok := htmlElements[e.Name]
+	return ok
+}
+
+// StartsForeignContent returns true if the element is an <svg> or <math>
+// element. The element, and the elements within it, are foreign elements,
+// which follow XML rules, e.g. any element can be self-closing.
+func (e Element) StartsForeignContent() bool {
+	return e.Name == "svg" || e.Name == "math"
+}
+
+// htmlIntegrationPoints are the foreign elements that contain HTML elements,
+// instead of foreign elements.
+// https://html.spec.whatwg.org/multipage/parsing.html#html-integration-point
+var htmlIntegrationPoints = map[string]struct{}{
+	"foreignObject": {}, "desc": {}, "title": {},
+	// MathML text integration points.
+	"mi": {}, "mo": {}, "mn": {}, "ms": {}, "mtext": {},
+}
+
+// IsHTMLIntegrationPoint returns true if the children of the element are HTML
+// elements, even if the element is a foreign element, e.g. <foreignObject>.
+func (e Element) IsHTMLIntegrationPoint() bool {
+	_, ok := htmlIntegrationPoints[e.Name]
+	return ok
+}
+
 func (e Element) hasNonWhitespaceChildren() bool {
 	for _, c := range e.Children {
 		if _, isWhitespace := c.(Whitespace); !isWhitespace {
@@ -523,11 +770,44 @@ func containsNonTextNodes(nodes []Node) bool {
 	return false
 }
 
+// containsLineBreaks returns true if any of the nodes are always followed by a
+// line break, e.g. @icon() or an HTML comment, so that they can't be written on
+// the same line as the tags of the parent element.
+func containsLineBreaks(nodes []Node) bool {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case Whitespace:
+			continue
+		case Element:
+			if containsLineBreaks(n.Children) {
+				return true
+			}
+			continue
+		case WhitespaceTrailer:
+			continue
+		}
+		return true
+	}
+	return false
+}
+
 func (e Element) ChildNodes() []Node {
 	return e.Children
 }
 func (e Element) IsNode() bool { return true }
 func (e Element) Write(w io.Writer, indent int) error {
+	return e.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (e Element) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
+	return e.write(w, indent, opts, e.IsPreformattedElement())
+}
+
+// write the element. If preformatted is true, the children are written as-is.
+func (e Element) write(w io.Writer, indent int, opts formatOptions, preformatted bool) error {
+	isForeign := opts.foreignContent || e.StartsForeignContent()
+	childOpts := opts
+	childOpts.foreignContent = isForeign && !e.IsHTMLIntegrationPoint()
 	if err := writeIndent(w, indent, "<", e.Name); err != nil {
 		return err
 	}
@@ -556,12 +836,22 @@ func (e Element) Write(w io.Writer, indent int) error {
 		}
 		closeAngleBracketIndent = indent
 	}
+	if preformatted && e.hasNonWhitespaceChildren() {
+		if err := writeIndent(w, closeAngleBracketIndent, ">"); err != nil {
+			return err
+		}
+		if err := writePreformattedNodes(w, indent, e.Children, childOpts); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "</"+e.Name+">")
+		return err
+	}
 	if e.hasNonWhitespaceChildren() {
-		if e.IndentChildren {
+		if e.IndentChildren || containsLineBreaks(e.Children) {
 			if err := writeIndent(w, closeAngleBracketIndent, ">\n"); err != nil {
 				return err
 			}
-			if err := writeNodesIndented(w, indent+1, e.Children); err != nil {
+			if err := writeNodesIndented(w, indent+1, e.Children, childOpts); err != nil {
 				return err
 			}
 			if err := writeIndent(w, indent, "</", e.Name, ">"); err != nil {
@@ -572,7 +862,7 @@ func (e Element) Write(w io.Writer, indent int) error {
 		if err := writeIndent(w, closeAngleBracketIndent, ">"); err != nil {
 			return err
 		}
-		if err := writeNodesWithoutIndentation(w, e.Children); err != nil {
+		if err := writeNodesWithoutIndentation(w, e.Children, childOpts); err != nil {
 			return err
 		}
 		if _, err := w.Write([]byte("</" + e.Name + ">")); err != nil {
@@ -580,7 +870,13 @@ func (e Element) Write(w io.Writer, indent int) error {
 		}
 		return nil
 	}
-	if e.IsVoidElement() {
+	if e.IsVoidElement() && opts.OmitVoidElementSlash {
+		if err := writeIndent(w, closeAngleBracketIndent, ">"); err != nil {
+			return err
+		}
+		return nil
+	}
+	if e.IsVoidElement() || isForeign || e.SelfClosing && !opts.ExpandSelfClosingElements {
 		if err := writeIndent(w, closeAngleBracketIndent, "/>"); err != nil {
 			return err
 		}
@@ -592,15 +888,52 @@ func (e Element) Write(w io.Writer, indent int) error {
 	return nil
 }
 
-func writeNodesWithoutIndentation(w io.Writer, nodes []Node) error {
-	return writeNodes(w, 0, nodes, false)
+// writePreformattedNodes writes the children of a preformatted element as-is.
+// Statements on lines of their own, e.g. if, are indented, and end the line.
+func writePreformattedNodes(w io.Writer, indent int, nodes []Node, opts formatOptions) (err error) {
+	var atStartOfLine bool
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case Text:
+			_, err = io.WriteString(w, n.Value)
+			atStartOfLine = strings.HasSuffix(n.Value, "\n")
+			if err != nil {
+				return err
+			}
+			continue
+		case Element:
+			err = n.write(w, 0, opts, true)
+		case IfExpression, ForExpression, SwitchExpression, BlockExpression:
+			if atStartOfLine {
+				if err = writeNodeWithOptions(w, indent+1, n, opts); err != nil {
+					return err
+				}
+				if _, err = io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+				continue
+			}
+			err = writeNodeWithOptions(w, 0, n, opts)
+		default:
+			err = writeNodeWithOptions(w, 0, n, opts)
+		}
+		if err != nil {
+			return err
+		}
+		atStartOfLine = false
+	}
+	return nil
+}
+
+func writeNodesWithoutIndentation(w io.Writer, nodes []Node, opts formatOptions) error {
+	return writeNodes(w, 0, nodes, false, opts)
 }
 
-func writeNodesIndented(w io.Writer, level int, nodes []Node) error {
-	return writeNodes(w, level, nodes, true)
+func writeNodesIndented(w io.Writer, level int, nodes []Node, opts formatOptions) error {
+	return writeNodes(w, level, nodes, true, opts)
 }
 
-func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
+func writeNodes(w io.Writer, level int, nodes []Node, indent bool, opts formatOptions) error {
 	startLevel := level
 	for i := 0; i < len(nodes); i++ {
 		_, isWhitespace := nodes[i].(Whitespace)
@@ -609,7 +942,7 @@ func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
 		if isWhitespace {
 			continue
 		}
-		if err := nodes[i].Write(w, level); err != nil {
+		if err := writeNodeWithOptions(w, level, nodes[i], opts); err != nil {
 			return err
 		}
 
@@ -638,8 +971,11 @@ func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
 }
 
 func shouldAlwaysBreakAfter(node Node) bool {
-	if el, isElement := node.(Element); isElement {
-		return strings.EqualFold(el.Name, "br") || strings.EqualFold(el.Name, "hr")
+	switch n := node.(type) {
+	case Element:
+		return strings.EqualFold(n.Name, "br") || strings.EqualFold(n.Name, "hr")
+	case BranchStatement:
+		return true
 	}
 	return false
 }
@@ -659,8 +995,12 @@ func isBlockNode(node Node) bool {
 		return true
 	case ForExpression:
 		return true
+	case BlockExpression:
+		return true
+	case BranchStatement:
+		return true
 	case Element:
-		return n.IsBlockElement() || n.IndentChildren
+		return n.IsBlockElement() || n.IndentChildren || containsLineBreaks(n.Children)
 	}
 	return false
 }
@@ -733,7 +1073,31 @@ func (ca ConstantAttribute) String() string {
 	if ca.SingleQuote {
 		quote = `'`
 	}
-	return ca.Name + `=` + quote + ca.Value + quote
+	return ca.Name + `=` + quote + escapeAttributeValue(ca.Value, quote) + quote
+}
+
+// escapeAttributeValue escapes the quote, and the ampersands that would otherwise
+// be parsed as the start of a character reference, e.g. the & of "&amp;amp;".
+// Other characters are left as they are, so that values like "a && b" aren't
+// changed by formatting.
+func escapeAttributeValue(value, quote string) string {
+	if !strings.ContainsAny(value, "&"+quote) {
+		return value
+	}
+	var sb strings.Builder
+	for i, r := range value {
+		switch {
+		case string(r) == quote && quote == `"`:
+			sb.WriteString("&quot;")
+		case string(r) == quote:
+			sb.WriteString("&#39;")
+		case r == '&' && html.UnescapeString(value[i:]) != "&"+html.UnescapeString(value[i+1:]):
+			sb.WriteString("&amp;")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
 }
 
 func (ca ConstantAttribute) Write(w io.Writer, indent int) error {
@@ -814,6 +1178,20 @@ func (ea ExpressionAttribute) Write(w io.Writer, indent int) (err error) {
 	return writeIndent(w, indent, "}")
 }
 
+// { "data-" + key }={ value }
+type KeyExpressionAttribute struct {
+	Key        Expression
+	Expression Expression
+}
+
+func (kea KeyExpressionAttribute) String() string {
+	return `{ ` + kea.Key.Value + ` }={ ` + kea.Expression.Value + ` }`
+}
+
+func (kea KeyExpressionAttribute) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, kea.String())
+}
+
 // <a { spread... } />
 type SpreadAttributes struct {
 	Expression Expression
@@ -938,7 +1316,12 @@ func (cte CallTemplateExpression) Write(w io.Writer, indent int) error {
 // @Other(p.First, p.Last)
 // or it can be used to render a template parameter.
 // @v
+// With wrap, the rest of the template, e.g. its end tags, is rendered even if
+// the children fail.
+// @wrap Layout() { <p>Children</p> }
 type TemplElementExpression struct {
+	// Wrap is true if the template is rendered with templ.Wrap.
+	Wrap bool
 	// Expression returns a template to execute.
 	Expression Expression
 	// Children returns the elements in a block element.
@@ -950,11 +1333,22 @@ func (tee TemplElementExpression) ChildNodes() []Node {
 }
 func (tee TemplElementExpression) IsNode() bool { return true }
 func (tee TemplElementExpression) Write(w io.Writer, indent int) error {
+	return tee.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (tee TemplElementExpression) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
+	prefix := "@"
+	if tee.Wrap {
+		prefix = "@wrap "
+	}
 	source, err := format.Source([]byte(tee.Expression.Value))
 	if err != nil {
-		source = []byte(tee.Expression.Value)
+		// Write invalid expressions as they are, without indenting the lines again.
+		err = writeIndent(w, indent, prefix+tee.Expression.Value)
+	} else {
+		err = writeLinesIndented(w, indent, prefix+string(source))
 	}
-	if err := writeLinesIndented(w, indent, "@"+string(source)); err != nil {
+	if err != nil {
 		return err
 	}
 	if len(tee.Children) == 0 {
@@ -963,7 +1357,7 @@ func (tee TemplElementExpression) Write(w io.Writer, indent int) error {
 	if _, err = io.WriteString(w, " {\n"); err != nil {
 		return err
 	}
-	if err := writeNodesIndented(w, indent+1, tee.Children); err != nil {
+	if err := writeNodesIndented(w, indent+1, tee.Children, opts); err != nil {
 		return err
 	}
 	if err := writeIndent(w, indent, "}"); err != nil {
@@ -1009,11 +1403,15 @@ func (n IfExpression) ChildNodes() []Node {
 }
 func (n IfExpression) IsNode() bool { return true }
 func (n IfExpression) Write(w io.Writer, indent int) error {
+	return n.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (n IfExpression) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
 	if err := writeIndent(w, indent, "if ", n.Expression.Value, " {\n"); err != nil {
 		return err
 	}
 	indent++
-	if err := writeNodesIndented(w, indent, n.Then); err != nil {
+	if err := writeNodesIndented(w, indent, n.Then, opts); err != nil {
 		return err
 	}
 	indent--
@@ -1022,7 +1420,7 @@ func (n IfExpression) Write(w io.Writer, indent int) error {
 			return err
 		}
 		indent++
-		if err := writeNodesIndented(w, indent, elseIf.Then); err != nil {
+		if err := writeNodesIndented(w, indent, elseIf.Then, opts); err != nil {
 			return err
 		}
 		indent--
@@ -1031,7 +1429,7 @@ func (n IfExpression) Write(w io.Writer, indent int) error {
 		if err := writeIndent(w, indent, "} else {\n"); err != nil {
 			return err
 		}
-		if err := writeNodesIndented(w, indent+1, n.Else); err != nil {
+		if err := writeNodesIndented(w, indent+1, n.Else, opts); err != nil {
 			return err
 		}
 	}
@@ -1058,6 +1456,10 @@ func (se SwitchExpression) ChildNodes() []Node {
 }
 func (se SwitchExpression) IsNode() bool { return true }
 func (se SwitchExpression) Write(w io.Writer, indent int) error {
+	return se.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (se SwitchExpression) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
 	if err := writeIndent(w, indent, "switch ", se.Expression.Value, " {\n"); err != nil {
 		return err
 	}
@@ -1067,7 +1469,7 @@ func (se SwitchExpression) Write(w io.Writer, indent int) error {
 		if err := writeIndent(w, indent, c.Expression.Value, "\n"); err != nil {
 			return err
 		}
-		if err := writeNodesIndented(w, indent+1, c.Children); err != nil {
+		if err := writeNodesIndented(w, indent+1, c.Children, opts); err != nil {
 			return err
 		}
 	}
@@ -1087,7 +1489,14 @@ type CaseExpression struct {
 //	for i, v := range p.Addresses {
 //	  {! Address(v) }
 //	}
+//
+// A for loop can have a label, which break and continue statements can use.
+//
+//	outer: for _, row := range rows {
+//	}
 type ForExpression struct {
+	// Label of the loop, if any.
+	Label      string
 	Expression Expression
 	Children   []Node
 }
@@ -1097,10 +1506,79 @@ func (fe ForExpression) ChildNodes() []Node {
 }
 func (fe ForExpression) IsNode() bool { return true }
 func (fe ForExpression) Write(w io.Writer, indent int) error {
-	if err := writeIndent(w, indent, "for ", fe.Expression.Value, " {\n"); err != nil {
+	return fe.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (fe ForExpression) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
+	var label string
+	if fe.Label != "" {
+		label = fe.Label + ": "
+	}
+	if err := writeIndent(w, indent, label, "for ", fe.Expression.Value, " {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, fe.Children, opts); err != nil {
+		return err
+	}
+	if err := writeIndent(w, indent, "}"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// BranchStatement stops a for loop, continues with the next iteration of a for
+// loop, or stops rendering the template. It must be on its own line.
+//
+//	break
+//	continue outer
+//	return
+type BranchStatement struct {
+	// Keyword is break, continue or return.
+	Keyword string
+	// Label of the for loop that break or continue applies to, if any.
+	Label string
+	Range Range
+}
+
+func (bs BranchStatement) IsNode() bool { return true }
+func (bs BranchStatement) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, bs.String())
+}
+
+// String returns the Go code of the statement, e.g. "continue outer".
+func (bs BranchStatement) String() string {
+	if bs.Label == "" {
+		return bs.Keyword
+	}
+	return bs.Keyword + " " + bs.Label
+}
+
+// BlockExpression is a named region of a template that can be overridden by a
+// template that extends the template. The children are rendered if the block
+// isn't overridden.
+//
+//	block content {
+//		<p>No content.</p>
+//	}
+type BlockExpression struct {
+	Name      string
+	NameRange Range
+	Children  []Node
+}
+
+func (be BlockExpression) ChildNodes() []Node {
+	return be.Children
+}
+func (be BlockExpression) IsNode() bool { return true }
+func (be BlockExpression) Write(w io.Writer, indent int) error {
+	return be.writeWithOptions(w, indent, formatOptions{})
+}
+
+func (be BlockExpression) writeWithOptions(w io.Writer, indent int, opts formatOptions) error {
+	if err := writeIndent(w, indent, "block ", be.Name, " {\n"); err != nil {
 		return err
 	}
-	if err := writeNodesIndented(w, indent+1, fe.Children); err != nil {
+	if err := writeNodesIndented(w, indent+1, be.Children, opts); err != nil {
 		return err
 	}
 	if err := writeIndent(w, indent, "}"); err != nil {
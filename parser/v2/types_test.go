@@ -854,3 +854,75 @@ func displayWhitespaceChars(s string) (output string) {
 	s = strings.ReplaceAll(s, "\n", "↵\n")
 	return s
 }
+
+func TestFormattingWithOptions(t *testing.T) {
+	input := `package test
+
+templ form() {
+	<form>
+		<input type="text"/>
+		<br>
+		<div></div>
+		<span/>
+		<svg><circle></circle></svg>
+	</form>
+}
+`
+	tests := []struct {
+		name     string
+		opts     FormatOptions
+		expected string
+	}{
+		{
+			name: "default options",
+			opts: FormatOptions{},
+			expected: `package test
+
+templ form() {
+	<form>
+		<input type="text"/>
+		<br/>
+		<div></div>
+		<span/>
+		<svg><circle/></svg>
+	</form>
+}
+`,
+		},
+		{
+			name: "void elements without slash, and expanded self-closing elements",
+			opts: FormatOptions{
+				OmitVoidElementSlash:      true,
+				ExpandSelfClosingElements: true,
+			},
+			expected: `package test
+
+templ form() {
+	<form>
+		<input type="text">
+		<br>
+		<div></div>
+		<span></span>
+		<svg><circle/></svg>
+	</form>
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseString(input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			w := new(strings.Builder)
+			if err = template.WriteWithOptions(w, tt.opts); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+// A Visitor's Visit method is called for each node found by Walk. If the
+// visitor w returned by Visit is not nil, Walk visits each of the children of
+// the node with w, followed by a call to w.Visit(nil).
+type Visitor interface {
+	Visit(node any) (w Visitor)
+}
+
+// Walk traverses the template file, or a node of it, in depth-first order. It
+// starts by calling v.Visit(node), which must be one of:
+//
+//   - TemplateFile, Package, or a TemplateFileNode, e.g. HTMLTemplate or CSSTemplate.
+//   - Node, e.g. Element, IfExpression or StringExpression.
+//   - Attribute, e.g. ConstantAttribute or ConditionalAttribute.
+//   - CSSProperty, e.g. ConstantCSSProperty or CSSRule.
+//   - ElseIfExpression or CaseExpression.
+//
+// The attributes of an element are visited before its children.
+func Walk(v Visitor, node any) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	switch n := node.(type) {
+	case TemplateFile:
+		for _, h := range n.Header {
+			Walk(v, h)
+		}
+		Walk(v, n.Package)
+		for _, child := range n.Nodes {
+			Walk(v, child)
+		}
+	case HTMLTemplate:
+		walkNodeList(v, n.Children)
+	case CSSTemplate:
+		for _, p := range n.Properties {
+			Walk(v, p)
+		}
+	case CSSRule:
+		for _, p := range n.Properties {
+			Walk(v, p)
+		}
+	case Element:
+		walkAttributes(v, n.Attributes)
+		walkNodeList(v, n.Children)
+	case RawElement:
+		walkAttributes(v, n.Attributes)
+	case ConditionalAttribute:
+		walkAttributes(v, n.Then)
+		walkAttributes(v, n.Else)
+	case TemplElementExpression:
+		walkNodeList(v, n.Children)
+	case IfExpression:
+		walkNodeList(v, n.Then)
+		for _, elseIf := range n.ElseIfs {
+			Walk(v, elseIf)
+		}
+		walkNodeList(v, n.Else)
+	case ElseIfExpression:
+		walkNodeList(v, n.Then)
+	case SwitchExpression:
+		for _, c := range n.Cases {
+			Walk(v, c)
+		}
+	case CaseExpression:
+		walkNodeList(v, n.Children)
+	case ForExpression:
+		walkNodeList(v, n.Children)
+	case BlockExpression:
+		walkNodeList(v, n.Children)
+	case Package, TemplateFileGoExpression, ScriptTemplate, ConstantCSSProperty, ExpressionCSSProperty,
+		Node, Attribute:
+		// Nodes without children.
+	default:
+		panic(fmt.Sprintf("parser.Walk: unexpected node type %T", n))
+	}
+	v.Visit(nil)
+}
+
+func walkNodeList(v Visitor, nodes []Node) {
+	for _, n := range nodes {
+		Walk(v, n)
+	}
+}
+
+func walkAttributes(v Visitor, attrs []Attribute) {
+	for _, a := range attrs {
+		Walk(v, a)
+	}
+}
+
+type inspector func(node any) bool
+
+func (f inspector) Visit(node any) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the template file, or a node of it, in depth-first order,
+// like Walk. It starts by calling f(node). If f returns true, Inspect calls f
+// for each of the children of the node, followed by a call of f(nil).
+//
+// For example, to find the names of the templates that are called:
+//
+//	parser.Inspect(tf, func(n any) bool {
+//		if n, ok := n.(parser.TemplElementExpression); ok {
+//			names = append(names, n.Expression.Value)
+//		}
+//		return true
+//	})
+func Inspect(node any, f func(node any) bool) {
+	Walk(inspector(f), node)
+}
+
+// NodeRange returns the position of the node in the source of the template
+// file: the range of the name of an element, block or attribute, the range of
+// text or a break, continue or return statement, or the range of the Go
+// expression of a node, e.g. the condition of an IfExpression. It returns false if the node has no position, e.g. Whitespace.
+func NodeRange(node any) (r Range, ok bool) {
+	switch n := node.(type) {
+	case Element:
+		return n.NameRange, true
+	case BlockExpression:
+		return n.NameRange, true
+	case BoolConstantAttribute:
+		return n.NameRange, true
+	case ConstantAttribute:
+		return n.NameRange, true
+	case BoolExpressionAttribute:
+		return n.NameRange, true
+	case ExpressionAttribute:
+		return n.NameRange, true
+	case TemplateFileGoExpression:
+		return n.Expression.Range, true
+	case Package:
+		return n.Expression.Range, true
+	case HTMLTemplate:
+		return n.Expression.Range, true
+	case CSSTemplate:
+		return n.Expression.Range, true
+	case ScriptTemplate:
+		return n.Name.Range, true
+	case ExpressionCSSProperty:
+		return n.Value.Expression.Range, true
+	case KeyExpressionAttribute:
+		return n.Key.Range, true
+	case SpreadAttributes:
+		return n.Expression.Range, true
+	case ConditionalAttribute:
+		return n.Expression.Range, true
+	case CallTemplateExpression:
+		return n.Expression.Range, true
+	case TemplElementExpression:
+		return n.Expression.Range, true
+	case IfExpression:
+		return n.Expression.Range, true
+	case ElseIfExpression:
+		return n.Expression.Range, true
+	case SwitchExpression:
+		return n.Expression.Range, true
+	case CaseExpression:
+		return n.Expression.Range, true
+	case ForExpression:
+		return n.Expression.Range, true
+	case StringExpression:
+		return n.Expression.Range, true
+	case Text:
+		return n.Range, true
+	case BranchStatement:
+		return n.Range, true
+	}
+	return r, false
+}
+
+// NodeSpan returns the start and end indexes of the node in the source of the
+// template file, e.g. from the < of an element to the end of its end tag, or
+// from the if of an IfExpression to its closing brace. The parser only records
+// where names and expressions are, so the node is parsed again to find its end.
+// It returns false for nodes without a position, e.g. Whitespace.
+func NodeSpan(src string, node Node) (from, to int, ok bool) {
+	var p parse.Parser[Node]
+	switch n := node.(type) {
+	case Text:
+		return int(n.Range.From.Index), int(n.Range.To.Index), true
+	case Element:
+		from, p = int(n.NameRange.From.Index)-1, element
+	case BlockExpression:
+		from, p = prefixStart(src, n.NameRange.From.Index, "block"), blockExpression
+	case IfExpression:
+		from, p = prefixStart(src, n.Expression.Range.From.Index, "if"), ifExpression
+	case ForExpression:
+		prefix := "for"
+		if n.Label != "" {
+			prefix = n.Label + ": for"
+		}
+		from, p = prefixStart(src, n.Expression.Range.From.Index, prefix), forExpression
+	case BranchStatement:
+		return int(n.Range.From.Index), int(n.Range.To.Index), true
+	case SwitchExpression:
+		from, p = prefixStart(src, n.Expression.Range.From.Index, "switch"), switchExpression
+	case TemplElementExpression:
+		prefix := "@"
+		if n.Wrap {
+			prefix = "@wrap"
+		}
+		from, p = prefixStart(src, n.Expression.Range.From.Index, prefix), templElementExpression
+	case CallTemplateExpression:
+		from, p = prefixStart(src, n.Expression.Range.From.Index, "{!"), callTemplateExpression
+	case StringExpression:
+		from, p = prefixStart(src, n.Expression.Range.From.Index, "{"), stringExpression
+	default:
+		return 0, 0, false
+	}
+	if from < 0 || from >= len(src) {
+		return 0, 0, false
+	}
+	pi := parse.NewInput(src)
+	pi.Seek(from)
+	if _, ok, err := p.Parse(pi); err != nil || !ok {
+		return 0, 0, false
+	}
+	// Nodes include the whitespace after them.
+	to = from + len(strings.TrimRight(src[from:pi.Index()], " \t\r\n"))
+	return from, to, true
+}
+
+// prefixStart returns the index of the prefix before the index, ignoring the
+// spaces between them, e.g. the if of "if x {", or -1 if there isn't one.
+func prefixStart(src string, index int64, prefix string) int {
+	if index < 0 || index > int64(len(src)) {
+		return -1
+	}
+	before := strings.TrimRight(src[:index], " \t")
+	if !strings.HasSuffix(before, prefix) {
+		return -1
+	}
+	return len(before) - len(prefix)
+}
@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const walkTestFile = `package main
+
+templ list(items []string, ok bool) {
+	<ul
+		class="list"
+		if ok {
+			data-ok
+		}
+	>
+		for _, item := range items {
+			<li>{ item }</li>
+		}
+	</ul>
+	if ok {
+		<p>ok</p>
+	} else if len(items) > 0 {
+		@item(items[0])
+	}
+	switch len(items) {
+		case 0:
+			<p>none</p>
+	}
+}
+
+css red() {
+	color: red;
+}
+`
+
+func TestWalk(t *testing.T) {
+	tf, err := ParseString(walkTestFile)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	var actual []string
+	var depth int
+	Inspect(tf, func(n any) bool {
+		if n == nil {
+			depth--
+			return true
+		}
+		name := fmt.Sprintf("%T", n)
+		if e, ok := n.(Element); ok {
+			name += " " + e.Name
+		}
+		if _, ok := n.(Whitespace); !ok {
+			actual = append(actual, strings.Repeat("  ", depth)+strings.TrimPrefix(name, "parser."))
+		}
+		depth++
+		return true
+	})
+	expected := []string{
+		"TemplateFile",
+		"  Package",
+		"  HTMLTemplate",
+		"    Element ul",
+		"      ConstantAttribute",
+		"      ConditionalAttribute",
+		"        BoolConstantAttribute",
+		"      ForExpression",
+		"        Element li",
+		"          StringExpression",
+		"    IfExpression",
+		"      Element p",
+		"        Text",
+		"      ElseIfExpression",
+		"        TemplElementExpression",
+		"    SwitchExpression",
+		"      CaseExpression",
+		"        Element p",
+		"          Text",
+		"  CSSTemplate",
+		"    ConstantCSSProperty",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	tf, err := ParseString(walkTestFile)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	var elements []string
+	Inspect(tf, func(n any) bool {
+		if _, ok := n.(ForExpression); ok {
+			return false
+		}
+		if e, ok := n.(Element); ok {
+			elements = append(elements, e.Name)
+		}
+		return true
+	})
+	if diff := cmp.Diff([]string{"ul", "p", "p"}, elements); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNodeRange(t *testing.T) {
+	tf, err := ParseString(walkTestFile)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	lines := strings.Split(walkTestFile, "\n")
+	var actual []string
+	Inspect(tf, func(n any) bool {
+		switch n.(type) {
+		case Element, IfExpression, ElseIfExpression, StringExpression, Text:
+			r, ok := NodeRange(n)
+			if !ok {
+				t.Errorf("expected a range for %T", n)
+				return true
+			}
+			line := lines[r.From.Line]
+			actual = append(actual, line[r.From.Col:r.To.Col])
+		case Whitespace:
+			if _, ok := NodeRange(n); ok {
+				t.Error("expected no range for whitespace")
+			}
+		}
+		return true
+	})
+	expected := []string{"ul", "li", "item", "ok", "p", "ok", "len(items) > 0", "p", "none"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNodeSpan(t *testing.T) {
+	tf, err := ParseString(walkTestFile)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	var actual []string
+	Inspect(tf, func(n any) bool {
+		switch n := n.(type) {
+		case Element, IfExpression, ForExpression, SwitchExpression, TemplElementExpression, StringExpression:
+			from, to, ok := NodeSpan(walkTestFile, n.(Node))
+			if !ok {
+				t.Errorf("expected a span for %T", n)
+				return true
+			}
+			actual = append(actual, walkTestFile[from:to])
+		case Whitespace:
+			if _, _, ok := NodeSpan(walkTestFile, n); ok {
+				t.Error("expected no span for whitespace")
+			}
+		}
+		return true
+	})
+	expected := []string{
+		"<ul\n\t\tclass=\"list\"\n\t\tif ok {\n\t\t\tdata-ok\n\t\t}\n\t>\n\t\tfor _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}\n\t</ul>",
+		"for _, item := range items {\n\t\t\t<li>{ item }</li>\n\t\t}",
+		"<li>{ item }</li>",
+		"{ item }",
+		"if ok {\n\t\t<p>ok</p>\n\t} else if len(items) > 0 {\n\t\t@item(items[0])\n\t}",
+		"<p>ok</p>",
+		"@item(items[0])",
+		"switch len(items) {\n\t\tcase 0:\n\t\t\t<p>none</p>\n\t}",
+		"<p>none</p>",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	tf, err := ParseString(walkTestFile)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	t.Run("template files are formatted", func(t *testing.T) {
+		var sb strings.Builder
+		if err := Print(&sb, tf); err != nil {
+			t.Fatalf("failed to print: %v", err)
+		}
+		if diff := cmp.Diff(walkTestFile, sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("nodes are printed without indentation", func(t *testing.T) {
+		var sb strings.Builder
+		Inspect(tf, func(n any) bool {
+			if e, ok := n.(Element); ok && e.Name == "li" {
+				if err := Print(&sb, e); err != nil {
+					t.Fatalf("failed to print: %v", err)
+				}
+			}
+			return true
+		})
+		if diff := cmp.Diff("<li>{ item }</li>", sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("unexpected types are an error", func(t *testing.T) {
+		if err := Print(new(strings.Builder), 1); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
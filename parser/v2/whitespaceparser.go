@@ -10,3 +10,39 @@ var whitespaceExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, er
 	}
 	return r, len(r.Value) > 0, nil
 })
+
+// Trim whitespace, e.g. <span>A</span> {-} <span>B</span>.
+// The whitespace before the marker is removed from the previous node by the
+// template node parser.
+var trimWhitespaceExpression = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	if _, ok, err = parse.String("{-}").Parse(pi); err != nil || !ok {
+		return
+	}
+	var r TrimWhitespace
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return r, false, err
+	}
+	r.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return r, false, err
+	}
+	return r, true, nil
+})
+
+// trimTrailingSpace returns the node without its trailing space, and the trailing
+// space that was removed.
+func trimTrailingSpace(n Node) (trimmed Node, ts TrailingSpace) {
+	switch n := n.(type) {
+	case Text:
+		ts, n.TrailingSpace = n.TrailingSpace, SpaceNone
+		return n, ts
+	case Element:
+		ts, n.TrailingSpace = n.TrailingSpace, SpaceNone
+		return n, ts
+	case StringExpression:
+		ts, n.TrailingSpace = n.TrailingSpace, SpaceNone
+		return n, ts
+	}
+	return n, SpaceNone
+}
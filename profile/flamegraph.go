@@ -0,0 +1,62 @@
+package profile
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+var flameGraphTemplate = template.Must(template.New("flamegraph").Funcs(template.FuncMap{
+	"width": func(f, parent *Frame) string {
+		if parent == nil || parent.Duration <= 0 {
+			return "100%"
+		}
+		return fmt.Sprintf("%.2f%%", float64(f.Duration)/float64(parent.Duration)*100)
+	},
+	"duration": func(d time.Duration) string {
+		return d.Round(time.Microsecond).String()
+	},
+	"size": formatSize,
+	"frame": func(f, parent *Frame) map[string]*Frame {
+		return map[string]*Frame{"Frame": f, "Parent": parent}
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+	<head>
+		<title>templ profile: {{ .Name }}</title>
+		<style>
+			body { font-family: sans-serif; margin: 1rem; }
+			.frame { box-sizing: border-box; min-width: 0; overflow: hidden; }
+			.label { box-sizing: border-box; margin: 1px; padding: 2px 4px; background: #f5a25d; border-radius: 2px; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; font-size: 12px; }
+			.frame .frame .label { background: #f7c873; }
+			.children { display: flex; }
+		</style>
+	</head>
+	<body>
+		<h1>{{ .Name }}</h1>
+		<p>Rendered in {{ duration .Duration }}, {{ size .Size }}. Hover over a component for details.</p>
+		{{ template "frame" frame . nil }}
+	</body>
+</html>
+{{ define "frame" }}{{ $f := .Frame }}
+<div class="frame" style="width: {{ width $f .Parent }}">
+	<div class="label" title="{{ $f.Name }} - {{ duration $f.Duration }}, {{ size $f.Size }}, rendered {{ $f.Count }} time(s)">{{ $f.Name }} {{ duration $f.Duration }}</div>
+	{{ if $f.Children }}<div class="children">{{ range $f.Children }}{{ template "frame" frame . $f }}{{ end }}</div>{{ end }}
+</div>
+{{ end }}`))
+
+// WriteFlameGraph writes a HTML page that displays the profile as a flame graph.
+func (f *Frame) WriteFlameGraph(w io.Writer) error {
+	return flameGraphTemplate.Execute(w, f)
+}
+
+func formatSize(n int) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/1024/1024)
+	case n >= 1024:
+		return fmt.Sprintf("%.1f kB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%d B", n)
+}
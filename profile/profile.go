@@ -0,0 +1,188 @@
+// Package profile records the time taken, and the number of bytes written, by
+// each component rendered during an HTTP request.
+//
+// Templates must be generated with the -instrument flag so that each component
+// is wrapped with templ.Trace. When the handler is wrapped with profile.Handler,
+// the profile of each request is sent to the templ dev proxy in a response
+// header, and the dev proxy displays the most recent profile as a flame graph
+// at /_templ/profile.
+package profile
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// HeaderName is the HTTP response header used to send a profile to the templ dev proxy.
+const HeaderName = "templ-profile"
+
+// Frame is the profile of a component, including the components it rendered.
+type Frame struct {
+	Name string `json:"name"`
+	// Count is the number of times the component was rendered.
+	Count int `json:"count"`
+	// Duration is the total time taken to render the component.
+	Duration time.Duration `json:"duration"`
+	// Size is the total number of bytes written by the component.
+	Size     int      `json:"size"`
+	Children []*Frame `json:"children,omitempty"`
+}
+
+// Merge combines child frames that have the same name, so that a component
+// rendered many times, e.g. in a loop, is shown as a single frame.
+func (f *Frame) Merge() {
+	var merged []*Frame
+	byName := make(map[string]*Frame, len(f.Children))
+	for _, c := range f.Children {
+		existing, ok := byName[c.Name]
+		if !ok {
+			byName[c.Name] = c
+			merged = append(merged, c)
+			continue
+		}
+		existing.Count += c.Count
+		existing.Duration += c.Duration
+		existing.Size += c.Size
+		existing.Children = append(existing.Children, c.Children...)
+	}
+	for _, c := range merged {
+		c.Merge()
+	}
+	f.Children = merged
+}
+
+// Encode the frame for use as the value of a HTTP header.
+func (f *Frame) Encode() (string, error) {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Decode a frame encoded by Encode.
+func Decode(s string) (f *Frame, err error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	f = &Frame{}
+	if err = json.Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type frameContextKey struct{}
+
+// recorder is a templ.Tracer that records the frames of a single request.
+type recorder struct {
+	m     sync.Mutex
+	start time.Time
+	root  *Frame
+}
+
+func newRecorder(name string) *recorder {
+	return &recorder{
+		start: time.Now(),
+		root:  &Frame{Name: name, Count: 1},
+	}
+}
+
+func (rec *recorder) parent(ctx context.Context) *Frame {
+	if f, ok := ctx.Value(frameContextKey{}).(*Frame); ok {
+		return f
+	}
+	return rec.root
+}
+
+func (rec *recorder) Start(ctx context.Context, name string) (context.Context, func(err error)) {
+	f := &Frame{Name: name, Count: 1}
+	parent := rec.parent(ctx)
+	rec.m.Lock()
+	parent.Children = append(parent.Children, f)
+	rec.m.Unlock()
+	start := time.Now()
+	return context.WithValue(ctx, frameContextKey{}, f), func(err error) {
+		rec.m.Lock()
+		defer rec.m.Unlock()
+		f.Duration = time.Since(start)
+	}
+}
+
+func (rec *recorder) RecordSize(ctx context.Context, name string, size int) {
+	rec.m.Lock()
+	defer rec.m.Unlock()
+	rec.parent(ctx).Size = size
+}
+
+// Profile returns the merged profile of the request so far.
+func (rec *recorder) Profile() *Frame {
+	rec.m.Lock()
+	defer rec.m.Unlock()
+	rec.root.Duration = time.Since(rec.start)
+	rec.root.Size = 0
+	for _, c := range rec.root.Children {
+		rec.root.Size += c.Size
+	}
+	rec.root.Merge()
+	return rec.root
+}
+
+// Handler wraps the HTTP handler to record the profile of each request, and
+// send it to the templ dev proxy in the templ-profile response header.
+//
+// Only components rendered before the response headers are written are
+// included in the profile. templ.Handler renders the component before
+// writing headers, so the whole component is included unless streaming
+// is enabled.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := newRecorder(r.Method + " " + r.URL.Path)
+		pw := &responseWriter{ResponseWriter: w, rec: rec}
+		next.ServeHTTP(pw, r.WithContext(templ.WithTracer(r.Context(), rec)))
+	})
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	rec         *recorder
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if v, err := w.rec.Profile().Encode(); err == nil {
+			w.Header().Set(HeaderName, v)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap allows http.ResponseController to access the underlying http.ResponseWriter.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
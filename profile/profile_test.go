@@ -0,0 +1,80 @@
+package profile
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestHandler(t *testing.T) {
+	item := templ.Trace("pkg.Item", templ.Raw("<li>Item</li>"))
+	list := templ.Trace("pkg.List", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for i := 0; i < 3; i++ {
+			if err := item.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	h := Handler(templ.Handler(list))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/list", nil))
+
+	actual, err := Decode(w.Header().Get(HeaderName))
+	if err != nil {
+		t.Fatalf("failed to decode profile: %v", err)
+	}
+	expected := &Frame{
+		Name:  "GET /list",
+		Count: 1,
+		Size:  39,
+		Children: []*Frame{
+			{
+				Name:  "pkg.List",
+				Count: 1,
+				Size:  39,
+				Children: []*Frame{
+					{Name: "pkg.Item", Count: 3, Size: 39},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, actual, cmpopts.IgnoreFields(Frame{}, "Duration")); diff != "" {
+		t.Error(diff)
+	}
+	if w.Body.String() != strings.Repeat("<li>Item</li>", 3) {
+		t.Errorf("unexpected body: %q", w.Body.String())
+	}
+}
+
+func TestWriteFlameGraph(t *testing.T) {
+	f := &Frame{
+		Name:     "GET /",
+		Count:    1,
+		Duration: 100,
+		Children: []*Frame{
+			{Name: "pkg.Page", Count: 1, Duration: 50, Size: 2048},
+		},
+	}
+	w := new(strings.Builder)
+	if err := f.WriteFlameGraph(w); err != nil {
+		t.Fatalf("failed to write flame graph: %v", err)
+	}
+	for _, expected := range []string{
+		"<title>templ profile: GET /</title>",
+		"width: 50.00%",
+		"pkg.Page - 0s, 2.0 kB, rendered 1 time(s)",
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, w.String())
+		}
+	}
+}
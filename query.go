@@ -0,0 +1,108 @@
+package templ
+
+import (
+	"context"
+	"net/url"
+)
+
+const requestURLContextKey = contextKeyType(6)
+
+// WithRequestURL returns a context that contains the URL of the request, which
+// is used by WithQuery, WithoutQuery and Query. Handler adds the URL of each
+// request to the context.
+func WithRequestURL(ctx context.Context, u *url.URL) context.Context {
+	return context.WithValue(ctx, requestURLContextKey, u)
+}
+
+// GetRequestURL returns the URL of the request, or nil if the context doesn't
+// contain one.
+func GetRequestURL(ctx context.Context) *url.URL {
+	u, _ := ctx.Value(requestURLContextKey).(*url.URL)
+	return u
+}
+
+// WithQuery returns the URL of the request with the query string parameter set
+// to the value, keeping the other parameters, e.g. templ.WithQuery(ctx, "page", 2)
+// to link to another page of the same search results.
+func WithQuery(ctx context.Context, param string, value any) SafeURL {
+	return Query(ctx).Set(param, value).URL()
+}
+
+// WithoutQuery returns the URL of the request without the query string
+// parameters, e.g. to clear a filter.
+func WithoutQuery(ctx context.Context, params ...string) SafeURL {
+	q := Query(ctx)
+	for _, param := range params {
+		q.Del(param)
+	}
+	return q.URL()
+}
+
+// QueryURL is a copy of the URL of the request, with query string parameters
+// that can be changed, e.g.
+//
+//	templ.Query(ctx).Set("sort", "name").Del("page").URL()
+//
+// Values are converted to strings. If a value can't be converted, URL returns
+// FailedSanitizationURL.
+type QueryURL struct {
+	u      url.URL
+	values url.Values
+	err    error
+}
+
+// Query returns a copy of the URL of the request, with query string parameters
+// that can be changed. If the context doesn't contain the URL of the request,
+// the URL only contains the query string, e.g. ?page=2, which links to the
+// current path without the other parameters.
+func Query(ctx context.Context) *QueryURL {
+	q := &QueryURL{}
+	if u := GetRequestURL(ctx); u != nil {
+		q.u = *u
+	}
+	q.values = q.u.Query()
+	return q
+}
+
+// Set sets the parameter to the value, replacing its other values.
+func (q *QueryURL) Set(param string, value any) *QueryURL {
+	s, err := toString(value)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.values.Set(param, s)
+	return q
+}
+
+// Add adds the value to the values of the parameter.
+func (q *QueryURL) Add(param string, value any) *QueryURL {
+	s, err := toString(value)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.values.Add(param, s)
+	return q
+}
+
+// Del removes the values of the parameter.
+func (q *QueryURL) Del(param string) *QueryURL {
+	q.values.Del(param)
+	return q
+}
+
+// URL returns the sanitized URL. The parameters are sorted by name.
+func (q *QueryURL) URL() SafeURL {
+	if q.err != nil {
+		return FailedSanitizationURL
+	}
+	u := q.u
+	u.RawQuery = q.values.Encode()
+	u.ForceQuery = false
+	if u.String() == "" {
+		// An empty URL links to the current page, including its query string.
+		return URL("?")
+	}
+	return URL(u.String())
+}
@@ -0,0 +1,94 @@
+package templ_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestQuery(t *testing.T) {
+	u, err := url.Parse("/search?q=shoes&page=3&sort=price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := templ.WithRequestURL(context.Background(), u)
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		actual   func(ctx context.Context) templ.SafeURL
+		expected templ.SafeURL
+	}{
+		{
+			name:     "WithQuery sets the parameter and keeps the others",
+			ctx:      ctx,
+			actual:   func(ctx context.Context) templ.SafeURL { return templ.WithQuery(ctx, "page", 4) },
+			expected: "/search?page=4&q=shoes&sort=price",
+		},
+		{
+			name:     "values are escaped",
+			ctx:      ctx,
+			actual:   func(ctx context.Context) templ.SafeURL { return templ.WithQuery(ctx, "q", "red & blue") },
+			expected: "/search?page=3&q=red+%26+blue&sort=price",
+		},
+		{
+			name:     "WithoutQuery removes the parameters",
+			ctx:      ctx,
+			actual:   func(ctx context.Context) templ.SafeURL { return templ.WithoutQuery(ctx, "page", "sort") },
+			expected: "/search?q=shoes",
+		},
+		{
+			name: "Query changes several parameters",
+			ctx:  ctx,
+			actual: func(ctx context.Context) templ.SafeURL {
+				return templ.Query(ctx).Set("sort", "name").Del("page").Add("tag", "a").Add("tag", "b").URL()
+			},
+			expected: "/search?q=shoes&sort=name&tag=a&tag=b",
+		},
+		{
+			name:     "without a request URL, only the query string is returned",
+			ctx:      context.Background(),
+			actual:   func(ctx context.Context) templ.SafeURL { return templ.WithQuery(ctx, "page", 2) },
+			expected: "?page=2",
+		},
+		{
+			name:     "removing every parameter of an empty URL clears the query string",
+			ctx:      context.Background(),
+			actual:   func(ctx context.Context) templ.SafeURL { return templ.WithoutQuery(ctx, "page") },
+			expected: "?",
+		},
+		{
+			name:     "values that can't be converted to strings fail sanitization",
+			ctx:      ctx,
+			actual:   func(ctx context.Context) templ.SafeURL { return templ.WithQuery(ctx, "page", struct{}{}) },
+			expected: templ.FailedSanitizationURL,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.actual(tt.ctx); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+	if u.String() != "/search?q=shoes&page=3&sort=price" {
+		t.Errorf("expected the request URL not to be modified, got %q", u.String())
+	}
+}
+
+func TestHandlerAddsRequestURL(t *testing.T) {
+	c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, string(templ.WithQuery(ctx, "page", 2)))
+		return err
+	})
+	w := httptest.NewRecorder()
+	templ.Handler(c).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?page=1", nil))
+	if actual := w.Body.String(); actual != "/items?page=2" {
+		t.Errorf("expected /items?page=2, got %q", actual)
+	}
+}
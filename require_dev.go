@@ -0,0 +1,102 @@
+//go:build templ_dev
+
+package templ
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DevChecks is true if the invariants declared with Require are checked, i.e.
+// the program is built with the templ_dev build tag.
+const DevChecks = true
+
+// Require declares an invariant of a component, e.g. a limit on the length of a
+// slice parameter:
+//
+//	templ list(items []Item) {
+//		@templ.Require(len(items) <= 100, "too many items")
+//		...
+//	}
+//
+// If the program is built with the templ_dev build tag, and the invariant isn't
+// met, the message is logged, and rendered in an error element with the file
+// and line of the call to Require, in the .templ file if it's called by
+// generated code. Otherwise, Require renders nothing.
+func Require(ok bool, message string) Component {
+	if ok {
+		return NopComponent
+	}
+	location := "unknown"
+	if _, file, line, found := runtime.Caller(1); found {
+		location = fmt.Sprintf("%s:%d", file, line)
+		if templFile, templLine, ok := templLocation(file, line); ok {
+			location = fmt.Sprintf("%s:%d", templFile, templLine)
+		}
+	}
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		log.Printf("templ: requirement failed at %s: %s", location, message)
+		return writeStrings(w,
+			`<div data-templ-requirement style="border:2px solid #dc2626;background:#fef2f2;color:#991b1b;padding:0.5em;font-family:monospace;white-space:pre-wrap">`,
+			`templ: requirement failed: `, EscapeString(message), "\n", EscapeString(location),
+			`</div>`)
+	})
+}
+
+// generatedSourceLines caches the SourceLines of the templates in each generated
+// _templ.go file, by the path of the file.
+var generatedSourceLines sync.Map
+
+var sourceLinesPattern = regexp.MustCompile(`templ\.SourceLines\{FileName: ("(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `), From: (\d+), To: (\d+), Lines: \[\]int\{([\d, ]*)\}\}`)
+
+// templLocation returns the .templ file and line that the line of a generated
+// _templ.go file was generated from. The source lines of the templates are read
+// from the generated file, since it's on disk when developing.
+func templLocation(file string, line int) (templFile string, templLine int, ok bool) {
+	if !strings.HasSuffix(file, "_templ.go") {
+		return "", 0, false
+	}
+	v, loaded := generatedSourceLines.Load(file)
+	if !loaded {
+		v, _ = generatedSourceLines.LoadOrStore(file, readSourceLines(file))
+	}
+	for _, sl := range v.([]SourceLines) {
+		if templLine, ok = sl.templLine(line); ok {
+			return sl.FileName, templLine, true
+		}
+	}
+	return "", 0, false
+}
+
+// readSourceLines reads the SourceLines of the templates in a generated file.
+func readSourceLines(file string) (sourceLines []SourceLines) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	for _, m := range sourceLinesPattern.FindAllStringSubmatch(string(src), -1) {
+		var sl SourceLines
+		if sl.FileName, err = strconv.Unquote(m[1]); err != nil {
+			continue
+		}
+		sl.From, _ = strconv.Atoi(m[2])
+		sl.To, _ = strconv.Atoi(m[3])
+		for _, n := range strings.Split(m[4], ",") {
+			if n = strings.TrimSpace(n); n == "" {
+				continue
+			}
+			line, _ := strconv.Atoi(n)
+			sl.Lines = append(sl.Lines, line)
+		}
+		sourceLines = append(sourceLines, sl)
+	}
+	return sourceLines
+}
@@ -0,0 +1,38 @@
+//go:build templ_dev
+
+package templ
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequireTemplLocation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "page_templ.go")
+	src := "package main\n\n" +
+		"var templ_7745c5c3_SourceLines_1 = templ.SourceLines{FileName: `components/page.templ`, From: 10, To: 40, Lines: []int{12, 3, 20, 5}}\n" +
+		"var templ_7745c5c3_SourceLines_2 = templ.SourceLines{FileName: \"components/page.templ\", From: 50, To: 60, Lines: []int{52, 9}}\n"
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		line         int
+		expectedLine int
+		expectedOK   bool
+	}{
+		{line: 12, expectedLine: 3, expectedOK: true},
+		{line: 25, expectedLine: 5, expectedOK: true},
+		{line: 55, expectedLine: 9, expectedOK: true},
+		{line: 45, expectedOK: false},
+	}
+	for _, tt := range tests {
+		templFile, line, ok := templLocation(file, tt.line)
+		if ok != tt.expectedOK || line != tt.expectedLine {
+			t.Errorf("line %d: expected %d %v, got %d %v", tt.line, tt.expectedLine, tt.expectedOK, line, ok)
+		}
+		if ok && templFile != "components/page.templ" {
+			t.Errorf("line %d: expected components/page.templ, got %q", tt.line, templFile)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !templ_dev
+
+package templ
+
+// DevChecks is true if the invariants declared with Require are checked, i.e.
+// the program is built with the templ_dev build tag.
+const DevChecks = false
+
+// Require declares an invariant of a component, e.g. a limit on the length of a
+// slice parameter:
+//
+//	templ list(items []Item) {
+//		@templ.Require(len(items) <= 100, "too many items")
+//		...
+//	}
+//
+// If the program is built with the templ_dev build tag, and the invariant isn't
+// met, the message is logged, and rendered in an error element with the file
+// and line of the call to Require, in the .templ file if it's called by
+// generated code. Otherwise, Require renders nothing.
+func Require(ok bool, message string) Component {
+	return NopComponent
+}
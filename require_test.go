@@ -0,0 +1,38 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestRequire(t *testing.T) {
+	t.Run("nothing is rendered if the requirement is met", func(t *testing.T) {
+		var sb strings.Builder
+		if err := templ.Require(true, "ok").Render(context.Background(), &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.Len() != 0 {
+			t.Errorf("expected no output, got %q", sb.String())
+		}
+	})
+	t.Run("the error is only rendered in dev builds", func(t *testing.T) {
+		var sb strings.Builder
+		if err := templ.Require(false, "too many <items>").Render(context.Background(), &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !templ.DevChecks {
+			if sb.Len() != 0 {
+				t.Errorf("expected no output without the templ_dev build tag, got %q", sb.String())
+			}
+			return
+		}
+		for _, expected := range []string{"<div data-templ-requirement", "too many &lt;items&gt;", "require_test.go:"} {
+			if !strings.Contains(sb.String(), expected) {
+				t.Errorf("expected %q in output, got %q", expected, sb.String())
+			}
+		}
+	})
+}
@@ -5,13 +5,10 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"html"
-	"html/template"
 	"io"
-	"net/http"
 	"os"
 	"reflect"
 	"runtime"
@@ -20,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/a-h/templ/safehtml"
 )
@@ -41,12 +39,16 @@ func (cf ComponentFunc) Render(ctx context.Context, w io.Writer) error {
 	return cf(ctx, w)
 }
 
+// WithChildren returns a context that passes children to the next component that
+// is rendered, see GetChildren.
 func WithChildren(ctx context.Context, children Component) context.Context {
 	ctx, v := getContext(ctx)
 	v.children = &children
 	return ctx
 }
 
+// ClearChildren removes the children from the context, so that they aren't passed
+// to the components rendered by a component.
 func ClearChildren(ctx context.Context) context.Context {
 	_, v := getContext(ctx)
 	v.children = nil
@@ -65,72 +67,56 @@ func GetChildren(ctx context.Context) Component {
 	return *v.children
 }
 
-// ComponentHandler is a http.Handler that renders components.
-type ComponentHandler struct {
-	Component    Component
-	Status       int
-	ContentType  string
-	ErrorHandler func(r *http.Request, err error) http.Handler
+// WithBlocks adds the blocks that override the blocks of a template to the
+// context, before the template is rendered by a template that extends it. The
+// blocks that are already in the context take precedence, because they're
+// from a template that extends the template that adds the blocks.
+func WithBlocks(ctx context.Context, blocks map[string]Component) context.Context {
+	ctx, v := getContext(ctx)
+	merged := make(map[string]Component, len(blocks)+len(v.blocks))
+	for name, block := range blocks {
+		merged[name] = block
+	}
+	for name, block := range v.blocks {
+		merged[name] = block
+	}
+	v.blocks = merged
+	return ctx
 }
 
-const componentHandlerErrorMessage = "templ: failed to render template"
+// ClearBlocks removes the blocks from the context, so that they aren't used by
+// the components rendered by the template that uses them.
+func ClearBlocks(ctx context.Context) context.Context {
+	_, v := getContext(ctx)
+	v.blocks = nil
+	return ctx
+}
 
-// ServeHTTP implements the http.Handler interface.
-func (ch ComponentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Since the component may error, write to a buffer first.
-	// This prevents partial responses from being written to the client.
-	buf := GetBuffer()
-	defer ReleaseBuffer(buf)
-	err := ch.Component.Render(r.Context(), buf)
-	if err != nil {
-		if ch.ErrorHandler != nil {
-			w.Header().Set("Content-Type", ch.ContentType)
-			ch.ErrorHandler(r, err).ServeHTTP(w, r)
-			return
-		}
-		http.Error(w, componentHandlerErrorMessage, http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", ch.ContentType)
-	if ch.Status != 0 {
-		w.WriteHeader(ch.Status)
-	}
-	// Ignore write error like http.Error() does, because there is
-	// no way to recover at this point.
-	_, _ = w.Write(buf.Bytes())
+// GetBlocks from the context.
+func GetBlocks(ctx context.Context) map[string]Component {
+	_, v := getContext(ctx)
+	return v.blocks
 }
 
-// Handler creates a http.Handler that renders the template.
-func Handler(c Component, options ...func(*ComponentHandler)) *ComponentHandler {
-	ch := &ComponentHandler{
-		Component:   c,
-		ContentType: "text/html; charset=utf-8",
-	}
-	for _, o := range options {
-		o(ch)
-	}
-	return ch
+// ContentTyper is implemented by components that render content other than HTML,
+// e.g. XML templates. The content type is used as the default Content-Type header
+// of a ComponentHandler.
+type ContentTyper interface {
+	ContentType() string
 }
 
-// WithStatus sets the HTTP status code returned by the ComponentHandler.
-func WithStatus(status int) func(*ComponentHandler) {
-	return func(ch *ComponentHandler) {
-		ch.Status = status
-	}
+// ContentType sets the content type of the component's output.
+func ContentType(contentType string, c Component) Component {
+	return contentTypeComponent{Component: c, contentType: contentType}
 }
 
-// WithConentType sets the Content-Type header returned by the ComponentHandler.
-func WithContentType(contentType string) func(*ComponentHandler) {
-	return func(ch *ComponentHandler) {
-		ch.ContentType = contentType
-	}
+type contentTypeComponent struct {
+	Component
+	contentType string
 }
 
-// WithErrorHandler sets the error handler used if rendering fails.
-func WithErrorHandler(eh func(r *http.Request, err error) http.Handler) func(*ComponentHandler) {
-	return func(ch *ComponentHandler) {
-		ch.ErrorHandler = eh
-	}
+func (c contentTypeComponent) ContentType() string {
+	return c.contentType
 }
 
 // EscapeString escapes HTML text within templates.
@@ -144,7 +130,11 @@ func Bool(value bool) bool {
 }
 
 // Classes for CSS.
-// Supported types are string, ConstantCSSClass, ComponentCSSClass, map[string]bool.
+// Supported types are string, CSSClass, fmt.Stringer, map[string]bool, KeyValue
+// pairs of any supported type and a bool, and slices of any supported type.
+// Class names are rendered in the order they're first added, without
+// duplicates, and the last pair for a class name determines whether it's
+// rendered.
 func Classes(classes ...any) CSSClasses {
 	return CSSClasses(classes)
 }
@@ -176,17 +166,23 @@ type cssProcessor struct {
 }
 
 func (cp *cssProcessor) Add(item any) {
+	cp.add(item, true)
+}
+
+// add adds the class names of the item. If enabled is false, the class names
+// are disabled, e.g. by templ.KV(templ.Classes("a", "b"), false).
+func (cp *cssProcessor) add(item any, enabled bool) {
 	switch c := item.(type) {
 	case []string:
 		for _, className := range c {
-			cp.AddClassName(className, true)
+			cp.AddClassName(className, enabled)
 		}
 	case string:
-		cp.AddClassName(c, true)
+		cp.AddClassName(c, enabled)
 	case ConstantCSSClass:
-		cp.AddClassName(c.ClassName(), true)
+		cp.AddClassName(c.ClassName(), enabled)
 	case ComponentCSSClass:
-		cp.AddClassName(c.ClassName(), true)
+		cp.AddClassName(c.ClassName(), enabled)
 	case map[string]bool:
 		// In Go, map keys are iterated in a randomized order.
 		// So the keys in the map must be sorted to produce consistent output.
@@ -198,34 +194,117 @@ func (cp *cssProcessor) Add(item any) {
 		}
 		sort.Strings(keys)
 		for _, className := range keys {
-			cp.AddClassName(className, c[className])
+			cp.AddClassName(className, enabled && c[className])
 		}
 	case []KeyValue[string, bool]:
 		for _, kv := range c {
-			cp.AddClassName(kv.Key, kv.Value)
+			cp.AddClassName(kv.Key, enabled && kv.Value)
 		}
 	case KeyValue[string, bool]:
-		cp.AddClassName(c.Key, c.Value)
+		cp.AddClassName(c.Key, enabled && c.Value)
 	case []KeyValue[CSSClass, bool]:
 		for _, kv := range c {
-			cp.AddClassName(kv.Key.ClassName(), kv.Value)
+			cp.AddClassName(kv.Key.ClassName(), enabled && kv.Value)
 		}
 	case KeyValue[CSSClass, bool]:
-		cp.AddClassName(c.Key.ClassName(), c.Value)
+		cp.AddClassName(c.Key.ClassName(), enabled && c.Value)
 	case CSSClasses:
 		for _, item := range c {
-			cp.Add(item)
+			cp.add(item, enabled)
+		}
+	case []any:
+		for _, item := range c {
+			cp.add(item, enabled)
 		}
 	case func() CSSClass:
-		cp.AddClassName(c().ClassName(), true)
+		cp.AddClassName(c().ClassName(), enabled)
+	case CSSClass:
+		cp.AddClassName(c.ClassName(), enabled)
+	case conditionalClass:
+		key, value, ok := c.conditionalClass()
+		if !ok {
+			cp.AddClassName(unknownTypeClassName, enabled)
+			return
+		}
+		cp.add(key, enabled && value)
+	case fmt.Stringer:
+		cp.AddClassName(c.String(), enabled)
 	default:
-		cp.AddClassName(unknownTypeClassName, true)
+		cp.addValue(reflect.ValueOf(item), enabled)
+	}
+}
+
+// addValue adds the class names of types that don't have a case of their own,
+// e.g. a named string type, a slice of KeyValue pairs, or a map of a named
+// string type to bool.
+func (cp *cssProcessor) addValue(v reflect.Value, enabled bool) {
+	switch v.Kind() {
+	case reflect.String:
+		cp.AddClassName(v.String(), enabled)
+		return
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			cp.add(v.Index(i).Interface(), enabled)
+		}
+		return
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.Bool {
+			break
+		}
+		type entry struct {
+			name    string
+			enabled bool
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			name, ok := className(iter.Key().Interface())
+			if !ok {
+				name = unknownTypeClassName
+			}
+			entries = append(entries, entry{name: name, enabled: iter.Value().Bool()})
+		}
+		// Sort the keys to produce consistent output.
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+		for _, e := range entries {
+			cp.AddClassName(e.name, enabled && e.enabled)
+		}
+		return
 	}
+	cp.AddClassName(unknownTypeClassName, enabled)
 }
 
+// className returns the class name of a map key.
+func className(key any) (name string, ok bool) {
+	switch key := key.(type) {
+	case CSSClass:
+		return key.ClassName(), true
+	case fmt.Stringer:
+		return key.String(), true
+	}
+	if v := reflect.ValueOf(key); v.Kind() == reflect.String {
+		return v.String(), true
+	}
+	return "", false
+}
+
+// conditionalClass is implemented by KeyValue, so that pairs of any key type
+// and a bool can be used as classes, e.g. templ.KV(templ.Classes("a", "b"), true).
+type conditionalClass interface {
+	conditionalClass() (key any, enabled bool, ok bool)
+}
+
+func (kv KeyValue[TKey, TValue]) conditionalClass() (key any, enabled bool, ok bool) {
+	enabled, ok = any(kv.Value).(bool)
+	return kv.Key, enabled, ok
+}
+
+// AddClassName adds the class names, which are separated by whitespace.
 func (cp *cssProcessor) AddClassName(className string, enabled bool) {
-	cp.classNameToEnabled[className] = enabled
-	cp.orderedNames = append(cp.orderedNames, className)
+	for _, name := range strings.Fields(className) {
+		cp.classNameToEnabled[name] = enabled
+		cp.orderedNames = append(cp.orderedNames, name)
+	}
 }
 
 func (cp *cssProcessor) String() string {
@@ -247,13 +326,13 @@ func (cp *cssProcessor) String() string {
 }
 
 // KeyValue is a key and value pair.
-type KeyValue[TKey comparable, TValue any] struct {
+type KeyValue[TKey any, TValue any] struct {
 	Key   TKey   `json:"name"`
 	Value TValue `json:"value"`
 }
 
 // KV creates a new key/value pair from the input key and value.
-func KV[TKey comparable, TValue any](key TKey, value TValue) KeyValue[TKey, TValue] {
+func KV[TKey any, TValue any](key TKey, value TValue) KeyValue[TKey, TValue] {
 	return KeyValue[TKey, TValue]{
 		Key:   key,
 		Value: value,
@@ -311,74 +390,6 @@ func CSSID(name string, css string) string {
 	return name + "_" + hp
 }
 
-// NewCSSMiddleware creates HTTP middleware that renders a global stylesheet of ComponentCSSClass
-// CSS if the request path matches, or updates the HTTP context to ensure that any handlers that
-// use templ.Components skip rendering <style> elements for classes that are included in the global
-// stylesheet. By default, the stylesheet path is /styles/templ.css
-func NewCSSMiddleware(next http.Handler, classes ...CSSClass) CSSMiddleware {
-	return CSSMiddleware{
-		Path:       "/styles/templ.css",
-		CSSHandler: NewCSSHandler(classes...),
-		Next:       next,
-	}
-}
-
-// CSSMiddleware renders a global stylesheet.
-type CSSMiddleware struct {
-	Path       string
-	CSSHandler CSSHandler
-	Next       http.Handler
-}
-
-func (cssm CSSMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path == cssm.Path {
-		cssm.CSSHandler.ServeHTTP(w, r)
-		return
-	}
-	// Add registered classes to the context.
-	ctx, v := getContext(r.Context())
-	for _, c := range cssm.CSSHandler.Classes {
-		v.addClass(c.ID)
-	}
-	// Serve the request. Templ components will use the updated context
-	// to know to skip rendering <style> elements for any component CSS
-	// classes that have been included in the global stylesheet.
-	cssm.Next.ServeHTTP(w, r.WithContext(ctx))
-}
-
-// NewCSSHandler creates a handler that serves a stylesheet containing the CSS of the
-// classes passed in. This is used by the CSSMiddleware to provide global stylesheets
-// for templ components.
-func NewCSSHandler(classes ...CSSClass) CSSHandler {
-	ccssc := make([]ComponentCSSClass, 0, len(classes))
-	for _, c := range classes {
-		ccss, ok := c.(ComponentCSSClass)
-		if !ok {
-			continue
-		}
-		ccssc = append(ccssc, ccss)
-	}
-	return CSSHandler{
-		Classes: ccssc,
-	}
-}
-
-// CSSHandler is a HTTP handler that serves CSS.
-type CSSHandler struct {
-	Logger  func(err error)
-	Classes []ComponentCSSClass
-}
-
-func (cssh CSSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/css")
-	for _, c := range cssh.Classes {
-		_, err := w.Write([]byte(c.Class))
-		if err != nil && cssh.Logger != nil {
-			cssh.Logger(err)
-		}
-	}
-}
-
 // RenderCSSItems renders the CSS to the writer, if the items haven't already been rendered.
 func RenderCSSItems(ctx context.Context, w io.Writer, classes ...any) (err error) {
 	if len(classes) == 0 {
@@ -387,6 +398,10 @@ func RenderCSSItems(ctx context.Context, w io.Writer, classes ...any) (err error
 	_, v := getContext(ctx)
 	sb := new(strings.Builder)
 	renderCSSItemsToBuilder(sb, v, classes...)
+	if v.criticalCSS != nil {
+		v.criticalCSS.WriteString(sb.String())
+		return nil
+	}
 	if sb.Len() > 0 {
 		if _, err = io.WriteString(w, `<style type="text/css">`); err != nil {
 			return err
@@ -421,6 +436,8 @@ func renderCSSItemsToBuilder(sb *strings.Builder, v *contextValue, classes ...an
 			renderCSSItemsToBuilder(sb, v, ccc.Key)
 		case CSSClasses:
 			renderCSSItemsToBuilder(sb, v, ccc...)
+		case []any:
+			renderCSSItemsToBuilder(sb, v, ccc...)
 		case func() CSSClass:
 			renderCSSItemsToBuilder(sb, v, ccc())
 		case []string:
@@ -441,6 +458,10 @@ func renderCSSItemsToBuilder(sb *strings.Builder, v *contextValue, classes ...an
 			// Skip. These are class names, not CSS classes.
 		case []KeyValue[ConstantCSSClass, bool]:
 			// Skip. These are class names, not CSS classes.
+		case conditionalClass:
+			if key, enabled, ok := ccc.conditionalClass(); ok && enabled {
+				renderCSSItemsToBuilder(sb, v, key)
+			}
 		}
 	}
 }
@@ -464,6 +485,19 @@ func SanitizeCSS[T ~string](property string, value T) SafeCSS {
 // Attributes is an alias to map[string]any made for spread attributes.
 type Attributes map[string]any
 
+// MergeAttributes merges the attributes into a single set of attributes, so that
+// they can be spread onto an element. If more than one sets an attribute, the
+// last value is used.
+func MergeAttributes(attrs ...Attributes) Attributes {
+	merged := Attributes{}
+	for _, a := range attrs {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
 // sortedKeys returns the keys of a map in sorted order.
 func sortedKeys(m map[string]any) (keys []string) {
 	keys = make([]string, len(m))
@@ -499,6 +533,10 @@ func RenderAttributes(ctx context.Context, w io.Writer, attributes Attributes) (
 					return err
 				}
 			}
+		case SafeCSS:
+			if err = writeStrings(w, ` `, EscapeString(key), `="`, EscapeString(string(value)), `"`); err != nil {
+				return err
+			}
 		case bool:
 			if value {
 				if err = writeStrings(w, ` `, EscapeString(key)); err != nil {
@@ -534,17 +572,92 @@ func RenderAttributes(ctx context.Context, w io.Writer, attributes Attributes) (
 	return nil
 }
 
+// RenderAttribute renders an attribute with a name that is computed when the
+// template is rendered, e.g. <div { "data-" + key }={ value }>. An error is
+// returned if the name isn't a valid attribute name, or if it's the name of an
+// event handler, because its value would be run as JavaScript. The values of
+// attributes that contain URLs are sanitized.
+func RenderAttribute(w io.Writer, name, value string) (err error) {
+	if !isValidAttributeName(name) {
+		return fmt.Errorf("templ: invalid attribute name %q", name)
+	}
+	lower := strings.ToLower(name)
+	if strings.HasPrefix(lower, "on") || strings.HasPrefix(lower, "hx-on") {
+		return fmt.Errorf("templ: event handler attribute %q can't have a computed name", name)
+	}
+	if urlAttributes[lower] {
+		value = string(URL(value))
+	}
+	return writeStrings(w, ` `, name, `="`, EscapeString(value), `"`)
+}
+
+var urlAttributes = map[string]bool{
+	"action":     true,
+	"cite":       true,
+	"formaction": true,
+	"href":       true,
+	"poster":     true,
+	"src":        true,
+	"xlink:href": true,
+}
+
+// isValidAttributeName returns true if the name can be used as an attribute
+// name without changing the structure of the element.
+// https://html.spec.whatwg.org/multipage/syntax.html#attributes-2
+func isValidAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) || unicode.IsSpace(r) || r == unicode.ReplacementChar || strings.ContainsRune(`"'<>/=`, r) {
+			return false
+		}
+	}
+	return true
+}
+
 // Script handling.
 
+// ScriptArgMarshaler is implemented by types that customise how they're passed
+// to script templates. MarshalScriptArg returns a JavaScript expression that's
+// used as the argument, instead of the JSON encoded value.
+//
+// The expression isn't sanitized, so it must not contain untrusted input.
+type ScriptArgMarshaler interface {
+	MarshalScriptArg() (string, error)
+}
+
+// encodeScriptParam encodes a parameter of a script template call. Values are
+// JSON encoded, except for time.Time values, which are passed as Date objects,
+// and ScriptArgMarshaler implementations. Values that can't be encoded are null.
+func encodeScriptParam(param any) string {
+	switch p := param.(type) {
+	case ScriptArgMarshaler:
+		expr, err := p.MarshalScriptArg()
+		if err != nil {
+			return "null"
+		}
+		return expr
+	case time.Time:
+		enc, _ := marshalJSON(p.Format(time.RFC3339Nano))
+		return "new Date(" + string(enc) + ")"
+	}
+	enc, err := marshalJSON(param)
+	if err != nil {
+		return "null"
+	}
+	return string(enc)
+}
+
 func safeEncodeScriptParams(escapeHTML bool, params []any) []string {
 	encodedParams := make([]string, len(params))
 	for i := 0; i < len(encodedParams); i++ {
-		enc, _ := json.Marshal(params[i])
+		enc := encodeScriptParam(params[i])
 		if !escapeHTML {
-			encodedParams[i] = string(enc)
+			encodedParams[i] = enc
 			continue
 		}
-		encodedParams[i] = EscapeString(string(enc))
+		encodedParams[i] = EscapeString(enc)
 	}
 	return encodedParams
 }
@@ -571,6 +684,47 @@ func SafeScriptInline(functionName string, params ...any) string {
 	return sb.String()
 }
 
+// JSONScript renders a <script type="application/json"> element that contains
+// the JSON encoded value, so that it can be read by client-side scripts, e.g.
+// JSON.parse(document.getElementById(id).textContent). The id attribute is
+// omitted if id is empty. Use WithType to render structured data, e.g.
+// templ.JSONScript("", v).WithType("application/ld+json").
+//
+// The JSON is encoded with <, > and & escaped, so the value can't close the
+// script element.
+func JSONScript(id string, v any) JSONScriptElement {
+	return JSONScriptElement{ID: id, Type: "application/json", Data: v}
+}
+
+// JSONScriptElement is a <script> element that contains a JSON encoded value,
+// see JSONScript.
+type JSONScriptElement struct {
+	ID   string
+	Type string
+	Data any
+}
+
+// WithType returns a copy of the element with the type attribute set to t.
+func (j JSONScriptElement) WithType(t string) JSONScriptElement {
+	j.Type = t
+	return j
+}
+
+func (j JSONScriptElement) Render(ctx context.Context, w io.Writer) (err error) {
+	data, err := marshalJSON(j.Data)
+	if err != nil {
+		return err
+	}
+	if j.ID != "" {
+		if err = writeStrings(w, `<script id="`, EscapeString(j.ID), `" `); err != nil {
+			return err
+		}
+	} else if _, err = io.WriteString(w, `<script `); err != nil {
+		return err
+	}
+	return writeStrings(w, `type="`, EscapeString(j.Type), `">`, string(data), `</script>`)
+}
+
 type contextKeyType int
 
 const contextKey = contextKeyType(0)
@@ -578,6 +732,10 @@ const contextKey = contextKeyType(0)
 type contextValue struct {
 	ss       map[string]struct{}
 	children *Component
+	// blocks that override the blocks of a template, see WithBlocks.
+	blocks map[string]Component
+	// criticalCSS collects the CSS of rendered classes when set, see CriticalCSS.
+	criticalCSS *strings.Builder
 }
 
 func (v *contextValue) addScript(s string) {
@@ -659,6 +817,41 @@ type ComponentScript struct {
 	// This is can be used to call the function inside a script tag:
 	//    <script>__templ_functionName_sha("some string",12345))</script>
 	CallInline string
+	// Params of the call, used by WithDataArgs.
+	Params []any
+	// DataArgs is the JSON encoded array of parameters read by the Call, if
+	// the parameters are passed in a data attribute. See WithDataArgs.
+	DataArgs string
+}
+
+// WithDataArgs returns a script that passes the parameters of the call in a
+// data attribute of the element, instead of inline in the event handler, e.g.
+//
+//	<button onclick="__templ_functionName_sha.apply(this, JSON.parse(this.getAttribute('data-templ-args-__templ_functionName_sha')))" data-templ-args-__templ_functionName_sha="[&#34;some string&#34;,12345]">
+//
+// The parameters are JSON encoded, so time.Time values are passed as strings,
+// and ScriptArgMarshaler isn't used.
+func (c ComponentScript) WithDataArgs() ComponentScript {
+	data, err := marshalJSON(c.Params)
+	if err != nil || c.Params == nil {
+		data = []byte("[]")
+	}
+	c.DataArgs = string(data)
+	c.Call = c.Name + ".apply(this, JSON.parse(this.getAttribute('" + scriptDataArgsAttributeName(c.Name) + "')))"
+	return c
+}
+
+func scriptDataArgsAttributeName(name string) string {
+	return "data-templ-args-" + name
+}
+
+// ScriptDataArgsAttribute returns the data attribute that contains the parameters
+// of the script, if the script was created with WithDataArgs.
+func ScriptDataArgsAttribute(c ComponentScript) string {
+	if c.DataArgs == "" {
+		return ""
+	}
+	return " " + scriptDataArgsAttributeName(c.Name) + "=\"" + EscapeString(c.DataArgs) + "\""
 }
 
 var _ Component = ComponentScript{}
@@ -715,20 +908,88 @@ var bufferPool = sync.Pool{
 	},
 }
 
+// GetBuffer returns an empty buffer from a pool of buffers. Generated code renders
+// to a buffer from the pool, unless it's rendering to a *bytes.Buffer, so that
+// output is written to the io.Writer passed to Render in one call to Write.
 func GetBuffer() *bytes.Buffer {
 	return bufferPool.Get().(*bytes.Buffer)
 }
 
+// ReleaseBuffer resets the buffer, and returns it to the pool.
 func ReleaseBuffer(b *bytes.Buffer) {
 	b.Reset()
 	bufferPool.Put(b)
 }
 
-// JoinStringErrs joins an optional list of errors.
-func JoinStringErrs(s string, errs ...error) (string, error) {
+// JoinStringErrs converts the value of an expression to a string, and joins an
+// optional list of errors. Strings, integers, floats, booleans, fmt.Stringer and
+// error values are supported. Nil fmt.Stringer and error values are converted to
+// an empty string. Other values return an error.
+//
+// T isn't constrained to the supported types, because a constraint can't be a
+// union that contains interfaces with methods, e.g. fmt.Stringer. templ generate
+// uses JoinStringErrs[string], so that the type is checked when the code is
+// compiled, unless the -permissive-string-expressions flag is set.
+func JoinStringErrs[T any](v T, errs ...error) (s string, err error) {
+	switch x := any(v).(type) {
+	case string:
+		s = x
+	case int:
+		s = strconv.Itoa(x)
+	case int64:
+		s = strconv.FormatInt(x, 10)
+	case float64:
+		s = strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		s = strconv.FormatBool(x)
+	default:
+		if s, err = toString(any(v)); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	return s, errors.Join(errs...)
 }
 
+func toString(v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case fmt.Stringer:
+		if isNilPointer(rv) {
+			return "", nil
+		}
+		return v.String(), nil
+	case error:
+		if isNilPointer(rv) {
+			return "", nil
+		}
+		return v.Error(), nil
+	}
+	// Other numeric types, and types based on strings or numbers, e.g. type ID int.
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	}
+	return "", fmt.Errorf("templ: cannot render value of type %T as a string", v)
+}
+
+// isNilPointer returns true if v is a nil pointer, e.g. a nil *T stored in a
+// fmt.Stringer, whose methods may panic.
+func isNilPointer(v reflect.Value) bool {
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
 // Error returned during template rendering.
 type Error struct {
 	Err error
@@ -765,24 +1026,6 @@ func Raw[T ~string](html T, errs ...error) Component {
 	})
 }
 
-// FromGoHTML creates a templ Component from a Go html/template template.
-func FromGoHTML(t *template.Template, data any) Component {
-	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
-		return t.Execute(w, data)
-	})
-}
-
-// ToGoHTML renders the component to a Go html/template template.HTML string.
-func ToGoHTML(ctx context.Context, c Component) (s template.HTML, err error) {
-	b := GetBuffer()
-	defer ReleaseBuffer(b)
-	if err = c.Render(ctx, b); err != nil {
-		return
-	}
-	s = template.HTML(b.String())
-	return
-}
-
 // WriteWatchModeString is used when rendering templates in development mode.
 // the generator would have written non-go code to the _templ.txt file, which
 // is then read by this function and written to the output.
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	texttemplate "text/template"
+	"time"
 
 	"github.com/a-h/templ"
 	"github.com/google/go-cmp/cmp"
@@ -152,6 +154,14 @@ var cssInputs = []any{
 	}, // []KeyValue[ConstantCSSClass, bool]
 }
 
+type variant string
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
 type customClass struct {
 	name string
 }
@@ -218,6 +228,16 @@ func TestRenderCSS(t *testing.T) {
 			toRender: cssInputs,
 			expected: `<style type="text/css">.e{color:red}.j{color:red}</style>`,
 		},
+		{
+			name:     "CSS classes within enabled KV pairs and slices are rendered",
+			toIgnore: nil,
+			toRender: []any{
+				templ.KV(templ.Classes("a", c1), true),
+				templ.KV(templ.Classes(c2), false),
+				[]any{c2},
+			},
+			expected: `<style type="text/css">.c1{color:red}.c2{color:blue}</style>`,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -365,6 +385,75 @@ func TestClassesFunction(t *testing.T) {
 			},
 			expected: "a",
 		},
+		{
+			name: "KV pairs can contain other classes",
+			input: []any{
+				templ.KV(templ.Classes("a", "b"), true),
+				templ.KV([]string{"c", "d"}, false),
+				templ.KV(templ.Classes("e", templ.KV("f", false)), true),
+			},
+			expected: "a b e",
+		},
+		{
+			name: "disabled KV pairs disable the classes that they contain",
+			input: []any{
+				"a",
+				"b",
+				templ.KV(templ.Classes("a", templ.KV("b", true)), false),
+			},
+			expected: "",
+		},
+		{
+			name: "slices of any supported type are supported",
+			input: []any{
+				[]any{"a", templ.KV("b", true)},
+				[]templ.CSSClass{templ.SafeClass("c")},
+				[]templ.KeyValue[string, bool]{templ.KV("d", false)},
+			},
+			expected: "a b c",
+		},
+		{
+			name: "named string types and fmt.Stringers are supported",
+			input: []any{
+				variant("primary"),
+				stringer("large"),
+				templ.KV(variant("outline"), true),
+			},
+			expected: "primary large outline",
+		},
+		{
+			name: "maps of named string types to bool are rendered in sorted order",
+			input: []any{
+				map[variant]bool{"z": true, "y": false, "x": true},
+			},
+			expected: "x z",
+		},
+		{
+			name: "class names are deduplicated in the order they're first added",
+			input: []any{
+				"a b",
+				"c a",
+				[]string{"b", "d"},
+			},
+			expected: "a b c d",
+		},
+		{
+			name: "the last pair for a class name determines whether it's rendered",
+			input: []any{
+				templ.KV("a", false),
+				"b",
+				templ.KV("a", true),
+			},
+			expected: "a b",
+		},
+		{
+			name: "KV pairs without a bool value are rendered as unknown types",
+			input: []any{
+				templ.KV("a", "yes"),
+				"b",
+			},
+			expected: "--templ-css-class-unknown-type b",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -389,6 +478,10 @@ func TestHandler(t *testing.T) {
 		}
 		return errors.New("handler error")
 	})
+	slowComponent := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
 
 	tests := []struct {
 		name             string
@@ -425,6 +518,20 @@ func TestHandler(t *testing.T) {
 			expectedMIMEType: "text/csv",
 			expectedBody:     "Hello",
 		},
+		{
+			name:             "handlers use the content type of the component",
+			input:            templ.Handler(templ.ContentType("application/xml; charset=utf-8", hello)),
+			expectedStatus:   http.StatusOK,
+			expectedMIMEType: "application/xml; charset=utf-8",
+			expectedBody:     "Hello",
+		},
+		{
+			name:             "handler options override the content type of the component",
+			input:            templ.Handler(templ.ContentType("application/xml; charset=utf-8", hello), templ.WithContentType("application/rss+xml")),
+			expectedStatus:   http.StatusOK,
+			expectedMIMEType: "application/rss+xml",
+			expectedBody:     "Hello",
+		},
 		{
 			name:             "handlers that fail return a 500 error",
 			input:            templ.Handler(errorComponent),
@@ -448,6 +555,13 @@ func TestHandler(t *testing.T) {
 			expectedMIMEType: "text/html; charset=utf-8",
 			expectedBody:     "custom body",
 		},
+		{
+			name:             "handlers that time out return a 503 error",
+			input:            templ.Handler(slowComponent, templ.WithRenderTimeout(time.Millisecond)),
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedMIMEType: "text/plain; charset=utf-8",
+			expectedBody:     "templ: timed out rendering template\n",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -472,6 +586,199 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandlerClientDisconnect(t *testing.T) {
+	var errorHandlerCalled bool
+	h := templ.Handler(templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return ctx.Err()
+	}), templ.WithErrorHandler(func(r *http.Request, err error) http.Handler {
+		errorHandlerCalled = true
+		return http.NotFoundHandler()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil).WithContext(ctx))
+	if errorHandlerCalled {
+		t.Error("expected the error handler not to be called after the client disconnected")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected no response body, got %q", w.Body.String())
+	}
+}
+
+func TestHandlerETag(t *testing.T) {
+	var renders int
+	body := "Hello"
+	hello := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		renders++
+		_, err := io.WriteString(w, body)
+		return err
+	})
+	h := templ.Handler(hello)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+	etag := w.Result().Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	r := httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if got := w.Result().StatusCode; got != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, got)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected no body, got %q", w.Body.String())
+	}
+	if renders != 2 {
+		t.Errorf("expected the component to be rendered for each request, got %d renders", renders)
+	}
+
+	body = "Hello, World"
+	r = httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if got := w.Result().StatusCode; got != http.StatusOK {
+		t.Errorf("expected status %d after the output changed, got %d", http.StatusOK, got)
+	}
+	if got := w.Result().Header.Get("ETag"); got == etag || got == "" {
+		t.Errorf("expected a new ETag after the output changed, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	templ.Handler(hello, templ.WithStatus(http.StatusNotFound)).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+	if got := w.Result().Header.Get("ETag"); got != "" {
+		t.Errorf("expected no ETag for a 404 response, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	templ.Handler(hello, templ.WithStreaming()).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+	if got := w.Result().Header.Get("ETag"); got != "" {
+		t.Errorf("expected no ETag for a streamed response, got %q", got)
+	}
+}
+
+func TestHandlerCacheKeyETag(t *testing.T) {
+	var renders int
+	hello := templ.WithCacheKey("hello", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		renders++
+		_, err := io.WriteString(w, "Hello")
+		return err
+	}))
+	h := templ.Handler(hello, templ.WithCacheKeyETag())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+	etag := w.Result().Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		r := httptest.NewRequest("GET", "/test", nil)
+		r.Header.Set("If-None-Match", ifNoneMatch)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if got := w.Result().StatusCode; got != http.StatusNotModified {
+			t.Errorf("If-None-Match %s: expected status %d, got %d", ifNoneMatch, http.StatusNotModified, got)
+		}
+	}
+	if renders != 1 {
+		t.Errorf("expected 1 render, got %d", renders)
+	}
+
+	r := httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set("If-None-Match", `"other"`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if got := w.Result().StatusCode; got != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, got)
+	}
+
+	w = httptest.NewRecorder()
+	templ.Handler(hello, templ.WithStatus(http.StatusNotFound)).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+	if got := w.Result().Header.Get("ETag"); got != "" {
+		t.Errorf("expected no ETag for a 404 response, got %q", got)
+	}
+}
+
+func TestHandlerStreaming(t *testing.T) {
+	rows := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, row := range []string{"<p>1</p>", "<p>2</p>"} {
+			if _, err := io.WriteString(w, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	failing := func(output string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if output != "" {
+				if _, err := io.WriteString(w, output); err != nil {
+					return err
+				}
+			}
+			return errors.New("render error")
+		})
+	}
+
+	tests := []struct {
+		name            string
+		input           *templ.ComponentHandler
+		expectedStatus  int
+		expectedBody    string
+		expectedFlushed bool
+	}{
+		{
+			name:            "output is flushed as it's written",
+			input:           templ.Handler(rows, templ.WithStreaming(), templ.WithStatus(http.StatusAccepted)),
+			expectedStatus:  http.StatusAccepted,
+			expectedBody:    "<p>1</p><p>2</p>",
+			expectedFlushed: true,
+		},
+		{
+			name:           "errors before any output is written return a 500 error",
+			input:          templ.Handler(failing(""), templ.WithStreaming()),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "templ: failed to render template\n",
+		},
+		{
+			name:            "errors after output is written can't change the response",
+			input:           templ.Handler(failing("<p>1</p>"), templ.WithStreaming()),
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "<p>1</p>",
+			expectedFlushed: true,
+		},
+		{
+			name:           "components that don't write anything return the status",
+			input:          templ.Handler(templ.NopComponent, templ.WithStreaming(), templ.WithStatus(http.StatusNotFound)),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.input.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+			if got := w.Result().StatusCode; tt.expectedStatus != got {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, got)
+			}
+			if diff := cmp.Diff(tt.expectedBody, w.Body.String()); diff != "" {
+				t.Error(diff)
+			}
+			if w.Flushed != tt.expectedFlushed {
+				t.Errorf("expected flushed %v, got %v", tt.expectedFlushed, w.Flushed)
+			}
+		})
+	}
+}
+
 func TestRenderScriptItems(t *testing.T) {
 	s1 := templ.ComponentScript{
 		Name:     "s1",
@@ -551,6 +858,238 @@ func TestRenderScriptItems(t *testing.T) {
 	}
 }
 
+type point struct {
+	X, Y int
+}
+
+func (p point) MarshalScriptArg() (string, error) {
+	return fmt.Sprintf("new DOMPoint(%d, %d)", p.X, p.Y), nil
+}
+
+func TestRenderAttribute(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		value         string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "values are escaped",
+			key:      "data-name",
+			value:    `"a" & <b>`,
+			expected: ` data-name="&#34;a&#34; &amp; &lt;b&gt;"`,
+		},
+		{
+			name:     "URL values are sanitized",
+			key:      "HREF",
+			value:    "javascript:alert(1)",
+			expected: ` HREF="about:invalid#TemplFailedSanitizationURL"`,
+		},
+		{
+			name:          "names that would change the element are an error",
+			key:           `data-a="b" onclick`,
+			expectedError: true,
+		},
+		{
+			name:          "empty names are an error",
+			expectedError: true,
+		},
+		{
+			name:          "event handlers are an error",
+			key:           "onClick",
+			value:         "alert(1)",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb bytes.Buffer
+			err := templ.RenderAttribute(&sb, tt.key, tt.value)
+			if tt.expectedError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				if sb.Len() > 0 {
+					t.Errorf("expected nothing to be rendered, got %q", sb.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	actual := templ.MergeAttributes(
+		templ.Attributes{"id": "a", "hidden": true},
+		nil,
+		templ.Attributes{"id": "b", "data-count": 1},
+	)
+	expected := templ.Attributes{"id": "b", "hidden": true, "data-count": 1}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+type itemID int
+
+type itemName struct{ name string }
+
+func (n *itemName) String() string { return n.name }
+
+func TestJoinStringErrs(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         func() (string, error)
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "strings",
+			value:    func() (string, error) { return templ.JoinStringErrs("a") },
+			expected: "a",
+		},
+		{
+			name:     "integers",
+			value:    func() (string, error) { return templ.JoinStringErrs(-42) },
+			expected: "-42",
+		},
+		{
+			name:     "unsigned integers",
+			value:    func() (string, error) { return templ.JoinStringErrs(uint8(255)) },
+			expected: "255",
+		},
+		{
+			name:     "floats",
+			value:    func() (string, error) { return templ.JoinStringErrs(1.5) },
+			expected: "1.5",
+		},
+		{
+			name:     "booleans",
+			value:    func() (string, error) { return templ.JoinStringErrs(true) },
+			expected: "true",
+		},
+		{
+			name:     "types based on numbers",
+			value:    func() (string, error) { return templ.JoinStringErrs(itemID(7)) },
+			expected: "7",
+		},
+		{
+			name:     "stringers",
+			value:    func() (string, error) { return templ.JoinStringErrs(time.Second) },
+			expected: "1s",
+		},
+		{
+			name:     "errors",
+			value:    func() (string, error) { return templ.JoinStringErrs(errors.New("failed")) },
+			expected: "failed",
+		},
+		{
+			name:          "errors are joined",
+			value:         func() (string, error) { return templ.JoinStringErrs(1, errors.New("failed")) },
+			expected:      "1",
+			expectedError: true,
+		},
+		{
+			name:     "nil errors",
+			value:    func() (string, error) { return templ.JoinStringErrs(error(nil)) },
+			expected: "",
+		},
+		{
+			name:     "nil pointer stringers",
+			value:    func() (string, error) { return templ.JoinStringErrs((*itemName)(nil)) },
+			expected: "",
+		},
+		{
+			name: "nil pointer stringers in an interface",
+			value: func() (string, error) {
+				var s fmt.Stringer = (*itemName)(nil)
+				return templ.JoinStringErrs(s)
+			},
+			expected: "",
+		},
+		{
+			name:          "other types are an error",
+			value:         func() (string, error) { return templ.JoinStringErrs(struct{}{}) },
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.value()
+			if tt.expectedError != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSafeScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   []any
+		expected string
+	}{
+		{
+			name:     "values are JSON encoded",
+			params:   []any{"a", 1, struct{ Name string }{Name: "b"}},
+			expected: `fn(&#34;a&#34;,1,{&#34;Name&#34;:&#34;b&#34;})`,
+		},
+		{
+			name:     "times are passed as dates",
+			params:   []any{time.Date(2023, 11, 11, 1, 1, 40, 0, time.UTC)},
+			expected: `fn(new Date(&#34;2023-11-11T01:01:40Z&#34;))`,
+		},
+		{
+			name:     "script arg marshalers are used",
+			params:   []any{point{X: 1, Y: 2}},
+			expected: `fn(new DOMPoint(1, 2))`,
+		},
+		{
+			name:     "values that can't be encoded are null",
+			params:   []any{func() {}},
+			expected: `fn(null)`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, templ.SafeScript("fn", tt.params...)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestScriptWithDataArgs(t *testing.T) {
+	s := templ.ComponentScript{
+		Name:   "fn",
+		Call:   templ.SafeScript("fn", "a", 1),
+		Params: []any{"a", 1},
+	}
+	if actual := templ.ScriptDataArgsAttribute(s); actual != "" {
+		t.Errorf("expected no data attribute, got %q", actual)
+	}
+	s = s.WithDataArgs()
+	if diff := cmp.Diff(`fn.apply(this, JSON.parse(this.getAttribute('data-templ-args-fn')))`, s.Call); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(` data-templ-args-fn="[&#34;a&#34;,1]"`, templ.ScriptDataArgsAttribute(s)); diff != "" {
+		t.Error(diff)
+	}
+}
+
 type baseError struct {
 	Value int
 }
@@ -651,6 +1190,76 @@ func TestRawComponent(t *testing.T) {
 	})
 }
 
+func TestJSONScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		value       any
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "values are JSON encoded",
+			id:       "data",
+			value:    map[string]any{"name": "Alice", "count": 1},
+			expected: `<script id="data" type="application/json">{"count":1,"name":"Alice"}</script>`,
+		},
+		{
+			name:     "closing script tags in values are escaped",
+			id:       "data",
+			value:    "</script><script>alert(1)</script>",
+			expected: `<script id="data" type="application/json">"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"</script>`,
+		},
+		{
+			name:     "the id is HTML escaped",
+			id:       `"><script>`,
+			value:    1,
+			expected: `<script id="&#34;&gt;&lt;script&gt;" type="application/json">1</script>`,
+		},
+		{
+			name:     "the id is omitted if it's empty",
+			value:    1,
+			expected: `<script type="application/json">1</script>`,
+		},
+		{
+			name:        "values that cannot be encoded return an error",
+			id:          "data",
+			value:       func() {},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			err := templ.JSONScript(tt.id, tt.value).Render(context.Background(), b)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to render content: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, b.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("the type can be changed", func(t *testing.T) {
+		b := new(bytes.Buffer)
+		err := templ.JSONScript("", map[string]any{"@type": "Article"}).WithType("application/ld+json").Render(context.Background(), b)
+		if err != nil {
+			t.Fatalf("failed to render content: %v", err)
+		}
+		expected := `<script type="application/ld+json">{"@type":"Article"}</script>`
+		if diff := cmp.Diff(expected, b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
 var goTemplate = template.Must(template.New("example").Parse("<div>{{ . }}</div>"))
 
 func TestGoHTMLComponents(t *testing.T) {
@@ -725,3 +1334,97 @@ func TestGoHTMLComponents(t *testing.T) {
 		}
 	})
 }
+
+func TestGoTextTemplateComponents(t *testing.T) {
+	t.Run("Go text templates are escaped when they're rendered as templ components", func(t *testing.T) {
+		tt := texttemplate.Must(texttemplate.New("example").Parse("<div>{{ . }}</div>"))
+		b := new(bytes.Buffer)
+		if err := templ.FromGoTemplate(tt, "Test &").Render(context.Background(), b); err != nil {
+			t.Fatalf("failed to render content: %v", err)
+		}
+		if diff := cmp.Diff("&lt;div&gt;Test &amp;&lt;/div&gt;", b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("Go html templates aren't escaped again when they're rendered as templ components", func(t *testing.T) {
+		b := new(bytes.Buffer)
+		if err := templ.FromGoTemplate(goTemplate, "Test &").Render(context.Background(), b); err != nil {
+			t.Fatalf("failed to render content: %v", err)
+		}
+		if diff := cmp.Diff("<div>Test &amp;</div>", b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("templ components can be rendered with a Go template function", func(t *testing.T) {
+		page := template.Must(template.New("page").Funcs(template.FuncMap{
+			"templ": templ.ToGoTemplateFunc(context.Background()),
+		}).Parse("<main>{{ templ .Header }}{{ .Title }}</main>"))
+		header := templ.Raw("<h1>Header</h1>")
+		b := new(bytes.Buffer)
+		err := page.Execute(b, struct {
+			Header templ.Component
+			Title  string
+		}{Header: header, Title: "<Title>"})
+		if err != nil {
+			t.Fatalf("failed to render content: %v", err)
+		}
+		if diff := cmp.Diff("<main><h1>Header</h1>&lt;Title&gt;</main>", b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("errors in the Go template function are returned", func(t *testing.T) {
+		page := template.Must(template.New("page").Funcs(template.FuncMap{
+			"templ": templ.ToGoTemplateFunc(context.Background()),
+		}).Parse("{{ templ . }}"))
+		err := page.Execute(new(bytes.Buffer), templ.Raw("", errors.New("test error")))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+	t.Run("a component can be added to a Go template as a function", func(t *testing.T) {
+		header := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			_, err = io.WriteString(w, "<h1>Header</h1>")
+			return err
+		})
+		page := template.Must(template.New("page").Funcs(template.FuncMap{
+			"header": header.ToGoTemplateFunc(),
+		}).Parse("<main>{{ header }}{{ . }}</main>"))
+		b := new(bytes.Buffer)
+		if err := page.Execute(b, "<Title>"); err != nil {
+			t.Fatalf("failed to render content: %v", err)
+		}
+		if diff := cmp.Diff("<main><h1>Header</h1>&lt;Title&gt;</main>", b.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestBlocks(t *testing.T) {
+	text := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+	ctx := templ.InitializeContext(context.Background())
+	ctx = templ.WithBlocks(ctx, map[string]templ.Component{"content": text("page")})
+	ctx = templ.WithBlocks(ctx, map[string]templ.Component{"content": text("layout"), "header": text("header")})
+
+	blocks := templ.GetBlocks(ctx)
+	var actual []string
+	for _, name := range []string{"header", "content"} {
+		var sb bytes.Buffer
+		if err := blocks[name].Render(ctx, &sb); err != nil {
+			t.Fatalf("failed to render block %q: %v", name, err)
+		}
+		actual = append(actual, sb.String())
+	}
+	if diff := cmp.Diff([]string{"header", "page"}, actual); diff != "" {
+		t.Error(diff)
+	}
+
+	ctx = templ.ClearBlocks(ctx)
+	if blocks := templ.GetBlocks(ctx); blocks != nil {
+		t.Errorf("expected no blocks after clearing, got %v", blocks)
+	}
+}
@@ -0,0 +1,228 @@
+//go:build !tinygo
+
+package templ
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// StandaloneOptions configures RenderStandalone.
+type StandaloneOptions struct {
+	// Assets is the file system that local stylesheets, scripts and images
+	// are read from, e.g. an embed.FS. If it's nil, only the CSS of css
+	// components is inlined.
+	Assets fs.FS
+	// BasePath is the URL path that the assets are served from, e.g. "/static/".
+	// It's removed from the URLs of assets to find them in Assets. If it's empty,
+	// the base path of the manifest set by SetAssetManifest is used.
+	BasePath string
+}
+
+// RenderStandalone renders the component as a self-contained HTML document that
+// doesn't make any requests when it's loaded, e.g. to convert it to a PDF with
+// wkhtmltopdf or chromedp. The CSS of css components is inlined in the <head>,
+// local stylesheets and scripts are inlined, and the src attributes of images
+// and other media are replaced with data URIs. Remote URLs are left as they are.
+//
+// An error is returned if a local asset can't be read from opts.Assets.
+func RenderStandalone(ctx context.Context, w io.Writer, c Component, opts StandaloneOptions) (err error) {
+	buf := GetBuffer()
+	defer ReleaseBuffer(buf)
+	if err = CriticalCSS(c).Render(ctx, buf); err != nil {
+		return err
+	}
+	if opts.Assets == nil {
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	if opts.BasePath == "" {
+		if m := assetManifest.Load(); m != nil {
+			opts.BasePath = m.BasePath
+		}
+	}
+	s := standalone{opts: opts, w: w}
+	return s.write(buf.Bytes())
+}
+
+type standalone struct {
+	opts StandaloneOptions
+	w    io.Writer
+	// inlinedScript is true while the contents of a script that has been
+	// inlined are skipped.
+	inlinedScript bool
+}
+
+func (s *standalone) write(doc []byte) (err error) {
+	z := html.NewTokenizer(bytes.NewReader(doc))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			raw := string(z.Raw())
+			t := z.Token()
+			replaced, err := s.replace(t)
+			if err != nil {
+				return err
+			}
+			if replaced == "" {
+				replaced = raw
+			}
+			if _, err = io.WriteString(s.w, replaced); err != nil {
+				return err
+			}
+		case html.TextToken:
+			if s.inlinedScript {
+				continue
+			}
+			if _, err = s.w.Write(z.Raw()); err != nil {
+				return err
+			}
+		default:
+			s.inlinedScript = false
+			if _, err = s.w.Write(z.Raw()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replace returns the HTML that replaces the tag, or an empty string if the
+// tag is written as it is.
+func (s *standalone) replace(t html.Token) (replaced string, err error) {
+	switch t.Data {
+	case "link":
+		href, ok := s.localURL(getAttr(t, "href"))
+		if !ok || !strings.EqualFold(getAttr(t, "rel"), "stylesheet") {
+			break
+		}
+		css, err := s.read(href)
+		if err != nil {
+			return "", err
+		}
+		return `<style type="text/css">` + strings.ReplaceAll(string(css), "</style", `<\/style`) + `</style>`, nil
+	case "script":
+		src, ok := s.localURL(getAttr(t, "src"))
+		if !ok {
+			break
+		}
+		js, err := s.read(src)
+		if err != nil {
+			return "", err
+		}
+		t.Attr = removeAttr(t.Attr, "src")
+		s.inlinedScript = t.Type == html.StartTagToken
+		t.Type = html.StartTagToken
+		replaced = t.String() + strings.ReplaceAll(string(js), "</script", `<\/script`)
+		if !s.inlinedScript {
+			replaced += "</script>"
+		}
+		return replaced, nil
+	}
+	var changed bool
+	for i, a := range t.Attr {
+		if a.Namespace != "" || (a.Key != "src" && a.Key != "poster") {
+			continue
+		}
+		name, ok := s.localURL(a.Val)
+		if !ok {
+			continue
+		}
+		data, err := s.read(name)
+		if err != nil {
+			return "", err
+		}
+		t.Attr[i].Val = dataURI(name, data)
+		changed = true
+	}
+	if changed {
+		return t.String(), nil
+	}
+	return "", nil
+}
+
+// localURL returns the name of the asset in the file system, if the URL is a
+// local URL.
+func (s *standalone) localURL(u string) (name string, ok bool) {
+	if u == "" || strings.HasPrefix(u, "//") || strings.HasPrefix(u, "#") {
+		return "", false
+	}
+	if i := strings.IndexAny(u, ":/?#"); i >= 0 && u[i] == ':' {
+		// The URL has a scheme, e.g. https: or data:.
+		return "", false
+	}
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	if s.opts.BasePath != "" {
+		u = strings.TrimPrefix(u, strings.TrimSuffix(s.opts.BasePath, "/")+"/")
+	}
+	return strings.TrimPrefix(path.Clean("/"+u), "/"), true
+}
+
+func (s *standalone) read(name string) ([]byte, error) {
+	data, err := fs.ReadFile(s.opts.Assets, name)
+	if err != nil {
+		return nil, fmt.Errorf("templ: failed to read asset %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// mediaTypes of common assets. The system MIME types aren't used, so that the
+// output is the same on every machine.
+var mediaTypes = map[string]string{
+	".avif":  "image/avif",
+	".gif":   "image/gif",
+	".ico":   "image/x-icon",
+	".jpeg":  "image/jpeg",
+	".jpg":   "image/jpeg",
+	".mp3":   "audio/mpeg",
+	".mp4":   "video/mp4",
+	".png":   "image/png",
+	".svg":   "image/svg+xml",
+	".webm":  "video/webm",
+	".webp":  "image/webp",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+}
+
+func dataURI(name string, data []byte) string {
+	mediaType, ok := mediaTypes[strings.ToLower(path.Ext(name))]
+	if !ok {
+		mediaType = http.DetectContentType(data)
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func getAttr(t html.Token, key string) string {
+	for _, a := range t.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func removeAttr(attrs []html.Attribute, key string) []html.Attribute {
+	kept := make([]html.Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Namespace != "" || a.Key != key {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,81 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderStandalone(t *testing.T) {
+	assets := fstest.MapFS{
+		"app.css":  {Data: []byte("p{color:red}")},
+		"app.js":   {Data: []byte(`console.log("</script>")`)},
+		"logo.png": {Data: []byte("png")},
+	}
+	tests := []struct {
+		name          string
+		input         string
+		opts          templ.StandaloneOptions
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "stylesheets are inlined",
+			input:    `<head><link rel="stylesheet" href="/static/app.css?v=1"></head>`,
+			opts:     templ.StandaloneOptions{Assets: assets, BasePath: "/static/"},
+			expected: `<head><style type="text/css">p{color:red}</style></head>`,
+		},
+		{
+			name:     "scripts are inlined",
+			input:    `<script type="module" src="/static/app.js"></script>`,
+			opts:     templ.StandaloneOptions{Assets: assets, BasePath: "/static/"},
+			expected: `<script type="module">console.log("<\/script>")</script>`,
+		},
+		{
+			name:     "images are data URIs",
+			input:    `<img alt="Logo" src="logo.png"/>`,
+			opts:     templ.StandaloneOptions{Assets: assets},
+			expected: `<img alt="Logo" src="data:image/png;base64,cG5n"/>`,
+		},
+		{
+			name:     "remote URLs are kept",
+			input:    `<link rel="stylesheet" href="https://example.com/app.css"><img src="data:image/png;base64,AA==">`,
+			opts:     templ.StandaloneOptions{Assets: assets, BasePath: "/static/"},
+			expected: `<link rel="stylesheet" href="https://example.com/app.css"><img src="data:image/png;base64,AA==">`,
+		},
+		{
+			name:     "assets aren't inlined without a file system",
+			input:    `<script src="/static/app.js"></script>`,
+			expected: `<script src="/static/app.js"></script>`,
+		},
+		{
+			name:          "missing assets are an error",
+			input:         `<img src="/static/missing.png">`,
+			opts:          templ.StandaloneOptions{Assets: assets, BasePath: "/static/"},
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			err := templ.RenderStandalone(context.Background(), &sb, templ.Raw(tt.input), tt.opts)
+			if tt.expectedError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package stimulus provides typed builders for Stimulus attributes.
+//
+// Attribute values are HTML escaped when they're rendered, and the characters
+// that can't be used in attribute names are removed from identifiers, so that
+// they can't add other attributes to the element.
+package stimulus
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// Attributes merges the attributes into a single set of attributes, so that they
+// can be spread onto an element.
+func Attributes(attrs ...templ.Attributes) templ.Attributes {
+	return templ.MergeAttributes(attrs...)
+}
+
+// Controller connects the controllers to the element.
+func Controller(identifiers ...string) templ.Attributes {
+	ids := make([]string, len(identifiers))
+	for i, id := range identifiers {
+		ids[i] = identifier(id)
+	}
+	return templ.Attributes{"data-controller": strings.Join(ids, " ")}
+}
+
+// ActionDescriptor describes an event that calls a method of a controller.
+type ActionDescriptor struct {
+	// Event name, e.g. click. If empty, the default event of the element is used.
+	Event string
+	// Controller identifier, e.g. hello.
+	Controller string
+	// Method of the controller, e.g. greet.
+	Method string
+}
+
+// String returns the descriptor, e.g. click->hello#greet. The characters that
+// can't be used in event names, identifiers and method names are removed, so
+// that the descriptor can't add other actions.
+func (a ActionDescriptor) String() string {
+	var sb strings.Builder
+	if event := eventName(a.Event); event != "" {
+		sb.WriteString(event + "->")
+	}
+	sb.WriteString(identifier(a.Controller) + "#" + methodName(a.Method))
+	return sb.String()
+}
+
+// On returns an action that calls the method of the controller when the event is dispatched.
+func On(event, controller, method string) ActionDescriptor {
+	return ActionDescriptor{Event: event, Controller: controller, Method: method}
+}
+
+// Action connects the actions to the element.
+func Action(actions ...ActionDescriptor) templ.Attributes {
+	descriptors := make([]string, len(actions))
+	for i, a := range actions {
+		descriptors[i] = a.String()
+	}
+	return templ.Attributes{"data-action": strings.Join(descriptors, " ")}
+}
+
+// Target names the element as a target of the controller.
+func Target(controller, name string) templ.Attributes {
+	return templ.Attributes{"data-" + identifier(controller) + "-target": name}
+}
+
+// Value sets a value of the controller. Strings are used as is, and other
+// values are JSON encoded.
+func Value(controller, name string, value any) (templ.Attributes, error) {
+	return encodedAttribute("data-"+identifier(controller)+"-"+identifier(name)+"-value", value)
+}
+
+// Param sets a parameter passed to the actions of the controller.
+func Param(controller, name string, value any) (templ.Attributes, error) {
+	return encodedAttribute("data-"+identifier(controller)+"-"+identifier(name)+"-param", value)
+}
+
+// Class sets the CSS classes of a logical class name of the controller.
+func Class(controller, name string, classes ...string) templ.Attributes {
+	return templ.Attributes{"data-" + identifier(controller) + "-" + identifier(name) + "-class": strings.Join(classes, " ")}
+}
+
+func encodedAttribute(name string, value any) (templ.Attributes, error) {
+	if s, ok := value.(string); ok {
+		return templ.Attributes{name: s}, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return templ.Attributes{name: string(data)}, nil
+}
+
+// eventName removes the characters that can't be used in the event of an action,
+// keeping the filters and targets of events, e.g. keydown.enter and resize@window.
+func eventName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ':', '@', '+':
+			return r
+		}
+		return identifierRune(r)
+	}, s)
+}
+
+// methodName removes the characters that can't be used in JavaScript method names.
+func methodName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '$' {
+			return r
+		}
+		if r == '-' {
+			return -1
+		}
+		return identifierRune(r)
+	}, s)
+}
+
+// identifier removes the characters that can't be used in attribute names.
+func identifier(s string) string {
+	return strings.Map(identifierRune, s)
+}
+
+func identifierRune(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return r
+	case r == '-' || r == '_':
+		return r
+	}
+	return -1
+}
@@ -0,0 +1,42 @@
+package stimulus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAttributes(t *testing.T) {
+	count, err := Value("counter", "count", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attrs := Attributes(
+		Controller("counter", "tooltip"),
+		Action(On("click", "counter", "increment"), On("", "tooltip", "show"), On("keydown.enter@window", "counter", "reset")),
+		Target(`counter" onclick="alert(1)`, "output"),
+		count,
+	)
+	w := new(strings.Builder)
+	if err = templ.RenderAttributes(context.Background(), w, attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ` data-action="click-&gt;counter#increment tooltip#show keydown.enter@window-&gt;counter#reset"` +
+		` data-controller="counter tooltip"` +
+		` data-counter-count-value="3"` +
+		` data-counteronclickalert1-target="output"`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestActionDescriptor(t *testing.T) {
+	// Whitespace, -> and # would add other actions to the data-action attribute.
+	actual := On("click->other#method click", "counter", "increment other#method").String()
+	if diff := cmp.Diff("click-othermethodclick->counter#incrementothermethod", actual); diff != "" {
+		t.Error(diff)
+	}
+}
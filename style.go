@@ -0,0 +1,113 @@
+package templ
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/a-h/templ/safehtml"
+)
+
+// StyleProperty is a property of an inline style attribute, e.g. width:100px.
+type StyleProperty struct {
+	Name  string
+	Value any
+}
+
+// Style returns a property of an inline style attribute. String values are
+// sanitized. SafeCSSProperty values, e.g. the lengths returned by Px and
+// Percent, aren't. Numbers and fmt.Stringer values are converted to strings.
+func Style(name string, value any) StyleProperty {
+	return StyleProperty{Name: name, Value: value}
+}
+
+// Styles returns the value of an inline style attribute, e.g.
+//
+//	<div style={ templ.Styles(templ.Style("width", templ.Px(w)), map[string]string{"color": c}) }>
+//
+// Supported types are StyleProperty, []StyleProperty, map[string]string and
+// map[string]any, and SafeCSS, e.g. the result of another call to Styles. The
+// properties of maps are written in sorted order. Properties with unsafe names,
+// values or types are replaced with a harmless property.
+func Styles(properties ...any) SafeCSS {
+	var sb strings.Builder
+	for _, p := range properties {
+		writeStyle(&sb, p)
+	}
+	return SafeCSS(sb.String())
+}
+
+func writeStyle(sb *strings.Builder, item any) {
+	switch p := item.(type) {
+	case StyleProperty:
+		sb.WriteString(string(sanitizeStyleProperty(p.Name, p.Value)))
+	case []StyleProperty:
+		for _, sp := range p {
+			writeStyle(sb, sp)
+		}
+	case map[string]string:
+		for _, name := range sortedStyleNames(p) {
+			writeStyle(sb, Style(name, p[name]))
+		}
+	case map[string]any:
+		for _, name := range sortedKeys(p) {
+			writeStyle(sb, Style(name, p[name]))
+		}
+	case SafeCSS:
+		sb.WriteString(string(p))
+	default:
+		sb.WriteString(safehtml.InnocuousPropertyName + ":" + safehtml.InnocuousPropertyValue + ";")
+	}
+}
+
+func sanitizeStyleProperty(name string, value any) SafeCSS {
+	if v, ok := value.(SafeCSSProperty); ok {
+		return SanitizeCSS(name, v)
+	}
+	s, err := toString(value)
+	if err != nil {
+		return SafeCSS(safehtml.SanitizeCSSProperty(name) + ":" + safehtml.InnocuousPropertyValue + ";")
+	}
+	return SanitizeCSS(name, s)
+}
+
+func sortedStyleNames(m map[string]string) (names []string) {
+	names = make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cssNumber is a number that can be used as a CSS length.
+type cssNumber interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Px returns a length in pixels, e.g. 10px.
+func Px[T cssNumber](v T) SafeCSSProperty {
+	return cssLength(v, "px")
+}
+
+// Percent returns a percentage, e.g. 50%.
+func Percent[T cssNumber](v T) SafeCSSProperty {
+	return cssLength(v, "%")
+}
+
+// Em returns a length relative to the font size of the element, e.g. 1.5em.
+func Em[T cssNumber](v T) SafeCSSProperty {
+	return cssLength(v, "em")
+}
+
+// Rem returns a length relative to the font size of the root element, e.g. 2rem.
+func Rem[T cssNumber](v T) SafeCSSProperty {
+	return cssLength(v, "rem")
+}
+
+func cssLength(v any, unit string) SafeCSSProperty {
+	// Numbers are always converted.
+	s, _ := toString(v)
+	return SafeCSSProperty(s + unit)
+}
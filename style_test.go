@@ -0,0 +1,83 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+type color string
+
+func TestStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []any
+		expected templ.SafeCSS
+	}{
+		{
+			name:     "properties are written in order",
+			input:    []any{templ.Style("width", "100%"), templ.Style("color", "red")},
+			expected: "width:100%;color:red;",
+		},
+		{
+			name:     "unit helpers are written without sanitization",
+			input:    []any{templ.Style("width", templ.Px(10)), templ.Style("height", templ.Percent(50.5)), templ.Style("margin", templ.Em(1.5)), templ.Style("padding", templ.Rem(2))},
+			expected: "width:10px;height:50.5%;margin:1.5em;padding:2rem;",
+		},
+		{
+			name:     "numbers, named string types and fmt.Stringers are converted",
+			input:    []any{templ.Style("opacity", 0.5), templ.Style("color", color("blue")), templ.Style("z-index", stringer("10"))},
+			expected: "opacity:0.5;color:blue;z-index:10;",
+		},
+		{
+			name:     "the properties of maps are written in sorted order",
+			input:    []any{map[string]string{"width": "10px", "color": "red"}, map[string]any{"top": templ.Px(1), "left": 2}},
+			expected: "color:red;width:10px;left:2;top:1px;",
+		},
+		{
+			name:     "slices of properties and the results of Styles are supported",
+			input:    []any{[]templ.StyleProperty{templ.Style("width", "1px")}, templ.Styles(templ.Style("height", "2px"))},
+			expected: "width:1px;height:2px;",
+		},
+		{
+			name:     "property names are sanitized",
+			input:    []any{templ.Style("WIDTH", "1px"), templ.Style("width;color", "red")},
+			expected: "width:1px;zTemplUnsafeCSSPropertyName:zTemplUnsafeCSSPropertyValue;",
+		},
+		{
+			name:     "unsafe values are replaced",
+			input:    []any{templ.Style("background", "url(javascript:alert(1))"), templ.Style("color", "red;</style>")},
+			expected: "background:zTemplUnsafeCSSPropertyValue;color:zTemplUnsafeCSSPropertyValue;",
+		},
+		{
+			name:     "unsupported types are replaced",
+			input:    []any{templ.Style("width", struct{}{}), 123},
+			expected: "width:zTemplUnsafeCSSPropertyValue;zTemplUnsafeCSSPropertyName:zTemplUnsafeCSSPropertyValue;",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := templ.Styles(tt.input...)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestStylesAttribute(t *testing.T) {
+	// The value of the attribute is escaped when it's rendered.
+	var sb strings.Builder
+	attrs := templ.Attributes{"style": templ.Styles(templ.Style("font-family", `"Open Sans"`))}
+	if err := templ.RenderAttributes(context.Background(), &sb, attrs); err != nil {
+		t.Fatalf("failed to render attributes: %v", err)
+	}
+	expected := ` style="font-family:&#34;Open Sans&#34;;"`
+	if diff := cmp.Diff(expected, sb.String()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,95 @@
+package templ
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/a-h/templ/safehtml"
+)
+
+// ThemeToken is the name of a CSS custom property in a Theme, e.g. "color-primary".
+//
+// Declare tokens as constants, so that css components reference them type-safely:
+//
+//	const ColorPrimary templ.ThemeToken = "color-primary"
+//
+//	css button() {
+//		background-color: { ColorPrimary.Var() };
+//	}
+type ThemeToken string
+
+// Var returns a reference to the custom property of the token, e.g. var(--color-primary).
+// If the token isn't a valid custom property name, an innocuous value is returned.
+func (t ThemeToken) Var() SafeCSSProperty {
+	if !themeTokenPattern.MatchString(string(t)) {
+		return SafeCSSProperty(safehtml.InnocuousPropertyValue)
+	}
+	return SafeCSSProperty("var(--" + string(t) + ")")
+}
+
+// Theme maps tokens to their values. It's rendered as CSS custom properties,
+// e.g. :root{--color-primary:#0000ff;}.
+type Theme map[ThemeToken]string
+
+const themeContextKey = contextKeyType(2)
+
+// WithTheme returns a context that overrides the values of the tokens in the
+// rendered theme, e.g. to switch to a dark theme for the request.
+func WithTheme(ctx context.Context, overrides Theme) context.Context {
+	merged := Theme{}
+	for token, value := range getThemeOverrides(ctx) {
+		merged[token] = value
+	}
+	for token, value := range overrides {
+		merged[token] = value
+	}
+	return context.WithValue(ctx, themeContextKey, merged)
+}
+
+func getThemeOverrides(ctx context.Context) Theme {
+	t, _ := ctx.Value(themeContextKey).(Theme)
+	return t
+}
+
+// themeTokenPattern matches the names of custom properties.
+var themeTokenPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Render the theme as a <style> element that sets the custom properties on the
+// :root element. Overrides set with WithTheme take precedence. Only the first
+// Theme rendered with a context is written, so other themes rendered later in
+// the request, e.g. by nested layouts, are skipped rather than merged. Tokens
+// with invalid names are skipped, and unsafe values are replaced.
+func (t Theme) Render(ctx context.Context, w io.Writer) (err error) {
+	_, v := getContext(ctx)
+	if v.hasClassBeenRendered("templ_theme") {
+		return nil
+	}
+	v.addClass("templ_theme")
+
+	values := Theme{}
+	for token, value := range t {
+		values[token] = value
+	}
+	for token, value := range getThemeOverrides(ctx) {
+		values[token] = value
+	}
+	tokens := make([]string, 0, len(values))
+	for token := range values {
+		if themeTokenPattern.MatchString(string(token)) {
+			tokens = append(tokens, string(token))
+		}
+	}
+	sort.Strings(tokens)
+
+	var sb strings.Builder
+	sb.WriteString(`<style type="text/css">:root{`)
+	for _, token := range tokens {
+		sb.WriteString("--" + token + ":" + safehtml.SanitizeCSSValue("--"+token, values[ThemeToken(token)]) + ";")
+	}
+	sb.WriteString(`}</style>`)
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,85 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	colorPrimary    templ.ThemeToken = "color-primary"
+	colorBackground templ.ThemeToken = "color-background"
+)
+
+var defaultTheme = templ.Theme{
+	colorPrimary:    "#0000ff",
+	colorBackground: "#ffffff",
+}
+
+func TestTheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		theme    templ.Theme
+		expected string
+	}{
+		{
+			name:     "tokens are rendered as custom properties",
+			ctx:      context.Background(),
+			theme:    defaultTheme,
+			expected: `<style type="text/css">:root{--color-background:#ffffff;--color-primary:#0000ff;}</style>`,
+		},
+		{
+			name:     "overrides in the context take precedence",
+			ctx:      templ.WithTheme(context.Background(), templ.Theme{colorBackground: "#000000"}),
+			theme:    defaultTheme,
+			expected: `<style type="text/css">:root{--color-background:#000000;--color-primary:#0000ff;}</style>`,
+		},
+		{
+			name:     "invalid tokens are skipped, and unsafe values are replaced",
+			ctx:      context.Background(),
+			theme:    templ.Theme{"a}b": "red", "c": "red;}</style>"},
+			expected: `<style type="text/css">:root{--c:zTemplUnsafeCSSPropertyValue;}</style>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := tt.theme.Render(tt.ctx, w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("the theme is only rendered once", func(t *testing.T) {
+		ctx := templ.InitializeContext(context.Background())
+		w := new(strings.Builder)
+		for i := 0; i < 2; i++ {
+			if err := defaultTheme.Render(ctx, w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if n := strings.Count(w.String(), "<style"); n != 1 {
+			t.Errorf("expected the theme to be rendered once, got %d", n)
+		}
+	})
+}
+
+func TestThemeTokenVar(t *testing.T) {
+	actual := templ.SanitizeCSS("color", colorPrimary.Var())
+	if diff := cmp.Diff(templ.SafeCSS("color:var(--color-primary);"), actual); diff != "" {
+		t.Error(diff)
+	}
+	t.Run("invalid tokens are replaced with an innocuous value", func(t *testing.T) {
+		actual := templ.SanitizeCSS("color", templ.ThemeToken("x);background:url(evil)").Var())
+		if diff := cmp.Diff(templ.SafeCSS("color:zTemplUnsafeCSSPropertyValue;"), actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
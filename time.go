@@ -0,0 +1,131 @@
+package templ
+
+import (
+	"context"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+const timeLocationContextKey = contextKeyType(7)
+
+// WithTimeLocation returns a context that contains the time zone that Time
+// formats times in, e.g. the time zone of the user.
+func WithTimeLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timeLocationContextKey, loc)
+}
+
+// GetTimeLocation returns the time zone of the context, or nil if the context
+// doesn't contain one.
+func GetTimeLocation(ctx context.Context) *time.Location {
+	loc, _ := ctx.Value(timeLocationContextKey).(*time.Location)
+	return loc
+}
+
+// TimeOption changes how Time renders a time.
+type TimeOption func(o *timeOptions)
+
+type timeOptions struct {
+	relative bool
+	now      time.Time
+}
+
+// RelativeTime renders the time relative to now, e.g. 3 hours ago, with the
+// time formatted with the layout as the title of the element. A script updates
+// the relative times in the browser every minute.
+func RelativeTime(now time.Time) TimeOption {
+	return func(o *timeOptions) {
+		o.relative = true
+		o.now = now
+	}
+}
+
+// relativeTimeRuntime is the script that updates the text of the <time>
+// elements with data-templ-relative attributes every minute. The text is
+// calculated in the same way as relativeTime.
+const relativeTimeRuntime = `(function(){` +
+	`if(window.templ_relative_time)return;window.templ_relative_time=true;` +
+	`var u=[["year",31536e3],["month",2592e3],["day",86400],["hour",3600],["minute",60]];` +
+	`function f(d){` +
+	`var s=Math.round((Date.now()-Date.parse(d))/1e3),a=Math.abs(s);` +
+	`if(a<45)return "just now";` +
+	`for(var i=0;i<u.length;i++){` +
+	`if(a>=u[i][1]||i==u.length-1){` +
+	`var n=Math.max(1,Math.floor(a/u[i][1])),t=n+" "+u[i][0]+(n>1?"s":"");` +
+	`return s>0?t+" ago":"in "+t;}}}` +
+	`function r(){document.querySelectorAll("time[data-templ-relative]").forEach(function(e){` +
+	`var d=e.getAttribute("datetime");if(!isNaN(Date.parse(d)))e.textContent=f(d);});}` +
+	`document.addEventListener("DOMContentLoaded",r);setInterval(r,6e4);` +
+	`})();`
+
+// Time renders a <time> element, with the time in ISO 8601 format in the
+// datetime attribute, and formatted with the layout as its text, e.g.
+// templ.Time(post.Created, "2 Jan 2006"). The time is formatted in the time
+// zone of the context, see WithTimeLocation. Zero times aren't rendered.
+func Time(t time.Time, layout string, opts ...TimeOption) Component {
+	var o timeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if t.IsZero() {
+			return nil
+		}
+		if loc := GetTimeLocation(ctx); loc != nil {
+			t = t.In(loc)
+		}
+		datetime := t.Format(time.RFC3339)
+		formatted := t.Format(layout)
+		if !o.relative {
+			return writeStrings(w, `<time datetime="`, EscapeString(datetime), `">`, EscapeString(formatted), `</time>`)
+		}
+		if err = writeStrings(w, `<time datetime="`, EscapeString(datetime), `" title="`, EscapeString(formatted), `" data-templ-relative>`, EscapeString(relativeTime(t, o.now)), `</time>`); err != nil {
+			return err
+		}
+		_, v := getContext(ctx)
+		if v.hasScriptBeenRendered("templ_relative_time") {
+			return nil
+		}
+		v.addScript("templ_relative_time")
+		return writeStrings(w, `<script type="text/javascript">`, relativeTimeRuntime, `</script>`)
+	})
+}
+
+var relativeTimeUnits = []struct {
+	name    string
+	seconds int64
+}{
+	{"year", 365 * 24 * 60 * 60},
+	{"month", 30 * 24 * 60 * 60},
+	{"day", 24 * 60 * 60},
+	{"hour", 60 * 60},
+	{"minute", 60},
+}
+
+// relativeTime returns the time relative to now, e.g. 3 hours ago, or in 2 days.
+func relativeTime(t, now time.Time) string {
+	seconds := int64(math.Round(now.Sub(t).Seconds()))
+	abs := seconds
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs < 45 {
+		return "just now"
+	}
+	for i, unit := range relativeTimeUnits {
+		if abs < unit.seconds && i < len(relativeTimeUnits)-1 {
+			continue
+		}
+		n := max(1, abs/unit.seconds)
+		s := strconv.FormatInt(n, 10) + " " + unit.name
+		if n > 1 {
+			s += "s"
+		}
+		if seconds > 0 {
+			return s + " ago"
+		}
+		return "in " + s
+	}
+	return ""
+}
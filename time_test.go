@@ -0,0 +1,110 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTime(t *testing.T) {
+	created := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		component templ.Component
+		expected  string
+	}{
+		{
+			name:      "the time is rendered with a machine readable datetime",
+			ctx:       context.Background(),
+			component: templ.Time(created, "2 Jan 2006 15:04"),
+			expected:  `<time datetime="2024-03-05T14:30:00Z">5 Mar 2024 14:30</time>`,
+		},
+		{
+			name:      "the time is formatted in the time zone of the context",
+			ctx:       templ.WithTimeLocation(context.Background(), newYork),
+			component: templ.Time(created, "2 Jan 2006 15:04 MST"),
+			expected:  `<time datetime="2024-03-05T09:30:00-05:00">5 Mar 2024 09:30 EST</time>`,
+		},
+		{
+			name:      "zero times aren't rendered",
+			ctx:       context.Background(),
+			component: templ.Time(time.Time{}, "2 Jan 2006"),
+			expected:  ``,
+		},
+		{
+			name:      "the layout is escaped",
+			ctx:       context.Background(),
+			component: templ.Time(created, "<b>2006</b>"),
+			expected:  `<time datetime="2024-03-05T14:30:00Z">&lt;b&gt;2024&lt;/b&gt;</time>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.component.Render(tt.ctx, &sb); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		time     time.Time
+		expected string
+	}{
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-50 * time.Second), "1 minute ago"},
+		{now.Add(-3 * time.Minute), "3 minutes ago"},
+		{now.Add(-3 * time.Hour), "3 hours ago"},
+		{now.Add(-36 * time.Hour), "1 day ago"},
+		{now.Add(-60 * 24 * time.Hour), "2 months ago"},
+		{now.Add(-800 * 24 * time.Hour), "2 years ago"},
+		{now.Add(2 * time.Hour), "in 2 hours"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expected, func(t *testing.T) {
+			var sb strings.Builder
+			if err := templ.Time(tt.time, "2006", templ.RelativeTime(now)).Render(context.Background(), &sb); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if !strings.Contains(sb.String(), `data-templ-relative>`+tt.expected+`</time>`) {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestRelativeTimeScriptIsRenderedOnce(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+	c := templ.Join(
+		templ.Time(now.Add(-time.Hour), "15:04", templ.RelativeTime(now)),
+		templ.Time(now.Add(-2*time.Hour), "15:04", templ.RelativeTime(now)),
+	)
+	ctx := templ.InitializeContext(context.Background())
+	var sb strings.Builder
+	if err := c.Render(ctx, &sb); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if !strings.HasPrefix(sb.String(), `<time datetime="2024-03-05T13:30:00Z" title="13:30" data-templ-relative>1 hour ago</time><script type="text/javascript">`) {
+		t.Errorf("unexpected output: %s", sb.String())
+	}
+	if count := strings.Count(sb.String(), "<script"); count != 1 {
+		t.Errorf("expected the script to be rendered once, got %d", count)
+	}
+}
@@ -0,0 +1,81 @@
+package templ
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// Tracer is used to trace the rendering of components, e.g. by creating
+// OpenTelemetry spans. templ has no dependency on any tracing library, so
+// a small adapter is required to connect a Tracer to a tracing backend.
+type Tracer interface {
+	// Start is called before the named component is rendered. The returned
+	// function is called when rendering completes, with any error returned
+	// by the component.
+	Start(ctx context.Context, name string) (context.Context, func(err error))
+}
+
+// RenderSizeRecorder can optionally be implemented by a Tracer to record the
+// number of bytes written by each traced component. RecordSize is called with
+// the context returned by Start, before the end function is called.
+type RenderSizeRecorder interface {
+	RecordSize(ctx context.Context, name string, size int)
+}
+
+const tracerContextKey = contextKeyType(1)
+
+// WithTracer returns a context that traces components rendered with it.
+func WithTracer(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerContextKey, t)
+}
+
+// GetTracer returns the Tracer from the context, or nil if no Tracer is set.
+func GetTracer(ctx context.Context) Tracer {
+	t, _ := ctx.Value(tracerContextKey).(Tracer)
+	return t
+}
+
+// Trace wraps the component so that rendering it is traced by the Tracer in
+// the render context, if there is one. Code generated with the -instrument flag
+// wraps all templates with Trace.
+func Trace(name string, c Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		t := GetTracer(ctx)
+		if t == nil {
+			return c.Render(ctx, w)
+		}
+		ctx, end := t.Start(ctx, name)
+		defer func() {
+			end(err)
+		}()
+		if sr, ok := t.(RenderSizeRecorder); ok {
+			// Generated code writes to a *bytes.Buffer directly, instead of
+			// allocating a buffer of its own, so it's measured, not wrapped.
+			if buf, isBuffer := w.(*bytes.Buffer); isBuffer {
+				start := buf.Len()
+				defer func() {
+					sr.RecordSize(ctx, name, buf.Len()-start)
+				}()
+				return c.Render(ctx, w)
+			}
+			cw := &countingWriter{w: w}
+			w = cw
+			defer func() {
+				sr.RecordSize(ctx, name, cw.n)
+			}()
+		}
+		return c.Render(ctx, w)
+	})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
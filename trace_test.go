@@ -0,0 +1,133 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+type testTracer struct {
+	events  []string
+	renders int
+	size    int
+}
+
+func (tt *testTracer) Start(ctx context.Context, name string) (context.Context, func(err error)) {
+	tt.events = append(tt.events, "start "+name)
+	return ctx, func(err error) {
+		if err != nil {
+			tt.events = append(tt.events, "end "+name+": "+err.Error())
+			return
+		}
+		tt.events = append(tt.events, "end "+name)
+	}
+}
+
+func (tt *testTracer) RecordRender(r *http.Request, d time.Duration, size int, err error) {
+	tt.renders++
+	tt.size = size
+}
+
+type sizeTracer struct {
+	testTracer
+	sizes map[string]int
+}
+
+func (st *sizeTracer) RecordSize(ctx context.Context, name string, size int) {
+	st.sizes[name] = size
+}
+
+func TestTrace(t *testing.T) {
+	child := templ.Trace("pkg.Child", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "child")
+		return err
+	}))
+	failing := templ.Trace("pkg.Failing", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return errors.New("failed")
+	}))
+	parent := templ.Trace("pkg.Parent", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := child.Render(ctx, w); err != nil {
+			return err
+		}
+		return failing.Render(ctx, w)
+	}))
+
+	t.Run("components are not traced if there is no tracer in the context", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := child.Render(context.Background(), w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.String() != "child" {
+			t.Errorf("expected %q, got %q", "child", w.String())
+		}
+	})
+	t.Run("nested components are traced, including errors", func(t *testing.T) {
+		tracer := &testTracer{}
+		err := parent.Render(templ.WithTracer(context.Background(), tracer), io.Discard)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		expected := []string{
+			"start pkg.Parent",
+			"start pkg.Child",
+			"end pkg.Child",
+			"start pkg.Failing",
+			"end pkg.Failing: failed",
+			"end pkg.Parent: failed",
+		}
+		if diff := cmp.Diff(expected, tracer.events); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the size of each component is recorded, and buffers are passed on", func(t *testing.T) {
+		var isBuffer bool
+		inner := templ.Trace("pkg.Inner", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, isBuffer = w.(*bytes.Buffer)
+			_, err := io.WriteString(w, "inner")
+			return err
+		}))
+		outer := templ.Trace("pkg.Outer", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, "outer "); err != nil {
+				return err
+			}
+			return inner.Render(ctx, w)
+		}))
+		for _, w := range []io.Writer{bytes.NewBufferString("existing content"), new(strings.Builder)} {
+			tracer := &sizeTracer{sizes: map[string]int{}}
+			if err := outer.Render(templ.WithTracer(context.Background(), tracer), w); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := map[string]int{"pkg.Outer": len("outer inner"), "pkg.Inner": len("inner")}
+			if diff := cmp.Diff(expected, tracer.sizes); diff != "" {
+				t.Errorf("%T: %s", w, diff)
+			}
+			if _, wantBuffer := w.(*bytes.Buffer); isBuffer != wantBuffer {
+				t.Errorf("%T: expected the component to be passed a *bytes.Buffer: %v, got %v", w, wantBuffer, isBuffer)
+			}
+		}
+	})
+	t.Run("handlers set the tracer and record metrics", func(t *testing.T) {
+		tracer := &testTracer{}
+		h := templ.Handler(child, templ.WithTracing(tracer))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if diff := cmp.Diff([]string{"start pkg.Child", "end pkg.Child"}, tracer.events); diff != "" {
+			t.Error(diff)
+		}
+		if tracer.renders != 1 {
+			t.Errorf("expected 1 render to be recorded, got %d", tracer.renders)
+		}
+		if tracer.size != len("child") {
+			t.Errorf("expected size %d, got %d", len("child"), tracer.size)
+		}
+	})
+}
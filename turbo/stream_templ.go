@@ -17,6 +17,7 @@ func actionTemplate(action string, target string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `turbo.actionTemplate`, &templ_7745c5c3_SourceLines_26929645)
 		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var1 == nil {
 			templ_7745c5c3_Var1 = templ.NopComponent
@@ -67,6 +68,8 @@ func actionTemplate(action string, target string) templ.Component {
 	})
 }
 
+var templ_7745c5c3_SourceLines_26929645 = templ.SourceLines{FileName: `turbo/stream.templ`, From: 12, To: 69, Lines: []int{12, 3, 31, 4, 44, 4}}
+
 func removeTemplate(action string, target string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, templ_7745c5c3_W io.Writer) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templ_7745c5c3_W.(*bytes.Buffer)
@@ -75,6 +78,7 @@ func removeTemplate(action string, target string) templ.Component {
 			defer templ.ReleaseBuffer(templ_7745c5c3_Buffer)
 		}
 		ctx = templ.InitializeContext(ctx)
+		defer templ.RecoverPanic(ctx, &templ_7745c5c3_Err, `turbo.removeTemplate`, &templ_7745c5c3_SourceLines_6734a380)
 		templ_7745c5c3_Var4 := templ.GetChildren(ctx)
 		if templ_7745c5c3_Var4 == nil {
 			templ_7745c5c3_Var4 = templ.NopComponent
@@ -116,3 +120,5 @@ func removeTemplate(action string, target string) templ.Component {
 		return templ_7745c5c3_Err
 	})
 }
+
+var templ_7745c5c3_SourceLines_6734a380 = templ.SourceLines{FileName: `turbo/stream.templ`, From: 73, To: 122, Lines: []int{73, 11, 92, 12, 105, 12}}
@@ -0,0 +1,204 @@
+package templ
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// URLResolver returns the URL of a named route, so that templates don't build
+// URLs by hand, e.g. URLFor(ctx, "user.profile", id).
+type URLResolver interface {
+	ResolveURL(name string, params ...any) (string, error)
+}
+
+// URLResolverFunc is a function that implements URLResolver.
+type URLResolverFunc func(name string, params ...any) (string, error)
+
+// ResolveURL calls f(name, params...).
+func (f URLResolverFunc) ResolveURL(name string, params ...any) (string, error) {
+	return f(name, params...)
+}
+
+const urlResolverContextKey = contextKeyType(5)
+
+// WithURLResolver returns a context that contains the URL resolver used by
+// URLFor. See also URLResolverMiddleware.
+func WithURLResolver(ctx context.Context, r URLResolver) context.Context {
+	return context.WithValue(ctx, urlResolverContextKey, r)
+}
+
+// ErrNoURLResolver is returned by ResolveURL if the context doesn't contain a
+// URL resolver.
+var ErrNoURLResolver = errors.New("templ: the context doesn't contain a URL resolver, see templ.WithURLResolver")
+
+// ResolveURL returns the sanitized URL of the named route, using the URL
+// resolver of the context.
+func ResolveURL(ctx context.Context, name string, params ...any) (SafeURL, error) {
+	r, _ := ctx.Value(urlResolverContextKey).(URLResolver)
+	if r == nil {
+		return FailedSanitizationURL, ErrNoURLResolver
+	}
+	u, err := r.ResolveURL(name, params...)
+	if err != nil {
+		return FailedSanitizationURL, fmt.Errorf("templ: failed to resolve the URL of route %q: %w", name, err)
+	}
+	return URL(u), nil
+}
+
+// URLFor returns the sanitized URL of the named route, using the URL resolver
+// of the context, e.g. <a href={ templ.URLFor(ctx, "user.profile", id) }>.
+//
+// If the URL can't be resolved, FailedSanitizationURL is returned. Use
+// ResolveURL to get the error.
+func URLFor(ctx context.Context, name string, params ...any) SafeURL {
+	u, _ := ResolveURL(ctx, name, params...)
+	return u
+}
+
+// Routes maps the names of routes to their patterns, e.g.
+// "user.profile": "/users/{id}". It's a URLResolver for routers that don't
+// name routes, e.g. github.com/go-chi/chi, and the patterns can be used to
+// register the routes, so that they don't drift from the URLs:
+//
+//	r.Get(routes.Pattern("user.profile"), profileHandler)
+//
+// The params replace the {name} and {name:regexp} placeholders of the pattern
+// in order, and a trailing * wildcard. Params are converted to strings, and
+// path escaped. Params, and the segments of wildcard params, can't be "." or
+// "..", since they would change the path that the URL resolves to.
+type Routes map[string]string
+
+// Pattern returns the pattern of the route. It panics if there's no route with
+// the name, since that's a programming error.
+func (r Routes) Pattern(name string) string {
+	pattern, ok := r[name]
+	if !ok {
+		panic(fmt.Sprintf("templ: no route named %q", name))
+	}
+	return pattern
+}
+
+// ResolveURL returns the URL of the route, with the params in place of the
+// placeholders of its pattern.
+func (r Routes) ResolveURL(name string, params ...any) (string, error) {
+	pattern, ok := r[name]
+	if !ok {
+		return "", fmt.Errorf("no route named %q", name)
+	}
+	var sb strings.Builder
+	var used int
+	nextParam := func() (string, error) {
+		if used >= len(params) {
+			return "", fmt.Errorf("pattern %q has more placeholders than the %d params", pattern, len(params))
+		}
+		s, err := toString(params[used])
+		used++
+		return s, err
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '{':
+			end := placeholderEnd(pattern, i)
+			if end < 0 {
+				return "", fmt.Errorf("pattern %q has an unclosed placeholder", pattern)
+			}
+			value, err := nextParam()
+			if err != nil {
+				return "", err
+			}
+			if isDotSegment(value) {
+				return "", fmt.Errorf("pattern %q: param %q is a relative path segment", pattern, value)
+			}
+			sb.WriteString(url.PathEscape(value))
+			i = end
+		case pattern[i] == '*' && i == len(pattern)-1:
+			value, err := nextParam()
+			if err != nil {
+				return "", err
+			}
+			// The wildcard matches the rest of the path, so the slashes are kept.
+			segments := strings.Split(value, "/")
+			for j, segment := range segments {
+				if isDotSegment(segment) {
+					return "", fmt.Errorf("pattern %q: wildcard param %q contains a relative path segment", pattern, value)
+				}
+				segments[j] = url.PathEscape(segment)
+			}
+			sb.WriteString(strings.Join(segments, "/"))
+		default:
+			sb.WriteByte(pattern[i])
+		}
+	}
+	if used != len(params) {
+		return "", fmt.Errorf("pattern %q has %d placeholders, but %d params were passed", pattern, used, len(params))
+	}
+	return sb.String(), nil
+}
+
+// isDotSegment returns true if the path segment is "." or "..". Path escaping
+// doesn't escape dots, and escaped dots are treated as dots when URLs are
+// resolved, so they can't be escaped.
+func isDotSegment(segment string) bool {
+	return segment == "." || segment == ".."
+}
+
+// placeholderEnd returns the index of the brace that closes the placeholder
+// that starts at from. Regular expressions in placeholders may contain braces,
+// e.g. {id:[0-9]{4}}.
+func placeholderEnd(pattern string, from int) int {
+	var depth int
+	for i := from; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// muxRoute is implemented by the routes of github.com/gorilla/mux.
+type muxRoute interface {
+	URL(pairs ...string) (*url.URL, error)
+	GetVarNames() ([]string, error)
+}
+
+// MuxRoutes returns a URLResolver for the named routes of a router from
+// github.com/gorilla/mux, e.g. templ.MuxRoutes(router.Get). The params are the
+// values of the variables of the route, in order.
+func MuxRoutes[R muxRoute](get func(name string) R) URLResolver {
+	return URLResolverFunc(func(name string, params ...any) (string, error) {
+		route := get(name)
+		if v := reflect.ValueOf(route); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+			return "", fmt.Errorf("no route named %q", name)
+		}
+		names, err := route.GetVarNames()
+		if err != nil {
+			return "", err
+		}
+		if len(names) != len(params) {
+			return "", fmt.Errorf("route %q has %d variables, but %d params were passed", name, len(names), len(params))
+		}
+		pairs := make([]string, 0, len(names)*2)
+		for i, varName := range names {
+			value, err := toString(params[i])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, varName, value)
+		}
+		u, err := route.URL(pairs...)
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	})
+}
@@ -0,0 +1,220 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRoutes(t *testing.T) {
+	routes := templ.Routes{
+		"home":         "/",
+		"user.profile": "/users/{id}",
+		"user.post":    "/users/{id}/posts/{slug:[a-z-]+}",
+		"archive":      "/archive/{year:[0-9]{4}}",
+		"files":        "/files/*",
+	}
+	tests := []struct {
+		name          string
+		route         string
+		params        []any
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "routes without placeholders are returned as is",
+			route:    "home",
+			expected: "/",
+		},
+		{
+			name:     "placeholders are replaced by the params",
+			route:    "user.profile",
+			params:   []any{123},
+			expected: "/users/123",
+		},
+		{
+			name:     "placeholders with regular expressions are replaced in order",
+			route:    "user.post",
+			params:   []any{"ada", "hello-world"},
+			expected: "/users/ada/posts/hello-world",
+		},
+		{
+			name:     "regular expressions can contain braces",
+			route:    "archive",
+			params:   []any{2024},
+			expected: "/archive/2024",
+		},
+		{
+			name:     "params are path escaped",
+			route:    "user.profile",
+			params:   []any{"a/b c"},
+			expected: "/users/a%2Fb%20c",
+		},
+		{
+			name:     "wildcards keep slashes",
+			route:    "files",
+			params:   []any{"docs/a b.txt"},
+			expected: "/files/docs/a%20b.txt",
+		},
+		{
+			name:     "wildcards can contain dots within segments",
+			route:    "files",
+			params:   []any{"docs/.config/..a"},
+			expected: "/files/docs/.config/..a",
+		},
+		{
+			name:          "wildcards can't contain parent segments",
+			route:         "files",
+			params:        []any{"docs/../../admin"},
+			expectedError: true,
+		},
+		{
+			name:          "wildcards can't contain current segments",
+			route:         "files",
+			params:        []any{"./docs"},
+			expectedError: true,
+		},
+		{
+			name:          "placeholders can't be parent segments",
+			route:         "user.post",
+			params:        []any{"..", "hello-world"},
+			expectedError: true,
+		},
+		{
+			name:          "too few params are an error",
+			route:         "user.post",
+			params:        []any{"ada"},
+			expectedError: true,
+		},
+		{
+			name:          "too many params are an error",
+			route:         "user.profile",
+			params:        []any{1, 2},
+			expectedError: true,
+		},
+		{
+			name:          "unknown routes are an error",
+			route:         "missing",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := routes.ResolveURL(tt.route, tt.params...)
+			if tt.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRoutesPattern(t *testing.T) {
+	routes := templ.Routes{"user.profile": "/users/{id}"}
+	if actual := routes.Pattern("user.profile"); actual != "/users/{id}" {
+		t.Errorf("expected /users/{id}, got %q", actual)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an unknown route")
+		}
+	}()
+	routes.Pattern("missing")
+}
+
+func TestURLFor(t *testing.T) {
+	routes := templ.Routes{
+		"user.profile": "/users/{id}",
+		"unsafe":       "javascript:alert({id})",
+	}
+	ctx := templ.WithURLResolver(context.Background(), routes)
+	t.Run("the URL of the route is returned", func(t *testing.T) {
+		if actual := templ.URLFor(ctx, "user.profile", 1); actual != "/users/1" {
+			t.Errorf("expected /users/1, got %q", actual)
+		}
+	})
+	t.Run("URLs are sanitized", func(t *testing.T) {
+		if actual := templ.URLFor(ctx, "unsafe", 1); actual != templ.FailedSanitizationURL {
+			t.Errorf("expected %q, got %q", templ.FailedSanitizationURL, actual)
+		}
+	})
+	t.Run("URLs that can't be resolved are replaced", func(t *testing.T) {
+		actual, err := templ.ResolveURL(ctx, "missing")
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+		if actual != templ.FailedSanitizationURL {
+			t.Errorf("expected %q, got %q", templ.FailedSanitizationURL, actual)
+		}
+	})
+	t.Run("an error is returned if the context doesn't contain a resolver", func(t *testing.T) {
+		_, err := templ.ResolveURL(context.Background(), "user.profile", 1)
+		if !errors.Is(err, templ.ErrNoURLResolver) {
+			t.Errorf("expected ErrNoURLResolver, got %v", err)
+		}
+	})
+}
+
+// muxRoute has the methods of a *mux.Route from github.com/gorilla/mux.
+type muxRoute struct {
+	template string
+	vars     []string
+}
+
+func (r *muxRoute) URL(pairs ...string) (*url.URL, error) {
+	path := r.template
+	for i := 0; i < len(pairs); i += 2 {
+		path = strings.ReplaceAll(path, "{"+pairs[i]+"}", pairs[i+1])
+	}
+	return &url.URL{Path: path}, nil
+}
+
+func (r *muxRoute) GetVarNames() ([]string, error) {
+	return r.vars, nil
+}
+
+func TestMuxRoutes(t *testing.T) {
+	routes := map[string]*muxRoute{
+		"user.post": {template: "/users/{id}/posts/{slug}", vars: []string{"id", "slug"}},
+	}
+	// The Get method of a *mux.Router returns nil for unknown routes.
+	get := func(name string) *muxRoute { return routes[name] }
+	resolver := templ.MuxRoutes(get)
+
+	actual, err := resolver.ResolveURL("user.post", 1, "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != "/users/1/posts/hello" {
+		t.Errorf("expected /users/1/posts/hello, got %q", actual)
+	}
+	if _, err = resolver.ResolveURL("user.post", 1); err == nil {
+		t.Error("expected an error for too few params, got nil")
+	}
+	if _, err = resolver.ResolveURL("missing"); err == nil {
+		t.Error("expected an error for an unknown route, got nil")
+	}
+}
+
+func TestURLResolverMiddleware(t *testing.T) {
+	routes := templ.Routes{"user.profile": "/users/{id}"}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, templ.URLFor(r.Context(), "user.profile", 7))
+	})
+	w := httptest.NewRecorder()
+	templ.NewURLResolverMiddleware(handler, routes).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if actual := w.Body.String(); actual != "/users/7" {
+		t.Errorf("expected /users/7, got %q", actual)
+	}
+}
@@ -0,0 +1,70 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// Components write their output to the io.Writer passed to Render, and this is
+// the contract that generated code keeps with every version of templ:
+//
+//   - If the writer is a *bytes.Buffer, output is written to it directly.
+//   - Otherwise, output is collected in a pooled buffer (see GetBuffer), and is
+//     written with a single call to Write once the component has rendered. A
+//     component that fails writes nothing to the writer.
+//   - Components never flush or close the writer. Use RenderAndFlush to render
+//     to a buffered writer, such as *bufio.Writer or http.ResponseWriter, and
+//     NewReader to read the output, e.g. to stream it into another process.
+//
+// Rendering state, such as the children of a component and the scripts and CSS
+// classes already rendered, is stored in the context with an unexported key.
+// Use InitializeContext, WithChildren and GetChildren to access it, rather than
+// relying on the context values.
+
+// Flusher is implemented by writers that buffer their output, e.g. *bufio.Writer.
+type Flusher interface {
+	Flush() error
+}
+
+// RenderAndFlush renders the component to w, then flushes w. w is flushed if it
+// implements Flusher, e.g. *bufio.Writer, or has a Flush method that doesn't
+// return an error, e.g. http.ResponseWriter. If rendering fails, w isn't flushed.
+func RenderAndFlush(ctx context.Context, c Component, w io.Writer) error {
+	if err := c.Render(ctx, w); err != nil {
+		return err
+	}
+	switch f := w.(type) {
+	case Flusher:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
+
+// NewReader returns a reader of the output of the component, which is rendered in
+// a new goroutine as the reader is read. Once the output has been read, Read
+// returns io.EOF, or the error returned by Render.
+//
+// The reader must be closed. Closing the reader before the output has been read
+// cancels the context passed to Render, and further writes by the component fail
+// with io.ErrClosedPipe.
+func NewReader(ctx context.Context, c Component) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	go func() {
+		defer cancel()
+		pw.CloseWithError(c.Render(ctx, pw))
+	}()
+	return &componentReader{PipeReader: pr, cancel: cancel}
+}
+
+type componentReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *componentReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
@@ -0,0 +1,104 @@
+package templ_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushed int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushed++
+}
+
+func TestRenderAndFlush(t *testing.T) {
+	hello := templ.Raw("Hello")
+	failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return errors.New("render error")
+	})
+
+	t.Run("bufio writers are flushed", func(t *testing.T) {
+		var out bytes.Buffer
+		bw := bufio.NewWriter(&out)
+		if err := templ.RenderAndFlush(context.Background(), hello, bw); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("Hello", out.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("writers with a Flush method that doesn't return an error are flushed", func(t *testing.T) {
+		var w flushRecorder
+		if err := templ.RenderAndFlush(context.Background(), hello, &w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.flushed != 1 {
+			t.Errorf("expected 1 flush, got %d", w.flushed)
+		}
+	})
+	t.Run("writers aren't flushed if rendering fails", func(t *testing.T) {
+		var w flushRecorder
+		if err := templ.RenderAndFlush(context.Background(), failing, &w); err == nil {
+			t.Fatal("expected an error")
+		}
+		if w.flushed != 0 {
+			t.Errorf("expected no flushes, got %d", w.flushed)
+		}
+	})
+}
+
+func TestNewReader(t *testing.T) {
+	t.Run("the output of the component can be read", func(t *testing.T) {
+		r := templ.NewReader(context.Background(), templ.Join(templ.Raw("Hello"), templ.Raw(", World")))
+		defer r.Close()
+		actual, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("Hello, World", string(actual)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("render errors are returned by Read", func(t *testing.T) {
+		renderErr := errors.New("render error")
+		r := templ.NewReader(context.Background(), templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, "Hello"); err != nil {
+				return err
+			}
+			return renderErr
+		}))
+		defer r.Close()
+		actual, err := io.ReadAll(r)
+		if !errors.Is(err, renderErr) {
+			t.Errorf("expected the render error, got %v", err)
+		}
+		if diff := cmp.Diff("Hello", string(actual)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("closing the reader cancels rendering", func(t *testing.T) {
+		done := make(chan error, 1)
+		r := templ.NewReader(context.Background(), templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			<-ctx.Done()
+			_, err := io.WriteString(w, "Hello")
+			done <- err
+			return err
+		}))
+		if err := r.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := <-done; !errors.Is(err, io.ErrClosedPipe) {
+			t.Errorf("expected io.ErrClosedPipe, got %v", err)
+		}
+	})
+}